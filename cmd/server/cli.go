@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/config"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/mcp"
+)
+
+// CLI exit codes
+const (
+	ExitOK    = 0
+	ExitError = 1
+	ExitUsage = 2
+)
+
+// usage describes the available subcommands
+const usage = `Usage:
+  paperless-mcp                                Start the MCP server
+  paperless-mcp tool <name> [--args JSON]      Execute a tool and print its JSON result
+  paperless-mcp tools list [--format json|table] List registered tools and their schemas
+`
+
+// runCommand dispatches a CLI subcommand and returns the process exit code
+func runCommand(cfg *config.Config, command string, args []string) int {
+	switch command {
+	case "tool":
+		return runToolCommand(cfg, args)
+	case "tools":
+		return runToolsCommand(cfg, args)
+	case "help", "-h", "--help":
+		fmt.Fprint(os.Stdout, usage)
+		return ExitOK
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command: %s\n\n%s", command, usage)
+		return ExitUsage
+	}
+}
+
+// runToolCommand executes a single tool handler without an MCP client,
+// e.g. paperless-mcp tool search_documents --args '{"query":"invoice"}'
+func runToolCommand(cfg *config.Config, args []string) int {
+	if len(args) == 0 || args[0] == "" || args[0][0] == '-' {
+		fmt.Fprintf(os.Stderr, "A tool name is required\n\n%s", usage)
+		return ExitUsage
+	}
+	toolName := args[0]
+
+	flags := flag.NewFlagSet("tool", flag.ContinueOnError)
+	argsJSON := flags.String("args", "{}", "Tool arguments as a JSON object")
+	if err := flags.Parse(args[1:]); err != nil {
+		return ExitUsage
+	}
+
+	var toolArgs map[string]interface{}
+	if err := json.Unmarshal([]byte(*argsJSON), &toolArgs); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid --args: must be a JSON object: %v\n", err)
+		return ExitUsage
+	}
+	if toolArgs == nil {
+		toolArgs = map[string]interface{}{}
+	}
+
+	mcpServer, err := mcp.New(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create MCP server: %v\n", err)
+		return ExitError
+	}
+	if !mcpServer.HasTool(toolName) {
+		fmt.Fprintf(os.Stderr, "Unknown tool: %s\n", toolName)
+		return ExitUsage
+	}
+
+	// Allow Ctrl-C to cancel a long-running tool
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	result, err := mcpServer.ExecuteTool(ctx, toolName, toolArgs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitError
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to encode result: %v\n", err)
+		return ExitError
+	}
+
+	return ExitOK
+}
+
+// runToolsCommand handles the tools subcommands
+func runToolsCommand(cfg *config.Config, args []string) int {
+	if len(args) == 0 || args[0] != "list" {
+		fmt.Fprintf(os.Stderr, "Unknown tools command\n\n%s", usage)
+		return ExitUsage
+	}
+
+	flags := flag.NewFlagSet("tools list", flag.ContinueOnError)
+	format := flags.String("format", "table", "Output format: json or table")
+	if err := flags.Parse(args[1:]); err != nil {
+		return ExitUsage
+	}
+	if *format != "json" && *format != "table" {
+		fmt.Fprintf(os.Stderr, "Invalid --format: %s, allowed: json, table\n", *format)
+		return ExitUsage
+	}
+
+	mcpServer, err := mcp.New(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create MCP server: %v\n", err)
+		return ExitError
+	}
+
+	tools := mcpServer.ListTools()
+	sort.Slice(tools, func(i, j int) bool {
+		return tools[i].Name < tools[j].Name
+	})
+
+	if *format == "table" {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tARGUMENTS\tDESCRIPTION")
+		for _, tool := range tools {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", tool.Name, schemaArguments(tool.InputSchema), tool.Description)
+		}
+		w.Flush()
+		return ExitOK
+	}
+
+	type toolInfo struct {
+		Name        string                 `json:"name"`
+		Description string                 `json:"description"`
+		InputSchema map[string]interface{} `json:"input_schema,omitempty"`
+	}
+	infos := make([]toolInfo, 0, len(tools))
+	for _, tool := range tools {
+		infos = append(infos, toolInfo{
+			Name:        tool.Name,
+			Description: tool.Description,
+			InputSchema: tool.InputSchema,
+		})
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(infos); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to encode tools: %v\n", err)
+		return ExitError
+	}
+
+	return ExitOK
+}
+
+// schemaArguments summarises an input schema as its properties in the form
+// name:type, sorted by name, with required properties marked by a *
+func schemaArguments(schema map[string]interface{}) string {
+	properties, _ := schema["properties"].(map[string]interface{})
+	if len(properties) == 0 {
+		return "-"
+	}
+	required := make(map[string]bool)
+	switch names := schema["required"].(type) {
+	case []string:
+		for _, name := range names {
+			required[name] = true
+		}
+	case []interface{}:
+		for _, name := range names {
+			required[fmt.Sprint(name)] = true
+		}
+	}
+
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	arguments := make([]string, len(names))
+	for i, name := range names {
+		argType := "any"
+		if property, ok := properties[name].(map[string]interface{}); ok {
+			if t, ok := property["type"].(string); ok {
+				argType = t
+			}
+		}
+		arguments[i] = name + ":" + argType
+		if required[name] {
+			arguments[i] += "*"
+		}
+	}
+	return strings.Join(arguments, ",")
+}
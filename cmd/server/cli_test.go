@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/config"
+)
+
+// captureOutput runs fn with os.Stdout and os.Stderr redirected and
+// returns what it wrote to each
+func captureOutput(t *testing.T, fn func()) (string, string) {
+	t.Helper()
+	capture := func(target **os.File) func() string {
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatalf("Failed to create pipe: %v", err)
+		}
+		original := *target
+		*target = w
+		done := make(chan string)
+		go func() {
+			var buf bytes.Buffer
+			io.Copy(&buf, r)
+			done <- buf.String()
+		}()
+		return func() string {
+			w.Close()
+			*target = original
+			return <-done
+		}
+	}
+	stdout := capture(&os.Stdout)
+	stderr := capture(&os.Stderr)
+	fn()
+	return stdout(), stderr()
+}
+
+// loadTestConfig loads the configuration for a fake Paperless at url
+func loadTestConfig(t *testing.T, url string) *config.Config {
+	t.Helper()
+	t.Setenv(config.EnvPaperlessURL, url)
+	t.Setenv(config.EnvPaperlessToken, "test-token")
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	return cfg
+}
+
+// TestRunCommand tests argument parsing, unknown tools and commands, and
+// the exit codes and output of the tool and tools subcommands
+func TestRunCommand(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/tags/3/":
+			w.Write([]byte(`{"id":3,"name":"Bills"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"detail":"Not found."}`))
+		}
+	}))
+	defer ts.Close()
+	cfg := loadTestConfig(t, ts.URL)
+
+	tests := []struct {
+		name       string
+		command    string
+		args       []string
+		wantCode   int
+		wantStdout string
+		wantStderr string
+	}{
+		{
+			name:       "help",
+			command:    "help",
+			wantCode:   ExitOK,
+			wantStdout: "Usage:",
+		},
+		{
+			name:       "unknown command",
+			command:    "serve",
+			wantCode:   ExitUsage,
+			wantStderr: "Unknown command: serve",
+		},
+		{
+			name:       "tool without a name",
+			command:    "tool",
+			wantCode:   ExitUsage,
+			wantStderr: "A tool name is required",
+		},
+		{
+			name:       "tool with a flag instead of a name",
+			command:    "tool",
+			args:       []string{"--args", "{}"},
+			wantCode:   ExitUsage,
+			wantStderr: "A tool name is required",
+		},
+		{
+			name:       "tool with invalid arguments",
+			command:    "tool",
+			args:       []string{"get_tag", "--args", "[1]"},
+			wantCode:   ExitUsage,
+			wantStderr: "Invalid --args",
+		},
+		{
+			name:     "tool with an unknown flag",
+			command:  "tool",
+			args:     []string{"get_tag", "--verbose"},
+			wantCode: ExitUsage,
+		},
+		{
+			name:       "unknown tool",
+			command:    "tool",
+			args:       []string{"get_tagz"},
+			wantCode:   ExitUsage,
+			wantStderr: "Unknown tool: get_tagz",
+		},
+		{
+			name:       "tool",
+			command:    "tool",
+			args:       []string{"get_tag", "--args", `{"tag_id":3}`},
+			wantCode:   ExitOK,
+			wantStdout: `"name": "Bills"`,
+		},
+		{
+			name:       "failing tool",
+			command:    "tool",
+			args:       []string{"get_tag", "--args", `{"tag_id":4}`},
+			wantCode:   ExitError,
+			wantStderr: "Error:",
+		},
+		{
+			name:       "tools without a subcommand",
+			command:    "tools",
+			wantCode:   ExitUsage,
+			wantStderr: "Unknown tools command",
+		},
+		{
+			name:       "tools list",
+			command:    "tools",
+			args:       []string{"list"},
+			wantCode:   ExitOK,
+			wantStdout: "tag_id:integer*",
+		},
+		{
+			name:       "tools list as JSON",
+			command:    "tools",
+			args:       []string{"list", "--format", "json"},
+			wantCode:   ExitOK,
+			wantStdout: `"input_schema"`,
+		},
+		{
+			name:       "tools list with an invalid format",
+			command:    "tools",
+			args:       []string{"list", "--format", "yaml"},
+			wantCode:   ExitUsage,
+			wantStderr: "Invalid --format: yaml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var code int
+			stdout, stderr := captureOutput(t, func() {
+				code = runCommand(cfg, tt.command, tt.args)
+			})
+			if code != tt.wantCode {
+				t.Errorf("Expected exit code %d, got %d (stderr %q)", tt.wantCode, code, stderr)
+			}
+			if !strings.Contains(stdout, tt.wantStdout) {
+				t.Errorf("Expected stdout to contain %q, got %q", tt.wantStdout, stdout)
+			}
+			if !strings.Contains(stderr, tt.wantStderr) {
+				t.Errorf("Expected stderr to contain %q, got %q", tt.wantStderr, stderr)
+			}
+		})
+	}
+}
+
+// TestSchemaArguments tests the argument summary of the tools table
+func TestSchemaArguments(t *testing.T) {
+	tests := []struct {
+		schema map[string]interface{}
+		want   string
+	}{
+		{map[string]interface{}{"type": "object"}, "-"},
+		{
+			map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"query":     map[string]interface{}{"type": "string"},
+					"page_size": map[string]interface{}{"type": "integer"},
+					"filter":    map[string]interface{}{},
+				},
+				"required": []string{"query"},
+			},
+			"filter:any,page_size:integer,query:string*",
+		},
+		{
+			map[string]interface{}{
+				"properties": map[string]interface{}{"document_id": map[string]interface{}{"type": "integer"}},
+				"required":   []interface{}{"document_id"},
+			},
+			"document_id:integer*",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := schemaArguments(tt.schema); got != tt.want {
+			t.Errorf("schemaArguments(%v) = %q, want %q", tt.schema, got, tt.want)
+		}
+	}
+}
@@ -9,7 +9,12 @@ import (
 	"strings"
 	"syscall"
 
+	// Embed the time zone database for MCP_TIMEZONE, as minimal container
+	// images do not ship one
+	_ "time/tzdata"
+
 	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/config"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
 	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/mcp"
 )
 
@@ -37,25 +42,30 @@ func main() {
 	}
 
 	// Setup logger with level
-	// Use stderr for logging so stdout is available for stdio transport
-	handler := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level})
+	// Use stderr for logging so stdout is available for stdio transport.
+	// Every record passes through the redacting handler so credentials,
+	// share links, query text and document content never reach the logs.
+	handler := logging.NewRedactingHandler(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
 	logger := slog.New(handler)
 	slog.SetDefault(logger)
 
-	// Mask tokens for logging
-	maskToken := func(token string) string {
-		if len(token) <= 4 {
-			return "****"
-		}
-		return token[:2] + strings.Repeat("*", len(token)-4) + token[len(token)-2:]
+	// Report configuration problems found in strict warn mode
+	for _, warning := range cfg.Warnings {
+		slog.Warn("Configuration problem", "problem", warning)
+	}
+
+	// Run a CLI subcommand instead of the server if one was given
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(cfg, os.Args[1], os.Args[2:]))
 	}
 
 	slog.Info("Starting Paperless MCP Server",
 		"paperless_url", cfg.PaperlessURL,
 		"mcp_transport", cfg.MCPTransport,
 		"log_level", cfg.LogLevel,
-		"paperless_token", maskToken(cfg.PaperlessToken),
-		"mcp_auth_token", maskToken(cfg.MCPAuthToken),
+		"paperless_token", logging.Mask(cfg.PaperlessToken),
+		"mcp_auth_token", logging.Mask(cfg.MCPAuthToken),
+		"mcp_auth_identities", len(cfg.MCPAuthTokenMap),
 		"mcp_http_port", cfg.MCPHTTPPort,
 	)
 
@@ -79,6 +89,16 @@ func main() {
 		cancel()
 	}()
 
+	// Probe Paperless now and periodically; when it is down the server
+	// still starts and tools that need it report it as unavailable
+	mcpServer.StartHealthProbe(ctx)
+
+	// Load name lookup caches in the background, once Paperless answers
+	mcpServer.StartMetadataPrefetch(ctx)
+
+	// Run scheduled digests of saved queries in the background
+	mcpServer.StartDigests(ctx)
+
 	// Start server with appropriate transport
 	var serverErr error
 	switch cfg.MCPTransport {
@@ -1,10 +1,19 @@
 package config
 
 import (
+    "crypto/tls"
     "errors"
     "fmt"
+    "net/netip"
+    "net/url"
     "os"
+    "path"
+    "path/filepath"
+    "strconv"
     "strings"
+    "time"
+
+    "git.binckly.ca/cbinckly/paperless-mcp-go/internal/schedule"
 )
 
 // Environment variable name constants
@@ -15,6 +24,59 @@ const (
     EnvLogLevel        = "LOG_LEVEL"
     EnvMCPTransport    = "MCP_TRANSPORT"
     EnvMCPHTTPPort     = "MCP_HTTP_PORT"
+    EnvPaperlessMaxResponseBytes = "PAPERLESS_MAX_RESPONSE_BYTES"
+    EnvPaperlessRateLimitMaxWait = "PAPERLESS_RATE_LIMIT_MAX_WAIT"
+    EnvPaperlessRequestPolicies  = "PAPERLESS_REQUEST_POLICIES"
+    EnvPaperlessTokenFile    = "PAPERLESS_TOKEN_FILE"
+    EnvPaperlessTokenCommand = "PAPERLESS_TOKEN_COMMAND"
+    EnvMCPTrustedProxies     = "MCP_TRUSTED_PROXIES"
+    EnvMCPAuthTokenMap       = "MCP_AUTH_TOKEN_MAP"
+    EnvPaperlessCassetteMode = "PAPERLESS_CASSETTE_MODE"
+    EnvPaperlessCassetteFile = "PAPERLESS_CASSETTE_FILE"
+    EnvMCPIdempotencyFile    = "MCP_IDEMPOTENCY_FILE"
+    EnvPaperlessMaxIdleConns    = "PAPERLESS_MAX_IDLE_CONNS"
+    EnvPaperlessMaxConnsPerHost = "PAPERLESS_MAX_CONNS_PER_HOST"
+    EnvPaperlessIdleConnTimeout = "PAPERLESS_IDLE_CONN_TIMEOUT"
+    EnvPaperlessHTTP2           = "PAPERLESS_HTTP2"
+    EnvMCPDocumentCacheSize     = "MCP_DOCUMENT_CACHE_SIZE"
+    EnvMCPDocumentCacheTTL      = "MCP_DOCUMENT_CACHE_TTL"
+    EnvPaperlessBasicAuthUser   = "PAPERLESS_BASIC_AUTH_USER"
+    EnvPaperlessBasicAuthPass   = "PAPERLESS_BASIC_AUTH_PASS"
+    EnvPaperlessBasicAuthHeader = "PAPERLESS_BASIC_AUTH_HEADER"
+    EnvMCPEnvFile               = "MCP_ENV_FILE"
+    EnvMCPStrictConfig          = "MCP_STRICT_CONFIG"
+    EnvMCPSavedQueriesFile      = "MCP_SAVED_QUERIES_FILE"
+    EnvMCPDigests               = "MCP_DIGESTS"
+    EnvMCPDigestWebhookURL      = "MCP_DIGEST_WEBHOOK_URL"
+    EnvMCPDeleteRequireTitle    = "MCP_DELETE_REQUIRE_TITLE"
+    EnvMCPDeleteProtectYears    = "MCP_DELETE_PROTECT_YEARS"
+    EnvMCPDeleteProtectTags     = "MCP_DELETE_PROTECT_TAGS"
+    EnvMCPUndoJournalFile       = "MCP_UNDO_JOURNAL_FILE"
+    EnvMCPOperationJournalFile  = "MCP_OPERATION_JOURNAL_FILE"
+    EnvMCPConfirmThreshold      = "MCP_CONFIRM_THRESHOLD"
+    EnvMCPBulkChunkSize         = "MCP_BULK_CHUNK_SIZE"
+    EnvMCPHTTPReadTimeout       = "MCP_HTTP_READ_TIMEOUT"
+    EnvMCPHTTPWriteTimeout      = "MCP_HTTP_WRITE_TIMEOUT"
+    EnvMCPHTTPIdleTimeout       = "MCP_HTTP_IDLE_TIMEOUT"
+    EnvMCPHeartbeatInterval     = "MCP_HEARTBEAT_INTERVAL"
+    EnvMCPShutdownGracePeriod   = "MCP_SHUTDOWN_GRACE_PERIOD"
+    EnvMCPAllowedOrigins        = "MCP_ALLOWED_ORIGINS"
+    EnvMCPSessionStoreURL       = "MCP_SESSION_STORE_URL"
+    EnvMCPSessionTTL            = "MCP_SESSION_TTL"
+    EnvMCPHealthProbeInterval   = "MCP_HEALTH_PROBE_INTERVAL"
+    EnvMCPPrefetchMetadata      = "MCP_PREFETCH_METADATA"
+    EnvMCPTimezone              = "MCP_TIMEZONE"
+    EnvMCPSizeUnits             = "MCP_SIZE_UNITS"
+    EnvMCPTagDelimiter          = "MCP_TAG_DELIMITER"
+    EnvMCPSummariesFile         = "MCP_SUMMARIES_FILE"
+    EnvMCPStartupWait           = "MCP_STARTUP_WAIT"
+    EnvMCPTLSCertFile           = "MCP_TLS_CERT_FILE"
+    EnvMCPTLSKeyFile            = "MCP_TLS_KEY_FILE"
+    EnvMCPTLSMinVersion         = "MCP_TLS_MIN_VERSION"
+    EnvPaperlessTLSMinVersion   = "PAPERLESS_TLS_MIN_VERSION"
+    EnvMCPToolScope             = "MCP_TOOL_SCOPE"
+    EnvMCPRenameCheck           = "MCP_RENAME_CHECK"
+    EnvMCPRawAPIPaths           = "MCP_RAW_API_PATHS"
 )
 
 // Default values
@@ -22,36 +84,237 @@ const (
     DefaultLogLevel     = "info"
     DefaultMCPTransport = "stdio"
     DefaultMCPHTTPPort  = "8080"
+    DefaultPaperlessMaxResponseBytes int64 = 32 << 20 // 32 MiB
+    DefaultPaperlessRateLimitMaxWait = 10 * time.Second
+    DefaultPaperlessMaxIdleConns     = 100
+    DefaultPaperlessMaxConnsPerHost  = 0 // unlimited
+    DefaultPaperlessIdleConnTimeout  = 90 * time.Second
+    DefaultMCPDocumentCacheSize      = 50
+    DefaultMCPDocumentCacheTTL       = 5 * time.Minute
+    DefaultPaperlessBasicAuthHeader  = "Proxy-Authorization"
+    DefaultSavedQueriesFileName      = "saved_queries.json"
+    DefaultMCPConfirmThreshold       = 50
+    DefaultMCPBulkChunkSize          = 100
+    DefaultMCPHTTPReadTimeout        = 30 * time.Second
+    DefaultMCPHTTPWriteTimeout       = 0 // no timeout, responses may stream
+    DefaultMCPHTTPIdleTimeout        = 120 * time.Second
+    DefaultMCPHeartbeatInterval      = 30 * time.Second
+    DefaultMCPShutdownGracePeriod    = 30 * time.Second
+    DefaultMCPSessionTTL             = 24 * time.Hour
+    DefaultMCPHealthProbeInterval    = 30 * time.Second
+    DefaultMCPTagDelimiter           = "/"
+    DefaultTLSMinVersion             = "1.2"
+    DefaultMCPToolScope              = ToolScopeHide
+    DefaultMCPRenameCheck            = RenameCheckWarn
+)
+
+// Tool scope modes, deciding what happens to tools the Paperless token
+// lacks permissions for
+const (
+    ToolScopeHide     = "hide"
+    ToolScopeAnnotate = "annotate"
+    ToolScopeOff      = "off"
+)
+
+// Rename check modes, deciding what happens when a renamed tag,
+// correspondent or document type is referenced by a storage path template
+const (
+    RenameCheckWarn = "warn"
+    RenameCheckFail = "fail"
+    RenameCheckOff  = "off"
 )
 
+// ConfigDirName is the directory under the user's configuration directory
+// holding files the server maintains itself
+const ConfigDirName = "paperless-mcp-go"
+
 // Config holds all application configuration
 type Config struct {
     PaperlessURL   string
     PaperlessToken string
+    PaperlessTokenFile    string // optional, alternative to PaperlessToken
+    PaperlessTokenCommand string // optional, alternative to PaperlessToken
     MCPAuthToken   string // optional
+    MCPAuthTokenMap map[string]string // optional, MCP bearer token -> Paperless token
     LogLevel       string
     MCPTransport   string
     MCPHTTPPort    string
+    MCPHTTPReadTimeout        time.Duration
+    MCPHTTPWriteTimeout       time.Duration // 0 means no timeout
+    MCPHTTPIdleTimeout        time.Duration
+    MCPHeartbeatInterval      time.Duration // 0 disables heartbeats
+    MCPShutdownGracePeriod    time.Duration // how long in-flight tool calls may finish on shutdown
+    MCPTLSCertFile            string // optional, serves HTTPS together with MCPTLSKeyFile
+    MCPTLSKeyFile             string
+    MCPTLSMinVersion          uint16 // minimum TLS version accepted by the HTTPS server
+    PaperlessMaxResponseBytes int64
+    PaperlessRateLimitMaxWait time.Duration
+    PaperlessRequestPolicies  map[string]RequestPolicy // timeout and retries per endpoint class
+    MCPTrustedProxies         []netip.Prefix // optional, proxies allowed to set X-Forwarded-For
+    MCPAllowedOrigins         []string       // browser origins allowed besides loopback, "*" allows all
+    PaperlessCassetteMode     string         // optional, "record" or "replay"
+    PaperlessCassetteFile     string
+    MCPIdempotencyFile        string // optional, persists idempotency keys across restarts
+    MCPUndoJournalFile        string // optional, persists the undo journal across restarts
+    MCPOperationJournalFile   string // optional, persists progress of chunked operations across restarts
+    PaperlessMaxIdleConns     int
+    PaperlessMaxConnsPerHost  int // 0 means unlimited
+    PaperlessIdleConnTimeout  time.Duration
+    PaperlessHTTP2            bool
+    PaperlessTLSMinVersion    uint16 // minimum TLS version used to connect to Paperless
+    MCPDocumentCacheSize      int // documents cached per session, 0 disables
+    MCPDocumentCacheTTL       time.Duration
+    MCPPrefetchMetadata       bool // warm the tag, correspondent, type and storage path caches on startup
+    MCPTimezone               *time.Location // optional, zone timestamps are shown in in text results
+    MCPSizeUnits              string         // optional, "decimal" or "binary" to humanize sizes in text results
+    PaperlessBasicAuthUser    string // optional, for a basic-auth protected reverse proxy
+    PaperlessBasicAuthPass    string
+    PaperlessBasicAuthHeader  string // header carrying the basic credentials
+    MCPSavedQueriesFile       string   // where saved queries are stored, empty keeps them in memory
+    MCPDigests                []Digest // saved queries to run on a schedule
+    MCPDigestWebhookURL       string   // optional, receives digest results as JSON
+    MCPDeleteRequireTitle     bool     // delete_document must be given the document's title
+    MCPDeleteProtectYears     int      // documents created more than this many years ago cannot be deleted, 0 disables
+    MCPDeleteProtectTags      []string // names or IDs of tags whose documents cannot be deleted
+    MCPConfirmThreshold       int      // documents above which bulk edits ask the user to confirm, 0 disables prompts
+    MCPBulkChunkSize          int      // documents sent to Paperless per bulk edit request
+    MCPSessionStoreURL        string        // optional, redis:// URL of a store shared between replicas
+    MCPSessionTTL             time.Duration // how long session state is kept after its last change
+    MCPHealthProbeInterval    time.Duration // how often Paperless is probed for availability
+    MCPStartupWait            time.Duration // how long to retry reaching Paperless at startup, 0 probes once
+    MCPTagDelimiter           string        // separates levels of hierarchical tag names
+    MCPSummariesFile          string        // optional, persists document summaries across restarts
+    MCPToolScope              string        // hide, annotate or off for tools the token cannot use
+    MCPRenameCheck            string        // warn, fail or off when renames affect storage path templates
+    MCPRawAPIPaths            []string      // path prefixes raw_api_get may read, empty disables the tool
+    MCPStrictConfig           string   // off, warn or fail on unrecognised variables
+    Warnings                  []string // problems found in warn mode, for the caller to log
 }
 
-// Load reads configuration from environment variables
+// Load reads configuration from environment variables, optionally loaded
+// from a .env file, expanding ${VAR} references in their values
 func Load() (*Config, error) {
     cfg := &Config{}
 
-    cfg.PaperlessURL = os.Getenv(EnvPaperlessURL)
+    // Fill in variables not already set from a .env file; an explicitly
+    // configured file must exist
+    envFile, required := os.Getenv(EnvMCPEnvFile), true
+    if envFile == "" {
+        envFile, required = DefaultEnvFile, false
+    }
+    if err := loadEnvFile(envFile, required); err != nil {
+        return nil, fmt.Errorf("invalid %s: %w", EnvMCPEnvFile, err)
+    }
+
+    // Catch misspelt or unsupported variables before they cause confusing
+    // failures later on
+    cfg.MCPStrictConfig = strings.ToLower(getenv(EnvMCPStrictConfig))
+    if cfg.MCPStrictConfig == "" {
+        cfg.MCPStrictConfig = StrictConfigOff
+    }
+    switch cfg.MCPStrictConfig {
+    case StrictConfigOff, StrictConfigWarn, StrictConfigFail:
+    default:
+        return nil, fmt.Errorf("invalid %s: %s, allowed: off, warn, fail", EnvMCPStrictConfig, cfg.MCPStrictConfig)
+    }
+    warnings, err := checkStrict(cfg.MCPStrictConfig)
+    if err != nil {
+        return nil, err
+    }
+    cfg.Warnings = warnings
+
+    cfg.PaperlessURL = getenv(EnvPaperlessURL)
     if strings.TrimSpace(cfg.PaperlessURL) == "" {
         return nil, errors.New("environment variable PAPERLESS_URL is required but not set")
     }
 
-    cfg.PaperlessToken = os.Getenv(EnvPaperlessToken)
-    if strings.TrimSpace(cfg.PaperlessToken) == "" {
-        return nil, errors.New("environment variable PAPERLESS_TOKEN is required but not set")
+    // Basic credentials for a fronting reverse proxy, either embedded in
+    // the URL or given separately; explicit variables take precedence
+    if u, err := url.Parse(cfg.PaperlessURL); err == nil && u.User != nil {
+        cfg.PaperlessBasicAuthUser = u.User.Username()
+        cfg.PaperlessBasicAuthPass, _ = u.User.Password()
+        u.User = nil
+        cfg.PaperlessURL = u.String()
+    }
+    if err := validatePaperlessURL(cfg.PaperlessURL); err != nil {
+        return nil, err
+    }
+    if v := getenv(EnvPaperlessBasicAuthUser); v != "" {
+        cfg.PaperlessBasicAuthUser = v
+    }
+    if v := getenv(EnvPaperlessBasicAuthPass); v != "" {
+        pass, err := resolveSecret(EnvPaperlessBasicAuthPass, v)
+        if err != nil {
+            return nil, err
+        }
+        cfg.PaperlessBasicAuthPass = pass
+    }
+    cfg.PaperlessBasicAuthHeader = getenv(EnvPaperlessBasicAuthHeader)
+    if cfg.PaperlessBasicAuthHeader == "" {
+        cfg.PaperlessBasicAuthHeader = DefaultPaperlessBasicAuthHeader
+    }
+    if cfg.PaperlessBasicAuthPass != "" && cfg.PaperlessBasicAuthUser == "" {
+        return nil, fmt.Errorf("%s is set but %s is not", EnvPaperlessBasicAuthPass, EnvPaperlessBasicAuthUser)
     }
 
-    cfg.MCPAuthToken = os.Getenv(EnvMCPAuthToken) // optional, no error if empty
+    // The token may be given directly, read from a file, or produced by a
+    // command; the latter two are re-read when Paperless rejects the token
+    paperlessToken, err := resolveSecret(EnvPaperlessToken, getenv(EnvPaperlessToken))
+    if err != nil {
+        return nil, err
+    }
+    cfg.PaperlessToken = paperlessToken
+    cfg.PaperlessTokenFile = getenv(EnvPaperlessTokenFile)
+    cfg.PaperlessTokenCommand = getenv(EnvPaperlessTokenCommand)
+    tokenSources := 0
+    for _, v := range []string{cfg.PaperlessToken, cfg.PaperlessTokenFile, cfg.PaperlessTokenCommand} {
+        if strings.TrimSpace(v) != "" {
+            tokenSources++
+        }
+    }
+    // Record/replay of Paperless interactions, for tests and offline demos
+    cfg.PaperlessCassetteMode = strings.ToLower(getenv(EnvPaperlessCassetteMode))
+    cfg.PaperlessCassetteFile = getenv(EnvPaperlessCassetteFile)
+    if cfg.PaperlessCassetteMode != "" {
+        if cfg.PaperlessCassetteMode != "record" && cfg.PaperlessCassetteMode != "replay" {
+            return nil, fmt.Errorf("invalid %s: %s, allowed: record, replay", EnvPaperlessCassetteMode, cfg.PaperlessCassetteMode)
+        }
+        if strings.TrimSpace(cfg.PaperlessCassetteFile) == "" {
+            return nil, fmt.Errorf("%s is required when %s is set", EnvPaperlessCassetteFile, EnvPaperlessCassetteMode)
+        }
+    }
+
+    // Replay never contacts Paperless, so no token is needed; nor is one
+    // sent when basic credentials occupy the Authorization header
+    basicAuthOnly := cfg.PaperlessBasicAuthUser != "" && strings.EqualFold(cfg.PaperlessBasicAuthHeader, "Authorization")
+    if tokenSources == 0 && cfg.PaperlessCassetteMode != "replay" && !basicAuthOnly {
+        return nil, errors.New("environment variable PAPERLESS_TOKEN is required but not set (or set PAPERLESS_TOKEN_FILE or PAPERLESS_TOKEN_COMMAND)")
+    }
+    if tokenSources > 1 {
+        return nil, errors.New("only one of PAPERLESS_TOKEN, PAPERLESS_TOKEN_FILE and PAPERLESS_TOKEN_COMMAND may be set")
+    }
+
+    // optional, no error if empty
+    mcpAuthToken, err := resolveSecret(EnvMCPAuthToken, getenv(EnvMCPAuthToken))
+    if err != nil {
+        return nil, err
+    }
+    cfg.MCPAuthToken = mcpAuthToken
+
+    // Comma-separated mcp_token=paperless_token pairs, one per identity
+    if v := getenv(EnvMCPAuthTokenMap); v != "" {
+        tokenMap, err := parseTokenMap(v)
+        if err != nil {
+            return nil, fmt.Errorf("invalid %s: %w", EnvMCPAuthTokenMap, err)
+        }
+        if _, exists := tokenMap[cfg.MCPAuthToken]; exists {
+            return nil, fmt.Errorf("invalid %s: MCP_AUTH_TOKEN must not also appear in the token map", EnvMCPAuthTokenMap)
+        }
+        cfg.MCPAuthTokenMap = tokenMap
+    }
 
     // Optional vars with defaults
-    cfg.LogLevel = os.Getenv(EnvLogLevel)
+    cfg.LogLevel = getenv(EnvLogLevel)
     if cfg.LogLevel == "" {
         cfg.LogLevel = DefaultLogLevel
     }
@@ -61,7 +324,7 @@ func Load() (*Config, error) {
         return nil, fmt.Errorf("invalid log level: %s, allowed: debug, info, warn, error", cfg.LogLevel)
     }
 
-    cfg.MCPTransport = os.Getenv(EnvMCPTransport)
+    cfg.MCPTransport = getenv(EnvMCPTransport)
     if cfg.MCPTransport == "" {
         cfg.MCPTransport = DefaultMCPTransport
     }
@@ -70,11 +333,615 @@ func Load() (*Config, error) {
         return nil, fmt.Errorf("invalid MCP_TRANSPORT: %s, allowed: stdio, http", cfg.MCPTransport)
     }
 
-    cfg.MCPHTTPPort = os.Getenv(EnvMCPHTTPPort)
+    cfg.MCPHTTPPort = getenv(EnvMCPHTTPPort)
     if cfg.MCPHTTPPort == "" {
         cfg.MCPHTTPPort = DefaultMCPHTTPPort
     }
-    // Optional: Could add port format validation here but skipping per spec simplicity
+    if port, err := strconv.Atoi(cfg.MCPHTTPPort); err != nil || port < 1 || port > 65535 {
+        return nil, fmt.Errorf("%s must be a port number between 1 and 65535, got '%s'", EnvMCPHTTPPort, cfg.MCPHTTPPort)
+    }
+
+    // HTTP server timeouts, tunable for proxies that cut idle or long
+    // streams such as Cloudflare's 100 second limit
+    cfg.MCPHTTPReadTimeout = DefaultMCPHTTPReadTimeout
+    if v := getenv(EnvMCPHTTPReadTimeout); v != "" {
+        d, err := time.ParseDuration(v)
+        if err != nil || d <= 0 {
+            return nil, fmt.Errorf("invalid %s: %s, must be a positive duration such as 30s", EnvMCPHTTPReadTimeout, v)
+        }
+        cfg.MCPHTTPReadTimeout = d
+    }
+
+    cfg.MCPHTTPWriteTimeout = DefaultMCPHTTPWriteTimeout
+    if v := getenv(EnvMCPHTTPWriteTimeout); v != "" {
+        d, err := time.ParseDuration(v)
+        if err != nil || d < 0 {
+            return nil, fmt.Errorf("invalid %s: %s, must be a non-negative duration such as 5m (0 for no timeout)", EnvMCPHTTPWriteTimeout, v)
+        }
+        cfg.MCPHTTPWriteTimeout = d
+    }
+
+    cfg.MCPHTTPIdleTimeout = DefaultMCPHTTPIdleTimeout
+    if v := getenv(EnvMCPHTTPIdleTimeout); v != "" {
+        d, err := time.ParseDuration(v)
+        if err != nil || d <= 0 {
+            return nil, fmt.Errorf("invalid %s: %s, must be a positive duration such as 120s", EnvMCPHTTPIdleTimeout, v)
+        }
+        cfg.MCPHTTPIdleTimeout = d
+    }
+
+    cfg.MCPHeartbeatInterval = DefaultMCPHeartbeatInterval
+    if v := getenv(EnvMCPHeartbeatInterval); v != "" {
+        d, err := time.ParseDuration(v)
+        if err != nil || d < 0 {
+            return nil, fmt.Errorf("invalid %s: %s, must be a non-negative duration such as 30s (0 disables heartbeats)", EnvMCPHeartbeatInterval, v)
+        }
+        cfg.MCPHeartbeatInterval = d
+    }
+
+    cfg.MCPShutdownGracePeriod = DefaultMCPShutdownGracePeriod
+    if v := getenv(EnvMCPShutdownGracePeriod); v != "" {
+        d, err := time.ParseDuration(v)
+        if err != nil || d < 0 {
+            return nil, fmt.Errorf("invalid %s: %s, must be a non-negative duration such as 30s (0 does not wait)", EnvMCPShutdownGracePeriod, v)
+        }
+        cfg.MCPShutdownGracePeriod = d
+    }
+
+    cfg.MCPHealthProbeInterval = DefaultMCPHealthProbeInterval
+    if v := getenv(EnvMCPHealthProbeInterval); v != "" {
+        d, err := time.ParseDuration(v)
+        if err != nil || d <= 0 {
+            return nil, fmt.Errorf("invalid %s: %s, must be a positive duration such as 30s", EnvMCPHealthProbeInterval, v)
+        }
+        cfg.MCPHealthProbeInterval = d
+    }
+
+    if v := getenv(EnvMCPStartupWait); v != "" {
+        d, err := time.ParseDuration(v)
+        if err != nil || d < 0 {
+            return nil, fmt.Errorf("invalid %s: %s, must be a non-negative duration such as 2m", EnvMCPStartupWait, v)
+        }
+        cfg.MCPStartupWait = d
+    }
+
+    cfg.MCPTagDelimiter = DefaultMCPTagDelimiter
+    if v := getenv(EnvMCPTagDelimiter); v != "" {
+        cfg.MCPTagDelimiter = v
+    }
+
+    cfg.MCPToolScope = strings.ToLower(getenv(EnvMCPToolScope))
+    if cfg.MCPToolScope == "" {
+        cfg.MCPToolScope = DefaultMCPToolScope
+    }
+    switch cfg.MCPToolScope {
+    case ToolScopeHide, ToolScopeAnnotate, ToolScopeOff:
+    default:
+        return nil, fmt.Errorf("invalid %s: %s, allowed: hide, annotate, off", EnvMCPToolScope, cfg.MCPToolScope)
+    }
+
+    cfg.MCPRenameCheck = strings.ToLower(getenv(EnvMCPRenameCheck))
+    if cfg.MCPRenameCheck == "" {
+        cfg.MCPRenameCheck = DefaultMCPRenameCheck
+    }
+    switch cfg.MCPRenameCheck {
+    case RenameCheckWarn, RenameCheckFail, RenameCheckOff:
+    default:
+        return nil, fmt.Errorf("invalid %s: %s, allowed: warn, fail, off", EnvMCPRenameCheck, cfg.MCPRenameCheck)
+    }
+
+    // raw_api_get is only offered when paths it may read are configured
+    if v := getenv(EnvMCPRawAPIPaths); v != "" {
+        paths, err := parseRawAPIPaths(v)
+        if err != nil {
+            return nil, fmt.Errorf("invalid %s: %w", EnvMCPRawAPIPaths, err)
+        }
+        cfg.MCPRawAPIPaths = paths
+    }
+
+    cfg.MCPTLSCertFile = getenv(EnvMCPTLSCertFile)
+    cfg.MCPTLSKeyFile = getenv(EnvMCPTLSKeyFile)
+    if (cfg.MCPTLSCertFile == "") != (cfg.MCPTLSKeyFile == "") {
+        return nil, fmt.Errorf("%s and %s must be set together", EnvMCPTLSCertFile, EnvMCPTLSKeyFile)
+    }
+
+    // Both directions default to TLS 1.2, refusing TLS 1.0 and 1.1
+    cfg.MCPTLSMinVersion, _ = parseTLSVersion(DefaultTLSMinVersion)
+    if v := getenv(EnvMCPTLSMinVersion); v != "" {
+        version, err := parseTLSVersion(v)
+        if err != nil {
+            return nil, fmt.Errorf("invalid %s: %s, must be 1.0, 1.1, 1.2 or 1.3", EnvMCPTLSMinVersion, v)
+        }
+        cfg.MCPTLSMinVersion = version
+    }
+
+    cfg.PaperlessTLSMinVersion, _ = parseTLSVersion(DefaultTLSMinVersion)
+    if v := getenv(EnvPaperlessTLSMinVersion); v != "" {
+        version, err := parseTLSVersion(v)
+        if err != nil {
+            return nil, fmt.Errorf("invalid %s: %s, must be 1.0, 1.1, 1.2 or 1.3", EnvPaperlessTLSMinVersion, v)
+        }
+        cfg.PaperlessTLSMinVersion = version
+    }
+
+    cfg.PaperlessMaxResponseBytes = DefaultPaperlessMaxResponseBytes
+    if v := getenv(EnvPaperlessMaxResponseBytes); v != "" {
+        n, err := strconv.ParseInt(v, 10, 64)
+        if err != nil || n < 1 {
+            return nil, fmt.Errorf("invalid %s: %s, must be a positive integer", EnvPaperlessMaxResponseBytes, v)
+        }
+        cfg.PaperlessMaxResponseBytes = n
+    }
+
+    cfg.PaperlessRateLimitMaxWait = DefaultPaperlessRateLimitMaxWait
+    if v := getenv(EnvPaperlessRateLimitMaxWait); v != "" {
+        d, err := time.ParseDuration(v)
+        if err != nil || d < 0 {
+            return nil, fmt.Errorf("invalid %s: %s, must be a non-negative duration such as 10s", EnvPaperlessRateLimitMaxWait, v)
+        }
+        cfg.PaperlessRateLimitMaxWait = d
+    }
+
+    cfg.PaperlessRequestPolicies = DefaultRequestPolicies()
+    if v := getenv(EnvPaperlessRequestPolicies); v != "" {
+        if err := parseRequestPolicies(v, cfg.PaperlessRequestPolicies); err != nil {
+            return nil, fmt.Errorf("invalid %s: %w", EnvPaperlessRequestPolicies, err)
+        }
+    }
+
+    // Connection pool tuning for the Paperless client transport
+    cfg.PaperlessMaxIdleConns = DefaultPaperlessMaxIdleConns
+    if v := getenv(EnvPaperlessMaxIdleConns); v != "" {
+        n, err := strconv.Atoi(v)
+        if err != nil || n < 1 {
+            return nil, fmt.Errorf("invalid %s: %s, must be a positive integer", EnvPaperlessMaxIdleConns, v)
+        }
+        cfg.PaperlessMaxIdleConns = n
+    }
+
+    cfg.PaperlessMaxConnsPerHost = DefaultPaperlessMaxConnsPerHost
+    if v := getenv(EnvPaperlessMaxConnsPerHost); v != "" {
+        n, err := strconv.Atoi(v)
+        if err != nil || n < 0 {
+            return nil, fmt.Errorf("invalid %s: %s, must be a non-negative integer (0 for unlimited)", EnvPaperlessMaxConnsPerHost, v)
+        }
+        cfg.PaperlessMaxConnsPerHost = n
+    }
+
+    cfg.PaperlessIdleConnTimeout = DefaultPaperlessIdleConnTimeout
+    if v := getenv(EnvPaperlessIdleConnTimeout); v != "" {
+        d, err := time.ParseDuration(v)
+        if err != nil || d < 0 {
+            return nil, fmt.Errorf("invalid %s: %s, must be a non-negative duration such as 90s", EnvPaperlessIdleConnTimeout, v)
+        }
+        cfg.PaperlessIdleConnTimeout = d
+    }
+
+    cfg.PaperlessHTTP2 = true
+    if v := getenv(EnvPaperlessHTTP2); v != "" {
+        b, err := strconv.ParseBool(v)
+        if err != nil {
+            return nil, fmt.Errorf("invalid %s: %s, must be true or false", EnvPaperlessHTTP2, v)
+        }
+        cfg.PaperlessHTTP2 = b
+    }
+
+    if v := getenv(EnvMCPTimezone); v != "" {
+        loc, err := time.LoadLocation(v)
+        if err != nil {
+            return nil, fmt.Errorf("invalid %s: %s, must be an IANA time zone such as Europe/Berlin", EnvMCPTimezone, v)
+        }
+        cfg.MCPTimezone = loc
+    }
+
+    cfg.MCPSizeUnits = strings.ToLower(getenv(EnvMCPSizeUnits))
+    if cfg.MCPSizeUnits != "" && cfg.MCPSizeUnits != "decimal" && cfg.MCPSizeUnits != "binary" {
+        return nil, fmt.Errorf("invalid %s: %s, allowed: decimal, binary", EnvMCPSizeUnits, cfg.MCPSizeUnits)
+    }
+
+    cfg.MCPPrefetchMetadata = true
+    if v := getenv(EnvMCPPrefetchMetadata); v != "" {
+        b, err := strconv.ParseBool(v)
+        if err != nil {
+            return nil, fmt.Errorf("invalid %s: %s, must be true or false", EnvMCPPrefetchMetadata, v)
+        }
+        cfg.MCPPrefetchMetadata = b
+    }
+
+    if v := getenv(EnvMCPDeleteRequireTitle); v != "" {
+        b, err := strconv.ParseBool(v)
+        if err != nil {
+            return nil, fmt.Errorf("invalid %s: %s, must be true or false", EnvMCPDeleteRequireTitle, v)
+        }
+        cfg.MCPDeleteRequireTitle = b
+    }
+
+    // Protection rules refuse deletes whatever the caller asks for
+    if v := getenv(EnvMCPDeleteProtectYears); v != "" {
+        n, err := strconv.Atoi(v)
+        if err != nil || n < 0 {
+            return nil, fmt.Errorf("invalid %s: %s, must be a non-negative integer (0 disables the age rule)", EnvMCPDeleteProtectYears, v)
+        }
+        cfg.MCPDeleteProtectYears = n
+    }
+    if v := getenv(EnvMCPDeleteProtectTags); v != "" {
+        cfg.MCPDeleteProtectTags = parseDeleteProtectTags(v)
+    }
+
+    // Destructive and large operations are confirmed with the end user
+    // through MCP elicitation when the client supports it
+    cfg.MCPConfirmThreshold = DefaultMCPConfirmThreshold
+    if v := getenv(EnvMCPConfirmThreshold); v != "" {
+        n, err := strconv.Atoi(v)
+        if err != nil || n < 0 {
+            return nil, fmt.Errorf("invalid %s: %s, must be a non-negative integer (0 disables confirmation prompts)", EnvMCPConfirmThreshold, v)
+        }
+        cfg.MCPConfirmThreshold = n
+    }
+
+    cfg.MCPBulkChunkSize = DefaultMCPBulkChunkSize
+    if v := getenv(EnvMCPBulkChunkSize); v != "" {
+        n, err := strconv.Atoi(v)
+        if err != nil || n < 1 {
+            return nil, fmt.Errorf("invalid %s: %s, must be a positive integer", EnvMCPBulkChunkSize, v)
+        }
+        cfg.MCPBulkChunkSize = n
+    }
+
+    // Per-session cache of recently fetched documents
+    cfg.MCPDocumentCacheSize = DefaultMCPDocumentCacheSize
+    if v := getenv(EnvMCPDocumentCacheSize); v != "" {
+        n, err := strconv.Atoi(v)
+        if err != nil || n < 0 {
+            return nil, fmt.Errorf("invalid %s: %s, must be a non-negative integer (0 disables the cache)", EnvMCPDocumentCacheSize, v)
+        }
+        cfg.MCPDocumentCacheSize = n
+    }
+
+    cfg.MCPDocumentCacheTTL = DefaultMCPDocumentCacheTTL
+    if v := getenv(EnvMCPDocumentCacheTTL); v != "" {
+        d, err := time.ParseDuration(v)
+        if err != nil || d < 0 {
+            return nil, fmt.Errorf("invalid %s: %s, must be a non-negative duration such as 5m", EnvMCPDocumentCacheTTL, v)
+        }
+        cfg.MCPDocumentCacheTTL = d
+    }
+
+    // Session state is kept in memory unless a shared store is configured
+    cfg.MCPSessionStoreURL = getenv(EnvMCPSessionStoreURL)
+    if cfg.MCPSessionStoreURL != "" {
+        if u, err := url.Parse(cfg.MCPSessionStoreURL); err != nil || (u.Scheme != "redis" && u.Scheme != "rediss") || u.Host == "" {
+            return nil, fmt.Errorf("invalid %s: must be a redis:// or rediss:// URL", EnvMCPSessionStoreURL)
+        }
+    }
+
+    cfg.MCPSessionTTL = DefaultMCPSessionTTL
+    if v := getenv(EnvMCPSessionTTL); v != "" {
+        d, err := time.ParseDuration(v)
+        if err != nil || d <= 0 {
+            return nil, fmt.Errorf("invalid %s: %s, must be a positive duration such as 24h", EnvMCPSessionTTL, v)
+        }
+        cfg.MCPSessionTTL = d
+    }
+
+    cfg.MCPIdempotencyFile = getenv(EnvMCPIdempotencyFile)
+    cfg.MCPUndoJournalFile = getenv(EnvMCPUndoJournalFile)
+    cfg.MCPOperationJournalFile = getenv(EnvMCPOperationJournalFile)
+    cfg.MCPSummariesFile = getenv(EnvMCPSummariesFile)
+
+    // Saved queries default to the user's configuration directory
+    cfg.MCPSavedQueriesFile = getenv(EnvMCPSavedQueriesFile)
+    if cfg.MCPSavedQueriesFile == "" {
+        if dir, err := os.UserConfigDir(); err == nil {
+            cfg.MCPSavedQueriesFile = filepath.Join(dir, ConfigDirName, DefaultSavedQueriesFileName)
+        }
+    }
+
+    // Semicolon-separated schedule=query entries, e.g. "0 8 * * MON=needs tagging"
+    if v := getenv(EnvMCPDigests); v != "" {
+        digests, err := parseDigests(v)
+        if err != nil {
+            return nil, fmt.Errorf("invalid %s: %w", EnvMCPDigests, err)
+        }
+        cfg.MCPDigests = digests
+    }
+
+    if v := getenv(EnvMCPDigestWebhookURL); v != "" {
+        u, err := url.Parse(v)
+        if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+            return nil, fmt.Errorf("invalid %s: must be an http or https URL", EnvMCPDigestWebhookURL)
+        }
+        cfg.MCPDigestWebhookURL = v
+    }
+
+    // Comma-separated IPs or CIDR ranges, e.g. "10.0.0.0/8, 192.168.1.10"
+    if v := getenv(EnvMCPTrustedProxies); v != "" {
+        proxies, err := parseTrustedProxies(v)
+        if err != nil {
+            return nil, fmt.Errorf("invalid %s: %w", EnvMCPTrustedProxies, err)
+        }
+        cfg.MCPTrustedProxies = proxies
+    }
+
+    // Comma-separated origins, e.g. "https://chat.example.com"
+    if v := getenv(EnvMCPAllowedOrigins); v != "" {
+        origins, err := parseAllowedOrigins(v)
+        if err != nil {
+            return nil, fmt.Errorf("invalid %s: %w", EnvMCPAllowedOrigins, err)
+        }
+        cfg.MCPAllowedOrigins = origins
+    }
 
     return cfg, nil
 }
+
+// validatePaperlessURL checks that the Paperless URL is an absolute
+// http(s) URL, with a message that says what is wrong
+func validatePaperlessURL(value string) error {
+    if !strings.Contains(value, "://") {
+        return fmt.Errorf("%s must include scheme, got '%s' (did you mean 'http://%s'?)", EnvPaperlessURL, value, value)
+    }
+    u, err := url.Parse(value)
+    if err != nil {
+        return fmt.Errorf("%s is not a valid URL, got '%s': %v", EnvPaperlessURL, value, err)
+    }
+    if u.Scheme != "http" && u.Scheme != "https" {
+        return fmt.Errorf("%s scheme must be http or https, got '%s'", EnvPaperlessURL, u.Scheme)
+    }
+    if u.Hostname() == "" {
+        return fmt.Errorf("%s must include a host, got '%s'", EnvPaperlessURL, value)
+    }
+    if port := u.Port(); port != "" {
+        if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+            return fmt.Errorf("%s port must be between 1 and 65535, got '%s'", EnvPaperlessURL, port)
+        }
+    }
+    if u.RawQuery != "" || u.Fragment != "" {
+        return fmt.Errorf("%s must not include a query or fragment, got '%s'", EnvPaperlessURL, value)
+    }
+    return nil
+}
+
+// parseTrustedProxies parses a comma-separated list of IP addresses and
+// CIDR ranges into prefixes; bare addresses become single-host prefixes
+func parseTrustedProxies(value string) ([]netip.Prefix, error) {
+    var prefixes []netip.Prefix
+    for _, entry := range strings.Split(value, ",") {
+        entry = strings.TrimSpace(entry)
+        if entry == "" {
+            continue
+        }
+        if strings.Contains(entry, "/") {
+            prefix, err := netip.ParsePrefix(entry)
+            if err != nil {
+                return nil, fmt.Errorf("%q is not a valid CIDR range", entry)
+            }
+            prefixes = append(prefixes, prefix.Masked())
+            continue
+        }
+        addr, err := netip.ParseAddr(entry)
+        if err != nil {
+            return nil, fmt.Errorf("%q is not a valid IP address", entry)
+        }
+        prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+    }
+    return prefixes, nil
+}
+
+// parseAllowedOrigins parses a comma-separated list of origins such as
+// "https://chat.example.com, http://localhost:3000" into their lowercase
+// scheme://host[:port] form
+func parseAllowedOrigins(value string) ([]string, error) {
+    var origins []string
+    for _, entry := range strings.Split(value, ",") {
+        entry = strings.TrimSpace(entry)
+        if entry == "" {
+            continue
+        }
+        if entry == "*" {
+            origins = append(origins, entry)
+            continue
+        }
+        u, err := url.Parse(strings.ToLower(entry))
+        if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || strings.Trim(u.Path, "/") != "" {
+            return nil, fmt.Errorf("%q is not a valid origin, expected scheme://host[:port]", entry)
+        }
+        origins = append(origins, u.Scheme+"://"+u.Host)
+    }
+    return origins, nil
+}
+
+// parseRawAPIPaths parses a comma-separated list of Paperless API path
+// prefixes such as "/api/documents/, /api/workflows/", each under /api/ and
+// ending in a slash so that a prefix only matches whole path segments
+func parseRawAPIPaths(value string) ([]string, error) {
+    var paths []string
+    for _, entry := range strings.Split(value, ",") {
+        entry = strings.TrimSpace(entry)
+        if entry == "" {
+            continue
+        }
+        if !strings.HasSuffix(entry, "/") {
+            entry += "/"
+        }
+        if !strings.HasPrefix(entry, "/api/") || strings.ContainsAny(entry, "?#") || path.Clean(entry)+"/" != entry {
+            return nil, fmt.Errorf("%q is not a valid path prefix, expected a path under /api/ such as /api/documents/", entry)
+        }
+        paths = append(paths, entry)
+    }
+    return paths, nil
+}
+
+// parseDeleteProtectTags parses a comma-separated list of tag names or
+// IDs such as "legal-hold, 12", dropping empty entries and repeats
+func parseDeleteProtectTags(value string) []string {
+    var tags []string
+    seen := make(map[string]bool)
+    for _, entry := range strings.Split(value, ",") {
+        entry = strings.TrimSpace(entry)
+        key := strings.ToLower(entry)
+        if entry == "" || seen[key] {
+            continue
+        }
+        seen[key] = true
+        tags = append(tags, entry)
+    }
+    return tags
+}
+
+// tlsVersions maps the accepted version names to crypto/tls constants
+var tlsVersions = map[string]uint16{
+    "1.0": tls.VersionTLS10,
+    "1.1": tls.VersionTLS11,
+    "1.2": tls.VersionTLS12,
+    "1.3": tls.VersionTLS13,
+}
+
+// parseTLSVersion parses a TLS version such as "1.2", optionally written
+// as "TLS1.2" or "tls12"
+func parseTLSVersion(value string) (uint16, error) {
+    name := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(value)), "tls")
+    name = strings.TrimSpace(name)
+    if len(name) == 2 && !strings.Contains(name, ".") {
+        name = name[:1] + "." + name[1:]
+    }
+    version, ok := tlsVersions[name]
+    if !ok {
+        return 0, fmt.Errorf("unknown TLS version %q", value)
+    }
+    return version, nil
+}
+
+// RequestPolicy is the timeout and retry count of one class of Paperless
+// requests
+type RequestPolicy struct {
+    Timeout time.Duration // per attempt, 0 means no timeout
+    Retries int           // retries of transient failures
+}
+
+// RequestPolicyClasses are the endpoint classes PAPERLESS_REQUEST_POLICIES
+// configures: paginated lists, single object reads, changes, uploads and
+// file downloads
+var RequestPolicyClasses = []string{"list", "read", "write", "upload", "download"}
+
+// DefaultRequestPolicies returns the request policies used unless
+// configured otherwise: the client's former 30 second timeout without
+// retries, except that uploads and downloads of large files get longer
+func DefaultRequestPolicies() map[string]RequestPolicy {
+    return map[string]RequestPolicy{
+        "list":     {Timeout: 30 * time.Second},
+        "read":     {Timeout: 30 * time.Second},
+        "write":    {Timeout: 30 * time.Second},
+        "upload":   {Timeout: 5 * time.Minute},
+        "download": {Timeout: 2 * time.Minute},
+    }
+}
+
+// parseRequestPolicies applies comma-separated class=timeout/retries
+// entries to policies. Either part may be left out to keep its current
+// value, and the class * applies to every class; entries are applied in
+// order, so later ones override earlier ones.
+func parseRequestPolicies(value string, policies map[string]RequestPolicy) error {
+    for _, entry := range strings.Split(value, ",") {
+        entry = strings.TrimSpace(entry)
+        if entry == "" {
+            continue
+        }
+        class, spec, ok := strings.Cut(entry, "=")
+        class = strings.ToLower(strings.TrimSpace(class))
+        if !ok || class == "" {
+            return fmt.Errorf("entry %q must be class=timeout/retries", entry)
+        }
+        classes := []string{class}
+        if class == "*" {
+            classes = RequestPolicyClasses
+        } else if _, known := policies[class]; !known {
+            return fmt.Errorf("unknown class %q, allowed: *, %s", class, strings.Join(RequestPolicyClasses, ", "))
+        }
+
+        timeoutSpec, retriesSpec, _ := strings.Cut(spec, "/")
+        timeoutSpec, retriesSpec = strings.TrimSpace(timeoutSpec), strings.TrimSpace(retriesSpec)
+        if timeoutSpec == "" && retriesSpec == "" {
+            return fmt.Errorf("entry %q sets neither a timeout nor retries", entry)
+        }
+        var timeout time.Duration
+        if timeoutSpec != "" {
+            d, err := time.ParseDuration(timeoutSpec)
+            if err != nil || d < 0 {
+                return fmt.Errorf("entry %q: timeout must be a non-negative duration such as 30s (0 for no timeout)", entry)
+            }
+            timeout = d
+        }
+        retries := 0
+        if retriesSpec != "" {
+            n, err := strconv.Atoi(retriesSpec)
+            if err != nil || n < 0 {
+                return fmt.Errorf("entry %q: retries must be a non-negative integer", entry)
+            }
+            retries = n
+        }
+
+        for _, name := range classes {
+            policy := policies[name]
+            if timeoutSpec != "" {
+                policy.Timeout = timeout
+            }
+            if retriesSpec != "" {
+                policy.Retries = retries
+            }
+            policies[name] = policy
+        }
+    }
+    return nil
+}
+
+// Digest is a saved query run on a schedule
+type Digest struct {
+    Schedule *schedule.Schedule
+    Query    string
+}
+
+// parseDigests parses semicolon-separated schedule=query entries
+func parseDigests(value string) ([]Digest, error) {
+    var digests []Digest
+    for _, entry := range strings.Split(value, ";") {
+        entry = strings.TrimSpace(entry)
+        if entry == "" {
+            continue
+        }
+        spec, query, ok := strings.Cut(entry, "=")
+        query = strings.TrimSpace(query)
+        if !ok || query == "" {
+            return nil, fmt.Errorf("entry %q must be schedule=query name", entry)
+        }
+        sched, err := schedule.Parse(spec)
+        if err != nil {
+            return nil, err
+        }
+        digests = append(digests, Digest{Schedule: sched, Query: query})
+    }
+    return digests, nil
+}
+
+// parseTokenMap parses comma-separated mcp_token=paperless_token pairs
+func parseTokenMap(value string) (map[string]string, error) {
+    tokenMap := make(map[string]string)
+    for i, entry := range strings.Split(value, ",") {
+        entry = strings.TrimSpace(entry)
+        if entry == "" {
+            continue
+        }
+        mcpToken, paperlessToken, ok := strings.Cut(entry, "=")
+        mcpToken = strings.TrimSpace(mcpToken)
+        paperlessToken = strings.TrimSpace(paperlessToken)
+        if !ok || mcpToken == "" || paperlessToken == "" {
+            // Never echo the entry itself, it contains secrets
+            return nil, fmt.Errorf("entry %d must be of the form mcp_token=paperless_token", i+1)
+        }
+        if _, exists := tokenMap[mcpToken]; exists {
+            return nil, fmt.Errorf("entry %d repeats an MCP token", i+1)
+        }
+        tokenMap[mcpToken] = paperlessToken
+    }
+    return tokenMap, nil
+}
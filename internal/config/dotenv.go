@@ -0,0 +1,89 @@
+package config
+
+import (
+    "bufio"
+    "errors"
+    "fmt"
+    "os"
+    "regexp"
+    "strings"
+)
+
+// DefaultEnvFile is loaded from the working directory when present and
+// MCP_ENV_FILE is not set
+const DefaultEnvFile = ".env"
+
+// expansionPattern matches ${VAR} references inside configuration values
+var expansionPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// getenv returns an environment variable with ${VAR} references expanded.
+// Only the braced form is expanded, so tokens containing a bare $ are
+// left untouched.
+func getenv(name string) string {
+    return expand(os.Getenv(name))
+}
+
+// expand replaces ${VAR} references with the value of VAR
+func expand(value string) string {
+    if !strings.Contains(value, "${") {
+        return value
+    }
+    return expansionPattern.ReplaceAllStringFunc(value, func(ref string) string {
+        return os.Getenv(ref[2 : len(ref)-1])
+    })
+}
+
+// loadEnvFile reads KEY=VALUE lines from a .env file into the environment.
+// Variables already set in the environment take precedence. A missing file
+// is only an error when required is true.
+func loadEnvFile(path string, required bool) error {
+    file, err := os.Open(path)
+    if err != nil {
+        if errors.Is(err, os.ErrNotExist) && !required {
+            return nil
+        }
+        return fmt.Errorf("failed to open env file: %w", err)
+    }
+    defer file.Close()
+
+    scanner := bufio.NewScanner(file)
+    for lineNo := 1; scanner.Scan(); lineNo++ {
+        line := strings.TrimSpace(scanner.Text())
+        if line == "" || strings.HasPrefix(line, "#") {
+            continue
+        }
+        line = strings.TrimPrefix(line, "export ")
+
+        key, value, found := strings.Cut(line, "=")
+        key = strings.TrimSpace(key)
+        if !found || key == "" {
+            return fmt.Errorf("%s:%d: expected KEY=VALUE", path, lineNo)
+        }
+        value = unquote(strings.TrimSpace(value))
+
+        if _, set := os.LookupEnv(key); set {
+            continue
+        }
+        if err := os.Setenv(key, value); err != nil {
+            return fmt.Errorf("%s:%d: %w", path, lineNo, err)
+        }
+    }
+    if err := scanner.Err(); err != nil {
+        return fmt.Errorf("failed to read env file: %w", err)
+    }
+    return nil
+}
+
+// unquote strips matching single or double quotes from a .env value, or a
+// trailing comment from an unquoted one
+func unquote(value string) string {
+    if len(value) >= 2 {
+        if first, last := value[0], value[len(value)-1]; first == last && (first == '"' || first == '\'') {
+            return value[1 : len(value)-1]
+        }
+    }
+    if i := strings.Index(value, " #"); i >= 0 {
+        value = strings.TrimSpace(value[:i])
+    }
+    return value
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "os/exec"
+    "runtime"
+    "strings"
+)
+
+// KeyringScheme prefixes config values that should be read from the OS
+// keychain / secret service instead of being given in plain text, e.g.
+// PAPERLESS_TOKEN=keyring:paperless-mcp/paperless_token
+const KeyringScheme = "keyring:"
+
+// keyringLookup reads a secret from the OS keyring; replaceable in tests
+var keyringLookup = lookupKeyring
+
+// resolveSecret returns value unchanged unless it uses the keyring scheme,
+// in which case the secret is fetched from the OS keyring
+func resolveSecret(name, value string) (string, error) {
+    if !strings.HasPrefix(value, KeyringScheme) {
+        return value, nil
+    }
+
+    service, account, err := parseKeyringURI(value)
+    if err != nil {
+        return "", fmt.Errorf("invalid %s: %w", name, err)
+    }
+
+    secret, err := keyringLookup(service, account)
+    if err != nil {
+        return "", fmt.Errorf("failed to read %s from keyring (service %q, account %q): %w", name, service, account, err)
+    }
+    return secret, nil
+}
+
+// parseKeyringURI parses keyring:service/account (an optional "//" after
+// the scheme is accepted)
+func parseKeyringURI(value string) (string, string, error) {
+    rest := strings.TrimPrefix(strings.TrimPrefix(value, KeyringScheme), "//")
+    service, account, ok := strings.Cut(rest, "/")
+    if !ok || service == "" || account == "" {
+        return "", "", errors.New("keyring reference must be of the form keyring:service/account")
+    }
+    return service, account, nil
+}
+
+// lookupKeyring reads a generic password using the platform's keyring
+// tool: security(1) on macOS and secret-tool(1) (libsecret) on Linux
+func lookupKeyring(service, account string) (string, error) {
+    var cmd *exec.Cmd
+    switch runtime.GOOS {
+    case "darwin":
+        cmd = exec.Command("security", "find-generic-password", "-s", service, "-a", account, "-w")
+    case "linux", "freebsd", "openbsd", "netbsd":
+        cmd = exec.Command("secret-tool", "lookup", "service", service, "account", account)
+    default:
+        return "", fmt.Errorf("keyring is not supported on %s", runtime.GOOS)
+    }
+
+    var stderr bytes.Buffer
+    cmd.Stderr = &stderr
+    out, err := cmd.Output()
+    if err != nil {
+        return "", fmt.Errorf("%s: %w: %s", cmd.Path, err, strings.TrimSpace(stderr.String()))
+    }
+
+    secret := strings.TrimRight(string(out), "\r\n")
+    if secret == "" {
+        return "", errors.New("secret not found")
+    }
+    return secret, nil
+}
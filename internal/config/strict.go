@@ -0,0 +1,152 @@
+package config
+
+import (
+    "fmt"
+    "os"
+    "sort"
+    "strings"
+
+    "git.binckly.ca/cbinckly/paperless-mcp-go/internal/textutil"
+)
+
+// Strict config modes
+const (
+    StrictConfigOff  = "off"
+    StrictConfigWarn = "warn"
+    StrictConfigFail = "fail"
+)
+
+// strictPrefixes are the variable prefixes owned by this server
+var strictPrefixes = []string{"PAPERLESS_", "MCP_"}
+
+// maxTypoDistance is how many edits away from a known variable an unknown
+// one may be to be reported as a likely typo
+const maxTypoDistance = 2
+
+// knownEnvVars lists every variable Load reads. Add new variables here so
+// strict mode does not reject them.
+var knownEnvVars = []string{
+    EnvPaperlessURL,
+    EnvPaperlessToken,
+    EnvMCPAuthToken,
+    EnvLogLevel,
+    EnvMCPTransport,
+    EnvMCPHTTPPort,
+    EnvPaperlessMaxResponseBytes,
+    EnvPaperlessRateLimitMaxWait,
+    EnvPaperlessRequestPolicies,
+    EnvPaperlessTokenFile,
+    EnvPaperlessTokenCommand,
+    EnvMCPTrustedProxies,
+    EnvMCPAuthTokenMap,
+    EnvPaperlessCassetteMode,
+    EnvPaperlessCassetteFile,
+    EnvMCPIdempotencyFile,
+    EnvPaperlessMaxIdleConns,
+    EnvPaperlessMaxConnsPerHost,
+    EnvPaperlessIdleConnTimeout,
+    EnvPaperlessHTTP2,
+    EnvMCPDocumentCacheSize,
+    EnvMCPDocumentCacheTTL,
+    EnvPaperlessBasicAuthUser,
+    EnvPaperlessBasicAuthPass,
+    EnvPaperlessBasicAuthHeader,
+    EnvMCPEnvFile,
+    EnvMCPStrictConfig,
+    EnvMCPSavedQueriesFile,
+    EnvMCPDigests,
+    EnvMCPDigestWebhookURL,
+    EnvMCPDeleteRequireTitle,
+    EnvMCPDeleteProtectYears,
+    EnvMCPDeleteProtectTags,
+    EnvMCPUndoJournalFile,
+    EnvMCPOperationJournalFile,
+    EnvMCPConfirmThreshold,
+    EnvMCPBulkChunkSize,
+    EnvMCPHTTPReadTimeout,
+    EnvMCPHTTPWriteTimeout,
+    EnvMCPHTTPIdleTimeout,
+    EnvMCPHeartbeatInterval,
+    EnvMCPShutdownGracePeriod,
+    EnvMCPAllowedOrigins,
+    EnvMCPSessionStoreURL,
+    EnvMCPSessionTTL,
+    EnvMCPHealthProbeInterval,
+    EnvMCPPrefetchMetadata,
+    EnvMCPTimezone,
+    EnvMCPSizeUnits,
+    EnvMCPTagDelimiter,
+    EnvMCPSummariesFile,
+    EnvMCPStartupWait,
+    EnvMCPTLSCertFile,
+    EnvMCPTLSKeyFile,
+    EnvMCPTLSMinVersion,
+    EnvPaperlessTLSMinVersion,
+    EnvMCPToolScope,
+    EnvMCPRenameCheck,
+    EnvMCPRawAPIPaths,
+}
+
+// unknownEnvVars returns a message for every variable in environ that
+// looks like it was meant for this server but is not recognised: any
+// variable with one of our prefixes, and any other variable within a
+// couple of edits of a known one (e.g. PAPERLES_TOKEN)
+func unknownEnvVars(environ []string) []string {
+    known := make(map[string]bool, len(knownEnvVars))
+    for _, name := range knownEnvVars {
+        known[name] = true
+    }
+
+    var problems []string
+    for _, entry := range environ {
+        name, _, _ := strings.Cut(entry, "=")
+        if name == "" || known[name] {
+            continue
+        }
+
+        suggestion, distance := closestEnvVar(name)
+        prefixed := false
+        for _, prefix := range strictPrefixes {
+            if strings.HasPrefix(name, prefix) {
+                prefixed = true
+                break
+            }
+        }
+
+        switch {
+        case distance <= maxTypoDistance:
+            problems = append(problems, fmt.Sprintf("unknown variable %s, did you mean %s?", name, suggestion))
+        case prefixed:
+            problems = append(problems, fmt.Sprintf("unknown variable %s", name))
+        }
+    }
+    sort.Strings(problems)
+    return problems
+}
+
+// closestEnvVar returns the known variable nearest to name by edit distance
+func closestEnvVar(name string) (string, int) {
+    best, bestDistance := "", len(name)+1
+    for _, candidate := range knownEnvVars {
+        if d := textutil.EditDistance(name, candidate); d < bestDistance {
+            best, bestDistance = candidate, d
+        }
+    }
+    return best, bestDistance
+}
+
+// checkStrict applies the strict config mode to the current environment,
+// returning an error in fail mode and warnings in warn mode
+func checkStrict(mode string) ([]string, error) {
+    if mode == StrictConfigOff {
+        return nil, nil
+    }
+    problems := unknownEnvVars(os.Environ())
+    if len(problems) == 0 {
+        return nil, nil
+    }
+    if mode == StrictConfigFail {
+        return nil, fmt.Errorf("%s=%s: %s", EnvMCPStrictConfig, mode, strings.Join(problems, "; "))
+    }
+    return problems, nil
+}
@@ -0,0 +1,31 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+// loggerKey is the context key of the logger carried by a request
+type loggerKey struct{}
+
+// WithLogger returns a copy of ctx carrying logger, typically the default
+// logger with attributes identifying the request such as its session and
+// tool, so everything logged while serving it can be attributed
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the logger carried by ctx, or the default logger
+// when there is none
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// HasLogger reports whether ctx carries a logger
+func HasLogger(ctx context.Context) bool {
+	_, ok := ctx.Value(loggerKey{}).(*slog.Logger)
+	return ok
+}
@@ -0,0 +1,187 @@
+// Package logging provides slog handlers shared by the server and CLI
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Redacted replaces sensitive values in log output
+const Redacted = "[REDACTED]"
+
+// sensitiveKeyParts mark attribute keys holding credentials; any key
+// containing one of them is redacted
+var sensitiveKeyParts = []string{"token", "authorization", "password", "secret", "cookie", "api_key"}
+
+// contentKeys are attribute keys holding document content or user query
+// text, which must never reach the logs
+var contentKeys = map[string]bool{
+	"content": true,
+	"body":    true,
+	"query":   true,
+	"text":    true,
+	"note":    true,
+	"args":    true,
+}
+
+// safeQueryParams are URL query parameters whose values are logged as is;
+// every other parameter value is redacted
+var safeQueryParams = map[string]bool{
+	"page":      true,
+	"page_size": true,
+	"ordering":  true,
+	"fields":    true,
+}
+
+var (
+	// credentialPattern matches Authorization header values embedded in text
+	credentialPattern = regexp.MustCompile(`(?i)\b(token|bearer|basic)\s+[A-Za-z0-9._~+/=-]{8,}`)
+	// shareLinkPattern matches the secret slug of a Paperless share link
+	shareLinkPattern = regexp.MustCompile(`(/share/)[^/?#\s"]+`)
+	// urlPattern matches absolute URLs and API paths with a query string
+	urlPattern = regexp.MustCompile(`(https?://|/api/)[^\s"']+`)
+)
+
+// Masked is a secret shortened by Mask so it can be recognised in the logs
+// without being revealed. It is the only value the redacting handler logs
+// under a sensitive key.
+type Masked string
+
+// Mask returns secret with all but its first and last two characters
+// replaced by asterisks; secrets of four characters or fewer are masked
+// completely
+func Mask(secret string) Masked {
+	if len(secret) <= 4 {
+		return "****"
+	}
+	return Masked(secret[:2] + strings.Repeat("*", len(secret)-4) + secret[len(secret)-2:])
+}
+
+// LogValue implements slog.LogValuer
+func (m Masked) LogValue() slog.Value {
+	return slog.StringValue(string(m))
+}
+
+// redactingHandler wraps another handler, scrubbing credentials, share
+// link secrets, query strings and document content from every record
+type redactingHandler struct {
+	next slog.Handler
+}
+
+// NewRedactingHandler returns a handler that redacts sensitive values
+// before passing records to next
+func NewRedactingHandler(next slog.Handler) slog.Handler {
+	return &redactingHandler{next: next}
+}
+
+// Enabled implements slog.Handler
+func (h *redactingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler
+func (h *redactingHandler) Handle(ctx context.Context, record slog.Record) error {
+	redacted := slog.NewRecord(record.Time, record.Level, RedactString(record.Message), record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		redacted.AddAttrs(redactAttr(attr))
+		return true
+	})
+	return h.next.Handle(ctx, redacted)
+}
+
+// WithAttrs implements slog.Handler
+func (h *redactingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		redacted[i] = redactAttr(attr)
+	}
+	return &redactingHandler{next: h.next.WithAttrs(redacted)}
+}
+
+// WithGroup implements slog.Handler
+func (h *redactingHandler) WithGroup(name string) slog.Handler {
+	return &redactingHandler{next: h.next.WithGroup(name)}
+}
+
+// isSensitiveKey reports whether an attribute key names a credential or
+// content value
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	if contentKeys[key] {
+		return true
+	}
+	for _, part := range sensitiveKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactAttr returns attr with any sensitive value replaced
+func redactAttr(attr slog.Attr) slog.Attr {
+	// Checked before resolving, which would turn it into a plain string
+	if attr.Value.Kind() == slog.KindLogValuer {
+		if masked, ok := attr.Value.LogValuer().(Masked); ok {
+			return slog.String(attr.Key, string(masked))
+		}
+	}
+	value := attr.Value.Resolve()
+
+	if value.Kind() == slog.KindGroup {
+		group := value.Group()
+		redacted := make([]slog.Attr, len(group))
+		for i, a := range group {
+			redacted[i] = redactAttr(a)
+		}
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(redacted...)}
+	}
+
+	if isSensitiveKey(attr.Key) {
+		return slog.String(attr.Key, Redacted)
+	}
+
+	switch value.Kind() {
+	case slog.KindString:
+		return slog.String(attr.Key, RedactString(value.String()))
+	case slog.KindAny:
+		if err, ok := value.Any().(error); ok {
+			message := err.Error()
+			if redacted := RedactString(message); redacted != message {
+				return slog.String(attr.Key, redacted)
+			}
+		}
+	}
+	return slog.Attr{Key: attr.Key, Value: value}
+}
+
+// RedactString scrubs credentials, share link secrets and URL query
+// parameter values from free text
+func RedactString(s string) string {
+	s = credentialPattern.ReplaceAllString(s, "$1 "+Redacted)
+	s = shareLinkPattern.ReplaceAllString(s, "${1}"+Redacted)
+	return urlPattern.ReplaceAllStringFunc(s, redactURL)
+}
+
+// redactURL replaces the values of all but a few known-safe query
+// parameters in a URL or path
+func redactURL(raw string) string {
+	base, query, found := strings.Cut(raw, "?")
+	if !found {
+		return raw
+	}
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return base + "?" + Redacted
+	}
+	for key := range values {
+		if !safeQueryParams[key] {
+			values[key] = []string{Redacted}
+		}
+	}
+	// Encode escapes the brackets of the placeholder; keep it readable
+	return base + "?" + strings.ReplaceAll(values.Encode(), url.QueryEscape(Redacted), Redacted)
+}
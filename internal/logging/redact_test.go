@@ -0,0 +1,52 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestRedactingHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewRedactingHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	logger.Debug("request",
+		"url", "http://paperless/api/documents/?query=salary+2024&page=2",
+		"query", "salary 2024",
+		"content", "confidential letter",
+		"paperless_token", Mask("ab12345678yz"),
+		"api_key", "padded****secret",
+		"Authorization", "Token abcdef0123456789",
+		"error", errors.New(`GET http://paperless/share/s3cr3tslug failed: header "Token abcdef0123456789"`),
+		slog.Group("request", "body", `{"title":"x"}`),
+	)
+	logger.With("mcp_auth_token", "plaintext-secret").Info("configured")
+
+	out := buf.String()
+	for _, leaked := range []string{"salary", "confidential", "abcdef0123456789", "s3cr3tslug", `\"title\"`, "plaintext-secret", "padded"} {
+		if strings.Contains(out, leaked) {
+			t.Errorf("log output contains %q:\n%s", leaked, out)
+		}
+	}
+	for _, kept := range []string{"page=2", "ab********yz", "/share/" + Redacted} {
+		if !strings.Contains(out, kept) {
+			t.Errorf("log output missing %q:\n%s", kept, out)
+		}
+	}
+}
+
+// TestMask tests that masking keeps only the ends of long secrets
+func TestMask(t *testing.T) {
+	for secret, want := range map[string]Masked{
+		"":             "****",
+		"abcd":         "****",
+		"abcdef":       "ab**ef",
+		"ab12345678yz": "ab********yz",
+	} {
+		if got := Mask(secret); got != want {
+			t.Errorf("Mask(%q) = %q, want %q", secret, got, want)
+		}
+	}
+}
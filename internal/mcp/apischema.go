@@ -0,0 +1,301 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// Limits of the describe_api tool, which keep descriptions of large
+// resources such as documents, whose list endpoint accepts hundreds of
+// filters, readable
+const (
+	MaxDescribedParameters = 100
+	MaxAPIDescriptionChars = 300
+)
+
+// apiEndpoint summarizes one endpoint for describe_api
+type apiEndpoint struct {
+	Method              string         `json:"method"`
+	Path                string         `json:"path"`
+	Summary             string         `json:"summary,omitempty"`
+	Parameters          []apiParameter `json:"parameters,omitempty"`
+	ParametersTruncated int            `json:"parameters_truncated,omitempty"`
+	RequestBody         string         `json:"request_body,omitempty"`
+	Response            string         `json:"response,omitempty"`
+}
+
+// apiParameter summarizes a path or query parameter
+type apiParameter struct {
+	Name        string `json:"name"`
+	In          string `json:"in"`
+	Type        string `json:"type"`
+	Required    bool   `json:"required,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// apiField summarizes one field of an object the API exchanges
+type apiField struct {
+	Name        string        `json:"name"`
+	Type        string        `json:"type"`
+	Required    bool          `json:"required,omitempty"`
+	ReadOnly    bool          `json:"read_only,omitempty"`
+	WriteOnly   bool          `json:"write_only,omitempty"`
+	Nullable    bool          `json:"nullable,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Enum        []interface{} `json:"enum,omitempty"`
+}
+
+// apiObject summarizes a named schema: the fields of an object, or the
+// values of an enumeration
+type apiObject struct {
+	Type        string        `json:"type,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Fields      []apiField    `json:"fields,omitempty"`
+	Enum        []interface{} `json:"enum,omitempty"`
+}
+
+// apiResource returns the resource an API path belongs to, such as
+// documents for /api/documents/{id}/notes/
+func apiResource(path string) string {
+	rest, ok := strings.CutPrefix(path, "/api/")
+	if !ok {
+		return ""
+	}
+	resource, _, _ := strings.Cut(rest, "/")
+	return resource
+}
+
+// schemaRefName returns the component name a $ref points to
+func schemaRefName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// schemaTypeName describes the type of a value for people, naming
+// referenced schemas
+func schemaTypeName(obj paperless.APISchemaObject) string {
+	switch {
+	case obj.Ref != "":
+		return schemaRefName(obj.Ref)
+	case len(obj.AllOf) == 1:
+		return schemaTypeName(obj.AllOf[0])
+	case len(obj.OneOf)+len(obj.AnyOf) > 0:
+		var names []string
+		for _, alt := range append(obj.OneOf, obj.AnyOf...) {
+			names = append(names, schemaTypeName(alt))
+		}
+		return strings.Join(names, " | ")
+	case obj.Type == "array" && obj.Items != nil:
+		return "array of " + schemaTypeName(*obj.Items)
+	case obj.Format != "":
+		return obj.Type + " (" + obj.Format + ")"
+	case obj.Type != "":
+		return obj.Type
+	}
+	return "any"
+}
+
+// schemaRefs calls add with every schema obj references
+func schemaRefs(obj paperless.APISchemaObject, add func(string)) {
+	if obj.Ref != "" {
+		add(schemaRefName(obj.Ref))
+	}
+	if obj.Items != nil {
+		schemaRefs(*obj.Items, add)
+	}
+	for _, group := range [][]paperless.APISchemaObject{obj.AllOf, obj.OneOf, obj.AnyOf} {
+		for _, alt := range group {
+			schemaRefs(alt, add)
+		}
+	}
+	for _, property := range obj.Properties {
+		schemaRefs(property, add)
+	}
+}
+
+// bodySchema returns the schema of a body, preferring its JSON form
+func bodySchema(content map[string]paperless.APIMediaType) (paperless.APISchemaObject, bool) {
+	if media, ok := content[paperless.ContentTypeJSON]; ok {
+		return media.Schema, true
+	}
+	types := make([]string, 0, len(content))
+	for contentType := range content {
+		types = append(types, contentType)
+	}
+	sort.Strings(types)
+	if len(types) == 0 {
+		return paperless.APISchemaObject{}, false
+	}
+	return content[types[0]].Schema, true
+}
+
+// describeAPIObject summarizes a named schema
+func describeAPIObject(obj paperless.APISchemaObject) apiObject {
+	described := apiObject{
+		Type:        obj.Type,
+		Description: truncatePreview(obj.Description, MaxAPIDescriptionChars),
+		Enum:        obj.Enum,
+	}
+	required := make(map[string]bool, len(obj.Required))
+	for _, name := range obj.Required {
+		required[name] = true
+	}
+	for name, property := range obj.Properties {
+		described.Fields = append(described.Fields, apiField{
+			Name:        name,
+			Type:        schemaTypeName(property),
+			Required:    required[name],
+			ReadOnly:    property.ReadOnly,
+			WriteOnly:   property.WriteOnly,
+			Nullable:    property.Nullable,
+			Description: truncatePreview(property.Description, MaxAPIDescriptionChars),
+			Enum:        property.Enum,
+		})
+	}
+	sort.Slice(described.Fields, func(i, j int) bool {
+		return described.Fields[i].Name < described.Fields[j].Name
+	})
+	return described
+}
+
+// describeAPIResource summarizes the endpoints of resource and the objects
+// they exchange
+func describeAPIResource(schema *paperless.APISchema, resource string) ([]apiEndpoint, map[string]apiObject) {
+	var endpoints []apiEndpoint
+	var pending []string
+	objects := make(map[string]apiObject)
+	add := func(name string) {
+		if _, seen := objects[name]; !seen {
+			objects[name] = apiObject{}
+			pending = append(pending, name)
+		}
+	}
+
+	methodOrder := map[string]int{http.MethodGet: 0, http.MethodPost: 1, http.MethodPut: 2, http.MethodPatch: 3, http.MethodDelete: 4}
+	for path, item := range schema.Paths {
+		if apiResource(path) != resource {
+			continue
+		}
+		for method, op := range item.Operations() {
+			endpoint := apiEndpoint{Method: method, Path: path, Summary: truncatePreview(op.Summary, MaxAPIDescriptionChars)}
+			if endpoint.Summary == "" {
+				endpoint.Summary = truncatePreview(op.Description, MaxAPIDescriptionChars)
+			}
+			for i, param := range op.Parameters {
+				if i == MaxDescribedParameters {
+					endpoint.ParametersTruncated = len(op.Parameters) - i
+					break
+				}
+				endpoint.Parameters = append(endpoint.Parameters, apiParameter{
+					Name:        param.Name,
+					In:          param.In,
+					Type:        schemaTypeName(param.Schema),
+					Required:    param.Required,
+					Description: truncatePreview(param.Description, MaxAPIDescriptionChars),
+				})
+			}
+			if op.RequestBody != nil {
+				if body, ok := bodySchema(op.RequestBody.Content); ok {
+					endpoint.RequestBody = schemaTypeName(body)
+					schemaRefs(body, add)
+				}
+			}
+			for _, status := range []string{"200", "201", "202", "204"} {
+				if response, ok := op.Responses[status]; ok {
+					if body, ok := bodySchema(response.Content); ok {
+						endpoint.Response = schemaTypeName(body)
+						schemaRefs(body, add)
+					}
+					break
+				}
+			}
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		if endpoints[i].Path != endpoints[j].Path {
+			return endpoints[i].Path < endpoints[j].Path
+		}
+		return methodOrder[endpoints[i].Method] < methodOrder[endpoints[j].Method]
+	})
+
+	// Follow references from the exchanged objects to the ones they nest
+	for len(pending) > 0 {
+		name := pending[0]
+		pending = pending[1:]
+		obj, ok := schema.Components.Schemas[name]
+		if !ok {
+			delete(objects, name)
+			continue
+		}
+		objects[name] = describeAPIObject(obj)
+		schemaRefs(obj, add)
+	}
+	return endpoints, objects
+}
+
+// apiResources returns the resources of the API with their endpoint counts
+func apiResources(schema *paperless.APISchema) map[string]int {
+	resources := make(map[string]int)
+	for path, item := range schema.Paths {
+		if resource := apiResource(path); resource != "" {
+			resources[resource] += len(item.Operations())
+		}
+	}
+	return resources
+}
+
+// handleDescribeAPI handles the describe_api tool
+func (s *Server) handleDescribeAPI(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	resource, _ := args["resource"].(string)
+	resource = strings.Trim(strings.ToLower(strings.TrimSpace(resource)), "/")
+	resource = strings.TrimPrefix(resource, "api/")
+
+	logging.FromContext(ctx).Debug("Describing Paperless API", "resource", resource)
+
+	schema, err := s.paperlessClient.GetAPISchema(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to get API schema", "error", err)
+		return nil, fmt.Errorf("failed to get API schema: %w", err)
+	}
+
+	resources := apiResources(schema)
+	if resource == "" {
+		return map[string]interface{}{
+			"api_version": schema.Info.Version,
+			"resources":   resources,
+			"hint":        "pass one of these as resource to see its endpoints and fields",
+		}, nil
+	}
+	if _, ok := resources[resource]; !ok {
+		names := make([]string, 0, len(resources))
+		for name := range resources {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		message := fmt.Sprintf("Paperless has no API resource %q", resource)
+		if suggestion := closestMatch(resource, names); suggestion != "" {
+			message += fmt.Sprintf(", did you mean %q?", suggestion)
+		}
+		return nil, fmt.Errorf("%s; available resources: %s", message, strings.Join(names, ", "))
+	}
+
+	endpoints, objects := describeAPIResource(schema, resource)
+
+	logging.FromContext(ctx).Info("Paperless API described",
+		"resource", resource,
+		"endpoints", len(endpoints),
+		"objects", len(objects))
+
+	return map[string]interface{}{
+		"resource":    resource,
+		"api_version": schema.Info.Version,
+		"endpoints":   endpoints,
+		"objects":     objects,
+	}, nil
+}
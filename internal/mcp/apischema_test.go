@@ -0,0 +1,97 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// testAPISchema is a trimmed Paperless OpenAPI schema
+const testAPISchema = `{
+	"info": {"title": "Paperless-ngx REST API", "version": "6.0.0"},
+	"paths": {
+		"/api/workflows/": {
+			"get": {"operationId": "workflows_list", "parameters": [{"name": "page", "in": "query", "schema": {"type": "integer"}}],
+				"responses": {"200": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/PaginatedWorkflowList"}}}}}},
+			"post": {"operationId": "workflows_create", "requestBody": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/WorkflowRequest"}}}},
+				"responses": {"201": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Workflow"}}}}}}
+		},
+		"/api/workflows/{id}/": {
+			"delete": {"operationId": "workflows_destroy", "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "integer"}}], "responses": {"204": {"description": "No response body"}}}
+		},
+		"/api/tags/": {"get": {"operationId": "tags_list"}}
+	},
+	"components": {"schemas": {
+		"PaginatedWorkflowList": {"type": "object", "properties": {"count": {"type": "integer"}, "results": {"type": "array", "items": {"$ref": "#/components/schemas/Workflow"}}}},
+		"Workflow": {"type": "object", "required": ["name"], "properties": {
+			"id": {"type": "integer", "readOnly": true},
+			"name": {"type": "string", "description": "Workflow name"},
+			"triggers": {"type": "array", "items": {"$ref": "#/components/schemas/WorkflowTrigger"}}
+		}},
+		"WorkflowRequest": {"type": "object", "properties": {"name": {"type": "string"}}},
+		"WorkflowTrigger": {"type": "object", "properties": {"type": {"allOf": [{"$ref": "#/components/schemas/TriggerTypeEnum"}]}}},
+		"TriggerTypeEnum": {"type": "integer", "enum": [1, 2, 3]}
+	}}
+}`
+
+// TestDescribeAPI tests that a resource's endpoints and the objects they
+// exchange, including nested ones, are summarized from the schema
+func TestDescribeAPI(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/schema/" || r.URL.Query().Get("format") != "json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(testAPISchema))
+	}))
+	defer ts.Close()
+
+	s := &Server{paperlessClient: paperless.New(ts.URL, "test-token")}
+
+	result, err := s.handleDescribeAPI(context.Background(), map[string]interface{}{"resource": "/api/Workflows/"})
+	if err != nil {
+		t.Fatalf("describe_api failed: %v", err)
+	}
+	described := result.(map[string]interface{})
+	endpoints := described["endpoints"].([]apiEndpoint)
+	if len(endpoints) != 3 || endpoints[0].Method != "GET" || endpoints[1].Method != "POST" || endpoints[2].Path != "/api/workflows/{id}/" {
+		t.Fatalf("Unexpected endpoints: %+v", endpoints)
+	}
+	if endpoints[0].Response != "PaginatedWorkflowList" || endpoints[1].RequestBody != "WorkflowRequest" {
+		t.Errorf("Expected request and response schemas to be named, got %+v", endpoints[:2])
+	}
+
+	objects := described["objects"].(map[string]apiObject)
+	for _, name := range []string{"PaginatedWorkflowList", "Workflow", "WorkflowRequest", "WorkflowTrigger", "TriggerTypeEnum"} {
+		if _, ok := objects[name]; !ok {
+			t.Errorf("Expected object %s to be described, got %v", name, objects)
+		}
+	}
+	workflow := objects["Workflow"]
+	if len(workflow.Fields) != 3 || workflow.Fields[0].Name != "id" || !workflow.Fields[0].ReadOnly || !workflow.Fields[1].Required {
+		t.Errorf("Unexpected Workflow fields: %+v", workflow.Fields)
+	}
+	if workflow.Fields[2].Type != "array of WorkflowTrigger" {
+		t.Errorf("Expected the triggers type to name its items, got %q", workflow.Fields[2].Type)
+	}
+	if len(objects["TriggerTypeEnum"].Enum) != 3 {
+		t.Errorf("Expected enum values, got %+v", objects["TriggerTypeEnum"])
+	}
+
+	result, err = s.handleDescribeAPI(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("Listing resources failed: %v", err)
+	}
+	if resources := result.(map[string]interface{})["resources"].(map[string]int); resources["workflows"] != 3 || resources["tags"] != 1 {
+		t.Errorf("Unexpected resources: %v", resources)
+	}
+
+	_, err = s.handleDescribeAPI(context.Background(), map[string]interface{}{"resource": "workflow"})
+	if err == nil || !strings.Contains(err.Error(), `did you mean "workflows"?`) {
+		t.Errorf("Expected an unknown resource to suggest the closest one, got %v", err)
+	}
+}
@@ -0,0 +1,218 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// MaxBrowseYears is how many years browse_archive counts at most, so an
+// archive with a few mistyped ancient dates does not cost a request for
+// every year since
+const MaxBrowseYears = 50
+
+// archiveBucket is the number of documents created in one year or month
+type archiveBucket struct {
+	Year      int    `json:"year"`
+	Month     int    `json:"month,omitempty"`
+	MonthName string `json:"month_name,omitempty"`
+	Count     int    `json:"count"`
+}
+
+// countDocuments returns how many documents match filters, reading a single
+// ID
+func (s *Server) countDocuments(ctx context.Context, filters url.Values) (int, error) {
+	counted := url.Values{"fields": {"id"}}
+	for key, values := range filters {
+		counted[key] = values
+	}
+	response, err := s.paperlessClient.ListDocuments(ctx, counted, 1, 1)
+	if err != nil {
+		return 0, err
+	}
+	return response.Count, nil
+}
+
+// createdYear returns the year the first document in ordering was created,
+// or 0 for an empty archive
+func (s *Server) createdYear(ctx context.Context, ordering string) (int, error) {
+	filters := url.Values{"fields": {"id,created"}, "ordering": {ordering}}
+	response, err := s.paperlessClient.ListDocuments(ctx, filters, 1, 1)
+	if err != nil {
+		return 0, err
+	}
+	var documents []paperless.Document
+	if err := json.Unmarshal(response.Results, &documents); err != nil {
+		return 0, fmt.Errorf("failed to parse results: %w", err)
+	}
+	if len(documents) == 0 {
+		return 0, nil
+	}
+	return documents[0].Created.Year(), nil
+}
+
+// handleBrowseArchive handles the browse_archive tool
+func (s *Server) handleBrowseArchive(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	year := 0
+	if yearVal, present := args["year"]; present {
+		yearFloat, ok := yearVal.(float64)
+		if !ok || yearFloat < 1 || yearFloat > 9999 {
+			return nil, fmt.Errorf("year must be a year such as 2022")
+		}
+		year = int(yearFloat)
+	}
+	month := 0
+	if monthVal, present := args["month"]; present {
+		monthFloat, ok := monthVal.(float64)
+		if !ok || monthFloat < 1 || monthFloat > 12 {
+			return nil, fmt.Errorf("month must be between 1 and 12")
+		}
+		if year == 0 {
+			return nil, fmt.Errorf("month needs a year")
+		}
+		month = int(monthFloat)
+	}
+
+	logging.FromContext(ctx).Debug("Browsing archive",
+		"year", year,
+		"month", month)
+
+	switch {
+	case month != 0:
+		return s.browseMonth(ctx, year, month, args)
+	case year != 0:
+		return s.browseYear(ctx, year)
+	}
+	return s.browseYears(ctx)
+}
+
+// browseYears counts the documents created in each year, newest first
+func (s *Server) browseYears(ctx context.Context) (interface{}, error) {
+	newest, err := s.createdYear(ctx, "-created")
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to find the newest document", "error", err)
+		return nil, fmt.Errorf("failed to find the newest document: %w", err)
+	}
+	oldest, err := s.createdYear(ctx, "created")
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to find the oldest document", "error", err)
+		return nil, fmt.Errorf("failed to find the oldest document: %w", err)
+	}
+
+	result := map[string]interface{}{
+		"oldest_year": oldest,
+		"newest_year": newest,
+	}
+	years := []archiveBucket{}
+	total := 0
+	if newest != 0 {
+		last := max(oldest, newest-MaxBrowseYears+1)
+		for year := newest; year >= last; year-- {
+			count, err := s.countDocuments(ctx, url.Values{"created__year": {strconv.Itoa(year)}})
+			if err != nil {
+				logging.FromContext(ctx).Error("Failed to count documents", "year", year, "error", err)
+				return nil, fmt.Errorf("failed to count documents of %d: %w", year, err)
+			}
+			if count > 0 {
+				years = append(years, archiveBucket{Year: year, Count: count})
+				total += count
+			}
+		}
+		if last > oldest {
+			result["note"] = fmt.Sprintf("only the %d years up to %d are counted; documents dated as early as %d exist, pass year to browse them", MaxBrowseYears, newest, oldest)
+		}
+	}
+	result["total"] = total
+	result["years"] = years
+
+	logging.FromContext(ctx).Info("Archive years counted",
+		"years", len(years),
+		"total", total)
+
+	return result, nil
+}
+
+// browseYear counts the documents created in each month of year
+func (s *Server) browseYear(ctx context.Context, year int) (interface{}, error) {
+	months := []archiveBucket{}
+	total := 0
+	for month := 1; month <= 12; month++ {
+		count, err := s.countDocuments(ctx, url.Values{
+			"created__year":  {strconv.Itoa(year)},
+			"created__month": {strconv.Itoa(month)},
+		})
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to count documents", "year", year, "month", month, "error", err)
+			return nil, fmt.Errorf("failed to count documents of %d-%02d: %w", year, month, err)
+		}
+		months = append(months, archiveBucket{Year: year, Month: month, MonthName: time.Month(month).String(), Count: count})
+		total += count
+	}
+
+	logging.FromContext(ctx).Info("Archive months counted",
+		"year", year,
+		"total", total)
+
+	return map[string]interface{}{
+		"year":   year,
+		"total":  total,
+		"months": months,
+	}, nil
+}
+
+// browseMonth lists the documents created in a month, oldest first
+func (s *Server) browseMonth(ctx context.Context, year, month int, args map[string]interface{}) (interface{}, error) {
+	// Extract optional page parameter
+	page := DefaultPage
+	if pageVal, ok := args["page"].(float64); ok {
+		page = int(pageVal)
+		if page < 1 {
+			page = DefaultPage
+		}
+	}
+
+	// Extract optional page_size parameter
+	pageSize := DefaultPageSize
+	if pageSizeVal, ok := args["page_size"].(float64); ok {
+		pageSize = int(pageSizeVal)
+		if pageSize < 1 {
+			pageSize = DefaultPageSize
+		} else if pageSize > MaxPageSize {
+			pageSize = MaxPageSize
+		}
+	}
+
+	filters := url.Values{
+		"created__year":  {strconv.Itoa(year)},
+		"created__month": {strconv.Itoa(month)},
+		"ordering":       {"created"},
+	}
+	response, err := s.paperlessClient.ListDocuments(ctx, filters, page, pageSize)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to list documents", "year", year, "month", month, "error", err)
+		return nil, fmt.Errorf("failed to list documents of %d-%02d: %w", year, month, err)
+	}
+
+	var documents []paperless.Document
+	if err := json.Unmarshal(response.Results, &documents); err != nil {
+		logging.FromContext(ctx).Error("Failed to parse documents results", "error", err)
+		return nil, fmt.Errorf("failed to parse results: %w", err)
+	}
+
+	logging.FromContext(ctx).Info("Archive month listed",
+		"year", year,
+		"month", month,
+		"found", response.Count,
+		"returned", len(documents))
+
+	return newListResult(documents, response.Count, page, pageSize, map[string]interface{}{
+		"created__year":  year,
+		"created__month": month,
+	}), nil
+}
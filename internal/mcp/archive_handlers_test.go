@@ -0,0 +1,81 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// TestBrowseArchive tests the year, month and document levels of
+// browse_archive against an archive with documents in 2021 and 2022
+func TestBrowseArchive(t *testing.T) {
+	counts := map[string]int{"2021": 4, "2022": 7, "2022-3": 5, "2022-11": 2}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/documents/" {
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+		query := r.URL.Query()
+		var results []interface{}
+		count := 0
+		switch {
+		case query.Get("ordering") == "-created":
+			count, results = 11, []interface{}{map[string]interface{}{"id": 9, "created": "2022-11-02"}}
+		case query.Get("ordering") == "created" && query.Get("created__year") == "":
+			count, results = 11, []interface{}{map[string]interface{}{"id": 1, "created": "2021-01-15"}}
+		case query.Get("created__month") != "":
+			count = counts[query.Get("created__year")+"-"+query.Get("created__month")]
+			if query.Get("ordering") == "created" {
+				results = []interface{}{map[string]interface{}{"id": 3, "title": "Lease", "created": "2022-03-01"}}
+			}
+		default:
+			count = counts[query.Get("created__year")]
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"count": count, "next": nil, "results": results})
+	}))
+	defer ts.Close()
+
+	s := &Server{paperlessClient: paperless.New(ts.URL, "test-token")}
+
+	result, err := s.handleBrowseArchive(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("browse_archive failed: %v", err)
+	}
+	m := result.(map[string]interface{})
+	years := m["years"].([]archiveBucket)
+	if m["total"] != 11 || len(years) != 2 || years[0] != (archiveBucket{Year: 2022, Count: 7}) {
+		t.Errorf("Unexpected years %v", m)
+	}
+
+	result, err = s.handleBrowseArchive(context.Background(), map[string]interface{}{"year": float64(2022)})
+	if err != nil {
+		t.Fatalf("browse_archive failed: %v", err)
+	}
+	m = result.(map[string]interface{})
+	months := m["months"].([]archiveBucket)
+	if m["total"] != 7 || len(months) != 12 || months[2] != (archiveBucket{Year: 2022, Month: 3, MonthName: "March", Count: 5}) {
+		t.Errorf("Unexpected months %v", m)
+	}
+
+	result, err = s.handleBrowseArchive(context.Background(), map[string]interface{}{"year": float64(2022), "month": float64(3)})
+	if err != nil {
+		t.Fatalf("browse_archive failed: %v", err)
+	}
+	list := result.(*ListResult)
+	if documents := list.Items.([]paperless.Document); list.Count != 5 || len(documents) != 1 || documents[0].Title != "Lease" {
+		t.Errorf("Unexpected documents %+v", list)
+	}
+
+	for _, args := range []map[string]interface{}{
+		{"month": float64(3)},
+		{"year": float64(2022), "month": float64(13)},
+		{"year": "2022"},
+	} {
+		if _, err := s.handleBrowseArchive(context.Background(), args); err == nil {
+			t.Errorf("Expected %v to fail", args)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// Bulk edit verification modes
+const (
+	BulkVerifyNone   = "none"
+	BulkVerifySample = "sample"
+	BulkVerifyAll    = "all"
+)
+
+// BulkVerifySampleSize is how many edited documents are re-fetched when
+// verifying a bulk edit by sample
+const BulkVerifySampleSize = 20
+
+// verifySample picks up to n IDs spread evenly across ids, always
+// including the first and last, so a sample covers every chunk
+func verifySample(ids []int, n int) []int {
+	if len(ids) <= n {
+		return ids
+	}
+	if n == 1 {
+		return ids[:1]
+	}
+	sample := make([]int, n)
+	for i := range sample {
+		sample[i] = ids[i*(len(ids)-1)/(n-1)]
+	}
+	return sample
+}
+
+// bulkEditMismatches describes the operations that did not take effect on
+// document, as re-fetched after the edit
+func bulkEditMismatches(document paperless.Document, operations map[string]interface{}) []string {
+	has := make(map[int]bool, len(document.Tags))
+	for _, tag := range document.Tags {
+		has[tag] = true
+	}
+
+	var mismatches []string
+	if add, ok := operations["add_tags"].([]int); ok {
+		for _, tag := range add {
+			if !has[tag] {
+				mismatches = append(mismatches, fmt.Sprintf("tag %d was not added", tag))
+			}
+		}
+	}
+	if remove, ok := operations["remove_tags"].([]int); ok {
+		for _, tag := range remove {
+			if has[tag] {
+				mismatches = append(mismatches, fmt.Sprintf("tag %d was not removed", tag))
+			}
+		}
+	}
+	check := func(field string, got *int) {
+		want, ok := operations[field].(int)
+		if !ok {
+			return
+		}
+		if got == nil || *got != want {
+			mismatches = append(mismatches, fmt.Sprintf("%s is %s, expected %d", field, formatOptionalID(got), want))
+		}
+	}
+	check("correspondent", document.Correspondent)
+	check("document_type", document.DocumentType)
+	check("storage_path", document.StoragePath)
+	return mismatches
+}
+
+// formatOptionalID formats an optional object ID for messages
+func formatOptionalID(id *int) string {
+	if id == nil {
+		return "unset"
+	}
+	return strconv.Itoa(*id)
+}
+
+// verifyBulkEdit re-fetches edited documents, all or a sample depending on
+// mode, and reports those the operations did not take effect on. Paperless
+// skips documents the user may not change without failing the request, so
+// this is the only way to notice them. Documents that are no longer
+// visible are reported separately.
+func (s *Server) verifyBulkEdit(ctx context.Context, documentIDs []int, operations map[string]interface{}, mode string) map[string]interface{} {
+	ids := documentIDs
+	if mode == BulkVerifySample {
+		ids = verifySample(documentIDs, BulkVerifySampleSize)
+	}
+
+	report := map[string]interface{}{
+		"mode":    mode,
+		"checked": len(ids),
+	}
+
+	documents, err := s.listDocumentsByID(ctx, ids, "id,tags,correspondent,document_type,storage_path,user_can_change")
+	if err != nil {
+		logging.FromContext(ctx).Warn("Failed to verify bulk edit",
+			"document_count", len(ids),
+			"error", err)
+		report["error"] = fmt.Sprintf("failed to re-fetch documents: %v", err)
+		return report
+	}
+
+	found := make(map[int]bool, len(documents))
+	failed := []map[string]interface{}{}
+	for _, document := range documents {
+		found[document.ID] = true
+		mismatches := bulkEditMismatches(document, operations)
+		if len(mismatches) == 0 {
+			continue
+		}
+		failure := map[string]interface{}{
+			"document_id": document.ID,
+			"mismatches":  mismatches,
+		}
+		if !document.UserCanChange {
+			failure["reason"] = "the Paperless user may not change this document"
+		}
+		failed = append(failed, failure)
+	}
+
+	notFound := []int{}
+	for _, id := range ids {
+		if !found[id] {
+			notFound = append(notFound, id)
+		}
+	}
+
+	report["verified"] = len(documents) - len(failed)
+	report["failed"] = failed
+	report["not_found"] = notFound
+	report["ok"] = len(failed) == 0 && len(notFound) == 0
+
+	if len(failed) > 0 || len(notFound) > 0 {
+		logging.FromContext(ctx).Warn("Bulk edit did not take effect on every document",
+			"checked", len(ids),
+			"failed", len(failed),
+			"not_found", len(notFound))
+	}
+	return report
+}
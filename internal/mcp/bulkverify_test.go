@@ -0,0 +1,56 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// TestVerifyBulkEdit tests that documents Paperless skipped or no longer
+// shows are reported
+func TestVerifyBulkEdit(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Document 2 was skipped for lack of permission, 3 is not visible
+		w.Write([]byte(`{"count":2,"next":null,"results":[
+			{"id":1,"tags":[5,9],"correspondent":4,"user_can_change":true},
+			{"id":2,"tags":[9],"correspondent":null,"user_can_change":false}]}`))
+	}))
+	defer ts.Close()
+
+	s := &Server{paperlessClient: paperless.New(ts.URL, "test-token")}
+	operations := map[string]interface{}{
+		"add_tags":      []int{5},
+		"correspondent": 4,
+	}
+
+	report := s.verifyBulkEdit(context.Background(), []int{1, 2, 3}, operations, BulkVerifyAll)
+	if report["ok"] != false || report["verified"] != 1 {
+		t.Errorf("Expected 1 verified document and ok=false, got %v", report)
+	}
+	failed := report["failed"].([]map[string]interface{})
+	if len(failed) != 1 || failed[0]["document_id"] != 2 || failed[0]["reason"] == nil {
+		t.Fatalf("Expected document 2 to fail with a reason, got %v", failed)
+	}
+	want := []string{"tag 5 was not added", "correspondent is unset, expected 4"}
+	if mismatches := failed[0]["mismatches"]; !reflect.DeepEqual(mismatches, want) {
+		t.Errorf("Expected mismatches %v, got %v", want, mismatches)
+	}
+	if notFound := report["not_found"]; !reflect.DeepEqual(notFound, []int{3}) {
+		t.Errorf("Expected document 3 not to be found, got %v", notFound)
+	}
+}
+
+// TestVerifySample tests that samples span the edited documents
+func TestVerifySample(t *testing.T) {
+	ids := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	if got, want := verifySample(ids, 4), []int{1, 4, 7, 10}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if got := verifySample(ids, 20); len(got) != len(ids) {
+		t.Errorf("Expected all %d documents, got %v", len(ids), got)
+	}
+}
@@ -0,0 +1,61 @@
+package mcp
+
+import (
+	"context"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+)
+
+// handleGetCacheStats handles the get_cache_stats tool
+func (s *Server) handleGetCacheStats(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	stats := s.documents.snapshot()
+
+	logging.FromContext(ctx).Info("Cache statistics",
+		"cache", "documents",
+		"hits", stats.Hits,
+		"misses", stats.Misses,
+		"evictions", stats.Evictions,
+		"entries", stats.Entries)
+
+	return map[string]interface{}{
+		"documents": map[string]interface{}{
+			"enabled":   stats.Capacity > 0,
+			"stats":     stats,
+			"hit_ratio": stats.HitRatio(),
+		},
+		"metadata": s.metadata.snapshot(),
+	}, nil
+}
+
+// handleClearCache handles the clear_cache tool
+func (s *Server) handleClearCache(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	var session *cacheScope
+	scope := "all"
+	if current, _ := args["current_session_only"].(bool); current {
+		current := documentScope(ctx)
+		session = &current
+		scope = "session"
+	}
+
+	removed := s.documents.clear(session)
+	if session == nil {
+		s.metadata.invalidateAll()
+	}
+	stats := s.documents.snapshot()
+
+	logging.FromContext(ctx).Info("Cache cleared",
+		"cache", "documents",
+		"scope", scope,
+		"removed", removed,
+		"hits", stats.Hits,
+		"misses", stats.Misses,
+		"evictions", stats.Evictions)
+
+	return map[string]interface{}{
+		"success": true,
+		"scope":   scope,
+		"removed": map[string]interface{}{
+			"documents": removed,
+		},
+	}, nil
+}
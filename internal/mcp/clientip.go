@@ -0,0 +1,75 @@
+package mcp
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// ForwardedForHeader is the header reverse proxies use to pass the
+// original client address
+const ForwardedForHeader = "X-Forwarded-For"
+
+// clientIP returns the address of the client that made the request.
+//
+// X-Forwarded-For is only honoured when the direct peer is a trusted proxy
+// (MCP_TRUSTED_PROXIES). The header is walked from right to left, skipping
+// further trusted proxies, and the first untrusted address is the client.
+// Without trusted proxies the peer address is always used, so clients
+// cannot spoof their address by sending the header themselves.
+func (s *Server) clientIP(r *http.Request) string {
+	peer := remoteAddr(r)
+	if len(s.cfg.MCPTrustedProxies) == 0 || !s.isTrustedProxy(peer) {
+		return formatAddr(peer, r.RemoteAddr)
+	}
+
+	forwarded := strings.Split(strings.Join(r.Header.Values(ForwardedForHeader), ","), ",")
+	client := peer
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		addr, err := netip.ParseAddr(strings.TrimSpace(forwarded[i]))
+		if err != nil {
+			// Malformed entries end the trusted chain
+			break
+		}
+		client = addr.Unmap()
+		if !s.isTrustedProxy(client) {
+			break
+		}
+	}
+	return formatAddr(client, r.RemoteAddr)
+}
+
+// isTrustedProxy checks whether addr falls within a trusted proxy range
+func (s *Server) isTrustedProxy(addr netip.Addr) bool {
+	if !addr.IsValid() {
+		return false
+	}
+	for _, prefix := range s.cfg.MCPTrustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteAddr parses the peer address of the connection
+func remoteAddr(r *http.Request) netip.Addr {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}
+	}
+	return addr.Unmap()
+}
+
+// formatAddr renders addr, falling back to the raw value when unparseable
+func formatAddr(addr netip.Addr, fallback string) string {
+	if !addr.IsValid() {
+		return fallback
+	}
+	return addr.String()
+}
@@ -0,0 +1,209 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// ContentStats describes the similarity of two documents' content
+type ContentStats struct {
+	LengthA           int     `json:"length_a"`
+	LengthB           int     `json:"length_b"`
+	WordsA            int     `json:"words_a"`
+	WordsB            int     `json:"words_b"`
+	CommonWords       int     `json:"common_words"`
+	JaccardSimilarity float64 `json:"jaccard_similarity"`
+	Identical         bool    `json:"identical"`
+}
+
+// contentWords returns the set of lower-cased words in text
+func contentWords(text string) (map[string]struct{}, int) {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	set := make(map[string]struct{}, len(words))
+	for _, word := range words {
+		set[word] = struct{}{}
+	}
+	return set, len(words)
+}
+
+// compareContent computes similarity statistics for two content strings
+// using the Jaccard index of their distinct words
+func compareContent(a, b string) ContentStats {
+	setA, countA := contentWords(a)
+	setB, countB := contentWords(b)
+
+	common := 0
+	for word := range setA {
+		if _, ok := setB[word]; ok {
+			common++
+		}
+	}
+
+	similarity := 1.0
+	if union := len(setA) + len(setB) - common; union > 0 {
+		similarity = float64(common) / float64(union)
+	}
+
+	return ContentStats{
+		LengthA:           len(a),
+		LengthB:           len(b),
+		WordsA:            countA,
+		WordsB:            countB,
+		CommonWords:       common,
+		JaccardSimilarity: similarity,
+		Identical:         strings.TrimSpace(a) == strings.TrimSpace(b),
+	}
+}
+
+// diffIDs splits two ID lists into those only in a, only in b, and in both
+func diffIDs(a, b []int) (onlyA, onlyB, both []int) {
+	inB := make(map[int]bool, len(b))
+	for _, id := range b {
+		inB[id] = true
+	}
+	inA := make(map[int]bool, len(a))
+	for _, id := range a {
+		inA[id] = true
+		if inB[id] {
+			both = append(both, id)
+		} else {
+			onlyA = append(onlyA, id)
+		}
+	}
+	for _, id := range b {
+		if !inA[id] {
+			onlyB = append(onlyB, id)
+		}
+	}
+	return onlyA, onlyB, both
+}
+
+// compareDocuments returns the metadata fields that differ between a and b
+func compareDocuments(a, b *paperless.Document) map[string]interface{} {
+	differences := make(map[string]interface{})
+
+	fields := []struct {
+		name string
+		a, b interface{}
+	}{
+		{"title", a.Title, b.Title},
+		{"created_date", a.CreatedDate, b.CreatedDate},
+		{"added", a.Added, b.Added},
+		{"correspondent", a.Correspondent, b.Correspondent},
+		{"document_type", a.DocumentType, b.DocumentType},
+		{"storage_path", a.StoragePath, b.StoragePath},
+		{"archive_serial_number", a.ArchiveSerialNumber, b.ArchiveSerialNumber},
+		{"original_file_name", a.OriginalFileName, b.OriginalFileName},
+	}
+	for _, field := range fields {
+		if !reflect.DeepEqual(field.a, field.b) {
+			differences[field.name] = fieldChange{From: field.a, To: field.b}
+		}
+	}
+
+	if onlyA, onlyB, _ := diffIDs(a.Tags, b.Tags); len(onlyA) > 0 || len(onlyB) > 0 {
+		differences["tags"] = map[string]interface{}{
+			"only_in_a": onlyA,
+			"only_in_b": onlyB,
+		}
+	}
+
+	valuesA := make(map[int]interface{}, len(a.CustomFields))
+	for _, cf := range a.CustomFields {
+		valuesA[cf.Field] = cf.Value
+	}
+	valuesB := make(map[int]interface{}, len(b.CustomFields))
+	for _, cf := range b.CustomFields {
+		valuesB[cf.Field] = cf.Value
+	}
+	var fieldIDs []int
+	for id := range valuesA {
+		fieldIDs = append(fieldIDs, id)
+	}
+	for id := range valuesB {
+		if _, ok := valuesA[id]; !ok {
+			fieldIDs = append(fieldIDs, id)
+		}
+	}
+	sort.Ints(fieldIDs)
+
+	var customFields []map[string]interface{}
+	for _, id := range fieldIDs {
+		valueA, inA := valuesA[id]
+		valueB, inB := valuesB[id]
+		if inA && inB && reflect.DeepEqual(valueA, valueB) {
+			continue
+		}
+		customFields = append(customFields, map[string]interface{}{
+			"field":   id,
+			"in_a":    inA,
+			"in_b":    inB,
+			"value_a": valueA,
+			"value_b": valueB,
+		})
+	}
+	if len(customFields) > 0 {
+		differences["custom_fields"] = customFields
+	}
+
+	return differences
+}
+
+// handleCompareDocuments handles the compare_documents tool
+func (s *Server) handleCompareDocuments(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	documentAFloat, ok := args["document_id_a"].(float64)
+	if !ok || documentAFloat < 1 {
+		return nil, fmt.Errorf("document_id_a parameter is required and must be a positive integer")
+	}
+	documentBFloat, ok := args["document_id_b"].(float64)
+	if !ok || documentBFloat < 1 {
+		return nil, fmt.Errorf("document_id_b parameter is required and must be a positive integer")
+	}
+	documentIDA, documentIDB := int(documentAFloat), int(documentBFloat)
+	if documentIDA == documentIDB {
+		return nil, fmt.Errorf("document_id_a and document_id_b must be different documents")
+	}
+
+	logging.FromContext(ctx).Debug("Comparing documents",
+		"document_id_a", documentIDA,
+		"document_id_b", documentIDB)
+
+	documentA, err := s.getDocument(ctx, documentIDA)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to get document", "document_id", documentIDA, "error", err)
+		return nil, fmt.Errorf("failed to get document %d: %w", documentIDA, err)
+	}
+	documentB, err := s.getDocument(ctx, documentIDB)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to get document", "document_id", documentIDB, "error", err)
+		return nil, fmt.Errorf("failed to get document %d: %w", documentIDB, err)
+	}
+
+	differences := compareDocuments(documentA, documentB)
+	_, _, commonTags := diffIDs(documentA.Tags, documentB.Tags)
+	content := compareContent(documentA.Content, documentB.Content)
+
+	logging.FromContext(ctx).Info("Document comparison completed",
+		"document_id_a", documentIDA,
+		"document_id_b", documentIDB,
+		"differences", len(differences),
+		"similarity", content.JaccardSimilarity)
+
+	return map[string]interface{}{
+		"document_id_a":      documentIDA,
+		"document_id_b":      documentIDB,
+		"metadata_identical": len(differences) == 0,
+		"differences":        differences,
+		"common_tags":        commonTags,
+		"content":            content,
+	}, nil
+}
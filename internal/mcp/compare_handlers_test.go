@@ -0,0 +1,56 @@
+package mcp
+
+import (
+	"testing"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+func TestCompareContent(t *testing.T) {
+	stats := compareContent("Invoice 42 from ACME", "invoice 42 from acme")
+	if stats.JaccardSimilarity != 1 {
+		t.Errorf("JaccardSimilarity = %v, want 1", stats.JaccardSimilarity)
+	}
+	if stats.Identical {
+		t.Error("Identical = true for content differing in case")
+	}
+
+	stats = compareContent("alpha beta", "beta gamma")
+	if stats.CommonWords != 1 || stats.JaccardSimilarity != 1.0/3 {
+		t.Errorf("got common %d similarity %v, want 1 and 1/3", stats.CommonWords, stats.JaccardSimilarity)
+	}
+}
+
+func TestCompareDocuments(t *testing.T) {
+	correspondent := 3
+	a := &paperless.Document{
+		Title:         "Invoice",
+		Tags:          []int{1, 2},
+		Correspondent: &correspondent,
+		CustomFields:  []paperless.CustomFieldValue{{Field: 5, Value: "x"}},
+	}
+	b := &paperless.Document{
+		Title:         "Invoice",
+		Tags:          []int{2, 3},
+		Correspondent: &correspondent,
+		CustomFields:  []paperless.CustomFieldValue{{Field: 5, Value: "y"}, {Field: 6, Value: 1.0}},
+	}
+
+	diff := compareDocuments(a, b)
+	if _, ok := diff["title"]; ok {
+		t.Error("title reported as different")
+	}
+	if _, ok := diff["correspondent"]; ok {
+		t.Error("correspondent reported as different")
+	}
+	tags, ok := diff["tags"].(map[string]interface{})
+	if !ok {
+		t.Fatal("tags not reported as different")
+	}
+	if onlyA := tags["only_in_a"].([]int); len(onlyA) != 1 || onlyA[0] != 1 {
+		t.Errorf("only_in_a = %v, want [1]", onlyA)
+	}
+	if fields := diff["custom_fields"].([]map[string]interface{}); len(fields) != 2 {
+		t.Errorf("custom_fields differences = %d, want 2", len(fields))
+	}
+}
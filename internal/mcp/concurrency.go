@@ -0,0 +1,70 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// ConflictError is returned by update tools given an expected_modified
+// timestamp that no longer matches the document, because it was changed
+// elsewhere, such as in the web UI, since the caller read it
+type ConflictError struct {
+	DocumentID int
+	Expected   time.Time
+	Current    *paperless.Document
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("document %d was modified at %s, after the expected %s; it was not updated, re-read it and retry",
+		e.DocumentID,
+		e.Current.Modified.UTC().Format(time.RFC3339),
+		e.Expected.UTC().Format(time.RFC3339))
+}
+
+// parseExpectedModified reads the optional expected_modified argument, in
+// the RFC 3339 form documents are returned with
+func parseExpectedModified(args map[string]interface{}) (time.Time, bool, error) {
+	value, ok := args["expected_modified"].(string)
+	if !ok || value == "" {
+		return time.Time{}, false, nil
+	}
+	expected, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("expected_modified must be an RFC 3339 timestamp such as 2024-03-15T10:04:05Z, as returned in a document's modified field")
+	}
+	return expected, true, nil
+}
+
+// checkExpectedModified fetches the document from Paperless, bypassing the
+// session cache, and returns a *ConflictError when its modified timestamp
+// differs from expected. Timestamps are compared at the precision given,
+// as documents are returned with whole seconds. Paperless has no
+// conditional update, so an edit landing between this check and the
+// update can still be overwritten.
+func (s *Server) checkExpectedModified(ctx context.Context, documentID int, expected time.Time) error {
+	current, err := s.paperlessClient.GetDocument(ctx, documentID)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to get document",
+			"document_id", documentID,
+			"error", err)
+		return fmt.Errorf("failed to get document: %w", err)
+	}
+
+	modified := current.Modified.Time
+	if expected.Nanosecond() == 0 {
+		modified = modified.Truncate(time.Second)
+	}
+	if modified.Equal(expected) {
+		return nil
+	}
+
+	logging.FromContext(ctx).Warn("Refusing to update a document modified since it was read",
+		"document_id", documentID,
+		"expected_modified", expected,
+		"modified", current.Modified.Time)
+	return &ConflictError{DocumentID: documentID, Expected: expected, Current: current}
+}
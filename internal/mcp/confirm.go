@@ -0,0 +1,111 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+)
+
+// ConfirmationTimeout bounds how long an operation waits for the end user
+// to answer a confirmation prompt
+const ConfirmationTimeout = 5 * time.Minute
+
+// ErrNotConfirmed is returned when the end user declines or dismisses a
+// confirmation prompt
+var ErrNotConfirmed = errors.New("operation was not confirmed by the user")
+
+// confirmationSchema asks for a single yes/no answer
+var confirmationSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"confirm": map[string]interface{}{
+			"type":        "boolean",
+			"title":       "Proceed",
+			"description": "Check to carry out the operation",
+		},
+	},
+	"required": []string{"confirm"},
+}
+
+// supportsElicitation reports whether the calling client declared the
+// elicitation capability
+func supportsElicitation(ctx context.Context) bool {
+	session, ok := server.ClientSessionFromContext(ctx).(server.SessionWithClientInfo)
+	return ok && session.GetClientCapabilities().Elicitation != nil
+}
+
+// confirmationEnabled reports whether confirm would prompt the end user
+func (s *Server) confirmationEnabled(ctx context.Context) bool {
+	return s.cfg.MCPConfirmThreshold > 0 && supportsElicitation(ctx)
+}
+
+// describeBulkEdit summarizes bulk edit operations for a confirmation
+// prompt, e.g. "add tags [3 4], set correspondent 7"
+func describeBulkEdit(operations map[string]interface{}) string {
+	keys := make([]string, 0, len(operations))
+	for key := range operations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		name := strings.ReplaceAll(key, "_", " ")
+		if !strings.HasPrefix(key, "add_") && !strings.HasPrefix(key, "remove_") {
+			name = "set " + name
+		}
+		parts = append(parts, fmt.Sprintf("%s %v", name, operations[key]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// confirm asks the end user, not the agent, to approve an operation
+// described by message. Clients without elicitation support, and servers
+// with confirmation prompts disabled, proceed without asking; any answer
+// other than an explicit yes cancels the operation.
+func (s *Server) confirm(ctx context.Context, tool, message string) error {
+	if !s.confirmationEnabled(ctx) {
+		return nil
+	}
+
+	logging.FromContext(ctx).Debug("Requesting confirmation", "tool", tool)
+
+	ctx, cancel := context.WithTimeout(ctx, ConfirmationTimeout)
+	defer cancel()
+
+	result, err := s.mcpServer.RequestElicitation(ctx, mcp.ElicitationRequest{
+		Params: mcp.ElicitationParams{
+			Message:         message,
+			RequestedSchema: confirmationSchema,
+		},
+	})
+	if err != nil {
+		logging.FromContext(ctx).Error("Confirmation request failed", "tool", tool, "error", err)
+		return fmt.Errorf("could not ask the user for confirmation: %w", err)
+	}
+
+	confirmed := false
+	if result.Action == mcp.ElicitationResponseActionAccept {
+		if content, ok := result.Content.(map[string]interface{}); ok {
+			confirmed, _ = content["confirm"].(bool)
+		}
+	}
+
+	logging.FromContext(ctx).Info("Confirmation answered",
+		"tool", tool,
+		"action", result.Action,
+		"confirmed", confirmed)
+
+	if !confirmed {
+		return ErrNotConfirmed
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// elicitingSession is a client session that answers elicitation requests
+// with a fixed response
+type elicitingSession struct {
+	capabilities mcp.ClientCapabilities
+	response     mcp.ElicitationResponse
+	asked        []string
+}
+
+func (s *elicitingSession) Initialize()                                         {}
+func (s *elicitingSession) Initialized() bool                                   { return true }
+func (s *elicitingSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return nil }
+func (s *elicitingSession) SessionID() string                                   { return "test-session" }
+func (s *elicitingSession) GetClientInfo() mcp.Implementation                   { return mcp.Implementation{} }
+func (s *elicitingSession) SetClientInfo(mcp.Implementation)                    {}
+func (s *elicitingSession) GetClientCapabilities() mcp.ClientCapabilities       { return s.capabilities }
+func (s *elicitingSession) SetClientCapabilities(mcp.ClientCapabilities)        {}
+
+func (s *elicitingSession) RequestElicitation(ctx context.Context, request mcp.ElicitationRequest) (*mcp.ElicitationResult, error) {
+	s.asked = append(s.asked, request.Params.Message)
+	return &mcp.ElicitationResult{ElicitationResponse: s.response}, nil
+}
+
+// TestConfirm tests that only an explicit yes from an elicitation-capable
+// client confirms, and that other clients are not prompted
+func TestConfirm(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0", server.WithElicitation())
+	s := &Server{cfg: &config.Config{MCPConfirmThreshold: 50}, mcpServer: mcpServer}
+
+	if err := s.confirm(context.Background(), "delete_document", "Delete?"); err != nil {
+		t.Errorf("Expected no prompt without a session, got %v", err)
+	}
+
+	legacy := &elicitingSession{}
+	if err := s.confirm(mcpServer.WithContext(context.Background(), legacy), "delete_document", "Delete?"); err != nil || len(legacy.asked) != 0 {
+		t.Errorf("Expected clients without elicitation not to be prompted, got %v", err)
+	}
+
+	capable := mcp.ClientCapabilities{Elicitation: &struct{}{}}
+	tests := []struct {
+		response mcp.ElicitationResponse
+		want     error
+	}{
+		{mcp.ElicitationResponse{Action: mcp.ElicitationResponseActionAccept, Content: map[string]interface{}{"confirm": true}}, nil},
+		{mcp.ElicitationResponse{Action: mcp.ElicitationResponseActionAccept, Content: map[string]interface{}{"confirm": false}}, ErrNotConfirmed},
+		{mcp.ElicitationResponse{Action: mcp.ElicitationResponseActionDecline}, ErrNotConfirmed},
+		{mcp.ElicitationResponse{Action: mcp.ElicitationResponseActionCancel}, ErrNotConfirmed},
+	}
+	for _, tt := range tests {
+		session := &elicitingSession{capabilities: capable, response: tt.response}
+		err := s.confirm(mcpServer.WithContext(context.Background(), session), "delete_document", "Delete document 4?")
+		if !errors.Is(err, tt.want) {
+			t.Errorf("Response %+v: expected %v, got %v", tt.response, tt.want, err)
+		}
+		if len(session.asked) != 1 || session.asked[0] != "Delete document 4?" {
+			t.Errorf("Expected one prompt, got %v", session.asked)
+		}
+	}
+
+	s.cfg.MCPConfirmThreshold = 0
+	session := &elicitingSession{capabilities: capable}
+	if err := s.confirm(mcpServer.WithContext(context.Background(), session), "delete_document", "Delete?"); err != nil || len(session.asked) != 0 {
+		t.Errorf("Expected prompts to be disabled, got %v", err)
+	}
+}
+
+// TestDescribeBulkEdit tests the operation summary shown to the user
+func TestDescribeBulkEdit(t *testing.T) {
+	got := describeBulkEdit(map[string]interface{}{
+		"remove_tags":   []int{5},
+		"add_tags":      []int{3, 4},
+		"correspondent": 7,
+	})
+	want := "add tags [3 4], set correspondent 7, remove tags [5]"
+	if got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
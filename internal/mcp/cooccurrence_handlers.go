@@ -0,0 +1,219 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// Limits of the get_tag_cooccurrence tool
+const (
+	DefaultCooccurrenceSample = 1000
+	MaxCooccurrenceSample     = 10000
+	DefaultCooccurrenceLimit  = 20
+	DefaultCooccurrenceMin    = 3
+)
+
+// Conditional frequencies above which a tag pair is suggested for
+// simplification
+const (
+	cooccurrenceMergeRatio   = 0.9  // each tag comes with the other this often
+	cooccurrenceImpliesRatio = 0.95 // one tag comes with the other this often
+)
+
+// tagPair is how often two tags appear on the same documents
+type tagPair struct {
+	TagA      int     `json:"tag_a"`
+	TagAName  string  `json:"tag_a_name,omitempty"`
+	TagB      int     `json:"tag_b"`
+	TagBName  string  `json:"tag_b_name,omitempty"`
+	Count     int     `json:"count"`
+	Jaccard   float64 `json:"jaccard"`
+	AWithB    float64 `json:"a_with_b"` // share of documents tagged A that are also tagged B
+	BWithA    float64 `json:"b_with_a"`
+	Suggested string  `json:"suggestion,omitempty"`
+}
+
+// roundRatio keeps two decimals, enough to compare pairs
+func roundRatio(ratio float64) float64 {
+	return math.Round(ratio*100) / 100
+}
+
+// suggestSimplification proposes a taxonomy change for a pair that nearly
+// always appears together, or returns ""
+func suggestSimplification(pair tagPair) string {
+	nameA, nameB := pair.TagAName, pair.TagBName
+	if nameA == "" || nameB == "" {
+		nameA, nameB = fmt.Sprintf("tag %d", pair.TagA), fmt.Sprintf("tag %d", pair.TagB)
+	}
+	switch {
+	case pair.AWithB >= cooccurrenceMergeRatio && pair.BWithA >= cooccurrenceMergeRatio:
+		return fmt.Sprintf("%q and %q nearly always appear together; consider merging them", nameA, nameB)
+	case pair.AWithB >= cooccurrenceImpliesRatio:
+		return fmt.Sprintf("documents tagged %q are nearly always tagged %q; consider nesting %q under %q or assigning %q automatically", nameA, nameB, nameA, nameB, nameB)
+	case pair.BWithA >= cooccurrenceImpliesRatio:
+		return fmt.Sprintf("documents tagged %q are nearly always tagged %q; consider nesting %q under %q or assigning %q automatically", nameB, nameA, nameB, nameA, nameA)
+	}
+	return ""
+}
+
+// handleGetTagCooccurrence handles the get_tag_cooccurrence tool
+func (s *Server) handleGetTagCooccurrence(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	sampleSize := boundedIntArg(args, "sample_size", DefaultCooccurrenceSample, MaxCooccurrenceSample)
+	limit := boundedIntArg(args, "limit", DefaultCooccurrenceLimit, MaxPageSize)
+	minCount := boundedIntArg(args, "min_count", DefaultCooccurrenceMin, math.MaxInt32)
+	tagID := 0
+	if tagVal, present := args["tag_id"]; present {
+		tagFloat, ok := tagVal.(float64)
+		if !ok || tagFloat < 1 {
+			return nil, fmt.Errorf("tag_id must be a positive integer")
+		}
+		tagID = int(tagFloat)
+	}
+
+	logging.FromContext(ctx).Debug("Computing tag co-occurrence",
+		"sample_size", sampleSize,
+		"tag_id", tagID)
+
+	// Only the tags of each document are needed; the most recently added
+	// documents make up the sample
+	filters := url.Values{}
+	filters.Set("fields", "id,tags")
+	filters.Set("ordering", "-added")
+	if tagID != 0 {
+		filters.Set("tags__id__all", fmt.Sprint(tagID))
+	}
+
+	tagCounts := make(map[int]int)
+	pairCounts := make(map[[2]int]int)
+	scanned, total := 0, 0
+	for page := 1; scanned < sampleSize; page++ {
+		response, err := s.paperlessClient.ListDocuments(ctx, filters, page, paperless.MaxPageSize)
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to list documents", "page", page, "error", err)
+			return nil, fmt.Errorf("failed to list documents: %w", err)
+		}
+		total = response.Count
+
+		var documents []paperless.Document
+		if err := json.Unmarshal(response.Results, &documents); err != nil {
+			return nil, fmt.Errorf("failed to parse documents: %w", err)
+		}
+
+		for _, document := range documents {
+			if scanned >= sampleSize {
+				break
+			}
+			scanned++
+			tags := append([]int(nil), document.Tags...)
+			sort.Ints(tags)
+			for i, a := range tags {
+				tagCounts[a]++
+				for _, b := range tags[i+1:] {
+					if a != b {
+						pairCounts[[2]int{a, b}]++
+					}
+				}
+			}
+		}
+
+		if response.Next == nil || len(documents) == 0 {
+			break
+		}
+	}
+
+	names := make(map[int]string)
+	documentCounts := make(map[int]int)
+	if tags, err := s.metadata.tags.get(ctx, s.paperlessClient); err != nil {
+		// Without a tag filter the sample holds every count needed; with
+		// one, the partners' totals come from the tags
+		if tagID != 0 {
+			logging.FromContext(ctx).Error("Failed to list tags", "error", err)
+			return nil, fmt.Errorf("failed to list tags: %w", err)
+		}
+		logging.FromContext(ctx).Warn("Failed to look up tag names", "error", err)
+	} else {
+		for _, tag := range tags {
+			names[tag.ID] = tag.Name
+			documentCounts[tag.ID] = tag.DocumentCount
+		}
+	}
+
+	// The sample's counts serve as the totals of each tag, unless it holds
+	// only the documents of tag_id: then the partners' counts in it are just
+	// their documents shared with tag_id, so their totals are the tags'
+	// document counts, and the shared count is scaled from the sample to
+	// all documents of tag_id
+	totals := make(map[int]float64, len(tagCounts))
+	for id, count := range tagCounts {
+		totals[id] = float64(count)
+	}
+	scale := 1.0
+	if tagID != 0 && scanned > 0 {
+		scale = float64(total) / float64(scanned)
+		for id := range totals {
+			if id == tagID {
+				totals[id] = float64(total)
+			} else if count := documentCounts[id]; count > 0 {
+				totals[id] = float64(count)
+			}
+		}
+	}
+
+	pairs := []tagPair{}
+	for key, count := range pairCounts {
+		if count < minCount || tagID != 0 && key[0] != tagID && key[1] != tagID {
+			continue
+		}
+		a, b := key[0], key[1]
+		shared := min(float64(count)*scale, totals[a], totals[b])
+		pair := tagPair{
+			TagA:     a,
+			TagAName: names[a],
+			TagB:     b,
+			TagBName: names[b],
+			Count:    count,
+			Jaccard:  roundRatio(shared / (totals[a] + totals[b] - shared)),
+			AWithB:   roundRatio(shared / totals[a]),
+			BWithA:   roundRatio(shared / totals[b]),
+		}
+		pair.Suggested = suggestSimplification(pair)
+		pairs = append(pairs, pair)
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].Count != pairs[j].Count {
+			return pairs[i].Count > pairs[j].Count
+		}
+		if pairs[i].Jaccard != pairs[j].Jaccard {
+			return pairs[i].Jaccard > pairs[j].Jaccard
+		}
+		return pairs[i].TagA < pairs[j].TagA || pairs[i].TagA == pairs[j].TagA && pairs[i].TagB < pairs[j].TagB
+	})
+	found := len(pairs)
+	pairs = pairs[:min(len(pairs), limit)]
+
+	complete := scanned >= total
+
+	logging.FromContext(ctx).Info("Tag co-occurrence computed",
+		"scanned", scanned,
+		"total", total,
+		"pairs", found)
+
+	result := map[string]interface{}{
+		"scanned":  scanned,
+		"total":    total,
+		"complete": complete,
+		"count":    found,
+		"pairs":    pairs,
+	}
+	if !complete {
+		result["note"] = fmt.Sprintf("statistics cover the %d most recently added of %d documents; raise sample_size for the whole archive", scanned, total)
+	}
+	return result, nil
+}
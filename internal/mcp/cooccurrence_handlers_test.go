@@ -0,0 +1,124 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// TestGetTagCooccurrence tests that tag pairs are counted across pages of
+// documents and that nearly-always-paired tags come with a suggestion
+func TestGetTagCooccurrence(t *testing.T) {
+	// Tags 1 and 2 always appear together, tag 3 nearly always with 1
+	var documents []map[string]interface{}
+	for i := 0; i < 150; i++ {
+		tags := []int{1, 2}
+		if i < 40 {
+			tags = append(tags, 3)
+		}
+		if i%50 == 0 {
+			tags = []int{4}
+		}
+		documents = append(documents, map[string]interface{}{"id": i + 1, "tags": tags})
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/documents/":
+			if got := r.URL.Query().Get("fields"); got != "id,tags" {
+				t.Errorf("Expected only ids and tags to be requested, got %q", got)
+			}
+			matching := documents
+			if tag := r.URL.Query().Get("tags__id__all"); tag != "" {
+				matching = nil
+				for _, document := range documents {
+					for _, id := range document["tags"].([]int) {
+						if fmt.Sprint(id) == tag {
+							matching = append(matching, document)
+						}
+					}
+				}
+			}
+			start, next := 0, interface{}(nil)
+			if r.URL.Query().Get("page") == "2" {
+				start = paperless.MaxPageSize
+			} else if len(matching) > paperless.MaxPageSize {
+				next = "next"
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"count":   len(matching),
+				"next":    next,
+				"results": matching[start:min(start+paperless.MaxPageSize, len(matching))],
+			})
+		case "/api/tags/":
+			json.NewEncoder(w).Encode(map[string]interface{}{"count": 3, "next": nil, "results": []interface{}{
+				map[string]interface{}{"id": 1, "name": "Finance", "document_count": 147},
+				map[string]interface{}{"id": 2, "name": "Money", "document_count": 147},
+				map[string]interface{}{"id": 3, "name": "Tax", "document_count": 39},
+			}})
+		default:
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+	}))
+	defer ts.Close()
+
+	s := &Server{paperlessClient: paperless.New(ts.URL, "test-token")}
+
+	result, err := s.handleGetTagCooccurrence(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("get_tag_cooccurrence failed: %v", err)
+	}
+	m := result.(map[string]interface{})
+	if m["scanned"] != 150 || m["complete"] != true {
+		t.Errorf("Expected all 150 documents to be scanned, got %v", m)
+	}
+	pairs := m["pairs"].([]tagPair)
+	if len(pairs) != 3 {
+		t.Fatalf("Expected 3 pairs, got %+v", pairs)
+	}
+	if pairs[0].TagA != 1 || pairs[0].TagB != 2 || pairs[0].Count != 147 || pairs[0].Jaccard != 1 ||
+		!strings.Contains(pairs[0].Suggested, "merging") {
+		t.Errorf("Unexpected first pair %+v", pairs[0])
+	}
+	if pairs[1].TagAName != "Finance" || pairs[1].TagB != 3 || pairs[1].BWithA != 1 ||
+		!strings.Contains(pairs[1].Suggested, `tagged "Tax" are nearly always tagged "Finance"`) {
+		t.Errorf("Unexpected second pair %+v", pairs[1])
+	}
+
+	// tag_id samples only the documents of tag 3, so tag 1's total comes
+	// from its document count: Tax is nearly always Finance but not the
+	// other way round, whether or not the sample is complete
+	for _, sampleSize := range []int{1000, 20} {
+		result, err = s.handleGetTagCooccurrence(context.Background(), map[string]interface{}{
+			"sample_size": float64(sampleSize),
+			"tag_id":      float64(3),
+		})
+		if err != nil {
+			t.Fatalf("get_tag_cooccurrence failed: %v", err)
+		}
+		m = result.(map[string]interface{})
+		complete := sampleSize > 39
+		if m["complete"] != complete || (m["note"] == nil) == !complete {
+			t.Errorf("Expected complete=%v for a sample of %d, got %v", complete, sampleSize, m)
+		}
+		pairs = m["pairs"].([]tagPair)
+		if len(pairs) != 2 {
+			t.Fatalf("Expected the 2 pairs with tag 3, got %+v", pairs)
+		}
+		pair := pairs[0]
+		if pair.TagA != 1 || pair.TagB != 3 || pair.AWithB != 0.27 || pair.BWithA != 1 || pair.Jaccard != 0.27 ||
+			!strings.Contains(pair.Suggested, `tagged "Tax" are nearly always tagged "Finance"`) {
+			t.Errorf("Unexpected pair for a sample of %d: %+v", sampleSize, pair)
+		}
+	}
+
+	if _, err := s.handleGetTagCooccurrence(context.Background(), map[string]interface{}{"tag_id": float64(0)}); err == nil {
+		t.Error("Expected an invalid tag_id to fail")
+	}
+}
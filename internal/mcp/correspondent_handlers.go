@@ -4,8 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log/slog"
+	"net/url"
+	"strconv"
 
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
 	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
 )
 
@@ -31,34 +33,33 @@ func (s *Server) handleListCorrespondents(ctx context.Context, args map[string]i
 		}
 	}
 
-	slog.Debug("Listing correspondents", "page", page, "page_size", pageSize)
+	// Optional case-insensitive name filter and ordering
+	opts, err := parseListOptions(args, correspondentOrderings)
+	if err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Debug("Listing correspondents", "name", opts.Name, "ordering", opts.Ordering, "page", page, "page_size", pageSize)
 
 	// Call Paperless API
-	response, err := s.paperlessClient.ListCorrespondents(ctx, page, pageSize)
+	response, err := s.paperlessClient.ListCorrespondentsMatching(ctx, opts, page, pageSize)
 	if err != nil {
-		slog.Error("Failed to list correspondents", "error", err)
+		logging.FromContext(ctx).Error("Failed to list correspondents", "error", err)
 		return nil, fmt.Errorf("failed to list correspondents: %w", err)
 	}
 
 	// Parse correspondents from Results
 	var correspondents []paperless.Correspondent
 	if err := json.Unmarshal(response.Results, &correspondents); err != nil {
-		slog.Error("Failed to parse correspondents results", "error", err)
+		logging.FromContext(ctx).Error("Failed to parse correspondents results", "error", err)
 		return nil, fmt.Errorf("failed to parse results: %w", err)
 	}
 
-	slog.Info("Correspondents listed successfully",
+	logging.FromContext(ctx).Info("Correspondents listed successfully",
 		"count", response.Count,
 		"returned", len(correspondents))
 
-	return map[string]interface{}{
-		"count":          response.Count,
-		"page":           page,
-		"page_size":      pageSize,
-		"has_next":       response.Next != nil,
-		"has_prev":       response.Previous != nil,
-		"correspondents": correspondents,
-	}, nil
+	return newListResult(correspondents, response.Count, page, pageSize, listOptionFilters(opts)), nil
 }
 
 // handleGetCorrespondent handles the get_correspondent tool
@@ -73,18 +74,18 @@ func (s *Server) handleGetCorrespondent(ctx context.Context, args map[string]int
 		return nil, fmt.Errorf("correspondent_id must be a positive integer")
 	}
 
-	slog.Debug("Getting correspondent", "correspondent_id", correspondentID)
+	logging.FromContext(ctx).Debug("Getting correspondent", "correspondent_id", correspondentID)
 
 	// Call Paperless API
 	correspondent, err := s.paperlessClient.GetCorrespondent(ctx, correspondentID)
 	if err != nil {
-		slog.Error("Failed to get correspondent",
+		logging.FromContext(ctx).Error("Failed to get correspondent",
 			"correspondent_id", correspondentID,
 			"error", err)
 		return nil, fmt.Errorf("failed to get correspondent: %w", err)
 	}
 
-	slog.Info("Correspondent retrieved successfully",
+	logging.FromContext(ctx).Info("Correspondent retrieved successfully",
 		"correspondent_id", correspondentID,
 		"name", correspondent.Name)
 
@@ -99,7 +100,7 @@ func (s *Server) handleCreateCorrespondent(ctx context.Context, args map[string]
 		return nil, fmt.Errorf("name parameter is required and must be a non-empty string")
 	}
 
-	slog.Debug("Creating correspondent", "name", name)
+	logging.FromContext(ctx).Debug("Creating correspondent", "name", name)
 
 	// Build correspondent from args
 	correspondent := &paperless.Correspondent{
@@ -120,13 +121,18 @@ func (s *Server) handleCreateCorrespondent(ctx context.Context, args map[string]
 	// Call Paperless API
 	createdCorrespondent, err := s.paperlessClient.CreateCorrespondent(ctx, correspondent)
 	if err != nil {
-		slog.Error("Failed to create correspondent",
+		logging.FromContext(ctx).Error("Failed to create correspondent",
 			"name", name,
 			"error", err)
 		return nil, fmt.Errorf("failed to create correspondent: %w", err)
 	}
 
-	slog.Info("Correspondent created successfully",
+	s.metadata.correspondents.invalidate()
+
+	s.journal.recordCreate(ctx, "create_correspondent", "correspondents", createdCorrespondent.ID,
+		fmt.Sprintf("Created correspondent %q", createdCorrespondent.Name))
+
+	logging.FromContext(ctx).Info("Correspondent created successfully",
 		"correspondent_id", createdCorrespondent.ID,
 		"name", createdCorrespondent.Name)
 
@@ -157,24 +163,36 @@ func (s *Server) handleUpdateCorrespondent(ctx context.Context, args map[string]
 		return nil, fmt.Errorf("at least one field to update must be provided")
 	}
 
-	slog.Debug("Updating correspondent",
+	logging.FromContext(ctx).Debug("Updating correspondent",
 		"correspondent_id", correspondentID,
 		"fields", len(updates))
 
+	rename, err := s.checkRename(ctx, "correspondents", correspondentID, updates)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := s.snapshotFields(ctx, "correspondents", correspondentID, updates)
+
 	// Call Paperless API
 	updatedCorrespondent, err := s.paperlessClient.UpdateCorrespondent(ctx, correspondentID, updates)
 	if err != nil {
-		slog.Error("Failed to update correspondent",
+		logging.FromContext(ctx).Error("Failed to update correspondent",
 			"correspondent_id", correspondentID,
 			"error", err)
 		return nil, fmt.Errorf("failed to update correspondent: %w", err)
 	}
 
-	slog.Info("Correspondent updated successfully",
+	s.metadata.correspondents.invalidate()
+
+	s.journal.recordUpdate(ctx, "update_correspondent", "correspondents", correspondentID, previous,
+		fmt.Sprintf("Updated correspondent %d", correspondentID))
+
+	logging.FromContext(ctx).Info("Correspondent updated successfully",
 		"correspondent_id", correspondentID,
 		"name", updatedCorrespondent.Name)
 
-	return updatedCorrespondent, nil
+	return withRenameReport(ctx, updatedCorrespondent, updatedCorrespondent.Slug, rename)
 }
 
 // handleDeleteCorrespondent handles the delete_correspondent tool
@@ -189,18 +207,20 @@ func (s *Server) handleDeleteCorrespondent(ctx context.Context, args map[string]
 		return nil, fmt.Errorf("correspondent_id must be a positive integer")
 	}
 
-	slog.Debug("Deleting correspondent", "correspondent_id", correspondentID)
+	logging.FromContext(ctx).Debug("Deleting correspondent", "correspondent_id", correspondentID)
 
 	// Call Paperless API
 	err := s.paperlessClient.DeleteCorrespondent(ctx, correspondentID)
 	if err != nil {
-		slog.Error("Failed to delete correspondent",
+		logging.FromContext(ctx).Error("Failed to delete correspondent",
 			"correspondent_id", correspondentID,
 			"error", err)
 		return nil, fmt.Errorf("failed to delete correspondent: %w", err)
 	}
 
-	slog.Info("Correspondent deleted successfully", "correspondent_id", correspondentID)
+	s.metadata.correspondents.invalidate()
+
+	logging.FromContext(ctx).Info("Correspondent deleted successfully", "correspondent_id", correspondentID)
 
 	return map[string]interface{}{
 		"success":          true,
@@ -208,3 +228,110 @@ func (s *Server) handleDeleteCorrespondent(ctx context.Context, args map[string]
 		"message":          "Correspondent deleted successfully",
 	}, nil
 }
+
+// Limits for get_correspondent_summary
+const (
+	DefaultRecentDocuments = 5
+	MaxRecentDocuments     = 25
+)
+
+// summaryDocumentFields limits document listings to the fields a summary
+// needs, avoiding transferring every document's content
+const summaryDocumentFields = "id,title,created,created_date,document_type,tags"
+
+// handleGetCorrespondentSummary handles the get_correspondent_summary tool
+func (s *Server) handleGetCorrespondentSummary(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	// Extract and validate correspondent_id
+	correspondentIDFloat, ok := args["correspondent_id"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("correspondent_id parameter is required and must be an integer")
+	}
+	correspondentID := int(correspondentIDFloat)
+	if correspondentID < 1 {
+		return nil, fmt.Errorf("correspondent_id must be a positive integer")
+	}
+	recentCount := boundedIntArg(args, "recent_count", DefaultRecentDocuments, MaxRecentDocuments)
+
+	logging.FromContext(ctx).Debug("Summarising correspondent", "correspondent_id", correspondentID)
+
+	correspondent, err := s.paperlessClient.GetCorrespondent(ctx, correspondentID)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to get correspondent",
+			"correspondent_id", correspondentID,
+			"error", err)
+		return nil, fmt.Errorf("failed to get correspondent: %w", err)
+	}
+
+	filters := url.Values{}
+	filters.Set("correspondent__id", strconv.Itoa(correspondentID))
+	filters.Set("ordering", "-created")
+	filters.Set("fields", summaryDocumentFields)
+	documents, err := paperless.CollectAll[paperless.Document](ctx, func(ctx context.Context, page, pageSize int) (*paperless.PaginatedResponse, error) {
+		return s.paperlessClient.ListDocuments(ctx, filters, page, pageSize)
+	})
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to list correspondent documents",
+			"correspondent_id", correspondentID,
+			"error", err)
+		return nil, fmt.Errorf("failed to list documents: %w", err)
+	}
+
+	docTypes, err := s.metadata.documentTypes.get(ctx, s.paperlessClient)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to list document types", "error", err)
+		return nil, fmt.Errorf("failed to list document types: %w", err)
+	}
+	typeNames := make(map[int]string, len(docTypes))
+	for _, docType := range docTypes {
+		typeNames[docType.ID] = docType.Name
+	}
+
+	byYear := make(map[string]int)
+	byType := make(map[string]int)
+	for _, document := range documents {
+		year := "unknown"
+		if !document.Created.IsZero() {
+			year = strconv.Itoa(document.Created.Year())
+		} else if len(document.CreatedDate) >= 4 {
+			year = document.CreatedDate[:4]
+		}
+		byYear[year]++
+
+		typeName := "none"
+		if document.DocumentType != nil {
+			if name, ok := typeNames[*document.DocumentType]; ok {
+				typeName = name
+			} else {
+				typeName = strconv.Itoa(*document.DocumentType)
+			}
+		}
+		byType[typeName]++
+	}
+
+	// Documents are ordered newest first
+	recent := make([]previewDocument, 0, recentCount)
+	for _, document := range documents {
+		if len(recent) == recentCount {
+			break
+		}
+		recent = append(recent, newPreviewDocument(document))
+	}
+
+	logging.FromContext(ctx).Info("Correspondent summary completed",
+		"correspondent_id", correspondentID,
+		"documents", len(documents))
+
+	result := map[string]interface{}{
+		"correspondent_id":    correspondent.ID,
+		"name":                correspondent.Name,
+		"document_count":      len(documents),
+		"by_year":             byYear,
+		"by_document_type":    byType,
+		"recent_documents":    recent,
+		"last_correspondence": nil,
+	}
+	if !correspondent.LastCorrespondence.IsZero() {
+		result["last_correspondence"] = correspondent.LastCorrespondence
+	}
+	return result, nil
+}
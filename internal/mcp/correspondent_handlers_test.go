@@ -0,0 +1,72 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// TestGetCorrespondentSummary tests that a correspondent's documents are
+// counted by year and document type, newest first, with type names
+// resolved
+func TestGetCorrespondentSummary(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/correspondents/4/":
+			w.Write([]byte(`{"id":4,"name":"ACME","last_correspondence":"2024-05-02T10:00:00Z"}`))
+		case "/api/documents/":
+			query := r.URL.Query()
+			if query.Get("correspondent__id") != "4" || query.Get("ordering") != "-created" || query.Get("fields") != summaryDocumentFields {
+				t.Errorf("Unexpected document query %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"count":3,"next":null,"results":[
+				{"id":9,"title":"Invoice May","created":"2024-05-02","document_type":2},
+				{"id":7,"title":"Invoice January","created":"2024-01-15","document_type":2},
+				{"id":3,"title":"Contract","created":"2022-08-30","document_type":null}]}`))
+		case "/api/document_types/":
+			w.Write([]byte(`{"count":1,"next":null,"results":[{"id":2,"name":"Invoice"}]}`))
+		default:
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+	}))
+	defer ts.Close()
+
+	s := &Server{
+		paperlessClient: paperless.New(ts.URL, "test-token"),
+		metadata:        newMetadataCaches(time.Hour),
+	}
+
+	result, err := s.handleGetCorrespondentSummary(context.Background(), map[string]interface{}{
+		"correspondent_id": float64(4),
+		"recent_count":     float64(2),
+	})
+	if err != nil {
+		t.Fatalf("get_correspondent_summary failed: %v", err)
+	}
+	summary := result.(map[string]interface{})
+	if summary["correspondent_id"] != 4 || summary["name"] != "ACME" || summary["document_count"] != 3 {
+		t.Errorf("Unexpected summary %v", summary)
+	}
+	if got := summary["by_year"]; !reflect.DeepEqual(got, map[string]int{"2024": 2, "2022": 1}) {
+		t.Errorf("Unexpected counts by year %v", got)
+	}
+	if got := summary["by_document_type"]; !reflect.DeepEqual(got, map[string]int{"Invoice": 2, "none": 1}) {
+		t.Errorf("Unexpected counts by document type %v", got)
+	}
+	recent := summary["recent_documents"].([]previewDocument)
+	if len(recent) != 2 || recent[0].ID != 9 || recent[1].ID != 7 {
+		t.Errorf("Expected the 2 newest documents, got %+v", recent)
+	}
+	if summary["last_correspondence"] == nil {
+		t.Error("Expected the last correspondence date")
+	}
+
+	if _, err := s.handleGetCorrespondentSummary(context.Background(), map[string]interface{}{"correspondent_id": float64(0)}); err == nil {
+		t.Error("Expected an invalid correspondent_id to fail")
+	}
+}
@@ -4,8 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log/slog"
 
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
 	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
 )
 
@@ -31,36 +31,29 @@ func (s *Server) handleListCustomFields(ctx context.Context, args map[string]int
 		}
 	}
 
-	slog.Debug("Listing custom fields",
+	logging.FromContext(ctx).Debug("Listing custom fields",
 		"page", page,
 		"page_size", pageSize)
 
 	// Call Paperless API
 	response, err := s.paperlessClient.ListCustomFields(ctx, page, pageSize)
 	if err != nil {
-		slog.Error("Failed to list custom fields", "error", err)
+		logging.FromContext(ctx).Error("Failed to list custom fields", "error", err)
 		return nil, fmt.Errorf("failed to list custom fields: %w", err)
 	}
 
 	// Parse custom fields from Results
 	var fields []paperless.CustomField
 	if err := json.Unmarshal(response.Results, &fields); err != nil {
-		slog.Error("Failed to parse custom fields list", "error", err)
+		logging.FromContext(ctx).Error("Failed to parse custom fields list", "error", err)
 		return nil, fmt.Errorf("failed to parse custom fields: %w", err)
 	}
 
-	slog.Info("Custom fields listed successfully",
+	logging.FromContext(ctx).Info("Custom fields listed successfully",
 		"count", response.Count,
 		"returned", len(fields))
 
-	return map[string]interface{}{
-		"count":      response.Count,
-		"page":       page,
-		"page_size":  pageSize,
-		"has_next":   response.Next != nil,
-		"has_prev":   response.Previous != nil,
-		"fields":     fields,
-	}, nil
+	return newListResult(fields, response.Count, page, pageSize, nil), nil
 }
 
 // handleGetCustomField handles the get_custom_field tool
@@ -75,18 +68,18 @@ func (s *Server) handleGetCustomField(ctx context.Context, args map[string]inter
 		return nil, fmt.Errorf("field_id must be a positive integer")
 	}
 
-	slog.Debug("Getting custom field", "field_id", fieldID)
+	logging.FromContext(ctx).Debug("Getting custom field", "field_id", fieldID)
 
 	// Call Paperless API
 	field, err := s.paperlessClient.GetCustomField(ctx, fieldID)
 	if err != nil {
-		slog.Error("Failed to get custom field",
+		logging.FromContext(ctx).Error("Failed to get custom field",
 			"field_id", fieldID,
 			"error", err)
 		return nil, fmt.Errorf("failed to get custom field: %w", err)
 	}
 
-	slog.Info("Custom field retrieved successfully",
+	logging.FromContext(ctx).Info("Custom field retrieved successfully",
 		"field_id", fieldID,
 		"name", field.Name)
 
@@ -106,7 +99,7 @@ func (s *Server) handleCreateCustomField(ctx context.Context, args map[string]in
 		return nil, fmt.Errorf("data_type parameter is required and must be a non-empty string")
 	}
 
-	slog.Debug("Creating custom field",
+	logging.FromContext(ctx).Debug("Creating custom field",
 		"name", name,
 		"data_type", dataType)
 
@@ -119,13 +112,18 @@ func (s *Server) handleCreateCustomField(ctx context.Context, args map[string]in
 	// Call Paperless API
 	createdField, err := s.paperlessClient.CreateCustomField(ctx, field)
 	if err != nil {
-		slog.Error("Failed to create custom field",
+		logging.FromContext(ctx).Error("Failed to create custom field",
 			"name", name,
 			"error", err)
 		return nil, fmt.Errorf("failed to create custom field: %w", err)
 	}
 
-	slog.Info("Custom field created successfully",
+	s.metadata.customFields.invalidate()
+
+	s.journal.recordCreate(ctx, "create_custom_field", "custom_fields", createdField.ID,
+		fmt.Sprintf("Created custom field %q", createdField.Name))
+
+	logging.FromContext(ctx).Info("Custom field created successfully",
 		"field_id", createdField.ID,
 		"name", createdField.Name)
 
@@ -159,20 +157,27 @@ func (s *Server) handleUpdateCustomField(ctx context.Context, args map[string]in
 		return nil, fmt.Errorf("at least one field to update must be provided")
 	}
 
-	slog.Debug("Updating custom field",
+	logging.FromContext(ctx).Debug("Updating custom field",
 		"field_id", fieldID,
 		"fields", len(updates))
 
+	previous := s.snapshotFields(ctx, "custom_fields", fieldID, updates)
+
 	// Call Paperless API
 	field, err := s.paperlessClient.UpdateCustomField(ctx, fieldID, updates)
 	if err != nil {
-		slog.Error("Failed to update custom field",
+		logging.FromContext(ctx).Error("Failed to update custom field",
 			"field_id", fieldID,
 			"error", err)
 		return nil, fmt.Errorf("failed to update custom field: %w", err)
 	}
 
-	slog.Info("Custom field updated successfully",
+	s.metadata.customFields.invalidate()
+
+	s.journal.recordUpdate(ctx, "update_custom_field", "custom_fields", fieldID, previous,
+		fmt.Sprintf("Updated custom field %d", fieldID))
+
+	logging.FromContext(ctx).Info("Custom field updated successfully",
 		"field_id", fieldID,
 		"name", field.Name)
 
@@ -191,18 +196,20 @@ func (s *Server) handleDeleteCustomField(ctx context.Context, args map[string]in
 		return nil, fmt.Errorf("field_id must be a positive integer")
 	}
 
-	slog.Debug("Deleting custom field", "field_id", fieldID)
+	logging.FromContext(ctx).Debug("Deleting custom field", "field_id", fieldID)
 
 	// Call Paperless API
 	err := s.paperlessClient.DeleteCustomField(ctx, fieldID)
 	if err != nil {
-		slog.Error("Failed to delete custom field",
+		logging.FromContext(ctx).Error("Failed to delete custom field",
 			"field_id", fieldID,
 			"error", err)
 		return nil, fmt.Errorf("failed to delete custom field: %w", err)
 	}
 
-	slog.Info("Custom field deleted successfully", "field_id", fieldID)
+	s.metadata.customFields.invalidate()
+
+	logging.FromContext(ctx).Info("Custom field deleted successfully", "field_id", fieldID)
 
 	return map[string]interface{}{
 		"success": true,
@@ -0,0 +1,250 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// MaxCustomFieldStringLength is the longest value Paperless accepts for a
+// string custom field
+const MaxCustomFieldStringLength = 128
+
+// monetaryPattern matches a monetary value, an amount with an optional ISO
+// 4217 currency prefix such as EUR12.50
+var monetaryPattern = regexp.MustCompile(`^([A-Z]{3})?-?\d+(\.\d{1,2})?$`)
+
+// resolveCustomField finds a custom field by ID or by case-insensitive name
+func resolveCustomField(fields []paperless.CustomField, ref interface{}) (*paperless.CustomField, error) {
+	switch ref := ref.(type) {
+	case float64:
+		for i := range fields {
+			if float64(fields[i].ID) == ref {
+				return &fields[i], nil
+			}
+		}
+		return nil, fmt.Errorf("custom field %v does not exist", ref)
+	case string:
+		for i := range fields {
+			if strings.EqualFold(fields[i].Name, ref) {
+				return &fields[i], nil
+			}
+		}
+		message := fmt.Sprintf("custom field '%s' does not exist", ref)
+		names := make([]string, len(fields))
+		for i, field := range fields {
+			names[i] = field.Name
+		}
+		if suggestion := closestMatch(ref, names); suggestion != "" {
+			message += fmt.Sprintf(", did you mean '%s'?", suggestion)
+		}
+		return nil, fmt.Errorf("%s", message)
+	default:
+		return nil, fmt.Errorf("custom field must be given by name or ID, got %v", ref)
+	}
+}
+
+// normalizeCustomFieldValue checks that value suits the field's data_type
+// and returns it in the form Paperless expects, or an error naming the
+// field and the expected type. nil clears the value and is always valid.
+func normalizeCustomFieldValue(field *paperless.CustomField, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	expected := func(what string) error {
+		return fmt.Errorf("field '%s' expects %s, got %s", field.Name, what, describeValue(value))
+	}
+
+	switch field.DataType {
+	case paperless.CustomFieldString:
+		s, ok := value.(string)
+		if !ok {
+			return nil, expected("text")
+		}
+		if len(s) > MaxCustomFieldStringLength {
+			return nil, fmt.Errorf("field '%s' accepts at most %d characters, got %d", field.Name, MaxCustomFieldStringLength, len(s))
+		}
+		return s, nil
+	case paperless.CustomFieldURL:
+		s, ok := value.(string)
+		if !ok {
+			return nil, expected("a URL")
+		}
+		if u, err := url.Parse(s); err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, expected("a URL such as https://example.com")
+		}
+		return s, nil
+	case paperless.CustomFieldDate:
+		s, ok := value.(string)
+		if !ok {
+			return nil, expected("a date")
+		}
+		date, err := parseDocumentDate(s, "")
+		if err != nil {
+			return nil, expected("a date")
+		}
+		return date.Format(paperless.DateOnlyFormat), nil
+	case paperless.CustomFieldBoolean:
+		b, ok := value.(bool)
+		if !ok {
+			return nil, expected("true or false")
+		}
+		return b, nil
+	case paperless.CustomFieldInteger:
+		n, ok := value.(float64)
+		if !ok || n != math.Trunc(n) {
+			return nil, expected("a whole number")
+		}
+		return int(n), nil
+	case paperless.CustomFieldFloat:
+		n, ok := value.(float64)
+		if !ok {
+			return nil, expected("a number")
+		}
+		return n, nil
+	case paperless.CustomFieldMonetary:
+		switch v := value.(type) {
+		case float64:
+			return strconv.FormatFloat(v, 'f', 2, 64), nil
+		case string:
+			if monetaryPattern.MatchString(v) {
+				return v, nil
+			}
+		}
+		return nil, expected("an amount with an optional currency prefix, such as EUR12.50")
+	case paperless.CustomFieldDocumentLink:
+		ids, ok := value.([]interface{})
+		if !ok {
+			return nil, expected("a list of document IDs")
+		}
+		links := make([]int, len(ids))
+		for i, id := range ids {
+			n, ok := id.(float64)
+			if !ok || n < 1 || n != math.Trunc(n) {
+				return nil, expected("a list of document IDs")
+			}
+			links[i] = int(n)
+		}
+		return links, nil
+	case paperless.CustomFieldSelect:
+		return selectOptionValue(field, value)
+	}
+	// Data types added to Paperless after this server are passed through
+	return value, nil
+}
+
+// selectOptionValue resolves a select field value given as an option's
+// label or ID. Paperless 2.x identifies options by an id; older versions
+// store plain labels and use the option's index as the value.
+func selectOptionValue(field *paperless.CustomField, value interface{}) (interface{}, error) {
+	options, _ := field.ExtraData["select_options"].([]interface{})
+	labels := make([]string, 0, len(options))
+	for i, option := range options {
+		switch option := option.(type) {
+		case string:
+			labels = append(labels, option)
+			if s, ok := value.(string); ok && strings.EqualFold(s, option) {
+				return i, nil
+			}
+			if n, ok := value.(float64); ok && int(n) == i {
+				return i, nil
+			}
+		case map[string]interface{}:
+			label, _ := option["label"].(string)
+			labels = append(labels, label)
+			if value == option["id"] {
+				return option["id"], nil
+			}
+			if s, ok := value.(string); ok && strings.EqualFold(s, label) {
+				return option["id"], nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("field '%s' expects one of %s, got %s", field.Name, strings.Join(labels, ", "), describeValue(value))
+}
+
+// describeValue formats a value for error messages
+func describeValue(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return fmt.Sprintf("'%s'", s)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// customFieldValues resolves a custom_fields argument, a list of {field,
+// value} with fields given by name or ID, to values by field ID checked
+// against each field's data type, along with the field IDs in the order
+// first listed
+func (s *Server) customFieldValues(ctx context.Context, raw interface{}) (map[int]interface{}, []int, error) {
+	entries, ok := raw.([]interface{})
+	if !ok {
+		return nil, nil, fmt.Errorf("custom_fields must be an array of {field, value} objects")
+	}
+
+	fields, err := s.metadata.customFields.get(ctx, s.paperlessClient)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to list custom fields", "error", err)
+		return nil, nil, fmt.Errorf("failed to list custom fields: %w", err)
+	}
+
+	set := make(map[int]interface{}, len(entries))
+	var order []int
+	for _, entry := range entries {
+		object, ok := entry.(map[string]interface{})
+		if !ok {
+			return nil, nil, fmt.Errorf("custom_fields must be an array of {field, value} objects")
+		}
+		field, err := resolveCustomField(fields, object["field"])
+		if err != nil {
+			return nil, nil, err
+		}
+		value, err := normalizeCustomFieldValue(field, object["value"])
+		if err != nil {
+			return nil, nil, err
+		}
+		if _, seen := set[field.ID]; !seen {
+			order = append(order, field.ID)
+		}
+		set[field.ID] = value
+	}
+	return set, order, nil
+}
+
+// customFieldUpdates turns the custom_fields argument of update_document, a
+// list of {field, value} with fields given by name or ID, into the
+// document's new custom field values. Values for fields not listed are
+// kept, since Paperless replaces the whole list.
+func (s *Server) customFieldUpdates(ctx context.Context, documentID int, raw interface{}) ([]map[string]interface{}, error) {
+	set, order, err := s.customFieldValues(ctx, raw)
+	if err != nil {
+		return nil, err
+	}
+
+	document, err := s.paperlessClient.GetDocument(ctx, documentID)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to get document", "document_id", documentID, "error", err)
+		return nil, fmt.Errorf("failed to get document: %w", err)
+	}
+	values := make([]map[string]interface{}, 0, len(document.CustomFields)+len(order))
+	for _, current := range document.CustomFields {
+		value, replaced := set[current.Field]
+		if !replaced {
+			value = current.Value
+		}
+		delete(set, current.Field)
+		values = append(values, map[string]interface{}{"field": current.Field, "value": value})
+	}
+	for _, id := range order {
+		if value, ok := set[id]; ok {
+			values = append(values, map[string]interface{}{"field": id, "value": value})
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,102 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// TestNormalizeCustomFieldValue tests values accepted and rejected for each
+// data type
+func TestNormalizeCustomFieldValue(t *testing.T) {
+	tests := []struct {
+		dataType string
+		value    interface{}
+		want     interface{} // nil with wantErr
+		wantErr  string
+	}{
+		{paperless.CustomFieldDate, "15.03.2024", "2024-03-15", ""},
+		{paperless.CustomFieldDate, "soon", nil, "field 'Due Date' expects a date, got 'soon'"},
+		{paperless.CustomFieldInteger, float64(3), 3, ""},
+		{paperless.CustomFieldInteger, 3.5, nil, "expects a whole number"},
+		{paperless.CustomFieldBoolean, "yes", nil, "expects true or false"},
+		{paperless.CustomFieldMonetary, 12.5, "12.50", ""},
+		{paperless.CustomFieldMonetary, "EUR12.50", "EUR12.50", ""},
+		{paperless.CustomFieldMonetary, "12 euros", nil, "expects an amount"},
+		{paperless.CustomFieldURL, "example.com", nil, "expects a URL"},
+		{paperless.CustomFieldString, strings.Repeat("x", 129), nil, "at most 128 characters"},
+	}
+	for _, tt := range tests {
+		field := &paperless.CustomField{Name: "Due Date", DataType: tt.dataType}
+		got, err := normalizeCustomFieldValue(field, tt.value)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s %v: expected error containing %q, got %v", tt.dataType, tt.value, tt.wantErr, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%s %v: expected %v, got %v (%v)", tt.dataType, tt.value, tt.want, got, err)
+		}
+	}
+
+	selectField := &paperless.CustomField{Name: "Status", DataType: paperless.CustomFieldSelect, ExtraData: map[string]interface{}{
+		"select_options": []interface{}{
+			map[string]interface{}{"id": "a1", "label": "Open"},
+			map[string]interface{}{"id": "b2", "label": "Paid"},
+		},
+	}}
+	if got, err := normalizeCustomFieldValue(selectField, "paid"); err != nil || got != "b2" {
+		t.Errorf("Expected the option label to resolve to its id, got %v (%v)", got, err)
+	}
+	if _, err := normalizeCustomFieldValue(selectField, "Overdue"); err == nil || !strings.Contains(err.Error(), "Open, Paid") {
+		t.Errorf("Expected the options to be listed, got %v", err)
+	}
+}
+
+// TestCustomFieldUpdates tests resolving fields by name, keeping values of
+// fields not listed and caching the registry
+func TestCustomFieldUpdates(t *testing.T) {
+	registryReads := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/custom_fields/":
+			registryReads++
+			w.Write([]byte(`{"count":2,"next":null,"results":[{"id":1,"name":"Due Date","data_type":"date"},{"id":2,"name":"Amount","data_type":"monetary"}]}`))
+		case "/api/documents/5/":
+			w.Write([]byte(`{"id":5,"title":"Invoice","custom_fields":[{"field":2,"value":"EUR10.00"}]}`))
+		}
+	}))
+	defer ts.Close()
+
+	s := &Server{
+		paperlessClient: paperless.New(ts.URL, "test-token"),
+		metadata:        newMetadataCaches(time.Minute),
+	}
+	values, err := s.customFieldUpdates(context.Background(), 5, []interface{}{
+		map[string]interface{}{"field": "due date", "value": "2024-04-01"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	data, _ := json.Marshal(values)
+	if string(data) != `[{"field":2,"value":"EUR10.00"},{"field":1,"value":"2024-04-01"}]` {
+		t.Errorf("Expected the existing value kept and the date added, got %s", data)
+	}
+
+	_, err = s.customFieldUpdates(context.Background(), 5, []interface{}{
+		map[string]interface{}{"field": "Due Dat", "value": "soon"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "did you mean 'Due Date'") {
+		t.Errorf("Expected an unknown field with a suggestion, got %v", err)
+	}
+	if registryReads != 1 {
+		t.Errorf("Expected the registry to be read once, got %d", registryReads)
+	}
+}
@@ -0,0 +1,178 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// Date orders for numeric dates such as 03/04/2024
+const (
+	DateOrderDMY = "DMY"
+	DateOrderMDY = "MDY"
+)
+
+// dateLayouts are the unambiguous layouts accepted for document dates, tried
+// in order
+var dateLayouts = []string{
+	paperless.DateOnlyFormat,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006/01/02",
+	"2006.01.02",
+	"20060102",
+	"January 2, 2006",
+	"January 2 2006",
+	"Jan 2, 2006",
+	"Jan 2 2006",
+	"2 January 2006",
+	"2 Jan 2006",
+	"2-Jan-2006",
+	"Monday, January 2, 2006",
+	"Mon, 2 Jan 2006",
+}
+
+var (
+	// numericDatePattern matches day and month in either order before a
+	// four digit year
+	numericDatePattern = regexp.MustCompile(`^(\d{1,2})([./-])(\d{1,2})[./-](\d{4})$`)
+
+	// ordinalPattern matches ordinal suffixes such as the "rd" in 3rd
+	ordinalPattern = regexp.MustCompile(`(\d)(st|nd|rd|th)\b`)
+)
+
+// parseDocumentDate parses a date written the way people and documents
+// write them. Numeric dates where both day and month could be either need
+// order, DMY or MDY; dotted dates default to DMY as in most locales that
+// use them.
+func parseDocumentDate(value, order string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, fmt.Errorf("date is empty")
+	}
+
+	if m := numericDatePattern.FindStringSubmatch(value); m != nil {
+		return parseNumericDate(m, order)
+	}
+
+	normalized := strings.Join(strings.Fields(ordinalPattern.ReplaceAllString(value, "$1")), " ")
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, normalized); err == nil {
+			if t.Year() < 1000 {
+				return time.Time{}, fmt.Errorf("year %d is out of range", t.Year())
+			}
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognised date %q, use YYYY-MM-DD", value)
+}
+
+// parseNumericDate resolves the day and month of a numeric date match
+func parseNumericDate(m []string, order string) (time.Time, error) {
+	first, _ := strconv.Atoi(m[1])
+	second, _ := strconv.Atoi(m[3])
+	year, _ := strconv.Atoi(m[4])
+
+	if order == "" {
+		switch {
+		case first > 12 || m[2] == ".":
+			order = DateOrderDMY
+		case second > 12:
+			order = DateOrderMDY
+		case first == second:
+			order = DateOrderDMY
+		default:
+			return time.Time{}, fmt.Errorf("date %q is ambiguous, set date_order to %s or %s", m[0], DateOrderDMY, DateOrderMDY)
+		}
+	}
+
+	day, month := first, second
+	if order == DateOrderMDY {
+		day, month = second, first
+	}
+	t := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if t.Day() != day || int(t.Month()) != month {
+		return time.Time{}, fmt.Errorf("date %q does not exist when read as %s", m[0], order)
+	}
+	return t, nil
+}
+
+// handleSetDocumentDates handles the set_document_dates tool
+func (s *Server) handleSetDocumentDates(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	documentIDFloat, ok := args["document_id"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("document_id parameter is required and must be an integer")
+	}
+	documentID := int(documentIDFloat)
+	if documentID < 1 {
+		return nil, fmt.Errorf("document_id must be a positive integer")
+	}
+
+	createdStr, ok := args["created"].(string)
+	if !ok || createdStr == "" {
+		return nil, fmt.Errorf("created parameter is required and must be a date string")
+	}
+
+	order, _ := args["date_order"].(string)
+	order = strings.ToUpper(order)
+	if order != "" && order != DateOrderDMY && order != DateOrderMDY {
+		return nil, fmt.Errorf("date_order must be %s or %s", DateOrderDMY, DateOrderMDY)
+	}
+
+	created, err := parseDocumentDate(createdStr, order)
+	if err != nil {
+		return nil, fmt.Errorf("invalid created date: %w", err)
+	}
+	createdDate := created.Format(paperless.DateOnlyFormat)
+
+	expectedModified, checkModified, err := parseExpectedModified(args)
+	if err != nil {
+		return nil, err
+	}
+	if checkModified {
+		if err := s.checkExpectedModified(ctx, documentID, expectedModified); err != nil {
+			return nil, err
+		}
+	}
+
+	logging.FromContext(ctx).Debug("Setting document dates",
+		"document_id", documentID,
+		"created", createdDate)
+
+	updates := map[string]interface{}{"created": createdDate}
+	previous := s.snapshotFields(ctx, "documents", documentID, updates)
+
+	document, err := s.paperlessClient.UpdateDocument(ctx, documentID, updates)
+	s.documents.invalidate(documentID)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to set document dates",
+			"document_id", documentID,
+			"error", err)
+		return nil, fmt.Errorf("failed to set document dates: %w", err)
+	}
+
+	s.journal.recordUpdate(ctx, "set_document_dates", "documents", documentID, previous,
+		fmt.Sprintf("Set created date of document %d to %s", documentID, createdDate))
+
+	logging.FromContext(ctx).Info("Document dates set",
+		"document_id", documentID,
+		"created", createdDate)
+
+	result := map[string]interface{}{
+		"document_id": documentID,
+		"title":       document.Title,
+		"created":     createdDate,
+		"parsed_from": createdStr,
+	}
+	if value, ok := previous["created"]; ok {
+		result["previous_created"] = value
+	}
+	return result, nil
+}
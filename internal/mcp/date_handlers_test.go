@@ -0,0 +1,42 @@
+package mcp
+
+import (
+	"testing"
+)
+
+// TestParseDocumentDate tests the accepted formats and how numeric dates
+// are disambiguated
+func TestParseDocumentDate(t *testing.T) {
+	tests := []struct {
+		value string
+		order string
+		want  string
+	}{
+		{"2024-03-15", "", "2024-03-15"},
+		{"2024-03-15T10:30:00+01:00", "", "2024-03-15"},
+		{"2024/03/15", "", "2024-03-15"},
+		{"15.03.2024", "", "2024-03-15"},
+		{"15/03/2024", "", "2024-03-15"},
+		{"03/15/2024", "", "2024-03-15"},
+		{"03/04/2024", "MDY", "2024-03-04"},
+		{"03/04/2024", "DMY", "2024-04-03"},
+		{"March 15th, 2024", "", "2024-03-15"},
+		{"15  mar 2024", "", "2024-03-15"},
+	}
+	for _, tt := range tests {
+		got, err := parseDocumentDate(tt.value, tt.order)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.value, err)
+			continue
+		}
+		if got.Format("2006-01-02") != tt.want {
+			t.Errorf("%q: expected %s, got %s", tt.value, tt.want, got.Format("2006-01-02"))
+		}
+	}
+
+	for _, value := range []string{"", "03/04/2024", "31/02/2024", "next tuesday", "0099-01-01"} {
+		if _, err := parseDocumentDate(value, ""); err == nil {
+			t.Errorf("Expected %q to be rejected", value)
+		}
+	}
+}
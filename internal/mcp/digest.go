@@ -0,0 +1,177 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/config"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// Digest constants
+const (
+	// DigestSampleSize is how many matching documents a digest lists
+	DigestSampleSize = 5
+
+	// DigestWebhookTimeout bounds a single webhook delivery
+	DigestWebhookTimeout = 10 * time.Second
+
+	// DigestLogger names the MCP logger digest notifications are sent as
+	DigestLogger = "digest"
+)
+
+// digestIdentity is the saved query scope digests run in: queries saved
+// while using the server's own Paperless token
+const digestIdentity = "default"
+
+// digestResult is the payload of a digest notification or webhook
+type digestResult struct {
+	Query       string            `json:"query"`
+	Description string            `json:"description,omitempty"`
+	Schedule    string            `json:"schedule"`
+	Message     string            `json:"message"`
+	Count       int               `json:"count"`
+	Documents   []previewDocument `json:"documents"`
+	RanAt       time.Time         `json:"ran_at"`
+}
+
+// StartDigests runs each configured digest on its schedule until ctx is
+// cancelled. Digests whose query matches nothing are not sent.
+func (s *Server) StartDigests(ctx context.Context) {
+	for _, digest := range s.cfg.MCPDigests {
+		if _, err := s.savedQueries.get(digestIdentity, digest.Query); err != nil {
+			logging.FromContext(ctx).Warn("Digest refers to a query that is not saved yet",
+				"query", digest.Query,
+				"schedule", digest.Schedule.String())
+		}
+		go s.runDigest(ctx, digest)
+	}
+	if len(s.cfg.MCPDigests) > 0 {
+		logging.FromContext(ctx).Info("Digests scheduled",
+			"count", len(s.cfg.MCPDigests),
+			"webhook", s.cfg.MCPDigestWebhookURL != "")
+	}
+}
+
+// runDigest waits for each scheduled time of digest and sends it
+func (s *Server) runDigest(ctx context.Context, digest config.Digest) {
+	for {
+		next := digest.Schedule.Next(time.Now())
+		if next.IsZero() {
+			logging.FromContext(ctx).Warn("Digest schedule never fires", "query", digest.Query, "schedule", digest.Schedule.String())
+			return
+		}
+		logging.FromContext(ctx).Debug("Next digest scheduled", "query", digest.Query, "at", next)
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		result, err := s.buildDigest(ctx, digest)
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to run digest", "query", digest.Query, "error", err)
+			continue
+		}
+		if result.Count == 0 {
+			logging.FromContext(ctx).Debug("Digest matched no documents", "query", digest.Query)
+			continue
+		}
+		s.sendDigest(ctx, result)
+	}
+}
+
+// buildDigest runs the digest's saved query
+func (s *Server) buildDigest(ctx context.Context, digest config.Digest) (*digestResult, error) {
+	query, err := s.savedQueries.get(digestIdentity, digest.Query)
+	if err != nil {
+		return nil, err
+	}
+	filters, err := filterValues(query.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("saved query %q has an invalid filter: %w", query.Name, err)
+	}
+
+	response, err := s.paperlessClient.ListDocuments(ctx, filters, 1, DigestSampleSize)
+	if err != nil {
+		return nil, err
+	}
+	var documents []paperless.Document
+	if err := json.Unmarshal(response.Results, &documents); err != nil {
+		return nil, fmt.Errorf("failed to parse results: %w", err)
+	}
+
+	sample := make([]previewDocument, 0, len(documents))
+	for _, document := range documents {
+		sample = append(sample, newPreviewDocument(document))
+	}
+
+	noun := "documents match"
+	if response.Count == 1 {
+		noun = "document matches"
+	}
+
+	return &digestResult{
+		Query:       query.Name,
+		Description: query.Description,
+		Schedule:    digest.Schedule.String(),
+		Message:     fmt.Sprintf("%d %s %q", response.Count, noun, query.Name),
+		Count:       response.Count,
+		Documents:   sample,
+		RanAt:       time.Now().UTC(),
+	}, nil
+}
+
+// sendDigest logs the digest, notifies connected MCP clients and posts it
+// to the webhook when one is configured
+func (s *Server) sendDigest(ctx context.Context, result *digestResult) {
+	logging.FromContext(ctx).Info("Digest", "query", result.Query, "count", result.Count, "message", result.Message)
+
+	s.mcpServer.SendNotificationToAllClients("notifications/message", map[string]any{
+		"level":  "info",
+		"logger": DigestLogger,
+		"data":   result,
+	})
+
+	if s.cfg.MCPDigestWebhookURL == "" {
+		return
+	}
+	if err := postDigest(ctx, s.cfg.MCPDigestWebhookURL, result); err != nil {
+		logging.FromContext(ctx).Error("Failed to deliver digest webhook", "query", result.Query, "error", err)
+	}
+}
+
+// postDigest posts result as JSON to url
+func postDigest(ctx context.Context, url string, result *digestResult) error {
+	body, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to encode digest: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, DigestWebhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+package mcp
+
+import (
+	"container/list"
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// defaultSessionKey scopes the cache when a call has no MCP session, such
+// as tools run from the command line
+const defaultSessionKey = "default"
+
+// documentCache keeps the most recently fetched documents of each MCP
+// session in memory, so an agent re-reading the same documents within a
+// conversation does not go back to Paperless every time
+type documentCache struct {
+	mu       sync.Mutex
+	size     int
+	ttl      time.Duration
+	sessions map[cacheScope]*sessionDocuments
+	stats    CacheStats
+}
+
+// cacheScope is what cached documents are kept apart by: the session and
+// the Paperless identity of the caller. Any session ID is accepted, so a
+// caller presenting another session's ID must still not be served the
+// documents another Paperless token read.
+type cacheScope struct {
+	identity string
+	session  string
+}
+
+// CacheStats holds the counters of a cache
+type CacheStats struct {
+	Hits          uint64 `json:"hits"`
+	Misses        uint64 `json:"misses"`
+	Evictions     uint64 `json:"evictions"`
+	Expirations   uint64 `json:"expirations"`
+	Invalidations uint64 `json:"invalidations"`
+	Entries       int    `json:"entries"`
+	Sessions      int    `json:"sessions"`
+	Capacity      int    `json:"capacity_per_session"`
+	TTLSeconds    int    `json:"ttl_seconds"`
+}
+
+// HitRatio returns the fraction of lookups served from the cache
+func (st CacheStats) HitRatio() float64 {
+	if lookups := st.Hits + st.Misses; lookups > 0 {
+		return float64(st.Hits) / float64(lookups)
+	}
+	return 0
+}
+
+// sessionDocuments is the least-recently-used list of one session
+type sessionDocuments struct {
+	order *list.List // of *cachedDocument, most recent at the front
+	items map[int]*list.Element
+}
+
+type cachedDocument struct {
+	document *paperless.Document
+	fetched  time.Time
+}
+
+// newDocumentCache creates a cache holding up to size documents per
+// session for at most ttl. A size below 1 disables caching.
+func newDocumentCache(size int, ttl time.Duration) *documentCache {
+	return &documentCache{
+		size:     size,
+		ttl:      ttl,
+		sessions: make(map[cacheScope]*sessionDocuments),
+	}
+}
+
+// sessionKey identifies the MCP session of a tool call
+func sessionKey(ctx context.Context) string {
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		return session.SessionID()
+	}
+	return defaultSessionKey
+}
+
+// documentScope returns the cache scope of a tool call
+func documentScope(ctx context.Context) cacheScope {
+	return cacheScope{identity: identityKey(ctx), session: sessionKey(ctx)}
+}
+
+// get returns a cached document, or nil if it is missing or expired
+func (c *documentCache) get(scope cacheScope, documentID int) *paperless.Document {
+	if c.size < 1 {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	docs, ok := c.sessions[scope]
+	if !ok {
+		c.stats.Misses++
+		return nil
+	}
+	element, ok := docs.items[documentID]
+	if !ok {
+		c.stats.Misses++
+		return nil
+	}
+	entry := element.Value.(*cachedDocument)
+	if c.ttl > 0 && time.Since(entry.fetched) > c.ttl {
+		docs.order.Remove(element)
+		delete(docs.items, documentID)
+		c.stats.Expirations++
+		c.stats.Misses++
+		return nil
+	}
+	docs.order.MoveToFront(element)
+	c.stats.Hits++
+	return entry.document
+}
+
+// put stores a document for a scope, evicting the least recently used
+// document when the scope is full
+func (c *documentCache) put(scope cacheScope, document *paperless.Document) {
+	if c.size < 1 || document == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	docs, ok := c.sessions[scope]
+	if !ok {
+		docs = &sessionDocuments{order: list.New(), items: make(map[int]*list.Element)}
+		c.sessions[scope] = docs
+	}
+
+	entry := &cachedDocument{document: document, fetched: time.Now()}
+	if element, ok := docs.items[document.ID]; ok {
+		element.Value = entry
+		docs.order.MoveToFront(element)
+		return
+	}
+	docs.items[document.ID] = docs.order.PushFront(entry)
+
+	for docs.order.Len() > c.size {
+		oldest := docs.order.Back()
+		docs.order.Remove(oldest)
+		delete(docs.items, oldest.Value.(*cachedDocument).document.ID)
+		c.stats.Evictions++
+	}
+}
+
+// invalidate drops documents from every session, after they were changed
+// through this server
+func (c *documentCache) invalidate(documentIDs ...int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, docs := range c.sessions {
+		for _, id := range documentIDs {
+			if element, ok := docs.items[id]; ok {
+				docs.order.Remove(element)
+				delete(docs.items, id)
+				c.stats.Invalidations++
+			}
+		}
+	}
+}
+
+// dropSession forgets all documents cached for a session that has ended,
+// whichever identities used it
+func (c *documentCache) dropSession(session string) {
+	c.mu.Lock()
+	for scope := range c.sessions {
+		if scope.session == session {
+			delete(c.sessions, scope)
+		}
+	}
+	c.mu.Unlock()
+
+	stats := c.snapshot()
+	slog.Debug("Document cache session ended",
+		"session_id", session,
+		"hits", stats.Hits,
+		"misses", stats.Misses,
+		"evictions", stats.Evictions)
+}
+
+// snapshot returns the current counters and occupancy
+func (c *documentCache) snapshot() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Sessions = len(c.sessions)
+	for _, docs := range c.sessions {
+		stats.Entries += docs.order.Len()
+	}
+	stats.Capacity = c.size
+	stats.TTLSeconds = int(c.ttl.Seconds())
+	return stats
+}
+
+// clear drops every cached document, optionally only those of one
+// scope, and returns how many were removed. Counters are kept.
+func (c *documentCache) clear(scope *cacheScope) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	for key, docs := range c.sessions {
+		if scope != nil && key != *scope {
+			continue
+		}
+		removed += docs.order.Len()
+		delete(c.sessions, key)
+	}
+	return removed
+}
+
+// getDocument returns a document from the session cache, fetching it from
+// Paperless on a miss. Fetched documents are enriched with their file sizes
+// before being cached, so the extra metadata request is made only once.
+func (s *Server) getDocument(ctx context.Context, documentID int) (*paperless.Document, error) {
+	scope := documentScope(ctx)
+	if document := s.documents.get(scope, documentID); document != nil {
+		return document, nil
+	}
+
+	document, err := s.paperlessClient.GetDocument(ctx, documentID)
+	if err != nil {
+		return nil, err
+	}
+	s.enrichDocument(ctx, document, s.mailRules(ctx))
+	s.resolveDocumentLinks(ctx, []*paperless.Document{document})
+	s.documents.put(scope, document)
+	return document, nil
+}
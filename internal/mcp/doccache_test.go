@@ -0,0 +1,115 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// TestDocumentCache tests LRU eviction, session scoping and invalidation
+func TestDocumentCache(t *testing.T) {
+	a := cacheScope{identity: "default", session: "a"}
+	b := cacheScope{identity: "default", session: "b"}
+	c := newDocumentCache(2, time.Minute)
+
+	c.put(a, &paperless.Document{ID: 1})
+	c.put(a, &paperless.Document{ID: 2})
+	c.get(a, 1) // 1 is now the most recently used
+	c.put(a, &paperless.Document{ID: 3})
+
+	if c.get(a, 2) != nil {
+		t.Error("expected least recently used document 2 to be evicted")
+	}
+	if c.get(a, 1) == nil || c.get(a, 3) == nil {
+		t.Error("expected documents 1 and 3 to be cached")
+	}
+	if c.get(b, 1) != nil {
+		t.Error("expected sessions to be isolated")
+	}
+
+	c.put(b, &paperless.Document{ID: 1})
+	c.invalidate(1)
+	if c.get(a, 1) != nil || c.get(b, 1) != nil {
+		t.Error("expected invalidation to apply to every session")
+	}
+
+	stats := c.snapshot()
+	if stats.Evictions != 1 || stats.Invalidations != 2 || stats.Hits == 0 || stats.Misses == 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if removed := c.clear(&b); removed != 0 {
+		t.Errorf("expected nothing to clear for session b, removed %d", removed)
+	}
+
+	c.dropSession("a")
+	if c.get(a, 3) != nil {
+		t.Error("expected dropped session to be empty")
+	}
+
+	expired := newDocumentCache(2, time.Nanosecond)
+	expired.put(a, &paperless.Document{ID: 1})
+	time.Sleep(time.Millisecond)
+	if expired.get(a, 1) != nil {
+		t.Error("expected expired document to be a miss")
+	}
+
+	disabled := newDocumentCache(0, time.Minute)
+	disabled.put(a, &paperless.Document{ID: 1})
+	if disabled.get(a, 1) != nil {
+		t.Error("expected a zero-size cache to be disabled")
+	}
+}
+
+// TestDocumentCacheIdentities tests that callers with different Paperless
+// tokens presenting the same session ID do not see each other's cached
+// documents, and that ending the session drops the documents of both
+func TestDocumentCacheIdentities(t *testing.T) {
+	fetched := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/documents/7/":
+			if r.Header.Get(paperless.AuthHeaderName) != "Token alice-token" {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"detail":"No Document matches the given query."}`))
+				return
+			}
+			fetched++
+			w.Write([]byte(`{"id":7,"title":"Payslip","original_size":512}`))
+		default:
+			w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+		}
+	}))
+	defer ts.Close()
+
+	s := &Server{
+		paperlessClient: paperless.New(ts.URL, "server-token"),
+		documents:       newDocumentCache(10, time.Hour),
+		metadata:        newMetadataCaches(time.Hour),
+	}
+	alice := paperless.WithToken(context.Background(), "alice-token")
+	bob := paperless.WithToken(context.Background(), "bob-token")
+	if sessionKey(alice) != sessionKey(bob) {
+		t.Fatal("Expected both callers to share the session")
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := s.getDocument(alice, 7); err != nil {
+			t.Fatalf("Expected alice to read document 7, got %v", err)
+		}
+	}
+	if fetched != 1 {
+		t.Errorf("Expected alice's second read to be served from the cache, got %d fetches", fetched)
+	}
+	if _, err := s.getDocument(bob, 7); err == nil {
+		t.Error("Expected bob not to be served alice's cached document")
+	}
+
+	s.documents.dropSession(sessionKey(alice))
+	if stats := s.documents.snapshot(); stats.Entries != 0 {
+		t.Errorf("Expected the ended session to be dropped for every identity, got %d entries", stats.Entries)
+	}
+}
@@ -0,0 +1,100 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// MaxPreviewChars bounds the preview_chars argument of the document search
+// and list tools
+const MaxPreviewChars = 2000
+
+// Where a document preview was taken from
+const (
+	PreviewSourceHighlight = "highlight"
+	PreviewSourceContent   = "content"
+)
+
+// highlightTagPattern matches the markup Paperless wraps around matched
+// terms in search highlights
+var highlightTagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// documentPreview is a document returned with a short preview in place of
+// its content
+type documentPreview struct {
+	paperless.Document
+	Preview       string `json:"preview"`
+	PreviewSource string `json:"preview_source,omitempty"`
+}
+
+// previewCharsArg reads the optional preview_chars argument; 0 means
+// documents are returned with their full content
+func previewCharsArg(args map[string]interface{}) (int, error) {
+	value, ok := args["preview_chars"].(float64)
+	if !ok || value == 0 {
+		return 0, nil
+	}
+	if value < 1 || value > MaxPreviewChars {
+		return 0, fmt.Errorf("preview_chars must be between 1 and %d", MaxPreviewChars)
+	}
+	return int(value), nil
+}
+
+// searchHighlights returns the full text search highlight of each document
+// in a page of results, stripped of markup. Results of plain listings have
+// none.
+func searchHighlights(results json.RawMessage) map[int]string {
+	var hits []struct {
+		ID  int `json:"id"`
+		Hit *struct {
+			Highlights string `json:"highlights"`
+		} `json:"__search_hit__"`
+	}
+	if err := json.Unmarshal(results, &hits); err != nil {
+		return nil
+	}
+	highlights := make(map[int]string)
+	for _, hit := range hits {
+		if hit.Hit != nil && hit.Hit.Highlights != "" {
+			highlights[hit.ID] = html.UnescapeString(highlightTagPattern.ReplaceAllString(hit.Hit.Highlights, ""))
+		}
+	}
+	return highlights
+}
+
+// previewDocuments replaces the content of each document with a preview of
+// at most chars characters, preferring its search highlight
+func previewDocuments(documents []paperless.Document, highlights map[int]string, chars int) []documentPreview {
+	previews := make([]documentPreview, len(documents))
+	for i, document := range documents {
+		source, text := PreviewSourceContent, document.Content
+		if highlight, ok := highlights[document.ID]; ok {
+			source, text = PreviewSourceHighlight, highlight
+		}
+		if text == "" {
+			source = ""
+		}
+		document.Content = ""
+		previews[i] = documentPreview{
+			Document:      document,
+			Preview:       truncatePreview(text, chars),
+			PreviewSource: source,
+		}
+	}
+	return previews
+}
+
+// truncatePreview collapses whitespace in text and cuts it to at most chars
+// characters, marking a cut with an ellipsis
+func truncatePreview(text string, chars int) string {
+	runes := []rune(strings.Join(strings.Fields(text), " "))
+	if len(runes) <= chars {
+		return string(runes)
+	}
+	return strings.TrimSpace(string(runes[:chars-1])) + "…"
+}
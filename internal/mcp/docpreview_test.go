@@ -0,0 +1,42 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// TestPreviewDocuments tests that previews prefer the search highlight and
+// otherwise cut the content
+func TestPreviewDocuments(t *testing.T) {
+	results := json.RawMessage(`[
+		{"id":1,"content":"Invoice for March","__search_hit__":{"score":1,"highlights":"the <span class=\"match\">invoice</span> &amp; receipt"}},
+		{"id":2,"content":"Lease agreement\n\nbetween   landlord and tenant"}
+	]`)
+	var documents []paperless.Document
+	if err := json.Unmarshal(results, &documents); err != nil {
+		t.Fatalf("Failed to parse documents: %v", err)
+	}
+
+	previews := previewDocuments(documents, searchHighlights(results), 25)
+	if previews[0].Preview != "the invoice & receipt" || previews[0].PreviewSource != PreviewSourceHighlight {
+		t.Errorf("Expected the highlight without markup, got %+v", previews[0])
+	}
+	if previews[1].Preview != "Lease agreement between…" || previews[1].PreviewSource != PreviewSourceContent {
+		t.Errorf("Expected the start of the content, got %q", previews[1].Preview)
+	}
+	if previews[1].Content != "" {
+		t.Errorf("Expected the full content to be left out, got %q", previews[1].Content)
+	}
+}
+
+// TestPreviewCharsArg tests the bounds of preview_chars
+func TestPreviewCharsArg(t *testing.T) {
+	if n, err := previewCharsArg(map[string]interface{}{}); err != nil || n != 0 {
+		t.Errorf("Expected no preview by default, got %d (%v)", n, err)
+	}
+	if _, err := previewCharsArg(map[string]interface{}{"preview_chars": float64(MaxPreviewChars + 1)}); err == nil {
+		t.Error("Expected preview_chars above the maximum to be rejected")
+	}
+}
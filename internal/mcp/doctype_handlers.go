@@ -4,8 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log/slog"
 
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
 	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
 )
 
@@ -31,34 +31,33 @@ func (s *Server) handleListDocumentTypes(ctx context.Context, args map[string]in
 		}
 	}
 
-	slog.Debug("Listing document types", "page", page, "page_size", pageSize)
+	// Optional case-insensitive name filter and ordering
+	opts, err := parseListOptions(args, metadataOrderings)
+	if err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Debug("Listing document types", "name", opts.Name, "ordering", opts.Ordering, "page", page, "page_size", pageSize)
 
 	// Call Paperless API
-	response, err := s.paperlessClient.ListDocumentTypes(ctx, page, pageSize)
+	response, err := s.paperlessClient.ListDocumentTypesMatching(ctx, opts, page, pageSize)
 	if err != nil {
-		slog.Error("Failed to list document types", "error", err)
+		logging.FromContext(ctx).Error("Failed to list document types", "error", err)
 		return nil, fmt.Errorf("failed to list document types: %w", err)
 	}
 
 	// Parse document types from Results
 	var documentTypes []paperless.DocumentType
 	if err := json.Unmarshal(response.Results, &documentTypes); err != nil {
-		slog.Error("Failed to parse document types results", "error", err)
+		logging.FromContext(ctx).Error("Failed to parse document types results", "error", err)
 		return nil, fmt.Errorf("failed to parse results: %w", err)
 	}
 
-	slog.Info("Document types listed successfully",
+	logging.FromContext(ctx).Info("Document types listed successfully",
 		"count", response.Count,
 		"returned", len(documentTypes))
 
-	return map[string]interface{}{
-		"count":          response.Count,
-		"page":           page,
-		"page_size":      pageSize,
-		"has_next":       response.Next != nil,
-		"has_prev":       response.Previous != nil,
-		"document_types": documentTypes,
-	}, nil
+	return newListResult(documentTypes, response.Count, page, pageSize, listOptionFilters(opts)), nil
 }
 
 // handleGetDocumentType handles the get_document_type tool
@@ -73,18 +72,18 @@ func (s *Server) handleGetDocumentType(ctx context.Context, args map[string]inte
 		return nil, fmt.Errorf("document_type_id must be a positive integer")
 	}
 
-	slog.Debug("Getting document type", "document_type_id", documentTypeID)
+	logging.FromContext(ctx).Debug("Getting document type", "document_type_id", documentTypeID)
 
 	// Call Paperless API
 	documentType, err := s.paperlessClient.GetDocumentType(ctx, documentTypeID)
 	if err != nil {
-		slog.Error("Failed to get document type",
+		logging.FromContext(ctx).Error("Failed to get document type",
 			"document_type_id", documentTypeID,
 			"error", err)
 		return nil, fmt.Errorf("failed to get document type: %w", err)
 	}
 
-	slog.Info("Document type retrieved successfully",
+	logging.FromContext(ctx).Info("Document type retrieved successfully",
 		"document_type_id", documentTypeID,
 		"name", documentType.Name)
 
@@ -99,7 +98,7 @@ func (s *Server) handleCreateDocumentType(ctx context.Context, args map[string]i
 		return nil, fmt.Errorf("name parameter is required and must be a non-empty string")
 	}
 
-	slog.Debug("Creating document type", "name", name)
+	logging.FromContext(ctx).Debug("Creating document type", "name", name)
 
 	// Build document type from args
 	documentType := &paperless.DocumentType{
@@ -120,13 +119,18 @@ func (s *Server) handleCreateDocumentType(ctx context.Context, args map[string]i
 	// Call Paperless API
 	createdDocumentType, err := s.paperlessClient.CreateDocumentType(ctx, documentType)
 	if err != nil {
-		slog.Error("Failed to create document type",
+		logging.FromContext(ctx).Error("Failed to create document type",
 			"name", name,
 			"error", err)
 		return nil, fmt.Errorf("failed to create document type: %w", err)
 	}
 
-	slog.Info("Document type created successfully",
+	s.metadata.documentTypes.invalidate()
+
+	s.journal.recordCreate(ctx, "create_document_type", "document_types", createdDocumentType.ID,
+		fmt.Sprintf("Created document type %q", createdDocumentType.Name))
+
+	logging.FromContext(ctx).Info("Document type created successfully",
 		"document_type_id", createdDocumentType.ID,
 		"name", createdDocumentType.Name)
 
@@ -157,24 +161,36 @@ func (s *Server) handleUpdateDocumentType(ctx context.Context, args map[string]i
 		return nil, fmt.Errorf("at least one field to update must be provided")
 	}
 
-	slog.Debug("Updating document type",
+	logging.FromContext(ctx).Debug("Updating document type",
 		"document_type_id", documentTypeID,
 		"fields", len(updates))
 
+	rename, err := s.checkRename(ctx, "document_types", documentTypeID, updates)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := s.snapshotFields(ctx, "document_types", documentTypeID, updates)
+
 	// Call Paperless API
 	updatedDocumentType, err := s.paperlessClient.UpdateDocumentType(ctx, documentTypeID, updates)
 	if err != nil {
-		slog.Error("Failed to update document type",
+		logging.FromContext(ctx).Error("Failed to update document type",
 			"document_type_id", documentTypeID,
 			"error", err)
 		return nil, fmt.Errorf("failed to update document type: %w", err)
 	}
 
-	slog.Info("Document type updated successfully",
+	s.metadata.documentTypes.invalidate()
+
+	s.journal.recordUpdate(ctx, "update_document_type", "document_types", documentTypeID, previous,
+		fmt.Sprintf("Updated document type %d", documentTypeID))
+
+	logging.FromContext(ctx).Info("Document type updated successfully",
 		"document_type_id", documentTypeID,
 		"name", updatedDocumentType.Name)
 
-	return updatedDocumentType, nil
+	return withRenameReport(ctx, updatedDocumentType, updatedDocumentType.Slug, rename)
 }
 
 // handleDeleteDocumentType handles the delete_document_type tool
@@ -189,18 +205,20 @@ func (s *Server) handleDeleteDocumentType(ctx context.Context, args map[string]i
 		return nil, fmt.Errorf("document_type_id must be a positive integer")
 	}
 
-	slog.Debug("Deleting document type", "document_type_id", documentTypeID)
+	logging.FromContext(ctx).Debug("Deleting document type", "document_type_id", documentTypeID)
 
 	// Call Paperless API
 	err := s.paperlessClient.DeleteDocumentType(ctx, documentTypeID)
 	if err != nil {
-		slog.Error("Failed to delete document type",
+		logging.FromContext(ctx).Error("Failed to delete document type",
 			"document_type_id", documentTypeID,
 			"error", err)
 		return nil, fmt.Errorf("failed to delete document type: %w", err)
 	}
 
-	slog.Info("Document type deleted successfully", "document_type_id", documentTypeID)
+	s.metadata.documentTypes.invalidate()
+
+	logging.FromContext(ctx).Info("Document type deleted successfully", "document_type_id", documentTypeID)
 
 	return map[string]interface{}{
 		"success":          true,
@@ -4,8 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
 
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
 	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
 )
 
@@ -44,15 +48,29 @@ func (s *Server) handleSearchDocuments(ctx context.Context, args map[string]inte
 		}
 	}
 
-	slog.Debug("Searching documents",
+	previewChars, err := previewCharsArg(args)
+	if err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Debug("Searching documents",
 		"query", query,
 		"page", page,
 		"page_size", pageSize)
 
-	// Call Paperless API
-	response, err := s.paperlessClient.SearchDocuments(ctx, query, page, pageSize)
+	// Call Paperless API, through the filtered document list when the
+	// session restricts searches to default tags
+	filters := url.Values{}
+	sessionTags := s.applyDefaultTags(ctx, filters)
+	var response *paperless.PaginatedResponse
+	if sessionTags != nil {
+		filters.Set("query", query)
+		response, err = s.paperlessClient.ListDocuments(ctx, filters, page, pageSize)
+	} else {
+		response, err = s.paperlessClient.SearchDocuments(ctx, query, page, pageSize)
+	}
 	if err != nil {
-		slog.Error("Failed to search documents",
+		logging.FromContext(ctx).Error("Failed to search documents",
 			"query", query,
 			"error", err)
 		return nil, fmt.Errorf("failed to search documents: %w", err)
@@ -61,23 +79,24 @@ func (s *Server) handleSearchDocuments(ctx context.Context, args map[string]inte
 	// Parse documents from Results
 	var documents []paperless.Document
 	if err := json.Unmarshal(response.Results, &documents); err != nil {
-		slog.Error("Failed to parse search results", "error", err)
+		logging.FromContext(ctx).Error("Failed to parse search results", "error", err)
 		return nil, fmt.Errorf("failed to parse search results: %w", err)
 	}
 
-	slog.Info("Documents search completed",
+	logging.FromContext(ctx).Info("Documents search completed",
 		"query", query,
 		"found", response.Count,
 		"returned", len(documents))
 
-	return map[string]interface{}{
-		"count":      response.Count,
-		"page":       page,
-		"page_size":  pageSize,
-		"has_next":   response.Next != nil,
-		"has_prev":   response.Previous != nil,
-		"documents":  documents,
-	}, nil
+	applied := map[string]interface{}{"query": query}
+	if sessionTags != nil {
+		applied["session_tags"] = sessionTags
+	}
+	var items interface{} = documents
+	if previewChars > 0 {
+		items = previewDocuments(documents, searchHighlights(response.Results), previewChars)
+	}
+	return newListResult(items, response.Count, page, pageSize, applied), nil
 }
 
 // handleFindSimilarDocuments handles the find_similar_documents tool
@@ -112,7 +131,12 @@ func (s *Server) handleFindSimilarDocuments(ctx context.Context, args map[string
 		}
 	}
 
-	slog.Debug("Finding similar documents",
+	previewChars, err := previewCharsArg(args)
+	if err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Debug("Finding similar documents",
 		"document_id", documentID,
 		"page", page,
 		"page_size", pageSize)
@@ -120,7 +144,7 @@ func (s *Server) handleFindSimilarDocuments(ctx context.Context, args map[string
 	// Call Paperless API
 	response, err := s.paperlessClient.GetSimilarDocuments(ctx, documentID, page, pageSize)
 	if err != nil {
-		slog.Error("Failed to find similar documents",
+		logging.FromContext(ctx).Error("Failed to find similar documents",
 			"document_id", documentID,
 			"error", err)
 		return nil, fmt.Errorf("failed to find similar documents: %w", err)
@@ -129,24 +153,22 @@ func (s *Server) handleFindSimilarDocuments(ctx context.Context, args map[string
 	// Parse documents from Results
 	var documents []paperless.Document
 	if err := json.Unmarshal(response.Results, &documents); err != nil {
-		slog.Error("Failed to parse similar documents results", "error", err)
+		logging.FromContext(ctx).Error("Failed to parse similar documents results", "error", err)
 		return nil, fmt.Errorf("failed to parse results: %w", err)
 	}
 
-	slog.Info("Similar documents search completed",
+	logging.FromContext(ctx).Info("Similar documents search completed",
 		"document_id", documentID,
 		"found", response.Count,
 		"returned", len(documents))
 
-	return map[string]interface{}{
+	var items interface{} = documents
+	if previewChars > 0 {
+		items = previewDocuments(documents, searchHighlights(response.Results), previewChars)
+	}
+	return newListResult(items, response.Count, page, pageSize, map[string]interface{}{
 		"document_id": documentID,
-		"count":       response.Count,
-		"page":        page,
-		"page_size":   pageSize,
-		"has_next":    response.Next != nil,
-		"has_prev":    response.Previous != nil,
-		"documents":   documents,
-	}, nil
+	}), nil
 }
 
 // handleGetDocument handles the get_document tool
@@ -161,21 +183,34 @@ func (s *Server) handleGetDocument(ctx context.Context, args map[string]interfac
 		return nil, fmt.Errorf("document_id must be a positive integer")
 	}
 
-	slog.Debug("Getting document", "document_id", documentID)
+	logging.FromContext(ctx).Debug("Getting document", "document_id", documentID)
 
-	// Call Paperless API
-	document, err := s.paperlessClient.GetDocument(ctx, documentID)
+	// Served from the session cache when recently fetched
+	document, err := s.getDocument(ctx, documentID)
 	if err != nil {
-		slog.Error("Failed to get document",
+		logging.FromContext(ctx).Error("Failed to get document",
 			"document_id", documentID,
 			"error", err)
 		return nil, fmt.Errorf("failed to get document: %w", err)
 	}
 
-	slog.Info("Document retrieved successfully",
+	logging.FromContext(ctx).Info("Document retrieved successfully",
 		"document_id", documentID,
 		"title", document.Title)
 
+	// Attach the file itself for clients that can open resources
+	if includeFile, _ := args["include_file"].(bool); includeFile {
+		original, _ := args["original"].(bool)
+		file, err := s.paperlessClient.DownloadDocument(ctx, documentID, original)
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to download document",
+				"document_id", documentID,
+				"error", err)
+			return nil, fmt.Errorf("failed to download document: %w", err)
+		}
+		return withResources(document, documentResource(documentID, original, file)), nil
+	}
+
 	return document, nil
 }
 
@@ -191,18 +226,19 @@ func (s *Server) handleGetDocumentContent(ctx context.Context, args map[string]i
 		return nil, fmt.Errorf("document_id must be a positive integer")
 	}
 
-	slog.Debug("Getting document content", "document_id", documentID)
+	logging.FromContext(ctx).Debug("Getting document content", "document_id", documentID)
 
-	// Call Paperless API
-	content, err := s.paperlessClient.GetDocumentContent(ctx, documentID)
+	// Served from the session cache when recently fetched
+	document, err := s.getDocument(ctx, documentID)
 	if err != nil {
-		slog.Error("Failed to get document content",
+		logging.FromContext(ctx).Error("Failed to get document content",
 			"document_id", documentID,
 			"error", err)
 		return nil, fmt.Errorf("failed to get document content: %w", err)
 	}
+	content := document.Content
 
-	slog.Info("Document content retrieved successfully",
+	logging.FromContext(ctx).Info("Document content retrieved successfully",
 		"document_id", documentID,
 		"content_length", len(content))
 
@@ -220,7 +256,7 @@ func (s *Server) handleCreateDocument(ctx context.Context, args map[string]inter
 		return nil, fmt.Errorf("title parameter is required and must be a non-empty string")
 	}
 
-	slog.Debug("Creating document", "title", title)
+	logging.FromContext(ctx).Debug("Creating document", "title", title)
 
 	// Build document from args
 	document := &paperless.Document{
@@ -250,20 +286,25 @@ func (s *Server) handleCreateDocument(ctx context.Context, args map[string]inter
 		document.Tags = tags
 	}
 
-	// Call Paperless API
-	createdDocument, err := s.paperlessClient.CreateDocument(ctx, document)
-	if err != nil {
-		slog.Error("Failed to create document",
-			"title", title,
-			"error", err)
-		return nil, fmt.Errorf("failed to create document: %w", err)
-	}
+	// Call Paperless API, at most once per idempotency key
+	return s.withIdempotency(ctx, "create_document", args, func() (interface{}, error) {
+		createdDocument, err := s.paperlessClient.CreateDocument(ctx, document)
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to create document",
+				"title", title,
+				"error", err)
+			return nil, fmt.Errorf("failed to create document: %w", err)
+		}
 
-	slog.Info("Document created successfully",
-		"document_id", createdDocument.ID,
-		"title", createdDocument.Title)
+		s.journal.recordCreate(ctx, "create_document", "documents", createdDocument.ID,
+			fmt.Sprintf("Created document %q", createdDocument.Title))
 
-	return createdDocument, nil
+		logging.FromContext(ctx).Info("Document created successfully",
+			"document_id", createdDocument.ID,
+			"title", createdDocument.Title)
+
+		return createdDocument, nil
+	})
 }
 
 // handleUpdateDocument handles the update_document tool
@@ -278,10 +319,15 @@ func (s *Server) handleUpdateDocument(ctx context.Context, args map[string]inter
 		return nil, fmt.Errorf("document_id must be a positive integer")
 	}
 
-	// Build updates map from args (exclude document_id)
+	expectedModified, checkModified, err := parseExpectedModified(args)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build updates map from args (exclude document_id and expected_modified)
 	updates := make(map[string]interface{})
 	for key, value := range args {
-		if key != "document_id" {
+		if key != "document_id" && key != "expected_modified" {
 			updates[key] = value
 		}
 	}
@@ -290,26 +336,80 @@ func (s *Server) handleUpdateDocument(ctx context.Context, args map[string]inter
 		return nil, fmt.Errorf("at least one field to update must be provided")
 	}
 
-	slog.Debug("Updating document",
+	if checkModified {
+		if err := s.checkExpectedModified(ctx, documentID, expectedModified); err != nil {
+			return nil, err
+		}
+	}
+
+	if asn, ok := updates["archive_serial_number"].(float64); ok {
+		if err := s.checkASNAvailable(ctx, int(asn), documentID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Custom fields may be given by name and are validated against their
+	// data type before anything is sent
+	if raw, ok := updates["custom_fields"]; ok {
+		values, err := s.customFieldUpdates(ctx, documentID, raw)
+		if err != nil {
+			return nil, err
+		}
+		updates["custom_fields"] = values
+	}
+
+	logging.FromContext(ctx).Debug("Updating document",
 		"document_id", documentID,
 		"fields", len(updates))
 
+	previous := s.snapshotFields(ctx, "documents", documentID, updates)
+
 	// Call Paperless API
 	updatedDocument, err := s.paperlessClient.UpdateDocument(ctx, documentID, updates)
+	// Invalidate even on failure; the change may still have been applied
+	s.documents.invalidate(documentID)
 	if err != nil {
-		slog.Error("Failed to update document",
+		logging.FromContext(ctx).Error("Failed to update document",
 			"document_id", documentID,
 			"error", err)
 		return nil, fmt.Errorf("failed to update document: %w", err)
 	}
 
-	slog.Info("Document updated successfully",
+	s.journal.recordUpdate(ctx, "update_document", "documents", documentID, previous,
+		fmt.Sprintf("Updated document %d", documentID))
+
+	logging.FromContext(ctx).Info("Document updated successfully",
 		"document_id", documentID,
 		"title", updatedDocument.Title)
 
 	return updatedDocument, nil
 }
 
+// checkASNAvailable returns an error naming the document that already
+// holds an archive serial number, other than documentID, so callers get a
+// clear answer instead of Paperless's unique constraint error. A failed
+// lookup is logged and left for Paperless to validate.
+func (s *Server) checkASNAvailable(ctx context.Context, asn, documentID int) error {
+	if asn < 0 {
+		return fmt.Errorf("archive_serial_number must be a non-negative integer")
+	}
+	holder, err := s.paperlessClient.FindDocumentByASN(ctx, asn)
+	if err != nil {
+		logging.FromContext(ctx).Warn("Failed to check archive serial number",
+			"asn", asn,
+			"error", err)
+		return nil
+	}
+	if holder != nil && holder.ID != documentID {
+		logging.FromContext(ctx).Warn("Archive serial number already in use",
+			"asn", asn,
+			"document_id", documentID,
+			"holder_id", holder.ID)
+		return fmt.Errorf("archive serial number %d is already assigned to document %d (%q)", asn, holder.ID, holder.Title)
+	}
+	return nil
+}
+
 // handleDeleteDocument handles the delete_document tool
 func (s *Server) handleDeleteDocument(ctx context.Context, args map[string]interface{}) (interface{}, error) {
 	// Extract and validate document_id
@@ -322,18 +422,63 @@ func (s *Server) handleDeleteDocument(ctx context.Context, args map[string]inter
 		return nil, fmt.Errorf("document_id must be a positive integer")
 	}
 
-	slog.Debug("Deleting document", "document_id", documentID)
+	logging.FromContext(ctx).Debug("Deleting document", "document_id", documentID)
+
+	if err := s.checkDeleteProtection(ctx, []int{documentID}); err != nil {
+		return nil, fmt.Errorf("document %d was not deleted: %w", documentID, err)
+	}
+
+	// Guard against a transposed or misremembered ID by checking the title
+	// the caller believes it is deleting
+	confirmTitle, _ := args["confirm_title"].(string)
+	if confirmTitle == "" && s.cfg.MCPDeleteRequireTitle {
+		return nil, fmt.Errorf("confirm_title is required: pass the title of the document to delete, or a distinctive part of it")
+	}
+	askUser := s.confirmationEnabled(ctx)
+	if confirmTitle != "" || askUser {
+		// Always check against Paperless rather than a cached copy
+		document, err := s.paperlessClient.GetDocument(ctx, documentID)
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to get document",
+				"document_id", documentID,
+				"error", err)
+			return nil, fmt.Errorf("failed to get document: %w", err)
+		}
+		if confirmTitle != "" {
+			if err := checkConfirmTitle(document.Title, confirmTitle); err != nil {
+				logging.FromContext(ctx).Warn("Delete confirmation failed",
+					"document_id", documentID,
+					"error", err)
+				return nil, fmt.Errorf("document %d was not deleted: %w", documentID, err)
+			}
+		}
+		if askUser {
+			message := fmt.Sprintf("Delete document %d %q? It will be moved to the Paperless trash.", documentID, document.Title)
+			if err := s.confirm(ctx, "delete_document", message); err != nil {
+				return nil, fmt.Errorf("document %d was not deleted: %w", documentID, err)
+			}
+		}
+	}
 
 	// Call Paperless API
 	err := s.paperlessClient.DeleteDocument(ctx, documentID)
+	s.documents.invalidate(documentID)
 	if err != nil {
-		slog.Error("Failed to delete document",
+		logging.FromContext(ctx).Error("Failed to delete document",
 			"document_id", documentID,
 			"error", err)
 		return nil, fmt.Errorf("failed to delete document: %w", err)
 	}
 
-	slog.Info("Document deleted successfully", "document_id", documentID)
+	s.journal.record(ctx, journalEntry{
+		Tool:     "delete_document",
+		Summary:  fmt.Sprintf("Deleted document %d", documentID),
+		Action:   undoRestoreDeleted,
+		Resource: "documents",
+		Objects:  []journalObject{{ID: documentID}},
+	})
+
+	logging.FromContext(ctx).Info("Document deleted successfully", "document_id", documentID)
 
 	return map[string]interface{}{
 		"success":     true,
@@ -342,6 +487,31 @@ func (s *Server) handleDeleteDocument(ctx context.Context, args map[string]inter
 	}, nil
 }
 
+// MinConfirmTitleLength is the shortest title fragment accepted as
+// confirmation, so a single letter cannot confirm any document
+const MinConfirmTitleLength = 4
+
+// normalizeTitle lowercases a title and collapses its whitespace
+func normalizeTitle(title string) string {
+	return strings.Join(strings.Fields(strings.ToLower(title)), " ")
+}
+
+// checkConfirmTitle verifies that confirm is the document's title or a
+// distinctive part of it. The actual title is not included in the error
+// so it cannot simply be copied into a retry.
+func checkConfirmTitle(title, confirm string) error {
+	want, got := normalizeTitle(title), normalizeTitle(confirm)
+	if got == want {
+		return nil
+	}
+	if len([]rune(got)) < MinConfirmTitleLength {
+		return fmt.Errorf("confirm_title must be the full title or at least %d characters of it", MinConfirmTitleLength)
+	}
+	if !strings.Contains(want, got) {
+		return fmt.Errorf("confirm_title %q does not match the document's title; check the document ID", confirm)
+	}
+	return nil
+}
 
 // handleBulkEditDocuments handles the bulk_edit_documents tool
 func (s *Server) handleBulkEditDocuments(ctx context.Context, args map[string]interface{}) (interface{}, error) {
@@ -413,22 +583,313 @@ func (s *Server) handleBulkEditDocuments(ctx context.Context, args map[string]in
 		return nil, fmt.Errorf("at least one operation must be specified")
 	}
 
-	slog.Debug("Bulk editing documents",
+	verify, _ := args["verify"].(string)
+	switch verify {
+	case "":
+		verify = BulkVerifyNone
+	case BulkVerifyNone, BulkVerifySample, BulkVerifyAll:
+	default:
+		return nil, fmt.Errorf("verify must be one of: none, sample, all")
+	}
+
+	if planOnly(args) {
+		logging.FromContext(ctx).Debug("Planning bulk edit",
+			"document_count", len(documentIDs),
+			"operations", len(operations))
+		return s.planBulkEdit(documentIDs, operations, verify), nil
+	}
+
+	logging.FromContext(ctx).Debug("Bulk editing documents",
 		"document_count", len(documentIDs),
 		"operations", len(operations))
 
-	// Call Paperless API
-	response, err := s.paperlessClient.BulkEditDocuments(ctx, documentIDs, operations)
+	if len(documentIDs) > s.cfg.MCPConfirmThreshold {
+		message := fmt.Sprintf("Apply %s to %d documents?", describeBulkEdit(operations), len(documentIDs))
+		if err := s.confirm(ctx, "bulk_edit_documents", message); err != nil {
+			return nil, fmt.Errorf("documents were not edited: %w", err)
+		}
+	}
+
+	previous := s.snapshotBulkEdit(ctx, documentIDs, operations)
+
+	// Call Paperless API in chunks
+	result, edited, err := s.bulkEditInChunks(ctx, documentIDs, operations)
 	if err != nil {
-		slog.Error("Failed to bulk edit documents",
+		logging.FromContext(ctx).Error("Failed to bulk edit documents",
 			"document_count", len(documentIDs),
 			"error", err)
 		return nil, fmt.Errorf("failed to bulk edit documents: %w", err)
 	}
 
-	slog.Info("Bulk edit completed successfully",
+	s.recordBulkEdit(ctx, previous, edited)
+
+	logging.FromContext(ctx).Info("Bulk edit completed",
 		"document_count", len(documentIDs),
+		"edited", len(edited),
 		"operations", len(operations))
 
-	return response, nil
+	if verify != BulkVerifyNone {
+		if response, ok := result.(map[string]interface{}); ok {
+			response["verification"] = s.verifyBulkEdit(ctx, edited, operations, verify)
+		}
+	}
+
+	return result, nil
+}
+
+// recordBulkEdit journals the previous values of the edited documents for
+// undo; previous is nil when they could not be read
+func (s *Server) recordBulkEdit(ctx context.Context, previous []journalObject, edited []int) {
+	if previous == nil {
+		return
+	}
+	done := make(map[int]bool, len(edited))
+	for _, id := range edited {
+		done[id] = true
+	}
+	objects := make([]journalObject, 0, len(edited))
+	for _, object := range previous {
+		if done[object.ID] {
+			objects = append(objects, object)
+		}
+	}
+	s.journal.record(ctx, journalEntry{
+		Tool:     "bulk_edit_documents",
+		Summary:  fmt.Sprintf("Bulk edited %d documents", len(edited)),
+		Action:   undoRestoreFields,
+		Resource: "documents",
+		Objects:  objects,
+	})
+}
+
+// bulkEditChunk is the outcome of one bulk edit request
+type bulkEditChunk struct {
+	Documents int                    `json:"documents"`
+	Response  map[string]interface{} `json:"response,omitempty"`
+	Error     string                 `json:"error,omitempty"`
+}
+
+// bulkEditInChunks sends a bulk edit to Paperless in requests of at most
+// MCPBulkChunkSize documents, one after another, so that no single request
+// runs long enough to time out and the client's rate limit handling can
+// pace them. It stops at the first failed chunk. Edits that fit in one
+// chunk return Paperless' response unchanged; otherwise the per-chunk
+// results are aggregated and the progress is kept in the operation journal
+// so an edit cut short can be finished with resume_operation. The IDs of
+// edited documents are returned, and an error only when nothing was edited.
+func (s *Server) bulkEditInChunks(ctx context.Context, documentIDs []int, operations map[string]interface{}) (interface{}, []int, error) {
+	chunkSize := s.cfg.MCPBulkChunkSize
+	if len(documentIDs) <= chunkSize {
+		response, err := s.paperlessClient.BulkEditDocuments(ctx, documentIDs, operations)
+		s.documents.invalidate(documentIDs...)
+		if err != nil {
+			return nil, nil, err
+		}
+		return response, documentIDs, nil
+	}
+
+	record := s.operations.start(ctx, "bulk_edit_documents", documentIDs, operations)
+	return s.runBulkEditOperation(ctx, record)
+}
+
+// runBulkEditOperation sends the chunks of a journaled bulk edit from its
+// completed offset on, recording progress after each chunk. The IDs of
+// the documents edited by this run are returned.
+func (s *Server) runBulkEditOperation(ctx context.Context, record operationRecord) (interface{}, []int, error) {
+	documentIDs, operations := record.DocumentIDs, record.Operations
+	chunkSize := s.cfg.MCPBulkChunkSize
+	total := len(documentIDs)
+	chunks := make([]bulkEditChunk, 0, (total-record.Completed+chunkSize-1)/chunkSize)
+	start := record.Completed
+	edited := start
+	var chunkErr error
+	for edited < total {
+		chunk := documentIDs[edited:min(edited+chunkSize, total)]
+
+		logging.FromContext(ctx).Debug("Bulk editing chunk",
+			"chunk", len(chunks)+1,
+			"offset", edited,
+			"document_count", len(chunk))
+
+		response, err := s.paperlessClient.BulkEditDocuments(ctx, chunk, operations)
+		s.documents.invalidate(chunk...)
+		if err != nil {
+			chunkErr = err
+			chunks = append(chunks, bulkEditChunk{Documents: len(chunk), Error: err.Error()})
+			break
+		}
+		chunks = append(chunks, bulkEditChunk{Documents: len(chunk), Response: response})
+		edited += len(chunk)
+		s.operations.advance(record.ID, edited)
+
+		s.reportProgress(ctx, float64(edited), float64(total),
+			fmt.Sprintf("Edited %d of %d documents", edited, total))
+	}
+
+	if chunkErr != nil {
+		s.operations.fail(record.ID, chunkErr)
+	} else {
+		s.operations.finish(record.ID)
+	}
+	if edited == start && chunkErr != nil {
+		return nil, nil, chunkErr
+	}
+
+	result := map[string]interface{}{
+		"result":         "OK",
+		"document_count": total,
+		"edited":         edited,
+		"chunks":         chunks,
+	}
+	if start > 0 {
+		result["resumed_from"] = start
+	}
+	if chunkErr != nil {
+		logging.FromContext(ctx).Warn("Bulk edit stopped after a failed chunk",
+			"edited", edited,
+			"remaining", total-edited,
+			"error", chunkErr)
+		result["result"] = "partial"
+		result["error"] = chunkErr.Error()
+		result["not_edited"] = documentIDs[edited:]
+		if record.ID != 0 {
+			result["operation_id"] = record.ID
+			result["hint"] = fmt.Sprintf("call resume_operation with operation_id %d to edit the remaining documents", record.ID)
+		}
+	}
+	return result, documentIDs[start:edited], nil
+}
+
+// MaxBulkGetDocuments bounds how many documents get_documents returns in
+// one call, matching Paperless' largest page size
+const MaxBulkGetDocuments = paperless.MaxPageSize
+
+// handleGetDocuments handles the get_documents tool
+func (s *Server) handleGetDocuments(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	// Extract and validate document_ids
+	docIDsInterface, ok := args["document_ids"].([]interface{})
+	if !ok || len(docIDsInterface) == 0 {
+		return nil, fmt.Errorf("document_ids parameter is required and must be a non-empty array")
+	}
+	if len(docIDsInterface) > MaxBulkGetDocuments {
+		return nil, fmt.Errorf("at most %d document_ids can be fetched at once", MaxBulkGetDocuments)
+	}
+
+	documentIDs := make([]int, 0, len(docIDsInterface))
+	seen := make(map[int]bool, len(docIDsInterface))
+	for _, idInterface := range docIDsInterface {
+		idFloat, ok := idInterface.(float64)
+		if !ok || idFloat < 1 {
+			return nil, fmt.Errorf("document_ids must contain only positive integers")
+		}
+		if id := int(idFloat); !seen[id] {
+			seen[id] = true
+			documentIDs = append(documentIDs, id)
+		}
+	}
+
+	logging.FromContext(ctx).Debug("Getting documents", "document_count", len(documentIDs))
+
+	// Serve what we can from the session cache and fetch the rest in a
+	// single id__in request
+	scope := documentScope(ctx)
+	found := make(map[int]*paperless.Document, len(documentIDs))
+	var missing []string
+	for _, id := range documentIDs {
+		if document := s.documents.get(scope, id); document != nil {
+			found[id] = document
+		} else {
+			missing = append(missing, strconv.Itoa(id))
+		}
+	}
+
+	if len(missing) > 0 {
+		filters := url.Values{}
+		filters.Set("id__in", strings.Join(missing, ","))
+		response, err := s.paperlessClient.ListDocuments(ctx, filters, 1, len(missing))
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to get documents", "error", err)
+			return nil, fmt.Errorf("failed to get documents: %w", err)
+		}
+
+		var documents []paperless.Document
+		if err := json.Unmarshal(response.Results, &documents); err != nil {
+			logging.FromContext(ctx).Error("Failed to parse documents results", "error", err)
+			return nil, fmt.Errorf("failed to parse results: %w", err)
+		}
+		fetched := make([]*paperless.Document, len(documents))
+		for i := range documents {
+			fetched[i] = &documents[i]
+		}
+		s.enrichDocuments(ctx, fetched)
+		s.resolveDocumentLinks(ctx, fetched)
+		for _, document := range fetched {
+			found[document.ID] = document
+			s.documents.put(scope, document)
+		}
+	}
+
+	// Return documents in the order requested
+	result := make([]*paperless.Document, 0, len(found))
+	notFound := []int{}
+	for _, id := range documentIDs {
+		if document, ok := found[id]; ok {
+			result = append(result, document)
+		} else {
+			notFound = append(notFound, id)
+		}
+	}
+
+	logging.FromContext(ctx).Info("Documents retrieved successfully",
+		"requested", len(documentIDs),
+		"returned", len(result),
+		"from_cache", len(documentIDs)-len(missing))
+
+	return map[string]interface{}{
+		"count":     len(result),
+		"documents": result,
+		"not_found": notFound,
+	}, nil
+}
+
+// enrichConcurrency bounds parallel metadata requests when enriching
+// several documents
+const enrichConcurrency = 8
+
+// enrichDocument fills in the file sizes and mail provenance of a
+// document from its metadata, attributing it to one of rules. Failures are
+// logged and leave the fields unset rather than failing the tool call.
+func (s *Server) enrichDocument(ctx context.Context, document *paperless.Document, rules []paperless.MailRule) {
+	if document.OriginalSize != nil {
+		return
+	}
+	metadata, err := s.paperlessClient.GetDocumentMetadata(ctx, document.ID)
+	if err != nil {
+		logging.FromContext(ctx).Warn("Failed to get document metadata",
+			"document_id", document.ID,
+			"error", err)
+		return
+	}
+	originalSize := metadata.OriginalSize
+	document.OriginalSize = &originalSize
+	document.ArchiveSize = metadata.ArchiveSize
+	document.MailProvenance = s.mailProvenance(ctx, document, metadata, rules)
+}
+
+// enrichDocuments enriches several documents concurrently, reading the
+// mail rules once for all of them
+func (s *Server) enrichDocuments(ctx context.Context, documents []*paperless.Document) {
+	rules := s.mailRules(ctx)
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, enrichConcurrency)
+	for _, document := range documents {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(document *paperless.Document) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			s.enrichDocument(ctx, document, rules)
+		}(document)
+	}
+	wg.Wait()
 }
@@ -0,0 +1,291 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/config"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// TestCheckConfirmTitle tests full and partial title confirmation
+func TestCheckConfirmTitle(t *testing.T) {
+	title := "Electricity  Invoice March 2024"
+
+	for _, confirm := range []string{"electricity invoice march 2024", "Invoice March", " march 2024 "} {
+		if err := checkConfirmTitle(title, confirm); err != nil {
+			t.Errorf("Expected %q to confirm %q, got %v", confirm, title, err)
+		}
+	}
+	for _, confirm := range []string{"Gas Invoice", "Inv", "2023"} {
+		if err := checkConfirmTitle(title, confirm); err == nil {
+			t.Errorf("Expected %q not to confirm %q", confirm, title)
+		}
+	}
+	if err := checkConfirmTitle("Tax", "tax"); err != nil {
+		t.Errorf("Expected a short title to be confirmed in full, got %v", err)
+	}
+}
+
+// TestBulkEditInChunks tests that large bulk edits are split into chunks
+// and that a failed chunk stops the edit with a partial result
+func TestBulkEditInChunks(t *testing.T) {
+	var requests [][]int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Documents []int `json:"documents"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		requests = append(requests, body.Documents)
+		if len(requests) == 3 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"detail":"bad request"}`))
+			return
+		}
+		w.Write([]byte(`{"result":"OK"}`))
+	}))
+	defer ts.Close()
+
+	s := &Server{
+		cfg:             &config.Config{MCPBulkChunkSize: 2},
+		paperlessClient: paperless.New(ts.URL, "test-token"),
+		documents:       newDocumentCache(10, time.Minute),
+	}
+	operations := map[string]interface{}{"add_tags": []int{1}}
+
+	response, edited, err := s.bulkEditInChunks(context.Background(), []int{1, 2}, operations)
+	if err != nil || !reflect.DeepEqual(edited, []int{1, 2}) {
+		t.Fatalf("Unexpected single chunk result: %v, %v", edited, err)
+	}
+	if response.(map[string]interface{})["result"] != "OK" {
+		t.Errorf("Expected the Paperless response for a single chunk, got %v", response)
+	}
+
+	requests = nil
+	response, edited, err = s.bulkEditInChunks(context.Background(), []int{1, 2, 3, 4, 5, 6, 7}, operations)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := [][]int{{1, 2}, {3, 4}, {5, 6}}; !reflect.DeepEqual(requests, want) {
+		t.Errorf("Expected chunks %v, got %v", want, requests)
+	}
+	result := response.(map[string]interface{})
+	if result["result"] != "partial" || result["edited"] != 4 || !reflect.DeepEqual(result["not_edited"], []int{5, 6, 7}) {
+		t.Errorf("Unexpected partial result: %v", result)
+	}
+	if !reflect.DeepEqual(edited, []int{1, 2, 3, 4}) {
+		t.Errorf("Expected documents 1-4 to be reported edited, got %v", edited)
+	}
+}
+
+// TestCheckASNAvailable tests that an archive serial number held by another
+// document is reported with its holder, and that a document may keep its own
+func TestCheckASNAvailable(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("archive_serial_number") == "42" {
+			w.Write([]byte(`{"count":1,"results":[{"id":7,"title":"Lease"}]}`))
+			return
+		}
+		w.Write([]byte(`{"count":0,"results":[]}`))
+	}))
+	defer ts.Close()
+
+	s := &Server{paperlessClient: paperless.New(ts.URL, "test-token")}
+	ctx := context.Background()
+
+	err := s.checkASNAvailable(ctx, 42, 3)
+	if err == nil || !strings.Contains(err.Error(), "document 7") {
+		t.Errorf("Expected the holding document to be named, got %v", err)
+	}
+	if err := s.checkASNAvailable(ctx, 42, 7); err != nil {
+		t.Errorf("Expected a document to keep its own ASN, got %v", err)
+	}
+	if err := s.checkASNAvailable(ctx, 43, 3); err != nil {
+		t.Errorf("Expected a free ASN to be accepted, got %v", err)
+	}
+}
+
+// TestExpectedModified tests that updates are refused, with the current
+// document, once it has been modified since the caller read it
+func TestExpectedModified(t *testing.T) {
+	var patches int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch {
+			patches++
+		}
+		w.Write([]byte(`{"id":3,"title":"Lease","modified":"2024-03-15T10:04:05.123456Z"}`))
+	}))
+	defer ts.Close()
+
+	s := &Server{
+		cfg:             &config.Config{},
+		paperlessClient: paperless.New(ts.URL, "test-token"),
+		documents:       newDocumentCache(0, 0),
+		journal:         newUndoJournal(""),
+	}
+	ctx := context.Background()
+
+	_, err := s.handleUpdateDocument(ctx, map[string]interface{}{
+		"document_id":       float64(3),
+		"title":             "Old lease",
+		"expected_modified": "2024-03-15T09:00:00Z",
+	})
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) || conflict.Current.Title != "Lease" {
+		t.Fatalf("Expected a conflict with the current document, got %v", err)
+	}
+	if patches != 0 {
+		t.Errorf("Expected no update to be sent, got %d", patches)
+	}
+	result := newToolErrorResult(err)
+	if content, ok := result.StructuredContent.(map[string]interface{}); !ok || content["current"] == nil {
+		t.Errorf("Expected the tool error to carry the current document, got %v", result.StructuredContent)
+	}
+
+	// Whole seconds, as documents are returned, match
+	if _, err := s.handleUpdateDocument(ctx, map[string]interface{}{
+		"document_id":       float64(3),
+		"title":             "Old lease",
+		"expected_modified": "2024-03-15T10:04:05Z",
+	}); err != nil {
+		t.Errorf("Expected the update to proceed, got %v", err)
+	}
+	if patches != 1 {
+		t.Errorf("Expected one update to be sent, got %d", patches)
+	}
+}
+
+// TestGetDocuments tests that get_documents fetches the documents missing
+// from the session cache in one request, returns them in the order asked
+// with duplicates dropped, and reports the IDs Paperless does not have
+func TestGetDocuments(t *testing.T) {
+	var listed []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/documents/":
+			listed = append(listed, r.URL.Query().Get("id__in"))
+			w.Write([]byte(`{"count":2,"next":null,"results":[
+				{"id":3,"title":"Receipt"},
+				{"id":5,"title":"Invoice"}]}`))
+		case strings.HasSuffix(r.URL.Path, "/metadata/"):
+			w.Write([]byte(`{"original_size":1024}`))
+		case r.URL.Path == "/api/mail_rules/":
+			w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+		default:
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+	}))
+	defer ts.Close()
+
+	s := &Server{
+		paperlessClient: paperless.New(ts.URL, "test-token"),
+		documents:       newDocumentCache(10, time.Hour),
+		metadata:        newMetadataCaches(time.Hour),
+	}
+	getDocuments := func(ids ...float64) map[string]interface{} {
+		t.Helper()
+		args := make([]interface{}, len(ids))
+		for i, id := range ids {
+			args[i] = id
+		}
+		result, err := s.handleGetDocuments(context.Background(), map[string]interface{}{"document_ids": args})
+		if err != nil {
+			t.Fatalf("get_documents failed: %v", err)
+		}
+		return result.(map[string]interface{})
+	}
+	ids := func(result map[string]interface{}) []int {
+		var ids []int
+		for _, document := range result["documents"].([]*paperless.Document) {
+			ids = append(ids, document.ID)
+		}
+		return ids
+	}
+
+	result := getDocuments(5, 4, 3, 5)
+	if got := ids(result); !reflect.DeepEqual(got, []int{5, 3}) || result["count"] != 2 {
+		t.Errorf("Expected documents 5 and 3 in the order asked, got %v", got)
+	}
+	if got := result["not_found"]; !reflect.DeepEqual(got, []int{4}) {
+		t.Errorf("Expected document 4 not found, got %v", got)
+	}
+	if !reflect.DeepEqual(listed, []string{"5,4,3"}) {
+		t.Errorf("Expected one request for the three documents, got %v", listed)
+	}
+
+	// Cached documents are not fetched again
+	listed = nil
+	if got := ids(getDocuments(3, 5)); !reflect.DeepEqual(got, []int{3, 5}) || len(listed) != 0 {
+		t.Errorf("Expected documents 3 and 5 from the cache, got %v after requests %v", got, listed)
+	}
+
+	for _, args := range []map[string]interface{}{
+		{},
+		{"document_ids": []interface{}{}},
+		{"document_ids": []interface{}{float64(0)}},
+		{"document_ids": make([]interface{}, MaxBulkGetDocuments+1)},
+	} {
+		if _, err := s.handleGetDocuments(context.Background(), args); err == nil {
+			t.Errorf("Expected %v to fail", args)
+		}
+	}
+}
+
+// TestDocumentPageCountAndSizes tests that documents carry the page count
+// Paperless reports and the file sizes from their metadata, and that a
+// failed metadata request leaves the sizes out instead of failing
+func TestDocumentPageCountAndSizes(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/documents/1/":
+			w.Write([]byte(`{"id":1,"title":"Scan","page_count":3}`))
+		case "/api/documents/1/metadata/":
+			w.Write([]byte(`{"original_size":2048,"has_archive_version":true,"archive_size":4096}`))
+		case "/api/documents/2/":
+			w.Write([]byte(`{"id":2,"title":"Note","page_count":null}`))
+		case "/api/documents/2/metadata/":
+			w.WriteHeader(http.StatusInternalServerError)
+		case "/api/mail_rules/":
+			w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+		default:
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+	}))
+	defer ts.Close()
+
+	s := &Server{
+		paperlessClient: paperless.New(ts.URL, "test-token"),
+		documents:       newDocumentCache(0, 0),
+		metadata:        newMetadataCaches(time.Hour),
+	}
+	getDocument := func(id int) map[string]interface{} {
+		t.Helper()
+		result, err := s.handleGetDocument(context.Background(), map[string]interface{}{"document_id": float64(id)})
+		if err != nil {
+			t.Fatalf("get_document failed: %v", err)
+		}
+		data, _ := json.Marshal(result)
+		var fields map[string]interface{}
+		json.Unmarshal(data, &fields)
+		return fields
+	}
+
+	document := getDocument(1)
+	if document["page_count"] != float64(3) || document["original_size"] != float64(2048) || document["archive_size"] != float64(4096) {
+		t.Errorf("Expected the page count and file sizes, got %v", document)
+	}
+
+	document = getDocument(2)
+	for _, field := range []string{"page_count", "original_size", "archive_size"} {
+		if _, ok := document[field]; ok {
+			t.Errorf("Expected no %s, got %v", field, document[field])
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+)
+
+// ErrShuttingDown is returned for tool calls arriving while the server
+// waits for in-flight calls to finish before shutting down
+var ErrShuttingDown = errors.New("server is shutting down, retry the call shortly")
+
+// inflightCall is a tool call being executed
+type inflightCall struct {
+	Tool    string
+	Session string
+	Started time.Time
+}
+
+// inflightCalls tracks running tool calls so shutdown can wait for them.
+// The zero value is ready to use.
+type inflightCalls struct {
+	mu       sync.Mutex
+	draining bool
+	nextID   uint64
+	calls    map[uint64]inflightCall
+	idle     chan struct{} // closed when the last call ends while draining
+}
+
+// begin records the start of a call, returning the function ending it, or
+// ErrShuttingDown once draining has started
+func (f *inflightCalls) begin(tool, session string) (func(), error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.draining {
+		return nil, ErrShuttingDown
+	}
+	if f.calls == nil {
+		f.calls = make(map[uint64]inflightCall)
+	}
+	id := f.nextID
+	f.nextID++
+	f.calls[id] = inflightCall{Tool: tool, Session: session, Started: time.Now()}
+
+	return func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		delete(f.calls, id)
+		if len(f.calls) == 0 && f.idle != nil {
+			close(f.idle)
+			f.idle = nil
+		}
+	}, nil
+}
+
+// isDraining reports whether new calls are being refused
+func (f *inflightCalls) isDraining() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.draining
+}
+
+// count returns the number of running calls
+func (f *inflightCalls) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.calls)
+}
+
+// drain refuses new calls and waits up to grace for the running ones to
+// end, returning those still running afterwards, oldest first
+func (f *inflightCalls) drain(grace time.Duration) []inflightCall {
+	f.mu.Lock()
+	f.draining = true
+	if len(f.calls) == 0 {
+		f.mu.Unlock()
+		return nil
+	}
+	if f.idle == nil {
+		f.idle = make(chan struct{})
+	}
+	idle := f.idle
+	f.mu.Unlock()
+
+	timer := time.NewTimer(grace)
+	defer timer.Stop()
+	select {
+	case <-idle:
+		return nil
+	case <-timer.C:
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	running := make([]inflightCall, 0, len(f.calls))
+	for _, call := range f.calls {
+		running = append(running, call)
+	}
+	sort.Slice(running, func(i, j int) bool {
+		return running[i].Started.Before(running[j].Started)
+	})
+	return running
+}
+
+// Drain stops the server accepting tool calls and gives the running ones
+// the configured grace period to finish. Calls still running afterwards
+// are logged, since their changes to Paperless may be partly applied, and
+// Drain returns false.
+func (s *Server) Drain(ctx context.Context) bool {
+	grace := s.cfg.MCPShutdownGracePeriod
+	logging.FromContext(ctx).Info("Draining in-flight tool calls",
+		"in_flight", s.inflight.count(),
+		"grace_period", grace)
+
+	running := s.inflight.drain(grace)
+	for _, call := range running {
+		logging.FromContext(ctx).Warn("Tool call still running at shutdown, its changes may be partly applied",
+			"tool", call.Tool,
+			"session_id", call.Session,
+			"running_for", time.Since(call.Started).Round(time.Millisecond))
+	}
+	return len(running) == 0
+}
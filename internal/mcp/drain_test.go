@@ -0,0 +1,88 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/config"
+)
+
+// TestDrain tests that draining refuses new tool calls, waits for running
+// ones and reports those outliving the grace period
+func TestDrain(t *testing.T) {
+	release := make(chan struct{})
+	s := &Server{
+		cfg:      &config.Config{MCPShutdownGracePeriod: time.Second},
+		tools:    make(map[string]Tool),
+		sessions: newSessionStore(newMemorySessionStore(), time.Hour),
+	}
+	s.tools["slow"] = Tool{
+		Name:  "slow",
+		Local: true,
+		Handler: func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			<-release
+			return "done", nil
+		},
+	}
+
+	results := make(chan error, 1)
+	go func() {
+		_, err := s.ExecuteTool(context.Background(), "slow", map[string]interface{}{})
+		results <- err
+	}()
+	for s.inflight.count() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	drained := make(chan bool, 1)
+	go func() { drained <- s.Drain(context.Background()) }()
+	for !s.inflight.isDraining() {
+		time.Sleep(time.Millisecond)
+	}
+
+	_, err := s.ExecuteTool(context.Background(), "slow", map[string]interface{}{})
+	if !errors.Is(err, ErrShuttingDown) {
+		t.Errorf("Expected a call during draining to be refused, got %v", err)
+	}
+	if result := newToolErrorResult(err); result.StructuredContent.(map[string]interface{})["retryable"] != true {
+		t.Errorf("Expected a refused call to be retryable, got %+v", result.StructuredContent)
+	}
+
+	rec := httptest.NewRecorder()
+	s.handleHealth(rec, httptest.NewRequest(http.MethodGet, HealthEndpoint, nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected health to answer 503 while draining, got %d", rec.Code)
+	}
+
+	close(release)
+	if err := <-results; err != nil {
+		t.Errorf("Expected the running call to finish, got %v", err)
+	}
+	if !<-drained {
+		t.Error("Expected Drain to report all calls finished")
+	}
+}
+
+// TestDrainGracePeriod tests that calls still running after the grace
+// period are returned
+func TestDrainGracePeriod(t *testing.T) {
+	var calls inflightCalls
+	done, err := calls.begin("bulk_edit_documents", "session-1")
+	if err != nil {
+		t.Fatalf("begin failed: %v", err)
+	}
+	defer done()
+
+	running := calls.drain(10 * time.Millisecond)
+	if len(running) != 1 || running[0].Tool != "bulk_edit_documents" || running[0].Session != "session-1" {
+		t.Errorf("Expected the bulk edit to be reported as still running, got %+v", running)
+	}
+
+	if idle := (&inflightCalls{}).drain(time.Hour); idle != nil {
+		t.Errorf("Expected draining without calls to return at once, got %+v", idle)
+	}
+}
@@ -0,0 +1,113 @@
+package mcp
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// readUploadContent reads file bytes from either a file_path readable by
+// the server, within the client's roots when it advertises any, or inline
+// content_base64 arguments
+func (s *Server) readUploadContent(ctx context.Context, args map[string]interface{}) ([]byte, error) {
+	filePath, hasPath := args["file_path"].(string)
+	contentB64, hasContent := args["content_base64"].(string)
+
+	switch {
+	case hasPath && filePath != "" && hasContent && contentB64 != "":
+		return nil, fmt.Errorf("provide either file_path or content_base64, not both")
+	case hasPath && filePath != "":
+		filePath, err := s.checkFilePath(ctx, "file_path", filePath)
+		if err != nil {
+			return nil, err
+		}
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+		return data, nil
+	case hasContent && contentB64 != "":
+		data, err := base64.StdEncoding.DecodeString(contentB64)
+		if err != nil {
+			return nil, fmt.Errorf("content_base64 is not valid base64: %w", err)
+		}
+		return data, nil
+	default:
+		return nil, fmt.Errorf("file_path or content_base64 is required")
+	}
+}
+
+// documentChecksum returns the MD5 checksum Paperless records for an
+// original file
+func documentChecksum(data []byte) string {
+	sum := md5.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// findDuplicate returns the existing document whose original file has the
+// same checksum as data, or nil if there is none
+func (s *Server) findDuplicate(ctx context.Context, data []byte) (*paperless.Document, string, error) {
+	checksum := documentChecksum(data)
+	document, err := s.paperlessClient.FindDocumentByChecksum(ctx, checksum)
+	if err != nil {
+		return nil, checksum, fmt.Errorf("failed to check for duplicate document: %w", err)
+	}
+	return document, checksum, nil
+}
+
+// handleCheckDuplicateDocument handles the check_duplicate_document tool
+func (s *Server) handleCheckDuplicateDocument(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	data, err := s.readUploadContent(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	asn := -1
+	if asnVal, present := args["archive_serial_number"]; present {
+		asnFloat, ok := asnVal.(float64)
+		if !ok || asnFloat < 0 {
+			return nil, fmt.Errorf("archive_serial_number must be a non-negative integer")
+		}
+		asn = int(asnFloat)
+	}
+
+	logging.FromContext(ctx).Debug("Checking for duplicate document", "size", len(data), "asn", asn)
+
+	document, checksum, err := s.findDuplicate(ctx, data)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to check for duplicate document", "error", err)
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Info("Duplicate check completed",
+		"checksum", checksum,
+		"duplicate", document != nil)
+
+	result := map[string]interface{}{
+		"checksum":  checksum,
+		"duplicate": document != nil,
+	}
+	if document != nil {
+		result["document"] = document
+	}
+
+	// An upload with an archive serial number another document holds is
+	// rejected, so report the holder alongside the checksum match
+	if asn >= 0 {
+		holder, err := s.paperlessClient.FindDocumentByASN(ctx, asn)
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to check archive serial number", "asn", asn, "error", err)
+			return nil, fmt.Errorf("failed to check archive serial number: %w", err)
+		}
+		result["asn_in_use"] = holder != nil
+		if holder != nil {
+			result["asn_document"] = holder
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,83 @@
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// TestCheckDuplicateDocument tests that check_duplicate_document looks the
+// file up by the checksum of its content and reports the document already
+// holding an archive serial number
+func TestCheckDuplicateDocument(t *testing.T) {
+	content := []byte("%PDF-1.4 invoice")
+	checksum := documentChecksum(content)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch {
+		case r.URL.Path != "/api/documents/":
+			t.Errorf("Unexpected request %s", r.URL)
+		case query.Get("checksum__iexact") == checksum:
+			w.Write([]byte(`{"count":1,"next":null,"results":[{"id":12,"title":"Invoice 2024-03"}]}`))
+		case query.Get("archive_serial_number") == "42":
+			w.Write([]byte(`{"count":1,"next":null,"results":[{"id":30,"title":"Lease","archive_serial_number":42}]}`))
+		default:
+			w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+		}
+	}))
+	defer ts.Close()
+
+	s := &Server{paperlessClient: paperless.New(ts.URL, "test-token")}
+	check := func(content []byte, extra map[string]interface{}) map[string]interface{} {
+		t.Helper()
+		args := map[string]interface{}{"content_base64": base64.StdEncoding.EncodeToString(content)}
+		for key, value := range extra {
+			args[key] = value
+		}
+		result, err := s.handleCheckDuplicateDocument(context.Background(), args)
+		if err != nil {
+			t.Fatalf("check_duplicate_document failed: %v", err)
+		}
+		return result.(map[string]interface{})
+	}
+
+	t.Run("checksum match", func(t *testing.T) {
+		result := check(content, nil)
+		document, _ := result["document"].(*paperless.Document)
+		if result["checksum"] != checksum || result["duplicate"] != true || document == nil || document.ID != 12 {
+			t.Errorf("Expected document 12 as the duplicate, got %v", result)
+		}
+		if _, ok := result["asn_in_use"]; ok {
+			t.Errorf("Expected no archive serial number check without one, got %v", result)
+		}
+	})
+
+	t.Run("ASN match", func(t *testing.T) {
+		result := check([]byte("%PDF-1.4 lease"), map[string]interface{}{"archive_serial_number": float64(42)})
+		holder, _ := result["asn_document"].(*paperless.Document)
+		if result["duplicate"] != false || result["asn_in_use"] != true || holder == nil || holder.ID != 30 {
+			t.Errorf("Expected document 30 as the holder of ASN 42, got %v", result)
+		}
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		result := check([]byte("%PDF-1.4 new"), map[string]interface{}{"archive_serial_number": float64(43)})
+		if result["duplicate"] != false || result["asn_in_use"] != false || result["document"] != nil || result["asn_document"] != nil {
+			t.Errorf("Expected no duplicate, got %v", result)
+		}
+	})
+
+	if _, err := s.handleCheckDuplicateDocument(context.Background(), map[string]interface{}{}); err == nil {
+		t.Error("Expected a missing file to fail")
+	}
+	if _, err := s.handleCheckDuplicateDocument(context.Background(), map[string]interface{}{
+		"content_base64":        base64.StdEncoding.EncodeToString(content),
+		"archive_serial_number": float64(-1),
+	}); err == nil {
+		t.Error("Expected a negative archive serial number to fail")
+	}
+}
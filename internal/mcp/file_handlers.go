@@ -0,0 +1,309 @@
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/pdf"
+)
+
+// MaxUploadBytes bounds the size of a file sent with upload_document,
+// after decoding
+const MaxUploadBytes = 64 << 20
+
+// handleUploadDocument handles the upload_document tool
+func (s *Server) handleUploadDocument(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	// Extract and validate the file
+	filename, _ := args["filename"].(string)
+	filename = strings.TrimSpace(filename)
+	if filename == "" {
+		return nil, fmt.Errorf("filename parameter is required and must be a non-empty string")
+	}
+	if filepath.Base(filename) != filename {
+		return nil, fmt.Errorf("filename must not contain a directory")
+	}
+	encoded, ok := args["content_base64"].(string)
+	if !ok || encoded == "" {
+		return nil, fmt.Errorf("content_base64 parameter is required and must be a non-empty string")
+	}
+	if base64.StdEncoding.DecodedLen(len(encoded)) > MaxUploadBytes {
+		return nil, fmt.Errorf("file exceeds the upload limit of %d bytes", MaxUploadBytes)
+	}
+	content, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("content_base64 is not valid base64: %w", err)
+	}
+
+	// Paperless cannot consume a PDF that needs a password to open, so
+	// decrypt it here; the password never leaves this process
+	password, hasPassword := args["pdf_password"].(string)
+	decrypted := false
+	if pdf.IsEncrypted(content) {
+		plain, err := pdf.Decrypt(content, password)
+		switch {
+		case err == nil:
+			// Without a password the PDF only restricts editing or
+			// printing, which Paperless ignores, so it is sent as is
+			if hasPassword {
+				content, decrypted = plain, true
+			}
+		case errors.Is(err, pdf.ErrWrongPassword) && !hasPassword:
+			return nil, fmt.Errorf("the PDF is password protected and Paperless cannot consume it; pass its password as pdf_password to decrypt it before upload")
+		case errors.Is(err, pdf.ErrWrongPassword):
+			return nil, fmt.Errorf("pdf_password does not open the PDF")
+		case hasPassword:
+			return nil, fmt.Errorf("failed to decrypt PDF: %w", err)
+		}
+	}
+
+	// Extract optional overrides
+	opts := &paperless.UploadOptions{}
+	if title, ok := args["title"].(string); ok {
+		opts.Title = title
+	}
+	if created, ok := args["created"].(string); ok {
+		opts.Created = created
+	}
+	for _, field := range []struct {
+		name string
+		id   **int
+	}{
+		{"correspondent", &opts.Correspondent},
+		{"document_type", &opts.DocumentType},
+		{"storage_path", &opts.StoragePath},
+		{"owner", &opts.Owner},
+	} {
+		if value, present := args[field.name]; present {
+			idFloat, ok := value.(float64)
+			if !ok || idFloat < 1 {
+				return nil, fmt.Errorf("%s must be a positive integer", field.name)
+			}
+			id := int(idFloat)
+			*field.id = &id
+		}
+	}
+	if tagsInterface, present := args["tags"]; present {
+		tags, ok := tagsInterface.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("tags must be an array of tag IDs")
+		}
+		for _, tagInterface := range tags {
+			tagFloat, ok := tagInterface.(float64)
+			if !ok || tagFloat < 1 {
+				return nil, fmt.Errorf("tags must contain only positive integers")
+			}
+			opts.Tags = append(opts.Tags, int(tagFloat))
+		}
+	}
+	if asnVal, present := args["archive_serial_number"]; present {
+		asnFloat, ok := asnVal.(float64)
+		if !ok || asnFloat < 0 {
+			return nil, fmt.Errorf("archive_serial_number must be a non-negative integer")
+		}
+		asn := int(asnFloat)
+		opts.ArchiveSerialNumber = &asn
+	}
+	if raw, present := args["custom_fields"]; present {
+		values, _, err := s.customFieldValues(ctx, raw)
+		if err != nil {
+			return nil, err
+		}
+		opts.CustomFields = values
+	}
+	force, _ := args["force"].(bool)
+
+	logging.FromContext(ctx).Debug("Uploading document",
+		"filename", filename,
+		"size", len(content),
+		"decrypted", decrypted)
+
+	// Call Paperless API, at most once per idempotency key
+	return s.withIdempotency(ctx, "upload_document", args, func() (interface{}, error) {
+		// The checks run only for a new upload, so a repeated call is
+		// answered with its task rather than with the document it created
+		if asn := opts.ArchiveSerialNumber; asn != nil {
+			if err := s.checkASNAvailable(ctx, *asn, 0); err != nil {
+				return nil, err
+			}
+		}
+
+		// Paperless only finds a duplicate once consumption fails, so
+		// look for one first; a failed check leaves it to Paperless
+		if !force {
+			duplicate, checksum, err := s.findDuplicate(ctx, content)
+			switch {
+			case err != nil:
+				logging.FromContext(ctx).Warn("Failed to check upload for duplicates",
+					"filename", filename,
+					"error", err)
+			case duplicate != nil:
+				// Answer with the existing document instead of creating
+				// a second copy
+				logging.FromContext(ctx).Info("Upload is a duplicate, not uploading",
+					"filename", filename,
+					"checksum", checksum,
+					"document_id", duplicate.ID)
+				return map[string]interface{}{
+					"duplicate": true,
+					"checksum":  checksum,
+					"document":  duplicate,
+					"message":   fmt.Sprintf("this file is already in Paperless as document %d (%q) and was not uploaded; pass force: true to upload it anyway", duplicate.ID, duplicate.Title),
+				}, nil
+			}
+		}
+
+		taskID, err := s.paperlessClient.UploadDocument(ctx, filename, content, opts)
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to upload document",
+				"filename", filename,
+				"error", err)
+			return nil, fmt.Errorf("failed to upload document: %w", err)
+		}
+
+		logging.FromContext(ctx).Info("Document uploaded for consumption",
+			"filename", filename,
+			"task_id", taskID)
+
+		return map[string]interface{}{
+			"task_id":   taskID,
+			"filename":  filename,
+			"size":      len(content),
+			"decrypted": decrypted,
+			"status":    "queued for consumption; the document gets an ID once Paperless has consumed it, and diagnose_failed_task explains a failure",
+		}, nil
+	})
+}
+
+// handleDownloadDocumentOriginal handles the download_document_original tool
+func (s *Server) handleDownloadDocumentOriginal(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	return s.downloadDocumentFile(ctx, args, true)
+}
+
+// handleDownloadDocumentArchive handles the download_document_archive tool
+func (s *Server) handleDownloadDocumentArchive(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	return s.downloadDocumentFile(ctx, args, false)
+}
+
+// downloadDocumentFile returns the original or archived file of a
+// document as an embedded resource, described by its filename, MIME type,
+// size and the version Paperless served
+func (s *Server) downloadDocumentFile(ctx context.Context, args map[string]interface{}, original bool) (interface{}, error) {
+	// Extract and validate document_id
+	documentIDFloat, ok := args["document_id"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("document_id parameter is required and must be an integer")
+	}
+	documentID := int(documentIDFloat)
+	if documentID < 1 {
+		return nil, fmt.Errorf("document_id must be a positive integer")
+	}
+
+	// Paperless serves the original when a document has no archived
+	// version, so ask which one it has rather than assume
+	served := original
+	if !original {
+		metadata, err := s.paperlessClient.GetDocumentMetadata(ctx, documentID)
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to get document metadata",
+				"document_id", documentID,
+				"error", err)
+			return nil, fmt.Errorf("failed to get document metadata: %w", err)
+		}
+		served = !metadata.HasArchiveVersion
+	}
+	version := "archived"
+	if served {
+		version = "original"
+	}
+
+	logging.FromContext(ctx).Debug("Downloading document file",
+		"document_id", documentID,
+		"version", version)
+
+	file, err := s.paperlessClient.DownloadDocument(ctx, documentID, original)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to download document file",
+			"document_id", documentID,
+			"version", version,
+			"error", err)
+		return nil, fmt.Errorf("failed to download %s document: %w", version, err)
+	}
+
+	logging.FromContext(ctx).Info("Document file downloaded",
+		"document_id", documentID,
+		"version", version,
+		"filename", file.Filename,
+		"size", len(file.Content))
+
+	result := map[string]interface{}{
+		"document_id": documentID,
+		"version":     version,
+		"filename":    file.Filename,
+		"mime_type":   file.ContentType,
+		"size":        len(file.Content),
+	}
+	if served != original {
+		result["note"] = "the document has no archived version, so its original file was returned"
+	}
+	return withResources(result, documentResource(documentID, served, file)), nil
+}
+
+// handleGetDocumentMetadata handles the get_document_metadata tool
+func (s *Server) handleGetDocumentMetadata(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	// Extract and validate document_id
+	documentIDFloat, ok := args["document_id"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("document_id parameter is required and must be an integer")
+	}
+	documentID := int(documentIDFloat)
+	if documentID < 1 {
+		return nil, fmt.Errorf("document_id must be a positive integer")
+	}
+
+	logging.FromContext(ctx).Debug("Getting document metadata", "document_id", documentID)
+
+	metadata, err := s.paperlessClient.GetDocumentMetadata(ctx, documentID)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to get document metadata",
+			"document_id", documentID,
+			"error", err)
+		return nil, fmt.Errorf("failed to get document metadata: %w", err)
+	}
+
+	// The page count is kept on the document rather than in its metadata
+	var pageCount *int
+	if document, err := s.getDocument(ctx, documentID); err != nil {
+		logging.FromContext(ctx).Warn("Failed to get document page count",
+			"document_id", documentID,
+			"error", err)
+	} else {
+		pageCount = document.PageCount
+	}
+
+	logging.FromContext(ctx).Info("Document metadata retrieved",
+		"document_id", documentID,
+		"mime_type", metadata.OriginalMimeType)
+
+	return map[string]interface{}{
+		"document_id":            documentID,
+		"page_count":             pageCount,
+		"original_filename":      metadata.OriginalFilename,
+		"original_mime_type":     metadata.OriginalMimeType,
+		"original_size":          metadata.OriginalSize,
+		"original_checksum":      metadata.OriginalChecksum,
+		"media_filename":         metadata.MediaFilename,
+		"has_archive_version":    metadata.HasArchiveVersion,
+		"archive_size":           metadata.ArchiveSize,
+		"archive_checksum":       metadata.ArchiveChecksum,
+		"archive_media_filename": metadata.ArchiveMediaFilename,
+		"lang":                   metadata.Lang,
+		"original_metadata":      metadata.OriginalMetadata,
+		"archive_metadata":       metadata.ArchiveMetadata,
+	}, nil
+}
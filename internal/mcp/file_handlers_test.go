@@ -0,0 +1,407 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/rc4"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TestUploadDocument tests that the file and overrides are sent as a
+// multipart upload and the consumption task is returned
+func TestUploadDocument(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && r.URL.Query().Get("checksum__iexact") != "" {
+			w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+			return
+		}
+		if r.Method != http.MethodPost || r.URL.Path != "/api/documents/post_document/" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL)
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("Failed to parse upload: %v", err)
+		}
+		file, header, err := r.FormFile("document")
+		if err != nil {
+			t.Fatalf("Expected a document file: %v", err)
+		}
+		content, _ := io.ReadAll(file)
+		if header.Filename != "invoice.pdf" || string(content) != "%PDF-1.4" {
+			t.Errorf("Unexpected file %s: %q", header.Filename, content)
+		}
+		if got := r.MultipartForm.Value["tags"]; !reflect.DeepEqual(got, []string{"3", "4"}) {
+			t.Errorf("Expected tags 3 and 4, got %v", got)
+		}
+		if r.FormValue("title") != "Invoice" || r.FormValue("correspondent") != "2" {
+			t.Errorf("Unexpected overrides: %v", r.MultipartForm.Value)
+		}
+		w.Write([]byte(`"0f8b1a52-7c1e-4a9e-b0a4-3c1f0e5d2a11"`))
+	}))
+	defer ts.Close()
+
+	s := &Server{paperlessClient: paperless.New(ts.URL, "test-token")}
+
+	result, err := s.handleUploadDocument(context.Background(), map[string]interface{}{
+		"filename":       "invoice.pdf",
+		"content_base64": base64.StdEncoding.EncodeToString([]byte("%PDF-1.4")),
+		"title":          "Invoice",
+		"correspondent":  float64(2),
+		"tags":           []interface{}{float64(3), float64(4)},
+	})
+	if err != nil {
+		t.Fatalf("upload_document failed: %v", err)
+	}
+	if taskID := result.(map[string]interface{})["task_id"]; taskID != "0f8b1a52-7c1e-4a9e-b0a4-3c1f0e5d2a11" {
+		t.Errorf("Unexpected task ID %v", taskID)
+	}
+
+	for _, args := range []map[string]interface{}{
+		{"content_base64": "JVBERg=="},
+		{"filename": "../invoice.pdf", "content_base64": "JVBERg=="},
+		{"filename": "invoice.pdf", "content_base64": "not base64!"},
+		{"filename": "invoice.pdf", "content_base64": "JVBERg==", "tags": []interface{}{"inbox"}},
+	} {
+		if _, err := s.handleUploadDocument(context.Background(), args); err == nil {
+			t.Errorf("Expected %v to be rejected", args)
+		}
+	}
+}
+
+// TestUploadDocumentChecksDuplicates tests that a file already in Paperless
+// is answered with the existing document instead of being uploaded unless
+// forced, that a taken archive serial number is refused, and that the ASN,
+// owner and custom fields are sent with the upload
+func TestUploadDocumentChecksDuplicates(t *testing.T) {
+	var uploads []map[string][]string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/custom_fields/":
+			w.Write([]byte(`{"count":1,"next":null,"results":[{"id":4,"name":"Amount","data_type":"monetary"}]}`))
+		case r.URL.Query().Get("checksum__iexact") != "":
+			w.Write([]byte(`{"count":1,"next":null,"results":[{"id":12,"title":"March invoice"}]}`))
+		case r.URL.Query().Get("archive_serial_number") == "99":
+			w.Write([]byte(`{"count":1,"next":null,"results":[{"id":3,"title":"Lease"}]}`))
+		case r.URL.Query().Get("archive_serial_number") != "":
+			w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/documents/post_document/":
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				t.Fatalf("Failed to parse upload: %v", err)
+			}
+			uploads = append(uploads, r.MultipartForm.Value)
+			w.Write([]byte(`"0f8b1a52-7c1e-4a9e-b0a4-3c1f0e5d2a11"`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL)
+		}
+	}))
+	defer ts.Close()
+
+	s := &Server{paperlessClient: paperless.New(ts.URL, "test-token")}
+	uploadResult := func(extra map[string]interface{}) (interface{}, error) {
+		args := map[string]interface{}{
+			"filename":       "invoice.pdf",
+			"content_base64": base64.StdEncoding.EncodeToString([]byte("%PDF-1.4")),
+		}
+		for key, value := range extra {
+			args[key] = value
+		}
+		return s.handleUploadDocument(context.Background(), args)
+	}
+	upload := func(extra map[string]interface{}) error {
+		_, err := uploadResult(extra)
+		return err
+	}
+
+	result, err := uploadResult(nil)
+	if err != nil {
+		t.Fatalf("Expected the duplicate answered with the existing document, got %v", err)
+	}
+	duplicate := result.(map[string]interface{})
+	if existing, _ := duplicate["document"].(*paperless.Document); duplicate["duplicate"] != true || existing == nil || existing.ID != 12 {
+		t.Errorf("Expected document 12 as the duplicate, got %v", duplicate)
+	}
+	if _, ok := duplicate["task_id"]; ok || !strings.Contains(fmt.Sprint(duplicate["message"]), "force") {
+		t.Errorf("Expected no consumption task and a hint to force the upload, got %v", duplicate)
+	}
+	if err := upload(map[string]interface{}{"force": true, "archive_serial_number": float64(99)}); err == nil ||
+		!strings.Contains(err.Error(), "already assigned to document 3") {
+		t.Errorf("Expected the taken ASN refused, got %v", err)
+	}
+	if len(uploads) != 0 {
+		t.Fatalf("Expected nothing uploaded, got %v", uploads)
+	}
+
+	err = upload(map[string]interface{}{
+		"force":                 true,
+		"archive_serial_number": float64(100),
+		"owner":                 float64(2),
+		"custom_fields":         []interface{}{map[string]interface{}{"field": "Amount", "value": "EUR12.50"}},
+	})
+	if err != nil {
+		t.Fatalf("upload_document failed: %v", err)
+	}
+	if len(uploads) != 1 {
+		t.Fatalf("Expected one upload, got %v", uploads)
+	}
+	want := map[string][]string{
+		"archive_serial_number": {"100"},
+		"owner":                 {"2"},
+		"custom_fields":         {`{"4":"EUR12.50"}`},
+	}
+	for key, value := range want {
+		if got := uploads[0][key]; !reflect.DeepEqual(got, value) {
+			t.Errorf("Expected %s %v, got %v", key, value, got)
+		}
+	}
+
+	if err := upload(map[string]interface{}{"archive_serial_number": float64(-1)}); err == nil {
+		t.Error("Expected a negative archive_serial_number to be rejected")
+	}
+}
+
+// encryptedStatement returns a one page PDF encrypted with the 40-bit RC4
+// standard security handler (revision 2) and the user password user
+func encryptedStatement(user string) []byte {
+	padding := []byte("\x28\xBF\x4E\x5E\x4E\x75\x8A\x41\x64\x00\x4E\x56\xFF\xFA\x01\x08" +
+		"\x2E\x2E\x00\xB6\xD0\x68\x3E\x80\x2F\x0C\xA9\xFE\x64\x53\x69\x7A")
+	pad := func(password string) []byte {
+		return append([]byte(password), padding[:32-len(password)]...)
+	}
+	crypt := func(key, data []byte) []byte {
+		c, _ := rc4.NewCipher(key)
+		out := make([]byte, len(data))
+		c.XORKeyStream(out, data)
+		return out
+	}
+	id := []byte("statement-id-001")
+
+	ownerKey := md5.Sum(pad("owner"))
+	o := crypt(ownerKey[:5], pad(user))
+	h := md5.New()
+	h.Write(pad(user))
+	h.Write(o)
+	binary.Write(h, binary.LittleEndian, int32(-4))
+	h.Write(id)
+	fileKey := h.Sum(nil)[:5]
+	u := crypt(fileKey, padding)
+	objectKey := func(num int) []byte {
+		sum := md5.Sum(append(append([]byte{}, fileKey...), byte(num), 0, 0, 0, 0))
+		return sum[:10]
+	}
+
+	content := crypt(objectKey(3), []byte("BT (Closing balance 1,234.56) Tj ET"))
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n")
+	out.WriteString("1 0 obj\n<< /Type /Catalog /Pages 2 0 R >>\nendobj\n")
+	out.WriteString("2 0 obj\n<< /Type /Pages /Kids [] /Count 0 >>\nendobj\n")
+	fmt.Fprintf(&out, "3 0 obj\n<< /Length %d >>\nstream\n%s\nendstream\nendobj\n", len(content), content)
+	fmt.Fprintf(&out, "4 0 obj\n<< /Filter /Standard /V 1 /R 2 /P -4 /O <%x> /U <%x> >>\nendobj\n", o, u)
+	fmt.Fprintf(&out, "trailer\n<< /Size 5 /Root 1 0 R /Encrypt 4 0 R /ID [<%x> <%x>] >>\n%%%%EOF\n", id, id)
+	return out.Bytes()
+}
+
+// TestUploadDocumentDecryptsPDF tests that a password-protected PDF is
+// decrypted with pdf_password before upload and refused without it
+func TestUploadDocumentDecryptsPDF(t *testing.T) {
+	var uploaded []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`{"count":0,"next":null,"results":[]}`))
+			return
+		}
+		file, _, err := r.FormFile("document")
+		if err != nil {
+			t.Fatalf("Expected a document file: %v", err)
+		}
+		uploaded, _ = io.ReadAll(file)
+		w.Write([]byte(`"7d3c9e2a-1b4f-4c6d-8e0a-5f2b1c3d4e5f"`))
+	}))
+	defer ts.Close()
+
+	s := &Server{paperlessClient: paperless.New(ts.URL, "test-token")}
+	encoded := base64.StdEncoding.EncodeToString(encryptedStatement("1234"))
+
+	for _, tc := range []struct {
+		args    map[string]interface{}
+		wantErr string
+	}{
+		{map[string]interface{}{}, "pass its password as pdf_password"},
+		{map[string]interface{}{"pdf_password": "4321"}, "pdf_password does not open the PDF"},
+	} {
+		tc.args["filename"] = "statement.pdf"
+		tc.args["content_base64"] = encoded
+		if _, err := s.handleUploadDocument(context.Background(), tc.args); err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("Expected an error containing %q, got %v", tc.wantErr, err)
+		}
+	}
+	if uploaded != nil {
+		t.Fatal("Expected nothing to be uploaded without the right password")
+	}
+
+	result, err := s.handleUploadDocument(context.Background(), map[string]interface{}{
+		"filename":       "statement.pdf",
+		"content_base64": encoded,
+		"pdf_password":   "1234",
+	})
+	if err != nil {
+		t.Fatalf("upload_document failed: %v", err)
+	}
+	if decrypted := result.(map[string]interface{})["decrypted"]; decrypted != true {
+		t.Errorf("Expected the upload to report decryption, got %v", decrypted)
+	}
+	if !bytes.Contains(uploaded, []byte("(Closing balance 1,234.56) Tj")) || bytes.Contains(uploaded, []byte("/Encrypt")) {
+		t.Errorf("Expected the decrypted PDF to be uploaded, got %q", uploaded)
+	}
+}
+
+// TestDownloadDocumentOriginal tests that the original file is attached as
+// an embedded resource described by its filename and MIME type
+func TestDownloadDocumentOriginal(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/documents/404/download/" {
+			http.Error(w, `{"detail":"No Document matches the given query."}`, http.StatusNotFound)
+			return
+		}
+		if r.URL.Path != "/api/documents/7/download/" || r.URL.Query().Get("original") != "true" {
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Header().Set("Content-Disposition", `attachment; filename="scan.png"`)
+		w.Write([]byte("png-bytes"))
+	}))
+	defer ts.Close()
+
+	s := &Server{paperlessClient: paperless.New(ts.URL, "test-token")}
+
+	result, err := s.handleDownloadDocumentOriginal(context.Background(), map[string]interface{}{"document_id": float64(7)})
+	if err != nil {
+		t.Fatalf("download_document_original failed: %v", err)
+	}
+	wrapped, ok := result.(*contentResult)
+	if !ok || len(wrapped.Content) != 1 {
+		t.Fatalf("Expected one content block, got %#v", result)
+	}
+	file := wrapped.Result.(map[string]interface{})
+	if file["version"] != "original" || file["filename"] != "scan.png" || file["mime_type"] != "image/png" || file["size"] != 9 {
+		t.Errorf("Unexpected file: %v", file)
+	}
+	if _, ok := file["content_base64"]; ok {
+		t.Error("Expected the content only in the embedded resource")
+	}
+	resource := wrapped.Content[0].(mcp.EmbeddedResource).Resource.(mcp.BlobResourceContents)
+	if resource.URI != "paperless://documents/7/original" || resource.MIMEType != "image/png" ||
+		resource.Blob != base64.StdEncoding.EncodeToString([]byte("png-bytes")) {
+		t.Errorf("Unexpected resource %+v", resource)
+	}
+
+	_, err = s.handleDownloadDocumentOriginal(context.Background(), map[string]interface{}{"document_id": float64(404)})
+	if err == nil || !strings.Contains(err.Error(), "failed to download original document") {
+		t.Errorf("Expected a missing document to fail, got %v", err)
+	}
+}
+
+// TestDownloadDocumentArchive tests that the archived version is requested
+// and reported as the version Paperless has, which is the original for a
+// document it never archived
+func TestDownloadDocumentArchive(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/documents/7/metadata/":
+			w.Write([]byte(`{"has_archive_version":true}`))
+		case "/api/documents/8/metadata/":
+			w.Write([]byte(`{"has_archive_version":false}`))
+		case "/api/documents/7/download/", "/api/documents/8/download/":
+			if r.URL.Query().Get("original") == "true" {
+				t.Errorf("Unexpected request %s", r.URL)
+			}
+			w.Header().Set("Content-Type", "application/pdf")
+			w.Header().Set("Content-Disposition", `attachment; filename="2026-03-01 Invoice.pdf"`)
+			w.Write([]byte("%PDF-1.7 archived"))
+		default:
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+	}))
+	defer ts.Close()
+
+	s := &Server{paperlessClient: paperless.New(ts.URL, "test-token")}
+
+	for id, version := range map[int]string{7: "archived", 8: "original"} {
+		result, err := s.handleDownloadDocumentArchive(context.Background(), map[string]interface{}{"document_id": float64(id)})
+		if err != nil {
+			t.Fatalf("download_document_archive failed: %v", err)
+		}
+		wrapped := result.(*contentResult)
+		file := wrapped.Result.(map[string]interface{})
+		if file["version"] != version || file["filename"] != "2026-03-01 Invoice.pdf" || file["mime_type"] != "application/pdf" || file["size"] != 17 {
+			t.Errorf("Unexpected file for document %d: %v", id, file)
+		}
+		if (file["note"] != nil) != (version == "original") {
+			t.Errorf("Expected a note only when the original was served, got %v", file["note"])
+		}
+		uri := wrapped.Content[0].(mcp.EmbeddedResource).Resource.(mcp.BlobResourceContents).URI
+		if want := fmt.Sprintf("paperless://documents/%d/%s", id, map[string]string{"archived": "archive", "original": "original"}[version]); uri != want {
+			t.Errorf("Expected resource %s, got %s", want, uri)
+		}
+	}
+
+	if _, err := s.handleDownloadDocumentArchive(context.Background(), map[string]interface{}{"document_id": float64(0)}); err == nil {
+		t.Error("Expected a non-positive document_id to be rejected")
+	}
+}
+
+// TestGetDocumentMetadata tests that the file metadata is returned with the
+// document's page count
+func TestGetDocumentMetadata(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/documents/7/metadata/":
+			w.Write([]byte(`{"original_checksum": "9e107d9d372bb6826bd81d3542a419d6", "original_size": 52311,
+				"original_mime_type": "application/pdf", "media_filename": "0000007.pdf", "has_archive_version": true,
+				"original_metadata": [{"namespace": "http://ns.adobe.com/pdf/1.3/", "prefix": "pdf", "key": "Producer", "value": "Scanner"}],
+				"archive_checksum": "e4d909c290d0fb1ca068ffaddf22cbd0", "archive_size": 61440, "lang": "en"}`))
+		case "/api/documents/7/":
+			w.Write([]byte(`{"id": 7, "title": "Invoice", "page_count": 3}`))
+		case "/api/documents/404/metadata/":
+			http.Error(w, `{"detail":"Not found."}`, http.StatusNotFound)
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer ts.Close()
+
+	s := &Server{
+		paperlessClient: paperless.New(ts.URL, "test-token"),
+		documents:       newDocumentCache(10, time.Minute),
+	}
+
+	result, err := s.handleGetDocumentMetadata(context.Background(), map[string]interface{}{"document_id": float64(7)})
+	if err != nil {
+		t.Fatalf("get_document_metadata failed: %v", err)
+	}
+	metadata := result.(map[string]interface{})
+	if metadata["original_checksum"] != "9e107d9d372bb6826bd81d3542a419d6" || metadata["original_size"] != int64(52311) || metadata["has_archive_version"] != true {
+		t.Errorf("Unexpected metadata %v", metadata)
+	}
+	if pageCount := metadata["page_count"].(*int); pageCount == nil || *pageCount != 3 {
+		t.Errorf("Expected 3 pages, got %v", metadata["page_count"])
+	}
+	if embedded := metadata["original_metadata"].([]paperless.FileMetadata); len(embedded) != 1 || embedded[0].Value != "Scanner" {
+		t.Errorf("Unexpected embedded metadata %v", embedded)
+	}
+
+	if _, err := s.handleGetDocumentMetadata(context.Background(), map[string]interface{}{"document_id": float64(404)}); err == nil {
+		t.Error("Expected a missing document to fail")
+	}
+}
@@ -3,7 +3,8 @@ package mcp
 import (
 	"context"
 	"fmt"
-	"log/slog"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
 )
 
 // Tool execution error messages
@@ -14,32 +15,57 @@ const (
 
 // ExecuteTool executes a registered tool by name
 func (s *Server) ExecuteTool(ctx context.Context, toolName string, args map[string]interface{}) (interface{}, error) {
+	// Everything logged while serving the call, including by the Paperless
+	// client, is attributed to its session, tool and server instance
+	ctx = logging.WithLogger(ctx, logging.FromContext(ctx).With(
+		"session_id", sessionKey(ctx),
+		"tool", toolName,
+		"instance", s.instance))
+
 	// Check if tool exists
 	tool, exists := s.tools[toolName]
 	if !exists {
-		slog.Warn("Tool not found",
-			"tool", toolName,
+		logging.FromContext(ctx).Warn("Tool not found",
 			"available_tools", s.getToolNames())
 		return nil, fmt.Errorf(ErrToolNotFound, toolName)
 	}
 
+	// Calls arriving while the server drains before shutting down are
+	// refused, so they are retried rather than cut off part way
+	done, err := s.inflight.begin(toolName, sessionKey(ctx))
+	if err != nil {
+		logging.FromContext(ctx).Debug("Tool call refused", "error", err)
+		return nil, fmt.Errorf(ErrToolExecFailed, err)
+	}
+	defer done()
+
+	// Tools that need Paperless fail fast while it is unreachable
+	if err := s.checkUpstream(tool); err != nil {
+		logging.FromContext(ctx).Debug("Tool unavailable", "error", err)
+		return nil, fmt.Errorf(ErrToolExecFailed, err)
+	}
+
+	// Tools the Paperless token cannot use fail before reaching Paperless
+	if err := s.checkScope(tool); err != nil {
+		logging.FromContext(ctx).Debug("Tool not permitted", "error", err)
+		return nil, fmt.Errorf(ErrToolExecFailed, err)
+	}
+
+	// Fill in arguments from the session's sticky defaults
+	args = s.applySessionDefaults(ctx, tool, args)
+
 	// Log execution start
-	slog.Debug("Executing tool",
-		"tool", toolName,
-		"args_count", len(args))
+	logging.FromContext(ctx).Debug("Executing tool", "args_count", len(args))
 
 	// Execute the tool handler
 	result, err := tool.Handler(ctx, args)
 	if err != nil {
-		slog.Error("Tool execution failed",
-			"tool", toolName,
-			"error", err)
+		logging.FromContext(ctx).Error("Tool execution failed", "error", err)
 		return nil, fmt.Errorf(ErrToolExecFailed, err)
 	}
 
 	// Log successful execution
-	slog.Debug("Tool executed successfully",
-		"tool", toolName)
+	logging.FromContext(ctx).Debug("Tool executed successfully")
 
 	return result, nil
 }
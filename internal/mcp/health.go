@@ -0,0 +1,191 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// Health probe timing
+const (
+	// HealthProbeTimeout bounds a single probe of Paperless
+	HealthProbeTimeout = 10 * time.Second
+
+	// StartupRetryInitialDelay is the first delay between startup probes;
+	// it doubles after each failure up to StartupRetryMaxDelay
+	StartupRetryInitialDelay = 1 * time.Second
+	StartupRetryMaxDelay     = 15 * time.Second
+)
+
+// ErrUpstreamUnavailable is returned by tools that need Paperless while it
+// cannot be reached
+var ErrUpstreamUnavailable = errors.New("paperless is unavailable")
+
+// upstreamHealth tracks whether Paperless answered the last probe. The zero
+// value is healthy, so tools run until a probe says otherwise.
+type upstreamHealth struct {
+	mu        sync.RWMutex
+	degraded  bool
+	lastError error
+	checkedAt time.Time
+	since     time.Time // when the current state began
+}
+
+// healthState is a snapshot of upstreamHealth
+type healthState struct {
+	Degraded  bool
+	LastError error
+	CheckedAt time.Time
+	Since     time.Time
+}
+
+// record stores the outcome of a probe and reports whether the state changed
+func (h *upstreamHealth) record(err error, now time.Time) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	degraded := err != nil
+	changed := degraded != h.degraded || h.since.IsZero()
+	if changed {
+		h.since = now
+	}
+	h.degraded = degraded
+	h.lastError = err
+	h.checkedAt = now
+	return changed
+}
+
+// state returns the current health
+func (h *upstreamHealth) state() healthState {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return healthState{
+		Degraded:  h.degraded,
+		LastError: h.lastError,
+		CheckedAt: h.checkedAt,
+		Since:     h.since,
+	}
+}
+
+// isUnreachable reports whether a probe error means Paperless itself is
+// down, as opposed to answering with an error such as a rejected token
+func isUnreachable(err error) bool {
+	var apiErr *paperless.Error
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500
+	}
+	return err != nil
+}
+
+// probeUpstream checks that Paperless answers and records the outcome
+func (s *Server) probeUpstream(ctx context.Context) {
+	probeCtx, cancel := context.WithTimeout(ctx, HealthProbeTimeout)
+	defer cancel()
+
+	_, err := s.paperlessClient.GetServerVersion(probeCtx)
+	if ctx.Err() != nil {
+		return
+	}
+	if err != nil && !isUnreachable(err) {
+		// Paperless answered; the error is one tools will report themselves
+		logging.FromContext(ctx).Warn("Paperless health probe returned an error", "error", err)
+		err = nil
+	}
+
+	if err == nil {
+		s.resolvePaperlessURL(probeCtx)
+		s.detectTokenScope(ctx)
+	}
+
+	if !s.health.record(err, time.Now()) {
+		return
+	}
+	if err != nil {
+		logging.FromContext(ctx).Warn("Paperless is unreachable, tools that need it are unavailable until it recovers",
+			"error", err,
+			"probe_interval", s.cfg.MCPHealthProbeInterval)
+	} else {
+		logging.FromContext(ctx).Info("Paperless is reachable")
+	}
+}
+
+// StartHealthProbe probes Paperless before returning, retrying with
+// backoff for the configured startup wait, so the server starts in a
+// degraded state only when Paperless is still down after it. It then
+// re-probes every configured interval until ctx is cancelled.
+func (s *Server) StartHealthProbe(ctx context.Context) {
+	s.waitForUpstream(ctx, s.cfg.MCPStartupWait)
+	if s.cfg.MCPHealthProbeInterval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(s.cfg.MCPHealthProbeInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.probeUpstream(ctx)
+			}
+		}
+	}()
+}
+
+// waitForUpstream probes Paperless until it answers or wait has passed,
+// for stacks where the MCP server starts before Paperless has booted. A
+// zero wait probes once.
+func (s *Server) waitForUpstream(ctx context.Context, wait time.Duration) {
+	deadline := time.Now().Add(wait)
+	delay := StartupRetryInitialDelay
+	for {
+		s.probeUpstream(ctx)
+		if !s.health.state().Degraded || ctx.Err() != nil {
+			return
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			if wait > 0 {
+				logging.FromContext(ctx).Warn("Paperless did not become reachable during startup, starting degraded", "waited", wait)
+			}
+			return
+		}
+		if delay > remaining {
+			delay = remaining
+		}
+		logging.FromContext(ctx).Info("Waiting for Paperless to become reachable", "retry_in", delay, "remaining", remaining)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		delay = min(delay*2, StartupRetryMaxDelay)
+	}
+}
+
+// checkUpstream returns ErrUpstreamUnavailable, with the reason, when tool
+// needs Paperless and it is down
+func (s *Server) checkUpstream(tool Tool) error {
+	if tool.Local {
+		return nil
+	}
+	state := s.health.state()
+	if !state.Degraded {
+		return nil
+	}
+	return fmt.Errorf("%w since %s (%v), retrying every %s",
+		ErrUpstreamUnavailable,
+		state.Since.UTC().Format(time.RFC3339),
+		state.LastError,
+		s.cfg.MCPHealthProbeInterval)
+}
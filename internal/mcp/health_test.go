@@ -0,0 +1,175 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/config"
+)
+
+// TestDegradedTools tests that tools needing Paperless fail with
+// ErrUpstreamUnavailable while it is down, local tools keep working, and a
+// successful probe restores them
+func TestDegradedTools(t *testing.T) {
+	var status atomic.Int32
+	status.Store(http.StatusBadGateway)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(int(status.Load()))
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	s, err := New(&config.Config{
+		PaperlessURL:           ts.URL,
+		PaperlessToken:         "test-token",
+		MCPTransport:           "stdio",
+		MCPHealthProbeInterval: time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	ctx := context.Background()
+
+	s.probeUpstream(ctx)
+	if _, err := s.ExecuteTool(ctx, "get_document", map[string]interface{}{"id": float64(1)}); !errors.Is(err, ErrUpstreamUnavailable) {
+		t.Errorf("Expected ErrUpstreamUnavailable, got %v", err)
+	}
+	if _, err := s.ExecuteTool(ctx, "ping", map[string]interface{}{}); err != nil {
+		t.Errorf("Expected local tools to keep working, got %v", err)
+	}
+	result, err := s.ExecuteTool(ctx, "server_info", map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if info := result.(map[string]interface{}); info["status"] != "degraded" {
+		t.Errorf("Expected server_info to report degraded, got %v", info["status"])
+	}
+
+	// A Paperless that answers, even with an error, is not down
+	status.Store(http.StatusUnauthorized)
+	s.probeUpstream(ctx)
+	if state := s.health.state(); state.Degraded {
+		t.Errorf("Expected an authentication error not to degrade the server, got %v", state.LastError)
+	}
+	if _, err := s.ExecuteTool(ctx, "get_document", map[string]interface{}{"id": float64(1)}); errors.Is(err, ErrUpstreamUnavailable) {
+		t.Errorf("Expected tools to be available again, got %v", err)
+	}
+}
+
+// TestWaitForUpstream tests that startup keeps probing until Paperless
+// answers within the wait, then checks the Paperless URL once
+func TestWaitForUpstream(t *testing.T) {
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	s, err := New(&config.Config{
+		PaperlessURL:   ts.URL,
+		PaperlessToken: "test-token",
+		MCPTransport:   "stdio",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	s.waitForUpstream(context.Background(), time.Minute)
+	if state := s.health.state(); state.Degraded || requests.Load() != 3 {
+		t.Errorf("Expected Paperless to be reachable after 2 probes and the URL check, got degraded=%v after %d requests", state.Degraded, requests.Load())
+	}
+}
+
+// TestProbeResolvesPaperlessURL tests that a PAPERLESS_URL missing the
+// subpath Paperless is installed under is corrected once it answers, and
+// that server_info reports both URLs
+func TestProbeResolvesPaperlessURL(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/paperless/api/":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{}`))
+		case "/":
+			http.Redirect(w, r, "/paperless/", http.StatusFound)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	s, err := New(&config.Config{
+		PaperlessURL:   ts.URL + "/",
+		PaperlessToken: "test-token",
+		MCPTransport:   "stdio",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	s.probeUpstream(context.Background())
+	if got := s.paperlessClient.BaseURL(); got != ts.URL+"/paperless" {
+		t.Fatalf("Expected the client to use %s/paperless, got %s", ts.URL, got)
+	}
+
+	result, err := s.ExecuteTool(context.Background(), "server_info", map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	info := result.(map[string]interface{})
+	paperlessInfo := info["paperless"].(map[string]interface{})
+	if info["paperless_url"] != ts.URL+"/paperless" || paperlessInfo["configured_url"] != ts.URL+"/" || paperlessInfo["reachable"] != true {
+		t.Errorf("Unexpected server_info %v", info)
+	}
+}
+
+// TestProbeKeepsPaperlessURLOffHost tests that a PAPERLESS_URL redirecting
+// to the API on another host is not switched to but reported in
+// server_info
+func TestProbeKeepsPaperlessURLOffHost(t *testing.T) {
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer other.Close()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, other.URL+r.URL.Path, http.StatusFound)
+	}))
+	defer ts.Close()
+
+	s, err := New(&config.Config{
+		PaperlessURL:   ts.URL,
+		PaperlessToken: "test-token",
+		MCPTransport:   "stdio",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	s.probeUpstream(context.Background())
+	if got := s.paperlessClient.BaseURL(); got != ts.URL {
+		t.Fatalf("Expected the client to keep %s, got %s", ts.URL, got)
+	}
+
+	result, err := s.ExecuteTool(context.Background(), "server_info", map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	paperlessInfo := result.(map[string]interface{})["paperless"].(map[string]interface{})
+	if problem, _ := paperlessInfo["url_problem"].(string); !strings.Contains(problem, "another host") || !strings.Contains(problem, other.URL) {
+		t.Errorf("Expected the redirect reported as url_problem, got %v", paperlessInfo)
+	}
+	if _, ok := paperlessInfo["configured_url"]; ok {
+		t.Errorf("Expected no configured_url without a switch, got %v", paperlessInfo)
+	}
+}
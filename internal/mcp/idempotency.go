@@ -0,0 +1,199 @@
+package mcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// Idempotency constants
+const (
+	// IdempotencyTTL is how long a completed idempotency key is remembered
+	IdempotencyTTL = 24 * time.Hour
+
+	// IdempotencyKeyArg is the tool argument carrying the idempotency key
+	IdempotencyKeyArg = "idempotency_key"
+
+	// MaxIdempotencyKeyLength bounds the size of client-supplied keys
+	MaxIdempotencyKeyLength = 255
+)
+
+// idempotencyEntry records the outcome of a completed operation
+type idempotencyEntry struct {
+	Tool      string          `json:"tool"`
+	Result    json.RawMessage `json:"result"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// idempotencyStore remembers the results of create/upload operations by
+// idempotency key so that a retried agent turn returns the original result
+// instead of creating the document twice. Entries are kept in memory and,
+// when a path is configured, persisted to a small JSON file.
+type idempotencyStore struct {
+	path string
+
+	mu      sync.Mutex
+	entries map[string]idempotencyEntry
+	pending map[string]bool
+}
+
+// newIdempotencyStore creates a store, loading persisted entries from path
+// when it is non-empty
+func newIdempotencyStore(path string) *idempotencyStore {
+	store := &idempotencyStore{
+		path:    path,
+		entries: make(map[string]idempotencyEntry),
+		pending: make(map[string]bool),
+	}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err == nil {
+			if err := json.Unmarshal(data, &store.entries); err != nil {
+				slog.Warn("Ignoring unreadable idempotency store", "path", path, "error", err)
+				store.entries = make(map[string]idempotencyEntry)
+			}
+		} else if !os.IsNotExist(err) {
+			slog.Warn("Failed to read idempotency store", "path", path, "error", err)
+		}
+	}
+
+	return store
+}
+
+// identityKey identifies the Paperless identity of a request without
+// storing its token, so per-user state (MCP_AUTH_TOKEN_MAP) stays apart
+func identityKey(ctx context.Context) string {
+	if token, ok := paperless.TokenFromContext(ctx); ok {
+		sum := sha256.Sum256([]byte(token))
+		return hex.EncodeToString(sum[:8])
+	}
+	return "default"
+}
+
+// scopedKey namespaces a client key by tool and Paperless identity, so
+// different users can never collide on a key
+func scopedKey(ctx context.Context, tool, key string) string {
+	return identityKey(ctx) + ":" + tool + ":" + key
+}
+
+// begin reserves key for a new operation. If the key has already completed
+// its stored result is returned; if it is still in flight an error is returned.
+func (st *idempotencyStore) begin(key string) (json.RawMessage, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.expireLocked()
+
+	if entry, ok := st.entries[key]; ok {
+		return entry.Result, nil
+	}
+	if st.pending[key] {
+		return nil, fmt.Errorf("an operation with this idempotency_key is already in progress")
+	}
+	st.pending[key] = true
+	return nil, nil
+}
+
+// complete stores the result for key and releases its reservation
+func (st *idempotencyStore) complete(key, tool string, result interface{}) {
+	data, err := json.Marshal(result)
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	delete(st.pending, key)
+	if err != nil {
+		slog.Error("Failed to record idempotent result", "tool", tool, "error", err)
+		return
+	}
+	st.entries[key] = idempotencyEntry{
+		Tool:      tool,
+		Result:    data,
+		CreatedAt: time.Now(),
+	}
+	st.saveLocked()
+}
+
+// abort releases the reservation for key after a failed operation so the
+// client can retry with the same key
+func (st *idempotencyStore) abort(key string) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	delete(st.pending, key)
+}
+
+// expireLocked drops entries older than IdempotencyTTL; callers must hold st.mu
+func (st *idempotencyStore) expireLocked() {
+	cutoff := time.Now().Add(-IdempotencyTTL)
+	for key, entry := range st.entries {
+		if entry.CreatedAt.Before(cutoff) {
+			delete(st.entries, key)
+		}
+	}
+}
+
+// saveLocked persists entries when a path is configured; callers must hold st.mu
+func (st *idempotencyStore) saveLocked() {
+	if st.path == "" {
+		return
+	}
+	data, err := json.Marshal(st.entries)
+	if err != nil {
+		slog.Error("Failed to marshal idempotency store", "error", err)
+		return
+	}
+	if err := os.WriteFile(st.path, data, 0o600); err != nil {
+		slog.Error("Failed to write idempotency store", "path", st.path, "error", err)
+	}
+}
+
+// withIdempotency runs op at most once per idempotency key. Without a key
+// op always runs. When the key was already used, the original result is
+// returned with "idempotent_replay": true instead of running op again.
+func (s *Server) withIdempotency(ctx context.Context, tool string, args map[string]interface{}, op func() (interface{}, error)) (interface{}, error) {
+	rawKey, present := args[IdempotencyKeyArg]
+	if !present {
+		return op()
+	}
+	key, ok := rawKey.(string)
+	if !ok || key == "" || len(key) > MaxIdempotencyKeyLength {
+		return nil, fmt.Errorf("%s must be a non-empty string of at most %d characters", IdempotencyKeyArg, MaxIdempotencyKeyLength)
+	}
+
+	scoped := scopedKey(ctx, tool, key)
+	previous, err := s.idempotency.begin(scoped)
+	if err != nil {
+		return nil, err
+	}
+	if previous != nil {
+		logging.FromContext(ctx).Info("Returning result for repeated idempotency key", "tool", tool)
+		var result map[string]interface{}
+		if err := json.Unmarshal(previous, &result); err != nil {
+			return map[string]interface{}{
+				"result":            previous,
+				"idempotent_replay": true,
+			}, nil
+		}
+		result["idempotent_replay"] = true
+		return result, nil
+	}
+
+	result, err := op()
+	if err != nil {
+		s.idempotency.abort(scoped)
+		return nil, err
+	}
+	s.idempotency.complete(scoped, tool, result)
+	return result, nil
+}
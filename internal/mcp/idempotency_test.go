@@ -0,0 +1,55 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+// TestWithIdempotency tests that repeated keys replay the original result
+// and that failed operations release their key
+func TestWithIdempotency(t *testing.T) {
+	s := &Server{idempotency: newIdempotencyStore("")}
+	ctx := context.Background()
+
+	calls := 0
+	op := func() (interface{}, error) {
+		calls++
+		return map[string]interface{}{"id": calls}, nil
+	}
+	args := map[string]interface{}{IdempotencyKeyArg: "turn-1"}
+
+	first, err := s.withIdempotency(ctx, "create_document", args, op)
+	if err != nil {
+		t.Fatalf("First call failed: %v", err)
+	}
+	second, err := s.withIdempotency(ctx, "create_document", args, op)
+	if err != nil {
+		t.Fatalf("Second call failed: %v", err)
+	}
+
+	if calls != 1 {
+		t.Errorf("Expected operation to run once, ran %d times", calls)
+	}
+	if first.(map[string]interface{})["id"] != 1 {
+		t.Errorf("Unexpected first result: %v", first)
+	}
+	replay := second.(map[string]interface{})
+	if replay["id"] != float64(1) || replay["idempotent_replay"] != true {
+		t.Errorf("Expected replay of first result, got %v", replay)
+	}
+
+	// Failures must not consume the key
+	failArgs := map[string]interface{}{IdempotencyKeyArg: "turn-2"}
+	if _, err := s.withIdempotency(ctx, "create_document", failArgs, func() (interface{}, error) {
+		return nil, fmt.Errorf("paperless unavailable")
+	}); err == nil {
+		t.Fatal("Expected failing operation to return an error")
+	}
+	if _, err := s.withIdempotency(ctx, "create_document", failArgs, op); err != nil {
+		t.Fatalf("Expected retry after failure to succeed, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected retry after failure to run the operation, ran %d times", calls)
+	}
+}
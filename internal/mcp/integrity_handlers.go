@@ -0,0 +1,208 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// Limits of the verify_documents tool
+const (
+	DefaultVerifyMaxDocuments = 100
+	MaxVerifyMaxDocuments     = 1000
+)
+
+// Problems reported by verify_documents
+const (
+	integrityMetadataUnavailable = "metadata_unavailable"
+	integrityMissingChecksum     = "missing_checksum"
+	integrityMissingArchive      = "missing_archive"
+	integrityArchiveIncomplete   = "archive_incomplete"
+	integrityChecksumMismatch    = "checksum_mismatch"
+	integrityArchiveMismatch     = "archive_checksum_mismatch"
+	integrityDownloadFailed      = "download_failed"
+	integrityTooLarge            = "too_large_to_verify"
+)
+
+// integrityIssue is a problem found with one document
+type integrityIssue struct {
+	DocumentID int    `json:"document_id"`
+	Title      string `json:"title"`
+	Problem    string `json:"problem"`
+	Detail     string `json:"detail"`
+}
+
+// verifyTargets returns the documents verify_documents should check, given
+// by document_ids or a filter, and how many matched in total
+func (s *Server) verifyTargets(ctx context.Context, args map[string]interface{}, maxDocuments int) ([]paperless.Document, int, error) {
+	ids, err := intListArg(args, "document_ids")
+	if err != nil {
+		return nil, 0, err
+	}
+	filter, hasFilter := args["filter"].(map[string]interface{})
+	switch {
+	case len(ids) > 0 && hasFilter:
+		return nil, 0, fmt.Errorf("provide either document_ids or filter, not both")
+	case len(ids) > 0:
+		if len(ids) > maxDocuments {
+			return nil, 0, fmt.Errorf("at most %d document_ids can be verified at once", maxDocuments)
+		}
+		documents, err := s.listDocumentsByID(ctx, ids, "id,title")
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to get documents: %w", err)
+		}
+		return documents, len(documents), nil
+	case !hasFilter:
+		return nil, 0, fmt.Errorf("document_ids or filter is required")
+	}
+
+	filters, err := filterValues(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	filters.Set("fields", "id,title")
+	if filters.Get("ordering") == "" {
+		filters.Set("ordering", "id")
+	}
+
+	var documents []paperless.Document
+	total := 0
+	for page := 1; len(documents) < maxDocuments; page++ {
+		response, err := s.paperlessClient.ListDocuments(ctx, filters, page, paperless.MaxPageSize)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to list documents: %w", err)
+		}
+		total = response.Count
+
+		var results []paperless.Document
+		if err := json.Unmarshal(response.Results, &results); err != nil {
+			return nil, 0, fmt.Errorf("failed to parse documents: %w", err)
+		}
+		documents = append(documents, results[:min(len(results), maxDocuments-len(documents))]...)
+		if response.Next == nil || len(results) == 0 {
+			break
+		}
+	}
+	return documents, total, nil
+}
+
+// verifyDocument checks the stored files of one document, returning the
+// problems found
+func (s *Server) verifyDocument(ctx context.Context, document paperless.Document, checksums bool) []integrityIssue {
+	issue := func(problem, detail string) integrityIssue {
+		return integrityIssue{DocumentID: document.ID, Title: document.Title, Problem: problem, Detail: detail}
+	}
+
+	metadata, err := s.paperlessClient.GetDocumentMetadata(ctx, document.ID)
+	if err != nil {
+		return []integrityIssue{issue(integrityMetadataUnavailable, err.Error())}
+	}
+
+	var issues []integrityIssue
+	if metadata.OriginalChecksum == "" {
+		issues = append(issues, issue(integrityMissingChecksum, "Paperless has no checksum for the original file"))
+	}
+	if !metadata.HasArchiveVersion {
+		issues = append(issues, issue(integrityMissingArchive, "no archived PDF version; expected only when archiving was skipped for this file"))
+	} else if metadata.ArchiveChecksum == nil || *metadata.ArchiveChecksum == "" {
+		issues = append(issues, issue(integrityArchiveIncomplete, "an archived version is recorded without a checksum"))
+	}
+	if !checksums {
+		return issues
+	}
+
+	verify := func(original bool, want string, mismatch string) {
+		if want == "" {
+			return
+		}
+		file, err := s.paperlessClient.DownloadDocument(ctx, document.ID, original)
+		if errors.Is(err, paperless.ErrResponseTooLarge) {
+			issues = append(issues, issue(integrityTooLarge, err.Error()))
+			return
+		}
+		if err != nil {
+			issues = append(issues, issue(integrityDownloadFailed, err.Error()))
+			return
+		}
+		if got := documentChecksum(file.Content); got != want {
+			issues = append(issues, issue(mismatch, fmt.Sprintf("stored checksum %s, file has %s", want, got)))
+		}
+	}
+	verify(true, metadata.OriginalChecksum, integrityChecksumMismatch)
+	if metadata.HasArchiveVersion && metadata.ArchiveChecksum != nil {
+		verify(false, *metadata.ArchiveChecksum, integrityArchiveMismatch)
+	}
+	return issues
+}
+
+// handleVerifyDocuments handles the verify_documents tool
+func (s *Server) handleVerifyDocuments(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	maxDocuments := boundedIntArg(args, "max_documents", DefaultVerifyMaxDocuments, MaxVerifyMaxDocuments)
+	checksums, _ := args["verify_checksums"].(bool)
+
+	stream, err := s.newResultStream(ctx, "verify_documents", args)
+	if err != nil {
+		return nil, err
+	}
+
+	documents, total, err := s.verifyTargets(ctx, args, maxDocuments)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to select documents to verify", "error", err)
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Debug("Verifying documents",
+		"document_count", len(documents),
+		"verify_checksums", checksums)
+
+	issues := []integrityIssue{}
+	problems := map[string]int{}
+	healthy := 0
+	for i, document := range documents {
+		found := s.verifyDocument(ctx, document, checksums)
+		if len(found) == 0 {
+			healthy++
+		}
+		for _, issue := range found {
+			problems[issue.Problem]++
+		}
+		issues = append(issues, found...)
+
+		// When streaming, issues are sent on once a chunk has accumulated
+		if stream != nil && (len(issues) >= stream.chunkSize || i == len(documents)-1) {
+			if err := streamItems(ctx, stream, "issues", issues); err != nil {
+				logging.FromContext(ctx).Error("Failed to stream issues", "error", err)
+				return nil, err
+			}
+			// Queued notifications still hold the sent issues
+			issues = []integrityIssue{}
+		}
+
+		s.reportProgress(ctx, float64(i+1), float64(len(documents)),
+			fmt.Sprintf("Verified %d of %d documents", i+1, len(documents)))
+	}
+
+	logging.FromContext(ctx).Info("Documents verified",
+		"document_count", len(documents),
+		"healthy", healthy,
+		"problems", problems)
+
+	result := map[string]interface{}{
+		"checked":            len(documents),
+		"total_matching":     total,
+		"truncated":          len(documents) < total,
+		"checksums_verified": checksums,
+		"healthy":            healthy,
+		"problems":           problems,
+	}
+	if stream != nil {
+		result["streamed"] = stream.summary()
+	} else {
+		result["issues"] = issues
+	}
+	return result, nil
+}
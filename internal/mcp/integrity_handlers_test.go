@@ -0,0 +1,81 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// TestVerifyDocuments tests that missing archives, unreadable metadata and
+// checksum mismatches are reported per document
+func TestVerifyDocuments(t *testing.T) {
+	original := []byte("%PDF original")
+	archive := []byte("%PDF archive")
+	sum := documentChecksum(original)
+	archiveSum := documentChecksum(archive)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/documents/":
+			if r.URL.Query().Get("fields") != "id,title" {
+				t.Errorf("Expected only IDs and titles to be listed, got %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"count":4,"next":null,"results":[{"id":1,"title":"Healthy"},{"id":2,"title":"Image"},{"id":3,"title":"Corrupted"},{"id":4,"title":"Gone"}]}`))
+		case "/api/documents/1/metadata/", "/api/documents/3/metadata/":
+			fmt.Fprintf(w, `{"original_checksum":%q,"has_archive_version":true,"archive_checksum":%q}`, sum, archiveSum)
+		case "/api/documents/2/metadata/":
+			fmt.Fprintf(w, `{"original_checksum":%q,"has_archive_version":false,"archive_checksum":null}`, sum)
+		case "/api/documents/4/metadata/":
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"detail":"file not found"}`))
+		case "/api/documents/1/download/", "/api/documents/2/download/":
+			if r.URL.Query().Get("original") == "true" {
+				w.Write(original)
+			} else {
+				w.Write(archive)
+			}
+		case "/api/documents/3/download/":
+			if r.URL.Query().Get("original") == "true" {
+				w.Write(original)
+			} else {
+				w.Write([]byte("truncated"))
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	s := &Server{paperlessClient: paperless.New(ts.URL, "test-token")}
+
+	result, err := s.handleVerifyDocuments(context.Background(), map[string]interface{}{
+		"filter":           map[string]interface{}{},
+		"verify_checksums": true,
+	})
+	if err != nil {
+		t.Fatalf("verify_documents failed: %v", err)
+	}
+	report := result.(map[string]interface{})
+	if report["checked"] != 4 || report["healthy"] != 1 || report["truncated"] != false {
+		t.Errorf("Unexpected totals: %v", report)
+	}
+
+	want := map[int]string{2: integrityMissingArchive, 3: integrityArchiveMismatch, 4: integrityMetadataUnavailable}
+	issues := report["issues"].([]integrityIssue)
+	if len(issues) != len(want) {
+		t.Fatalf("Expected %d issues, got %+v", len(want), issues)
+	}
+	for _, issue := range issues {
+		if want[issue.DocumentID] != issue.Problem {
+			t.Errorf("Unexpected issue %+v", issue)
+		}
+	}
+
+	if _, err := s.handleVerifyDocuments(context.Background(), map[string]interface{}{}); err == nil {
+		t.Error("Expected document_ids or filter to be required")
+	}
+}
@@ -0,0 +1,385 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+)
+
+// Undo journal constants
+const (
+	// UndoJournalTTL is how long an operation can be undone
+	UndoJournalTTL = time.Hour
+
+	// MaxUndoJournalEntries bounds how many operations are remembered
+	MaxUndoJournalEntries = 50
+)
+
+// Undo actions
+const (
+	// undoRestoreFields writes the recorded previous values back
+	undoRestoreFields = "restore_fields"
+
+	// undoDeleteCreated deletes an object the operation created
+	undoDeleteCreated = "delete_created"
+
+	// undoRestoreDeleted restores deleted documents from the trash
+	undoRestoreDeleted = "restore_deleted"
+)
+
+// journalObject is one object changed by an operation, with the values
+// its changed fields had before
+type journalObject struct {
+	ID       int                    `json:"id"`
+	Previous map[string]interface{} `json:"previous,omitempty"`
+}
+
+// journalEntry records how to revert one operation
+type journalEntry struct {
+	ID        int64           `json:"id"`
+	Identity  string          `json:"identity"`
+	Tool      string          `json:"tool"`
+	Summary   string          `json:"summary"`
+	Action    string          `json:"action"`
+	Resource  string          `json:"resource"` // API collection, e.g. "documents" or "tags"
+	Objects   []journalObject `json:"objects"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// undoJournal remembers recent mutations made through this server so the
+// most recent one can be reverted. Entries are kept in memory and, when a
+// path is configured, persisted to a small JSON file.
+type undoJournal struct {
+	path string
+
+	mu      sync.Mutex
+	entries []journalEntry // oldest first
+	nextID  int64
+}
+
+// newUndoJournal creates a journal, loading persisted entries from path
+// when it is non-empty
+func newUndoJournal(path string) *undoJournal {
+	j := &undoJournal{path: path, nextID: 1}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err == nil {
+			if err := json.Unmarshal(data, &j.entries); err != nil {
+				slog.Warn("Ignoring unreadable undo journal", "path", path, "error", err)
+				j.entries = nil
+			}
+		} else if !os.IsNotExist(err) {
+			slog.Warn("Failed to read undo journal", "path", path, "error", err)
+		}
+		for _, entry := range j.entries {
+			if entry.ID >= j.nextID {
+				j.nextID = entry.ID + 1
+			}
+		}
+	}
+
+	return j
+}
+
+// record adds an entry for the calling identity
+func (j *undoJournal) record(ctx context.Context, entry journalEntry) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	entry.ID = j.nextID
+	j.nextID++
+	entry.Identity = identityKey(ctx)
+	entry.CreatedAt = time.Now().UTC()
+
+	j.expireLocked()
+	j.entries = append(j.entries, entry)
+	if len(j.entries) > MaxUndoJournalEntries {
+		j.entries = j.entries[len(j.entries)-MaxUndoJournalEntries:]
+	}
+	j.saveLocked()
+
+	logging.FromContext(ctx).Debug("Recorded operation for undo",
+		"tool", entry.Tool,
+		"objects", len(entry.Objects))
+}
+
+// recordUpdate journals a field update; previous is nil when the state
+// before the update could not be read, in which case nothing is recorded
+func (j *undoJournal) recordUpdate(ctx context.Context, tool, resource string, id int, previous map[string]interface{}, summary string) {
+	if previous == nil {
+		return
+	}
+	j.record(ctx, journalEntry{
+		Tool:     tool,
+		Summary:  summary,
+		Action:   undoRestoreFields,
+		Resource: resource,
+		Objects:  []journalObject{{ID: id, Previous: previous}},
+	})
+}
+
+// recordCreate journals the creation of an object
+func (j *undoJournal) recordCreate(ctx context.Context, tool, resource string, id int, summary string) {
+	j.record(ctx, journalEntry{
+		Tool:     tool,
+		Summary:  summary,
+		Action:   undoDeleteCreated,
+		Resource: resource,
+		Objects:  []journalObject{{ID: id}},
+	})
+}
+
+// last returns the most recent entry of identity
+func (j *undoJournal) last(identity string) (journalEntry, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.expireLocked()
+	for i := len(j.entries) - 1; i >= 0; i-- {
+		if j.entries[i].Identity == identity {
+			return j.entries[i], true
+		}
+	}
+	return journalEntry{}, false
+}
+
+// pending returns how many entries identity can still undo
+func (j *undoJournal) pending(identity string) int {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	n := 0
+	for _, entry := range j.entries {
+		if entry.Identity == identity {
+			n++
+		}
+	}
+	return n
+}
+
+// remove drops the entry with the given ID
+func (j *undoJournal) remove(id int64) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for i, entry := range j.entries {
+		if entry.ID == id {
+			j.entries = append(j.entries[:i], j.entries[i+1:]...)
+			break
+		}
+	}
+	j.saveLocked()
+}
+
+// expireLocked drops entries older than UndoJournalTTL; callers must hold j.mu
+func (j *undoJournal) expireLocked() {
+	cutoff := time.Now().Add(-UndoJournalTTL)
+	i := 0
+	for i < len(j.entries) && j.entries[i].CreatedAt.Before(cutoff) {
+		i++
+	}
+	j.entries = j.entries[i:]
+}
+
+// saveLocked persists entries when a path is configured; callers must hold j.mu
+func (j *undoJournal) saveLocked() {
+	if j.path == "" {
+		return
+	}
+	data, err := json.Marshal(j.entries)
+	if err != nil {
+		slog.Error("Failed to marshal undo journal", "error", err)
+		return
+	}
+	if err := os.WriteFile(j.path, data, 0o600); err != nil {
+		slog.Error("Failed to write undo journal", "path", j.path, "error", err)
+	}
+}
+
+// objectPath is the API path of an object in resource
+func objectPath(resource string, id int) string {
+	return fmt.Sprintf("/api/%s/%d/", resource, id)
+}
+
+// snapshotFields reads the current values of the fields about to be
+// updated, for the undo journal. Failures are logged and return nil so
+// that journaling never blocks the update itself.
+func (s *Server) snapshotFields(ctx context.Context, resource string, id int, updates map[string]interface{}) map[string]interface{} {
+	data, err := s.paperlessClient.GET(ctx, objectPath(resource, id))
+	if err != nil {
+		logging.FromContext(ctx).Warn("Failed to read state for undo journal",
+			"resource", resource,
+			"id", id,
+			"error", err)
+		return nil
+	}
+	var current map[string]interface{}
+	if err := json.Unmarshal(data, &current); err != nil {
+		logging.FromContext(ctx).Warn("Failed to parse state for undo journal",
+			"resource", resource,
+			"id", id,
+			"error", err)
+		return nil
+	}
+
+	previous := make(map[string]interface{}, len(updates))
+	for field := range updates {
+		if value, ok := current[field]; ok {
+			previous[field] = value
+		}
+	}
+	return previous
+}
+
+// bulkEditFields maps bulk edit operations to the document field they change
+var bulkEditFields = map[string]string{
+	"add_tags":      "tags",
+	"remove_tags":   "tags",
+	"correspondent": "correspondent",
+	"document_type": "document_type",
+	"storage_path":  "storage_path",
+}
+
+// snapshotBulkEdit reads the fields a bulk edit is about to change on each
+// document, for the undo journal. It returns nil when the state could not
+// be read.
+func (s *Server) snapshotBulkEdit(ctx context.Context, documentIDs []int, operations map[string]interface{}) []journalObject {
+	touched := make(map[string]bool)
+	for operation := range operations {
+		if field, ok := bulkEditFields[operation]; ok {
+			touched[field] = true
+		}
+	}
+	if len(touched) == 0 {
+		return nil
+	}
+
+	documents, err := s.listDocumentsByID(ctx, documentIDs, "id,tags,correspondent,document_type,storage_path")
+	if err != nil {
+		logging.FromContext(ctx).Warn("Failed to read state for undo journal",
+			"document_count", len(documentIDs),
+			"error", err)
+		return nil
+	}
+
+	objects := make([]journalObject, 0, len(documents))
+	for _, document := range documents {
+		previous := make(map[string]interface{}, len(touched))
+		if touched["tags"] {
+			previous["tags"] = document.Tags
+		}
+		if touched["correspondent"] {
+			previous["correspondent"] = document.Correspondent
+		}
+		if touched["document_type"] {
+			previous["document_type"] = document.DocumentType
+		}
+		if touched["storage_path"] {
+			previous["storage_path"] = document.StoragePath
+		}
+		objects = append(objects, journalObject{ID: document.ID, Previous: previous})
+	}
+	return objects
+}
+
+// handleUndoLastOperation handles the undo_last_operation tool
+func (s *Server) handleUndoLastOperation(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	identity := identityKey(ctx)
+	entry, ok := s.journal.last(identity)
+	if !ok {
+		return nil, fmt.Errorf("there is no recent operation to undo (operations can be undone for %s)", UndoJournalTTL)
+	}
+
+	logging.FromContext(ctx).Debug("Undoing operation",
+		"tool", entry.Tool,
+		"action", entry.Action,
+		"objects", len(entry.Objects))
+
+	var failures []map[string]interface{}
+	fail := func(id int, err error) {
+		logging.FromContext(ctx).Error("Failed to undo operation",
+			"tool", entry.Tool,
+			"id", id,
+			"error", err)
+		failures = append(failures, map[string]interface{}{"id": id, "error": err.Error()})
+	}
+
+	switch entry.Action {
+	case undoRestoreFields:
+		for _, object := range entry.Objects {
+			if _, err := s.paperlessClient.PATCH(ctx, objectPath(entry.Resource, object.ID), object.Previous); err != nil {
+				fail(object.ID, err)
+			}
+		}
+	case undoDeleteCreated:
+		// Undoing a create deletes the document, so the delete protection
+		// rules apply as they do to delete_document
+		if entry.Resource == "documents" {
+			ids := make([]int, len(entry.Objects))
+			for i, object := range entry.Objects {
+				ids[i] = object.ID
+			}
+			if err := s.checkDeleteProtection(ctx, ids); err != nil {
+				return nil, fmt.Errorf("%s was not undone: %w", entry.Summary, err)
+			}
+		}
+		for _, object := range entry.Objects {
+			if err := s.paperlessClient.DELETE(ctx, objectPath(entry.Resource, object.ID)); err != nil {
+				fail(object.ID, err)
+			}
+		}
+	case undoRestoreDeleted:
+		ids := make([]int, len(entry.Objects))
+		for i, object := range entry.Objects {
+			ids[i] = object.ID
+		}
+		if err := s.paperlessClient.RestoreDocuments(ctx, ids); err != nil {
+			for _, id := range ids {
+				fail(id, fmt.Errorf("could not restore from trash (the trash may be disabled or emptied): %w", err))
+			}
+		}
+	default:
+		return nil, fmt.Errorf("operation %s cannot be undone", entry.Tool)
+	}
+
+	if entry.Resource == "documents" {
+		ids := make([]int, len(entry.Objects))
+		for i, object := range entry.Objects {
+			ids[i] = object.ID
+		}
+		s.documents.invalidate(ids...)
+	} else {
+		s.metadata.invalidateAll()
+	}
+
+	// Keep the entry when nothing could be reverted so it can be retried
+	if len(failures) == len(entry.Objects) {
+		return nil, fmt.Errorf("failed to undo %s: %s", entry.Summary, failures[0]["error"])
+	}
+	s.journal.remove(entry.ID)
+
+	logging.FromContext(ctx).Info("Operation undone",
+		"tool", entry.Tool,
+		"summary", entry.Summary,
+		"failed", len(failures))
+
+	result := map[string]interface{}{
+		"success":   len(failures) == 0,
+		"undone":    entry.Summary,
+		"tool":      entry.Tool,
+		"performed": entry.CreatedAt,
+		"objects":   len(entry.Objects) - len(failures),
+		"undoable":  s.journal.pending(identity),
+	}
+	if len(failures) > 0 {
+		result["failures"] = failures
+	}
+	return result, nil
+}
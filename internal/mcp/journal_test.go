@@ -0,0 +1,58 @@
+package mcp
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// TestUndoJournal tests per-identity scoping, expiry, persistence and
+// removal of journal entries
+func TestUndoJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "undo.json")
+	journal := newUndoJournal(path)
+
+	alice := paperless.WithToken(context.Background(), "alice-token")
+	bob := paperless.WithToken(context.Background(), "bob-token")
+
+	journal.recordUpdate(alice, "update_tag", "tags", 3, map[string]interface{}{"name": "old"}, "Updated tag 3")
+	journal.recordCreate(alice, "create_tag", "tags", 9, "Created tag \"new\"")
+	journal.recordUpdate(bob, "update_tag", "tags", 4, nil, "Updated tag 4")
+
+	if _, ok := journal.last(identityKey(bob)); ok {
+		t.Error("Expected an update without a snapshot not to be journaled")
+	}
+
+	entry, ok := journal.last(identityKey(alice))
+	if !ok || entry.Tool != "create_tag" || entry.Action != undoDeleteCreated {
+		t.Fatalf("Expected the most recent entry of alice, got %+v", entry)
+	}
+
+	reloaded := newUndoJournal(path)
+	if n := reloaded.pending(identityKey(alice)); n != 2 {
+		t.Fatalf("Expected 2 persisted entries, got %d", n)
+	}
+	reloaded.remove(entry.ID)
+	entry, ok = reloaded.last(identityKey(alice))
+	if !ok || entry.Tool != "update_tag" || entry.Objects[0].Previous["name"] != "old" {
+		t.Fatalf("Expected the update to be next, got %+v", entry)
+	}
+
+	// A new entry after reloading must not reuse an existing ID
+	reloaded.recordCreate(alice, "create_tag", "tags", 10, "Created tag \"other\"")
+	if latest, _ := reloaded.last(identityKey(alice)); latest.ID <= entry.ID {
+		t.Errorf("Expected a fresh ID, got %d after %d", latest.ID, entry.ID)
+	}
+
+	reloaded.mu.Lock()
+	for i := range reloaded.entries {
+		reloaded.entries[i].CreatedAt = time.Now().Add(-2 * UndoJournalTTL)
+	}
+	reloaded.mu.Unlock()
+	if _, ok := reloaded.last(identityKey(alice)); ok {
+		t.Error("Expected expired entries to be dropped")
+	}
+}
@@ -0,0 +1,284 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+const (
+	// DefaultLinkDepth is how many links get_linked_documents follows
+	// from the starting document by default
+	DefaultLinkDepth = 1
+
+	// MaxLinkDepth bounds how far get_linked_documents traverses
+	MaxLinkDepth = 5
+
+	// MaxLinkedDocuments bounds how many documents a traversal visits
+	MaxLinkedDocuments = 200
+)
+
+// linkDocumentFields limits document lookups during link traversal to the
+// fields needed to follow links
+const linkDocumentFields = "id,title,custom_fields"
+
+// linkIDs returns the document IDs held by a document link value
+func linkIDs(value interface{}) []int {
+	values, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+	ids := make([]int, 0, len(values))
+	for _, v := range values {
+		if id, ok := v.(float64); ok && id >= 1 {
+			ids = append(ids, int(id))
+		}
+	}
+	return ids
+}
+
+// hasListValues reports whether any document has a list-valued custom
+// field, the shape document link values take
+func hasListValues(documents []*paperless.Document) bool {
+	for _, document := range documents {
+		for _, field := range document.CustomFields {
+			if _, ok := field.Value.([]interface{}); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// documentLinkFields returns the names of the document link custom
+// fields keyed by field ID
+func (s *Server) documentLinkFields(ctx context.Context) (map[int]string, error) {
+	fields, err := s.metadata.customFields.get(ctx, s.paperlessClient)
+	if err != nil {
+		return nil, err
+	}
+	linkFields := make(map[int]string)
+	for _, field := range fields {
+		if field.DataType == paperless.CustomFieldDocumentLink {
+			linkFields[field.ID] = field.Name
+		}
+	}
+	return linkFields, nil
+}
+
+// listDocumentsByID fetches the given documents in batched id__in
+// requests, restricted to fields when non-empty
+func (s *Server) listDocumentsByID(ctx context.Context, ids []int, fields string) ([]paperless.Document, error) {
+	idStrings := make([]string, len(ids))
+	for i, id := range ids {
+		idStrings[i] = strconv.Itoa(id)
+	}
+	filters := url.Values{}
+	filters.Set("id__in", strings.Join(idStrings, ","))
+	if fields != "" {
+		filters.Set("fields", fields)
+	}
+	return paperless.CollectAll[paperless.Document](ctx, func(ctx context.Context, page, pageSize int) (*paperless.PaginatedResponse, error) {
+		return s.paperlessClient.ListDocuments(ctx, filters, page, pageSize)
+	})
+}
+
+// resolveDocumentLinks fills in the IDs and titles of linked documents
+// on document link custom fields, using one lookup for all documents.
+// Failures are logged and leave the links unresolved rather than failing
+// the tool call.
+func (s *Server) resolveDocumentLinks(ctx context.Context, documents []*paperless.Document) {
+	if !hasListValues(documents) {
+		return
+	}
+
+	linkFields, err := s.documentLinkFields(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Warn("Failed to list custom fields for document links", "error", err)
+		return
+	}
+
+	seen := make(map[int]bool)
+	var ids []int
+	for _, document := range documents {
+		for _, field := range document.CustomFields {
+			if _, ok := linkFields[field.Field]; !ok {
+				continue
+			}
+			for _, id := range linkIDs(field.Value) {
+				if !seen[id] {
+					seen[id] = true
+					ids = append(ids, id)
+				}
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	linked, err := s.listDocumentsByID(ctx, ids, "id,title")
+	if err != nil {
+		logging.FromContext(ctx).Warn("Failed to look up linked documents", "error", err)
+		return
+	}
+	titles := make(map[int]string, len(linked))
+	for _, document := range linked {
+		titles[document.ID] = document.Title
+	}
+
+	for _, document := range documents {
+		for i := range document.CustomFields {
+			field := &document.CustomFields[i]
+			if _, ok := linkFields[field.Field]; !ok {
+				continue
+			}
+			// Documents the token cannot see keep their ID without a title
+			refs := []paperless.DocumentRef{}
+			for _, id := range linkIDs(field.Value) {
+				refs = append(refs, paperless.DocumentRef{ID: id, Title: titles[id]})
+			}
+			field.LinkedDocuments = refs
+		}
+	}
+}
+
+// documentLink is an edge followed during link traversal
+type documentLink struct {
+	From  int    `json:"from"`
+	To    int    `json:"to"`
+	Field string `json:"field"`
+}
+
+// linkedDocument is a document reached during link traversal
+type linkedDocument struct {
+	ID      int    `json:"id"`
+	Title   string `json:"title,omitempty"`
+	Depth   int    `json:"depth"`
+	Preview string `json:"preview,omitempty"`
+}
+
+// handleGetLinkedDocuments handles the get_linked_documents tool
+func (s *Server) handleGetLinkedDocuments(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	// Extract and validate document_id
+	documentIDFloat, ok := args["document_id"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("document_id parameter is required and must be an integer")
+	}
+	documentID := int(documentIDFloat)
+	if documentID < 1 {
+		return nil, fmt.Errorf("document_id must be a positive integer")
+	}
+
+	maxDepth := boundedIntArg(args, "max_depth", DefaultLinkDepth, MaxLinkDepth)
+	previewChars, err := previewCharsArg(args)
+	if err != nil {
+		return nil, err
+	}
+	fields := linkDocumentFields
+	if previewChars > 0 {
+		fields += ",content"
+	}
+
+	logging.FromContext(ctx).Debug("Getting linked documents",
+		"document_id", documentID,
+		"max_depth", maxDepth)
+
+	document, err := s.getDocument(ctx, documentID)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to get document",
+			"document_id", documentID,
+			"error", err)
+		return nil, fmt.Errorf("failed to get document: %w", err)
+	}
+
+	linkFields, err := s.documentLinkFields(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to list custom fields", "error", err)
+		return nil, fmt.Errorf("failed to list custom fields: %w", err)
+	}
+
+	visited := map[int]bool{documentID: true}
+	documents := []linkedDocument{}
+	links := []documentLink{}
+	truncated := false
+
+	// Breadth-first, fetching each level of linked documents in one lookup
+	frontier := []paperless.Document{*document}
+	for depth := 1; len(frontier) > 0; depth++ {
+		var next []int
+		for _, current := range frontier {
+			for _, field := range current.CustomFields {
+				name, ok := linkFields[field.Field]
+				if !ok {
+					continue
+				}
+				for _, id := range linkIDs(field.Value) {
+					links = append(links, documentLink{From: current.ID, To: id, Field: name})
+					if visited[id] {
+						continue
+					}
+					if depth > maxDepth || len(visited) >= MaxLinkedDocuments {
+						truncated = true
+						continue
+					}
+					visited[id] = true
+					next = append(next, id)
+				}
+			}
+		}
+		if len(next) == 0 {
+			break
+		}
+
+		fetched, err := s.listDocumentsByID(ctx, next, fields)
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to get linked documents",
+				"document_id", documentID,
+				"error", err)
+			return nil, fmt.Errorf("failed to get linked documents: %w", err)
+		}
+		byID := make(map[int]paperless.Document, len(fetched))
+		for _, linked := range fetched {
+			byID[linked.ID] = linked
+		}
+		for _, id := range next {
+			linked := linkedDocument{ID: id, Title: byID[id].Title, Depth: depth}
+			if previewChars > 0 {
+				linked.Preview = truncatePreview(byID[id].Content, previewChars)
+			}
+			documents = append(documents, linked)
+		}
+		frontier = fetched
+	}
+
+	// Links found past the depth limit point outside the returned set
+	if truncated {
+		kept := links[:0]
+		for _, link := range links {
+			if visited[link.To] {
+				kept = append(kept, link)
+			}
+		}
+		links = kept
+	}
+
+	logging.FromContext(ctx).Info("Linked documents retrieved",
+		"document_id", documentID,
+		"linked", len(documents),
+		"truncated", truncated)
+
+	return map[string]interface{}{
+		"document":  paperless.DocumentRef{ID: document.ID, Title: document.Title},
+		"max_depth": maxDepth,
+		"count":     len(documents),
+		"documents": documents,
+		"links":     links,
+		"truncated": truncated,
+	}, nil
+}
@@ -0,0 +1,105 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// TestGetLinkedDocuments tests link resolution and depth-limited traversal
+// of a contract -> amendment -> amendment chain
+func TestGetLinkedDocuments(t *testing.T) {
+	docs := map[int]map[string]interface{}{
+		1: {"id": 1, "title": "Contract", "custom_fields": []interface{}{map[string]interface{}{"field": 7, "value": []int{2}}}},
+		2: {"id": 2, "title": "Amendment 1", "content": "First amendment to the contract", "custom_fields": []interface{}{map[string]interface{}{"field": 7, "value": []int{1, 3}}}},
+		3: {"id": 3, "title": "Amendment 2", "custom_fields": []interface{}{map[string]interface{}{"field": 7, "value": []int{2}}}},
+	}
+	page := func(results interface{}) map[string]interface{} {
+		return map[string]interface{}{"count": 1, "next": nil, "results": results}
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body interface{}
+		switch {
+		case r.URL.Path == "/api/custom_fields/":
+			body = page([]interface{}{
+				map[string]interface{}{"id": 7, "name": "Related", "data_type": "documentlink"},
+				map[string]interface{}{"id": 8, "name": "Amount", "data_type": "monetary"},
+			})
+		case r.URL.Path == "/api/documents/":
+			var results []interface{}
+			for _, id := range strings.Split(r.URL.Query().Get("id__in"), ",") {
+				n, _ := strconv.Atoi(id)
+				if doc, ok := docs[n]; ok {
+					results = append(results, doc)
+				}
+			}
+			body = page(results)
+		case strings.HasSuffix(r.URL.Path, "/metadata/"):
+			body = map[string]interface{}{"original_size": 10}
+		default:
+			n, _ := strconv.Atoi(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/documents/"), "/"))
+			body = docs[n]
+		}
+		json.NewEncoder(w).Encode(body)
+	}))
+	defer ts.Close()
+
+	s := &Server{
+		paperlessClient: paperless.New(ts.URL, "test-token"),
+		documents:       newDocumentCache(10, time.Minute),
+	}
+
+	document, err := s.getDocument(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	refs := document.CustomFields[0].LinkedDocuments
+	if len(refs) != 2 || refs[0].Title != "Contract" || refs[1].Title != "Amendment 2" {
+		t.Errorf("Expected linked documents to be resolved with titles, got %+v", refs)
+	}
+
+	result, err := s.handleGetLinkedDocuments(context.Background(), map[string]interface{}{"document_id": float64(1)})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	out := result.(map[string]interface{})
+	if linked := out["documents"].([]linkedDocument); len(linked) != 1 || linked[0].ID != 2 {
+		t.Errorf("Expected only document 2 at depth 1, got %+v", linked)
+	}
+	if out["truncated"] != true {
+		t.Error("Expected traversal to report truncation at max depth")
+	}
+
+	result, err = s.handleGetLinkedDocuments(context.Background(), map[string]interface{}{"document_id": float64(1), "max_depth": float64(3)})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	out = result.(map[string]interface{})
+	linked := out["documents"].([]linkedDocument)
+	if len(linked) != 2 || linked[1].ID != 3 || linked[1].Depth != 2 {
+		t.Errorf("Expected the full chain, got %+v", linked)
+	}
+	if out["truncated"] != false {
+		t.Error("Expected a complete traversal")
+	}
+	if linked[0].Preview != "" {
+		t.Errorf("Expected no preview unless asked for, got %q", linked[0].Preview)
+	}
+
+	result, err = s.handleGetLinkedDocuments(context.Background(), map[string]interface{}{"document_id": float64(1), "preview_chars": float64(16)})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	linked = result.(map[string]interface{})["documents"].([]linkedDocument)
+	if len(linked) != 1 || linked[0].Preview != "First amendment…" {
+		t.Errorf("Expected a preview of document 2, got %+v", linked)
+	}
+}
@@ -0,0 +1,48 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// Orderings accepted by the metadata list tools, each optionally prefixed
+// with - for descending
+var (
+	metadataOrderings      = []string{"name", "document_count"}
+	correspondentOrderings = []string{"name", "document_count", "last_correspondence"}
+)
+
+// parseListOptions reads the optional name filter and ordering arguments
+// of a metadata list tool, rejecting orderings not in allowed
+func parseListOptions(args map[string]interface{}, allowed []string) (paperless.ListOptions, error) {
+	var opts paperless.ListOptions
+	opts.Name, _ = args["name"].(string)
+
+	ordering, _ := args["ordering"].(string)
+	if ordering == "" {
+		return opts, nil
+	}
+	field := strings.TrimPrefix(ordering, "-")
+	for _, allowedField := range allowed {
+		if field == allowedField {
+			opts.Ordering = ordering
+			return opts, nil
+		}
+	}
+	return opts, fmt.Errorf("ordering must be one of %s, optionally prefixed with - for descending", strings.Join(allowed, ", "))
+}
+
+// listOptionFilters reports the name filter and ordering that were set, as
+// the applied_filters of a list result
+func listOptionFilters(opts paperless.ListOptions) map[string]interface{} {
+	filters := map[string]interface{}{}
+	if opts.Name != "" {
+		filters["name"] = opts.Name
+	}
+	if opts.Ordering != "" {
+		filters["ordering"] = opts.Ordering
+	}
+	return filters
+}
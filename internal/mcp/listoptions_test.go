@@ -0,0 +1,18 @@
+package mcp
+
+import "testing"
+
+// TestParseListOptions tests that only the tool's orderings are accepted,
+// in either direction
+func TestParseListOptions(t *testing.T) {
+	opts, err := parseListOptions(map[string]interface{}{"name": "bell", "ordering": "-document_count"}, metadataOrderings)
+	if err != nil || opts.Name != "bell" || opts.Ordering != "-document_count" {
+		t.Errorf("Expected name and descending ordering, got %+v, %v", opts, err)
+	}
+	if _, err := parseListOptions(map[string]interface{}{"ordering": "last_correspondence"}, metadataOrderings); err == nil {
+		t.Errorf("Expected last_correspondence to be rejected for tags")
+	}
+	if _, err := parseListOptions(map[string]interface{}{"ordering": "last_correspondence"}, correspondentOrderings); err != nil {
+		t.Errorf("Expected last_correspondence to be accepted for correspondents, got %v", err)
+	}
+}
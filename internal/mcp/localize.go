@@ -0,0 +1,113 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/config"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// LocalizedTimeFormat is how timestamps are written in localized text results
+const LocalizedTimeFormat = "2006-01-02 15:04:05 MST"
+
+// sizeFields are result fields holding a file size in bytes
+var sizeFields = map[string]bool{
+	"original_size": true,
+	"archive_size":  true,
+	"size":          true,
+}
+
+// textLocalizer rewrites the text fallback of tool results for people
+// reading it: timestamps in the configured time zone and file sizes in
+// human units. Structured content keeps the raw values for programs. A nil
+// localizer leaves results alone.
+type textLocalizer struct {
+	location *time.Location
+	binary   bool
+	sizes    bool
+}
+
+// newTextLocalizer returns the localizer configured by MCP_TIMEZONE and
+// MCP_SIZE_UNITS, or nil when neither is set
+func newTextLocalizer(cfg *config.Config) *textLocalizer {
+	if cfg.MCPTimezone == nil && cfg.MCPSizeUnits == "" {
+		return nil
+	}
+	return &textLocalizer{
+		location: cfg.MCPTimezone,
+		binary:   cfg.MCPSizeUnits == "binary",
+		sizes:    cfg.MCPSizeUnits != "",
+	}
+}
+
+// apply replaces the JSON text fallback of result with a localized copy
+func (l *textLocalizer) apply(result *mcp.CallToolResult) {
+	if l == nil || result.StructuredContent == nil || len(result.Content) == 0 {
+		return
+	}
+	text, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		return
+	}
+
+	var value interface{}
+	if err := json.Unmarshal([]byte(text.Text), &value); err != nil {
+		return
+	}
+	localized, err := json.Marshal(l.localize("", value))
+	if err != nil {
+		return
+	}
+	text.Text = string(localized)
+	result.Content[0] = text
+}
+
+// localize rewrites the timestamps and sizes in value, the JSON decoded
+// field named key
+func (l *textLocalizer) localize(key string, value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, field := range v {
+			v[k] = l.localize(k, field)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = l.localize(key, item)
+		}
+	case string:
+		if l.location == nil {
+			return v
+		}
+		if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return t.In(l.location).Format(LocalizedTimeFormat)
+		}
+	case float64:
+		if l.sizes && sizeFields[key] {
+			return humanSize(int64(v), l.binary)
+		}
+	}
+	return value
+}
+
+// humanSize formats a number of bytes such as "2.4 MB", or "2.3 MiB" in
+// binary units
+func humanSize(bytes int64, binary bool) string {
+	base, units := 1000.0, []string{"B", "kB", "MB", "GB", "TB", "PB"}
+	if binary {
+		base, units = 1024.0, []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB"}
+	}
+
+	size := float64(bytes)
+	i := 0
+	for math.Abs(size) >= base && i < len(units)-1 {
+		size /= base
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	return fmt.Sprintf("%.1f %s", size, units[i])
+}
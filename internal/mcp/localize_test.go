@@ -0,0 +1,72 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/config"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TestTextLocalizer tests that the text fallback shows timestamps in the
+// configured zone and human sizes while structured content stays raw
+func TestTextLocalizer(t *testing.T) {
+	if newTextLocalizer(&config.Config{}) != nil {
+		t.Error("Expected no localizer without MCP_TIMEZONE or MCP_SIZE_UNITS")
+	}
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("Time zone database unavailable: %v", err)
+	}
+	l := newTextLocalizer(&config.Config{MCPTimezone: berlin, MCPSizeUnits: "decimal"})
+
+	raw := map[string]interface{}{
+		"created":       "2024-03-01",
+		"modified":      "2024-03-01T13:05:00.123456Z",
+		"original_size": 2400000,
+		"page_size":     25,
+		"notes":         []interface{}{map[string]interface{}{"created": "2024-07-01T08:00:00+00:00"}},
+	}
+	result := newStructuredToolResult(raw)
+	l.apply(result)
+
+	var text map[string]interface{}
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &text); err != nil {
+		t.Fatalf("Text fallback is not JSON: %v", err)
+	}
+	if text["modified"] != "2024-03-01 14:05:00 CET" || text["created"] != "2024-03-01" {
+		t.Errorf("Unexpected timestamps: %v, %v", text["modified"], text["created"])
+	}
+	if note := text["notes"].([]interface{})[0].(map[string]interface{}); note["created"] != "2024-07-01 10:00:00 CEST" {
+		t.Errorf("Expected nested timestamps localized, got %v", note["created"])
+	}
+	if text["original_size"] != "2.4 MB" || text["page_size"] != float64(25) {
+		t.Errorf("Unexpected sizes: %v, %v", text["original_size"], text["page_size"])
+	}
+
+	structured := result.StructuredContent.(map[string]interface{})
+	if structured["modified"] != "2024-03-01T13:05:00.123456Z" || structured["original_size"] != 2400000 {
+		t.Errorf("Expected structured content to keep raw values, got %v", structured)
+	}
+}
+
+// TestHumanSize tests decimal and binary size formatting
+func TestHumanSize(t *testing.T) {
+	tests := []struct {
+		bytes  int64
+		binary bool
+		want   string
+	}{
+		{512, false, "512 B"},
+		{2400000, false, "2.4 MB"},
+		{1536, true, "1.5 KiB"},
+		{5 << 30, true, "5.0 GiB"},
+	}
+	for _, tt := range tests {
+		if got := humanSize(tt.bytes, tt.binary); got != tt.want {
+			t.Errorf("humanSize(%d, %v) = %q, want %q", tt.bytes, tt.binary, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// Sources of the subject in a document's mail provenance
+const (
+	mailSubjectFromEmail = "email"
+	mailSubjectFromTitle = "title"
+)
+
+// mailRules returns the mail rules visible to the caller, or nil when they
+// cannot be read; listing them needs mail permissions many tokens lack, so
+// a failure only leaves provenance incomplete
+func (s *Server) mailRules(ctx context.Context) []paperless.MailRule {
+	rules, err := s.metadata.mailRules.get(ctx, s.paperlessClient)
+	if err != nil {
+		logging.FromContext(ctx).Debug("Failed to list mail rules", "error", err)
+		return nil
+	}
+	return rules
+}
+
+// attributeMailRule returns the mail rule that most likely consumed a
+// document: of the rules whose assigned tags the document all carries, the
+// one assigning the most, earlier rules winning ties as Paperless applies
+// rules in order. Rules assigning no tags cannot be told apart and are
+// never attributed.
+func attributeMailRule(document *paperless.Document, rules []paperless.MailRule) *paperless.MailRule {
+	tags := make(map[int]bool, len(document.Tags))
+	for _, tag := range document.Tags {
+		tags[tag] = true
+	}
+
+	var best *paperless.MailRule
+	for i := range rules {
+		rule := &rules[i]
+		if len(rule.AssignTags) == 0 || best != nil && len(rule.AssignTags) <= len(best.AssignTags) {
+			continue
+		}
+		matched := true
+		for _, tag := range rule.AssignTags {
+			if !tags[tag] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			best = rule
+		}
+	}
+	return best
+}
+
+// mailProvenance describes how a document arrived by e-mail, from the
+// headers Paperless extracted when the e-mail itself was consumed and the
+// mail rule attributed to it. It returns nil for documents with neither.
+func (s *Server) mailProvenance(ctx context.Context, document *paperless.Document, metadata *paperless.DocumentMetadata, rules []paperless.MailRule) *paperless.MailProvenance {
+	provenance := &paperless.MailProvenance{}
+	for _, entry := range metadata.OriginalMetadata {
+		if entry.Prefix != "header" {
+			continue
+		}
+		switch strings.ToLower(entry.Key) {
+		case "subject":
+			provenance.Subject, provenance.SubjectSource = entry.Value, mailSubjectFromEmail
+		case "from":
+			provenance.From = entry.Value
+		}
+	}
+
+	if rule := attributeMailRule(document, rules); rule != nil {
+		provenance.MailRule = rule.ID
+		provenance.MailRuleName = rule.Name
+		provenance.MailAccount = rule.Account
+		provenance.Basis = fmt.Sprintf("carries every tag the rule assigns (%s)", joinInts(rule.AssignTags))
+		if provenance.Subject == "" && rule.AssignTitleFrom == paperless.MailTitleFromSubject {
+			provenance.Subject, provenance.SubjectSource = document.Title, mailSubjectFromTitle
+		}
+		if accounts, err := s.metadata.mailAccounts.get(ctx, s.paperlessClient); err == nil {
+			for _, account := range accounts {
+				if account.ID == rule.Account {
+					provenance.MailAccountName = account.Name
+				}
+			}
+		}
+	}
+
+	if *provenance == (paperless.MailProvenance{}) {
+		return nil
+	}
+	return provenance
+}
+
+// joinInts formats IDs as a comma separated list
+func joinInts(ids []int) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.Itoa(id)
+	}
+	return strings.Join(parts, ",")
+}
+
+// mailRuleSummary is a mail rule as listed by list_mail_rules
+type mailRuleSummary struct {
+	ID                  int    `json:"id"`
+	Name                string `json:"name"`
+	Enabled             bool   `json:"enabled"`
+	Account             int    `json:"account"`
+	AccountName         string `json:"account_name,omitempty"`
+	Folder              string `json:"folder"`
+	AssignTags          []int  `json:"assign_tags"`
+	AssignCorrespondent *int   `json:"assign_correspondent,omitempty"`
+	AssignDocumentType  *int   `json:"assign_document_type,omitempty"`
+	// Filterable reports whether get_mail_rule_documents can find the
+	// rule's documents, which needs the rule to assign a tag
+	Filterable bool `json:"filterable"`
+}
+
+// handleListMailRules handles the list_mail_rules tool
+func (s *Server) handleListMailRules(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	logging.FromContext(ctx).Debug("Listing mail rules")
+
+	rules, err := s.metadata.mailRules.get(ctx, s.paperlessClient)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to list mail rules", "error", err)
+		return nil, fmt.Errorf("failed to list mail rules: %w", err)
+	}
+	accountNames := make(map[int]string)
+	if accounts, err := s.metadata.mailAccounts.get(ctx, s.paperlessClient); err != nil {
+		logging.FromContext(ctx).Warn("Failed to list mail accounts", "error", err)
+	} else {
+		for _, account := range accounts {
+			accountNames[account.ID] = account.Name
+		}
+	}
+
+	summaries := make([]mailRuleSummary, len(rules))
+	for i, rule := range rules {
+		summaries[i] = mailRuleSummary{
+			ID:                  rule.ID,
+			Name:                rule.Name,
+			Enabled:             rule.Enabled,
+			Account:             rule.Account,
+			AccountName:         accountNames[rule.Account],
+			Folder:              rule.Folder,
+			AssignTags:          rule.AssignTags,
+			AssignCorrespondent: rule.AssignCorrespondent,
+			AssignDocumentType:  rule.AssignDocumentType,
+			Filterable:          len(rule.AssignTags) > 0,
+		}
+	}
+
+	logging.FromContext(ctx).Info("Mail rules listed successfully", "count", len(summaries))
+
+	return newListResult(summaries, len(summaries), 1, len(summaries), nil), nil
+}
+
+// handleGetMailRuleDocuments handles the get_mail_rule_documents tool
+func (s *Server) handleGetMailRuleDocuments(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	// Extract and validate mail_rule_id
+	ruleIDFloat, ok := args["mail_rule_id"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("mail_rule_id parameter is required and must be an integer")
+	}
+	ruleID := int(ruleIDFloat)
+	if ruleID < 1 {
+		return nil, fmt.Errorf("mail_rule_id must be a positive integer")
+	}
+
+	// Extract optional page parameter
+	page := DefaultPage
+	if pageVal, ok := args["page"].(float64); ok {
+		page = int(pageVal)
+		if page < 1 {
+			page = DefaultPage
+		}
+	}
+
+	// Extract optional page_size parameter
+	pageSize := DefaultPageSize
+	if pageSizeVal, ok := args["page_size"].(float64); ok {
+		pageSize = int(pageSizeVal)
+		if pageSize < 1 {
+			pageSize = DefaultPageSize
+		} else if pageSize > MaxPageSize {
+			pageSize = MaxPageSize
+		}
+	}
+
+	previewChars, err := previewCharsArg(args)
+	if err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Debug("Getting mail rule documents",
+		"mail_rule_id", ruleID,
+		"page", page,
+		"page_size", pageSize)
+
+	rules, err := s.metadata.mailRules.get(ctx, s.paperlessClient)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to list mail rules", "error", err)
+		return nil, fmt.Errorf("failed to list mail rules: %w", err)
+	}
+	var rule *paperless.MailRule
+	for i := range rules {
+		if rules[i].ID == ruleID {
+			rule = &rules[i]
+		}
+	}
+	if rule == nil {
+		return nil, fmt.Errorf("mail rule %d not found", ruleID)
+	}
+
+	// Paperless does not record the rule on the document, so the rule's
+	// documents are the ones carrying every tag it assigns
+	if len(rule.AssignTags) == 0 {
+		return nil, fmt.Errorf("mail rule %q assigns no tags, so its documents cannot be told apart; have the rule assign a tag in Paperless to track them", rule.Name)
+	}
+	filters := url.Values{}
+	filters.Set("tags__id__all", joinInts(rule.AssignTags))
+
+	response, err := s.paperlessClient.ListDocuments(ctx, filters, page, pageSize)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to get mail rule documents",
+			"mail_rule_id", ruleID,
+			"error", err)
+		return nil, fmt.Errorf("failed to get mail rule documents: %w", err)
+	}
+
+	var documents []paperless.Document
+	if err := json.Unmarshal(response.Results, &documents); err != nil {
+		logging.FromContext(ctx).Error("Failed to parse documents results", "error", err)
+		return nil, fmt.Errorf("failed to parse results: %w", err)
+	}
+
+	logging.FromContext(ctx).Info("Mail rule documents retrieved",
+		"mail_rule_id", ruleID,
+		"found", response.Count,
+		"returned", len(documents))
+
+	var items interface{} = documents
+	if previewChars > 0 {
+		items = previewDocuments(documents, nil, previewChars)
+	}
+	return newListResult(items, response.Count, page, pageSize, map[string]interface{}{
+		"mail_rule_id":  ruleID,
+		"tags__id__all": rule.AssignTags,
+	}), nil
+}
@@ -0,0 +1,170 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// mailTestServer serves two mail rules, their account and a statement
+// consumed as .eml by the "Bank" rule
+func mailTestServer(t *testing.T) *httptest.Server {
+	page := func(results interface{}) map[string]interface{} {
+		return map[string]interface{}{"count": 1, "next": nil, "results": results}
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body interface{}
+		switch r.URL.Path {
+		case "/api/mail_rules/":
+			body = page([]interface{}{
+				map[string]interface{}{"id": 1, "name": "Inbox", "account": 4, "assign_tags": []int{10}, "order": 0},
+				map[string]interface{}{"id": 2, "name": "Bank", "account": 4, "assign_tags": []int{10, 11}, "assign_title_from": 1, "order": 1},
+				map[string]interface{}{"id": 3, "name": "Untagged", "account": 4, "assign_tags": []int{}, "order": 2},
+			})
+		case "/api/mail_accounts/":
+			body = page([]interface{}{map[string]interface{}{"id": 4, "name": "Personal", "username": "me@example.com"}})
+		case "/api/documents/7/":
+			body = map[string]interface{}{"id": 7, "title": "March statement", "tags": []int{10, 11, 12}}
+		case "/api/documents/7/metadata/":
+			body = map[string]interface{}{"original_size": 2048, "original_metadata": []interface{}{
+				map[string]interface{}{"namespace": "", "prefix": "header", "key": "Subject", "value": "Your March statement"},
+				map[string]interface{}{"namespace": "", "prefix": "header", "key": "From", "value": "Bank <noreply@bank.example>"},
+				map[string]interface{}{"namespace": "", "prefix": "", "key": "attachments", "value": "statement.pdf"},
+			}}
+		case "/api/documents/":
+			if got := r.URL.Query().Get("tags__id__all"); got != "10,11" {
+				t.Errorf("Expected the rule's tags as filter, got %q", got)
+			}
+			body = page([]interface{}{map[string]interface{}{"id": 7, "title": "March statement", "content": "Statement for March, balance 120.00"}})
+		default:
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+		json.NewEncoder(w).Encode(body)
+	}))
+}
+
+// TestAttributeMailRule tests that the rule assigning the most of a
+// document's tags wins and rules assigning none are never attributed
+func TestAttributeMailRule(t *testing.T) {
+	rules := []paperless.MailRule{
+		{ID: 1, AssignTags: []int{10}},
+		{ID: 2, AssignTags: []int{10, 11}},
+		{ID: 3, AssignTags: []int{20, 21}},
+		{ID: 4, AssignTags: []int{10, 12}},
+		{ID: 5},
+	}
+	for _, tc := range []struct {
+		tags []int
+		want int
+	}{
+		{[]int{10, 11, 12}, 2}, // rules 2 and 4 tie, the earlier wins
+		{[]int{10}, 1},
+		{[]int{20}, 0},
+		{nil, 0},
+	} {
+		rule := attributeMailRule(&paperless.Document{Tags: tc.tags}, rules)
+		got := 0
+		if rule != nil {
+			got = rule.ID
+		}
+		if got != tc.want {
+			t.Errorf("Tags %v: expected rule %d, got %d", tc.tags, tc.want, got)
+		}
+	}
+}
+
+// TestGetDocumentMailProvenance tests that get_document reports the mail
+// rule, account and e-mail headers of a document that arrived by e-mail
+func TestGetDocumentMailProvenance(t *testing.T) {
+	ts := mailTestServer(t)
+	defer ts.Close()
+
+	s := &Server{
+		paperlessClient: paperless.New(ts.URL, "test-token"),
+		documents:       newDocumentCache(10, time.Minute),
+	}
+
+	document, err := s.getDocument(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := paperless.MailProvenance{
+		MailRule:        2,
+		MailRuleName:    "Bank",
+		MailAccount:     4,
+		MailAccountName: "Personal",
+		Basis:           "carries every tag the rule assigns (10,11)",
+		Subject:         "Your March statement",
+		SubjectSource:   mailSubjectFromEmail,
+		From:            "Bank <noreply@bank.example>",
+	}
+	if document.MailProvenance == nil || *document.MailProvenance != want {
+		t.Errorf("Expected provenance %+v, got %+v", want, document.MailProvenance)
+	}
+
+	// Without the e-mail headers, a rule titling documents by subject
+	// still gives the subject
+	provenance := s.mailProvenance(context.Background(), &paperless.Document{Title: "Statement", Tags: []int{10, 11}},
+		&paperless.DocumentMetadata{}, []paperless.MailRule{{ID: 2, Name: "Bank", AssignTags: []int{10, 11}, AssignTitleFrom: paperless.MailTitleFromSubject}})
+	if provenance == nil || provenance.Subject != "Statement" || provenance.SubjectSource != mailSubjectFromTitle {
+		t.Errorf("Expected the title as subject, got %+v", provenance)
+	}
+	if provenance := s.mailProvenance(context.Background(), &paperless.Document{Tags: []int{1}}, &paperless.DocumentMetadata{}, nil); provenance != nil {
+		t.Errorf("Expected no provenance for a document that did not arrive by e-mail, got %+v", provenance)
+	}
+}
+
+// TestGetMailRuleDocuments tests that a rule's documents are listed by the
+// tags it assigns and that rules assigning none are refused
+func TestGetMailRuleDocuments(t *testing.T) {
+	ts := mailTestServer(t)
+	defer ts.Close()
+
+	s := &Server{paperlessClient: paperless.New(ts.URL, "test-token")}
+
+	result, err := s.handleGetMailRuleDocuments(context.Background(), map[string]interface{}{"mail_rule_id": float64(2)})
+	if err != nil {
+		t.Fatalf("get_mail_rule_documents failed: %v", err)
+	}
+	list := result.(*ListResult)
+	if documents := list.Items.([]paperless.Document); len(documents) != 1 || documents[0].ID != 7 {
+		t.Errorf("Unexpected documents %+v", list.Items)
+	}
+
+	result, err = s.handleGetMailRuleDocuments(context.Background(), map[string]interface{}{"mail_rule_id": float64(2), "preview_chars": float64(10)})
+	if err != nil {
+		t.Fatalf("get_mail_rule_documents with preview_chars failed: %v", err)
+	}
+	list = result.(*ListResult)
+	if previews := list.Items.([]documentPreview); len(previews) != 1 || previews[0].Preview != "Statement…" || previews[0].Content != "" {
+		t.Errorf("Expected a preview in place of the content, got %+v", list.Items)
+	}
+
+	for _, args := range []map[string]interface{}{
+		{"mail_rule_id": float64(3)},
+		{"mail_rule_id": float64(99)},
+		{},
+	} {
+		if _, err := s.handleGetMailRuleDocuments(context.Background(), args); err == nil {
+			t.Errorf("Expected %v to fail", args)
+		}
+	}
+
+	rules, err := s.handleListMailRules(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("list_mail_rules failed: %v", err)
+	}
+	list = rules.(*ListResult)
+	summaries := list.Items.([]mailRuleSummary)
+	if list.Count != 3 || list.TotalPages != 1 || list.NextPage != nil {
+		t.Errorf("Expected all mail rules on one page, got %+v", list)
+	}
+	if len(summaries) != 3 || summaries[1].AccountName != "Personal" || !summaries[1].Filterable || summaries[2].Filterable {
+		t.Errorf("Unexpected mail rules %+v", summaries)
+	}
+}
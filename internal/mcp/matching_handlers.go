@@ -0,0 +1,92 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// handleTestMatchingRule handles the test_matching_rule tool
+func (s *Server) handleTestMatchingRule(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	match, _ := args["match"].(string)
+
+	algorithmFloat, ok := args["matching_algorithm"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("matching_algorithm parameter is required and must be an integer")
+	}
+	algorithm := int(algorithmFloat)
+	algorithmName, known := paperless.MatchingAlgorithmNames[algorithm]
+	if !known {
+		return nil, fmt.Errorf("matching_algorithm must be between %d and %d", paperless.MatchNone, paperless.MatchAuto)
+	}
+
+	// Paperless creates rules case-insensitive by default
+	insensitive := true
+	if v, ok := args["is_insensitive"].(bool); ok {
+		insensitive = v
+	}
+
+	text, hasText := args["text"].(string)
+	documentIDFloat, hasDocument := args["document_id"].(float64)
+	if hasText == hasDocument {
+		return nil, fmt.Errorf("provide either text or document_id")
+	}
+
+	result := map[string]interface{}{
+		"match":              match,
+		"matching_algorithm": algorithm,
+		"algorithm_name":     algorithmName,
+		"is_insensitive":     insensitive,
+	}
+
+	if hasDocument {
+		documentID := int(documentIDFloat)
+		if documentID < 1 {
+			return nil, fmt.Errorf("document_id must be a positive integer")
+		}
+		document, err := s.getDocument(ctx, documentID)
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to get document content",
+				"document_id", documentID,
+				"error", err)
+			return nil, fmt.Errorf("failed to get document content: %w", err)
+		}
+		text = document.Content
+		result["document_id"] = documentID
+	}
+
+	logging.FromContext(ctx).Debug("Testing matching rule",
+		"algorithm", algorithmName,
+		"insensitive", insensitive,
+		"text_length", len(text))
+
+	matched, terms, err := paperless.MatchText(match, algorithm, insensitive, text)
+	if errors.Is(err, paperless.ErrMatchNotEvaluable) {
+		result["evaluable"] = false
+		result["reason"] = err.Error()
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Info("Matching rule tested",
+		"algorithm", algorithmName,
+		"matches", matched)
+
+	result["evaluable"] = true
+	result["matches"] = matched
+	if algorithm == paperless.MatchAny || algorithm == paperless.MatchAll {
+		if terms == nil {
+			terms = []string{}
+		}
+		result["matched_terms"] = terms
+	}
+	if algorithm == paperless.MatchRegex {
+		result["note"] = "regular expressions are evaluated with Go syntax, which may differ from Python's in edge cases"
+	}
+	return result, nil
+}
@@ -0,0 +1,490 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// MetadataSnapshotVersion is the current metadata snapshot format version
+const MetadataSnapshotVersion = 1
+
+// MetadataSnapshot is a point-in-time export of the Paperless taxonomy,
+// suitable for versioning, diffing and restoring with import_metadata
+type MetadataSnapshot struct {
+	Version        int                       `json:"version"`
+	ExportedAt     time.Time                 `json:"exported_at"`
+	PaperlessURL   string                    `json:"paperless_url"`
+	Tags           []paperless.Tag           `json:"tags"`
+	Correspondents []paperless.Correspondent `json:"correspondents"`
+	DocumentTypes  []paperless.DocumentType  `json:"document_types"`
+	StoragePaths   []paperless.StoragePath   `json:"storage_paths"`
+	CustomFields   []paperless.CustomField   `json:"custom_fields"`
+	SavedViews     []paperless.SavedView     `json:"saved_views"`
+}
+
+// counts summarises how many objects of each kind a snapshot holds
+func (m *MetadataSnapshot) counts() map[string]int {
+	return map[string]int{
+		"tags":           len(m.Tags),
+		"correspondents": len(m.Correspondents),
+		"document_types": len(m.DocumentTypes),
+		"storage_paths":  len(m.StoragePaths),
+		"custom_fields":  len(m.CustomFields),
+		"saved_views":    len(m.SavedViews),
+	}
+}
+
+// exportMetadata walks every metadata list endpoint with full pagination
+func (s *Server) exportMetadata(ctx context.Context) (*MetadataSnapshot, error) {
+	client := s.paperlessClient
+	snapshot := &MetadataSnapshot{
+		Version:      MetadataSnapshotVersion,
+		ExportedAt:   time.Now().UTC(),
+		PaperlessURL: s.cfg.PaperlessURL,
+	}
+
+	var err error
+	if snapshot.Tags, err = paperless.CollectAll[paperless.Tag](ctx, client.ListTags); err != nil {
+		return nil, fmt.Errorf("failed to export tags: %w", err)
+	}
+	if snapshot.Correspondents, err = paperless.CollectAll[paperless.Correspondent](ctx, client.ListCorrespondents); err != nil {
+		return nil, fmt.Errorf("failed to export correspondents: %w", err)
+	}
+	if snapshot.DocumentTypes, err = paperless.CollectAll[paperless.DocumentType](ctx, client.ListDocumentTypes); err != nil {
+		return nil, fmt.Errorf("failed to export document types: %w", err)
+	}
+	if snapshot.StoragePaths, err = paperless.CollectAll[paperless.StoragePath](ctx, client.ListStoragePaths); err != nil {
+		return nil, fmt.Errorf("failed to export storage paths: %w", err)
+	}
+	if snapshot.CustomFields, err = paperless.CollectAll[paperless.CustomField](ctx, client.ListCustomFields); err != nil {
+		return nil, fmt.Errorf("failed to export custom fields: %w", err)
+	}
+	if snapshot.SavedViews, err = paperless.CollectAll[paperless.SavedView](ctx, client.ListSavedViews); err != nil {
+		return nil, fmt.Errorf("failed to export saved views: %w", err)
+	}
+
+	return snapshot, nil
+}
+
+// streamMetadata sends a metadata snapshot as partial results, one page of
+// one kind at a time, instead of returning it whole. Each kind is streamed
+// under its snapshot key, so the chunks reassemble into a snapshot.
+func (s *Server) streamMetadata(ctx context.Context, stream *resultStream) (interface{}, error) {
+	logging.FromContext(ctx).Debug("Streaming metadata snapshot", "chunk_size", stream.chunkSize)
+
+	client := s.paperlessClient
+	exportedAt := time.Now().UTC()
+	kinds := []struct {
+		name   string
+		stream func() (int, error)
+	}{
+		{"tags", func() (int, error) { return streamList[paperless.Tag](ctx, stream, "tags", client.ListTags) }},
+		{"correspondents", func() (int, error) {
+			return streamList[paperless.Correspondent](ctx, stream, "correspondents", client.ListCorrespondents)
+		}},
+		{"document_types", func() (int, error) {
+			return streamList[paperless.DocumentType](ctx, stream, "document_types", client.ListDocumentTypes)
+		}},
+		{"storage_paths", func() (int, error) {
+			return streamList[paperless.StoragePath](ctx, stream, "storage_paths", client.ListStoragePaths)
+		}},
+		{"custom_fields", func() (int, error) {
+			return streamList[paperless.CustomField](ctx, stream, "custom_fields", client.ListCustomFields)
+		}},
+		{"saved_views", func() (int, error) {
+			return streamList[paperless.SavedView](ctx, stream, "saved_views", client.ListSavedViews)
+		}},
+	}
+
+	counts := make(map[string]int, len(kinds))
+	for _, kind := range kinds {
+		n, err := kind.stream()
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to stream metadata", "kind", kind.name, "error", err)
+			return nil, fmt.Errorf("failed to export %s: %w", strings.ReplaceAll(kind.name, "_", " "), err)
+		}
+		counts[kind.name] = n
+	}
+
+	logging.FromContext(ctx).Info("Metadata streamed successfully",
+		"counts", counts,
+		"chunks", stream.chunks)
+
+	return map[string]interface{}{
+		"version":       MetadataSnapshotVersion,
+		"exported_at":   exportedAt,
+		"paperless_url": s.cfg.PaperlessURL,
+		"counts":        counts,
+		"streamed":      stream.summary(),
+	}, nil
+}
+
+// handleExportMetadata handles the export_metadata tool
+func (s *Server) handleExportMetadata(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	outputPath, _ := args["output_path"].(string)
+	if outputPath != "" {
+		checked, err := s.checkFilePath(ctx, "output_path", outputPath)
+		if err != nil {
+			return nil, err
+		}
+		outputPath = checked
+	}
+
+	stream, err := s.newResultStream(ctx, "export_metadata", args)
+	if err != nil {
+		return nil, err
+	}
+	if stream != nil {
+		if outputPath != "" {
+			return nil, fmt.Errorf("stream and output_path cannot be combined")
+		}
+		return s.streamMetadata(ctx, stream)
+	}
+
+	logging.FromContext(ctx).Debug("Exporting metadata snapshot", "output_path", outputPath)
+
+	snapshot, err := s.exportMetadata(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to export metadata", "error", err)
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Info("Metadata exported successfully", "counts", snapshot.counts())
+
+	if outputPath == "" {
+		return snapshot, nil
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	if err := os.WriteFile(outputPath, data, 0o644); err != nil {
+		logging.FromContext(ctx).Error("Failed to write metadata snapshot", "output_path", outputPath, "error", err)
+		return nil, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	return map[string]interface{}{
+		"success":     true,
+		"output_path": outputPath,
+		"exported_at": snapshot.ExportedAt,
+		"counts":      snapshot.counts(),
+	}, nil
+}
+
+// importEntry is a snapshot object to be matched against Paperless by name
+type importEntry struct {
+	Name   string
+	Fields map[string]interface{}
+	Create func(ctx context.Context) (int, error)
+}
+
+// existingEntry is an object already present in Paperless
+type existingEntry struct {
+	ID     int
+	Name   string
+	Fields map[string]interface{}
+}
+
+// fieldChange describes a single differing field
+type fieldChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// importChange describes an existing object whose fields differ from the snapshot
+type importChange struct {
+	ID      int                    `json:"id"`
+	Name    string                 `json:"name"`
+	Changes map[string]fieldChange `json:"changes"`
+	Applied bool                   `json:"applied"`
+}
+
+// importReport summarises the import of one kind of object
+type importReport struct {
+	Created   []string       `json:"created"`
+	Changed   []importChange `json:"changed"`
+	Unchanged int            `json:"unchanged"`
+	Errors    []string       `json:"errors,omitempty"`
+}
+
+// importKind creates snapshot entries missing from Paperless (matched by
+// case-insensitive name, so nothing is ever duplicated) and reports or
+// applies field differences for entries that already exist
+func importKind(ctx context.Context, desired []importEntry, existing []existingEntry,
+	update func(ctx context.Context, id int, updates map[string]interface{}) error,
+	dryRun, updateExisting bool) *importReport {
+
+	report := &importReport{Created: []string{}, Changed: []importChange{}}
+
+	byName := make(map[string]existingEntry, len(existing))
+	for _, entry := range existing {
+		byName[strings.ToLower(entry.Name)] = entry
+	}
+
+	for _, entry := range desired {
+		current, found := byName[strings.ToLower(entry.Name)]
+		if !found {
+			// A dry run creates nothing, so the entry gets no ID yet
+			id := 0
+			if !dryRun {
+				var err error
+				if id, err = entry.Create(ctx); err != nil {
+					report.Errors = append(report.Errors, fmt.Sprintf("create %q: %v", entry.Name, err))
+					continue
+				}
+			}
+			// Guard against duplicate names within the snapshot itself, in a
+			// dry run too so it reports what a real run would do
+			byName[strings.ToLower(entry.Name)] = existingEntry{ID: id, Name: entry.Name, Fields: entry.Fields}
+			report.Created = append(report.Created, entry.Name)
+			continue
+		}
+
+		changes := make(map[string]fieldChange)
+		updates := make(map[string]interface{})
+		for field, want := range entry.Fields {
+			if have := current.Fields[field]; !reflect.DeepEqual(have, want) {
+				changes[field] = fieldChange{From: have, To: want}
+				updates[field] = want
+			}
+		}
+		if len(changes) == 0 {
+			report.Unchanged++
+			continue
+		}
+
+		change := importChange{ID: current.ID, Name: current.Name, Changes: changes}
+		if !dryRun && updateExisting && update != nil {
+			if err := update(ctx, current.ID, updates); err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("update %q: %v", current.Name, err))
+			} else {
+				change.Applied = true
+			}
+		}
+		report.Changed = append(report.Changed, change)
+	}
+
+	return report
+}
+
+// matchingFields returns the auto-matching fields shared by most metadata types
+func matchingFields(match string, algorithm int, insensitive bool) map[string]interface{} {
+	return map[string]interface{}{
+		"match":              match,
+		"matching_algorithm": algorithm,
+		"is_insensitive":     insensitive,
+	}
+}
+
+// loadSnapshot reads a snapshot from the snapshot or input_path arguments,
+// the file being within the client's roots when it advertises any
+func (s *Server) loadSnapshot(ctx context.Context, args map[string]interface{}) (*MetadataSnapshot, error) {
+	var data []byte
+	if inline, ok := args["snapshot"].(map[string]interface{}); ok {
+		encoded, err := json.Marshal(inline)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot: %w", err)
+		}
+		data = encoded
+	} else if inputPath, ok := args["input_path"].(string); ok && inputPath != "" {
+		inputPath, err := s.checkFilePath(ctx, "input_path", inputPath)
+		if err != nil {
+			return nil, err
+		}
+		fileData, err := os.ReadFile(inputPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot file: %w", err)
+		}
+		data = fileData
+	} else {
+		return nil, fmt.Errorf("snapshot or input_path is required")
+	}
+
+	var snapshot MetadataSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot: %w", err)
+	}
+	if snapshot.Version != MetadataSnapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d, expected %d", snapshot.Version, MetadataSnapshotVersion)
+	}
+	return &snapshot, nil
+}
+
+// handleImportMetadata handles the import_metadata tool
+func (s *Server) handleImportMetadata(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	snapshot, err := s.loadSnapshot(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	// Default to a dry run; changes are only made when explicitly requested
+	dryRun := true
+	if v, ok := args["dry_run"].(bool); ok {
+		dryRun = v
+	}
+	updateExisting, _ := args["update_existing"].(bool)
+
+	logging.FromContext(ctx).Debug("Importing metadata snapshot",
+		"dry_run", dryRun,
+		"update_existing", updateExisting,
+		"counts", snapshot.counts())
+
+	current, err := s.exportMetadata(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to read current metadata", "error", err)
+		return nil, err
+	}
+
+	client := s.paperlessClient
+	reports := make(map[string]*importReport)
+
+	// Tags
+	var desired []importEntry
+	var existing []existingEntry
+	tagFields := func(t paperless.Tag) map[string]interface{} {
+		fields := matchingFields(t.Match, t.MatchingAlgorithm, t.IsInsensitive)
+		fields["color"] = t.Color
+		fields["is_inbox_tag"] = t.IsInboxTag
+		return fields
+	}
+	for _, t := range snapshot.Tags {
+		tag := paperless.Tag{Name: t.Name, Color: t.Color, Match: t.Match, MatchingAlgorithm: t.MatchingAlgorithm,
+			IsInsensitive: t.IsInsensitive, IsInboxTag: t.IsInboxTag}
+		desired = append(desired, importEntry{Name: t.Name, Fields: tagFields(t), Create: func(ctx context.Context) (int, error) {
+			created, err := client.CreateTag(ctx, &tag)
+			if err != nil {
+				return 0, err
+			}
+			return created.ID, nil
+		}})
+	}
+	for _, t := range current.Tags {
+		existing = append(existing, existingEntry{ID: t.ID, Name: t.Name, Fields: tagFields(t)})
+	}
+	reports["tags"] = importKind(ctx, desired, existing, func(ctx context.Context, id int, updates map[string]interface{}) error {
+		_, err := client.UpdateTag(ctx, id, updates)
+		return err
+	}, dryRun, updateExisting)
+
+	// Correspondents
+	desired, existing = nil, nil
+	for _, c := range snapshot.Correspondents {
+		correspondent := paperless.Correspondent{Name: c.Name, Match: c.Match, MatchingAlgorithm: c.MatchingAlgorithm,
+			IsInsensitive: c.IsInsensitive}
+		desired = append(desired, importEntry{Name: c.Name, Fields: matchingFields(c.Match, c.MatchingAlgorithm, c.IsInsensitive),
+			Create: func(ctx context.Context) (int, error) {
+				created, err := client.CreateCorrespondent(ctx, &correspondent)
+				if err != nil {
+					return 0, err
+				}
+				return created.ID, nil
+			}})
+	}
+	for _, c := range current.Correspondents {
+		existing = append(existing, existingEntry{ID: c.ID, Name: c.Name, Fields: matchingFields(c.Match, c.MatchingAlgorithm, c.IsInsensitive)})
+	}
+	reports["correspondents"] = importKind(ctx, desired, existing, func(ctx context.Context, id int, updates map[string]interface{}) error {
+		_, err := client.UpdateCorrespondent(ctx, id, updates)
+		return err
+	}, dryRun, updateExisting)
+
+	// Document types
+	desired, existing = nil, nil
+	for _, d := range snapshot.DocumentTypes {
+		docType := paperless.DocumentType{Name: d.Name, Match: d.Match, MatchingAlgorithm: d.MatchingAlgorithm,
+			IsInsensitive: d.IsInsensitive}
+		desired = append(desired, importEntry{Name: d.Name, Fields: matchingFields(d.Match, d.MatchingAlgorithm, d.IsInsensitive),
+			Create: func(ctx context.Context) (int, error) {
+				created, err := client.CreateDocumentType(ctx, &docType)
+				if err != nil {
+					return 0, err
+				}
+				return created.ID, nil
+			}})
+	}
+	for _, d := range current.DocumentTypes {
+		existing = append(existing, existingEntry{ID: d.ID, Name: d.Name, Fields: matchingFields(d.Match, d.MatchingAlgorithm, d.IsInsensitive)})
+	}
+	reports["document_types"] = importKind(ctx, desired, existing, func(ctx context.Context, id int, updates map[string]interface{}) error {
+		_, err := client.UpdateDocumentType(ctx, id, updates)
+		return err
+	}, dryRun, updateExisting)
+
+	// Storage paths
+	desired, existing = nil, nil
+	storagePathFields := func(p paperless.StoragePath) map[string]interface{} {
+		fields := matchingFields(p.Match, p.MatchingAlgorithm, p.IsInsensitive)
+		fields["path"] = p.Path
+		return fields
+	}
+	for _, p := range snapshot.StoragePaths {
+		storagePath := paperless.StoragePath{Name: p.Name, Path: p.Path, Match: p.Match, MatchingAlgorithm: p.MatchingAlgorithm,
+			IsInsensitive: p.IsInsensitive}
+		desired = append(desired, importEntry{Name: p.Name, Fields: storagePathFields(p), Create: func(ctx context.Context) (int, error) {
+			created, err := client.CreateStoragePath(ctx, &storagePath)
+			if err != nil {
+				return 0, err
+			}
+			return created.ID, nil
+		}})
+	}
+	for _, p := range current.StoragePaths {
+		existing = append(existing, existingEntry{ID: p.ID, Name: p.Name, Fields: storagePathFields(p)})
+	}
+	reports["storage_paths"] = importKind(ctx, desired, existing, func(ctx context.Context, id int, updates map[string]interface{}) error {
+		_, err := client.UpdateStoragePath(ctx, id, updates)
+		return err
+	}, dryRun, updateExisting)
+
+	// Custom fields; the data type of an existing field cannot be changed,
+	// so differences are reported but never applied
+	desired, existing = nil, nil
+	for _, f := range snapshot.CustomFields {
+		field := paperless.CustomField{Name: f.Name, DataType: f.DataType}
+		desired = append(desired, importEntry{Name: f.Name, Fields: map[string]interface{}{"data_type": f.DataType},
+			Create: func(ctx context.Context) (int, error) {
+				created, err := client.CreateCustomField(ctx, &field)
+				if err != nil {
+					return 0, err
+				}
+				return created.ID, nil
+			}})
+	}
+	for _, f := range current.CustomFields {
+		existing = append(existing, existingEntry{ID: f.ID, Name: f.Name, Fields: map[string]interface{}{"data_type": f.DataType}})
+	}
+	reports["custom_fields"] = importKind(ctx, desired, existing, nil, dryRun, updateExisting)
+
+	if !dryRun {
+		s.metadata.invalidateAll()
+	}
+
+	errorCount := 0
+	for _, report := range reports {
+		errorCount += len(report.Errors)
+	}
+
+	logging.FromContext(ctx).Info("Metadata import completed",
+		"dry_run", dryRun,
+		"errors", errorCount)
+
+	return map[string]interface{}{
+		"dry_run":         dryRun,
+		"update_existing": updateExisting,
+		"success":         errorCount == 0,
+		"results":         reports,
+		"skipped": map[string]interface{}{
+			"saved_views": len(snapshot.SavedViews),
+			"reason":      "saved views reference object IDs that differ between instances and are not imported",
+		},
+	}, nil
+}
@@ -0,0 +1,156 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/config"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// TestImportMetadata tests that import_metadata defaults to a dry run
+// reporting what a real run does, creates missing objects once even when
+// the snapshot names them twice, applies differences only with
+// update_existing, and never imports saved views
+func TestImportMetadata(t *testing.T) {
+	snapshot := map[string]interface{}{
+		"version": float64(MetadataSnapshotVersion),
+		"tags": []interface{}{
+			map[string]interface{}{"name": "Urgent", "color": "#ff0000"},
+			map[string]interface{}{"name": "urgent", "color": "#ff0000"},
+			map[string]interface{}{"name": "Bills", "color": "#00ff00"},
+			map[string]interface{}{"name": "Inbox", "color": "#ffffff", "is_inbox_tag": true},
+		},
+		"correspondents": []interface{}{
+			map[string]interface{}{"name": "ACME", "match": "acme", "matching_algorithm": float64(1)},
+		},
+		"custom_fields": []interface{}{
+			map[string]interface{}{"name": "Amount", "data_type": "monetary"},
+		},
+		"saved_views": []interface{}{
+			map[string]interface{}{"name": "Unpaid", "filter_rules": []interface{}{}},
+		},
+	}
+
+	var writes []string
+	nextID := 100
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := func(results ...interface{}) map[string]interface{} {
+			return map[string]interface{}{"count": len(results), "next": nil, "results": results}
+		}
+		if r.Method != http.MethodGet {
+			writes = append(writes, r.Method+" "+r.URL.Path)
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			if r.Method == http.MethodPost {
+				nextID++
+				body["id"] = nextID
+			}
+			json.NewEncoder(w).Encode(body)
+			return
+		}
+		var body interface{}
+		switch r.URL.Path {
+		case "/api/tags/":
+			body = page(
+				map[string]interface{}{"id": 1, "name": "bills", "color": "#0000ff"},
+				map[string]interface{}{"id": 2, "name": "Inbox", "color": "#ffffff", "is_inbox_tag": true},
+			)
+		case "/api/custom_fields/":
+			body = page(map[string]interface{}{"id": 5, "name": "Amount", "data_type": "string"})
+		case "/api/correspondents/", "/api/document_types/", "/api/storage_paths/", "/api/saved_views/":
+			body = page()
+		default:
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+		json.NewEncoder(w).Encode(body)
+	}))
+	defer ts.Close()
+
+	s := &Server{
+		cfg:             &config.Config{PaperlessURL: ts.URL},
+		paperlessClient: paperless.New(ts.URL, "test-token"),
+		metadata:        newMetadataCaches(time.Hour),
+	}
+	importMetadata := func(args map[string]interface{}) map[string]interface{} {
+		t.Helper()
+		args["snapshot"] = snapshot
+		result, err := s.handleImportMetadata(context.Background(), args)
+		if err != nil {
+			t.Fatalf("import_metadata failed: %v", err)
+		}
+		return result.(map[string]interface{})
+	}
+	reports := func(result map[string]interface{}) map[string]*importReport {
+		return result["results"].(map[string]*importReport)
+	}
+
+	tests := []struct {
+		name        string
+		args        map[string]interface{}
+		wantWrites  []string
+		wantApplied bool
+	}{
+		{
+			name: "dry run by default",
+			args: map[string]interface{}{},
+		},
+		{
+			name:       "create",
+			args:       map[string]interface{}{"dry_run": false},
+			wantWrites: []string{"POST /api/tags/", "POST /api/correspondents/"},
+		},
+		{
+			name:        "update existing",
+			args:        map[string]interface{}{"dry_run": false, "update_existing": true},
+			wantWrites:  []string{"POST /api/tags/", "PATCH /api/tags/1/", "POST /api/correspondents/"},
+			wantApplied: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writes = nil
+			result := importMetadata(tt.args)
+			if dryRun := result["dry_run"].(bool); dryRun != (tt.wantWrites == nil) {
+				t.Errorf("Expected dry_run %v, got %v", tt.wantWrites == nil, dryRun)
+			}
+			if !reflect.DeepEqual(writes, tt.wantWrites) {
+				t.Errorf("Expected writes %v, got %v", tt.wantWrites, writes)
+			}
+
+			// The duplicate "urgent" matches the "Urgent" created before it
+			tags := reports(result)["tags"]
+			if !reflect.DeepEqual(tags.Created, []string{"Urgent"}) || tags.Unchanged != 2 || len(tags.Errors) != 0 {
+				t.Errorf("Unexpected tag report %+v", tags)
+			}
+			if len(tags.Changed) != 1 || tags.Changed[0].ID != 1 || tags.Changed[0].Changes["color"].To != "#00ff00" {
+				t.Fatalf("Expected the colour of tag 1 reported, got %+v", tags.Changed)
+			}
+			if tags.Changed[0].Applied != tt.wantApplied {
+				t.Errorf("Expected applied %v, got %v", tt.wantApplied, tags.Changed[0].Applied)
+			}
+
+			if correspondents := reports(result)["correspondents"]; !reflect.DeepEqual(correspondents.Created, []string{"ACME"}) {
+				t.Errorf("Unexpected correspondent report %+v", correspondents)
+			}
+			// A custom field's data type is reported but never changed
+			fields := reports(result)["custom_fields"]
+			if len(fields.Changed) != 1 || fields.Changed[0].Applied {
+				t.Errorf("Expected the data type reported and not applied, got %+v", fields.Changed)
+			}
+
+			skipped := result["skipped"].(map[string]interface{})
+			if skipped["saved_views"] != 1 || !strings.Contains(fmt.Sprint(skipped["reason"]), "not imported") {
+				t.Errorf("Expected the saved view skipped, got %v", skipped)
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// MetadataCacheTTL is how long tags, correspondents, document types,
+// storage paths and custom field definitions are cached before being read
+// from Paperless again
+const MetadataCacheTTL = 5 * time.Minute
+
+// MetadataPrefetchDelay spaces the requests of the startup prefetch so it
+// does not compete with the first tool calls for Paperless' attention
+const MetadataPrefetchDelay = 250 * time.Millisecond
+
+// metadataCache caches the full list of one kind of metadata object, which
+// changes rarely but is needed to resolve names on many tool calls. Lists
+// are kept per Paperless identity, as tokens mapped with
+// MCP_AUTH_TOKEN_MAP may see different objects. A nil cache always reads
+// from Paperless.
+type metadataCache[T any] struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]metadataCacheEntry[T]
+	stats   MetadataCacheStats
+}
+
+// MetadataCacheStats holds the counters of one metadata cache: lookups
+// answered from memory, lookups that had to read Paperless, and reads of
+// Paperless for any reason, including fresh reads and the prefetch
+type MetadataCacheStats struct {
+	Hits       uint64  `json:"hits"`
+	Misses     uint64  `json:"misses"`
+	Refreshes  uint64  `json:"refreshes"`
+	Identities int     `json:"identities"`
+	TTLSeconds int     `json:"ttl_seconds"`
+	HitRatio   float64 `json:"hit_ratio"`
+}
+
+// metadataCacheEntry is the list cached for one identity
+type metadataCacheEntry[T any] struct {
+	items   []T
+	fetched time.Time
+}
+
+// newMetadataCache creates a cache keeping lists for ttl
+func newMetadataCache[T any](ttl time.Duration) *metadataCache[T] {
+	return &metadataCache[T]{ttl: ttl, entries: make(map[string]metadataCacheEntry[T])}
+}
+
+// metadataList returns the client method listing objects of type T
+func metadataList[T any](client *paperless.Client) paperless.ListFunc {
+	switch any(*new(T)).(type) {
+	case paperless.Tag:
+		return client.ListTags
+	case paperless.Correspondent:
+		return client.ListCorrespondents
+	case paperless.DocumentType:
+		return client.ListDocumentTypes
+	case paperless.StoragePath:
+		return client.ListStoragePaths
+	case paperless.CustomField:
+		return client.ListCustomFields
+	case paperless.MailRule:
+		return client.ListMailRules
+	case paperless.MailAccount:
+		return client.ListMailAccounts
+	}
+	panic(fmt.Sprintf("no list endpoint for %T", *new(T)))
+}
+
+// get returns the objects visible to the calling identity, reading them
+// from Paperless when the cached copy is missing or expired
+func (c *metadataCache[T]) get(ctx context.Context, client *paperless.Client) ([]T, error) {
+	if c == nil {
+		return paperless.CollectAll[T](ctx, metadataList[T](client))
+	}
+
+	identity := identityKey(ctx)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.entries[identity]; ok && time.Since(entry.fetched) < c.ttl {
+		c.stats.Hits++
+		return entry.items, nil
+	}
+	c.stats.Misses++
+	return c.refreshLocked(ctx, client, identity)
+}
+
+// refreshLocked reads the objects from Paperless; callers must hold c.mu
+func (c *metadataCache[T]) refreshLocked(ctx context.Context, client *paperless.Client, identity string) ([]T, error) {
+	items, err := paperless.CollectAll[T](ctx, metadataList[T](client))
+	if err != nil {
+		return nil, err
+	}
+	c.entries[identity] = metadataCacheEntry[T]{items: items, fetched: time.Now()}
+	c.stats.Refreshes++
+	return items, nil
+}
+
+// fresh reads the objects from Paperless whatever the cached copy, for
+// checks that must not act on a stale list, and keeps them for later gets
+func (c *metadataCache[T]) fresh(ctx context.Context, client *paperless.Client) ([]T, error) {
+	if c == nil {
+		return paperless.CollectAll[T](ctx, metadataList[T](client))
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.refreshLocked(ctx, client, identityKey(ctx))
+}
+
+// warm reads the objects of the calling identity into the cache
+func (c *metadataCache[T]) warm(ctx context.Context, client *paperless.Client) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	items, err := c.refreshLocked(ctx, client, identityKey(ctx))
+	return len(items), err
+}
+
+// invalidate drops the cached lists of every identity after an object changes
+func (c *metadataCache[T]) invalidate() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+}
+
+// snapshot returns the cache's counters; a nil cache has none
+func (c *metadataCache[T]) snapshot() MetadataCacheStats {
+	if c == nil {
+		return MetadataCacheStats{}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Identities = len(c.entries)
+	stats.TTLSeconds = int(c.ttl.Seconds())
+	if lookups := stats.Hits + stats.Misses; lookups > 0 {
+		stats.HitRatio = float64(stats.Hits) / float64(lookups)
+	}
+	return stats
+}
+
+// metadataCaches holds the cache of each kind of metadata object
+type metadataCaches struct {
+	tags           *metadataCache[paperless.Tag]
+	correspondents *metadataCache[paperless.Correspondent]
+	documentTypes  *metadataCache[paperless.DocumentType]
+	storagePaths   *metadataCache[paperless.StoragePath]
+	customFields   *metadataCache[paperless.CustomField]
+	mailRules      *metadataCache[paperless.MailRule]
+	mailAccounts   *metadataCache[paperless.MailAccount]
+}
+
+// newMetadataCaches creates caches keeping lists for ttl
+func newMetadataCaches(ttl time.Duration) metadataCaches {
+	return metadataCaches{
+		tags:           newMetadataCache[paperless.Tag](ttl),
+		correspondents: newMetadataCache[paperless.Correspondent](ttl),
+		documentTypes:  newMetadataCache[paperless.DocumentType](ttl),
+		storagePaths:   newMetadataCache[paperless.StoragePath](ttl),
+		customFields:   newMetadataCache[paperless.CustomField](ttl),
+		mailRules:      newMetadataCache[paperless.MailRule](ttl),
+		mailAccounts:   newMetadataCache[paperless.MailAccount](ttl),
+	}
+}
+
+// invalidateAll drops every cached list, e.g. after an import
+func (m *metadataCaches) invalidateAll() {
+	m.tags.invalidate()
+	m.correspondents.invalidate()
+	m.documentTypes.invalidate()
+	m.storagePaths.invalidate()
+	m.customFields.invalidate()
+	m.mailRules.invalidate()
+	m.mailAccounts.invalidate()
+}
+
+// snapshot returns the counters of each cache by kind
+func (m *metadataCaches) snapshot() map[string]MetadataCacheStats {
+	return map[string]MetadataCacheStats{
+		"tags":           m.tags.snapshot(),
+		"correspondents": m.correspondents.snapshot(),
+		"document_types": m.documentTypes.snapshot(),
+		"storage_paths":  m.storagePaths.snapshot(),
+		"custom_fields":  m.customFields.snapshot(),
+		"mail_rules":     m.mailRules.snapshot(),
+		"mail_accounts":  m.mailAccounts.snapshot(),
+	}
+}
+
+// StartMetadataPrefetch warms the metadata caches of the server's own
+// Paperless identity in the background, one list at a time, so the first
+// name lookups of a conversation are answered from memory. It does nothing
+// when MCP_PREFETCH_METADATA is disabled or Paperless is unavailable.
+func (s *Server) StartMetadataPrefetch(ctx context.Context) {
+	if !s.cfg.MCPPrefetchMetadata {
+		return
+	}
+
+	go func() {
+		started := time.Now()
+		warms := []struct {
+			name string
+			warm func(context.Context, *paperless.Client) (int, error)
+		}{
+			{"tags", s.metadata.tags.warm},
+			{"correspondents", s.metadata.correspondents.warm},
+			{"document_types", s.metadata.documentTypes.warm},
+			{"storage_paths", s.metadata.storagePaths.warm},
+			{"custom_fields", s.metadata.customFields.warm},
+		}
+
+		counts := make(map[string]int, len(warms))
+		for i, w := range warms {
+			if i > 0 {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(MetadataPrefetchDelay):
+				}
+			}
+			if s.health.state().Degraded {
+				logging.FromContext(ctx).Debug("Skipping metadata prefetch while Paperless is unavailable")
+				return
+			}
+			n, err := w.warm(ctx, s.paperlessClient)
+			if err != nil {
+				logging.FromContext(ctx).Warn("Failed to prefetch metadata", "kind", w.name, "error", err)
+				continue
+			}
+			counts[w.name] = n
+		}
+
+		logging.FromContext(ctx).Info("Metadata caches prefetched",
+			"counts", counts,
+			"duration", time.Since(started).Round(time.Millisecond))
+	}()
+}
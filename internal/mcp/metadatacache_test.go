@@ -0,0 +1,130 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/config"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// TestMetadataCache tests that lists are cached per identity until they
+// are invalidated, that get_cache_stats reports the hits, misses and
+// refreshes, and that a nil cache reads from Paperless every time
+func TestMetadataCache(t *testing.T) {
+	reads := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reads++
+		w.Write([]byte(`{"count":1,"next":null,"results":[{"id":1,"name":"Inbox"}]}`))
+	}))
+	defer ts.Close()
+	client := paperless.New(ts.URL, "test-token")
+
+	alice := paperless.WithToken(context.Background(), "alice-token")
+	bob := paperless.WithToken(context.Background(), "bob-token")
+
+	cache := newMetadataCache[paperless.Tag](time.Minute)
+	for _, ctx := range []context.Context{alice, alice, bob} {
+		tags, err := cache.get(ctx, client)
+		if err != nil || len(tags) != 1 || tags[0].Name != "Inbox" {
+			t.Fatalf("Unexpected tags %+v, %v", tags, err)
+		}
+	}
+	if reads != 2 {
+		t.Errorf("Expected one read per identity, got %d", reads)
+	}
+	if stats := cache.snapshot(); stats.Hits != 1 || stats.Misses != 2 || stats.Refreshes != 2 || stats.Identities != 2 || stats.HitRatio != 1.0/3 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+
+	cache.invalidate()
+	if _, err := cache.get(alice, client); err != nil || reads != 3 {
+		t.Errorf("Expected a read after invalidation, got %d reads, %v", reads, err)
+	}
+	if _, err := cache.fresh(alice, client); err != nil || reads != 4 {
+		t.Errorf("Expected a fresh read, got %d reads, %v", reads, err)
+	}
+	if stats := cache.snapshot(); stats.Hits != 1 || stats.Misses != 3 || stats.Refreshes != 4 || stats.Identities != 1 {
+		t.Errorf("Unexpected stats after invalidation %+v", stats)
+	}
+
+	s := &Server{documents: newDocumentCache(0, 0), metadata: metadataCaches{tags: cache}}
+	result, err := s.handleGetCacheStats(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("get_cache_stats failed: %v", err)
+	}
+	metadata := result.(map[string]interface{})["metadata"].(map[string]MetadataCacheStats)
+	if metadata["tags"].Refreshes != 4 || metadata["correspondents"] != (MetadataCacheStats{}) {
+		t.Errorf("Unexpected metadata stats %+v", metadata)
+	}
+
+	var none *metadataCache[paperless.Tag]
+	none.invalidate()
+	if _, err := none.get(alice, client); err != nil || reads != 5 {
+		t.Errorf("Expected a nil cache to read through, got %d reads, %v", reads, err)
+	}
+}
+
+// TestStartMetadataPrefetch tests that every list is warmed in the
+// background, and nothing is read when prefetching is disabled
+func TestStartMetadataPrefetch(t *testing.T) {
+	var mu sync.Mutex
+	paths := map[string]int{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths[r.URL.Path]++
+		mu.Unlock()
+		w.Write([]byte(`{"count":1,"next":null,"results":[{"id":1,"name":"Example"}]}`))
+	}))
+	defer ts.Close()
+
+	s := &Server{
+		cfg:             &config.Config{},
+		paperlessClient: paperless.New(ts.URL, "test-token"),
+		metadata:        newMetadataCaches(time.Minute),
+	}
+	s.StartMetadataPrefetch(context.Background())
+	time.Sleep(2 * MetadataPrefetchDelay)
+	mu.Lock()
+	if len(paths) != 0 {
+		t.Errorf("Expected no requests with prefetching disabled, got %v", paths)
+	}
+	mu.Unlock()
+
+	s.cfg.MCPPrefetchMetadata = true
+	s.StartMetadataPrefetch(context.Background())
+
+	want := []string{"/api/tags/", "/api/correspondents/", "/api/document_types/", "/api/storage_paths/", "/api/custom_fields/"}
+	deadline := time.Now().Add(10 * MetadataPrefetchDelay * time.Duration(len(want)))
+	for {
+		mu.Lock()
+		done := len(paths) == len(want)
+		mu.Unlock()
+		if done || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(MetadataPrefetchDelay / 5)
+	}
+
+	mu.Lock()
+	for _, path := range want {
+		if paths[path] != 1 {
+			t.Errorf("Expected %s to be read once, got %v", path, paths)
+		}
+	}
+	mu.Unlock()
+
+	// Name lookups are now answered from the cache
+	if _, err := s.metadata.tags.get(context.Background(), s.paperlessClient); err != nil {
+		t.Fatalf("Failed to read cached tags: %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if paths["/api/tags/"] != 1 {
+		t.Errorf("Expected cached tags to be reused, got %d reads", paths["/api/tags/"])
+	}
+}
@@ -0,0 +1,35 @@
+package mcp
+
+import (
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/metrics"
+)
+
+// MetricsEndpoint serves metrics in the Prometheus text format
+const MetricsEndpoint = "/metrics"
+
+// paperlessMetrics counts and times requests to Paperless
+type paperlessMetrics struct {
+	requests *metrics.CounterVec
+	duration *metrics.HistogramVec
+}
+
+// newPaperlessMetrics registers the Paperless request metrics with r
+func newPaperlessMetrics(r *metrics.Registry) *paperlessMetrics {
+	return &paperlessMetrics{
+		requests: r.NewCounterVec("paperless_mcp_paperless_requests_total",
+			"Requests to Paperless by endpoint and outcome class (ok, timeout, network, canceled, rate_limited, auth, 4xx, 5xx).",
+			"method", "endpoint", "class"),
+		duration: r.NewHistogramVec("paperless_mcp_paperless_request_duration_seconds",
+			"Duration of requests to Paperless by endpoint.",
+			metrics.DefaultBuckets,
+			"method", "endpoint"),
+	}
+}
+
+// observe implements paperless.RequestObserver
+func (m *paperlessMetrics) observe(method, endpoint, class string, duration time.Duration) {
+	m.requests.Inc(method, endpoint, class)
+	m.duration.Observe(duration.Seconds(), method, endpoint)
+}
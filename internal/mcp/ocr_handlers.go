@@ -0,0 +1,267 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+)
+
+// OCR quality thresholds used by assess_document_ocr
+const (
+	// MinUsefulContentLength is the content length below which a document
+	// is treated as having no usable text
+	MinUsefulContentLength = 20
+
+	// MinCharsPerPage is the average characters per page below which the
+	// text layer is likely missing for some pages
+	MinCharsPerPage = 100
+
+	// PoorGarbageRatio and FairGarbageRatio bound the share of characters
+	// that are neither letters, digits, whitespace nor common punctuation
+	PoorGarbageRatio = 0.15
+	FairGarbageRatio = 0.05
+
+	// PoorNonWordRatio is the share of tokens that do not look like words
+	// above which the text is likely gibberish
+	PoorNonWordRatio = 0.4
+
+	// MinLanguageHits is how many stop words must be recognised before a
+	// language is reported
+	MinLanguageHits = 3
+)
+
+// stopWords lists frequent short words of the languages Paperless is most
+// commonly configured to OCR, keyed by their Tesseract language code
+var stopWords = map[string][]string{
+	"eng": {"the", "and", "of", "to", "in", "is", "for", "that", "with", "on", "this", "be", "are", "from", "you", "your", "by", "at", "as", "not"},
+	"deu": {"der", "die", "und", "das", "ist", "nicht", "mit", "den", "von", "sie", "ein", "eine", "auf", "für", "dem", "des", "zu", "im", "sich", "wir"},
+	"fra": {"le", "la", "les", "et", "des", "est", "une", "pour", "dans", "que", "qui", "sur", "par", "pas", "au", "aux", "du", "vous", "avec", "ce"},
+	"spa": {"el", "la", "los", "las", "y", "que", "del", "en", "por", "para", "una", "con", "es", "se", "no", "su", "al", "como", "sus", "lo"},
+	"ita": {"il", "di", "che", "e", "la", "per", "un", "una", "non", "sono", "del", "della", "con", "gli", "le", "nel", "si", "da", "alla", "questo"},
+	"nld": {"de", "het", "een", "en", "van", "is", "dat", "niet", "op", "te", "voor", "met", "zijn", "er", "wordt", "ook", "aan", "bij", "uw", "deze"},
+	"por": {"o", "a", "os", "as", "que", "de", "do", "da", "em", "para", "com", "não", "uma", "um", "por", "se", "dos", "das", "ao", "mais"},
+}
+
+// stopWordLanguages indexes stopWords by word
+var stopWordLanguages = func() map[string][]string {
+	index := make(map[string][]string)
+	for lang, words := range stopWords {
+		for _, word := range words {
+			index[word] = append(index[word], lang)
+		}
+	}
+	return index
+}()
+
+// commonPunctuation is punctuation expected in ordinary documents
+const commonPunctuation = ".,;:!?'\"()[]-/&%€$£@#*+=_<>§°"
+
+// languageScore is the number of stop words recognised for a language
+type languageScore struct {
+	Language string `json:"language"`
+	Hits     int    `json:"hits"`
+}
+
+// ocrAssessment describes the quality of a document's text layer
+type ocrAssessment struct {
+	ContentLength      int             `json:"content_length"`
+	WordCount          int             `json:"word_count"`
+	LineCount          int             `json:"line_count"`
+	CharsPerPage       *int            `json:"chars_per_page,omitempty"`
+	GarbageRatio       float64         `json:"garbage_ratio"`
+	NonWordRatio       float64         `json:"non_word_ratio"`
+	Language           string          `json:"detected_language,omitempty"`
+	LanguageScores     []languageScore `json:"language_scores,omitempty"`
+	Quality            string          `json:"quality"`
+	Issues             []string        `json:"issues"`
+	RecommendReprocess bool            `json:"recommend_reprocess"`
+}
+
+// looksLikeWord reports whether a token is plausibly a word or number:
+// mostly letters or mostly digits, rather than a mix of symbols
+func looksLikeWord(token string) bool {
+	letters, digits, other := 0, 0, 0
+	for _, r := range token {
+		switch {
+		case unicode.IsLetter(r):
+			letters++
+		case unicode.IsDigit(r):
+			digits++
+		default:
+			other++
+		}
+	}
+	total := letters + digits + other
+	if total == 0 {
+		return false
+	}
+	return float64(letters+digits)/float64(total) >= 0.7
+}
+
+// detectLanguage scores the words against the stop word lists and returns
+// the best language, or "" when too few stop words were recognised
+func detectLanguage(words []string) (string, []languageScore) {
+	hits := make(map[string]int)
+	for _, word := range words {
+		for _, lang := range stopWordLanguages[word] {
+			hits[lang]++
+		}
+	}
+
+	scores := make([]languageScore, 0, len(hits))
+	for lang, n := range hits {
+		scores = append(scores, languageScore{Language: lang, Hits: n})
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Hits != scores[j].Hits {
+			return scores[i].Hits > scores[j].Hits
+		}
+		return scores[i].Language < scores[j].Language
+	})
+
+	if len(scores) == 0 || scores[0].Hits < MinLanguageHits {
+		return "", scores
+	}
+	return scores[0].Language, scores
+}
+
+// assessOCR measures the text layer of a document. pageCount may be nil
+// when the page count is unknown.
+func assessOCR(content string, pageCount *int) ocrAssessment {
+	a := ocrAssessment{Issues: []string{}}
+
+	letters, garbage := 0, 0
+	for _, r := range content {
+		a.ContentLength++
+		switch {
+		case unicode.IsSpace(r):
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			letters++
+		case strings.ContainsRune(commonPunctuation, r):
+		default:
+			garbage++
+		}
+	}
+	if nonSpace := letters + garbage; nonSpace > 0 {
+		a.GarbageRatio = float64(garbage) / float64(nonSpace)
+	}
+
+	trimmed := strings.TrimSpace(content)
+	if trimmed != "" {
+		a.LineCount = strings.Count(trimmed, "\n") + 1
+	}
+
+	tokens := strings.Fields(content)
+	a.WordCount = len(tokens)
+	words := make([]string, 0, len(tokens))
+	nonWords := 0
+	for _, token := range tokens {
+		if !looksLikeWord(token) {
+			nonWords++
+			continue
+		}
+		words = append(words, strings.ToLower(strings.TrimFunc(token, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})))
+	}
+	if len(tokens) > 0 {
+		a.NonWordRatio = float64(nonWords) / float64(len(tokens))
+	}
+
+	a.Language, a.LanguageScores = detectLanguage(words)
+
+	if pageCount != nil && *pageCount > 0 {
+		perPage := a.ContentLength / *pageCount
+		a.CharsPerPage = &perPage
+	}
+
+	// Grade from the worst finding
+	a.Quality = "good"
+	degrade := func(quality, issue string) {
+		a.Issues = append(a.Issues, issue)
+		if quality == "poor" || a.Quality == "good" {
+			a.Quality = quality
+		}
+	}
+	if len(trimmed) < MinUsefulContentLength {
+		a.Quality = "empty"
+		a.Issues = append(a.Issues, "document has little or no text content")
+		a.RecommendReprocess = true
+		return a
+	}
+	if a.GarbageRatio >= PoorGarbageRatio {
+		degrade("poor", fmt.Sprintf("%.0f%% of characters are unusual symbols", a.GarbageRatio*100))
+	} else if a.GarbageRatio >= FairGarbageRatio {
+		degrade("fair", fmt.Sprintf("%.0f%% of characters are unusual symbols", a.GarbageRatio*100))
+	}
+	if a.NonWordRatio >= PoorNonWordRatio {
+		degrade("poor", fmt.Sprintf("%.0f%% of tokens do not look like words", a.NonWordRatio*100))
+	}
+	if a.CharsPerPage != nil && *a.CharsPerPage < MinCharsPerPage {
+		degrade("fair", fmt.Sprintf("only %d characters per page on average; some pages may have no text", *a.CharsPerPage))
+	}
+	if a.Language == "" && a.WordCount >= 50 {
+		degrade("fair", "no known language recognised")
+	}
+	a.RecommendReprocess = a.Quality == "poor"
+
+	return a
+}
+
+// handleAssessDocumentOCR handles the assess_document_ocr tool
+func (s *Server) handleAssessDocumentOCR(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	// Extract and validate document_id
+	documentIDFloat, ok := args["document_id"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("document_id parameter is required and must be an integer")
+	}
+	documentID := int(documentIDFloat)
+	if documentID < 1 {
+		return nil, fmt.Errorf("document_id must be a positive integer")
+	}
+
+	logging.FromContext(ctx).Debug("Assessing document OCR", "document_id", documentID)
+
+	// Served from the session cache when recently fetched
+	document, err := s.getDocument(ctx, documentID)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to get document",
+			"document_id", documentID,
+			"error", err)
+		return nil, fmt.Errorf("failed to get document: %w", err)
+	}
+
+	assessment := assessOCR(document.Content, document.PageCount)
+
+	result := map[string]interface{}{
+		"document_id": document.ID,
+		"title":       document.Title,
+		"page_count":  document.PageCount,
+		"assessment":  assessment,
+	}
+
+	// Paperless records the language the text was detected as; a mismatch
+	// with the OCR language setting is a common cause of poor text
+	if metadata, err := s.paperlessClient.GetDocumentMetadata(ctx, documentID); err != nil {
+		logging.FromContext(ctx).Warn("Failed to get document metadata",
+			"document_id", documentID,
+			"error", err)
+	} else if metadata.Lang != "" {
+		result["paperless_language"] = metadata.Lang
+	}
+
+	if assessment.RecommendReprocess {
+		result["suggestion"] = "The text layer looks unreliable; consider reprocessing the document (bulk edit method \"reprocess\") before summarizing it"
+	}
+
+	logging.FromContext(ctx).Info("Document OCR assessed",
+		"document_id", documentID,
+		"quality", assessment.Quality,
+		"language", assessment.Language)
+
+	return result, nil
+}
@@ -0,0 +1,39 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestAssessOCR tests grading and language detection on clean, noisy and
+// empty text
+func TestAssessOCR(t *testing.T) {
+	clean := strings.Repeat("This is the invoice for the repair of your heating system and the total is due within 30 days. ", 5)
+	pages := 1
+	a := assessOCR(clean, &pages)
+	if a.Quality != "good" || a.RecommendReprocess {
+		t.Errorf("Expected clean text to be good, got %s: %v", a.Quality, a.Issues)
+	}
+	if a.Language != "eng" {
+		t.Errorf("Expected English, got %q (%v)", a.Language, a.LanguageScores)
+	}
+
+	german := "Sehr geehrte Damen und Herren, die Rechnung ist nicht bezahlt und wir bitten Sie, den Betrag auf das Konto zu überweisen."
+	if a := assessOCR(german, nil); a.Language != "deu" {
+		t.Errorf("Expected German, got %q (%v)", a.Language, a.LanguageScores)
+	}
+
+	garbage := strings.Repeat("~|{} ¬¦ ^^~ ¤¤|| ÷×~ }{|| ", 10)
+	if a := assessOCR(garbage, nil); a.Quality != "poor" || !a.RecommendReprocess {
+		t.Errorf("Expected garbage to be poor, got %s: %v", a.Quality, a.Issues)
+	}
+
+	pages = 10
+	if a := assessOCR("  \n ", &pages); a.Quality != "empty" || !a.RecommendReprocess {
+		t.Errorf("Expected empty content to be flagged, got %s", a.Quality)
+	}
+
+	if a := assessOCR(clean, &pages); a.Quality != "fair" {
+		t.Errorf("Expected sparse text per page to be fair, got %s: %v", a.Quality, a.Issues)
+	}
+}
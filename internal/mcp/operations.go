@@ -0,0 +1,326 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+)
+
+// Operation journal constants
+const (
+	// OperationJournalTTL is how long an interrupted operation can be
+	// resumed; after that the documents may have changed too much for
+	// finishing it to be what the caller wanted
+	OperationJournalTTL = 24 * time.Hour
+
+	// MaxOperationJournalEntries bounds how many operations are remembered
+	MaxOperationJournalEntries = 20
+)
+
+// operationRecord tracks the progress of an operation sent to Paperless
+// in several chunks
+type operationRecord struct {
+	ID          int64                  `json:"id"`
+	Identity    string                 `json:"identity"`
+	Tool        string                 `json:"tool"`
+	DocumentIDs []int                  `json:"document_ids"`
+	Operations  map[string]interface{} `json:"operations"`
+	Completed   int                    `json:"completed"` // documents done, the offset of the next chunk
+	Error       string                 `json:"error,omitempty"`
+	StartedAt   time.Time              `json:"started_at"`
+	UpdatedAt   time.Time              `json:"updated_at"`
+}
+
+// operationJournal records the progress of chunked operations so one cut
+// short by a failed chunk, a shutdown or a crash can be resumed where it
+// stopped. Records are kept in memory and, when a path is configured,
+// persisted to a small JSON file after every chunk. A nil journal records
+// nothing.
+type operationJournal struct {
+	path string
+
+	mu      sync.Mutex
+	records []operationRecord // oldest first
+	running map[int64]bool    // operations being executed by this process
+	nextID  int64
+}
+
+// newOperationJournal creates a journal, loading persisted records from
+// path when it is non-empty. Records loaded from a previous run are all
+// resumable, as nothing executes them any more.
+func newOperationJournal(path string) *operationJournal {
+	j := &operationJournal{path: path, running: make(map[int64]bool), nextID: 1}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err == nil {
+			if err := json.Unmarshal(data, &j.records); err != nil {
+				slog.Warn("Ignoring unreadable operation journal", "path", path, "error", err)
+				j.records = nil
+			}
+		} else if !os.IsNotExist(err) {
+			slog.Warn("Failed to read operation journal", "path", path, "error", err)
+		}
+		for i := range j.records {
+			j.records[i].Operations = restoreBulkEditOperations(j.records[i].Operations)
+			if j.records[i].ID >= j.nextID {
+				j.nextID = j.records[i].ID + 1
+			}
+		}
+	}
+
+	return j
+}
+
+// restoreBulkEditOperations converts bulk edit operations read back from
+// JSON to the types the bulk edit handler builds
+func restoreBulkEditOperations(operations map[string]interface{}) map[string]interface{} {
+	for key, value := range operations {
+		switch value := value.(type) {
+		case float64:
+			operations[key] = int(value)
+		case []interface{}:
+			ids := make([]int, 0, len(value))
+			for _, id := range value {
+				if n, ok := id.(float64); ok {
+					ids = append(ids, int(n))
+				}
+			}
+			operations[key] = ids
+		}
+	}
+	return operations
+}
+
+// start records a new operation of the calling identity as running
+func (j *operationJournal) start(ctx context.Context, tool string, documentIDs []int, operations map[string]interface{}) operationRecord {
+	record := operationRecord{
+		Tool:        tool,
+		DocumentIDs: documentIDs,
+		Operations:  operations,
+	}
+	if j == nil {
+		return record
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	now := time.Now().UTC()
+	record.ID = j.nextID
+	j.nextID++
+	record.Identity = identityKey(ctx)
+	record.StartedAt, record.UpdatedAt = now, now
+
+	j.expireLocked()
+	j.records = append(j.records, record)
+	if len(j.records) > MaxOperationJournalEntries {
+		j.records = j.records[len(j.records)-MaxOperationJournalEntries:]
+	}
+	j.running[record.ID] = true
+	j.saveLocked()
+
+	logging.FromContext(ctx).Debug("Recorded operation progress",
+		"operation_id", record.ID,
+		"document_count", len(documentIDs))
+	return record
+}
+
+// advance records that the first completed documents of an operation are done
+func (j *operationJournal) advance(id int64, completed int) {
+	if j == nil {
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if i := j.indexLocked(id); i >= 0 {
+		j.records[i].Completed = completed
+		j.records[i].UpdatedAt = time.Now().UTC()
+		j.saveLocked()
+	}
+}
+
+// finish drops a completed operation
+func (j *operationJournal) finish(id int64) {
+	if j == nil {
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if i := j.indexLocked(id); i >= 0 {
+		j.records = append(j.records[:i], j.records[i+1:]...)
+	}
+	delete(j.running, id)
+	j.saveLocked()
+}
+
+// fail records why an operation stopped, leaving it to be resumed
+func (j *operationJournal) fail(id int64, err error) {
+	if j == nil {
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if i := j.indexLocked(id); i >= 0 {
+		j.records[i].Error = err.Error()
+		j.records[i].UpdatedAt = time.Now().UTC()
+	}
+	delete(j.running, id)
+	j.saveLocked()
+}
+
+// interrupted returns the operations of identity that can be resumed
+func (j *operationJournal) interrupted(identity string) []operationRecord {
+	if j == nil {
+		return nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.expireLocked()
+	records := []operationRecord{}
+	for _, record := range j.records {
+		if record.Identity == identity && !j.running[record.ID] {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// claim marks an interrupted operation of identity as running again and
+// returns it
+func (j *operationJournal) claim(identity string, id int64) (operationRecord, error) {
+	if j == nil {
+		return operationRecord{}, fmt.Errorf("operation %d does not exist", id)
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.expireLocked()
+	i := j.indexLocked(id)
+	if i < 0 || j.records[i].Identity != identity {
+		return operationRecord{}, fmt.Errorf("operation %d does not exist or is older than %s", id, OperationJournalTTL)
+	}
+	if j.running[id] {
+		return operationRecord{}, fmt.Errorf("operation %d is still running", id)
+	}
+	j.running[id] = true
+	return j.records[i], nil
+}
+
+// indexLocked returns the index of the record with the given ID, or -1;
+// callers must hold j.mu
+func (j *operationJournal) indexLocked(id int64) int {
+	for i, record := range j.records {
+		if record.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// expireLocked drops records not updated for OperationJournalTTL, unless
+// they are running; callers must hold j.mu
+func (j *operationJournal) expireLocked() {
+	cutoff := time.Now().Add(-OperationJournalTTL)
+	kept := j.records[:0]
+	for _, record := range j.records {
+		if j.running[record.ID] || !record.UpdatedAt.Before(cutoff) {
+			kept = append(kept, record)
+		}
+	}
+	j.records = kept
+}
+
+// saveLocked persists records when a path is configured; callers must hold j.mu
+func (j *operationJournal) saveLocked() {
+	if j.path == "" {
+		return
+	}
+	data, err := json.Marshal(j.records)
+	if err != nil {
+		slog.Error("Failed to marshal operation journal", "error", err)
+		return
+	}
+	if err := os.WriteFile(j.path, data, 0o600); err != nil {
+		slog.Error("Failed to write operation journal", "path", j.path, "error", err)
+	}
+}
+
+// describeOperation summarises an interrupted operation for resume_operation
+func describeOperation(record operationRecord) map[string]interface{} {
+	description := map[string]interface{}{
+		"operation_id":   record.ID,
+		"tool":           record.Tool,
+		"summary":        describeBulkEdit(record.Operations),
+		"document_count": len(record.DocumentIDs),
+		"completed":      record.Completed,
+		"remaining":      len(record.DocumentIDs) - record.Completed,
+		"started_at":     record.StartedAt,
+		"updated_at":     record.UpdatedAt,
+	}
+	if record.Error != "" {
+		description["error"] = record.Error
+	}
+	return description
+}
+
+// handleResumeOperation handles the resume_operation tool
+func (s *Server) handleResumeOperation(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	identity := identityKey(ctx)
+
+	// Without an operation_id, list what can be resumed
+	idFloat, ok := args["operation_id"].(float64)
+	if !ok {
+		records := s.operations.interrupted(identity)
+		operations := make([]map[string]interface{}, len(records))
+		for i, record := range records {
+			operations[i] = describeOperation(record)
+		}
+		return map[string]interface{}{
+			"count":      len(operations),
+			"operations": operations,
+		}, nil
+	}
+
+	record, err := s.operations.claim(identity, int64(idFloat))
+	if err != nil {
+		return nil, err
+	}
+	if record.Tool != "bulk_edit_documents" {
+		s.operations.fail(record.ID, fmt.Errorf("cannot be resumed by this server version"))
+		return nil, fmt.Errorf("operation %d was started by %s, which cannot be resumed", record.ID, record.Tool)
+	}
+
+	remaining := record.DocumentIDs[record.Completed:]
+	logging.FromContext(ctx).Debug("Resuming operation",
+		"operation_id", record.ID,
+		"completed", record.Completed,
+		"remaining", len(remaining))
+
+	previous := s.snapshotBulkEdit(ctx, remaining, record.Operations)
+
+	result, edited, err := s.runBulkEditOperation(ctx, record)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to resume operation",
+			"operation_id", record.ID,
+			"error", err)
+		return nil, fmt.Errorf("failed to resume operation %d: %w", record.ID, err)
+	}
+	s.recordBulkEdit(ctx, previous, edited)
+
+	logging.FromContext(ctx).Info("Operation resumed",
+		"operation_id", record.ID,
+		"edited", len(edited))
+
+	return result, nil
+}
@@ -0,0 +1,117 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/config"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// TestResumeOperation tests that a chunked bulk edit stopped by a failed
+// chunk is journaled, survives a restart and is finished from where it
+// stopped
+func TestResumeOperation(t *testing.T) {
+	var requests [][]int
+	var tags [][]int
+	failed := false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`{"count":0,"results":[]}`))
+			return
+		}
+		var body struct {
+			Documents []int `json:"documents"`
+			AddTags   []int `json:"add_tags"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		requests = append(requests, body.Documents)
+		tags = append(tags, body.AddTags)
+		if len(requests) == 2 && !failed {
+			failed = true
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte(`{"detail":"bad gateway"}`))
+			return
+		}
+		w.Write([]byte(`{"result":"OK"}`))
+	}))
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "operations.json")
+	s := &Server{
+		cfg:             &config.Config{MCPBulkChunkSize: 2},
+		paperlessClient: paperless.New(ts.URL, "test-token"),
+		documents:       newDocumentCache(10, time.Minute),
+		journal:         newUndoJournal(""),
+		operations:      newOperationJournal(path),
+	}
+	ctx := context.Background()
+
+	response, edited, err := s.bulkEditInChunks(ctx, []int{1, 2, 3, 4, 5}, map[string]interface{}{"add_tags": []int{9}})
+	if err != nil || !reflect.DeepEqual(edited, []int{1, 2}) {
+		t.Fatalf("Unexpected partial edit: %v, %v", edited, err)
+	}
+	partial := response.(map[string]interface{})
+	if partial["result"] != "partial" || partial["operation_id"] != int64(1) {
+		t.Fatalf("Expected a partial result naming the operation, got %v", partial)
+	}
+
+	// A restart forgets running operations but keeps their progress
+	s.operations = newOperationJournal(path)
+
+	listed, err := s.handleResumeOperation(ctx, map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("Listing operations failed: %v", err)
+	}
+	operations := listed.(map[string]interface{})["operations"].([]map[string]interface{})
+	if len(operations) != 1 || operations[0]["completed"] != 2 || operations[0]["remaining"] != 3 {
+		t.Fatalf("Expected one operation with 3 documents left, got %v", operations)
+	}
+
+	requests, tags = nil, nil
+	result, err := s.handleResumeOperation(ctx, map[string]interface{}{"operation_id": float64(1)})
+	if err != nil {
+		t.Fatalf("Resuming failed: %v", err)
+	}
+	if want := [][]int{{3, 4}, {5}}; !reflect.DeepEqual(requests, want) {
+		t.Errorf("Expected the remaining chunks %v, got %v", want, requests)
+	}
+	if want := [][]int{{9}, {9}}; !reflect.DeepEqual(tags, want) {
+		t.Errorf("Expected the original operations to be resent, got %v", tags)
+	}
+	resumed := result.(map[string]interface{})
+	if resumed["result"] != "OK" || resumed["edited"] != 5 || resumed["resumed_from"] != 2 {
+		t.Errorf("Unexpected resumed result: %v", resumed)
+	}
+
+	if _, err := s.handleResumeOperation(ctx, map[string]interface{}{"operation_id": float64(1)}); err == nil {
+		t.Error("Expected a finished operation not to be resumable")
+	}
+	if reloaded := newOperationJournal(path).interrupted("default"); len(reloaded) != 0 {
+		t.Errorf("Expected the finished operation to be dropped from the file, got %v", reloaded)
+	}
+}
+
+// TestOperationJournalClaim tests that running operations and those of
+// other identities cannot be claimed
+func TestOperationJournalClaim(t *testing.T) {
+	j := newOperationJournal("")
+	record := j.start(context.Background(), "bulk_edit_documents", []int{1, 2, 3}, map[string]interface{}{})
+
+	if _, err := j.claim("default", record.ID); err == nil {
+		t.Error("Expected a running operation not to be claimable")
+	}
+	j.fail(record.ID, context.Canceled)
+	if _, err := j.claim("someone-else", record.ID); err == nil {
+		t.Error("Expected another identity's operation not to be claimable")
+	}
+	if _, err := j.claim("default", record.ID); err != nil {
+		t.Errorf("Expected a failed operation to be claimable, got %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package mcp
+
+import (
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+)
+
+// originMiddleware rejects browser requests to the MCP endpoint from
+// origins that are not allowed, protecting servers bound to localhost
+// from DNS rebinding: a malicious page whose hostname resolves to the
+// server is still sent with its own Origin.
+//
+// Requests without an Origin header (non-browser clients) are accepted.
+// Loopback origins are always allowed; others must be listed in
+// MCP_ALLOWED_ORIGINS, where "*" allows any origin.
+func (s *Server) originMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if r.URL.Path != StreamableHTTPEndpoint || origin == "" || s.isAllowedOrigin(origin) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		logging.FromContext(r.Context()).Warn("Rejected request from disallowed origin",
+			"origin", origin,
+			"client_ip", s.clientIP(r))
+		http.Error(w, "Forbidden: origin not allowed", http.StatusForbidden)
+	})
+}
+
+// isAllowedOrigin checks origin against MCP_ALLOWED_ORIGINS and the
+// loopback addresses
+func (s *Server) isAllowedOrigin(origin string) bool {
+	normalized := strings.TrimSuffix(strings.ToLower(origin), "/")
+	for _, allowed := range s.cfg.MCPAllowedOrigins {
+		if allowed == "*" || allowed == normalized {
+			return true
+		}
+	}
+
+	u, err := url.Parse(normalized)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+	return isLoopbackHost(u.Hostname())
+}
+
+// isLoopbackHost reports whether host names the local machine
+func isLoopbackHost(host string) bool {
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	// Other hostnames could be rebound, only literal addresses are trusted
+	addr, err := netip.ParseAddr(host)
+	return err == nil && addr.Unmap().IsLoopback()
+}
@@ -0,0 +1,89 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// DefaultOverviewLimit is how many of each kind get_taxonomy_overview
+// lists by default
+const DefaultOverviewLimit = 10
+
+// taxonomyEntry is one tag, correspondent or document type in an overview
+type taxonomyEntry struct {
+	ID                 int    `json:"id"`
+	Name               string `json:"name"`
+	DocumentCount      int    `json:"document_count"`
+	LastCorrespondence string `json:"last_correspondence,omitempty"`
+}
+
+// listMatchingFunc is a client method listing metadata objects
+type listMatchingFunc func(ctx context.Context, opts paperless.ListOptions, page, pageSize int) (*paperless.PaginatedResponse, error)
+
+// topByDocumentCount returns how many objects list has in total and the
+// limit most used, in one request ordered by document count
+func topByDocumentCount(ctx context.Context, list listMatchingFunc, limit int) (map[string]interface{}, error) {
+	response, err := list(ctx, paperless.ListOptions{Ordering: "-document_count"}, 1, limit)
+	if err != nil {
+		return nil, err
+	}
+	var entries []taxonomyEntry
+	if err := json.Unmarshal(response.Results, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse results: %w", err)
+	}
+	return map[string]interface{}{
+		"total": response.Count,
+		"top":   entries,
+	}, nil
+}
+
+// handleGetTaxonomyOverview handles the get_taxonomy_overview tool
+func (s *Server) handleGetTaxonomyOverview(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	limit := DefaultOverviewLimit
+	if limitVal, ok := args["limit"].(float64); ok {
+		limit = int(limitVal)
+		if limit < 1 || limit > MaxPageSize {
+			return nil, fmt.Errorf("limit must be between 1 and %d", MaxPageSize)
+		}
+	}
+
+	logging.FromContext(ctx).Debug("Getting taxonomy overview", "limit", limit)
+
+	client := s.paperlessClient
+	documents, err := client.ListDocuments(ctx, url.Values{"fields": {"id"}}, 1, 1)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to count documents", "error", err)
+		return nil, fmt.Errorf("failed to count documents: %w", err)
+	}
+
+	overview := map[string]interface{}{
+		"documents": documents.Count,
+		"limit":     limit,
+	}
+	for _, kind := range []struct {
+		key  string
+		list listMatchingFunc
+	}{
+		{"tags", client.ListTagsMatching},
+		{"correspondents", client.ListCorrespondentsMatching},
+		{"document_types", client.ListDocumentTypesMatching},
+	} {
+		top, err := topByDocumentCount(ctx, kind.list, limit)
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to list taxonomy", "kind", kind.key, "error", err)
+			return nil, fmt.Errorf("failed to list %s: %w", kind.key, err)
+		}
+		overview[kind.key] = top
+	}
+
+	logging.FromContext(ctx).Info("Taxonomy overview assembled",
+		"documents", documents.Count,
+		"limit", limit)
+
+	return overview, nil
+}
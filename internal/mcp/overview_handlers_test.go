@@ -0,0 +1,49 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// TestTaxonomyOverview tests that the overview asks each list endpoint for
+// its most used entries and reports the totals
+func TestTaxonomyOverview(t *testing.T) {
+	orderings := make(map[string]string)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		orderings[r.URL.Path] = r.URL.Query().Get("ordering")
+		switch r.URL.Path {
+		case "/api/documents/":
+			w.Write([]byte(`{"count":1200,"results":[{"id":1}]}`))
+		case "/api/tags/":
+			w.Write([]byte(`{"count":40,"results":[{"id":3,"name":"Taxes","document_count":310}]}`))
+		default:
+			w.Write([]byte(`{"count":0,"results":[]}`))
+		}
+	}))
+	defer ts.Close()
+
+	s := &Server{paperlessClient: paperless.New(ts.URL, "test-token")}
+	result, err := s.handleGetTaxonomyOverview(context.Background(), map[string]interface{}{"limit": float64(5)})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	overview := result.(map[string]interface{})
+	if overview["documents"] != 1200 {
+		t.Errorf("Expected 1200 documents, got %v", overview["documents"])
+	}
+	tags := overview["tags"].(map[string]interface{})
+	top := tags["top"].([]taxonomyEntry)
+	if tags["total"] != 40 || len(top) != 1 || top[0].Name != "Taxes" || top[0].DocumentCount != 310 {
+		t.Errorf("Expected 40 tags led by Taxes, got %v", tags)
+	}
+	for _, path := range []string{"/api/tags/", "/api/correspondents/", "/api/document_types/"} {
+		if orderings[path] != "-document_count" {
+			t.Errorf("Expected %s to be ordered by -document_count, got %q", path, orderings[path])
+		}
+	}
+}
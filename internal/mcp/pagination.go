@@ -0,0 +1,48 @@
+package mcp
+
+// ListResult is the response of every paginated list and search tool, so
+// callers can rely on one shape whatever is being listed
+type ListResult struct {
+	Count          int                    `json:"count"`
+	Page           int                    `json:"page"`
+	PageSize       int                    `json:"page_size"`
+	TotalPages     int                    `json:"total_pages"`
+	NextPage       *int                   `json:"next_page"`
+	PrevPage       *int                   `json:"prev_page"`
+	Items          interface{}            `json:"items"`
+	AppliedFilters map[string]interface{} `json:"applied_filters"`
+}
+
+// newListResult builds a ListResult for one page of items. total_pages is
+// computed from the total count, and next_page and prev_page are page
+// numbers, or nil when there is no such page; numbers rather than
+// Paperless' next and previous URLs let callers ask for another page
+// directly. filters are the filters the tool applied, reported as an empty
+// object when there are none.
+func newListResult(items interface{}, count, page, pageSize int, filters map[string]interface{}) *ListResult {
+	totalPages := 0
+	if pageSize > 0 {
+		totalPages = (count + pageSize - 1) / pageSize
+	}
+
+	result := &ListResult{
+		Count:          count,
+		Page:           page,
+		PageSize:       pageSize,
+		TotalPages:     totalPages,
+		Items:          items,
+		AppliedFilters: filters,
+	}
+	if page < totalPages {
+		next := page + 1
+		result.NextPage = &next
+	}
+	if page > 1 {
+		prev := min(page-1, max(totalPages, 1))
+		result.PrevPage = &prev
+	}
+	if result.AppliedFilters == nil {
+		result.AppliedFilters = map[string]interface{}{}
+	}
+	return result
+}
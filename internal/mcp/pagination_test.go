@@ -0,0 +1,55 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestNewListResult tests the page numbers derived from the total count
+func TestNewListResult(t *testing.T) {
+	tests := []struct {
+		count, page, pageSize int
+		totalPages            int
+		next, prev            int // 0 for none
+	}{
+		{count: 0, page: 1, pageSize: 25, totalPages: 0},
+		{count: 60, page: 1, pageSize: 25, totalPages: 3, next: 2},
+		{count: 60, page: 3, pageSize: 25, totalPages: 3, prev: 2},
+		{count: 50, page: 2, pageSize: 25, totalPages: 2, prev: 1},
+		// Past the end, the previous page is the last one
+		{count: 30, page: 5, pageSize: 25, totalPages: 2, prev: 2},
+	}
+	pageNumber := func(p *int) int {
+		if p == nil {
+			return 0
+		}
+		return *p
+	}
+	for _, tt := range tests {
+		result := newListResult([]int{}, tt.count, tt.page, tt.pageSize, nil)
+		if result.TotalPages != tt.totalPages || pageNumber(result.NextPage) != tt.next || pageNumber(result.PrevPage) != tt.prev {
+			t.Errorf("count=%d page=%d: expected %d pages, next %d, prev %d, got %+v",
+				tt.count, tt.page, tt.totalPages, tt.next, tt.prev, result)
+		}
+	}
+}
+
+// TestListResultShape tests the JSON fields every list tool returns
+func TestListResultShape(t *testing.T) {
+	data, err := json.Marshal(newListResult([]string{"a"}, 1, 1, 25, nil))
+	if err != nil {
+		t.Fatalf("Failed to encode: %v", err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to decode: %v", err)
+	}
+	for _, key := range []string{"count", "page", "page_size", "total_pages", "next_page", "prev_page", "items", "applied_filters"} {
+		if _, ok := decoded[key]; !ok {
+			t.Errorf("Expected %s in %s", key, data)
+		}
+	}
+	if filters, ok := decoded["applied_filters"].(map[string]interface{}); !ok || len(filters) != 0 {
+		t.Errorf("Expected empty applied_filters, got %v", decoded["applied_filters"])
+	}
+}
@@ -0,0 +1,83 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// paperlessURLCheck records whether PAPERLESS_URL was found to serve the
+// Paperless API. The zero value has not checked yet.
+type paperlessURLCheck struct {
+	mu       sync.RWMutex
+	checked  bool
+	resolved string // the base URL the API answers under, if it differs
+	problem  string // why the API was not found
+}
+
+// state returns the corrected base URL and the problem found, if any
+func (c *paperlessURLCheck) state() (checked bool, resolved, problem string) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.checked, c.resolved, c.problem
+}
+
+// resolvePaperlessURL checks, once Paperless answers, that the API lives
+// under PAPERLESS_URL, switching the client to the URL it actually
+// answers under when redirects, trailing slashes or a subpath install
+// make it differ. A redirect to another host is reported rather than
+// followed. While Paperless cannot be reached the check is left for the
+// next probe.
+func (s *Server) resolvePaperlessURL(ctx context.Context) {
+	if checked, _, _ := s.paperlessURL.state(); checked {
+		return
+	}
+
+	configured := s.paperlessClient.BaseURL()
+	resolved, err := s.paperlessClient.ResolveBaseURL(ctx)
+	if err != nil && !errors.Is(err, paperless.ErrNotPaperlessAPI) && !errors.Is(err, paperless.ErrOffHostRedirect) {
+		logging.FromContext(ctx).Debug("Could not check the Paperless URL yet", "error", err)
+		return
+	}
+
+	s.paperlessURL.mu.Lock()
+	defer s.paperlessURL.mu.Unlock()
+	s.paperlessURL.checked = true
+	switch {
+	case errors.Is(err, paperless.ErrOffHostRedirect):
+		s.paperlessURL.problem = err.Error()
+		logging.FromContext(ctx).Error("PAPERLESS_URL redirects to another host, which is not followed; set PAPERLESS_URL to that host if it is trusted",
+			"paperless_url", configured,
+			"error", err)
+	case err != nil:
+		s.paperlessURL.problem = err.Error()
+		logging.FromContext(ctx).Error("PAPERLESS_URL does not serve the Paperless API; set it to the address of the Paperless web UI, including any subpath such as /paperless",
+			"paperless_url", configured,
+			"error", err)
+	case resolved != configured:
+		s.paperlessURL.resolved = resolved
+		s.paperlessClient.SetBaseURL(resolved)
+		logging.FromContext(ctx).Warn("Paperless API answers under a different URL, using it; update PAPERLESS_URL to match",
+			"paperless_url", configured,
+			"resolved_url", resolved)
+	default:
+		logging.FromContext(ctx).Debug("Paperless URL checked", "paperless_url", configured)
+	}
+}
+
+// paperlessURLInfo describes the Paperless URL for server_info: the URL
+// in use and, when it was corrected or found wrong, what was configured
+func (s *Server) paperlessURLInfo(info map[string]interface{}) {
+	info["url"] = s.paperlessClient.BaseURL()
+	checked, resolved, problem := s.paperlessURL.state()
+	info["url_checked"] = checked
+	if resolved != "" {
+		info["configured_url"] = s.cfg.PaperlessURL
+	}
+	if problem != "" {
+		info["url_problem"] = problem
+	}
+}
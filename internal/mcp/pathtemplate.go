@@ -0,0 +1,112 @@
+package mcp
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// storagePathPlaceholders are the variables Paperless fills in when
+// rendering a storage path template, in either the {name} or the
+// Jinja {{ name }} syntax
+var storagePathPlaceholders = map[string]bool{
+	"title":                    true,
+	"correspondent":            true,
+	"document_type":            true,
+	"tag_list":                 true,
+	"tags":                     true,
+	"custom_fields":            true,
+	"asn":                      true,
+	"owner_username":           true,
+	"original_name":            true,
+	"doc_pk":                   true,
+	"document":                 true,
+	"created":                  true,
+	"created_year":             true,
+	"created_year_short":       true,
+	"created_month":            true,
+	"created_month_name":       true,
+	"created_month_name_short": true,
+	"created_day":              true,
+	"created_time":             true,
+	"added":                    true,
+	"added_year":               true,
+	"added_year_short":         true,
+	"added_month":              true,
+	"added_month_name":         true,
+	"added_month_name_short":   true,
+	"added_day":                true,
+	"added_time":               true,
+}
+
+// MaxPlaceholderTypoDistance is the largest edit distance at which an
+// unknown placeholder, search field, API resource or custom field is
+// reported as a likely typo of a known one
+const MaxPlaceholderTypoDistance = 3
+
+var (
+	// jinjaExpressionPattern matches {{ expression }}
+	jinjaExpressionPattern = regexp.MustCompile(`\{\{(.*?)\}\}`)
+	// jinjaStatementPattern matches {% statement %}
+	jinjaStatementPattern = regexp.MustCompile(`\{%(.*?)%\}`)
+	// jinjaBindingPattern matches variables bound by for loops and set
+	jinjaBindingPattern = regexp.MustCompile(`^\s*(?:for\s+([A-Za-z_]\w*)(?:\s*,\s*([A-Za-z_]\w*))?\s+in\b|set\s+([A-Za-z_]\w*)\s*=)`)
+	// legacyPlaceholderPattern matches {name} and {name[key]}
+	legacyPlaceholderPattern = regexp.MustCompile(`\{([^{}]*)\}`)
+	// leadingIdentifierPattern matches the variable an expression starts with
+	leadingIdentifierPattern = regexp.MustCompile(`^\s*([A-Za-z_]\w*)`)
+)
+
+// validateStoragePathTemplate checks that every placeholder in a storage
+// path template is one Paperless supports, so a typo is caught now rather
+// than producing broken paths when documents are filed. Variables bound by
+// Jinja for loops and set statements are accepted; string literals and
+// other expressions not starting with a variable are not checked.
+func validateStoragePathTemplate(template string) error {
+	bound := make(map[string]bool)
+	for _, m := range jinjaStatementPattern.FindAllStringSubmatch(template, -1) {
+		if binding := jinjaBindingPattern.FindStringSubmatch(m[1]); binding != nil {
+			for _, name := range binding[1:] {
+				if name != "" {
+					bound[name] = true
+				}
+			}
+		}
+	}
+
+	var names []string
+	for _, m := range jinjaExpressionPattern.FindAllStringSubmatch(template, -1) {
+		if id := leadingIdentifierPattern.FindStringSubmatch(m[1]); id != nil {
+			names = append(names, id[1])
+		}
+	}
+	legacy := jinjaStatementPattern.ReplaceAllString(jinjaExpressionPattern.ReplaceAllString(template, ""), "")
+	for _, m := range legacyPlaceholderPattern.FindAllStringSubmatch(legacy, -1) {
+		if id := leadingIdentifierPattern.FindStringSubmatch(m[1]); id != nil {
+			names = append(names, id[1])
+		}
+	}
+
+	for _, name := range names {
+		if storagePathPlaceholders[name] || bound[name] {
+			continue
+		}
+		message := fmt.Sprintf("unknown placeholder %q in storage path template", name)
+		if suggestion := closestMatch(name, sortedPlaceholders()); suggestion != "" {
+			message += fmt.Sprintf(", did you mean %q?", suggestion)
+		}
+		return fmt.Errorf("%s; supported placeholders: %s", message, strings.Join(sortedPlaceholders(), ", "))
+	}
+	return nil
+}
+
+// sortedPlaceholders returns the supported placeholders in order
+func sortedPlaceholders() []string {
+	names := make([]string, 0, len(storagePathPlaceholders))
+	for name := range storagePathPlaceholders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,33 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestValidateStoragePathTemplate tests that supported placeholders in
+// both syntaxes pass and typos are rejected with a suggestion
+func TestValidateStoragePathTemplate(t *testing.T) {
+	valid := []string{
+		"{correspondent}/{created_year}/{title}",
+		"{{ correspondent }}/{{ created_year }}-{{ created_month }}/{{ title }}",
+		"{tags[Taxes]}/{asn}",
+		"{{ custom_fields|get_cf_value('Account') }}/{{ title|lower }}",
+		"{% for tag in tag_list %}{{ tag }}/{% endfor %}{{ title }}",
+		"{% if correspondent %}{{ correspondent }}{% else %}unknown{% endif %}/{{ title }}",
+		"invoices/{{ \"fixed\" }}/{{ title }}",
+	}
+	for _, template := range valid {
+		if err := validateStoragePathTemplate(template); err != nil {
+			t.Errorf("Expected %q to be valid, got %v", template, err)
+		}
+	}
+
+	err := validateStoragePathTemplate("{{ correspondent }}/{{ crated_year }}/{{ title }}")
+	if err == nil || !strings.Contains(err.Error(), `did you mean "created_year"`) {
+		t.Errorf("Expected a suggestion for crated_year, got %v", err)
+	}
+	if err := validateStoragePathTemplate("{owner}/{title}"); err == nil || !strings.Contains(err.Error(), "supported placeholders") {
+		t.Errorf("Expected the supported placeholders to be listed, got %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package mcp
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// plannedCall is one Paperless API request of an operation plan
+type plannedCall struct {
+	Step    int         `json:"step"`
+	Method  string      `json:"method"`
+	Path    string      `json:"path"`
+	Body    interface{} `json:"body,omitempty"`
+	Purpose string      `json:"purpose"`
+	// Performed marks reads already made to build the plan; every other
+	// call is only made when the tool runs without plan_only
+	Performed bool `json:"performed"`
+}
+
+// operationPlan is the ordered list of API calls a composite tool would
+// make, returned instead of running it when plan_only is set, so the user
+// or agent can review the calls before executing them
+type operationPlan struct {
+	Tool     string        `json:"tool"`
+	PlanOnly bool          `json:"plan_only"`
+	Calls    []plannedCall `json:"calls"`
+	Writes   int           `json:"writes"`
+	Notes    []string      `json:"notes,omitempty"`
+}
+
+// newOperationPlan starts the plan of a call of tool
+func newOperationPlan(tool string) *operationPlan {
+	return &operationPlan{Tool: tool, PlanOnly: true, Calls: []plannedCall{}}
+}
+
+// planOnly reports whether a tool call asks only for its plan
+func planOnly(args map[string]interface{}) bool {
+	planOnly, _ := args["plan_only"].(bool)
+	return planOnly
+}
+
+// add appends a call, counting those that change Paperless
+func (p *operationPlan) add(call plannedCall) {
+	call.Step = len(p.Calls) + 1
+	p.Calls = append(p.Calls, call)
+	if call.Method != http.MethodGet {
+		p.Writes++
+	}
+}
+
+// performed records a read made while building the plan
+func (p *operationPlan) performed(path, purpose string) {
+	p.add(plannedCall{Method: http.MethodGet, Path: path, Purpose: purpose, Performed: true})
+}
+
+// read records a read the tool would make
+func (p *operationPlan) read(path, purpose string) {
+	p.add(plannedCall{Method: http.MethodGet, Path: path, Purpose: purpose})
+}
+
+// write records a request that would change Paperless
+func (p *operationPlan) write(method, path string, body interface{}, purpose string) {
+	p.add(plannedCall{Method: method, Path: path, Body: body, Purpose: purpose})
+}
+
+// note adds a remark about how the plan may differ when run
+func (p *operationPlan) note(format string, args ...interface{}) {
+	p.Notes = append(p.Notes, fmt.Sprintf(format, args...))
+}
+
+// prepend puts calls made before those already planned, e.g. by a tool
+// that builds on another's plan
+func (p *operationPlan) prepend(calls ...plannedCall) {
+	planned := p.Calls
+	p.Calls, p.Writes = []plannedCall{}, 0
+	for _, call := range append(calls, planned...) {
+		p.add(call)
+	}
+}
+
+// documentsByIDPath is the first page request listDocumentsByID makes for
+// ids
+func documentsByIDPath(ids []int, fields string) string {
+	idStrings := make([]string, len(ids))
+	for i, id := range ids {
+		idStrings[i] = strconv.Itoa(id)
+	}
+	filters := url.Values{}
+	filters.Set("id__in", strings.Join(idStrings, ","))
+	filters.Set("fields", fields)
+	return "/api/documents/?" + filters.Encode()
+}
+
+// planBulkEdit lists the calls handleBulkEditDocuments makes for an edit:
+// the undo snapshot, one request per chunk and the verification reads
+func (s *Server) planBulkEdit(documentIDs []int, operations map[string]interface{}, verify string) *operationPlan {
+	plan := newOperationPlan("bulk_edit_documents")
+
+	if s.cfg.MCPConfirmThreshold > 0 && len(documentIDs) > s.cfg.MCPConfirmThreshold {
+		plan.note("Editing more than %d documents asks the user to confirm first, where the client supports it", s.cfg.MCPConfirmThreshold)
+	}
+	for operation := range operations {
+		if _, ok := bulkEditFields[operation]; ok {
+			plan.read(documentsByIDPath(documentIDs, "id,tags,correspondent,document_type,storage_path"),
+				"Read the fields about to change, for undo_last_operation")
+			break
+		}
+	}
+
+	chunkSize := s.cfg.MCPBulkChunkSize
+	chunks := (len(documentIDs) + chunkSize - 1) / chunkSize
+	for start := 0; start < len(documentIDs); start += chunkSize {
+		chunk := documentIDs[start:min(start+chunkSize, len(documentIDs))]
+		body := map[string]interface{}{"documents": chunk}
+		for key, value := range operations {
+			body[key] = value
+		}
+		purpose := fmt.Sprintf("Apply %s", describeBulkEdit(operations))
+		if chunks > 1 {
+			purpose = fmt.Sprintf("%s to chunk %d of %d", purpose, start/chunkSize+1, chunks)
+		}
+		plan.write(http.MethodPost, "/api/documents/bulk_edit/", body, purpose)
+	}
+	if chunks > 1 {
+		plan.note("Chunks are sent one after another and the edit stops at the first that fails; resume_operation finishes it")
+	}
+
+	if verify != BulkVerifyNone {
+		ids := documentIDs
+		if verify == BulkVerifySample {
+			ids = verifySample(documentIDs, BulkVerifySampleSize)
+		}
+		plan.read(documentsByIDPath(ids, "id,tags,correspondent,document_type,storage_path,user_can_change"),
+			"Re-read the edited documents to verify the edit")
+	}
+	return plan
+}
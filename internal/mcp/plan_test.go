@@ -0,0 +1,106 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/config"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// TestPlanOnly tests that plan_only returns the ordered API calls of
+// triage, bulk edit and mark processed without changing anything
+func TestPlanOnly(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("Unexpected write %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/documents/5/":
+			w.Write([]byte(`{"id":5,"title":"Invoice","tags":[1,2],"archive_serial_number":null}`))
+		case "/api/documents/next_asn/":
+			w.Write([]byte(`42`))
+		case "/api/tags/":
+			w.Write([]byte(`{"count":2,"next":null,"results":[{"id":1,"name":"Inbox","is_inbox_tag":true},{"id":2,"name":"Bills"}]}`))
+		default:
+			t.Errorf("Unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	s := &Server{
+		cfg:             &config.Config{MCPBulkChunkSize: 2, MCPConfirmThreshold: 50},
+		paperlessClient: paperless.New(ts.URL, "test-token"),
+		journal:         newUndoJournal(""),
+		documents:       newDocumentCache(0, 0),
+	}
+
+	paths := func(plan *operationPlan) []string {
+		var calls []string
+		for i, call := range plan.Calls {
+			if call.Step != i+1 {
+				t.Errorf("Call %d numbered step %d", i, call.Step)
+			}
+			calls = append(calls, call.Method+" "+strings.SplitN(call.Path, "?", 2)[0])
+		}
+		return calls
+	}
+
+	result, err := s.handleTriageDocument(context.Background(), map[string]interface{}{
+		"document_id": float64(5),
+		"add_tags":    []interface{}{float64(7)},
+		"assign_asn":  true,
+		"plan_only":   true,
+	})
+	if err != nil {
+		t.Fatalf("triage_document failed: %v", err)
+	}
+	plan := result.(*operationPlan)
+	want := "GET /api/documents/5/,GET /api/tags/,GET /api/documents/next_asn/,PATCH /api/documents/5/"
+	if got := strings.Join(paths(plan), ","); got != want {
+		t.Errorf("Expected triage calls %s, got %s", want, got)
+	}
+	update := plan.Calls[3]
+	if plan.Writes != 1 || update.Performed || !plan.Calls[0].Performed || update.Body.(map[string]interface{})["archive_serial_number"] != 42 {
+		t.Errorf("Unexpected triage plan %+v", plan)
+	}
+
+	// Three documents in chunks of two take two bulk edit requests, after
+	// the undo snapshot and before the verification read
+	result, err = s.handleBulkEditDocuments(context.Background(), map[string]interface{}{
+		"document_ids": []interface{}{float64(5), float64(6), float64(7)},
+		"add_tags":     []interface{}{float64(3)},
+		"verify":       "all",
+		"plan_only":    true,
+	})
+	if err != nil {
+		t.Fatalf("bulk_edit_documents failed: %v", err)
+	}
+	plan = result.(*operationPlan)
+	want = "GET /api/documents/,POST /api/documents/bulk_edit/,POST /api/documents/bulk_edit/,GET /api/documents/"
+	if got := strings.Join(paths(plan), ","); got != want {
+		t.Errorf("Expected bulk edit calls %s, got %s", want, got)
+	}
+	if chunk := plan.Calls[2].Body.(map[string]interface{})["documents"].([]int); plan.Writes != 2 || len(chunk) != 1 || chunk[0] != 7 {
+		t.Errorf("Unexpected bulk edit plan %+v", plan)
+	}
+
+	result, err = s.handleMarkDocumentsProcessed(context.Background(), map[string]interface{}{
+		"document_ids": []interface{}{float64(5)},
+		"plan_only":    true,
+	})
+	if err != nil {
+		t.Fatalf("mark_documents_processed failed: %v", err)
+	}
+	plan = result.(*operationPlan)
+	want = "GET /api/tags/,GET /api/documents/,POST /api/documents/bulk_edit/"
+	if got := strings.Join(paths(plan), ","); got != want || plan.Tool != "mark_documents_processed" || plan.Writes != 1 {
+		t.Errorf("Expected mark processed calls %s, got %s in %+v", want, got, plan)
+	}
+}
@@ -0,0 +1,239 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// Limits for preview_filter_matches
+const (
+	DefaultPreviewSampleSize = 10
+	MaxPreviewSampleSize     = 50
+	DefaultPreviewMaxScan    = 1000
+	MaxPreviewMaxScan        = 10000
+)
+
+// previewDocument is the compact form of a document returned in previews
+type previewDocument struct {
+	ID            int    `json:"id"`
+	Title         string `json:"title"`
+	CreatedDate   string `json:"created_date"`
+	Correspondent *int   `json:"correspondent"`
+	DocumentType  *int   `json:"document_type"`
+	Tags          []int  `json:"tags"`
+	Preview       string `json:"preview,omitempty"`
+}
+
+func newPreviewDocument(d paperless.Document) previewDocument {
+	return previewDocument{
+		ID:            d.ID,
+		Title:         d.Title,
+		CreatedDate:   d.CreatedDate,
+		Correspondent: d.Correspondent,
+		DocumentType:  d.DocumentType,
+		Tags:          d.Tags,
+	}
+}
+
+// filterValues converts a filter object of Paperless document query
+// parameters, such as {"tags__id__all": [1, 2]}, into URL values
+func filterValues(filter map[string]interface{}) (url.Values, error) {
+	values := url.Values{}
+	for key, raw := range filter {
+		if key == "page" || key == "page_size" {
+			return nil, fmt.Errorf("filter must not set %s", key)
+		}
+		var value string
+		switch v := raw.(type) {
+		case string:
+			value = v
+		case float64:
+			value = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			value = strconv.FormatBool(v)
+		case []interface{}:
+			parts := make([]string, 0, len(v))
+			for _, item := range v {
+				parts = append(parts, fmt.Sprint(item))
+			}
+			value = strings.Join(parts, ",")
+		default:
+			return nil, fmt.Errorf("filter %s has unsupported value type %T", key, raw)
+		}
+		values.Set(key, value)
+	}
+	return values, nil
+}
+
+// boundedIntArg returns an integer argument clamped to [1, max]
+func boundedIntArg(args map[string]interface{}, name string, def, max int) int {
+	value := def
+	if v, ok := args[name].(float64); ok {
+		value = int(v)
+		if value < 1 {
+			value = def
+		} else if value > max {
+			value = max
+		}
+	}
+	return value
+}
+
+// handlePreviewFilterMatches handles the preview_filter_matches tool
+func (s *Server) handlePreviewFilterMatches(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	filters := url.Values{}
+	if filter, ok := args["filter"].(map[string]interface{}); ok {
+		values, err := filterValues(filter)
+		if err != nil {
+			return nil, err
+		}
+		filters = values
+	}
+
+	sampleSize := boundedIntArg(args, "sample_size", DefaultPreviewSampleSize, MaxPreviewSampleSize)
+	previewChars, err := previewCharsArg(args)
+	if err != nil {
+		return nil, err
+	}
+
+	algorithmFloat, hasRule := args["matching_algorithm"].(float64)
+	if !hasRule {
+		if len(filters) == 0 {
+			return nil, fmt.Errorf("provide a filter, a matching rule (match and matching_algorithm), or both")
+		}
+		return s.previewFilter(ctx, filters, sampleSize, previewChars)
+	}
+
+	algorithm := int(algorithmFloat)
+	if _, known := paperless.MatchingAlgorithmNames[algorithm]; !known {
+		return nil, fmt.Errorf("matching_algorithm must be between %d and %d", paperless.MatchNone, paperless.MatchAuto)
+	}
+	if algorithm == paperless.MatchAuto {
+		return nil, paperless.ErrMatchNotEvaluable
+	}
+	match, _ := args["match"].(string)
+	insensitive := true
+	if v, ok := args["is_insensitive"].(bool); ok {
+		insensitive = v
+	}
+	maxScan := boundedIntArg(args, "max_documents", DefaultPreviewMaxScan, MaxPreviewMaxScan)
+
+	logging.FromContext(ctx).Debug("Previewing matching rule",
+		"algorithm", algorithm,
+		"filters", filters.Encode(),
+		"max_documents", maxScan)
+
+	// Matching rules run on document content, so scan documents page by page
+	// and evaluate the rule locally
+	sample := []previewDocument{}
+	matched, scanned, total := 0, 0, 0
+	for page := 1; scanned < maxScan; page++ {
+		response, err := s.paperlessClient.ListDocuments(ctx, filters, page, paperless.MaxPageSize)
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to list documents", "page", page, "error", err)
+			return nil, fmt.Errorf("failed to list documents: %w", err)
+		}
+		total = response.Count
+
+		var documents []paperless.Document
+		if err := json.Unmarshal(response.Results, &documents); err != nil {
+			return nil, fmt.Errorf("failed to parse documents: %w", err)
+		}
+
+		for _, document := range documents {
+			if scanned >= maxScan {
+				break
+			}
+			scanned++
+			ok, _, err := paperless.MatchText(match, algorithm, insensitive, document.Content)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+			matched++
+			if len(sample) < sampleSize {
+				preview := newPreviewDocument(document)
+				if previewChars > 0 {
+					preview.Preview = truncatePreview(document.Content, previewChars)
+				}
+				sample = append(sample, preview)
+			}
+		}
+
+		if response.Next == nil || len(documents) == 0 {
+			break
+		}
+	}
+	complete := scanned >= total
+
+	logging.FromContext(ctx).Info("Matching rule preview completed",
+		"matched", matched,
+		"scanned", scanned,
+		"total", total)
+
+	result := map[string]interface{}{
+		"mode":      "matching_rule",
+		"count":     matched,
+		"scanned":   scanned,
+		"total":     total,
+		"complete":  complete,
+		"documents": sample,
+	}
+	if !complete {
+		result["note"] = fmt.Sprintf("only the first %d of %d documents were scanned; raise max_documents or narrow the filter for an exact count", scanned, total)
+	}
+	return result, nil
+}
+
+// previewFilter returns the count and a sample of documents matching a
+// saved-view style filter, evaluated by Paperless, with a preview of each
+// when previewChars is set
+func (s *Server) previewFilter(ctx context.Context, filters url.Values, sampleSize, previewChars int) (interface{}, error) {
+	logging.FromContext(ctx).Debug("Previewing filter", "filters", filters.Encode())
+
+	response, err := s.paperlessClient.ListDocuments(ctx, filters, 1, sampleSize)
+	if err != nil {
+		if paperless.IsValidation(err) {
+			return nil, fmt.Errorf("invalid filter: %w", err)
+		}
+		logging.FromContext(ctx).Error("Failed to preview filter", "error", err)
+		return nil, fmt.Errorf("failed to list documents: %w", err)
+	}
+
+	var documents []paperless.Document
+	if err := json.Unmarshal(response.Results, &documents); err != nil {
+		return nil, fmt.Errorf("failed to parse documents: %w", err)
+	}
+
+	highlights := searchHighlights(response.Results)
+	sample := make([]previewDocument, 0, len(documents))
+	for _, document := range documents {
+		preview := newPreviewDocument(document)
+		if previewChars > 0 {
+			text, ok := highlights[document.ID]
+			if !ok {
+				text = document.Content
+			}
+			preview.Preview = truncatePreview(text, previewChars)
+		}
+		sample = append(sample, preview)
+	}
+
+	logging.FromContext(ctx).Info("Filter preview completed", "count", response.Count)
+
+	return map[string]interface{}{
+		"mode":      "filter",
+		"count":     response.Count,
+		"complete":  true,
+		"documents": sample,
+	}, nil
+}
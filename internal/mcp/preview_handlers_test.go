@@ -0,0 +1,98 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// TestPreviewFilterMatches tests that a filter is translated into
+// Paperless document query parameters and its count reported, and that a
+// matching rule is evaluated over the scanned documents' content
+func TestPreviewFilterMatches(t *testing.T) {
+	var queries []url.Values
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/documents/" {
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+		queries = append(queries, r.URL.Query())
+		if r.URL.Query().Get("page") == "2" {
+			w.Write([]byte(`{"count":3,"next":null,"results":[{"id":3,"title":"Flyer","content":"Pizza delivery"}]}`))
+			return
+		}
+		next := "null"
+		if r.URL.Query().Get("page_size") == fmt.Sprint(paperless.MaxPageSize) {
+			next = `"page-2"`
+		}
+		w.Write([]byte(`{"count":3,"next":` + next + `,"results":[
+			{"id":1,"title":"Invoice 1","content":"ACME Corp invoice for March"},
+			{"id":2,"title":"Invoice 2","content":"Invoice from Globex"}]}`))
+	}))
+	defer ts.Close()
+
+	s := &Server{paperlessClient: paperless.New(ts.URL, "test-token")}
+
+	result, err := s.handlePreviewFilterMatches(context.Background(), map[string]interface{}{
+		"filter": map[string]interface{}{
+			"tags__id__all":      []interface{}{float64(1), float64(2)},
+			"correspondent__id":  float64(5),
+			"is_in_inbox":        true,
+			"title__icontains":   "invoice",
+			"created__date__gte": "2024-01-01",
+		},
+		"sample_size": float64(2),
+	})
+	if err != nil {
+		t.Fatalf("preview_filter_matches failed: %v", err)
+	}
+	preview := result.(map[string]interface{})
+	if preview["mode"] != "filter" || preview["count"] != 3 || len(preview["documents"].([]previewDocument)) != 2 {
+		t.Errorf("Expected 3 matches with a sample of 2, got %v", preview)
+	}
+	want := map[string]string{
+		"tags__id__all":      "1,2",
+		"correspondent__id":  "5",
+		"is_in_inbox":        "true",
+		"title__icontains":   "invoice",
+		"created__date__gte": "2024-01-01",
+		"page_size":          "2",
+	}
+	for key, value := range want {
+		if got := queries[0].Get(key); got != value {
+			t.Errorf("Expected %s=%s, got %q", key, value, got)
+		}
+	}
+
+	queries = nil
+	result, err = s.handlePreviewFilterMatches(context.Background(), map[string]interface{}{
+		"filter":             map[string]interface{}{"document_type__id": float64(4)},
+		"match":              "invoice",
+		"matching_algorithm": float64(paperless.MatchAny),
+	})
+	if err != nil {
+		t.Fatalf("preview_filter_matches failed: %v", err)
+	}
+	preview = result.(map[string]interface{})
+	if preview["mode"] != "matching_rule" || preview["count"] != 2 || preview["scanned"] != 3 || preview["complete"] != true {
+		t.Errorf("Expected 2 of 3 scanned documents to match, got %v", preview)
+	}
+	if len(queries) != 2 || queries[0].Get("document_type__id") != "4" {
+		t.Errorf("Expected two filtered pages to be scanned, got %v", queries)
+	}
+
+	for _, args := range []map[string]interface{}{
+		{},
+		{"filter": map[string]interface{}{"page": float64(2)}},
+		{"filter": map[string]interface{}{"tags__id": map[string]interface{}{}}},
+		{"match": "invoice", "matching_algorithm": float64(paperless.MatchAuto)},
+	} {
+		if _, err := s.handlePreviewFilterMatches(context.Background(), args); err == nil {
+			t.Errorf("Expected %v to fail", args)
+		}
+	}
+}
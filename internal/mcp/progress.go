@@ -0,0 +1,37 @@
+package mcp
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+)
+
+// progressTokenKey is the context key of the progress token a client sent
+// with a tool call
+type progressTokenKey struct{}
+
+// withProgressToken stores the progress token of a tool call in ctx
+func withProgressToken(ctx context.Context, token mcp.ProgressToken) context.Context {
+	return context.WithValue(ctx, progressTokenKey{}, token)
+}
+
+// reportProgress sends a progress notification for the current tool call.
+// It does nothing when the client did not ask for progress.
+func (s *Server) reportProgress(ctx context.Context, progress, total float64, message string) {
+	token := ctx.Value(progressTokenKey{})
+	if token == nil {
+		return
+	}
+
+	err := s.mcpServer.SendNotificationToClient(ctx, "notifications/progress", map[string]any{
+		"progressToken": token,
+		"progress":      progress,
+		"total":         total,
+		"message":       message,
+	})
+	if err != nil {
+		logging.FromContext(ctx).Debug("Failed to send progress notification", "error", err)
+	}
+}
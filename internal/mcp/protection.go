@@ -0,0 +1,131 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+)
+
+// ErrDeleteProtected is returned when a delete targets a document the
+// configured protection rules cover. The rules cannot be overridden by
+// the caller.
+var ErrDeleteProtected = errors.New("protected from deletion by the server's configuration")
+
+// protectedDocument is a document a delete protection rule covers
+type protectedDocument struct {
+	ID     int
+	Title  string
+	Reason string
+}
+
+// deleteProtectionEnabled reports whether any delete protection rule is
+// configured
+func (s *Server) deleteProtectionEnabled() bool {
+	return s.cfg.MCPDeleteProtectYears > 0 || len(s.cfg.MCPDeleteProtectTags) > 0
+}
+
+// protectedTags resolves MCP_DELETE_PROTECT_TAGS to the IDs and names of
+// the tags. The tags are read fresh rather than from the metadata cache,
+// so a protected tag created or renamed moments ago is honoured, and an
+// entry naming no tag is an error: it may be a tag the server cannot see
+// or one whose rename the configuration has not caught up with.
+func (s *Server) protectedTags(ctx context.Context) (map[int]string, error) {
+	if len(s.cfg.MCPDeleteProtectTags) == 0 {
+		return nil, nil
+	}
+	tags, err := s.metadata.tags.fresh(ctx, s.paperlessClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	protected := make(map[int]string)
+	var missing []string
+	for _, entry := range s.cfg.MCPDeleteProtectTags {
+		id, err := strconv.Atoi(entry)
+		isID := err == nil
+		found := false
+		for _, tag := range tags {
+			if (isID && tag.ID == id) || strings.EqualFold(tag.Name, entry) {
+				protected[tag.ID] = tag.Name
+				found = true
+			}
+		}
+		if !found {
+			missing = append(missing, entry)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("protected tags not found in Paperless: %s", strings.Join(missing, ", "))
+	}
+	return protected, nil
+}
+
+// checkDeleteProtection refuses a delete of documents when any of them is
+// covered by a protection rule: created more than MCP_DELETE_PROTECT_YEARS
+// ago, or carrying a tag in MCP_DELETE_PROTECT_TAGS. It fails closed, so a
+// delete is also refused when the documents or tags cannot be read or a
+// protected tag does not resolve.
+// Handlers deleting documents call it before anything else, including
+// asking the user to confirm.
+func (s *Server) checkDeleteProtection(ctx context.Context, documentIDs []int) error {
+	if !s.deleteProtectionEnabled() {
+		return nil
+	}
+
+	tags, err := s.protectedTags(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to check delete protection", "error", err)
+		return fmt.Errorf("cannot check delete protection rules: %w", err)
+	}
+	documents, err := s.listDocumentsByID(ctx, documentIDs, "id,title,created,tags")
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to check delete protection",
+			"document_count", len(documentIDs),
+			"error", err)
+		return fmt.Errorf("cannot check delete protection rules: failed to read documents: %w", err)
+	}
+
+	var cutoff time.Time
+	if years := s.cfg.MCPDeleteProtectYears; years > 0 {
+		cutoff = time.Now().AddDate(-years, 0, 0)
+	}
+	var protected []protectedDocument
+	for _, document := range documents {
+		var reasons []string
+		if !cutoff.IsZero() && !document.Created.IsZero() && document.Created.Before(cutoff) {
+			reasons = append(reasons, fmt.Sprintf("created %s, more than %d years ago",
+				document.Created.Format(time.DateOnly), s.cfg.MCPDeleteProtectYears))
+		}
+		for _, id := range document.Tags {
+			if name, ok := tags[id]; ok {
+				reasons = append(reasons, fmt.Sprintf("tagged %q", name))
+			}
+		}
+		if len(reasons) > 0 {
+			protected = append(protected, protectedDocument{
+				ID:     document.ID,
+				Title:  document.Title,
+				Reason: strings.Join(reasons, " and "),
+			})
+		}
+	}
+	if len(protected) == 0 {
+		return nil
+	}
+
+	sort.Slice(protected, func(i, j int) bool { return protected[i].ID < protected[j].ID })
+	described := make([]string, len(protected))
+	for i, document := range protected {
+		described[i] = fmt.Sprintf("document %d %q (%s)", document.ID, document.Title, document.Reason)
+	}
+	logging.FromContext(ctx).Warn("Delete refused by protection rules",
+		"document_count", len(documentIDs),
+		"protected", len(protected))
+	return fmt.Errorf("%w: %s", ErrDeleteProtected, strings.Join(described, "; "))
+}
@@ -0,0 +1,202 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/config"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// TestDeleteProtection tests that delete_document refuses documents that
+// are too old or carry a protected tag, before anything is deleted, and
+// fails closed when the documents cannot be read or a protected tag does
+// not exist
+func TestDeleteProtection(t *testing.T) {
+	recent := time.Now().AddDate(-1, 0, 0).Format(time.DateOnly)
+	documents := map[string]string{
+		"1": `{"id":1,"title":"Old lease","created":"2010-03-01","tags":[]}`,
+		"2": fmt.Sprintf(`{"id":2,"title":"Lawsuit","created":%q,"tags":[7]}`, recent),
+		"3": fmt.Sprintf(`{"id":3,"title":"Flyer","created":%q,"tags":[2]}`, recent),
+	}
+	var deleted []string
+	failRead := false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodDelete:
+			deleted = append(deleted, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		case r.URL.Path == "/api/tags/":
+			w.Write([]byte(`{"count":2,"next":null,"results":[{"id":2,"name":"Ads"},{"id":7,"name":"Legal-Hold"}]}`))
+		case r.URL.Path == "/api/documents/" && !failRead:
+			if r.URL.Query().Get("fields") != "id,title,created,tags" {
+				t.Errorf("Unexpected fields %q", r.URL.Query().Get("fields"))
+			}
+			w.Write([]byte(`{"count":1,"next":null,"results":[` + documents[r.URL.Query().Get("id__in")] + `]}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer ts.Close()
+
+	s := &Server{
+		cfg:             &config.Config{MCPDeleteProtectYears: 7, MCPDeleteProtectTags: []string{"legal-hold"}},
+		paperlessClient: paperless.New(ts.URL, "test-token"),
+		journal:         newUndoJournal(""),
+		documents:       newDocumentCache(0, 0),
+		metadata:        newMetadataCaches(time.Hour),
+	}
+	// A cached tag list from before the tag was renamed to Legal-Hold must
+	// not be used
+	s.metadata.tags.entries[identityKey(context.Background())] = metadataCacheEntry[paperless.Tag]{
+		items:   []paperless.Tag{{ID: 2, Name: "Ads"}, {ID: 7, Name: "Lawyers"}},
+		fetched: time.Now(),
+	}
+	deleteDocument := func(id int) error {
+		_, err := s.handleDeleteDocument(context.Background(), map[string]interface{}{"document_id": float64(id)})
+		return err
+	}
+
+	for id, reason := range map[int]string{1: "more than 7 years ago", 2: `tagged "Legal-Hold"`} {
+		err := deleteDocument(id)
+		if !errors.Is(err, ErrDeleteProtected) || !strings.Contains(err.Error(), reason) {
+			t.Errorf("Expected document %d refused as %s, got %v", id, reason, err)
+		}
+	}
+	if len(deleted) != 0 {
+		t.Fatalf("Expected no deletes, got %v", deleted)
+	}
+
+	if err := deleteDocument(3); err != nil {
+		t.Fatalf("Expected an unprotected document to be deleted, got %v", err)
+	}
+	if len(deleted) != 1 || deleted[0] != "/api/documents/3/" {
+		t.Errorf("Expected document 3 deleted, got %v", deleted)
+	}
+
+	// A protected tag that does not resolve refuses every delete
+	s.cfg.MCPDeleteProtectTags = []string{"legal-hold", "tax-hold"}
+	if err := deleteDocument(3); err == nil || !strings.Contains(err.Error(), "tax-hold") {
+		t.Errorf("Expected the delete refused for an unknown protected tag, got %v", err)
+	}
+	s.cfg.MCPDeleteProtectTags = []string{"7"}
+
+	// A rule that cannot be checked refuses the delete
+	failRead = true
+	if err := deleteDocument(3); err == nil || !strings.Contains(err.Error(), "cannot check delete protection") {
+		t.Errorf("Expected the delete refused when documents cannot be read, got %v", err)
+	}
+	if len(deleted) != 1 {
+		t.Errorf("Expected no further deletes, got %v", deleted)
+	}
+}
+
+// newProtectionTestServer returns a server protecting documents tagged
+// legal-hold, backed by a fake Paperless serving documents and recording
+// the paths of the deletes it receives
+func newProtectionTestServer(t *testing.T, documents map[string]string, deleted *[]string) *Server {
+	t.Helper()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodDelete:
+			*deleted = append(*deleted, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		case r.URL.Path == "/api/tags/":
+			w.Write([]byte(`{"count":1,"next":null,"results":[{"id":7,"name":"Legal-Hold"}]}`))
+		case r.URL.Path == "/api/documents/":
+			w.Write([]byte(`{"count":1,"next":null,"results":[` + documents[r.URL.Query().Get("id__in")] + `]}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	return &Server{
+		cfg:             &config.Config{MCPDeleteProtectTags: []string{"legal-hold"}},
+		paperlessClient: paperless.New(ts.URL, "test-token"),
+		journal:         newUndoJournal(""),
+		documents:       newDocumentCache(0, 0),
+		metadata:        newMetadataCaches(time.Hour),
+	}
+}
+
+// TestUndoCreateDeleteProtection tests that undoing create_document does
+// not delete a document tagged with a protected tag since it was created,
+// and keeps the entry so the undo can be retried
+func TestUndoCreateDeleteProtection(t *testing.T) {
+	documents := map[string]string{
+		"4": `{"id":4,"title":"Settlement","created":"2025-01-10","tags":[7]}`,
+		"5": `{"id":5,"title":"Receipt","created":"2025-01-10","tags":[]}`,
+	}
+	var deleted []string
+	s := newProtectionTestServer(t, documents, &deleted)
+	ctx := context.Background()
+
+	s.journal.recordCreate(ctx, "create_document", "documents", 4, `Created document "Settlement"`)
+	_, err := s.handleUndoLastOperation(ctx, map[string]interface{}{})
+	if !errors.Is(err, ErrDeleteProtected) || !strings.Contains(err.Error(), "was not undone") {
+		t.Fatalf("Expected the undo refused by delete protection, got %v", err)
+	}
+	if len(deleted) != 0 {
+		t.Fatalf("Expected no deletes, got %v", deleted)
+	}
+	if entry, ok := s.journal.last(identityKey(ctx)); !ok || entry.Objects[0].ID != 4 {
+		t.Errorf("Expected the refused entry to be kept, got %+v", entry)
+	}
+
+	s.journal.recordCreate(ctx, "create_document", "documents", 5, `Created document "Receipt"`)
+	if _, err := s.handleUndoLastOperation(ctx, map[string]interface{}{}); err != nil {
+		t.Fatalf("Expected an unprotected document to be deleted, got %v", err)
+	}
+	if len(deleted) != 1 || deleted[0] != "/api/documents/5/" {
+		t.Errorf("Expected document 5 deleted, got %v", deleted)
+	}
+}
+
+// TestDeleteProtectionCoversEveryDeletePath tests that every handler able
+// to delete a document refuses a protected one. A new way of deleting
+// documents belongs in this table.
+func TestDeleteProtectionCoversEveryDeletePath(t *testing.T) {
+	documents := map[string]string{
+		"8": `{"id":8,"title":"Contract","created":"2025-01-10","tags":[7]}`,
+	}
+	tests := []struct {
+		name   string
+		delete func(ctx context.Context, s *Server) error
+	}{
+		{
+			name: "delete_document",
+			delete: func(ctx context.Context, s *Server) error {
+				_, err := s.handleDeleteDocument(ctx, map[string]interface{}{"document_id": float64(8)})
+				return err
+			},
+		},
+		{
+			name: "undo_last_operation of create_document",
+			delete: func(ctx context.Context, s *Server) error {
+				s.journal.recordCreate(ctx, "create_document", "documents", 8, `Created document "Contract"`)
+				_, err := s.handleUndoLastOperation(ctx, map[string]interface{}{})
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deleted []string
+			s := newProtectionTestServer(t, documents, &deleted)
+			if err := tt.delete(context.Background(), s); !errors.Is(err, ErrDeleteProtected) {
+				t.Errorf("Expected the delete refused by protection, got %v", err)
+			}
+			if len(deleted) != 0 {
+				t.Errorf("Expected no deletes, got %v", deleted)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// handleSaveQuery handles the save_query tool
+func (s *Server) handleSaveQuery(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	name, _ := args["name"].(string)
+	filter, ok := args["filter"].(map[string]interface{})
+	if !ok || len(filter) == 0 {
+		return nil, fmt.Errorf("filter parameter is required and must be a non-empty object")
+	}
+	// Reject filters that could never run before storing them
+	if _, err := filterValues(filter); err != nil {
+		return nil, err
+	}
+	description, _ := args["description"].(string)
+	overwrite, _ := args["overwrite"].(bool)
+
+	logging.FromContext(ctx).Debug("Saving query", "name", name, "overwrite", overwrite)
+
+	query, err := s.savedQueries.save(identityKey(ctx), savedQuery{
+		Name:        name,
+		Description: description,
+		Filter:      filter,
+	}, overwrite)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to save query", "name", name, "error", err)
+		return nil, fmt.Errorf("failed to save query: %w", err)
+	}
+
+	logging.FromContext(ctx).Info("Query saved", "name", query.Name)
+
+	return map[string]interface{}{
+		"success":   true,
+		"query":     query,
+		"persisted": s.cfg.MCPSavedQueriesFile != "",
+	}, nil
+}
+
+// handleListSavedQueries handles the list_saved_queries tool
+func (s *Server) handleListSavedQueries(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	queries := s.savedQueries.list(identityKey(ctx))
+
+	logging.FromContext(ctx).Info("Saved queries listed", "count", len(queries))
+
+	// Saved queries are few and returned as a single page
+	return newListResult(queries, len(queries), 1, len(queries), nil), nil
+}
+
+// handleRunSavedQuery handles the run_saved_query tool
+func (s *Server) handleRunSavedQuery(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	name, _ := args["name"].(string)
+	query, err := s.savedQueries.get(identityKey(ctx), name)
+	if err != nil {
+		return nil, err
+	}
+
+	filters, err := filterValues(query.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("saved query %q has an invalid filter: %w", query.Name, err)
+	}
+	sessionTags := s.applyDefaultTags(ctx, filters)
+
+	// Extract optional page parameter
+	page := DefaultPage
+	if pageVal, ok := args["page"].(float64); ok {
+		page = int(pageVal)
+		if page < 1 {
+			page = DefaultPage
+		}
+	}
+
+	pageSize := boundedIntArg(args, "page_size", DefaultPageSize, MaxPageSize)
+
+	previewChars, err := previewCharsArg(args)
+	if err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Debug("Running saved query",
+		"name", query.Name,
+		"page", page,
+		"page_size", pageSize)
+
+	response, err := s.paperlessClient.ListDocuments(ctx, filters, page, pageSize)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to run saved query",
+			"name", query.Name,
+			"error", err)
+		return nil, fmt.Errorf("failed to run saved query: %w", err)
+	}
+
+	var documents []paperless.Document
+	if err := json.Unmarshal(response.Results, &documents); err != nil {
+		logging.FromContext(ctx).Error("Failed to parse documents results", "error", err)
+		return nil, fmt.Errorf("failed to parse results: %w", err)
+	}
+
+	logging.FromContext(ctx).Info("Saved query completed",
+		"name", query.Name,
+		"found", response.Count,
+		"returned", len(documents))
+
+	applied := map[string]interface{}{
+		"saved_query": query.Name,
+		"filter":      query.Filter,
+	}
+	if sessionTags != nil {
+		applied["session_tags"] = sessionTags
+	}
+	var items interface{} = documents
+	if previewChars > 0 {
+		items = previewDocuments(documents, searchHighlights(response.Results), previewChars)
+	}
+	return newListResult(items, response.Count, page, pageSize, applied), nil
+}
+
+// handleDeleteSavedQuery handles the delete_saved_query tool
+func (s *Server) handleDeleteSavedQuery(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	name, _ := args["name"].(string)
+
+	if err := s.savedQueries.remove(identityKey(ctx), name); err != nil {
+		logging.FromContext(ctx).Error("Failed to delete saved query", "name", name, "error", err)
+		return nil, fmt.Errorf("failed to delete saved query: %w", err)
+	}
+
+	logging.FromContext(ctx).Info("Saved query deleted", "name", name)
+
+	return map[string]interface{}{
+		"success": true,
+		"message": fmt.Sprintf("Saved query %q deleted", name),
+	}, nil
+}
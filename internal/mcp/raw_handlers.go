@@ -0,0 +1,74 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/config"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+)
+
+// rawAPIPath checks that a raw_api_get path is a clean path under one of
+// the allowed prefixes, returning it with the trailing slash Paperless
+// expects
+func rawAPIPath(requested string, allowed []string) (string, error) {
+	if requested == "" {
+		return "", fmt.Errorf("path parameter is required and must be a non-empty string")
+	}
+	if strings.ContainsAny(requested, "?#") {
+		return "", fmt.Errorf("path must not include a query or fragment, pass query parameters in query")
+	}
+	cleaned := path.Clean(requested)
+	if !strings.HasPrefix(requested, "/") || cleaned != strings.TrimSuffix(requested, "/") {
+		return "", fmt.Errorf("path must be an absolute path without . or .. segments, got %q", requested)
+	}
+	cleaned += "/"
+	for _, prefix := range allowed {
+		if strings.HasPrefix(cleaned, prefix) {
+			return cleaned, nil
+		}
+	}
+	return "", fmt.Errorf("path %s is not allowed, %s permits: %s", cleaned, config.EnvMCPRawAPIPaths, strings.Join(allowed, ", "))
+}
+
+// handleRawAPIGet handles the raw_api_get tool
+func (s *Server) handleRawAPIGet(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	requested, _ := args["path"].(string)
+	apiPath, err := rawAPIPath(requested, s.cfg.MCPRawAPIPaths)
+	if err != nil {
+		return nil, err
+	}
+
+	rawQuery, _ := args["query"].(string)
+	query, err := url.ParseQuery(strings.TrimPrefix(rawQuery, "?"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid query: %w", err)
+	}
+	if encoded := query.Encode(); encoded != "" {
+		apiPath += "?" + encoded
+	}
+
+	logging.FromContext(ctx).Debug("Raw API request", "path", apiPath)
+
+	data, err := s.paperlessClient.GET(ctx, apiPath)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed raw API request", "path", apiPath, "error", err)
+		return nil, fmt.Errorf("failed to get %s: %w", apiPath, err)
+	}
+
+	var response interface{}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("%s did not return JSON: %w", apiPath, err)
+	}
+
+	logging.FromContext(ctx).Info("Raw API request completed", "path", apiPath, "bytes", len(data))
+
+	return map[string]interface{}{
+		"path":     apiPath,
+		"response": response,
+	}, nil
+}
@@ -0,0 +1,55 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/config"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// TestRawAPIGet tests that raw_api_get forwards the query to an allowed
+// path and refuses paths outside the allowlist
+func TestRawAPIGet(t *testing.T) {
+	var gotURI string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("Expected GET, got %s", r.Method)
+		}
+		gotURI = r.URL.RequestURI()
+		w.Write([]byte(`{"count":1,"results":[{"id":7}]}`))
+	}))
+	defer ts.Close()
+
+	s := &Server{
+		cfg:             &config.Config{MCPRawAPIPaths: []string{"/api/documents/"}},
+		paperlessClient: paperless.New(ts.URL, "test-token"),
+	}
+
+	result, err := s.handleRawAPIGet(context.Background(), map[string]interface{}{
+		"path":  "/api/documents",
+		"query": "title__icontains=invoice",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if gotURI != "/api/documents/?title__icontains=invoice" {
+		t.Errorf("Expected the query to be forwarded, got %s", gotURI)
+	}
+	response := result.(map[string]interface{})["response"].(map[string]interface{})
+	if response["count"] != float64(1) {
+		t.Errorf("Expected the JSON response, got %v", response)
+	}
+
+	for _, path := range []string{"/api/tags/", "/api/documents/../users/", "/api/documents/?page=2", "api/documents/", ""} {
+		gotURI = ""
+		if _, err := s.handleRawAPIGet(context.Background(), map[string]interface{}{"path": path}); err == nil {
+			t.Errorf("Expected %q to be refused", path)
+		}
+		if gotURI != "" {
+			t.Errorf("Expected no request for %q, got %s", path, gotURI)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/config"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// renameCheck is what is known about an object before it is renamed
+type renameCheck struct {
+	OldName    string
+	OldSlug    string
+	References []paperless.StoragePath // storage paths whose template mentions the old name or slug
+}
+
+// checkRename looks up the current name and slug of an object about to be
+// renamed and the storage paths whose templates mention either, since
+// Paperless does not update templates on rename and filing silently
+// changes. It returns nil when updates do not rename the object or the
+// check is off, and an error in fail mode when templates would be
+// affected. Lookup failures are logged and skip the check.
+func (s *Server) checkRename(ctx context.Context, resource string, id int, updates map[string]interface{}) (*renameCheck, error) {
+	mode := s.cfg.MCPRenameCheck
+	if mode != config.RenameCheckWarn && mode != config.RenameCheckFail {
+		return nil, nil
+	}
+	newName, ok := updates["name"].(string)
+	if !ok {
+		return nil, nil
+	}
+
+	var current struct {
+		Name string `json:"name"`
+		Slug string `json:"slug"`
+	}
+	data, err := s.paperlessClient.GET(ctx, objectPath(resource, id))
+	if err == nil {
+		err = json.Unmarshal(data, &current)
+	}
+	if err != nil {
+		logging.FromContext(ctx).Warn("Failed to read object before rename, skipping rename check",
+			"resource", resource,
+			"id", id,
+			"error", err)
+		return nil, nil
+	}
+	if current.Name == newName {
+		return nil, nil
+	}
+
+	check := &renameCheck{OldName: current.Name, OldSlug: current.Slug}
+	paths, err := s.metadata.storagePaths.get(ctx, s.paperlessClient)
+	if err != nil {
+		logging.FromContext(ctx).Warn("Failed to list storage paths, skipping rename check",
+			"resource", resource,
+			"id", id,
+			"error", err)
+		return check, nil
+	}
+	for _, path := range paths {
+		if templateMentions(path.Path, current.Name, current.Slug) {
+			check.References = append(check.References, path)
+		}
+	}
+
+	if len(check.References) > 0 && mode == config.RenameCheckFail {
+		return nil, fmt.Errorf("%q was not renamed: storage path templates %s mention it and would no longer match; update them first or set %s=warn",
+			current.Name, describeStoragePaths(check.References), config.EnvMCPRenameCheck)
+	}
+	return check, nil
+}
+
+// templateLiteralPattern matches a quoted string in a Jinja expression
+var templateLiteralPattern = regexp.MustCompile(`"([^"]*)"|'([^']*)'`)
+
+// templateMentions reports whether a storage path template names name or
+// slug as a whole segment, ignoring case, so renaming "Tax" does not flag
+// a template filing under "Taxes"
+func templateMentions(template, name, slug string) bool {
+	for _, segment := range templateSegments(template) {
+		for _, value := range []string{name, slug} {
+			if value != "" && strings.EqualFold(segment, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// templateSegments returns the values a storage path template can name an
+// object by: the literal path segments between placeholders and slashes,
+// without separators next to a placeholder,
+// the quoted strings of Jinja expressions and statements, and the keys of
+// {name[key]} placeholders
+func templateSegments(template string) []string {
+	var segments []string
+	for _, pattern := range []*regexp.Regexp{jinjaExpressionPattern, jinjaStatementPattern} {
+		for _, m := range pattern.FindAllStringSubmatch(template, -1) {
+			for _, literal := range templateLiteralPattern.FindAllStringSubmatch(m[1], -1) {
+				segments = append(segments, literal[1]+literal[2])
+			}
+		}
+	}
+	literal := jinjaStatementPattern.ReplaceAllString(jinjaExpressionPattern.ReplaceAllString(template, "/"), "/")
+	for _, m := range legacyPlaceholderPattern.FindAllStringSubmatch(literal, -1) {
+		if open := strings.Index(m[1], "["); open >= 0 && strings.HasSuffix(m[1], "]") {
+			segments = append(segments, strings.Trim(m[1][open+1:len(m[1])-1], `"'`))
+		}
+	}
+	for _, segment := range strings.Split(legacyPlaceholderPattern.ReplaceAllString(literal, "/"), "/") {
+		// Separators next to a placeholder are not part of the name
+		if segment = strings.Trim(segment, " -_."); segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
+
+// describeStoragePaths lists storage paths as name (id) for messages
+func describeStoragePaths(paths []paperless.StoragePath) string {
+	names := make([]string, len(paths))
+	for i, path := range paths {
+		names[i] = fmt.Sprintf("%q (%d)", path.Name, path.ID)
+	}
+	return strings.Join(names, ", ")
+}
+
+// withRenameReport adds a rename section to the updated object, reporting
+// the slug change and any storage path templates that mention the old
+// name. The object is returned unchanged when check is nil.
+func withRenameReport(ctx context.Context, updated interface{}, newSlug string, check *renameCheck) (interface{}, error) {
+	if check == nil {
+		return updated, nil
+	}
+
+	data, err := json.Marshal(updated)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode result: %w", err)
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to encode result: %w", err)
+	}
+
+	report := map[string]interface{}{
+		"previous_name": check.OldName,
+		"previous_slug": check.OldSlug,
+		"slug_changed":  newSlug != check.OldSlug,
+	}
+	if len(check.References) > 0 {
+		references := make([]map[string]interface{}, len(check.References))
+		for i, path := range check.References {
+			references[i] = map[string]interface{}{
+				"id":   path.ID,
+				"name": path.Name,
+				"path": path.Path,
+			}
+		}
+		report["referencing_storage_paths"] = references
+		report["warning"] = fmt.Sprintf("storage path templates %s mention the previous name or slug and are not updated by Paperless; documents may be filed differently until they are edited",
+			describeStoragePaths(check.References))
+		logging.FromContext(ctx).Warn("Renamed object is referenced by storage path templates",
+			"previous_name", check.OldName,
+			"storage_paths", len(check.References))
+	}
+	result["rename"] = report
+	return result, nil
+}
@@ -0,0 +1,86 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/config"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// TestRenameCheck tests that renames report the slug change and storage
+// path templates mentioning the old name, and are refused in fail mode
+func TestRenameCheck(t *testing.T) {
+	var patches int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/storage_paths/":
+			w.Write([]byte(`{"count":2,"next":null,"results":[
+				{"id":1,"name":"Utilities","path":"{{ correspondent }}/bell-canada/{{ created_year }}"},
+				{"id":2,"name":"Default","path":"{{ created_year }}/{{ title }}"}]}`))
+		case r.Method == http.MethodPatch:
+			patches++
+			w.Write([]byte(`{"id":4,"name":"Bell","slug":"bell"}`))
+		default:
+			w.Write([]byte(`{"id":4,"name":"Bell Canada","slug":"bell-canada"}`))
+		}
+	}))
+	defer ts.Close()
+
+	s := &Server{
+		cfg:             &config.Config{MCPRenameCheck: config.RenameCheckWarn},
+		paperlessClient: paperless.New(ts.URL, "test-token"),
+		journal:         newUndoJournal(""),
+	}
+	ctx := context.Background()
+	args := map[string]interface{}{"correspondent_id": float64(4), "name": "Bell"}
+
+	result, err := s.handleUpdateCorrespondent(ctx, args)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	report, ok := result.(map[string]interface{})["rename"].(map[string]interface{})
+	if !ok || report["slug_changed"] != true || report["previous_slug"] != "bell-canada" {
+		t.Fatalf("Expected a rename report with the slug change, got %v", result)
+	}
+	references := report["referencing_storage_paths"].([]map[string]interface{})
+	if len(references) != 1 || references[0]["id"] != 1 {
+		t.Errorf("Expected storage path 1 to be reported, got %v", references)
+	}
+
+	s.cfg.MCPRenameCheck = config.RenameCheckFail
+	_, err = s.handleUpdateCorrespondent(ctx, args)
+	if err == nil || !strings.Contains(err.Error(), `"Utilities" (1)`) {
+		t.Errorf("Expected the rename to be refused naming the storage path, got %v", err)
+	}
+	if patches != 1 {
+		t.Errorf("Expected only the first rename to be sent, got %d", patches)
+	}
+}
+
+// TestTemplateMentions tests that only whole segments of a template match
+// a name or slug
+func TestTemplateMentions(t *testing.T) {
+	for _, tc := range []struct {
+		template string
+		want     bool
+	}{
+		{"Tax/{{ created_year }}", true},
+		{"{{ created_year }}/tax", true},
+		{"Tax-{{ created_year }}/{{ title }}", true},
+		{`{% if correspondent == "Tax" %}Finance{% endif %}/{{ title }}`, true},
+		{`{{ custom_fields|get_cf_value('tax-office') }}/{title}`, true},
+		{"{tags[Tax]}/{title}", true},
+		{"Taxes/{{ created_year }}", false},
+		{"{{ created_year }}/Tax Returns", false},
+		{`{% if correspondent == "Taxi" %}x{% endif %}`, false},
+		{"{{ tax_year }}/{title}", false},
+	} {
+		if got := templateMentions(tc.template, "Tax", "tax-office"); got != tc.want {
+			t.Errorf("templateMentions(%q) = %v, want %v", tc.template, got, tc.want)
+		}
+	}
+}
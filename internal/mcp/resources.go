@@ -0,0 +1,56 @@
+package mcp
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// DocumentResourceURIFormat is the URI of a document file embedded in a
+// tool result; the second verb is "original" or "archive"
+const DocumentResourceURIFormat = "paperless://documents/%d/%s"
+
+// contentResult is a tool result that also carries files, returned to MCP
+// clients as extra content blocks (embedded resources or images) next to
+// the structured JSON. It marshals as the plain result so the JSON form is
+// unchanged.
+type contentResult struct {
+	Result  interface{}
+	Content []mcp.Content
+}
+
+// MarshalJSON encodes only the structured result
+func (r *contentResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.Result)
+}
+
+// withContent attaches extra content blocks to a tool result
+func withContent(result interface{}, content ...mcp.Content) *contentResult {
+	return &contentResult{Result: result, Content: content}
+}
+
+// withResources attaches embedded resources to a tool result
+func withResources(result interface{}, resources ...mcp.ResourceContents) *contentResult {
+	content := make([]mcp.Content, 0, len(resources))
+	for _, resource := range resources {
+		content = append(content, mcp.NewEmbeddedResource(resource))
+	}
+	return withContent(result, content...)
+}
+
+// documentResource builds an embedded resource for a downloaded document file
+func documentResource(documentID int, original bool, file *paperless.DocumentFile) mcp.BlobResourceContents {
+	version := "archive"
+	if original {
+		version = "original"
+	}
+	return mcp.BlobResourceContents{
+		URI:      fmt.Sprintf(DocumentResourceURIFormat, documentID, version),
+		MIMEType: file.ContentType,
+		Blob:     base64.StdEncoding.EncodeToString(file.Content),
+		Meta:     map[string]any{"filename": file.Filename},
+	}
+}
@@ -0,0 +1,141 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// RootsRequestTimeout bounds how long the client may take to list its roots
+const RootsRequestTimeout = 10 * time.Second
+
+// supportsRoots reports whether the client of the current session
+// advertised filesystem roots
+func supportsRoots(ctx context.Context) bool {
+	session, ok := server.ClientSessionFromContext(ctx).(server.SessionWithClientInfo)
+	return ok && session.GetClientCapabilities().Roots != nil
+}
+
+// clientRoots returns the local directories the client allows file access
+// to, and whether access is restricted to them at all: clients that do not
+// advertise roots leave file paths unrestricted. The client is asked on
+// every call, as its roots may change during a session.
+func (s *Server) clientRoots(ctx context.Context) ([]string, bool, error) {
+	if !supportsRoots(ctx) {
+		return nil, false, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, RootsRequestTimeout)
+	defer cancel()
+
+	result, err := s.mcpServer.RequestRoots(ctx, mcp.ListRootsRequest{})
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to list the client's roots: %w", err)
+	}
+
+	roots := make([]string, 0, len(result.Roots))
+	for _, root := range result.Roots {
+		dir, err := rootPath(root.URI)
+		if err != nil {
+			logging.FromContext(ctx).Warn("Ignoring client root", "uri", root.URI, "error", err)
+			continue
+		}
+		roots = append(roots, dir)
+	}
+	return roots, true, nil
+}
+
+// rootPath converts a file:// root URI to a clean local path with
+// symbolic links resolved
+func rootPath(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "file" || u.Path == "" {
+		return "", fmt.Errorf("not a file:// URI")
+	}
+	if u.Host != "" && u.Host != "localhost" {
+		return "", fmt.Errorf("root is on another host")
+	}
+	return resolvePath(filepath.FromSlash(u.Path))
+}
+
+// resolvePath makes path absolute and resolves symbolic links in it. A
+// path that does not exist yet, such as a file about to be written, has
+// the links in its parent directory resolved.
+func resolvePath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	resolved, err := filepath.EvalSymlinks(abs)
+	if errors.Is(err, os.ErrNotExist) {
+		dir, err := filepath.EvalSymlinks(filepath.Dir(abs))
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(dir, filepath.Base(abs)), nil
+	}
+	return resolved, err
+}
+
+// withinRoots reports whether path lies in one of roots
+func withinRoots(path string, roots []string) bool {
+	for _, root := range roots {
+		rel, err := filepath.Rel(root, path)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkFilePath returns the path a file tool may read or write for the
+// path argument named param, refusing paths outside the client's roots
+// when it advertises any
+func (s *Server) checkFilePath(ctx context.Context, param, path string) (string, error) {
+	roots, restricted, err := s.clientRoots(ctx)
+	if err != nil {
+		return "", err
+	}
+	if !restricted {
+		return path, nil
+	}
+
+	resolved, err := resolvePath(path)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s: %w", param, err)
+	}
+	if !withinRoots(resolved, roots) {
+		logging.FromContext(ctx).Warn("Refused file path outside the client's roots",
+			"param", param,
+			"path", resolved)
+		if len(roots) == 0 {
+			return "", fmt.Errorf("%s %s is not allowed, the client has not shared any directories", param, path)
+		}
+		return "", fmt.Errorf("%s %s is outside the directories the client allows: %s", param, path, strings.Join(roots, ", "))
+	}
+	return resolved, nil
+}
+
+// fileAccess describes where file tools may read and write, for server_info
+func (s *Server) fileAccess(ctx context.Context) map[string]interface{} {
+	roots, restricted, err := s.clientRoots(ctx)
+	access := map[string]interface{}{"restricted": restricted}
+	if err != nil {
+		access["error"] = err.Error()
+	} else if restricted {
+		access["roots"] = roots
+	}
+	return access
+}
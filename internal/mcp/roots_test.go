@@ -0,0 +1,70 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// rootsSession is a client session that shares fixed filesystem roots
+type rootsSession struct {
+	elicitingSession
+	roots []mcp.Root
+}
+
+func (s *rootsSession) ListRoots(ctx context.Context, request mcp.ListRootsRequest) (*mcp.ListRootsResult, error) {
+	return &mcp.ListRootsResult{Roots: s.roots}, nil
+}
+
+// TestCheckFilePath tests that file paths are confined to the client's
+// roots when it shares any, including through symbolic links
+func TestCheckFilePath(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0", server.WithRoots())
+	s := &Server{mcpServer: mcpServer}
+
+	shared := t.TempDir()
+	private := t.TempDir()
+	if err := os.Symlink(private, filepath.Join(shared, "escape")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	// Clients without roots support leave paths unrestricted
+	if path, err := s.checkFilePath(context.Background(), "file_path", "/etc/hosts"); err != nil || path != "/etc/hosts" {
+		t.Errorf("Expected an unrestricted path without roots support, got %q, %v", path, err)
+	}
+
+	session := &rootsSession{
+		elicitingSession: elicitingSession{capabilities: mcp.ClientCapabilities{Roots: &struct {
+			ListChanged bool `json:"listChanged,omitempty"`
+		}{}}},
+		roots: []mcp.Root{{URI: "file://" + filepath.ToSlash(shared), Name: "shared"}, {URI: "https://example.com/"}},
+	}
+	ctx := mcpServer.WithContext(context.Background(), session)
+
+	for _, path := range []string{filepath.Join(shared, "scan.pdf"), filepath.Join(shared, "sub", "..", "export.json")} {
+		if _, err := s.checkFilePath(ctx, "file_path", path); err != nil {
+			t.Errorf("Expected %s to be allowed, got %v", path, err)
+		}
+	}
+	for _, path := range []string{filepath.Join(private, "secret.pdf"), filepath.Join(shared, "..", "other"), filepath.Join(shared, "escape", "secret.pdf")} {
+		_, err := s.checkFilePath(ctx, "file_path", path)
+		if err == nil || !strings.Contains(err.Error(), "outside the directories the client allows") {
+			t.Errorf("Expected %s to be refused, got %v", path, err)
+		}
+	}
+
+	access := s.fileAccess(ctx)
+	if access["restricted"] != true || len(access["roots"].([]string)) != 1 {
+		t.Errorf("Expected one usable root to be reported, got %v", access)
+	}
+
+	session.roots = nil
+	if _, err := s.checkFilePath(ctx, "output_path", filepath.Join(shared, "export.json")); err == nil || !strings.Contains(err.Error(), "not shared any directories") {
+		t.Errorf("Expected every path to be refused without roots, got %v", err)
+	}
+}
@@ -0,0 +1,191 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Saved query limits
+const (
+	// MaxSavedQueryNameLength bounds the length of a saved query name
+	MaxSavedQueryNameLength = 100
+
+	// MaxSavedQueries bounds how many queries one identity can save
+	MaxSavedQueries = 200
+)
+
+// savedQuery is a named set of document filters stored on the MCP side
+type savedQuery struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Filter      map[string]interface{} `json:"filter"`
+	CreatedAt   time.Time              `json:"created_at"`
+	UpdatedAt   time.Time              `json:"updated_at"`
+}
+
+// savedQueryStore keeps saved queries per Paperless identity in memory
+// and, when a path is configured, in a small JSON file
+type savedQueryStore struct {
+	path string
+
+	mu      sync.Mutex
+	queries map[string]map[string]savedQuery // identity -> lowercased name -> query
+}
+
+// newSavedQueryStore creates a store, loading persisted queries from path
+// when it is non-empty
+func newSavedQueryStore(path string) *savedQueryStore {
+	store := &savedQueryStore{
+		path:    path,
+		queries: make(map[string]map[string]savedQuery),
+	}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err == nil {
+			if err := json.Unmarshal(data, &store.queries); err != nil {
+				slog.Warn("Ignoring unreadable saved queries file", "path", path, "error", err)
+				store.queries = make(map[string]map[string]savedQuery)
+			}
+		} else if !os.IsNotExist(err) {
+			slog.Warn("Failed to read saved queries file", "path", path, "error", err)
+		}
+	}
+
+	return store
+}
+
+// normalizeQueryName validates a query name and returns its lookup key
+func normalizeQueryName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("name parameter is required and must be a non-empty string")
+	}
+	if len(name) > MaxSavedQueryNameLength {
+		return "", fmt.Errorf("name must be at most %d characters", MaxSavedQueryNameLength)
+	}
+	return strings.ToLower(name), nil
+}
+
+// save stores query for identity. An existing query with the same name is
+// only replaced when overwrite is set.
+func (st *savedQueryStore) save(identity string, query savedQuery, overwrite bool) (savedQuery, error) {
+	key, err := normalizeQueryName(query.Name)
+	if err != nil {
+		return savedQuery{}, err
+	}
+	query.Name = strings.TrimSpace(query.Name)
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	queries := st.queries[identity]
+	if queries == nil {
+		queries = make(map[string]savedQuery)
+		st.queries[identity] = queries
+	}
+
+	now := time.Now().UTC()
+	query.CreatedAt, query.UpdatedAt = now, now
+	previous, existed := queries[key]
+	if existed {
+		if !overwrite {
+			return savedQuery{}, fmt.Errorf("a saved query named %q already exists; set overwrite to replace it", previous.Name)
+		}
+		query.CreatedAt = previous.CreatedAt
+	} else if len(queries) >= MaxSavedQueries {
+		return savedQuery{}, fmt.Errorf("at most %d queries can be saved", MaxSavedQueries)
+	}
+
+	queries[key] = query
+	if err := st.saveLocked(); err != nil {
+		if existed {
+			queries[key] = previous
+		} else {
+			delete(queries, key)
+		}
+		return savedQuery{}, err
+	}
+	return query, nil
+}
+
+// get returns the query saved under name for identity
+func (st *savedQueryStore) get(identity, name string) (savedQuery, error) {
+	key, err := normalizeQueryName(name)
+	if err != nil {
+		return savedQuery{}, err
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	query, ok := st.queries[identity][key]
+	if !ok {
+		return savedQuery{}, fmt.Errorf("no saved query named %q", strings.TrimSpace(name))
+	}
+	return query, nil
+}
+
+// list returns the queries saved by identity, sorted by name
+func (st *savedQueryStore) list(identity string) []savedQuery {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	queries := make([]savedQuery, 0, len(st.queries[identity]))
+	for _, query := range st.queries[identity] {
+		queries = append(queries, query)
+	}
+	sort.Slice(queries, func(i, j int) bool {
+		return strings.ToLower(queries[i].Name) < strings.ToLower(queries[j].Name)
+	})
+	return queries
+}
+
+// remove deletes the query saved under name for identity
+func (st *savedQueryStore) remove(identity, name string) error {
+	key, err := normalizeQueryName(name)
+	if err != nil {
+		return err
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	previous, ok := st.queries[identity][key]
+	if !ok {
+		return fmt.Errorf("no saved query named %q", strings.TrimSpace(name))
+	}
+	delete(st.queries[identity], key)
+	if err := st.saveLocked(); err != nil {
+		st.queries[identity][key] = previous
+		return err
+	}
+	return nil
+}
+
+// saveLocked persists queries when a path is configured; callers must
+// hold st.mu. Unlike the idempotency store, failures are returned since
+// the user explicitly asked for the query to be kept.
+func (st *savedQueryStore) saveLocked() error {
+	if st.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(st.queries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode saved queries: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(st.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create saved queries directory: %w", err)
+	}
+	if err := os.WriteFile(st.path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write saved queries file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package mcp
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestSavedQueryStore tests name handling, overwrite protection,
+// per-identity scoping and persistence across restarts
+func TestSavedQueryStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config", "saved_queries.json")
+	store := newSavedQueryStore(path)
+
+	filter := map[string]interface{}{"tags__id__all": []interface{}{float64(4)}}
+	if _, err := store.save("alice", savedQuery{Name: " Unpaid invoices ", Filter: filter}, false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := store.save("alice", savedQuery{Name: "unpaid INVOICES", Filter: filter}, false); err == nil {
+		t.Error("Expected saving a duplicate name without overwrite to fail")
+	}
+	if _, err := store.save("alice", savedQuery{Name: "unpaid invoices", Description: "updated", Filter: filter}, true); err != nil {
+		t.Fatalf("Unexpected error overwriting: %v", err)
+	}
+	if _, err := store.get("bob", "Unpaid invoices"); err == nil {
+		t.Error("Expected queries to be scoped per identity")
+	}
+
+	reloaded := newSavedQueryStore(path)
+	query, err := reloaded.get("alice", "UNPAID INVOICES")
+	if err != nil {
+		t.Fatalf("Expected query to be persisted: %v", err)
+	}
+	if query.Description != "updated" || query.Name != "unpaid invoices" {
+		t.Errorf("Unexpected reloaded query: %+v", query)
+	}
+
+	if err := reloaded.remove("alice", "unpaid invoices"); err != nil {
+		t.Fatalf("Unexpected error removing: %v", err)
+	}
+	if queries := newSavedQueryStore(path).list("alice"); len(queries) != 0 {
+		t.Errorf("Expected no queries after removal, got %+v", queries)
+	}
+}
@@ -0,0 +1,356 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// filterRuleTypeNames names saved view filter rule types
+var filterRuleTypeNames = func() map[int]string {
+	names := make(map[int]string, len(paperless.FilterRuleTypes))
+	for _, ruleType := range paperless.FilterRuleTypes {
+		names[ruleType.Value.(int)] = ruleType.Name
+	}
+	return names
+}()
+
+// filterRuleObjects is the kind of object whose ID a filter rule holds as
+// its value, for the rule types that refer to one
+var filterRuleObjects = map[int]string{
+	3: "correspondent", 26: "correspondent", 27: "correspondent",
+	4: "document_type", 28: "document_type", 29: "document_type",
+	6: "tag", 17: "tag", 22: "tag",
+	25: "storage_path", 30: "storage_path", 31: "storage_path",
+	38: "custom_field", 39: "custom_field", 40: "custom_field",
+}
+
+// filterRuleParam is the documents list filter a saved view filter rule
+// type translates to, as the Paperless UI sends it. Rules of a multi type
+// are combined into one comma separated value; a rule with no value
+// filters by isNull instead, where the type has one.
+type filterRuleParam struct {
+	param  string
+	multi  bool
+	isNull string
+}
+
+// filterRuleParams translates every filter rule type of FilterRuleTypes,
+// following the rule type table of the Paperless UI
+var filterRuleParams = map[int]filterRuleParam{
+	0:  {param: "title__icontains"},
+	1:  {param: "content__icontains"},
+	2:  {param: "archive_serial_number"},
+	3:  {param: "correspondent__id", isNull: "correspondent__isnull"},
+	4:  {param: "document_type__id", isNull: "document_type__isnull"},
+	5:  {param: "is_in_inbox"},
+	6:  {param: "tags__id__all", multi: true},
+	7:  {param: "is_tagged"},
+	8:  {param: "created__date__lt"},
+	9:  {param: "created__date__gt"},
+	10: {param: "created__year"},
+	11: {param: "created__month"},
+	12: {param: "created__day"},
+	13: {param: "added__date__lt"},
+	14: {param: "added__date__gt"},
+	15: {param: "modified__date__lt"},
+	16: {param: "modified__date__gt"},
+	17: {param: "tags__id__none", multi: true},
+	18: {param: "archive_serial_number__isnull"},
+	19: {param: "title_content"},
+	20: {param: "query"},
+	21: {param: "more_like_id"},
+	22: {param: "tags__id__in", multi: true},
+	23: {param: "archive_serial_number__gt"},
+	24: {param: "archive_serial_number__lt"},
+	25: {param: "storage_path__id", isNull: "storage_path__isnull"},
+	26: {param: "correspondent__id__in", multi: true},
+	27: {param: "correspondent__id__none", multi: true},
+	28: {param: "document_type__id__in", multi: true},
+	29: {param: "document_type__id__none", multi: true},
+	30: {param: "storage_path__id__in", multi: true},
+	31: {param: "storage_path__id__none", multi: true},
+	32: {param: "owner__id", isNull: "owner__isnull"},
+	33: {param: "owner__id__in", multi: true},
+	34: {param: "owner__isnull"},
+	35: {param: "owner__id__none", multi: true},
+	36: {param: "custom_fields__icontains"},
+	37: {param: "shared_by__id"},
+	38: {param: "custom_fields__id__all", multi: true},
+	39: {param: "custom_fields__id__in", multi: true},
+	40: {param: "custom_fields__id__none", multi: true},
+	41: {param: "has_custom_fields"},
+	42: {param: "custom_field_query"},
+	43: {param: "created__date__lte"},
+	44: {param: "created__date__gte"},
+	45: {param: "added__date__lte"},
+	46: {param: "added__date__gte"},
+	47: {param: "mime_type"},
+}
+
+// savedViewFilters translates the filter rules and sort order of a saved
+// view into documents list filters. A rule type this server does not know
+// is an error rather than dropped, which would widen the view.
+func savedViewFilters(view paperless.SavedView) (url.Values, error) {
+	filters := url.Values{}
+	multi := make(map[string][]string)
+	for _, rule := range view.FilterRules {
+		translation, ok := filterRuleParams[rule.RuleType]
+		if !ok {
+			return nil, fmt.Errorf("saved view %q uses filter rule type %d, which cannot be translated into a documents query", view.Name, rule.RuleType)
+		}
+		switch {
+		case rule.Value == nil || *rule.Value == "":
+			if translation.isNull != "" {
+				filters.Set(translation.isNull, "1")
+			}
+		case translation.multi:
+			multi[translation.param] = append(multi[translation.param], *rule.Value)
+		default:
+			filters.Set(translation.param, *rule.Value)
+		}
+	}
+	for param, values := range multi {
+		filters.Set(param, strings.Join(values, ","))
+	}
+
+	if view.SortField != nil && *view.SortField != "" {
+		ordering := *view.SortField
+		if view.SortReverse {
+			ordering = "-" + ordering
+		}
+		filters.Set("ordering", ordering)
+	}
+	return filters, nil
+}
+
+// describedFilterRule is a saved view filter rule with its type named and,
+// for rules referring to an object, the object's name
+type describedFilterRule struct {
+	RuleType  int     `json:"rule_type"`
+	Name      string  `json:"name"`
+	Value     *string `json:"value"`
+	ValueName string  `json:"value_name,omitempty"`
+}
+
+// savedViewDetail is a saved view with its filter rules described
+type savedViewDetail struct {
+	paperless.SavedView
+	FilterRules []describedFilterRule `json:"filter_rules"`
+}
+
+// objectNames maps the IDs of items to their names
+func objectNames[T any](items []T, err error, idName func(T) (int, string)) (map[int]string, error) {
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[int]string, len(items))
+	for _, item := range items {
+		id, name := idName(item)
+		names[id] = name
+	}
+	return names, nil
+}
+
+// describeSavedViews names the filter rules of views, reading the objects
+// the rules refer to from the metadata caches only when a rule needs them
+func (s *Server) describeSavedViews(ctx context.Context, views []paperless.SavedView) []savedViewDetail {
+	loaders := map[string]func() (map[int]string, error){
+		"tag": func() (map[int]string, error) {
+			tags, err := s.metadata.tags.get(ctx, s.paperlessClient)
+			return objectNames(tags, err, func(t paperless.Tag) (int, string) { return t.ID, t.Name })
+		},
+		"correspondent": func() (map[int]string, error) {
+			correspondents, err := s.metadata.correspondents.get(ctx, s.paperlessClient)
+			return objectNames(correspondents, err, func(c paperless.Correspondent) (int, string) { return c.ID, c.Name })
+		},
+		"document_type": func() (map[int]string, error) {
+			types, err := s.metadata.documentTypes.get(ctx, s.paperlessClient)
+			return objectNames(types, err, func(t paperless.DocumentType) (int, string) { return t.ID, t.Name })
+		},
+		"storage_path": func() (map[int]string, error) {
+			paths, err := s.metadata.storagePaths.get(ctx, s.paperlessClient)
+			return objectNames(paths, err, func(p paperless.StoragePath) (int, string) { return p.ID, p.Name })
+		},
+		"custom_field": func() (map[int]string, error) {
+			fields, err := s.metadata.customFields.get(ctx, s.paperlessClient)
+			return objectNames(fields, err, func(f paperless.CustomField) (int, string) { return f.ID, f.Name })
+		},
+	}
+	loaded := make(map[string]map[int]string)
+	nameOf := func(kind string, value *string) string {
+		if value == nil {
+			return ""
+		}
+		id, err := strconv.Atoi(*value)
+		if err != nil {
+			return ""
+		}
+		names, ok := loaded[kind]
+		if !ok {
+			names, err = loaders[kind]()
+			if err != nil {
+				logging.FromContext(ctx).Warn("Failed to look up filter rule names", "kind", kind, "error", err)
+			}
+			loaded[kind] = names
+		}
+		return names[id]
+	}
+
+	details := make([]savedViewDetail, len(views))
+	for i, view := range views {
+		rules := make([]describedFilterRule, len(view.FilterRules))
+		for j, rule := range view.FilterRules {
+			rules[j] = describedFilterRule{
+				RuleType: rule.RuleType,
+				Name:     filterRuleTypeNames[rule.RuleType],
+				Value:    rule.Value,
+			}
+			if kind, ok := filterRuleObjects[rule.RuleType]; ok {
+				rules[j].ValueName = nameOf(kind, rule.Value)
+			}
+		}
+		details[i] = savedViewDetail{SavedView: view, FilterRules: rules}
+	}
+	return details
+}
+
+// handleListSavedViews handles the list_saved_views tool
+func (s *Server) handleListSavedViews(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	// Extract pagination parameters, bounded as the client bounds them so
+	// the page fields of the result are accurate
+	page := DefaultPage
+	if p, ok := args["page"].(float64); ok && p >= 1 {
+		page = int(p)
+	}
+	pageSize := boundedIntArg(args, "page_size", DefaultPageSize, MaxPageSize)
+
+	logging.FromContext(ctx).Debug("List saved views tool invoked", "page", page, "page_size", pageSize)
+
+	response, err := s.paperlessClient.ListSavedViews(ctx, page, pageSize)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to list saved views", "error", err)
+		return nil, fmt.Errorf("failed to list saved views: %w", err)
+	}
+
+	var views []paperless.SavedView
+	if err := json.Unmarshal(response.Results, &views); err != nil {
+		logging.FromContext(ctx).Error("Failed to parse saved views from response", "error", err)
+		return nil, fmt.Errorf("failed to parse saved views: %w", err)
+	}
+
+	logging.FromContext(ctx).Info("Saved views listed successfully", "count", len(views), "page", page)
+
+	return newListResult(s.describeSavedViews(ctx, views), response.Count, page, pageSize, nil), nil
+}
+
+// handleGetSavedView handles the get_saved_view tool
+func (s *Server) handleGetSavedView(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	viewIDFloat, ok := args["view_id"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("view_id is required and must be an integer")
+	}
+	viewID := int(viewIDFloat)
+	if viewID < 1 {
+		return nil, fmt.Errorf("view_id must be a positive integer")
+	}
+
+	logging.FromContext(ctx).Debug("Get saved view tool invoked", "view_id", viewID)
+
+	view, err := s.paperlessClient.GetSavedView(ctx, viewID)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to get saved view", "view_id", viewID, "error", err)
+		return nil, fmt.Errorf("failed to get saved view: %w", err)
+	}
+
+	return s.describeSavedViews(ctx, []paperless.SavedView{*view})[0], nil
+}
+
+// handleExecuteSavedView handles the execute_saved_view tool, returning
+// the documents a saved view shows in the Paperless UI
+func (s *Server) handleExecuteSavedView(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	viewIDFloat, ok := args["view_id"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("view_id is required and must be an integer")
+	}
+	viewID := int(viewIDFloat)
+	if viewID < 1 {
+		return nil, fmt.Errorf("view_id must be a positive integer")
+	}
+
+	page := DefaultPage
+	if p, ok := args["page"].(float64); ok && p >= 1 {
+		page = int(p)
+	}
+	previewChars, err := previewCharsArg(args)
+	if err != nil {
+		return nil, err
+	}
+
+	view, err := s.paperlessClient.GetSavedView(ctx, viewID)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to get saved view", "view_id", viewID, "error", err)
+		return nil, fmt.Errorf("failed to get saved view: %w", err)
+	}
+
+	// The view's own page size applies unless the call asks for another
+	defaultPageSize := DefaultPageSize
+	if view.PageSize != nil && *view.PageSize >= 1 {
+		defaultPageSize = min(*view.PageSize, MaxPageSize)
+	}
+	pageSize := boundedIntArg(args, "page_size", defaultPageSize, MaxPageSize)
+
+	filters, err := savedViewFilters(*view)
+	if err != nil {
+		return nil, err
+	}
+	sessionTags := s.applyDefaultTags(ctx, filters)
+
+	logging.FromContext(ctx).Debug("Executing saved view",
+		"view_id", viewID,
+		"filters", filters.Encode(),
+		"page", page,
+		"page_size", pageSize)
+
+	response, err := s.paperlessClient.ListDocuments(ctx, filters, page, pageSize)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to execute saved view",
+			"view_id", viewID,
+			"error", err)
+		return nil, fmt.Errorf("failed to execute saved view: %w", err)
+	}
+
+	var documents []paperless.Document
+	if err := json.Unmarshal(response.Results, &documents); err != nil {
+		logging.FromContext(ctx).Error("Failed to parse documents results", "error", err)
+		return nil, fmt.Errorf("failed to parse results: %w", err)
+	}
+
+	logging.FromContext(ctx).Info("Saved view executed",
+		"view_id", viewID,
+		"found", response.Count,
+		"returned", len(documents))
+
+	query := make(map[string]string, len(filters))
+	for key := range filters {
+		query[key] = filters.Get(key)
+	}
+	applied := map[string]interface{}{
+		"saved_view": map[string]interface{}{"id": view.ID, "name": view.Name},
+		"filter":     query,
+	}
+	if sessionTags != nil {
+		applied["session_tags"] = sessionTags
+	}
+	var items interface{} = documents
+	if previewChars > 0 {
+		items = previewDocuments(documents, searchHighlights(response.Results), previewChars)
+	}
+	return newListResult(items, response.Count, page, pageSize, applied), nil
+}
@@ -0,0 +1,263 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// TestSavedViews tests that saved views are listed and retrieved with
+// their filter rules named and referenced objects resolved
+func TestSavedViews(t *testing.T) {
+	view := map[string]interface{}{
+		"id":           3,
+		"name":         "Unpaid invoices",
+		"sort_field":   "created",
+		"sort_reverse": true,
+		"filter_rules": []interface{}{
+			map[string]interface{}{"rule_type": 6, "value": "12"},
+			map[string]interface{}{"rule_type": 4, "value": "2"},
+			map[string]interface{}{"rule_type": 0, "value": "invoice"},
+		},
+		"display_fields": []string{"title", "created"},
+	}
+	var requests []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		page := func(results ...interface{}) map[string]interface{} {
+			return map[string]interface{}{"count": len(results), "next": nil, "results": results}
+		}
+		var body interface{}
+		switch r.URL.Path {
+		case "/api/saved_views/":
+			body = page(view)
+		case "/api/saved_views/3/":
+			body = view
+		case "/api/tags/":
+			body = page(map[string]interface{}{"id": 12, "name": "Unpaid"})
+		case "/api/document_types/":
+			body = page(map[string]interface{}{"id": 2, "name": "Invoice"})
+		default:
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+		json.NewEncoder(w).Encode(body)
+	}))
+	defer ts.Close()
+
+	s := &Server{paperlessClient: paperless.New(ts.URL, "test-token")}
+
+	want := []describedFilterRule{
+		{RuleType: 6, Name: "has_tag", ValueName: "Unpaid"},
+		{RuleType: 4, Name: "document_type_is", ValueName: "Invoice"},
+		{RuleType: 0, Name: "title_contains"},
+	}
+	check := func(detail savedViewDetail) {
+		t.Helper()
+		if detail.ID != 3 || detail.Name != "Unpaid invoices" || detail.SortField == nil || *detail.SortField != "created" || len(detail.FilterRules) != len(want) {
+			t.Fatalf("Unexpected saved view %+v", detail)
+		}
+		for i, rule := range detail.FilterRules {
+			if rule.RuleType != want[i].RuleType || rule.Name != want[i].Name || rule.ValueName != want[i].ValueName || rule.Value == nil {
+				t.Errorf("Rule %d: expected %+v, got %+v", i, want[i], rule)
+			}
+		}
+	}
+
+	result, err := s.handleListSavedViews(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("list_saved_views failed: %v", err)
+	}
+	views := result.(*ListResult).Items.([]savedViewDetail)
+	if len(views) != 1 {
+		t.Fatalf("Expected 1 saved view, got %d", len(views))
+	}
+	check(views[0])
+
+	result, err = s.handleGetSavedView(context.Background(), map[string]interface{}{"view_id": float64(3)})
+	if err != nil {
+		t.Fatalf("get_saved_view failed: %v", err)
+	}
+	check(result.(savedViewDetail))
+
+	// Only the kinds of objects the rules refer to are read
+	for _, path := range requests {
+		if path == "/api/correspondents/" || path == "/api/storage_paths/" {
+			t.Errorf("Unexpected lookup of %s", path)
+		}
+	}
+
+	if _, err := s.handleGetSavedView(context.Background(), map[string]interface{}{}); err == nil {
+		t.Error("Expected a missing view_id to fail")
+	}
+}
+
+// TestExecuteSavedView tests that a saved view's filter rules and sort
+// order are translated into a documents query, with multi-value rules
+// combined and the view's page size used
+func TestExecuteSavedView(t *testing.T) {
+	value := func(v string) *string { return &v }
+	sortField := "created"
+	pageSize := 10
+	view := paperless.SavedView{
+		ID:          3,
+		Name:        "Taxes 2024",
+		SortField:   &sortField,
+		SortReverse: true,
+		PageSize:    &pageSize,
+		FilterRules: []paperless.SavedViewFilterRule{
+			{RuleType: 22, Value: value("4")},
+			{RuleType: 22, Value: value("9")},
+			{RuleType: 10, Value: value("2024")},
+			{RuleType: 3, Value: nil},
+		},
+	}
+	var query url.Values
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/saved_views/3/":
+			json.NewEncoder(w).Encode(view)
+		case "/api/documents/":
+			query = r.URL.Query()
+			w.Write([]byte(`{"count":1,"next":null,"results":[{"id":5,"title":"Tax return"}]}`))
+		default:
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+	}))
+	defer ts.Close()
+
+	s := &Server{
+		paperlessClient: paperless.New(ts.URL, "test-token"),
+		sessions:        newSessionStore(newMemorySessionStore(), time.Hour),
+	}
+	result, err := s.handleExecuteSavedView(context.Background(), map[string]interface{}{"view_id": float64(3)})
+	if err != nil {
+		t.Fatalf("execute_saved_view failed: %v", err)
+	}
+
+	want := map[string]string{
+		"tags__id__in":          "4,9",
+		"created__year":         "2024",
+		"correspondent__isnull": "1",
+		"ordering":              "-created",
+		"page_size":             "10",
+	}
+	for param, v := range want {
+		if got := query.Get(param); got != v {
+			t.Errorf("Expected %s=%s, got %q", param, v, got)
+		}
+	}
+	list := result.(*ListResult)
+	if documents := list.Items.([]paperless.Document); len(documents) != 1 || documents[0].ID != 5 || list.PageSize != 10 {
+		t.Errorf("Unexpected result %+v", list)
+	}
+
+	// A rule type that cannot be translated refuses the view
+	view.FilterRules = append(view.FilterRules, paperless.SavedViewFilterRule{RuleType: 99, Value: value("x")})
+	query = nil
+	if _, err := s.handleExecuteSavedView(context.Background(), map[string]interface{}{"view_id": float64(3)}); err == nil || query != nil {
+		t.Errorf("Expected an unknown rule type to be refused without a query, got %v", err)
+	}
+}
+
+// TestSavedViewFilterRules tests the documents filter of every rule type,
+// as the Paperless UI translates it
+func TestSavedViewFilterRules(t *testing.T) {
+	want := map[int]string{
+		0:  "title__icontains",
+		1:  "content__icontains",
+		2:  "archive_serial_number",
+		3:  "correspondent__id",
+		4:  "document_type__id",
+		5:  "is_in_inbox",
+		6:  "tags__id__all",
+		7:  "is_tagged",
+		8:  "created__date__lt",
+		9:  "created__date__gt",
+		10: "created__year",
+		11: "created__month",
+		12: "created__day",
+		13: "added__date__lt",
+		14: "added__date__gt",
+		15: "modified__date__lt",
+		16: "modified__date__gt",
+		17: "tags__id__none",
+		18: "archive_serial_number__isnull",
+		19: "title_content",
+		20: "query",
+		21: "more_like_id",
+		22: "tags__id__in",
+		23: "archive_serial_number__gt",
+		24: "archive_serial_number__lt",
+		25: "storage_path__id",
+		26: "correspondent__id__in",
+		27: "correspondent__id__none",
+		28: "document_type__id__in",
+		29: "document_type__id__none",
+		30: "storage_path__id__in",
+		31: "storage_path__id__none",
+		32: "owner__id",
+		33: "owner__id__in",
+		34: "owner__isnull",
+		35: "owner__id__none",
+		36: "custom_fields__icontains",
+		37: "shared_by__id",
+		38: "custom_fields__id__all",
+		39: "custom_fields__id__in",
+		40: "custom_fields__id__none",
+		41: "has_custom_fields",
+		42: "custom_field_query",
+		43: "created__date__lte",
+		44: "created__date__gte",
+		45: "added__date__lte",
+		46: "added__date__gte",
+		47: "mime_type",
+	}
+	if len(want) != len(paperless.FilterRuleTypes) {
+		t.Fatalf("Expected a translation for each of the %d rule types", len(paperless.FilterRuleTypes))
+	}
+
+	value := func(v string) *string { return &v }
+	for ruleType, param := range want {
+		view := paperless.SavedView{Name: "View", FilterRules: []paperless.SavedViewFilterRule{
+			{RuleType: ruleType, Value: value("7")},
+		}}
+		filters, err := savedViewFilters(view)
+		if err != nil {
+			t.Errorf("Rule type %d: unexpected error %v", ruleType, err)
+			continue
+		}
+		if len(filters) != 1 || filters.Get(param) != "7" {
+			t.Errorf("Rule type %d: expected %s=7, got %v", ruleType, param, filters)
+		}
+	}
+
+	// Rules of the custom field list types combine; has_custom_fields is
+	// a single boolean
+	view := paperless.SavedView{Name: "View", FilterRules: []paperless.SavedViewFilterRule{
+		{RuleType: 38, Value: value("1")},
+		{RuleType: 38, Value: value("2")},
+		{RuleType: 40, Value: value("3")},
+		{RuleType: 41, Value: value("true")},
+	}}
+	filters, err := savedViewFilters(view)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if filters.Get("custom_fields__id__all") != "1,2" || filters.Get("custom_fields__id__none") != "3" || filters.Get("has_custom_fields") != "true" {
+		t.Errorf("Unexpected custom field filters %v", filters)
+	}
+	for _, ruleType := range []int{38, 39, 40} {
+		if filterRuleObjects[ruleType] != "custom_field" {
+			t.Errorf("Expected rule type %d to name a custom field", ruleType)
+		}
+	}
+	if _, ok := filterRuleObjects[41]; ok {
+		t.Error("Expected rule type 41 not to name an object")
+	}
+}
@@ -0,0 +1,218 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/config"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+)
+
+// toolPermissions lists the Paperless permissions, as reported in the
+// user's UI settings, that each tool needs. Tools not listed need none.
+var toolPermissions = map[string][]string{
+	"search_documents":           {"view_document"},
+	"find_similar_documents":     {"view_document"},
+	"get_document":               {"view_document"},
+	"get_document_metadata":      {"view_document"},
+	"download_document_original": {"view_document"},
+	"download_document_archive":  {"view_document"},
+	"get_documents":              {"view_document"},
+	"browse_archive":             {"view_document"},
+	"get_linked_documents":       {"view_document"},
+	"assess_document_ocr":        {"view_document"},
+	"get_document_thumbnail":     {"view_document"},
+	"get_thumbnails":             {"view_document"},
+	"get_document_content":       {"view_document"},
+	"get_document_summary":       {"view_document"},
+	"store_document_summary":     {"view_document"},
+	"check_duplicate_document":   {"view_document"},
+	"compare_documents":          {"view_document"},
+	"verify_documents":           {"view_document"},
+	"test_matching_rule":         {"view_document"},
+	"preview_filter_matches":     {"view_document"},
+	"run_saved_query":            {"view_document"},
+	"create_document":            {"add_document"},
+	"upload_document":            {"add_document"},
+	"update_document":            {"change_document"},
+	"set_document_dates":         {"change_document"},
+	"triage_document":            {"change_document", "view_tag"},
+	"mark_documents_processed":   {"change_document", "view_tag"},
+	"bulk_edit_documents":        {"change_document"},
+	"resume_operation":           {"change_document"},
+	"delete_document":            {"delete_document"},
+
+	"list_correspondents":       {"view_correspondent"},
+	"get_correspondent":         {"view_correspondent"},
+	"get_correspondent_summary": {"view_correspondent", "view_document"},
+	"create_correspondent":      {"add_correspondent"},
+	"update_correspondent":      {"change_correspondent"},
+	"delete_correspondent":      {"delete_correspondent"},
+
+	"list_document_types":  {"view_documenttype"},
+	"get_document_type":    {"view_documenttype"},
+	"create_document_type": {"add_documenttype"},
+	"update_document_type": {"change_documenttype"},
+	"delete_document_type": {"delete_documenttype"},
+
+	"list_storage_paths":  {"view_storagepath"},
+	"create_storage_path": {"add_storagepath"},
+	"update_storage_path": {"change_storagepath"},
+
+	"list_tags":        {"view_tag"},
+	"get_tag":          {"view_tag"},
+	"list_tag_tree":    {"view_tag"},
+	"resolve_tag_path": {"view_tag"},
+	"create_tag":       {"add_tag"},
+	"update_tag":       {"change_tag"},
+	"delete_tag":       {"delete_tag"},
+
+	"list_custom_fields":  {"view_customfield"},
+	"get_custom_field":    {"view_customfield"},
+	"create_custom_field": {"add_customfield"},
+	"update_custom_field": {"change_customfield"},
+	"delete_custom_field": {"delete_customfield"},
+
+	"list_mail_rules":         {"view_mailrule"},
+	"get_mail_rule_documents": {"view_mailrule", "view_document"},
+
+	"list_saved_views":   {"view_savedview"},
+	"get_saved_view":     {"view_savedview"},
+	"execute_saved_view": {"view_savedview", "view_document"},
+
+	"export_metadata":       {"view_tag", "view_correspondent", "view_documenttype", "view_storagepath", "view_customfield", "view_savedview"},
+	"get_taxonomy_overview": {"view_document", "view_tag", "view_correspondent", "view_documenttype"},
+	"get_tag_cooccurrence":  {"view_document", "view_tag"},
+	"import_metadata":       {"view_tag", "view_correspondent", "view_documenttype", "view_storagepath", "view_customfield"},
+}
+
+// tokenScope is what the configured Paperless token may do. The zero value
+// is unknown, in which case every tool is offered.
+type tokenScope struct {
+	mu          sync.RWMutex
+	known       bool
+	username    string
+	superuser   bool
+	permissions map[string]bool
+}
+
+// missing returns the permissions a tool needs that the token lacks
+func (t *tokenScope) missing(toolName string) []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if !t.known || t.superuser {
+		return nil
+	}
+	var missing []string
+	for _, permission := range toolPermissions[toolName] {
+		if !t.permissions[permission] {
+			missing = append(missing, permission)
+		}
+	}
+	return missing
+}
+
+// detectTokenScope reads the permissions of the configured Paperless
+// token once, then hides or annotates the tools it cannot use. With a
+// token map each identity has its own permissions, so nothing is hidden.
+func (s *Server) detectTokenScope(ctx context.Context) {
+	mode := s.cfg.MCPToolScope
+	if mode != config.ToolScopeHide && mode != config.ToolScopeAnnotate || len(s.cfg.MCPAuthTokenMap) > 0 {
+		return
+	}
+	s.scope.mu.RLock()
+	known := s.scope.known
+	s.scope.mu.RUnlock()
+	if known {
+		return
+	}
+
+	settings, err := s.paperlessClient.GetUISettings(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Warn("Failed to read Paperless token permissions, offering all tools", "error", err)
+		return
+	}
+
+	permissions := make(map[string]bool, len(settings.Permissions))
+	for _, permission := range settings.Permissions {
+		permissions[permission] = true
+	}
+	s.scope.mu.Lock()
+	s.scope.known = true
+	s.scope.username = settings.User.Username
+	s.scope.superuser = settings.User.IsSuperuser
+	s.scope.permissions = permissions
+	s.scope.mu.Unlock()
+
+	unavailable := s.unavailableTools()
+	logging.FromContext(ctx).Info("Detected Paperless token permissions",
+		"user", settings.User.Username,
+		"superuser", settings.User.IsSuperuser,
+		"unavailable_tools", len(unavailable))
+	if len(unavailable) == 0 {
+		return
+	}
+
+	switch mode {
+	case config.ToolScopeHide:
+		s.mcpServer.DeleteTools(unavailable...)
+		logging.FromContext(ctx).Info("Hid tools the Paperless token cannot use", "tools", unavailable)
+	case config.ToolScopeAnnotate:
+		// Only the advertised copy is annotated; s.tools is left alone as
+		// tools may be executing concurrently
+		for _, name := range unavailable {
+			tool := s.tools[name]
+			tool.Description = fmt.Sprintf("[Unavailable: the Paperless token lacks %s] %s",
+				strings.Join(s.scope.missing(name), ", "), tool.Description)
+			s.addMCPTool(tool)
+		}
+		logging.FromContext(ctx).Info("Annotated tools the Paperless token cannot use", "tools", unavailable)
+	}
+}
+
+// unavailableTools returns the sorted names of the registered tools the
+// token lacks permissions for
+func (s *Server) unavailableTools() []string {
+	var names []string
+	for name := range s.tools {
+		if len(s.scope.missing(name)) > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// checkScope rejects tools the token lacks permissions for, so agents get
+// a clear answer instead of a 403 from Paperless
+func (s *Server) checkScope(tool Tool) error {
+	missing := s.scope.missing(tool.Name)
+	if len(missing) == 0 {
+		return nil
+	}
+	s.scope.mu.RLock()
+	username := s.scope.username
+	s.scope.mu.RUnlock()
+	return fmt.Errorf("the Paperless token of user %q lacks the %s permission needed by %s",
+		username, strings.Join(missing, ", "), tool.Name)
+}
+
+// scopeInfo describes the detected token scope for server_info, or nil
+// before it is known
+func (s *Server) scopeInfo() map[string]interface{} {
+	s.scope.mu.RLock()
+	known, username, superuser := s.scope.known, s.scope.username, s.scope.superuser
+	s.scope.mu.RUnlock()
+	if !known {
+		return nil
+	}
+	return map[string]interface{}{
+		"user":              username,
+		"superuser":         superuser,
+		"mode":              s.cfg.MCPToolScope,
+		"unavailable_tools": s.unavailableTools(),
+	}
+}
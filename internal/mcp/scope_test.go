@@ -0,0 +1,91 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/config"
+)
+
+// TestTokenScope tests that tools needing permissions the token lacks are
+// rejected before reaching Paperless, and that permitted tools still run
+func TestTokenScope(t *testing.T) {
+	var deletes int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/ui_settings/":
+			w.Write([]byte(`{"user":{"id":2,"username":"reader","is_superuser":false},"permissions":["view_document","view_tag"]}`))
+		case r.Method == http.MethodDelete:
+			deletes++
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer ts.Close()
+
+	s, err := New(&config.Config{
+		PaperlessURL:   ts.URL,
+		PaperlessToken: "test-token",
+		MCPTransport:   "stdio",
+		MCPToolScope:   config.ToolScopeHide,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	ctx := context.Background()
+
+	s.probeUpstream(ctx)
+	_, err = s.ExecuteTool(ctx, "delete_document", map[string]interface{}{"id": float64(1)})
+	if err == nil || !strings.Contains(err.Error(), "delete_document permission") || !strings.Contains(err.Error(), `"reader"`) {
+		t.Errorf("Expected an error naming the missing permission and user, got %v", err)
+	}
+	if deletes != 0 {
+		t.Errorf("Expected no request to reach Paperless, got %d", deletes)
+	}
+
+	if err := s.checkScope(s.tools["list_tags"]); err != nil {
+		t.Errorf("Expected list_tags to be permitted, got %v", err)
+	}
+	if err := s.checkScope(s.tools["ping"]); err != nil {
+		t.Errorf("Expected ping to need no permissions, got %v", err)
+	}
+
+	unavailable := s.unavailableTools()
+	for _, name := range []string{"create_tag", "delete_document", "list_correspondents"} {
+		if !containsString(unavailable, name) {
+			t.Errorf("Expected %s to be unavailable, got %v", name, unavailable)
+		}
+	}
+	if containsString(unavailable, "search_documents") {
+		t.Errorf("Expected search_documents to be available, got %v", unavailable)
+	}
+}
+
+// TestTokenScopeSuperuser tests that superusers may use every tool
+func TestTokenScopeSuperuser(t *testing.T) {
+	s := &Server{cfg: &config.Config{}}
+	s.scope.known = true
+	s.scope.superuser = true
+	if missing := s.scope.missing("delete_document"); len(missing) != 0 {
+		t.Errorf("Expected a superuser to lack nothing, got %v", missing)
+	}
+
+	// Before detection every tool is offered
+	var unknown tokenScope
+	if missing := unknown.missing("delete_document"); len(missing) != 0 {
+		t.Errorf("Expected an unknown scope to lack nothing, got %v", missing)
+	}
+}
+
+func containsString(values []string, want string) bool {
+	for _, value := range values {
+		if value == want {
+			return true
+		}
+	}
+	return false
+}
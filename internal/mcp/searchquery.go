@@ -0,0 +1,443 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+)
+
+// searchFieldKind is how Paperless' full-text index stores a field, which
+// decides the values a query may compare it with
+type searchFieldKind string
+
+const (
+	searchFieldText    searchFieldKind = "text"
+	searchFieldDate    searchFieldKind = "date"
+	searchFieldNumber  searchFieldKind = "number"
+	searchFieldBoolean searchFieldKind = "boolean"
+)
+
+// searchFields are the fields of Paperless' full-text index that a query
+// may name as field:value
+var searchFields = map[string]searchFieldKind{
+	"title":              searchFieldText,
+	"content":            searchFieldText,
+	"correspondent":      searchFieldText,
+	"tag":                searchFieldText,
+	"type":               searchFieldText,
+	"path":               searchFieldText,
+	"notes":              searchFieldText,
+	"owner":              searchFieldText,
+	"custom_fields":      searchFieldText,
+	"original_filename":  searchFieldText,
+	"checksum":           searchFieldText,
+	"created":            searchFieldDate,
+	"added":              searchFieldDate,
+	"modified":           searchFieldDate,
+	"id":                 searchFieldNumber,
+	"asn":                searchFieldNumber,
+	"correspondent_id":   searchFieldNumber,
+	"tag_id":             searchFieldNumber,
+	"type_id":            searchFieldNumber,
+	"path_id":            searchFieldNumber,
+	"owner_id":           searchFieldNumber,
+	"custom_fields_id":   searchFieldNumber,
+	"num_notes":          searchFieldNumber,
+	"custom_field_count": searchFieldNumber,
+	"page_count":         searchFieldNumber,
+	"has_tag":            searchFieldBoolean,
+	"has_correspondent":  searchFieldBoolean,
+	"has_type":           searchFieldBoolean,
+	"has_path":           searchFieldBoolean,
+	"has_owner":          searchFieldBoolean,
+	"has_custom_fields":  searchFieldBoolean,
+	"is_shared":          searchFieldBoolean,
+}
+
+var (
+	// searchFieldPattern matches a field:value term
+	searchFieldPattern = regexp.MustCompile(`^([A-Za-z_]+):(.*)$`)
+	// searchRangePattern matches the inside of a [start to end] range,
+	// either end of which may be left open
+	searchRangePattern = regexp.MustCompile(`(?i)^\s*(.*?)\s*\bto\b\s*(.*?)\s*$`)
+	// searchAbsoluteDatePattern matches YYYY, YYYY-MM, YYYY-MM-DD and YYYYMMDD
+	searchAbsoluteDatePattern = regexp.MustCompile(`^\d{4}(-\d{2}(-\d{2})?|\d{4})?$`)
+	// searchRelativeDatePattern matches dates such as "-1 week" and "last month"
+	searchRelativeDatePattern = regexp.MustCompile(`(?i)^(-?\d+\s*(second|minute|hour|day|week|month|year)s?|(last|this|next)\s+(week|month|year)|today|yesterday|tomorrow|now)$`)
+)
+
+// tokenizeSearchQuery splits a query into terms, boolean operators and
+// parentheses, keeping quoted phrases and ranges whole
+func tokenizeSearchQuery(query string) ([]string, error) {
+	var tokens []string
+	var term strings.Builder
+	flush := func() {
+		if term.Len() > 0 {
+			tokens = append(tokens, term.String())
+			term.Reset()
+		}
+	}
+
+	inQuote := false
+	var closeRange byte
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case inQuote:
+			term.WriteByte(c)
+			inQuote = c != '"'
+		case closeRange != 0:
+			term.WriteByte(c)
+			if c == closeRange {
+				closeRange = 0
+			}
+		case c == '"':
+			inQuote = true
+			term.WriteByte(c)
+		case c == '[' || c == '{':
+			closeRange = map[byte]byte{'[': ']', '{': '}'}[c]
+			term.WriteByte(c)
+		case c == '(' || c == ')':
+			flush()
+			tokens = append(tokens, string(c))
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			flush()
+		default:
+			term.WriteByte(c)
+		}
+	}
+	if inQuote {
+		return nil, fmt.Errorf("unterminated quoted phrase, close it with \"")
+	}
+	if closeRange != 0 {
+		return nil, fmt.Errorf("unterminated range, close it with %c", closeRange)
+	}
+	flush()
+	return tokens, nil
+}
+
+// validateSearchQuery checks a query in Paperless' advanced search syntax,
+// returning the problems that make it fail or match nothing, and warnings
+// about parts Paperless may read differently than intended
+func validateSearchQuery(query string) ([]string, []string) {
+	problems, warnings := []string{}, []string{}
+	if strings.TrimSpace(query) == "" {
+		return append(problems, "query is empty"), warnings
+	}
+	tokens, err := tokenizeSearchQuery(query)
+	if err != nil {
+		return append(problems, err.Error()), warnings
+	}
+
+	// prev is what the previous token was: start, open, close, term,
+	// operator or not
+	prev := "start"
+	depth := 0
+	operators := map[int]map[string]bool{}
+	for _, token := range tokens {
+		switch token {
+		case "(":
+			depth++
+			operators[depth] = map[string]bool{}
+			prev = "open"
+		case ")":
+			switch {
+			case depth == 0:
+				problems = append(problems, "unbalanced ')' without a matching '('")
+				continue
+			case prev == "open":
+				problems = append(problems, "empty parentheses")
+			case prev == "operator" || prev == "not":
+				problems = append(problems, "an operator is followed by ')' instead of a term")
+			}
+			depth--
+			prev = "close"
+		case "AND", "OR":
+			if prev == "start" || prev == "open" || prev == "operator" || prev == "not" {
+				problems = append(problems, fmt.Sprintf("%s must be between two terms", token))
+			}
+			if operators[depth] == nil {
+				operators[depth] = map[string]bool{}
+			}
+			operators[depth][token] = true
+			prev = "operator"
+		case "NOT":
+			prev = "not"
+		default:
+			switch strings.ToLower(token) {
+			case "and", "or", "not":
+				warnings = append(warnings, fmt.Sprintf("%q is searched for as a word; write boolean operators in upper case", token))
+			}
+			problem, warning := checkSearchTerm(token)
+			if problem != "" {
+				problems = append(problems, problem)
+			}
+			if warning != "" {
+				warnings = append(warnings, warning)
+			}
+			prev = "term"
+		}
+	}
+	if depth > 0 {
+		problems = append(problems, "unbalanced '(' without a matching ')'")
+	}
+	if prev == "operator" || prev == "not" {
+		problems = append(problems, "query ends with an operator")
+	}
+	for _, ops := range operators {
+		if ops["AND"] && ops["OR"] {
+			warnings = append(warnings, "AND binds more tightly than OR; add parentheses to make the grouping explicit")
+			break
+		}
+	}
+	return problems, warnings
+}
+
+// checkSearchTerm checks one term of a query, returning a problem or a
+// warning about it
+func checkSearchTerm(term string) (string, string) {
+	if term == `""` {
+		return "empty quoted phrase", ""
+	}
+	m := searchFieldPattern.FindStringSubmatch(term)
+	if m == nil {
+		if strings.HasPrefix(term, "[") || strings.HasPrefix(term, "{") {
+			return fmt.Sprintf("range %s needs a field, e.g. created:%s", term, term), ""
+		}
+		return "", ""
+	}
+
+	field, value := strings.ToLower(m[1]), m[2]
+	kind, ok := searchFields[field]
+	if !ok {
+		message := fmt.Sprintf("unknown search field %q", m[1])
+		if suggestion := closestMatch(field, sortedSearchFields()); suggestion != "" {
+			message += fmt.Sprintf(", did you mean %q?", suggestion)
+		}
+		return message + "; quote the term to search for it as text", ""
+	}
+	if value == "" || value == `""` {
+		return fmt.Sprintf("%s: has no value", field), ""
+	}
+
+	if strings.HasPrefix(value, "[") || strings.HasPrefix(value, "{") {
+		if kind != searchFieldDate && kind != searchFieldNumber {
+			return fmt.Sprintf("%s is a %s field; ranges only apply to date and number fields", field, kind), ""
+		}
+		r := searchRangePattern.FindStringSubmatch(value[1 : len(value)-1])
+		if r == nil || (r[1] == "" && r[2] == "") {
+			return fmt.Sprintf("range %s must have the form [start to end], with at most one end left open", value), ""
+		}
+		var warning string
+		for _, end := range r[1:] {
+			if end == "" {
+				continue
+			}
+			problem, w := checkSearchValue(field, kind, end)
+			if problem != "" {
+				return problem, ""
+			}
+			if w != "" {
+				warning = w
+			}
+		}
+		return "", warning
+	}
+
+	return checkSearchValue(field, kind, strings.Trim(value, `"`))
+}
+
+// checkSearchValue checks a value compared with a field of the given kind
+func checkSearchValue(field string, kind searchFieldKind, value string) (string, string) {
+	switch kind {
+	case searchFieldDate:
+		if !searchAbsoluteDatePattern.MatchString(value) && !searchRelativeDatePattern.MatchString(value) {
+			return "", fmt.Sprintf("Paperless may not understand the date %q of %s; use YYYY, YYYY-MM, YYYY-MM-DD or relative dates such as -1 week, yesterday or last month", value, field)
+		}
+	case searchFieldNumber:
+		if _, err := strconv.Atoi(value); err != nil && !strings.Contains(value, "*") {
+			return fmt.Sprintf("%s expects a whole number, got %q", field, value), ""
+		}
+	case searchFieldBoolean:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Sprintf("%s expects true or false, got %q", field, value), ""
+		}
+	}
+	return "", ""
+}
+
+// sortedSearchFields returns the search fields in order
+func sortedSearchFields() []string {
+	names := make([]string, 0, len(searchFields))
+	for name := range searchFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// searchTermValue converts a clause value to text
+func searchTermValue(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v), strings.TrimSpace(v) != ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	}
+	return "", false
+}
+
+// searchTerm renders field:value, quoting values that contain spaces or
+// query syntax so they are searched for as written
+func searchTerm(field, value string) (string, error) {
+	if strings.Contains(value, `"`) {
+		return "", fmt.Errorf("values cannot contain double quotes")
+	}
+	switch value {
+	case "AND", "OR", "NOT":
+		value = `"` + value + `"`
+	default:
+		if strings.ContainsAny(value, " \t():[]{}") {
+			value = `"` + value + `"`
+		}
+	}
+	if field == "" {
+		return value, nil
+	}
+	return field + ":" + value, nil
+}
+
+// buildSearchClause renders one clause of build_search_query
+func buildSearchClause(clause map[string]interface{}) (string, error) {
+	field, _ := clause["field"].(string)
+	field = strings.ToLower(strings.TrimSpace(field))
+	if _, ok := searchFields[field]; field != "" && !ok {
+		message := fmt.Sprintf("unknown field %q", field)
+		if suggestion := closestMatch(field, sortedSearchFields()); suggestion != "" {
+			message += fmt.Sprintf(", did you mean %q?", suggestion)
+		}
+		return "", fmt.Errorf("%s; supported fields: %s", message, strings.Join(sortedSearchFields(), ", "))
+	}
+
+	value, hasValue := searchTermValue(clause["value"])
+	from, hasFrom := searchTermValue(clause["from"])
+	to, hasTo := searchTermValue(clause["to"])
+	anyOf, _ := clause["any_of"].([]interface{})
+
+	forms := 0
+	for _, has := range []bool{hasValue, hasFrom || hasTo, len(anyOf) > 0} {
+		if has {
+			forms++
+		}
+	}
+	if forms != 1 {
+		return "", fmt.Errorf("give exactly one of value, any_of, or from and/or to")
+	}
+
+	var expr string
+	switch {
+	case hasFrom || hasTo:
+		if field == "" {
+			return "", fmt.Errorf("from and to need a field")
+		}
+		if strings.ContainsAny(from+to, "[]{}\"") {
+			return "", fmt.Errorf("range ends cannot contain brackets or quotes")
+		}
+		expr = fmt.Sprintf("%s:[%s]", field, strings.TrimSpace(from+" to "+to))
+	case len(anyOf) > 0:
+		terms := make([]string, 0, len(anyOf))
+		for _, item := range anyOf {
+			v, ok := searchTermValue(item)
+			if !ok {
+				return "", fmt.Errorf("any_of entries must be non-empty strings or numbers")
+			}
+			term, err := searchTerm(field, v)
+			if err != nil {
+				return "", err
+			}
+			terms = append(terms, term)
+		}
+		expr = strings.Join(terms, " OR ")
+		if len(terms) > 1 {
+			expr = "(" + expr + ")"
+		}
+	default:
+		term, err := searchTerm(field, value)
+		if err != nil {
+			return "", err
+		}
+		expr = term
+	}
+
+	if exclude, _ := clause["exclude"].(bool); exclude {
+		expr = "NOT " + expr
+	}
+	return expr, nil
+}
+
+// handleBuildSearchQuery handles the build_search_query tool
+func (s *Server) handleBuildSearchQuery(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	query, hasQuery := args["query"].(string)
+	clauses, hasClauses := args["clauses"].([]interface{})
+	switch {
+	case hasQuery && hasClauses:
+		return nil, fmt.Errorf("provide either clauses to build a query or query to validate one, not both")
+	case hasQuery:
+		logging.FromContext(ctx).Debug("Validating search query", "query", query)
+		problems, warnings := validateSearchQuery(query)
+		return map[string]interface{}{
+			"query":    query,
+			"valid":    len(problems) == 0,
+			"problems": problems,
+			"warnings": warnings,
+		}, nil
+	case !hasClauses || len(clauses) == 0:
+		return nil, fmt.Errorf("clauses is required and must be a non-empty array, or pass query to validate an existing query")
+	}
+
+	join := " AND "
+	match, _ := args["match"].(string)
+	switch strings.ToLower(match) {
+	case "", "all":
+	case "any":
+		join = " OR "
+	default:
+		return nil, fmt.Errorf("match must be all or any, got %q", match)
+	}
+
+	parts := make([]string, 0, len(clauses))
+	for i, c := range clauses {
+		clause, ok := c.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("clauses[%d] must be an object", i)
+		}
+		part, err := buildSearchClause(clause)
+		if err != nil {
+			return nil, fmt.Errorf("clauses[%d]: %w", i, err)
+		}
+		parts = append(parts, part)
+	}
+	query = strings.Join(parts, join)
+
+	problems, warnings := validateSearchQuery(query)
+	if len(problems) > 0 {
+		logging.FromContext(ctx).Debug("Built search query is invalid", "query", query, "problems", problems)
+		return nil, fmt.Errorf("the query %s is invalid: %s", query, strings.Join(problems, "; "))
+	}
+
+	logging.FromContext(ctx).Debug("Search query built", "query", query, "clauses", len(clauses))
+
+	return map[string]interface{}{
+		"query":    query,
+		"valid":    true,
+		"warnings": warnings,
+		"hint":     "pass query to search_documents",
+	}, nil
+}
@@ -0,0 +1,91 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// TestValidateSearchQuery tests that malformed queries are reported with
+// the reason, and well-formed ones pass
+func TestValidateSearchQuery(t *testing.T) {
+	tests := []struct {
+		query   string
+		problem string
+		warning string
+	}{
+		{query: `invoice tag:tax created:[2023 to 2024]`},
+		{query: `(tag:"tax 2023" OR correspondent:acme) AND NOT type:receipt`},
+		{query: `added:[-1 week to now] asn:[100 to]`},
+		{query: ``, problem: "query is empty"},
+		{query: `title:"unterminated`, problem: "unterminated quoted phrase"},
+		{query: `created:[2023 to 2024`, problem: "unterminated range"},
+		{query: `(invoice OR receipt`, problem: "unbalanced '('"},
+		{query: `invoice)`, problem: "unbalanced ')'"},
+		{query: `AND invoice`, problem: "AND must be between two terms"},
+		{query: `invoice OR`, problem: "query ends with an operator"},
+		{query: `tags:tax`, problem: `unknown search field "tags", did you mean "tag"?`},
+		{query: `tag:`, problem: "tag: has no value"},
+		{query: `title:[a to b]`, problem: "ranges only apply to date and number fields"},
+		{query: `created:[2023]`, problem: "must have the form [start to end]"},
+		{query: `asn:twelve`, problem: "asn expects a whole number"},
+		{query: `has_tag:maybe`, problem: "has_tag expects true or false"},
+		{query: `invoice and receipt`, warning: `"and" is searched for as a word`},
+		{query: `created:someday`, warning: `Paperless may not understand the date "someday"`},
+		{query: `a OR b AND c`, warning: "AND binds more tightly than OR"},
+	}
+
+	for _, tt := range tests {
+		problems, warnings := validateSearchQuery(tt.query)
+		if tt.problem == "" && len(problems) > 0 {
+			t.Errorf("validateSearchQuery(%q) reported %v, want no problems", tt.query, problems)
+		}
+		if tt.problem != "" && !strings.Contains(strings.Join(problems, "; "), tt.problem) {
+			t.Errorf("validateSearchQuery(%q) = %v, want a problem containing %q", tt.query, problems, tt.problem)
+		}
+		if tt.warning != "" && !strings.Contains(strings.Join(warnings, "; "), tt.warning) {
+			t.Errorf("validateSearchQuery(%q) warnings = %v, want one containing %q", tt.query, warnings, tt.warning)
+		}
+	}
+}
+
+// TestBuildSearchQuery tests assembling a query from clauses
+func TestBuildSearchQuery(t *testing.T) {
+	s := &Server{}
+
+	result, err := s.handleBuildSearchQuery(context.Background(), map[string]interface{}{
+		"clauses": []interface{}{
+			map[string]interface{}{"value": "electricity bill"},
+			map[string]interface{}{"field": "Tag", "any_of": []interface{}{"tax", "home office"}},
+			map[string]interface{}{"field": "created", "from": "2023"},
+			map[string]interface{}{"field": "type", "value": "receipt", "exclude": true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("build_search_query failed: %v", err)
+	}
+	want := `"electricity bill" AND (tag:tax OR tag:"home office") AND created:[2023 to] AND NOT type:receipt`
+	if got := result.(map[string]interface{})["query"]; got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+
+	for _, args := range []map[string]interface{}{
+		{},
+		{"clauses": []interface{}{map[string]interface{}{"field": "tags", "value": "tax"}}},
+		{"clauses": []interface{}{map[string]interface{}{"field": "tag", "value": "tax", "any_of": []interface{}{"home"}}}},
+		{"clauses": []interface{}{map[string]interface{}{"field": "asn", "value": "abc"}}},
+		{"clauses": []interface{}{map[string]interface{}{"value": "x"}}, "match": "some"},
+	} {
+		if _, err := s.handleBuildSearchQuery(context.Background(), args); err == nil {
+			t.Errorf("Expected %v to be rejected", args)
+		}
+	}
+
+	result, err = s.handleBuildSearchQuery(context.Background(), map[string]interface{}{"query": "tag:(tax"})
+	if err != nil {
+		t.Fatalf("Validating a query failed: %v", err)
+	}
+	if report := result.(map[string]interface{}); report["valid"] != false {
+		t.Errorf("Expected the query to be reported invalid, got %v", report)
+	}
+}
@@ -5,9 +5,14 @@ package mcp
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
+	"math"
+	"os"
 
 	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/config"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/metrics"
 	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -26,6 +31,23 @@ type Server struct {
 	paperlessClient *paperless.Client
 	mcpServer       *server.MCPServer
 	tools           map[string]Tool
+	idempotency     *idempotencyStore
+	savedQueries    *savedQueryStore
+	journal         *undoJournal
+	operations      *operationJournal
+	metrics         *metrics.Registry
+	summaries       *summaryStore
+	sessions        *sessionStore
+	documents       *documentCache
+	metadata        metadataCaches
+	health          upstreamHealth
+	paperlessURL    paperlessURLCheck
+	scope           tokenScope
+	inflight        inflightCalls
+	localizer       *textLocalizer
+
+	// instance identifies this replica in log lines
+	instance string
 }
 
 // Tool represents an MCP tool definition
@@ -34,6 +56,10 @@ type Tool struct {
 	Description string
 	InputSchema map[string]interface{}
 	Handler     ToolHandler
+
+	// Local tools do not call Paperless and stay available while it is
+	// unreachable
+	Local bool
 }
 
 // ToolHandler is the function signature for tool handlers
@@ -47,12 +73,86 @@ func New(cfg *config.Config) (*Server, error) {
 
 	// Create Paperless client
 	paperlessClient := paperless.New(cfg.PaperlessURL, cfg.PaperlessToken)
+	switch {
+	case cfg.PaperlessTokenFile != "":
+		paperlessClient.SetTokenSource(paperless.NewFileTokenSource(cfg.PaperlessTokenFile))
+	case cfg.PaperlessTokenCommand != "":
+		paperlessClient.SetTokenSource(paperless.NewCommandTokenSource(cfg.PaperlessTokenCommand))
+	}
+	if cfg.PaperlessBasicAuthUser != "" {
+		paperlessClient.SetBasicAuth(cfg.PaperlessBasicAuthUser, cfg.PaperlessBasicAuthPass, cfg.PaperlessBasicAuthHeader)
+		slog.Info("Sending basic credentials to Paperless reverse proxy",
+			"header", cfg.PaperlessBasicAuthHeader,
+			"user", cfg.PaperlessBasicAuthUser)
+	}
+	paperlessClient.SetTransport(paperless.NewTransport(paperless.TransportOptions{
+		MaxIdleConns:    cfg.PaperlessMaxIdleConns,
+		MaxConnsPerHost: cfg.PaperlessMaxConnsPerHost,
+		IdleConnTimeout: cfg.PaperlessIdleConnTimeout,
+		HTTP2:           cfg.PaperlessHTTP2,
+		MinTLSVersion:   cfg.PaperlessTLSMinVersion,
+	}))
+	paperlessClient.SetMaxResponseSize(cfg.PaperlessMaxResponseBytes)
+	paperlessClient.Use(paperless.RetryOnRateLimit(paperless.DefaultRateLimitRetries, cfg.PaperlessRateLimitMaxWait))
+
+	// Timeouts and retries per endpoint class replace the client's single
+	// timeout; installed inside the rate limiter so each rate limited
+	// attempt gets the full timeout
+	policies := make(map[string]paperless.RequestPolicy, len(cfg.PaperlessRequestPolicies))
+	for class, policy := range cfg.PaperlessRequestPolicies {
+		policies[class] = paperless.RequestPolicy{Timeout: policy.Timeout, Retries: policy.Retries}
+	}
+	if len(policies) > 0 {
+		paperlessClient.SetTimeout(0)
+		paperlessClient.Use(paperless.ApplyRequestPolicies(policies))
+	}
+
+	// Count each attempt, including rate limited ones that are retried
+	registry := metrics.NewRegistry()
+	paperlessClient.Use(paperless.Instrument(newPaperlessMetrics(registry).observe))
+
+	// Record or replay Paperless interactions; installed inside the rate
+	// limiter so that retried attempts are recorded individually
+	switch cfg.PaperlessCassetteMode {
+	case paperless.CassetteModeRecord:
+		recorder, err := paperless.NewRecorder(cfg.PaperlessCassetteFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to start cassette recording: %w", err)
+		}
+		paperlessClient.Use(recorder.Interceptor)
+		slog.Info("Recording Paperless interactions", "cassette", cfg.PaperlessCassetteFile)
+	case paperless.CassetteModeReplay:
+		replayer, err := paperless.NewReplayer(cfg.PaperlessCassetteFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load cassette for replay: %w", err)
+		}
+		paperlessClient.Use(replayer.Interceptor)
+		slog.Info("Replaying Paperless interactions", "cassette", cfg.PaperlessCassetteFile)
+	}
+
+	documents := newDocumentCache(cfg.MCPDocumentCacheSize, cfg.MCPDocumentCacheTTL)
+	sessionBackend, err := newSessionStoreBackend(cfg.MCPSessionStoreURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session store: %w", err)
+	}
+	sessions := newSessionStore(sessionBackend, cfg.MCPSessionTTL)
+
+	// Forget a session's cached documents when it ends. Session defaults
+	// may be shared with other replicas, so they are only dropped when the
+	// client terminates the session or they expire.
+	hooks := &server.Hooks{}
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		documents.dropSession(session.SessionID())
+	})
 
 	// Create MCP server instance with the mark3labs SDK
 	mcpServer := server.NewMCPServer(
 		ServerName,
 		ServerVersion,
 		server.WithLogging(),
+		server.WithElicitation(),
+		server.WithRoots(),
+		server.WithHooks(hooks),
 	)
 
 	s := &Server{
@@ -60,6 +160,17 @@ func New(cfg *config.Config) (*Server, error) {
 		paperlessClient: paperlessClient,
 		mcpServer:       mcpServer,
 		tools:           make(map[string]Tool),
+		idempotency:     newIdempotencyStore(cfg.MCPIdempotencyFile),
+		savedQueries:    newSavedQueryStore(cfg.MCPSavedQueriesFile),
+		journal:         newUndoJournal(cfg.MCPUndoJournalFile),
+		operations:      newOperationJournal(cfg.MCPOperationJournalFile),
+		metrics:         registry,
+		summaries:       newSummaryStore(cfg.MCPSummariesFile),
+		sessions:        sessions,
+		documents:       documents,
+		metadata:        newMetadataCaches(MetadataCacheTTL),
+		localizer:       newTextLocalizer(cfg),
+		instance:        instanceName(),
 	}
 
 	// Register initial tools
@@ -81,7 +192,15 @@ func (s *Server) RegisterTool(tool Tool) error {
 
 	// Store in our tools map
 	s.tools[tool.Name] = tool
+	s.addMCPTool(tool)
+
+	slog.Info("Tool registered successfully", "tool_name", tool.Name)
+	return nil
+}
 
+// addMCPTool advertises tool to MCP clients, replacing any tool of the same
+// name
+func (s *Server) addMCPTool(tool Tool) {
 	// Create the MCP tool using the appropriate method based on whether we have an InputSchema
 	// 
 	// The mcp-go SDK v0.43.2 has two ways to create tools with schemas:
@@ -135,21 +254,35 @@ func (s *Server) RegisterTool(tool Tool) error {
 			}
 		}
 
+		// Long-running tools report progress when the client asks for it
+		if request.Params.Meta != nil && request.Params.Meta.ProgressToken != nil {
+			ctx = withProgressToken(ctx, request.Params.Meta.ProgressToken)
+		}
+
 		// Call our tool handler
 		result, err := s.ExecuteTool(ctx, toolName, args)
 		if err != nil {
-			return mcp.NewToolResultError(err.Error()), nil
+			return newToolErrorResult(err), nil
 		}
 
-		// Return structured result using the SDK's built-in function
-		return newStructuredToolResult(result), nil
+		// Return structured result using the SDK's built-in function, with
+		// the text fallback localized when configured
+		toolResult := newStructuredToolResult(result)
+		s.localizer.apply(toolResult)
+		return toolResult, nil
 	}
 
 	// Add the tool to the MCP server
 	s.mcpServer.AddTool(mcpTool, handlerWrapper)
+}
 
-	slog.Info("Tool registered successfully", "tool_name", tool.Name)
-	return nil
+// instanceName identifies the replica serving a request, the host name
+// (the pod name on Kubernetes) or the process ID when it is not available
+func instanceName() string {
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		return hostname
+	}
+	return fmt.Sprintf("pid-%d", os.Getpid())
 }
 
 // newStructuredToolResult creates an MCP tool result with structured JSON content.
@@ -175,12 +308,67 @@ func (s *Server) RegisterTool(tool Tool) error {
 // MIME types on content blocks) to indicate structured data. MCP clients should
 // check for the presence of StructuredContent to determine if the response
 // contains parseable structured data.
+//
+// Results wrapped with withContent or withResources additionally get their
+// extra content blocks, so clients that support resource attachments or
+// images can open the files natively.
 func newStructuredToolResult(result interface{}) *mcp.CallToolResult {
+	cr, hasContent := result.(*contentResult)
+	if hasContent {
+		result = cr.Result
+	}
+
 	// Use the SDK's NewToolResultStructuredOnly function which:
 	// - Sets the StructuredContent field to the provided data
 	// - Creates a JSON string fallback in the Content array for backward compatibility
 	// - Handles marshaling errors gracefully by including error message in fallback
-	return mcp.NewToolResultStructuredOnly(result)
+	toolResult := mcp.NewToolResultStructuredOnly(result)
+
+	if hasContent {
+		toolResult.Content = append(toolResult.Content, cr.Content...)
+	}
+	return toolResult
+}
+
+// newToolErrorResult creates an MCP error result for a failed tool call.
+//
+// Errors that are safe to retry later (such as Paperless rate limiting)
+// additionally carry structured content with "retryable" and
+// "retry_after_seconds" so agents can back off instead of giving up.
+// Validation failures carry the per-field messages as "field_errors", and
+// refused concurrent updates the document's current state as "current".
+func newToolErrorResult(err error) *mcp.CallToolResult {
+	result := mcp.NewToolResultError(err.Error())
+
+	var rateLimitErr *paperless.RateLimitError
+	var conflictErr *ConflictError
+	if errors.Is(err, ErrShuttingDown) {
+		result.StructuredContent = map[string]interface{}{
+			"error":     err.Error(),
+			"retryable": true,
+		}
+	} else if errors.As(err, &rateLimitErr) {
+		result.StructuredContent = map[string]interface{}{
+			"error":               err.Error(),
+			"retryable":           true,
+			"retry_after_seconds": int(math.Ceil(rateLimitErr.RetryAfter.Seconds())),
+		}
+	} else if errors.As(err, &conflictErr) {
+		result.StructuredContent = map[string]interface{}{
+			"error":     err.Error(),
+			"retryable": false,
+			"conflict":  true,
+			"current":   conflictErr.Current,
+		}
+	} else if fields := paperless.GetFieldErrors(err); len(fields) > 0 {
+		result.StructuredContent = map[string]interface{}{
+			"error":        err.Error(),
+			"retryable":    false,
+			"field_errors": fields,
+		}
+	}
+
+	return result
 }
 
 // GetPaperlessClient returns the Paperless API client
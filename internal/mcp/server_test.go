@@ -1,11 +1,20 @@
 package mcp
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/config"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+	"github.com/mark3labs/mcp-go/mcp"
 )
 
 // TestToolRegistrationWithSchema tests that tools can be registered with input schemas
@@ -129,3 +138,82 @@ func TestToolHandlerExecution(t *testing.T) {
 
 	t.Logf("Ping result: %+v", resultMap)
 }
+
+// TestExecuteToolContextLogger tests that lines logged by a handler and by
+// the Paperless client while serving a tool call carry the session, tool
+// and instance
+func TestExecuteToolContextLogger(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id": 7, "title": "Invoice"}`))
+	}))
+	defer ts.Close()
+
+	s := &Server{
+		cfg:             &config.Config{},
+		paperlessClient: paperless.New(ts.URL, "test-token"),
+		tools:           make(map[string]Tool),
+		sessions:        newSessionStore(newMemorySessionStore(), time.Hour),
+		instance:        "replica-1",
+	}
+	s.tools["probe"] = Tool{
+		Name: "probe",
+		Handler: func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+			logging.FromContext(ctx).Info("Probing Paperless")
+			return s.paperlessClient.GetDocument(ctx, 7)
+		},
+	}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	ctx := logging.WithLogger(context.Background(), logger)
+	if _, err := s.ExecuteTool(ctx, "probe", map[string]interface{}{}); err != nil {
+		t.Fatalf("ExecuteTool failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	for _, message := range []string{"Executing tool", "Probing Paperless", "Getting document", "Making API request"} {
+		found := false
+		for _, line := range lines {
+			if !strings.Contains(line, `msg="`+message+`"`) {
+				continue
+			}
+			found = true
+			for _, attr := range []string{"session_id=" + defaultSessionKey, "tool=probe", "instance=replica-1"} {
+				if !strings.Contains(line, attr) {
+					t.Errorf("log line %q missing %s", line, attr)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("no %q log line in:\n%s", message, buf.String())
+		}
+	}
+}
+
+// TestStructuredResultWithResources tests that attached files become
+// embedded resource blocks while the JSON form stays the plain result
+func TestStructuredResultWithResources(t *testing.T) {
+	file := &paperless.DocumentFile{Filename: "scan.pdf", ContentType: "application/pdf", Content: []byte("%PDF")}
+	result := withResources(map[string]interface{}{"id": 3}, documentResource(3, true, file))
+
+	data, err := json.Marshal(result)
+	if err != nil || string(data) != `{"id":3}` {
+		t.Errorf("Expected plain JSON, got %s (%v)", data, err)
+	}
+
+	toolResult := newStructuredToolResult(result)
+	if len(toolResult.Content) != 2 {
+		t.Fatalf("Expected text and resource content, got %d blocks", len(toolResult.Content))
+	}
+	embedded, ok := toolResult.Content[1].(mcp.EmbeddedResource)
+	if !ok {
+		t.Fatalf("Expected an embedded resource, got %T", toolResult.Content[1])
+	}
+	blob, ok := embedded.Resource.(mcp.BlobResourceContents)
+	if !ok || blob.URI != "paperless://documents/3/original" || blob.Blob != "JVBERg==" {
+		t.Errorf("Unexpected resource: %+v", embedded.Resource)
+	}
+	if _, ok := toolResult.StructuredContent.(map[string]interface{}); !ok {
+		t.Errorf("Expected structured content to be the plain result, got %T", toolResult.StructuredContent)
+	}
+}
@@ -0,0 +1,176 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+)
+
+// sessionDefaults are sticky settings a session applies to its later tool
+// calls
+type sessionDefaults struct {
+	// PageSize is used by paginated tools called without page_size
+	PageSize int `json:"page_size,omitempty"`
+
+	// TagIDs restrict document filters that do not filter by tag
+	// themselves to documents having all of these tags
+	TagIDs []int `json:"tags,omitempty"`
+}
+
+// sessionStore keeps the defaults of each MCP session in a SessionStore,
+// expiring them ttl after they were last changed
+type sessionStore struct {
+	backend SessionStore
+	ttl     time.Duration
+}
+
+// newSessionStore creates a session store on top of backend
+func newSessionStore(backend SessionStore, ttl time.Duration) *sessionStore {
+	return &sessionStore{backend: backend, ttl: ttl}
+}
+
+// get returns the defaults of a session, zero when none were set or the
+// store cannot be read
+func (st *sessionStore) get(ctx context.Context, session string) sessionDefaults {
+	var defaults sessionDefaults
+	data, err := st.backend.Load(ctx, session)
+	if err != nil {
+		logging.FromContext(ctx).Warn("Failed to load session state", "error", err)
+		return defaults
+	}
+	if data != nil {
+		if err := json.Unmarshal(data, &defaults); err != nil {
+			logging.FromContext(ctx).Warn("Ignoring unreadable session state", "error", err)
+		}
+	}
+	return defaults
+}
+
+// set replaces the defaults of a session
+func (st *sessionStore) set(ctx context.Context, session string, defaults sessionDefaults) error {
+	if defaults.PageSize == 0 && len(defaults.TagIDs) == 0 {
+		return st.backend.Delete(ctx, session)
+	}
+	data, err := json.Marshal(defaults)
+	if err != nil {
+		return err
+	}
+	return st.backend.Save(ctx, session, data, st.ttl)
+}
+
+// dropSession forgets the defaults of a session that has ended
+func (st *sessionStore) dropSession(ctx context.Context, session string) {
+	if err := st.backend.Delete(ctx, session); err != nil {
+		logging.FromContext(ctx).Warn("Failed to delete session state", "error", err)
+	}
+}
+
+// applySessionDefaults fills in arguments the caller left out from the
+// session's defaults. The caller's map is not modified.
+func (s *Server) applySessionDefaults(ctx context.Context, tool Tool, args map[string]interface{}) map[string]interface{} {
+	defaults := s.sessions.get(ctx, sessionKey(ctx))
+	if defaults.PageSize == 0 {
+		return args
+	}
+	if _, set := args["page_size"]; set || !hasProperty(tool, "page_size") {
+		return args
+	}
+
+	merged := make(map[string]interface{}, len(args)+1)
+	for key, value := range args {
+		merged[key] = value
+	}
+	merged["page_size"] = float64(defaults.PageSize)
+	return merged
+}
+
+// hasProperty reports whether a tool's input schema declares a property
+func hasProperty(tool Tool, name string) bool {
+	properties, ok := tool.InputSchema["properties"].(map[string]interface{})
+	if !ok {
+		return false
+	}
+	_, ok = properties[name]
+	return ok
+}
+
+// applyDefaultTags restricts a document filter to the session's default
+// tags unless the filter already filters by tag. It returns the tag IDs
+// applied, or nil.
+func (s *Server) applyDefaultTags(ctx context.Context, filters url.Values) []int {
+	defaults := s.sessions.get(ctx, sessionKey(ctx))
+	if len(defaults.TagIDs) == 0 {
+		return nil
+	}
+	for key := range filters {
+		if strings.HasPrefix(key, "tags__") || key == "is_tagged" {
+			return nil
+		}
+	}
+
+	ids := make([]string, len(defaults.TagIDs))
+	for i, id := range defaults.TagIDs {
+		ids[i] = strconv.Itoa(id)
+	}
+	filters.Set("tags__id__all", strings.Join(ids, ","))
+	return defaults.TagIDs
+}
+
+// handleSetSessionDefaults handles the set_session_defaults tool
+func (s *Server) handleSetSessionDefaults(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	// A server talks to the one Paperless instance of PAPERLESS_URL, and
+	// its tools have no detail levels, so neither can be a default
+	if _, ok := args["instance"]; ok {
+		return nil, fmt.Errorf("instance is not supported: this server talks to the single Paperless instance set by PAPERLESS_URL; run one server per instance")
+	}
+	if _, ok := args["detail_level"]; ok {
+		return nil, fmt.Errorf("detail_level is not supported: tools have no detail levels; limit output with a tool's own options, such as preview_chars on searches")
+	}
+
+	session := sessionKey(ctx)
+	defaults := s.sessions.get(ctx, session)
+
+	if clear, _ := args["clear"].(bool); clear {
+		defaults = sessionDefaults{}
+	}
+
+	if pageSizeVal, ok := args["page_size"].(float64); ok {
+		pageSize := int(pageSizeVal)
+		if pageSize < 0 || pageSize > MaxPageSize {
+			return nil, fmt.Errorf("page_size must be between 1 and %d, or 0 to unset", MaxPageSize)
+		}
+		defaults.PageSize = pageSize
+	}
+
+	if tagsInterface, ok := args["tags"].([]interface{}); ok {
+		tagIDs := make([]int, 0, len(tagsInterface))
+		for _, tagInterface := range tagsInterface {
+			tagFloat, ok := tagInterface.(float64)
+			if !ok || tagFloat < 1 {
+				return nil, fmt.Errorf("tags must contain only positive integers")
+			}
+			tagIDs = append(tagIDs, int(tagFloat))
+		}
+		defaults.TagIDs = tagIDs
+	}
+
+	if err := s.sessions.set(ctx, session, defaults); err != nil {
+		logging.FromContext(ctx).Error("Failed to save session defaults", "error", err)
+		return nil, fmt.Errorf("failed to save session defaults: %w", err)
+	}
+
+	logging.FromContext(ctx).Info("Session defaults set",
+		"page_size", defaults.PageSize,
+		"tags", defaults.TagIDs)
+
+	return map[string]interface{}{
+		"success":  true,
+		"defaults": defaults,
+	}, nil
+}
@@ -0,0 +1,98 @@
+package mcp
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// TestSessionDefaults tests that defaults fill in missing arguments only,
+// apply tag filters only to untagged filters and stay within the session,
+// and that an instance or detail level is rejected
+func TestSessionDefaults(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0")
+	s := &Server{mcpServer: mcpServer, sessions: newSessionStore(newMemorySessionStore(), time.Hour)}
+	ctx := mcpServer.WithContext(context.Background(), &elicitingSession{})
+
+	_, err := s.handleSetSessionDefaults(ctx, map[string]interface{}{
+		"page_size": float64(10),
+		"tags":      []interface{}{float64(4), float64(7)},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	paginated := Tool{InputSchema: map[string]interface{}{
+		"properties": map[string]interface{}{"page_size": map[string]interface{}{}},
+	}}
+	args := map[string]interface{}{"query": "invoice"}
+	if got := s.applySessionDefaults(ctx, paginated, args); got["page_size"] != float64(10) {
+		t.Errorf("Expected the default page_size, got %v", got)
+	}
+	if _, modified := args["page_size"]; modified {
+		t.Error("Expected the caller's arguments not to be modified")
+	}
+	explicit := map[string]interface{}{"page_size": float64(50)}
+	if got := s.applySessionDefaults(ctx, paginated, explicit); got["page_size"] != float64(50) {
+		t.Errorf("Expected an explicit page_size to win, got %v", got)
+	}
+	if got := s.applySessionDefaults(ctx, Tool{}, map[string]interface{}{}); len(got) != 0 {
+		t.Errorf("Expected tools without page_size to be left alone, got %v", got)
+	}
+
+	filters := url.Values{"correspondent__id": {"3"}}
+	if tags := s.applyDefaultTags(ctx, filters); !reflect.DeepEqual(tags, []int{4, 7}) || filters.Get("tags__id__all") != "4,7" {
+		t.Errorf("Expected default tags to be applied, got %v and %v", tags, filters)
+	}
+	filters = url.Values{"tags__id__in": {"9"}}
+	if tags := s.applyDefaultTags(ctx, filters); tags != nil || filters.Get("tags__id__all") != "" {
+		t.Errorf("Expected an explicit tag filter to win, got %v", filters)
+	}
+
+	if tags := s.applyDefaultTags(context.Background(), url.Values{}); tags != nil {
+		t.Errorf("Expected defaults to be scoped to their session, got %v", tags)
+	}
+
+	if _, err := s.handleSetSessionDefaults(ctx, map[string]interface{}{"clear": true}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if defaults := s.sessions.get(ctx, sessionKey(ctx)); defaults.PageSize != 0 || defaults.TagIDs != nil {
+		t.Errorf("Expected defaults to be cleared, got %+v", defaults)
+	}
+
+	// Defaults the server cannot honour are refused rather than ignored
+	for _, name := range []string{"instance", "detail_level"} {
+		_, err := s.handleSetSessionDefaults(ctx, map[string]interface{}{name: "x", "page_size": float64(5)})
+		if err == nil || !strings.Contains(err.Error(), name+" is not supported") {
+			t.Errorf("Expected %s to be rejected, got %v", name, err)
+		}
+	}
+	if defaults := s.sessions.get(ctx, sessionKey(ctx)); defaults.PageSize != 0 {
+		t.Errorf("Expected a rejected call to change nothing, got %+v", defaults)
+	}
+}
+
+// TestMemorySessionStore tests expiry and deletion of session state
+func TestMemorySessionStore(t *testing.T) {
+	ctx := context.Background()
+	store := newMemorySessionStore()
+
+	store.Save(ctx, "a", []byte("one"), time.Hour)
+	store.Save(ctx, "b", []byte("two"), -time.Second)
+	if data, _ := store.Load(ctx, "a"); string(data) != "one" {
+		t.Errorf("Expected stored state, got %q", data)
+	}
+	if data, _ := store.Load(ctx, "b"); data != nil {
+		t.Errorf("Expected expired state to be gone, got %q", data)
+	}
+
+	store.Delete(ctx, "a")
+	if data, _ := store.Load(ctx, "a"); data != nil {
+		t.Errorf("Expected deleted state to be gone, got %q", data)
+	}
+}
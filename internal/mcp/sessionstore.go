@@ -0,0 +1,144 @@
+package mcp
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/redis"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// SessionKeyPrefix namespaces session state in a shared store
+const SessionKeyPrefix = "paperless-mcp:session:"
+
+// SessionStore holds the encoded state of MCP sessions. The in-memory
+// store serves a single process; a shared store such as Redis lets
+// StreamableHTTP sessions move between replicas behind a load balancer and
+// survive restarts.
+type SessionStore interface {
+	// Load returns the state of a session, or nil when there is none
+	Load(ctx context.Context, sessionID string) ([]byte, error)
+
+	// Save stores the state of a session, expiring it after ttl
+	Save(ctx context.Context, sessionID string, data []byte, ttl time.Duration) error
+
+	// Delete removes the state of a session
+	Delete(ctx context.Context, sessionID string) error
+}
+
+// memorySessionStore is a SessionStore local to the process
+type memorySessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]memorySession
+}
+
+// memorySession is the state of one session and when it expires
+type memorySession struct {
+	data    []byte
+	expires time.Time
+}
+
+// newMemorySessionStore creates an empty in-memory session store
+func newMemorySessionStore() *memorySessionStore {
+	return &memorySessionStore{sessions: make(map[string]memorySession)}
+}
+
+// Load implements SessionStore
+func (m *memorySessionStore) Load(ctx context.Context, sessionID string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	session, ok := m.sessions[sessionID]
+	if !ok {
+		return nil, nil
+	}
+	if time.Now().After(session.expires) {
+		delete(m.sessions, sessionID)
+		return nil, nil
+	}
+	return session.data, nil
+}
+
+// Save implements SessionStore
+func (m *memorySessionStore) Save(ctx context.Context, sessionID string, data []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Expired sessions are swept on write so abandoned ones do not pile up
+	now := time.Now()
+	for id, session := range m.sessions {
+		if now.After(session.expires) {
+			delete(m.sessions, id)
+		}
+	}
+	m.sessions[sessionID] = memorySession{data: data, expires: now.Add(ttl)}
+	return nil
+}
+
+// Delete implements SessionStore
+func (m *memorySessionStore) Delete(ctx context.Context, sessionID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, sessionID)
+	return nil
+}
+
+// redisSessionStore is a SessionStore shared between replicas through Redis
+type redisSessionStore struct {
+	client *redis.Client
+}
+
+// Load implements SessionStore
+func (r *redisSessionStore) Load(ctx context.Context, sessionID string) ([]byte, error) {
+	return r.client.Get(ctx, SessionKeyPrefix+sessionID)
+}
+
+// Save implements SessionStore
+func (r *redisSessionStore) Save(ctx context.Context, sessionID string, data []byte, ttl time.Duration) error {
+	return r.client.Set(ctx, SessionKeyPrefix+sessionID, data, ttl)
+}
+
+// Delete implements SessionStore
+func (r *redisSessionStore) Delete(ctx context.Context, sessionID string) error {
+	return r.client.Del(ctx, SessionKeyPrefix+sessionID)
+}
+
+// newSessionStoreBackend creates the store configured by
+// MCP_SESSION_STORE_URL: Redis when set, otherwise in memory
+func newSessionStoreBackend(storeURL string) (SessionStore, error) {
+	if storeURL == "" {
+		return newMemorySessionStore(), nil
+	}
+	client, err := redis.New(storeURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// An unreachable store is reported now but not fatal; session state
+	// falls back to defaults until it comes back
+	ctx, cancel := context.WithTimeout(context.Background(), redis.Timeout)
+	defer cancel()
+	if err := client.Ping(ctx); err != nil {
+		slog.Warn("Session store is not reachable", "error", err)
+	} else {
+		slog.Info("Using Redis session store")
+	}
+	return &redisSessionStore{client: client}, nil
+}
+
+// sessionIDManager generates StreamableHTTP session IDs that any replica
+// accepts, and drops a session's stored state when the client ends it
+type sessionIDManager struct {
+	server.StatelessGeneratingSessionIdManager
+	sessions *sessionStore
+}
+
+// Terminate implements server.SessionIdManager
+func (m *sessionIDManager) Terminate(sessionID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), redis.Timeout)
+	defer cancel()
+	m.sessions.dropSession(ctx, sessionID)
+	return false, nil
+}
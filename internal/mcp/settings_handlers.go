@@ -0,0 +1,62 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+)
+
+// handleGetPaperlessSettings handles the get_paperless_settings tool. Each
+// section is fetched independently so that a token without permission to
+// read the application configuration still gets the UI settings.
+func (s *Server) handleGetPaperlessSettings(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	logging.FromContext(ctx).Debug("Getting Paperless settings")
+
+	result := map[string]interface{}{}
+	failed := 0
+
+	uiSettings, err := s.paperlessClient.GetUISettings(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Warn("Failed to get UI settings", "error", err)
+		result["ui_settings_error"] = err.Error()
+		failed++
+	} else {
+		result["user"] = uiSettings.User
+		result["ui_settings"] = uiSettings.Settings
+	}
+
+	config, err := s.paperlessClient.GetApplicationConfig(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Warn("Failed to get application configuration", "error", err)
+		result["application_config_error"] = err.Error()
+		failed++
+	} else if len(config) > 0 {
+		result["application_config"] = config[0]
+	} else {
+		result["application_config"] = map[string]interface{}{}
+	}
+
+	tags, err := s.metadata.tags.get(ctx, s.paperlessClient)
+	if err != nil {
+		logging.FromContext(ctx).Warn("Failed to list tags", "error", err)
+		result["inbox_tags_error"] = err.Error()
+		failed++
+	} else {
+		inboxTags := []map[string]interface{}{}
+		for _, t := range tags {
+			if t.IsInboxTag {
+				inboxTags = append(inboxTags, map[string]interface{}{"id": t.ID, "name": t.Name})
+			}
+		}
+		result["inbox_tags"] = inboxTags
+	}
+
+	if failed == 3 {
+		logging.FromContext(ctx).Error("Failed to get Paperless settings", "error", err)
+		return nil, fmt.Errorf("failed to get Paperless settings: %w", err)
+	}
+
+	logging.FromContext(ctx).Info("Paperless settings retrieved", "sections_failed", failed)
+	return result, nil
+}
@@ -0,0 +1,74 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// TestGetPaperlessSettings tests that get_paperless_settings combines the
+// UI settings, application configuration and inbox tags, and that a
+// section the token cannot read is reported without failing the others
+func TestGetPaperlessSettings(t *testing.T) {
+	configForbidden := false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/ui_settings/":
+			w.Write([]byte(`{"user":{"id":2,"username":"alice"},"settings":{"dark_mode":{"enabled":true}}}`))
+		case "/api/config/":
+			if configForbidden {
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte(`{"detail":"You do not have permission to perform this action."}`))
+				return
+			}
+			w.Write([]byte(`[{"id":1,"output_type":"pdfa","language":"deu+eng"}]`))
+		case "/api/tags/":
+			w.Write([]byte(`{"count":2,"next":null,"results":[{"id":1,"name":"Inbox","is_inbox_tag":true},{"id":2,"name":"Bills"}]}`))
+		default:
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+	}))
+	defer ts.Close()
+
+	s := &Server{
+		paperlessClient: paperless.New(ts.URL, "test-token"),
+		metadata:        newMetadataCaches(time.Hour),
+	}
+	getSettings := func() map[string]interface{} {
+		t.Helper()
+		result, err := s.handleGetPaperlessSettings(context.Background(), map[string]interface{}{})
+		if err != nil {
+			t.Fatalf("get_paperless_settings failed: %v", err)
+		}
+		return result.(map[string]interface{})
+	}
+
+	settings := getSettings()
+	if user := settings["user"].(paperless.UISettingsUser); user.Username != "alice" {
+		t.Errorf("Expected the user of the token, got %+v", user)
+	}
+	if _, ok := settings["ui_settings"].(map[string]interface{})["dark_mode"]; !ok {
+		t.Errorf("Expected the UI settings, got %v", settings["ui_settings"])
+	}
+	if config := settings["application_config"].(map[string]interface{}); config["output_type"] != "pdfa" {
+		t.Errorf("Expected the application configuration, got %v", config)
+	}
+	want := []map[string]interface{}{{"id": 1, "name": "Inbox"}}
+	if got := settings["inbox_tags"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected the inbox tags %v, got %v", want, got)
+	}
+
+	configForbidden = true
+	settings = getSettings()
+	if _, ok := settings["application_config"]; ok {
+		t.Errorf("Expected no application configuration, got %v", settings["application_config"])
+	}
+	if settings["application_config_error"] == nil || settings["ui_settings"] == nil {
+		t.Errorf("Expected the error reported alongside the other sections, got %v", settings)
+	}
+}
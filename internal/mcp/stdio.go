@@ -0,0 +1,85 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// StdioDrainTimeout bounds how long in-flight tool calls may keep running
+// after the stdio client has gone away
+const StdioDrainTimeout = 5 * time.Second
+
+// clientGone is closed once the stdio client disconnects, either by
+// closing our stdin or by closing the pipe we write responses to
+type clientGone struct {
+	once sync.Once
+	done chan struct{}
+}
+
+func newClientGone() *clientGone {
+	return &clientGone{done: make(chan struct{})}
+}
+
+func (c *clientGone) signal() {
+	c.once.Do(func() { close(c.done) })
+}
+
+// isDisconnect reports whether err means the other end of a pipe is gone
+func isDisconnect(err error) bool {
+	return errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrClosedPipe) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, os.ErrClosed)
+}
+
+// stdinReader signals when the client closes stdin
+type stdinReader struct {
+	r    io.Reader
+	gone *clientGone
+}
+
+func (s *stdinReader) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	if err != nil && isDisconnect(err) {
+		s.gone.signal()
+	}
+	return n, err
+}
+
+// stdoutWriter signals when writing to the client fails with a broken pipe
+type stdoutWriter struct {
+	w    io.Writer
+	gone *clientGone
+}
+
+func (s *stdoutWriter) Write(p []byte) (int, error) {
+	n, err := s.w.Write(p)
+	if err != nil && isDisconnect(err) {
+		s.gone.signal()
+	}
+	return n, err
+}
+
+// stdioErrorLog routes the SDK's error log to slog. Errors after the
+// client has disconnected are expected and logged at debug level only.
+type stdioErrorLog struct {
+	gone *clientGone
+}
+
+func (l *stdioErrorLog) Write(p []byte) (int, error) {
+	level := slog.LevelWarn
+	select {
+	case <-l.gone.done:
+		level = slog.LevelDebug
+	default:
+	}
+	slog.Log(context.Background(), level, strings.TrimSpace(string(p)), "component", "stdio")
+	return len(p), nil
+}
@@ -4,8 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log/slog"
 
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
 	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
 )
 
@@ -31,34 +31,33 @@ func (s *Server) handleListStoragePaths(ctx context.Context, args map[string]int
 		}
 	}
 
-	slog.Debug("Listing storage paths", "page", page, "page_size", pageSize)
+	// Optional case-insensitive name filter and ordering
+	opts, err := parseListOptions(args, metadataOrderings)
+	if err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Debug("Listing storage paths", "name", opts.Name, "ordering", opts.Ordering, "page", page, "page_size", pageSize)
 
 	// Call Paperless API
-	response, err := s.paperlessClient.ListStoragePaths(ctx, page, pageSize)
+	response, err := s.paperlessClient.ListStoragePathsMatching(ctx, opts, page, pageSize)
 	if err != nil {
-		slog.Error("Failed to list storage paths", "error", err)
+		logging.FromContext(ctx).Error("Failed to list storage paths", "error", err)
 		return nil, fmt.Errorf("failed to list storage paths: %w", err)
 	}
 
 	// Parse storage paths from Results
 	var storagePaths []paperless.StoragePath
 	if err := json.Unmarshal(response.Results, &storagePaths); err != nil {
-		slog.Error("Failed to parse storage paths results", "error", err)
+		logging.FromContext(ctx).Error("Failed to parse storage paths results", "error", err)
 		return nil, fmt.Errorf("failed to parse results: %w", err)
 	}
 
-	slog.Info("Storage paths listed successfully",
+	logging.FromContext(ctx).Info("Storage paths listed successfully",
 		"count", response.Count,
 		"returned", len(storagePaths))
 
-	return map[string]interface{}{
-		"count":         response.Count,
-		"page":          page,
-		"page_size":     pageSize,
-		"has_next":      response.Next != nil,
-		"has_prev":      response.Previous != nil,
-		"storage_paths": storagePaths,
-	}, nil
+	return newListResult(storagePaths, response.Count, page, pageSize, listOptionFilters(opts)), nil
 }
 
 // handleGetStoragePath handles the get_storage_path tool
@@ -73,18 +72,18 @@ func (s *Server) handleGetStoragePath(ctx context.Context, args map[string]inter
 		return nil, fmt.Errorf("storage_path_id must be a positive integer")
 	}
 
-	slog.Debug("Getting storage path", "storage_path_id", storagePathID)
+	logging.FromContext(ctx).Debug("Getting storage path", "storage_path_id", storagePathID)
 
 	// Call Paperless API
 	storagePath, err := s.paperlessClient.GetStoragePath(ctx, storagePathID)
 	if err != nil {
-		slog.Error("Failed to get storage path",
+		logging.FromContext(ctx).Error("Failed to get storage path",
 			"storage_path_id", storagePathID,
 			"error", err)
 		return nil, fmt.Errorf("failed to get storage path: %w", err)
 	}
 
-	slog.Info("Storage path retrieved successfully",
+	logging.FromContext(ctx).Info("Storage path retrieved successfully",
 		"storage_path_id", storagePathID,
 		"name", storagePath.Name)
 
@@ -105,7 +104,11 @@ func (s *Server) handleCreateStoragePath(ctx context.Context, args map[string]in
 		return nil, fmt.Errorf("path parameter is required and must be a non-empty string")
 	}
 
-	slog.Debug("Creating storage path", "name", name, "path", pathStr)
+	if err := validateStoragePathTemplate(pathStr); err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Debug("Creating storage path", "name", name, "path", pathStr)
 
 	// Build storage path from args
 	storagePath := &paperless.StoragePath{
@@ -127,13 +130,18 @@ func (s *Server) handleCreateStoragePath(ctx context.Context, args map[string]in
 	// Call Paperless API
 	createdStoragePath, err := s.paperlessClient.CreateStoragePath(ctx, storagePath)
 	if err != nil {
-		slog.Error("Failed to create storage path",
+		logging.FromContext(ctx).Error("Failed to create storage path",
 			"name", name,
 			"error", err)
 		return nil, fmt.Errorf("failed to create storage path: %w", err)
 	}
 
-	slog.Info("Storage path created successfully",
+	s.metadata.storagePaths.invalidate()
+
+	s.journal.recordCreate(ctx, "create_storage_path", "storage_paths", createdStoragePath.ID,
+		fmt.Sprintf("Created storage path %q", createdStoragePath.Name))
+
+	logging.FromContext(ctx).Info("Storage path created successfully",
 		"storage_path_id", createdStoragePath.ID,
 		"name", createdStoragePath.Name)
 
@@ -164,20 +172,33 @@ func (s *Server) handleUpdateStoragePath(ctx context.Context, args map[string]in
 		return nil, fmt.Errorf("at least one field to update must be provided")
 	}
 
-	slog.Debug("Updating storage path",
+	if pathStr, ok := updates["path"].(string); ok {
+		if err := validateStoragePathTemplate(pathStr); err != nil {
+			return nil, err
+		}
+	}
+
+	logging.FromContext(ctx).Debug("Updating storage path",
 		"storage_path_id", storagePathID,
 		"fields", len(updates))
 
+	previous := s.snapshotFields(ctx, "storage_paths", storagePathID, updates)
+
 	// Call Paperless API
 	updatedStoragePath, err := s.paperlessClient.UpdateStoragePath(ctx, storagePathID, updates)
 	if err != nil {
-		slog.Error("Failed to update storage path",
+		logging.FromContext(ctx).Error("Failed to update storage path",
 			"storage_path_id", storagePathID,
 			"error", err)
 		return nil, fmt.Errorf("failed to update storage path: %w", err)
 	}
 
-	slog.Info("Storage path updated successfully",
+	s.metadata.storagePaths.invalidate()
+
+	s.journal.recordUpdate(ctx, "update_storage_path", "storage_paths", storagePathID, previous,
+		fmt.Sprintf("Updated storage path %d", storagePathID))
+
+	logging.FromContext(ctx).Info("Storage path updated successfully",
 		"storage_path_id", storagePathID,
 		"name", updatedStoragePath.Name)
 
@@ -196,18 +217,20 @@ func (s *Server) handleDeleteStoragePath(ctx context.Context, args map[string]in
 		return nil, fmt.Errorf("storage_path_id must be a positive integer")
 	}
 
-	slog.Debug("Deleting storage path", "storage_path_id", storagePathID)
+	logging.FromContext(ctx).Debug("Deleting storage path", "storage_path_id", storagePathID)
 
 	// Call Paperless API
 	err := s.paperlessClient.DeleteStoragePath(ctx, storagePathID)
 	if err != nil {
-		slog.Error("Failed to delete storage path",
+		logging.FromContext(ctx).Error("Failed to delete storage path",
 			"storage_path_id", storagePathID,
 			"error", err)
 		return nil, fmt.Errorf("failed to delete storage path: %w", err)
 	}
 
-	slog.Info("Storage path deleted successfully", "storage_path_id", storagePathID)
+	s.metadata.storagePaths.invalidate()
+
+	logging.FromContext(ctx).Info("Storage path deleted successfully", "storage_path_id", storagePathID)
 
 	return map[string]interface{}{
 		"success":         true,
@@ -0,0 +1,150 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// Result streaming
+const (
+	// PartialResultMethod is the notification carrying one chunk of a
+	// streamed tool result
+	PartialResultMethod = "notifications/partial_result"
+
+	// DefaultStreamChunkSize is how many items a partial result carries
+	// unless the call asks for another chunk_size
+	DefaultStreamChunkSize = 50
+	MaxStreamChunkSize     = 500
+
+	// StreamSendTimeout bounds how long a partial result waits for room in
+	// the notification queue of a client that is not reading it
+	StreamSendTimeout = 30 * time.Second
+
+	// streamRetryDelay spaces attempts to queue a partial result
+	streamRetryDelay = 10 * time.Millisecond
+)
+
+// ErrStreamUnavailable is returned when a tool call asks to stream its
+// result outside an MCP client session, e.g. from the CLI
+var ErrStreamUnavailable = errors.New("stream needs an MCP client session to send partial results to")
+
+// resultStream sends the items of a large tool result to the client as
+// partial result notifications while they are read, so neither the server
+// nor the client holds the whole result at once. The final tool result
+// then only summarises what was streamed. Over StreamableHTTP the
+// notifications turn the response into an event stream.
+type resultStream struct {
+	server    *Server
+	tool      string
+	token     interface{}
+	chunkSize int
+
+	chunks int
+	items  map[string]int
+}
+
+// newResultStream returns the stream for a call of tool that passed
+// stream: true, or nil when the result should be returned whole
+func (s *Server) newResultStream(ctx context.Context, tool string, args map[string]interface{}) (*resultStream, error) {
+	if stream, _ := args["stream"].(bool); !stream {
+		return nil, nil
+	}
+	if session := server.ClientSessionFromContext(ctx); session == nil || !session.Initialized() {
+		return nil, ErrStreamUnavailable
+	}
+	return &resultStream{
+		server:    s,
+		tool:      tool,
+		token:     ctx.Value(progressTokenKey{}),
+		chunkSize: boundedIntArg(args, "chunk_size", DefaultStreamChunkSize, MaxStreamChunkSize),
+		items:     make(map[string]int),
+	}, nil
+}
+
+// send queues one chunk of items of a kind, waiting while the client's
+// notification queue is full so memory stays bounded by the queue
+func (r *resultStream) send(ctx context.Context, kind string, items interface{}, count int) error {
+	params := map[string]any{
+		"tool":     r.tool,
+		"sequence": r.chunks + 1,
+		"kind":     kind,
+		"items":    items,
+	}
+	if r.token != nil {
+		params["progressToken"] = r.token
+	}
+
+	deadline := time.Now().Add(StreamSendTimeout)
+	for {
+		err := r.server.mcpServer.SendNotificationToClient(ctx, PartialResultMethod, params)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, server.ErrNotificationChannelBlocked) || time.Now().After(deadline) {
+			return fmt.Errorf("failed to send partial result: %w", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(streamRetryDelay):
+		}
+	}
+
+	r.chunks++
+	r.items[kind] += count
+	return nil
+}
+
+// summary describes what was streamed, for the final tool result
+func (r *resultStream) summary() map[string]interface{} {
+	return map[string]interface{}{
+		"notification": PartialResultMethod,
+		"chunks":       r.chunks,
+		"items":        r.items,
+	}
+}
+
+// streamItems sends items of a kind in chunks of the stream's chunk size
+func streamItems[T any](ctx context.Context, r *resultStream, kind string, items []T) error {
+	for start := 0; start < len(items); start += r.chunkSize {
+		chunk := items[start:min(start+r.chunkSize, len(items))]
+		if err := r.send(ctx, kind, chunk, len(chunk)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// streamList pages through a list endpoint like paperless.CollectAll, but
+// sends each page on as it arrives instead of collecting them. It returns
+// how many items were sent.
+func streamList[T any](ctx context.Context, r *resultStream, kind string, list paperless.ListFunc) (int, error) {
+	sent := 0
+	for page := 1; page <= paperless.MaxCollectPages; page++ {
+		response, err := list(ctx, page, paperless.MaxPageSize)
+		if err != nil {
+			return sent, err
+		}
+
+		var items []T
+		if err := json.Unmarshal(response.Results, &items); err != nil {
+			return sent, fmt.Errorf("failed to parse page %d: %w", page, err)
+		}
+		if err := streamItems(ctx, r, kind, items); err != nil {
+			return sent, err
+		}
+		sent += len(items)
+
+		if response.Next == nil || len(items) == 0 {
+			return sent, nil
+		}
+	}
+	return sent, fmt.Errorf("gave up after %d pages", paperless.MaxCollectPages)
+}
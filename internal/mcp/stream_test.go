@@ -0,0 +1,171 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/config"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// streamingSession is a client session with a small notification queue,
+// so a stream has to wait for the client to read
+type streamingSession struct {
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (s *streamingSession) Initialize()       {}
+func (s *streamingSession) Initialized() bool { return true }
+func (s *streamingSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+func (s *streamingSession) SessionID() string { return "stream-session" }
+
+// TestExportMetadataStream tests that a streamed export sends every object
+// in chunks, in order, and returns only the counts
+func TestExportMetadataStream(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		results := []interface{}{}
+		if r.URL.Path == "/api/tags/" {
+			for i := 1; i <= 5; i++ {
+				results = append(results, map[string]interface{}{"id": i, "name": "tag"})
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"count": len(results), "next": nil, "results": results})
+	}))
+	defer ts.Close()
+
+	mcpServer := server.NewMCPServer("test", "1.0")
+	s := &Server{
+		cfg:             &config.Config{PaperlessURL: ts.URL},
+		paperlessClient: paperless.New(ts.URL, "test-token"),
+		mcpServer:       mcpServer,
+	}
+
+	session := &streamingSession{notifications: make(chan mcp.JSONRPCNotification, 1)}
+	var received []mcp.JSONRPCNotification
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for notification := range session.notifications {
+			received = append(received, notification)
+		}
+	}()
+
+	ctx := withProgressToken(mcpServer.WithContext(context.Background(), session), "export-1")
+	result, err := s.handleExportMetadata(ctx, map[string]interface{}{"stream": true, "chunk_size": float64(2)})
+	close(session.notifications)
+	<-done
+	if err != nil {
+		t.Fatalf("export_metadata failed: %v", err)
+	}
+
+	m := result.(map[string]interface{})
+	if counts := m["counts"].(map[string]int); counts["tags"] != 5 || counts["saved_views"] != 0 {
+		t.Errorf("Unexpected counts %v", counts)
+	}
+	if streamed := m["streamed"].(map[string]interface{}); streamed["chunks"] != 3 {
+		t.Errorf("Expected 3 chunks, got %v", streamed)
+	}
+
+	if len(received) != 3 {
+		t.Fatalf("Expected 3 partial results, got %d", len(received))
+	}
+	for i, notification := range received {
+		params := notification.Params.AdditionalFields
+		if notification.Method != PartialResultMethod || params["sequence"] != i+1 || params["kind"] != "tags" ||
+			params["progressToken"] != "export-1" || params["tool"] != "export_metadata" {
+			t.Errorf("Unexpected partial result %d: %s %v", i, notification.Method, params)
+		}
+	}
+	if last := received[2].Params.AdditionalFields["items"].([]paperless.Tag); len(last) != 1 || last[0].ID != 5 {
+		t.Errorf("Expected the last chunk to hold tag 5, got %+v", last)
+	}
+
+	// Streaming needs a session and cannot be combined with a file
+	if _, err := s.handleExportMetadata(context.Background(), map[string]interface{}{"stream": true}); !errors.Is(err, ErrStreamUnavailable) {
+		t.Errorf("Expected ErrStreamUnavailable without a session, got %v", err)
+	}
+	session = &streamingSession{notifications: make(chan mcp.JSONRPCNotification, 1)}
+	_, err = s.handleExportMetadata(mcpServer.WithContext(context.Background(), session),
+		map[string]interface{}{"stream": true, "output_path": "/tmp/snapshot.json"})
+	if err == nil || !strings.Contains(err.Error(), "cannot be combined") {
+		t.Errorf("Expected stream and output_path to be refused, got %v", err)
+	}
+}
+
+// TestVerifyDocumentsStream tests that a streamed verification sends issues
+// once a chunk has accumulated, flushes the remainder with the last
+// document even when that document is healthy, and returns only the totals
+func TestVerifyDocumentsStream(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/documents/":
+			w.Write([]byte(`{"count":5,"next":null,"results":[{"id":1},{"id":2},{"id":3},{"id":4},{"id":5}]}`))
+		case "/api/documents/1/metadata/", "/api/documents/2/metadata/":
+			w.Write([]byte(`{"original_checksum":"abc","has_archive_version":false}`))
+		case "/api/documents/4/metadata/":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.Write([]byte(`{"original_checksum":"abc","has_archive_version":true,"archive_checksum":"def"}`))
+		}
+	}))
+	defer ts.Close()
+
+	mcpServer := server.NewMCPServer("test", "1.0")
+	s := &Server{
+		cfg:             &config.Config{PaperlessURL: ts.URL},
+		paperlessClient: paperless.New(ts.URL, "test-token"),
+		mcpServer:       mcpServer,
+	}
+
+	session := &streamingSession{notifications: make(chan mcp.JSONRPCNotification, 1)}
+	var chunks [][]integrityIssue
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for notification := range session.notifications {
+			if notification.Method == PartialResultMethod {
+				chunks = append(chunks, notification.Params.AdditionalFields["items"].([]integrityIssue))
+			}
+		}
+	}()
+
+	ctx := mcpServer.WithContext(context.Background(), session)
+	result, err := s.handleVerifyDocuments(ctx, map[string]interface{}{
+		"filter":     map[string]interface{}{},
+		"stream":     true,
+		"chunk_size": float64(2),
+	})
+	close(session.notifications)
+	<-done
+	if err != nil {
+		t.Fatalf("verify_documents failed: %v", err)
+	}
+
+	m := result.(map[string]interface{})
+	if m["checked"] != 5 || m["healthy"] != 2 || m["issues"] != nil {
+		t.Errorf("Expected only totals in the result, got %v", m)
+	}
+	if streamed := m["streamed"].(map[string]interface{}); streamed["chunks"] != 2 || streamed["items"].(map[string]int)["issues"] != 3 {
+		t.Errorf("Expected 3 issues in 2 chunks, got %v", streamed)
+	}
+
+	if len(chunks) != 2 {
+		t.Fatalf("Expected 2 partial results, got %d", len(chunks))
+	}
+	if len(chunks[0]) != 2 || chunks[0][0].DocumentID != 1 || chunks[0][1].DocumentID != 2 {
+		t.Errorf("Expected the first chunk to hold documents 1 and 2, got %+v", chunks[0])
+	}
+	if len(chunks[1]) != 1 || chunks[1][0].DocumentID != 4 || chunks[1][0].Problem != integrityMetadataUnavailable {
+		t.Errorf("Expected the last chunk to hold document 4, got %+v", chunks[1])
+	}
+}
@@ -0,0 +1,22 @@
+package mcp
+
+import (
+	"strings"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/textutil"
+)
+
+// closestMatch returns the candidate nearest to name by edit distance,
+// ignoring case, for a "did you mean" hint. It returns "" when no
+// candidate is within MaxPlaceholderTypoDistance; ties go to the earlier
+// candidate.
+func closestMatch(name string, candidates []string) string {
+	name = strings.ToLower(name)
+	best, bestDistance := "", MaxPlaceholderTypoDistance+1
+	for _, candidate := range candidates {
+		if d := textutil.EditDistance(name, strings.ToLower(candidate)); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best
+}
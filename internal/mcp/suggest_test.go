@@ -0,0 +1,25 @@
+package mcp
+
+import "testing"
+
+// TestClosestMatch tests that the nearest candidate is suggested ignoring
+// case, keeping its own spelling, and nothing when all are too far off
+func TestClosestMatch(t *testing.T) {
+	candidates := []string{"Invoice Number", "Due Date", "Amount"}
+	for _, tc := range []struct {
+		name string
+		want string
+	}{
+		{"invoice numbr", "Invoice Number"},
+		{"DUE DATES", "Due Date"},
+		{"amout", "Amount"},
+		{"correspondent", ""},
+	} {
+		if got := closestMatch(tc.name, candidates); got != tc.want {
+			t.Errorf("closestMatch(%q) = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+	if got := closestMatch("title", nil); got != "" {
+		t.Errorf("Expected no suggestion without candidates, got %q", got)
+	}
+}
@@ -0,0 +1,251 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+)
+
+// Summary cache limits
+const (
+	// MaxSummaryLength bounds the length of a stored summary in bytes
+	MaxSummaryLength = 20000
+
+	// MaxSummaries bounds how many summaries are kept; the oldest are
+	// evicted first
+	MaxSummaries = 10000
+)
+
+// documentSummary is a client-generated summary of a document's content
+type documentSummary struct {
+	Summary    string    `json:"summary"`
+	DocumentID int       `json:"document_id"`
+	Generator  string    `json:"generator,omitempty"`
+	StoredAt   time.Time `json:"stored_at"`
+}
+
+// summaryStore keeps document summaries keyed by the checksum of the
+// original file, so a summary outlives sessions and follows the file if
+// it is re-imported. Summaries are kept in memory and, when a path is
+// configured, in a JSON file.
+type summaryStore struct {
+	path string
+
+	mu        sync.Mutex
+	summaries map[string]documentSummary // original checksum -> summary
+}
+
+// newSummaryStore creates a store, loading persisted summaries from path
+// when it is non-empty
+func newSummaryStore(path string) *summaryStore {
+	store := &summaryStore{
+		path:      path,
+		summaries: make(map[string]documentSummary),
+	}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err == nil {
+			if err := json.Unmarshal(data, &store.summaries); err != nil {
+				slog.Warn("Ignoring unreadable summaries file", "path", path, "error", err)
+				store.summaries = make(map[string]documentSummary)
+			}
+		} else if !os.IsNotExist(err) {
+			slog.Warn("Failed to read summaries file", "path", path, "error", err)
+		}
+	}
+
+	return store
+}
+
+// get returns the summary stored for checksum
+func (st *summaryStore) get(checksum string) (documentSummary, bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	summary, ok := st.summaries[checksum]
+	return summary, ok
+}
+
+// put stores summary under checksum, evicting the oldest summary when the
+// store is full
+func (st *summaryStore) put(checksum string, summary documentSummary) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	previous, existed := st.summaries[checksum]
+	var evicted string
+	if !existed && len(st.summaries) >= MaxSummaries {
+		for key, candidate := range st.summaries {
+			if evicted == "" || candidate.StoredAt.Before(st.summaries[evicted].StoredAt) {
+				evicted = key
+			}
+		}
+	}
+	evictedSummary := st.summaries[evicted]
+	if evicted != "" {
+		delete(st.summaries, evicted)
+	}
+
+	st.summaries[checksum] = summary
+	if err := st.saveLocked(); err != nil {
+		if existed {
+			st.summaries[checksum] = previous
+		} else {
+			delete(st.summaries, checksum)
+		}
+		if evicted != "" {
+			st.summaries[evicted] = evictedSummary
+		}
+		return err
+	}
+	return nil
+}
+
+// saveLocked persists summaries when a path is configured; callers must
+// hold st.mu
+func (st *summaryStore) saveLocked() error {
+	if st.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(st.summaries)
+	if err != nil {
+		return fmt.Errorf("failed to encode summaries: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(st.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create summaries directory: %w", err)
+	}
+	if err := os.WriteFile(st.path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write summaries file: %w", err)
+	}
+	return nil
+}
+
+// documentChecksumByID returns the checksum of a document's original file
+func (s *Server) documentChecksumByID(ctx context.Context, documentID int) (string, error) {
+	metadata, err := s.paperlessClient.GetDocumentMetadata(ctx, documentID)
+	if err != nil {
+		return "", err
+	}
+	if metadata.OriginalChecksum == "" {
+		return "", fmt.Errorf("paperless did not report a checksum for document %d", documentID)
+	}
+	return metadata.OriginalChecksum, nil
+}
+
+// handleStoreDocumentSummary handles the store_document_summary tool
+func (s *Server) handleStoreDocumentSummary(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	documentIDFloat, ok := args["document_id"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("document_id parameter is required and must be an integer")
+	}
+	documentID := int(documentIDFloat)
+	if documentID < 1 {
+		return nil, fmt.Errorf("document_id must be a positive integer")
+	}
+
+	summaryText, _ := args["summary"].(string)
+	summaryText = strings.TrimSpace(summaryText)
+	if summaryText == "" {
+		return nil, fmt.Errorf("summary parameter is required and must be a non-empty string")
+	}
+	if len(summaryText) > MaxSummaryLength {
+		return nil, fmt.Errorf("summary must be at most %d bytes", MaxSummaryLength)
+	}
+	generator, _ := args["generator"].(string)
+
+	logging.FromContext(ctx).Debug("Storing document summary",
+		"document_id", documentID,
+		"length", len(summaryText))
+
+	checksum, err := s.documentChecksumByID(ctx, documentID)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to get document checksum",
+			"document_id", documentID,
+			"error", err)
+		return nil, fmt.Errorf("failed to get document checksum: %w", err)
+	}
+
+	summary := documentSummary{
+		Summary:    summaryText,
+		DocumentID: documentID,
+		Generator:  strings.TrimSpace(generator),
+		StoredAt:   time.Now().UTC(),
+	}
+	if err := s.summaries.put(checksum, summary); err != nil {
+		logging.FromContext(ctx).Error("Failed to store document summary",
+			"document_id", documentID,
+			"error", err)
+		return nil, fmt.Errorf("failed to store document summary: %w", err)
+	}
+
+	logging.FromContext(ctx).Info("Document summary stored",
+		"document_id", documentID,
+		"checksum", checksum)
+
+	return map[string]interface{}{
+		"success":     true,
+		"document_id": documentID,
+		"checksum":    checksum,
+		"stored_at":   summary.StoredAt,
+	}, nil
+}
+
+// handleGetDocumentSummary handles the get_document_summary tool
+func (s *Server) handleGetDocumentSummary(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	documentIDFloat, ok := args["document_id"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("document_id parameter is required and must be an integer")
+	}
+	documentID := int(documentIDFloat)
+	if documentID < 1 {
+		return nil, fmt.Errorf("document_id must be a positive integer")
+	}
+
+	logging.FromContext(ctx).Debug("Getting document summary", "document_id", documentID)
+
+	// Looking up the checksum also checks the caller may read the document
+	checksum, err := s.documentChecksumByID(ctx, documentID)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to get document checksum",
+			"document_id", documentID,
+			"error", err)
+		return nil, fmt.Errorf("failed to get document checksum: %w", err)
+	}
+
+	summary, found := s.summaries.get(checksum)
+
+	logging.FromContext(ctx).Info("Document summary looked up",
+		"document_id", documentID,
+		"found", found)
+
+	result := map[string]interface{}{
+		"document_id": documentID,
+		"checksum":    checksum,
+		"found":       found,
+	}
+	if !found {
+		result["hint"] = "No summary is stored; read the content with get_document_content and store one with store_document_summary"
+		return result, nil
+	}
+
+	result["summary"] = summary.Summary
+	result["stored_at"] = summary.StoredAt
+	if summary.Generator != "" {
+		result["generator"] = summary.Generator
+	}
+
+	// The content may have been edited or re-OCRed since, without the
+	// original file changing
+	if document, err := s.getDocument(ctx, documentID); err == nil {
+		result["stale"] = document.Modified.After(summary.StoredAt)
+	}
+	return result, nil
+}
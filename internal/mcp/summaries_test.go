@@ -0,0 +1,33 @@
+package mcp
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestSummaryStore tests replacing summaries and persistence across
+// restarts
+func TestSummaryStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "summaries.json")
+	store := newSummaryStore(path)
+
+	stored := time.Now().UTC().Truncate(time.Second)
+	if err := store.put("abc", documentSummary{Summary: "first", DocumentID: 1, StoredAt: stored}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := store.put("abc", documentSummary{Summary: "second", DocumentID: 2, StoredAt: stored}); err != nil {
+		t.Fatalf("Unexpected error replacing: %v", err)
+	}
+	if _, found := store.get("def"); found {
+		t.Error("Expected no summary for an unknown checksum")
+	}
+
+	summary, found := newSummaryStore(path).get("abc")
+	if !found {
+		t.Fatal("Expected the summary to be persisted")
+	}
+	if summary.Summary != "second" || summary.DocumentID != 2 || !summary.StoredAt.Equal(stored) {
+		t.Errorf("Unexpected reloaded summary: %+v", summary)
+	}
+}
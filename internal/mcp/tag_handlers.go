@@ -4,46 +4,44 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log/slog"
 
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
 	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
 )
 
 // handleListTags handles the list_tags tool
 func (s *Server) handleListTags(ctx context.Context, args map[string]interface{}) (interface{}, error) {
-	// Extract pagination parameters
-	page := 1
-	pageSize := paperless.DefaultPageSize
-
-	if p, ok := args["page"].(float64); ok {
+	// Extract pagination parameters, bounded as the client bounds them so
+	// the page fields of the result are accurate
+	page := DefaultPage
+	if p, ok := args["page"].(float64); ok && p >= 1 {
 		page = int(p)
 	}
-	if ps, ok := args["page_size"].(float64); ok {
-		pageSize = int(ps)
+	pageSize := boundedIntArg(args, "page_size", DefaultPageSize, MaxPageSize)
+
+	// Optional case-insensitive name filter and ordering
+	opts, err := parseListOptions(args, metadataOrderings)
+	if err != nil {
+		return nil, err
 	}
 
-	slog.Debug("List tags tool invoked", "page", page, "page_size", pageSize)
+	logging.FromContext(ctx).Debug("List tags tool invoked", "name", opts.Name, "ordering", opts.Ordering, "page", page, "page_size", pageSize)
 
 	// Call API
-	response, err := s.paperlessClient.ListTags(ctx, page, pageSize)
+	response, err := s.paperlessClient.ListTagsMatching(ctx, opts, page, pageSize)
 	if err != nil {
-		slog.Error("Failed to list tags", "error", err)
+		logging.FromContext(ctx).Error("Failed to list tags", "error", err)
 		return nil, fmt.Errorf("failed to list tags: %w", err)
 	}
 
 	// Parse results as tags
 	var tags []paperless.Tag
 	if err := json.Unmarshal(response.Results, &tags); err != nil {
-		slog.Error("Failed to parse tags from response", "error", err)
+		logging.FromContext(ctx).Error("Failed to parse tags from response", "error", err)
 		return nil, fmt.Errorf("failed to parse tags: %w", err)
 	}
 
-	return map[string]interface{}{
-		"count":    response.Count,
-		"next":     response.Next,
-		"previous": response.Previous,
-		"tags":     tags,
-	}, nil
+	return newListResult(tags, response.Count, page, pageSize, listOptionFilters(opts)), nil
 }
 
 // handleGetTag handles the get_tag tool
@@ -54,12 +52,12 @@ func (s *Server) handleGetTag(ctx context.Context, args map[string]interface{})
 		return nil, fmt.Errorf("tag_id is required and must be an integer")
 	}
 
-	slog.Debug("Get tag tool invoked", "tag_id", int(tagID))
+	logging.FromContext(ctx).Debug("Get tag tool invoked", "tag_id", int(tagID))
 
 	// Call API
 	tag, err := s.paperlessClient.GetTag(ctx, int(tagID))
 	if err != nil {
-		slog.Error("Failed to get tag", "tag_id", int(tagID), "error", err)
+		logging.FromContext(ctx).Error("Failed to get tag", "tag_id", int(tagID), "error", err)
 		return nil, fmt.Errorf("failed to get tag: %w", err)
 	}
 
@@ -79,7 +77,7 @@ func (s *Server) handleCreateTag(ctx context.Context, args map[string]interface{
 		return nil, fmt.Errorf("color is required and must be a non-empty string")
 	}
 
-	slog.Debug("Create tag tool invoked", "name", name, "color", color)
+	logging.FromContext(ctx).Debug("Create tag tool invoked", "name", name, "color", color)
 
 	// Build tag object
 	tag := &paperless.Tag{
@@ -104,10 +102,15 @@ func (s *Server) handleCreateTag(ctx context.Context, args map[string]interface{
 	// Call API
 	createdTag, err := s.paperlessClient.CreateTag(ctx, tag)
 	if err != nil {
-		slog.Error("Failed to create tag", "name", name, "error", err)
+		logging.FromContext(ctx).Error("Failed to create tag", "name", name, "error", err)
 		return nil, fmt.Errorf("failed to create tag: %w", err)
 	}
 
+	s.metadata.tags.invalidate()
+
+	s.journal.recordCreate(ctx, "create_tag", "tags", createdTag.ID,
+		fmt.Sprintf("Created tag %q", createdTag.Name))
+
 	return createdTag, nil
 }
 
@@ -119,7 +122,7 @@ func (s *Server) handleUpdateTag(ctx context.Context, args map[string]interface{
 		return nil, fmt.Errorf("tag_id is required and must be an integer")
 	}
 
-	slog.Debug("Update tag tool invoked", "tag_id", int(tagID))
+	logging.FromContext(ctx).Debug("Update tag tool invoked", "tag_id", int(tagID))
 
 	// Build updates map
 	updates := make(map[string]interface{})
@@ -147,14 +150,26 @@ func (s *Server) handleUpdateTag(ctx context.Context, args map[string]interface{
 		return nil, fmt.Errorf("at least one field must be provided for update")
 	}
 
+	rename, err := s.checkRename(ctx, "tags", int(tagID), updates)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := s.snapshotFields(ctx, "tags", int(tagID), updates)
+
 	// Call API
 	updatedTag, err := s.paperlessClient.UpdateTag(ctx, int(tagID), updates)
 	if err != nil {
-		slog.Error("Failed to update tag", "tag_id", int(tagID), "error", err)
+		logging.FromContext(ctx).Error("Failed to update tag", "tag_id", int(tagID), "error", err)
 		return nil, fmt.Errorf("failed to update tag: %w", err)
 	}
 
-	return updatedTag, nil
+	s.metadata.tags.invalidate()
+
+	s.journal.recordUpdate(ctx, "update_tag", "tags", int(tagID), previous,
+		fmt.Sprintf("Updated tag %d", int(tagID)))
+
+	return withRenameReport(ctx, updatedTag, updatedTag.Slug, rename)
 }
 
 // handleDeleteTag handles the delete_tag tool
@@ -165,15 +180,17 @@ func (s *Server) handleDeleteTag(ctx context.Context, args map[string]interface{
 		return nil, fmt.Errorf("tag_id is required and must be an integer")
 	}
 
-	slog.Debug("Delete tag tool invoked", "tag_id", int(tagID))
+	logging.FromContext(ctx).Debug("Delete tag tool invoked", "tag_id", int(tagID))
 
 	// Call API
 	err := s.paperlessClient.DeleteTag(ctx, int(tagID))
 	if err != nil {
-		slog.Error("Failed to delete tag", "tag_id", int(tagID), "error", err)
+		logging.FromContext(ctx).Error("Failed to delete tag", "tag_id", int(tagID), "error", err)
 		return nil, fmt.Errorf("failed to delete tag: %w", err)
 	}
 
+	s.metadata.tags.invalidate()
+
 	return map[string]interface{}{
 		"success": true,
 		"message": fmt.Sprintf("Tag %d deleted successfully", int(tagID)),
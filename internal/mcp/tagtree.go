@@ -0,0 +1,239 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// tagNode is one level of the tag hierarchy. Groups implied by a path but
+// without a tag of their own have no ID.
+type tagNode struct {
+	Name          string     `json:"name"`
+	Path          string     `json:"path"`
+	ID            *int       `json:"id,omitempty"`
+	DocumentCount int        `json:"document_count,omitempty"`
+	Children      []*tagNode `json:"children,omitempty"`
+}
+
+// splitTagPath splits a tag name into its trimmed, non-empty segments
+func splitTagPath(name, delimiter string) []string {
+	parts := strings.Split(name, delimiter)
+	segments := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			segments = append(segments, part)
+		}
+	}
+	return segments
+}
+
+// buildTagTree arranges tags into a hierarchy by splitting their names on
+// delimiter. Children are sorted by name.
+func buildTagTree(tags []paperless.Tag, delimiter string) []*tagNode {
+	root := &tagNode{}
+	index := make(map[string]*tagNode)
+
+	for i := range tags {
+		segments := splitTagPath(tags[i].Name, delimiter)
+		if len(segments) == 0 {
+			continue
+		}
+		parent := root
+		for depth, segment := range segments {
+			path := strings.Join(segments[:depth+1], delimiter)
+			node, ok := index[path]
+			if !ok {
+				node = &tagNode{Name: segment, Path: path}
+				index[path] = node
+				parent.Children = append(parent.Children, node)
+			}
+			parent = node
+		}
+		id := tags[i].ID
+		parent.ID = &id
+		parent.DocumentCount = tags[i].DocumentCount
+	}
+
+	sortTagNodes(root.Children)
+	return root.Children
+}
+
+// sortTagNodes sorts nodes and their descendants by name
+func sortTagNodes(nodes []*tagNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return strings.ToLower(nodes[i].Name) < strings.ToLower(nodes[j].Name)
+	})
+	for _, node := range nodes {
+		sortTagNodes(node.Children)
+	}
+}
+
+// pruneTagTree drops the levels of nodes below maxDepth
+func pruneTagTree(nodes []*tagNode, maxDepth int) {
+	for _, node := range nodes {
+		if maxDepth <= 1 {
+			node.Children = nil
+			continue
+		}
+		pruneTagTree(node.Children, maxDepth-1)
+	}
+}
+
+// matchTagPath reports whether a partial path matches the end of a tag's
+// path, comparing segments case-insensitively. The last query segment may
+// be a prefix, so "taxes/20" matches "Finance/Taxes/2024".
+func matchTagPath(tagSegments, querySegments []string) bool {
+	if len(querySegments) == 0 || len(querySegments) > len(tagSegments) {
+		return false
+	}
+	offset := len(tagSegments) - len(querySegments)
+	last := len(querySegments) - 1
+	for i, query := range querySegments {
+		segment := strings.ToLower(tagSegments[offset+i])
+		query = strings.ToLower(query)
+		if i == last {
+			if !strings.HasPrefix(segment, query) {
+				return false
+			}
+		} else if segment != query {
+			return false
+		}
+	}
+	return true
+}
+
+// tagPathMatch is a tag found by a partial path, with how closely it matched
+type tagPathMatch struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Exact bool   `json:"exact"`
+}
+
+// resolveTagPath finds the tags whose paths end with the partial path
+// query. Exact matches of the full path or of the trailing segments come
+// first.
+func resolveTagPath(tags []paperless.Tag, delimiter, query string) []tagPathMatch {
+	querySegments := splitTagPath(query, delimiter)
+	var matches []tagPathMatch
+	for _, tag := range tags {
+		segments := splitTagPath(tag.Name, delimiter)
+		if !matchTagPath(segments, querySegments) {
+			continue
+		}
+		exact := strings.EqualFold(segments[len(segments)-1], querySegments[len(querySegments)-1])
+		matches = append(matches, tagPathMatch{ID: tag.ID, Name: tag.Name, Exact: exact})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Exact != matches[j].Exact {
+			return matches[i].Exact
+		}
+		return matches[i].Name < matches[j].Name
+	})
+	return matches
+}
+
+// findTagNodes returns the nodes of the tree whose paths match query
+func findTagNodes(nodes []*tagNode, delimiter string, querySegments []string) []*tagNode {
+	var found []*tagNode
+	for _, node := range nodes {
+		if matchTagPath(splitTagPath(node.Path, delimiter), querySegments) {
+			found = append(found, node)
+			continue
+		}
+		found = append(found, findTagNodes(node.Children, delimiter, querySegments)...)
+	}
+	return found
+}
+
+// handleListTagTree handles the list_tag_tree tool
+func (s *Server) handleListTagTree(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	path, _ := args["path"].(string)
+	maxDepth := 0
+	if depth, ok := args["max_depth"].(float64); ok {
+		maxDepth = int(depth)
+		if maxDepth < 1 {
+			return nil, fmt.Errorf("max_depth must be a positive integer")
+		}
+	}
+	delimiter := s.cfg.MCPTagDelimiter
+
+	logging.FromContext(ctx).Debug("List tag tree tool invoked", "path", path, "max_depth", maxDepth)
+
+	tags, err := s.metadata.tags.get(ctx, s.paperlessClient)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to list tags", "error", err)
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	tree := buildTagTree(tags, delimiter)
+	if path != "" {
+		querySegments := splitTagPath(path, delimiter)
+		if len(querySegments) == 0 {
+			return nil, fmt.Errorf("path must contain a tag name")
+		}
+		tree = findTagNodes(tree, delimiter, querySegments)
+	}
+	if maxDepth > 0 {
+		pruneTagTree(tree, maxDepth)
+	}
+
+	logging.FromContext(ctx).Info("Tag tree listed",
+		"tags", len(tags),
+		"roots", len(tree))
+
+	return map[string]interface{}{
+		"delimiter": delimiter,
+		"tag_count": len(tags),
+		"tree":      tree,
+	}, nil
+}
+
+// handleResolveTagPath handles the resolve_tag_path tool
+func (s *Server) handleResolveTagPath(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	path, ok := args["path"].(string)
+	if !ok || strings.TrimSpace(path) == "" {
+		return nil, fmt.Errorf("path is required and must be a non-empty string")
+	}
+	delimiter := s.cfg.MCPTagDelimiter
+	if len(splitTagPath(path, delimiter)) == 0 {
+		return nil, fmt.Errorf("path must contain a tag name")
+	}
+
+	logging.FromContext(ctx).Debug("Resolve tag path tool invoked", "path", path)
+
+	tags, err := s.metadata.tags.get(ctx, s.paperlessClient)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to list tags", "error", err)
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	matches := resolveTagPath(tags, delimiter, path)
+	exact := 0
+	for _, match := range matches {
+		if match.Exact {
+			exact++
+		}
+	}
+
+	logging.FromContext(ctx).Info("Tag path resolved",
+		"matches", len(matches),
+		"exact", exact)
+
+	// A single exact match wins over prefix matches, which sort after it
+	resolved := exact == 1 || len(matches) == 1
+	result := map[string]interface{}{
+		"path":      path,
+		"matches":   matches,
+		"ambiguous": !resolved && len(matches) > 0,
+	}
+	if resolved {
+		result["tag_id"] = matches[0].ID
+	}
+	return result, nil
+}
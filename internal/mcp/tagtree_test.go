@@ -0,0 +1,69 @@
+package mcp
+
+import (
+	"reflect"
+	"testing"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+var hierarchicalTags = []paperless.Tag{
+	{ID: 1, Name: "Finance/Taxes/2024"},
+	{ID: 2, Name: "Finance/Taxes/2023"},
+	{ID: 3, Name: "Personal / Taxes / 2024"},
+	{ID: 4, Name: "Finance"},
+	{ID: 5, Name: "Inbox"},
+}
+
+// TestBuildTagTree tests that implied groups are created and tags are
+// attached at the end of their path
+func TestBuildTagTree(t *testing.T) {
+	tree := buildTagTree(hierarchicalTags, "/")
+
+	var roots []string
+	for _, node := range tree {
+		roots = append(roots, node.Name)
+	}
+	if want := []string{"Finance", "Inbox", "Personal"}; !reflect.DeepEqual(roots, want) {
+		t.Fatalf("Expected roots %v, got %v", want, roots)
+	}
+	if tree[0].ID == nil || *tree[0].ID != 4 {
+		t.Errorf("Expected Finance to carry its own tag, got %v", tree[0].ID)
+	}
+	if tree[2].ID != nil {
+		t.Errorf("Expected Personal to be an implied group, got %v", *tree[2].ID)
+	}
+	years := tree[0].Children[0].Children
+	if len(years) != 2 || years[0].Name != "2023" || years[1].Path != "Finance/Taxes/2024" {
+		t.Errorf("Unexpected leaves: %+v, %+v", years[0], years[1])
+	}
+
+	pruneTagTree(tree, 1)
+	if tree[0].Children != nil {
+		t.Errorf("Expected max depth 1 to drop children")
+	}
+}
+
+// TestResolveTagPath tests partial path matching and ambiguity
+func TestResolveTagPath(t *testing.T) {
+	ids := func(matches []tagPathMatch) []int {
+		var out []int
+		for _, match := range matches {
+			out = append(out, match.ID)
+		}
+		return out
+	}
+
+	if got := ids(resolveTagPath(hierarchicalTags, "/", "finance/taxes/2024")); !reflect.DeepEqual(got, []int{1}) {
+		t.Errorf("Expected a full path to match one tag, got %v", got)
+	}
+	if got := ids(resolveTagPath(hierarchicalTags, "/", "Taxes/2024")); !reflect.DeepEqual(got, []int{1, 3}) {
+		t.Errorf("Expected a partial path to match both branches, got %v", got)
+	}
+	if got := ids(resolveTagPath(hierarchicalTags, "/", "taxes/202")); len(got) != 3 {
+		t.Errorf("Expected the last level to match as a prefix, got %v", got)
+	}
+	if got := ids(resolveTagPath(hierarchicalTags, "/", "Finance/2024")); got != nil {
+		t.Errorf("Expected skipped levels not to match, got %v", got)
+	}
+}
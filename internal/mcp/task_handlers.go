@@ -0,0 +1,224 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// Limits of the diagnose_failed_task tool
+const (
+	MaxDiagnosedTasks  = 20
+	MaxTaskResultChars = 2000
+)
+
+// Failure categories reported by diagnose_failed_task
+const (
+	taskFailureDuplicate         = "duplicate"
+	taskFailurePasswordProtected = "password_protected"
+	taskFailureUnsupportedType   = "unsupported_file_type"
+	taskFailureTimeout           = "timeout"
+	taskFailureOCR               = "ocr_error"
+	taskFailureMissingFile       = "missing_file"
+	taskFailureUnknown           = "unknown"
+)
+
+// taskFailurePattern recognizes one kind of consumption failure from the
+// result Paperless stored for the task
+type taskFailurePattern struct {
+	category    string
+	markers     []string // lower case substrings of the result
+	explanation string
+	remediation string
+}
+
+// taskFailurePatterns are checked in order; the first match wins, so more
+// specific failures come before the OCR errors they are often wrapped in
+var taskFailurePatterns = []taskFailurePattern{
+	{
+		category:    taskFailureDuplicate,
+		markers:     []string{"duplicate"},
+		explanation: "Paperless already has a document with the same file checksum, so the upload was not consumed",
+		remediation: "Use the existing document instead; if the upload was meant to replace it, delete the existing document (and empty it from the trash) before uploading again",
+	},
+	{
+		category:    taskFailurePasswordProtected,
+		markers:     []string{"password", "encrypted"},
+		explanation: "The PDF is password protected, so Paperless could not read or OCR it",
+		remediation: "Upload the file again with upload_document, passing its password as pdf_password to decrypt it before upload",
+	},
+	{
+		category:    taskFailureUnsupportedType,
+		markers:     []string{"unsupported mime type", "unsupported file type"},
+		explanation: "Paperless has no parser for this kind of file",
+		remediation: "Convert the file to PDF or an image format before uploading, or enable Tika for office documents and e-mails",
+	},
+	{
+		category:    taskFailureTimeout,
+		markers:     []string{"timelimitexceeded", "time limit"},
+		explanation: "Consuming the file took longer than the Paperless worker allows, which is common for large scans",
+		remediation: "Raise PAPERLESS_WORKER_TIMEOUT on the Paperless server, or split the file into smaller documents",
+	},
+	{
+		category:    taskFailureOCR,
+		markers:     []string{"ocrmypdf", "tesseract", "ghostscript", "priorocrfounderror", "digitalsignatureerror", "ocr"},
+		explanation: "OCR of the file failed",
+		remediation: "Check that the OCR languages in PAPERLESS_OCR_LANGUAGE are installed; files that already contain text or are digitally signed may need PAPERLESS_OCR_MODE=skip. Retrying with a rescanned or re-exported PDF often helps with damaged files",
+	},
+	{
+		category:    taskFailureMissingFile,
+		markers:     []string{"no such file", "does not exist", "file not found"},
+		explanation: "The uploaded file disappeared from the consumption directory before Paperless could read it",
+		remediation: "Upload the file again; if this keeps happening, check that nothing else removes files from the Paperless consume directory",
+	},
+}
+
+// duplicateOfPattern extracts the ID of the document an upload duplicates
+// from Paperless' "It is a duplicate of <title> (#<id>)" message
+var duplicateOfPattern = regexp.MustCompile(`duplicate of .*\(#(\d+)\)`)
+
+// taskDiagnosis explains the state of one task
+type taskDiagnosis struct {
+	TaskID      string `json:"task_id"`
+	Filename    string `json:"filename,omitempty"`
+	Status      string `json:"status"`
+	DateDone    string `json:"date_done,omitempty"`
+	Category    string `json:"category,omitempty"`
+	Explanation string `json:"explanation"`
+	Remediation string `json:"remediation,omitempty"`
+	DuplicateOf int    `json:"duplicate_of,omitempty"`
+	DocumentID  int    `json:"document_id,omitempty"`
+	Result      string `json:"result,omitempty"`
+}
+
+// tailText returns the last chars characters of text, where tracebacks
+// keep the exception that ended them
+func tailText(text string, chars int) string {
+	runes := []rune(strings.TrimSpace(text))
+	if len(runes) <= chars {
+		return string(runes)
+	}
+	return "…" + strings.TrimSpace(string(runes[len(runes)-chars+1:]))
+}
+
+// diagnoseTask classifies a task's outcome and suggests what to do about
+// a failure
+func diagnoseTask(task paperless.Task) taskDiagnosis {
+	diagnosis := taskDiagnosis{TaskID: task.TaskID, Status: task.Status}
+	if task.TaskFileName != nil {
+		diagnosis.Filename = *task.TaskFileName
+	}
+	if !task.DateDone.IsZero() {
+		diagnosis.DateDone = task.DateDone.Format(paperless.DateOnlyFormat)
+	}
+	result := ""
+	if task.Result != nil {
+		result = *task.Result
+	}
+
+	switch task.Status {
+	case paperless.TaskStatusSuccess:
+		diagnosis.Explanation = "The task succeeded"
+		if task.RelatedDocument != nil {
+			diagnosis.DocumentID, _ = strconv.Atoi(*task.RelatedDocument)
+		}
+		return diagnosis
+	case paperless.TaskStatusFailure:
+	default:
+		diagnosis.Explanation = fmt.Sprintf("The task has not finished (%s); check again shortly", strings.ToLower(task.Status))
+		return diagnosis
+	}
+
+	diagnosis.Result = tailText(result, MaxTaskResultChars)
+	lower := strings.ToLower(result)
+	for _, pattern := range taskFailurePatterns {
+		for _, marker := range pattern.markers {
+			if strings.Contains(lower, marker) {
+				diagnosis.Category = pattern.category
+				diagnosis.Explanation = pattern.explanation
+				diagnosis.Remediation = pattern.remediation
+				break
+			}
+		}
+		if diagnosis.Category != "" {
+			break
+		}
+	}
+	if diagnosis.Category == "" {
+		diagnosis.Category = taskFailureUnknown
+		diagnosis.Explanation = "The failure is not one of the common consumption problems; see result for the message Paperless recorded"
+		diagnosis.Remediation = "Check the Paperless logs around date_done for the full error, and upload the file again once the cause is fixed"
+	}
+	if match := duplicateOfPattern.FindStringSubmatch(result); match != nil {
+		diagnosis.DuplicateOf, _ = strconv.Atoi(match[1])
+		diagnosis.Remediation = fmt.Sprintf("The existing document is %d; use get_document to inspect it. ", diagnosis.DuplicateOf) + diagnosis.Remediation
+	}
+	return diagnosis
+}
+
+// handleDiagnoseFailedTask handles the diagnose_failed_task tool
+func (s *Server) handleDiagnoseFailedTask(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	taskID, _ := args["task_id"].(string)
+	taskID = strings.TrimSpace(taskID)
+
+	// A single task, typically the one returned by upload_document
+	if taskID != "" {
+		logging.FromContext(ctx).Debug("Diagnosing task", "task_id", taskID)
+
+		task, err := s.paperlessClient.GetTask(ctx, taskID)
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to get task",
+				"task_id", taskID,
+				"error", err)
+			return nil, fmt.Errorf("failed to get task: %w", err)
+		}
+		diagnosis := diagnoseTask(*task)
+
+		logging.FromContext(ctx).Info("Task diagnosed",
+			"task_id", taskID,
+			"status", diagnosis.Status,
+			"category", diagnosis.Category)
+
+		return diagnosis, nil
+	}
+
+	// Otherwise the recent consumption failures not yet dismissed
+	logging.FromContext(ctx).Debug("Diagnosing failed consumption tasks")
+
+	tasks, err := s.paperlessClient.ListTasks(ctx, paperless.TaskFilter{
+		Status:         paperless.TaskStatusFailure,
+		TaskName:       paperless.TaskNameConsumeFile,
+		Unacknowledged: true,
+	})
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to list tasks", "error", err)
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
+
+	diagnoses := make([]taskDiagnosis, 0, min(len(tasks), MaxDiagnosedTasks))
+	categories := make(map[string]int)
+	for _, task := range tasks {
+		if len(diagnoses) == MaxDiagnosedTasks {
+			break
+		}
+		diagnosis := diagnoseTask(task)
+		categories[diagnosis.Category]++
+		diagnoses = append(diagnoses, diagnosis)
+	}
+
+	logging.FromContext(ctx).Info("Failed tasks diagnosed",
+		"failed", len(tasks),
+		"diagnosed", len(diagnoses))
+
+	return map[string]interface{}{
+		"failed":     len(tasks),
+		"categories": categories,
+		"tasks":      diagnoses,
+		"truncated":  len(tasks) > len(diagnoses),
+	}, nil
+}
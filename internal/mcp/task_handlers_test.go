@@ -0,0 +1,91 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// TestDiagnoseTask tests that common consumption failures are classified
+// from the result Paperless recorded
+func TestDiagnoseTask(t *testing.T) {
+	for _, tc := range []struct {
+		result   string
+		category string
+	}{
+		{"Not consuming invoice.pdf: It is a duplicate of Invoice 2024-03 (#412).", taskFailureDuplicate},
+		{"Error occurred while consuming document scan.pdf: EncryptedPdfError: Input PDF is encrypted", taskFailurePasswordProtected},
+		{"Unsupported mime type application/x-msdownload", taskFailureUnsupportedType},
+		{"TimeLimitExceeded(1800)", taskFailureTimeout},
+		{"Traceback (most recent call last):\n...\nocrmypdf.exceptions.SubprocessOutputError: tesseract failed", taskFailureOCR},
+		{"Something else went wrong", taskFailureUnknown},
+	} {
+		result := tc.result
+		diagnosis := diagnoseTask(paperless.Task{TaskID: "abc", Status: paperless.TaskStatusFailure, Result: &result})
+		if diagnosis.Category != tc.category || diagnosis.Remediation == "" {
+			t.Errorf("%q: expected %s with a remediation, got %+v", tc.result, tc.category, diagnosis)
+		}
+	}
+
+	result := "Not consuming invoice.pdf: It is a duplicate of Invoice 2024-03 (#412)."
+	if diagnosis := diagnoseTask(paperless.Task{Status: paperless.TaskStatusFailure, Result: &result}); diagnosis.DuplicateOf != 412 {
+		t.Errorf("Expected the duplicated document to be named, got %+v", diagnosis)
+	}
+
+	related := "57"
+	if diagnosis := diagnoseTask(paperless.Task{Status: paperless.TaskStatusSuccess, RelatedDocument: &related}); diagnosis.DocumentID != 57 || diagnosis.Category != "" {
+		t.Errorf("Expected a succeeded task to name its document, got %+v", diagnosis)
+	}
+}
+
+// TestDiagnoseFailedTask tests looking up a task by its UUID and listing
+// the recent failed consumption tasks
+func TestDiagnoseFailedTask(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch {
+		case r.URL.Path != "/api/tasks/":
+			http.NotFound(w, r)
+		case query.Get("task_id") == "11111111-2222":
+			w.Write([]byte(`[{"id": 3, "task_id": "11111111-2222", "task_file_name": "scan.pdf", "status": "FAILURE", "result": "PDF file is password protected", "date_done": "2026-10-01T09:30:00Z"}]`))
+		case query.Get("task_id") != "":
+			w.Write([]byte(`[]`))
+		case query.Get("status") == "FAILURE" && query.Get("task_name") == "consume_file" && query.Get("acknowledged") == "false":
+			w.Write([]byte(`[
+				{"id": 3, "task_id": "11111111-2222", "status": "FAILURE", "result": "PDF file is password protected"},
+				{"id": 2, "task_id": "33333333-4444", "status": "FAILURE", "result": "It is a duplicate of Receipt (#9)"}
+			]`))
+		default:
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+	}))
+	defer ts.Close()
+
+	s := &Server{paperlessClient: paperless.New(ts.URL, "test-token")}
+
+	result, err := s.handleDiagnoseFailedTask(context.Background(), map[string]interface{}{"task_id": "11111111-2222"})
+	if err != nil {
+		t.Fatalf("diagnose_failed_task failed: %v", err)
+	}
+	diagnosis := result.(taskDiagnosis)
+	if diagnosis.Category != taskFailurePasswordProtected || diagnosis.Filename != "scan.pdf" || diagnosis.DateDone != "2026-10-01" {
+		t.Errorf("Unexpected diagnosis: %+v", diagnosis)
+	}
+
+	if _, err := s.handleDiagnoseFailedTask(context.Background(), map[string]interface{}{"task_id": "unknown"}); !paperless.IsNotFound(err) {
+		t.Errorf("Expected an unknown task to be not found, got %v", err)
+	}
+
+	result, err = s.handleDiagnoseFailedTask(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("diagnose_failed_task failed: %v", err)
+	}
+	summary := result.(map[string]interface{})
+	categories := summary["categories"].(map[string]int)
+	if summary["failed"] != 2 || categories[taskFailurePasswordProtected] != 1 || categories[taskFailureDuplicate] != 1 {
+		t.Errorf("Unexpected summary: %v", summary)
+	}
+}
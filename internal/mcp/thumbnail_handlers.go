@@ -0,0 +1,151 @@
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sync"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// MaxThumbnails bounds how many thumbnails get_thumbnails returns at once
+const MaxThumbnails = 20
+
+// thumbnailInfo describes one thumbnail in the structured result
+type thumbnailInfo struct {
+	DocumentID int    `json:"document_id"`
+	Title      string `json:"title,omitempty"`
+	MimeType   string `json:"mime_type,omitempty"`
+	Size       int    `json:"size,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// handleGetDocumentThumbnail handles the get_document_thumbnail tool
+func (s *Server) handleGetDocumentThumbnail(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	// Extract and validate document_id
+	documentIDFloat, ok := args["document_id"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("document_id parameter is required and must be an integer")
+	}
+	documentID := int(documentIDFloat)
+	if documentID < 1 {
+		return nil, fmt.Errorf("document_id must be a positive integer")
+	}
+
+	logging.FromContext(ctx).Debug("Getting document thumbnail", "document_id", documentID)
+
+	file, err := s.paperlessClient.GetDocumentThumbnail(ctx, documentID)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to get thumbnail",
+			"document_id", documentID,
+			"error", err)
+		return nil, fmt.Errorf("failed to get thumbnail: %w", err)
+	}
+
+	logging.FromContext(ctx).Info("Document thumbnail retrieved",
+		"document_id", documentID,
+		"size", len(file.Content))
+
+	// The image block is what vision-capable clients look at; the
+	// structured result only describes it
+	return withContent(thumbnailInfo{
+		DocumentID: documentID,
+		MimeType:   file.ContentType,
+		Size:       len(file.Content),
+	}, mcp.NewImageContent(base64.StdEncoding.EncodeToString(file.Content), file.ContentType)), nil
+}
+
+// handleGetThumbnails handles the get_thumbnails tool
+func (s *Server) handleGetThumbnails(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	// Extract and validate document_ids
+	docIDsInterface, ok := args["document_ids"].([]interface{})
+	if !ok || len(docIDsInterface) == 0 {
+		return nil, fmt.Errorf("document_ids parameter is required and must be a non-empty array")
+	}
+	if len(docIDsInterface) > MaxThumbnails {
+		return nil, fmt.Errorf("at most %d thumbnails can be fetched at once", MaxThumbnails)
+	}
+
+	documentIDs := make([]int, 0, len(docIDsInterface))
+	seen := make(map[int]bool, len(docIDsInterface))
+	for _, idInterface := range docIDsInterface {
+		idFloat, ok := idInterface.(float64)
+		if !ok || idFloat < 1 {
+			return nil, fmt.Errorf("document_ids must contain only positive integers")
+		}
+		if id := int(idFloat); !seen[id] {
+			seen[id] = true
+			documentIDs = append(documentIDs, id)
+		}
+	}
+
+	logging.FromContext(ctx).Debug("Getting thumbnails", "document_count", len(documentIDs))
+
+	// Titles label each image so the batch can be checked at a glance
+	titles := make(map[int]string, len(documentIDs))
+	if documents, err := s.listDocumentsByID(ctx, documentIDs, "id,title"); err != nil {
+		logging.FromContext(ctx).Warn("Failed to look up document titles", "error", err)
+	} else {
+		for _, document := range documents {
+			titles[document.ID] = document.Title
+		}
+	}
+
+	files := make([]*paperless.DocumentFile, len(documentIDs))
+	errs := make([]error, len(documentIDs))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, enrichConcurrency)
+	for i, id := range documentIDs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i, id int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			files[i], errs[i] = s.paperlessClient.GetDocumentThumbnail(ctx, id)
+		}(i, id)
+	}
+	wg.Wait()
+
+	thumbnails := make([]thumbnailInfo, 0, len(documentIDs))
+	content := make([]mcp.Content, 0, 2*len(documentIDs))
+	failed := 0
+	for i, id := range documentIDs {
+		info := thumbnailInfo{DocumentID: id, Title: titles[id]}
+		if errs[i] != nil {
+			logging.FromContext(ctx).Warn("Failed to get thumbnail",
+				"document_id", id,
+				"error", errs[i])
+			info.Error = errs[i].Error()
+			thumbnails = append(thumbnails, info)
+			failed++
+			continue
+		}
+		info.MimeType = files[i].ContentType
+		info.Size = len(files[i].Content)
+		thumbnails = append(thumbnails, info)
+
+		label := fmt.Sprintf("Document %d", id)
+		if info.Title != "" {
+			label += ": " + info.Title
+		}
+		content = append(content,
+			mcp.NewTextContent(label),
+			mcp.NewImageContent(base64.StdEncoding.EncodeToString(files[i].Content), files[i].ContentType))
+	}
+
+	if failed == len(documentIDs) {
+		return nil, fmt.Errorf("failed to get thumbnails: %w", errs[0])
+	}
+
+	logging.FromContext(ctx).Info("Thumbnails retrieved",
+		"requested", len(documentIDs),
+		"failed", failed)
+
+	return withContent(map[string]interface{}{
+		"count":      len(documentIDs) - failed,
+		"thumbnails": thumbnails,
+	}, content...), nil
+}
@@ -0,0 +1,57 @@
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TestGetDocumentThumbnail tests that the thumbnail is returned as an image
+// content block described by the structured result
+func TestGetDocumentThumbnail(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/documents/404/thumb/" {
+			http.Error(w, `{"detail":"Not found."}`, http.StatusNotFound)
+			return
+		}
+		if r.URL.Path != "/api/documents/7/thumb/" {
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+		w.Header().Set("Content-Type", "image/webp")
+		w.Write([]byte("webp-bytes"))
+	}))
+	defer ts.Close()
+
+	s := &Server{paperlessClient: paperless.New(ts.URL, "test-token")}
+
+	result, err := s.handleGetDocumentThumbnail(context.Background(), map[string]interface{}{"document_id": float64(7)})
+	if err != nil {
+		t.Fatalf("get_document_thumbnail failed: %v", err)
+	}
+	wrapped, ok := result.(*contentResult)
+	if !ok || len(wrapped.Content) != 1 {
+		t.Fatalf("Expected one content block, got %#v", result)
+	}
+	image, ok := wrapped.Content[0].(mcp.ImageContent)
+	if !ok || image.MIMEType != "image/webp" || image.Data != base64.StdEncoding.EncodeToString([]byte("webp-bytes")) {
+		t.Errorf("Unexpected image content %#v", wrapped.Content[0])
+	}
+	if info := wrapped.Result.(thumbnailInfo); info.DocumentID != 7 || info.Size != 10 {
+		t.Errorf("Unexpected result %+v", info)
+	}
+
+	for _, args := range []map[string]interface{}{
+		{"document_id": float64(404)},
+		{"document_id": float64(0)},
+		{},
+	} {
+		if _, err := s.handleGetDocumentThumbnail(context.Background(), args); err == nil {
+			t.Errorf("Expected %v to fail", args)
+		}
+	}
+}
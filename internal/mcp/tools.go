@@ -3,6 +3,11 @@ package mcp
 import (
 	"context"
 	"log/slog"
+	"sort"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
 )
 
 // registerTools registers all MCP tools with the server
@@ -19,6 +24,7 @@ func (s *Server) registerTools() {
 			"required":   []string{},
 		},
 		Handler: s.handlePing,
+		Local:   true,
 	})
 	if err != nil {
 		slog.Error("Failed to register ping tool", "error", err)
@@ -27,18 +33,54 @@ func (s *Server) registerTools() {
 	// Register the server_info tool
 	err = s.RegisterTool(Tool{
 		Name:        "server_info",
-		Description: "Returns information about the MCP server and Paperless connection",
+		Description: "Returns information about the MCP server, the Paperless version and API version, registered tools, cache status, transport details and the directories file tools may access",
 		InputSchema: map[string]interface{}{
 			"type":       "object",
 			"properties": map[string]interface{}{},
 			"required":   []string{},
 		},
 		Handler: s.handleServerInfo,
+		Local:   true,
 	})
 	if err != nil {
 		slog.Error("Failed to register server_info tool", "error", err)
 	}
 
+	// Register the describe_paperless_enums tool
+	err = s.RegisterTool(Tool{
+		Name:        "describe_paperless_enums",
+		Description: "List the valid matching algorithms, custom field data types, bulk edit methods, permission levels and saved view filter rule types with descriptions, instead of guessing numeric codes or strings",
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+			"required":   []string{},
+		},
+		Handler: s.handleDescribePaperlessEnums,
+		Local:   true,
+	})
+	if err != nil {
+		slog.Error("Failed to register describe_paperless_enums tool", "error", err)
+	}
+
+	// Register the describe_api tool
+	err = s.RegisterTool(Tool{
+		Name:        "describe_api",
+		Description: "Summarize Paperless' REST API from its OpenAPI schema: without resource, the available resources; with one, its endpoints, parameters and the fields of the objects they exchange. Use it to answer questions about Paperless capabilities the other tools do not cover",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"resource": map[string]interface{}{
+					"type":        "string",
+					"description": "API resource to describe, the first path segment after /api/, e.g. documents, workflows, mail_accounts or share_links (optional, lists the resources when omitted)",
+				},
+			},
+			"required": []string{},
+		},
+		Handler: s.handleDescribeAPI,
+	})
+	if err != nil {
+		slog.Error("Failed to register describe_api tool", "error", err)
+	}
 
 	// Register the search_documents tool
 	err = s.RegisterTool(Tool{
@@ -49,7 +91,7 @@ func (s *Server) registerTools() {
 			"properties": map[string]interface{}{
 				"query": map[string]interface{}{
 					"type":        "string",
-					"description": "Search query text",
+					"description": "Search query text in Paperless' full-text syntax, e.g. invoice tag:tax created:[2023 to 2024]; use build_search_query to assemble or check field:value terms, ranges and AND/OR/NOT",
 				},
 				"page": map[string]interface{}{
 					"type":        "integer",
@@ -59,6 +101,10 @@ func (s *Server) registerTools() {
 					"type":        "integer",
 					"description": "Number of results per page (optional, default: 25, max: 100)",
 				},
+				"preview_chars": map[string]interface{}{
+					"type":        "integer",
+					"description": "Return at most this many characters per document instead of its full content: the search highlight when there is one, otherwise the start of the content (optional, max: 2000)",
+				},
 			},
 			"required": []string{"query"},
 		},
@@ -68,6 +114,65 @@ func (s *Server) registerTools() {
 		slog.Error("Failed to register search_documents tool", "error", err)
 	}
 
+	// Register the build_search_query tool
+	err = s.RegisterTool(Tool{
+		Name:        "build_search_query",
+		Description: "Assemble a search_documents query in Paperless' advanced full-text syntax (field:value terms, date and number ranges, AND/OR/NOT) from structured clauses, or check an existing query for unknown fields, unbalanced quotes or parentheses, misplaced operators and malformed values that would make the search fail or silently return nothing",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"clauses": map[string]interface{}{
+					"type":        "array",
+					"description": "Conditions to combine, each giving exactly one of value, any_of, or from and/or to",
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"field": map[string]interface{}{
+								"type":        "string",
+								"description": "Index field to match, e.g. title, content, correspondent, tag, type, path, notes, custom_fields, created, added, modified, asn, has_tag (optional, default: any text)",
+							},
+							"value": map[string]interface{}{
+								"type":        "string",
+								"description": "Word, phrase or wildcard pattern such as invoice* to match; phrases are quoted automatically",
+							},
+							"any_of": map[string]interface{}{
+								"type":        "array",
+								"items":       map[string]interface{}{"type": "string"},
+								"description": "Match any of these values, e.g. several tags",
+							},
+							"from": map[string]interface{}{
+								"type":        "string",
+								"description": "Start of a date or number range, e.g. 2023, 2023-04-01 or -1 week (leave out for an open start)",
+							},
+							"to": map[string]interface{}{
+								"type":        "string",
+								"description": "End of a date or number range, e.g. 2024 or now (leave out for an open end)",
+							},
+							"exclude": map[string]interface{}{
+								"type":        "boolean",
+								"description": "Match documents NOT satisfying this clause (optional, default: false)",
+							},
+						},
+					},
+				},
+				"match": map[string]interface{}{
+					"type":        "string",
+					"description": "all to require every clause (AND) or any to require at least one (OR) (optional, default: all)",
+				},
+				"query": map[string]interface{}{
+					"type":        "string",
+					"description": "An existing query to validate instead of building one",
+				},
+			},
+			"required": []string{},
+		},
+		Handler: s.handleBuildSearchQuery,
+		Local:   true,
+	})
+	if err != nil {
+		slog.Error("Failed to register build_search_query tool", "error", err)
+	}
+
 	// Register the find_similar_documents tool
 	err = s.RegisterTool(Tool{
 		Name:        "find_similar_documents",
@@ -87,6 +192,10 @@ func (s *Server) registerTools() {
 					"type":        "integer",
 					"description": "Number of results per page (optional, default: 25, max: 100)",
 				},
+				"preview_chars": map[string]interface{}{
+					"type":        "integer",
+					"description": "Return at most this many characters per document instead of its full content: the search highlight when there is one, otherwise the start of the content (optional, max: 2000)",
+				},
 			},
 			"required": []string{"document_id"},
 		},
@@ -96,11 +205,10 @@ func (s *Server) registerTools() {
 		slog.Error("Failed to register find_similar_documents tool", "error", err)
 	}
 
-
 	// Register the get_document tool
 	err = s.RegisterTool(Tool{
 		Name:        "get_document",
-		Description: "Get a document by ID with all metadata",
+		Description: "Get a document by ID with all metadata, including page_count and the original_size and archive_size of its files in bytes",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
@@ -108,6 +216,14 @@ func (s *Server) registerTools() {
 					"type":        "integer",
 					"description": "ID of the document to retrieve",
 				},
+				"include_file": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Also attach the document file as an embedded resource (optional, default: false)",
+				},
+				"original": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Attach the original file instead of the archived PDF version (optional, default: false)",
+				},
 			},
 			"required": []string{"document_id"},
 		},
@@ -117,136 +233,104 @@ func (s *Server) registerTools() {
 		slog.Error("Failed to register get_document tool", "error", err)
 	}
 
-	// Register the get_document_content tool
+	// Register the get_document_metadata tool
 	err = s.RegisterTool(Tool{
-		Name:        "get_document_content",
-		Description: "Get the text content of a document",
+		Name:        "get_document_metadata",
+		Description: "Get the file-level details of a document without downloading it: filenames, MIME type, sizes and checksums of the original and archived files, page count, detected language and the metadata embedded in the files (e.g. PDF producer, EXIF, e-mail headers)",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
 				"document_id": map[string]interface{}{
 					"type":        "integer",
-					"description": "ID of the document to retrieve content from",
+					"description": "ID of the document",
 				},
 			},
 			"required": []string{"document_id"},
 		},
-		Handler: s.handleGetDocumentContent,
+		Handler: s.handleGetDocumentMetadata,
 	})
 	if err != nil {
-		slog.Error("Failed to register get_document_content tool", "error", err)
+		slog.Error("Failed to register get_document_metadata tool", "error", err)
 	}
 
-	// Register the create_document tool
+	// Register the download_document_original tool
 	err = s.RegisterTool(Tool{
-		Name:        "create_document",
-		Description: "Create a new document in Paperless",
+		Name:        "download_document_original",
+		Description: "Download the original file of a document as uploaded, before Paperless archived it, attached as an embedded resource and described by its filename, MIME type and size so it can be handed to other systems",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
-				"title": map[string]interface{}{
-					"type":        "string",
-					"description": "Title of the document",
-				},
-				"correspondent": map[string]interface{}{
-					"type":        "integer",
-					"description": "Correspondent ID (optional)",
-				},
-				"document_type": map[string]interface{}{
-					"type":        "integer",
-					"description": "Document type ID (optional)",
-				},
-				"storage_path": map[string]interface{}{
+				"document_id": map[string]interface{}{
 					"type":        "integer",
-					"description": "Storage path ID (optional)",
-				},
-				"tags": map[string]interface{}{
-					"type":        "array",
-					"description": "Array of tag IDs (optional)",
-					"items": map[string]interface{}{
-						"type": "integer",
-					},
+					"description": "ID of the document to download",
 				},
 			},
-			"required": []string{"title"},
+			"required": []string{"document_id"},
 		},
-		Handler: s.handleCreateDocument,
+		Handler: s.handleDownloadDocumentOriginal,
 	})
 	if err != nil {
-		slog.Error("Failed to register create_document tool", "error", err)
+		slog.Error("Failed to register download_document_original tool", "error", err)
 	}
 
-	// Register the update_document tool
+	// Register the download_document_archive tool
 	err = s.RegisterTool(Tool{
-		Name:        "update_document",
-		Description: "Update a document's metadata",
+		Name:        "download_document_archive",
+		Description: "Download the archived PDF of a document, the searchable version Paperless made with OCR, attached as an embedded resource and described by its filename, MIME type and size. Documents without an archived version return their original file, reported as version original",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
 				"document_id": map[string]interface{}{
 					"type":        "integer",
-					"description": "ID of the document to update",
-				},
-				"title": map[string]interface{}{
-					"type":        "string",
-					"description": "New title (optional)",
-				},
-				"correspondent": map[string]interface{}{
-					"type":        "integer",
-					"description": "New correspondent ID (optional)",
-				},
-				"document_type": map[string]interface{}{
-					"type":        "integer",
-					"description": "New document type ID (optional)",
-				},
-				"storage_path": map[string]interface{}{
-					"type":        "integer",
-					"description": "New storage path ID (optional)",
-				},
-				"tags": map[string]interface{}{
-					"type":        "array",
-					"description": "New array of tag IDs (optional)",
-					"items": map[string]interface{}{
-						"type": "integer",
-					},
+					"description": "ID of the document to download",
 				},
 			},
 			"required": []string{"document_id"},
 		},
-		Handler: s.handleUpdateDocument,
+		Handler: s.handleDownloadDocumentArchive,
 	})
 	if err != nil {
-		slog.Error("Failed to register update_document tool", "error", err)
+		slog.Error("Failed to register download_document_archive tool", "error", err)
 	}
 
-	// Register the delete_document tool
+	// Register the get_documents tool
 	err = s.RegisterTool(Tool{
-		Name:        "delete_document",
-		Description: "Delete a document from Paperless",
+		Name:        "get_documents",
+		Description: "Retrieve several documents by ID in one call, returned in the order requested with page_count, original_size and archive_size; IDs that do not exist are listed in not_found",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
-				"document_id": map[string]interface{}{
-					"type":        "integer",
-					"description": "ID of the document to delete",
+				"document_ids": map[string]interface{}{
+					"type":        "array",
+					"description": "IDs of the documents to retrieve (at most 100)",
+					"items": map[string]interface{}{
+						"type": "integer",
+					},
 				},
 			},
-			"required": []string{"document_id"},
+			"required": []string{"document_ids"},
 		},
-		Handler: s.handleDeleteDocument,
+		Handler: s.handleGetDocuments,
 	})
 	if err != nil {
-		slog.Error("Failed to register delete_document tool", "error", err)
+		slog.Error("Failed to register get_documents tool", "error", err)
 	}
 
-
-	// Register the list_correspondents tool
+	// Register the browse_archive tool
 	err = s.RegisterTool(Tool{
-		Name:        "list_correspondents",
-		Description: "List all correspondents with pagination support",
+		Name:        "browse_archive",
+		Description: "Browse documents by the date they were created: without arguments, count the documents of each year; with a year, count each month of that year; with a year and month, list that month's documents oldest first. Start here for requests like \"what did I file in March 2022\".",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
+				"year": map[string]interface{}{
+					"type":        "integer",
+					"description": "Year to break down by month, e.g. 2022 (optional)",
+				},
+				"month": map[string]interface{}{
+					"type":        "integer",
+					"description": "Month of year whose documents to list, 1-12 (optional, needs year)",
+				},
 				"page": map[string]interface{}{
 					"type":        "integer",
 					"description": "Page number (1-based, optional, default: 1)",
@@ -258,261 +342,1665 @@ func (s *Server) registerTools() {
 			},
 			"required": []string{},
 		},
-		Handler: s.handleListCorrespondents,
+		Handler: s.handleBrowseArchive,
 	})
 	if err != nil {
-		slog.Error("Failed to register list_correspondents tool", "error", err)
+		slog.Error("Failed to register browse_archive tool", "error", err)
 	}
 
-	// Register the get_correspondent tool
+	// Register the get_linked_documents tool
 	err = s.RegisterTool(Tool{
-		Name:        "get_correspondent",
-		Description: "Get a correspondent by ID",
+		Name:        "get_linked_documents",
+		Description: "Follow document link custom fields from a document, e.g. through a contract and its amendments, returning the linked documents with their depth and the links between them",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
-				"correspondent_id": map[string]interface{}{
+				"document_id": map[string]interface{}{
 					"type":        "integer",
-					"description": "ID of the correspondent to retrieve",
+					"description": "The ID of the document to start from",
+				},
+				"max_depth": map[string]interface{}{
+					"type":        "integer",
+					"description": "How many links to follow from the document (optional, default: 1, max: 5)",
+				},
+				"preview_chars": map[string]interface{}{
+					"type":        "integer",
+					"description": "Also return the start of each linked document's content, at most this many characters (optional, max: 2000)",
 				},
 			},
-			"required": []string{"correspondent_id"},
+			"required": []string{"document_id"},
 		},
-		Handler: s.handleGetCorrespondent,
+		Handler: s.handleGetLinkedDocuments,
 	})
 	if err != nil {
-		slog.Error("Failed to register get_correspondent tool", "error", err)
+		slog.Error("Failed to register get_linked_documents tool", "error", err)
 	}
 
-	// Register the create_correspondent tool
+	// Register the assess_document_ocr tool
 	err = s.RegisterTool(Tool{
-		Name:        "create_correspondent",
-		Description: "Create a new correspondent in Paperless",
+		Name:        "assess_document_ocr",
+		Description: "Assess the quality of a document's OCR text: content length, share of garbage characters and non-words, characters per page and detected language. Use before summarizing to decide whether the document should be reprocessed instead.",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
-				"name": map[string]interface{}{
-					"type":        "string",
-					"description": "Name of the correspondent",
-				},
-				"match": map[string]interface{}{
-					"type":        "string",
-					"description": "Matching text pattern (optional)",
-				},
-				"matching_algorithm": map[string]interface{}{
+				"document_id": map[string]interface{}{
 					"type":        "integer",
-					"description": "Matching algorithm type (optional)",
-				},
-				"is_insensitive": map[string]interface{}{
-					"type":        "boolean",
-					"description": "Case insensitive matching (optional)",
+					"description": "The ID of the document to assess",
 				},
 			},
-			"required": []string{"name"},
+			"required": []string{"document_id"},
 		},
-		Handler: s.handleCreateCorrespondent,
+		Handler: s.handleAssessDocumentOCR,
 	})
 	if err != nil {
-		slog.Error("Failed to register create_correspondent tool", "error", err)
+		slog.Error("Failed to register assess_document_ocr tool", "error", err)
 	}
 
-	// Register the update_correspondent tool
+	// Register the get_document_thumbnail tool
 	err = s.RegisterTool(Tool{
-		Name:        "update_correspondent",
-		Description: "Update a correspondent's information",
+		Name:        "get_document_thumbnail",
+		Description: "Get the thumbnail of a document as image content, so vision-capable clients can see the document's first page",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
-				"correspondent_id": map[string]interface{}{
-					"type":        "integer",
-					"description": "ID of the correspondent to update",
-				},
-				"name": map[string]interface{}{
-					"type":        "string",
-					"description": "New name (optional)",
-				},
-				"match": map[string]interface{}{
-					"type":        "string",
-					"description": "New matching pattern (optional)",
-				},
-				"matching_algorithm": map[string]interface{}{
+				"document_id": map[string]interface{}{
 					"type":        "integer",
-					"description": "New matching algorithm (optional)",
-				},
-				"is_insensitive": map[string]interface{}{
-					"type":        "boolean",
-					"description": "Case insensitive matching (optional)",
+					"description": "ID of the document",
 				},
 			},
-			"required": []string{"correspondent_id"},
+			"required": []string{"document_id"},
 		},
-		Handler: s.handleUpdateCorrespondent,
+		Handler: s.handleGetDocumentThumbnail,
 	})
 	if err != nil {
-		slog.Error("Failed to register update_correspondent tool", "error", err)
+		slog.Error("Failed to register get_document_thumbnail tool", "error", err)
 	}
 
-	// Register the delete_correspondent tool
+	// Register the get_thumbnails tool
 	err = s.RegisterTool(Tool{
-		Name:        "delete_correspondent",
-		Description: "Delete a correspondent from Paperless",
+		Name:        "get_thumbnails",
+		Description: "Get the thumbnails of up to 20 documents as image content, each labelled with the document ID and title, to visually confirm a batch before a destructive bulk operation",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
-				"correspondent_id": map[string]interface{}{
-					"type":        "integer",
-					"description": "ID of the correspondent to delete",
+				"document_ids": map[string]interface{}{
+					"type":        "array",
+					"description": "IDs of the documents (at most 20)",
+					"items": map[string]interface{}{
+						"type": "integer",
+					},
 				},
 			},
-			"required": []string{"correspondent_id"},
+			"required": []string{"document_ids"},
 		},
-		Handler: s.handleDeleteCorrespondent,
+		Handler: s.handleGetThumbnails,
 	})
 	if err != nil {
-		slog.Error("Failed to register delete_correspondent tool", "error", err)
+		slog.Error("Failed to register get_thumbnails tool", "error", err)
 	}
 
-
-	// Register the list_document_types tool
+	// Register the get_document_content tool
 	err = s.RegisterTool(Tool{
-		Name:        "list_document_types",
-		Description: "List all document types with pagination support",
+		Name:        "get_document_content",
+		Description: "Get the text content of a document",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
-				"page": map[string]interface{}{
-					"type":        "integer",
-					"description": "Page number (1-based, optional, default: 1)",
-				},
-				"page_size": map[string]interface{}{
+				"document_id": map[string]interface{}{
 					"type":        "integer",
-					"description": "Number of results per page (optional, default: 25, max: 100)",
+					"description": "ID of the document to retrieve content from",
 				},
 			},
-			"required": []string{},
+			"required": []string{"document_id"},
 		},
-		Handler: s.handleListDocumentTypes,
+		Handler: s.handleGetDocumentContent,
 	})
 	if err != nil {
-		slog.Error("Failed to register list_document_types tool", "error", err)
+		slog.Error("Failed to register get_document_content tool", "error", err)
 	}
 
-	// Register the get_document_type tool
 	err = s.RegisterTool(Tool{
-		Name:        "get_document_type",
-		Description: "Get a document type by ID",
+		Name:        "get_document_summary",
+		Description: "Get a previously stored summary of a document, keyed by the checksum of its original file so it is shared across sessions. Check this before reading long document content; stale is true when the document changed after the summary was stored.",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
-				"document_type_id": map[string]interface{}{
+				"document_id": map[string]interface{}{
 					"type":        "integer",
-					"description": "ID of the document type to retrieve",
+					"description": "ID of the document",
 				},
 			},
-			"required": []string{"document_type_id"},
+			"required": []string{"document_id"},
 		},
-		Handler: s.handleGetDocumentType,
+		Handler: s.handleGetDocumentSummary,
 	})
 	if err != nil {
-		slog.Error("Failed to register get_document_type tool", "error", err)
+		slog.Error("Failed to register get_document_summary tool", "error", err)
 	}
 
-	// Register the create_document_type tool
 	err = s.RegisterTool(Tool{
-		Name:        "create_document_type",
-		Description: "Create a new document type in Paperless",
+		Name:        "store_document_summary",
+		Description: "Store a summary you wrote of a document's content so later sessions can use get_document_summary instead of reading the full text. Replaces any earlier summary of the same file.",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
-				"name": map[string]interface{}{
+				"document_id": map[string]interface{}{
+					"type":        "integer",
+					"description": "ID of the summarized document",
+				},
+				"summary": map[string]interface{}{
 					"type":        "string",
-					"description": "Name of the document type",
+					"description": "The summary text",
 				},
-				"match": map[string]interface{}{
+				"generator": map[string]interface{}{
+					"type":        "string",
+					"description": "What produced the summary, e.g. a model name (optional)",
+				},
+			},
+			"required": []string{"document_id", "summary"},
+		},
+		Handler: s.handleStoreDocumentSummary,
+	})
+	if err != nil {
+		slog.Error("Failed to register store_document_summary tool", "error", err)
+	}
+
+	// Register the create_document tool
+	err = s.RegisterTool(Tool{
+		Name:        "create_document",
+		Description: "Create a new document in Paperless",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"title": map[string]interface{}{
+					"type":        "string",
+					"description": "Title of the document",
+				},
+				"correspondent": map[string]interface{}{
+					"type":        "integer",
+					"description": "Correspondent ID (optional)",
+				},
+				"document_type": map[string]interface{}{
+					"type":        "integer",
+					"description": "Document type ID (optional)",
+				},
+				"storage_path": map[string]interface{}{
+					"type":        "integer",
+					"description": "Storage path ID (optional)",
+				},
+				"tags": map[string]interface{}{
+					"type":        "array",
+					"description": "Array of tag IDs (optional)",
+					"items": map[string]interface{}{
+						"type": "integer",
+					},
+				},
+				"idempotency_key": map[string]interface{}{
+					"type":        "string",
+					"description": "Unique key for this creation (optional); repeating a call with the same key returns the original document instead of creating a duplicate",
+				},
+			},
+			"required": []string{"title"},
+		},
+		Handler: s.handleCreateDocument,
+	})
+	if err != nil {
+		slog.Error("Failed to register create_document tool", "error", err)
+	}
+
+	// Register the upload_document tool
+	err = s.RegisterTool(Tool{
+		Name:        "upload_document",
+		Description: "Upload a file to Paperless for consumption, optionally setting its title, created date, correspondent, document type, storage path, tags, archive serial number, owner and custom fields. A file already in Paperless, by checksum, is not uploaded again unless force is set; the existing document is returned instead. Password-protected PDFs are decrypted locally with pdf_password before upload, since Paperless cannot consume them. Returns the UUID of the consumption task; the document gets an ID once Paperless has consumed it",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"filename": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the file, including its extension",
+				},
+				"content_base64": map[string]interface{}{
+					"type":        "string",
+					"description": "Content of the file, base64 encoded (at most 64 MiB decoded)",
+				},
+				"title": map[string]interface{}{
+					"type":        "string",
+					"description": "Title of the document (optional)",
+				},
+				"created": map[string]interface{}{
+					"type":        "string",
+					"description": "Created date of the document, as YYYY-MM-DD or an ISO 8601 timestamp (optional)",
+				},
+				"correspondent": map[string]interface{}{
+					"type":        "integer",
+					"description": "Correspondent ID (optional)",
+				},
+				"document_type": map[string]interface{}{
+					"type":        "integer",
+					"description": "Document type ID (optional)",
+				},
+				"storage_path": map[string]interface{}{
+					"type":        "integer",
+					"description": "Storage path ID (optional)",
+				},
+				"tags": map[string]interface{}{
+					"type":        "array",
+					"description": "Array of tag IDs (optional)",
+					"items": map[string]interface{}{
+						"type": "integer",
+					},
+				},
+				"archive_serial_number": map[string]interface{}{
+					"type":        "integer",
+					"description": "Archive serial number to assign; rejected with the holding document when already in use (optional)",
+				},
+				"owner": map[string]interface{}{
+					"type":        "integer",
+					"description": "User ID of the owner, on Paperless versions that accept one on upload (optional)",
+				},
+				"custom_fields": map[string]interface{}{
+					"type":        "array",
+					"description": "Custom field values to set on the document, each checked against the field's data type; a null value attaches the field without a value (optional)",
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"field": map[string]interface{}{
+								"description": "Custom field name or ID",
+							},
+							"value": map[string]interface{}{
+								"description": "Value in the field's type: text, URL, date, true/false, number, amount such as EUR12.50, list of document IDs, or select option label",
+							},
+						},
+						"required": []string{"field", "value"},
+					},
+				},
+				"pdf_password": map[string]interface{}{
+					"type":        "string",
+					"description": "Password of a password-protected PDF, used to decrypt it before upload; the password is not sent to Paperless (optional)",
+				},
+				"force": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Upload even when a document with the same checksum is already in Paperless, instead of returning that document; Paperless may still refuse the duplicate unless configured to accept them (optional, default: false)",
+				},
+				"idempotency_key": map[string]interface{}{
+					"type":        "string",
+					"description": "Unique key for this upload (optional); repeating a call with the same key returns the original task instead of uploading the file again",
+				},
+			},
+			"required": []string{"filename", "content_base64"},
+		},
+		Handler: s.handleUploadDocument,
+	})
+	if err != nil {
+		slog.Error("Failed to register upload_document tool", "error", err)
+	}
+
+	// Register the diagnose_failed_task tool
+	err = s.RegisterTool(Tool{
+		Name:        "diagnose_failed_task",
+		Description: "Explain why Paperless failed to consume an upload: fetches the task's result from Paperless, classifies common failures (duplicate, password-protected PDF, unsupported file type, timeout, OCR error) and suggests a remediation. Without task_id, diagnoses the recent failed consumption tasks not yet dismissed in Paperless",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"task_id": map[string]interface{}{
+					"type":        "string",
+					"description": "UUID of the task, as returned by upload_document (optional)",
+				},
+			},
+		},
+		Handler: s.handleDiagnoseFailedTask,
+	})
+	if err != nil {
+		slog.Error("Failed to register diagnose_failed_task tool", "error", err)
+	}
+
+	// Register the update_document tool
+	err = s.RegisterTool(Tool{
+		Name:        "update_document",
+		Description: "Update a document's metadata",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"document_id": map[string]interface{}{
+					"type":        "integer",
+					"description": "ID of the document to update",
+				},
+				"title": map[string]interface{}{
+					"type":        "string",
+					"description": "New title (optional)",
+				},
+				"correspondent": map[string]interface{}{
+					"type":        "integer",
+					"description": "New correspondent ID (optional)",
+				},
+				"document_type": map[string]interface{}{
+					"type":        "integer",
+					"description": "New document type ID (optional)",
+				},
+				"storage_path": map[string]interface{}{
+					"type":        "integer",
+					"description": "New storage path ID (optional)",
+				},
+				"tags": map[string]interface{}{
+					"type":        "array",
+					"description": "New array of tag IDs (optional)",
+					"items": map[string]interface{}{
+						"type": "integer",
+					},
+				},
+				"archive_serial_number": map[string]interface{}{
+					"type":        "integer",
+					"description": "New archive serial number; rejected with the holding document when already in use (optional)",
+				},
+				"custom_fields": map[string]interface{}{
+					"type":        "array",
+					"description": "Custom field values to set, each checked against the field's data type; fields not listed keep their values and a null value clears one (optional)",
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"field": map[string]interface{}{
+								"description": "Custom field name or ID",
+							},
+							"value": map[string]interface{}{
+								"description": "Value in the field's type: text, URL, date, true/false, number, amount such as EUR12.50, list of document IDs, or select option label",
+							},
+						},
+						"required": []string{"field", "value"},
+					},
+				},
+				"expected_modified": map[string]interface{}{
+					"type":        "string",
+					"description": "The document's modified timestamp as last read; the update is refused, returning the current document, if it has changed since (optional)",
+				},
+			},
+			"required": []string{"document_id"},
+		},
+		Handler: s.handleUpdateDocument,
+	})
+	if err != nil {
+		slog.Error("Failed to register update_document tool", "error", err)
+	}
+
+	err = s.RegisterTool(Tool{
+		Name:        "set_document_dates",
+		Description: "Correct a document's created date. Accepts dates as people write them (2024-03-15, 15.03.2024, March 15th 2024, 15 Mar 2024) and sends Paperless the YYYY-MM-DD form it expects. Numeric dates such as 03/04/2024 need date_order unless the day is obvious.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"document_id": map[string]interface{}{
+					"type":        "integer",
+					"description": "ID of the document to update",
+				},
+				"created": map[string]interface{}{
+					"type":        "string",
+					"description": "The date the document was created, e.g. as printed on it",
+				},
+				"date_order": map[string]interface{}{
+					"type":        "string",
+					"description": "How to read numeric dates where day and month could be swapped: DMY or MDY (optional)",
+				},
+				"expected_modified": map[string]interface{}{
+					"type":        "string",
+					"description": "The document's modified timestamp as last read; the update is refused, returning the current document, if it has changed since (optional)",
+				},
+			},
+			"required": []string{"document_id", "created"},
+		},
+		Handler: s.handleSetDocumentDates,
+	})
+	if err != nil {
+		slog.Error("Failed to register set_document_dates tool", "error", err)
+	}
+
+	// Register the triage_document tool
+	err = s.RegisterTool(Tool{
+		Name:        "triage_document",
+		Description: "Process an inbox document in one call: add tags, set correspondent, document type, storage path and title, remove its inbox tags, and optionally assign an archive serial number. All changes are applied in a single update.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"document_id": map[string]interface{}{
+					"type":        "integer",
+					"description": "ID of the document to triage",
+				},
+				"add_tags": map[string]interface{}{
+					"type":        "array",
+					"description": "Tag IDs to add (optional)",
+					"items": map[string]interface{}{
+						"type": "integer",
+					},
+				},
+				"correspondent": map[string]interface{}{
+					"type":        "integer",
+					"description": "Correspondent ID to set (optional)",
+				},
+				"document_type": map[string]interface{}{
+					"type":        "integer",
+					"description": "Document type ID to set (optional)",
+				},
+				"storage_path": map[string]interface{}{
+					"type":        "integer",
+					"description": "Storage path ID to set (optional)",
+				},
+				"title": map[string]interface{}{
+					"type":        "string",
+					"description": "New title (optional)",
+				},
+				"remove_inbox_tags": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Remove the document's inbox tags, except any listed in add_tags (optional, default: true)",
+				},
+				"assign_asn": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Assign the next free archive serial number if the document has none (optional, default: false)",
+				},
+				"archive_serial_number": map[string]interface{}{
+					"type":        "integer",
+					"description": "Archive serial number to assign instead of the next free one (optional)",
+				},
+				"plan_only": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Make the reads and return the ordered list of API calls, with the update that would be sent, without changing the document (optional, default: false)",
+				},
+			},
+			"required": []string{"document_id"},
+		},
+		Handler: s.handleTriageDocument,
+	})
+	if err != nil {
+		slog.Error("Failed to register triage_document tool", "error", err)
+	}
+
+	// Register the mark_documents_processed tool
+	err = s.RegisterTool(Tool{
+		Name:        "mark_documents_processed",
+		Description: "Remove all inbox tags from the given documents, finishing a triage session. The inbox tags are looked up automatically; the removal is a bulk edit and can be undone.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"document_ids": map[string]interface{}{
+					"type":        "array",
+					"description": "IDs of the documents to mark as processed",
+					"items": map[string]interface{}{
+						"type": "integer",
+					},
+				},
+				"plan_only": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Return the ordered list of API calls that would be made without editing any document (optional, default: false)",
+				},
+			},
+			"required": []string{"document_ids"},
+		},
+		Handler: s.handleMarkDocumentsProcessed,
+	})
+	if err != nil {
+		slog.Error("Failed to register mark_documents_processed tool", "error", err)
+	}
+
+	// Register the delete_document tool
+	err = s.RegisterTool(Tool{
+		Name:        "delete_document",
+		Description: "Delete a document from Paperless. Pass the document's title as confirm_title; it is checked before deleting to catch a wrong ID. Documents covered by the server's delete protection rules (e.g. a legal hold tag) are refused.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"document_id": map[string]interface{}{
+					"type":        "integer",
+					"description": "ID of the document to delete",
+				},
+				"confirm_title": map[string]interface{}{
+					"type":        "string",
+					"description": "Title of the document, or a distinctive part of it (at least 4 characters); the document is only deleted if it matches. Required when the server enforces title confirmation.",
+				},
+			},
+			"required": []string{"document_id"},
+		},
+		Handler: s.handleDeleteDocument,
+	})
+	if err != nil {
+		slog.Error("Failed to register delete_document tool", "error", err)
+	}
+
+	// Register the list_correspondents tool
+	err = s.RegisterTool(Tool{
+		Name:        "list_correspondents",
+		Description: "List all correspondents with pagination support",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"page": map[string]interface{}{
+					"type":        "integer",
+					"description": "Page number (1-based, optional, default: 1)",
+				},
+				"page_size": map[string]interface{}{
+					"type":        "integer",
+					"description": "Number of results per page (optional, default: 25, max: 100)",
+				},
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Only list correspondents whose name contains this text, ignoring case (optional)",
+				},
+				"ordering": map[string]interface{}{
+					"type":        "string",
+					"description": "Sort by name, document_count, last_correspondence; prefix with - for descending, e.g. -document_count for the most used first (optional)",
+				},
+			},
+			"required": []string{},
+		},
+		Handler: s.handleListCorrespondents,
+	})
+	if err != nil {
+		slog.Error("Failed to register list_correspondents tool", "error", err)
+	}
+
+	// Register the get_correspondent tool
+	err = s.RegisterTool(Tool{
+		Name:        "get_correspondent",
+		Description: "Get a correspondent by ID",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"correspondent_id": map[string]interface{}{
+					"type":        "integer",
+					"description": "ID of the correspondent to retrieve",
+				},
+			},
+			"required": []string{"correspondent_id"},
+		},
+		Handler: s.handleGetCorrespondent,
+	})
+	if err != nil {
+		slog.Error("Failed to register get_correspondent tool", "error", err)
+	}
+
+	// Register the create_correspondent tool
+	err = s.RegisterTool(Tool{
+		Name:        "create_correspondent",
+		Description: "Create a new correspondent in Paperless",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the correspondent",
+				},
+				"match": map[string]interface{}{
+					"type":        "string",
+					"description": "Matching text pattern (optional)",
+				},
+				"matching_algorithm": map[string]interface{}{
+					"type":        "integer",
+					"description": "Matching algorithm type, see describe_paperless_enums (optional)",
+				},
+				"is_insensitive": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Case insensitive matching (optional)",
+				},
+			},
+			"required": []string{"name"},
+		},
+		Handler: s.handleCreateCorrespondent,
+	})
+	if err != nil {
+		slog.Error("Failed to register create_correspondent tool", "error", err)
+	}
+
+	// Register the update_correspondent tool
+	err = s.RegisterTool(Tool{
+		Name:        "update_correspondent",
+		Description: "Update a correspondent's information. Renames report the slug change and any storage path templates that mention the old name.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"correspondent_id": map[string]interface{}{
+					"type":        "integer",
+					"description": "ID of the correspondent to update",
+				},
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "New name (optional)",
+				},
+				"match": map[string]interface{}{
+					"type":        "string",
+					"description": "New matching pattern (optional)",
+				},
+				"matching_algorithm": map[string]interface{}{
+					"type":        "integer",
+					"description": "New matching algorithm (optional)",
+				},
+				"is_insensitive": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Case insensitive matching (optional)",
+				},
+			},
+			"required": []string{"correspondent_id"},
+		},
+		Handler: s.handleUpdateCorrespondent,
+	})
+	if err != nil {
+		slog.Error("Failed to register update_correspondent tool", "error", err)
+	}
+
+	// Register the delete_correspondent tool
+	err = s.RegisterTool(Tool{
+		Name:        "delete_correspondent",
+		Description: "Delete a correspondent from Paperless",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"correspondent_id": map[string]interface{}{
+					"type":        "integer",
+					"description": "ID of the correspondent to delete",
+				},
+			},
+			"required": []string{"correspondent_id"},
+		},
+		Handler: s.handleDeleteCorrespondent,
+	})
+	if err != nil {
+		slog.Error("Failed to register delete_correspondent tool", "error", err)
+	}
+
+	// Register the get_correspondent_summary tool
+	err = s.RegisterTool(Tool{
+		Name:        "get_correspondent_summary",
+		Description: "Summarise the history with a correspondent: document counts by year and document type, the most recent documents, and the last correspondence date",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"correspondent_id": map[string]interface{}{
+					"type":        "integer",
+					"description": "ID of the correspondent",
+				},
+				"recent_count": map[string]interface{}{
+					"type":        "integer",
+					"description": "Number of recent documents to include (optional, default: 5, max: 25)",
+				},
+			},
+			"required": []string{"correspondent_id"},
+		},
+		Handler: s.handleGetCorrespondentSummary,
+	})
+	if err != nil {
+		slog.Error("Failed to register get_correspondent_summary tool", "error", err)
+	}
+
+	// Register the list_document_types tool
+	err = s.RegisterTool(Tool{
+		Name:        "list_document_types",
+		Description: "List all document types with pagination support",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"page": map[string]interface{}{
+					"type":        "integer",
+					"description": "Page number (1-based, optional, default: 1)",
+				},
+				"page_size": map[string]interface{}{
+					"type":        "integer",
+					"description": "Number of results per page (optional, default: 25, max: 100)",
+				},
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Only list document types whose name contains this text, ignoring case (optional)",
+				},
+				"ordering": map[string]interface{}{
+					"type":        "string",
+					"description": "Sort by name, document_count; prefix with - for descending, e.g. -document_count for the most used first (optional)",
+				},
+			},
+			"required": []string{},
+		},
+		Handler: s.handleListDocumentTypes,
+	})
+	if err != nil {
+		slog.Error("Failed to register list_document_types tool", "error", err)
+	}
+
+	// Register the list_storage_paths tool
+	err = s.RegisterTool(Tool{
+		Name:        "list_storage_paths",
+		Description: "List all storage paths with pagination support",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"page": map[string]interface{}{
+					"type":        "integer",
+					"description": "Page number (1-based, optional, default: 1)",
+				},
+				"page_size": map[string]interface{}{
+					"type":        "integer",
+					"description": "Number of results per page (optional, default: 25, max: 100)",
+				},
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Only list storage paths whose name contains this text, ignoring case (optional)",
+				},
+				"ordering": map[string]interface{}{
+					"type":        "string",
+					"description": "Sort by name, document_count; prefix with - for descending, e.g. -document_count for the most used first (optional)",
+				},
+			},
+			"required": []string{},
+		},
+		Handler: s.handleListStoragePaths,
+	})
+	if err != nil {
+		slog.Error("Failed to register list_storage_paths tool", "error", err)
+	}
+
+	// Register the create_storage_path tool
+	err = s.RegisterTool(Tool{
+		Name:        "create_storage_path",
+		Description: "Create a new storage path. The path template is checked against the placeholders Paperless supports, such as {{ correspondent }}/{{ created_year }}/{{ title }}, and typos are rejected.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the storage path",
+				},
+				"path": map[string]interface{}{
+					"type":        "string",
+					"description": "Path template, e.g. {{ correspondent }}/{{ created_year }}/{{ title }}",
+				},
+				"match": map[string]interface{}{
+					"type":        "string",
+					"description": "Matching text pattern (optional)",
+				},
+				"matching_algorithm": map[string]interface{}{
+					"type":        "integer",
+					"description": "Matching algorithm type, see describe_paperless_enums (optional)",
+				},
+				"is_insensitive": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Case insensitive matching (optional)",
+				},
+			},
+			"required": []string{"name", "path"},
+		},
+		Handler: s.handleCreateStoragePath,
+	})
+	if err != nil {
+		slog.Error("Failed to register create_storage_path tool", "error", err)
+	}
+
+	// Register the update_storage_path tool
+	err = s.RegisterTool(Tool{
+		Name:        "update_storage_path",
+		Description: "Update a storage path. A new path template is checked against the placeholders Paperless supports and typos are rejected.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"storage_path_id": map[string]interface{}{
+					"type":        "integer",
+					"description": "ID of the storage path to update",
+				},
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "New name (optional)",
+				},
+				"path": map[string]interface{}{
+					"type":        "string",
+					"description": "New path template (optional)",
+				},
+				"match": map[string]interface{}{
+					"type":        "string",
+					"description": "Matching text pattern (optional)",
+				},
+				"matching_algorithm": map[string]interface{}{
+					"type":        "integer",
+					"description": "Matching algorithm type, see describe_paperless_enums (optional)",
+				},
+				"is_insensitive": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Case insensitive matching (optional)",
+				},
+			},
+			"required": []string{"storage_path_id"},
+		},
+		Handler: s.handleUpdateStoragePath,
+	})
+	if err != nil {
+		slog.Error("Failed to register update_storage_path tool", "error", err)
+	}
+
+	// Register the get_document_type tool
+	err = s.RegisterTool(Tool{
+		Name:        "get_document_type",
+		Description: "Get a document type by ID",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"document_type_id": map[string]interface{}{
+					"type":        "integer",
+					"description": "ID of the document type to retrieve",
+				},
+			},
+			"required": []string{"document_type_id"},
+		},
+		Handler: s.handleGetDocumentType,
+	})
+	if err != nil {
+		slog.Error("Failed to register get_document_type tool", "error", err)
+	}
+
+	// Register the create_document_type tool
+	err = s.RegisterTool(Tool{
+		Name:        "create_document_type",
+		Description: "Create a new document type in Paperless",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the document type",
+				},
+				"match": map[string]interface{}{
+					"type":        "string",
+					"description": "Matching text pattern (optional)",
+				},
+				"matching_algorithm": map[string]interface{}{
+					"type":        "integer",
+					"description": "Matching algorithm type, see describe_paperless_enums (optional)",
+				},
+				"is_insensitive": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Case insensitive matching (optional)",
+				},
+			},
+			"required": []string{"name"},
+		},
+		Handler: s.handleCreateDocumentType,
+	})
+	if err != nil {
+		slog.Error("Failed to register create_document_type tool", "error", err)
+	}
+
+	// Register the update_document_type tool
+	err = s.RegisterTool(Tool{
+		Name:        "update_document_type",
+		Description: "Update a document type's information. Renames report the slug change and any storage path templates that mention the old name.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"document_type_id": map[string]interface{}{
+					"type":        "integer",
+					"description": "ID of the document type to update",
+				},
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "New name (optional)",
+				},
+				"match": map[string]interface{}{
+					"type":        "string",
+					"description": "New matching pattern (optional)",
+				},
+				"matching_algorithm": map[string]interface{}{
+					"type":        "integer",
+					"description": "New matching algorithm (optional)",
+				},
+				"is_insensitive": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Case insensitive matching (optional)",
+				},
+			},
+			"required": []string{"document_type_id"},
+		},
+		Handler: s.handleUpdateDocumentType,
+	})
+	if err != nil {
+		slog.Error("Failed to register update_document_type tool", "error", err)
+	}
+
+	// Register the delete_document_type tool
+	err = s.RegisterTool(Tool{
+		Name:        "delete_document_type",
+		Description: "Delete a document type from Paperless",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"document_type_id": map[string]interface{}{
+					"type":        "integer",
+					"description": "ID of the document type to delete",
+				},
+			},
+			"required": []string{"document_type_id"},
+		},
+		Handler: s.handleDeleteDocumentType,
+	})
+	if err != nil {
+		slog.Error("Failed to register delete_document_type tool", "error", err)
+	}
+
+	// Register the list_tags tool
+	err = s.RegisterTool(Tool{
+		Name:        "list_tags",
+		Description: "List all tags with pagination support",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"page": map[string]interface{}{
+					"type":        "integer",
+					"description": "Page number (1-based, optional, default: 1)",
+				},
+				"page_size": map[string]interface{}{
+					"type":        "integer",
+					"description": "Number of results per page (optional, default: 25, max: 100)",
+				},
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Only list tags whose name contains this text, ignoring case (optional)",
+				},
+				"ordering": map[string]interface{}{
+					"type":        "string",
+					"description": "Sort by name, document_count; prefix with - for descending, e.g. -document_count for the most used first (optional)",
+				},
+			},
+			"required": []string{},
+		},
+		Handler: s.handleListTags,
+	})
+	if err != nil {
+		slog.Error("Failed to register list_tags tool", "error", err)
+	}
+
+	// Register the get_tag tool
+	err = s.RegisterTool(Tool{
+		Name:        "get_tag",
+		Description: "Get a tag by ID",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"tag_id": map[string]interface{}{
+					"type":        "integer",
+					"description": "ID of the tag to retrieve",
+				},
+			},
+			"required": []string{"tag_id"},
+		},
+		Handler: s.handleGetTag,
+	})
+	if err != nil {
+		slog.Error("Failed to register get_tag tool", "error", err)
+	}
+
+	// Register the create_tag tool
+	err = s.RegisterTool(Tool{
+		Name:        "create_tag",
+		Description: "Create a new tag in Paperless",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the tag",
+				},
+				"color": map[string]interface{}{
+					"type":        "string",
+					"description": "Color of the tag (hex format)",
+				},
+				"match": map[string]interface{}{
 					"type":        "string",
 					"description": "Matching text pattern (optional)",
 				},
-				"matching_algorithm": map[string]interface{}{
+				"matching_algorithm": map[string]interface{}{
+					"type":        "integer",
+					"description": "Matching algorithm type, see describe_paperless_enums (optional)",
+				},
+				"is_insensitive": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Case insensitive matching (optional)",
+				},
+				"is_inbox_tag": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Whether this is an inbox tag (optional)",
+				},
+			},
+			"required": []string{"name", "color"},
+		},
+		Handler: s.handleCreateTag,
+	})
+	if err != nil {
+		slog.Error("Failed to register create_tag tool", "error", err)
+	}
+
+	// Register the update_tag tool
+	err = s.RegisterTool(Tool{
+		Name:        "update_tag",
+		Description: "Update a tag's information. Renames report the slug change and any storage path templates that mention the old name.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"tag_id": map[string]interface{}{
+					"type":        "integer",
+					"description": "ID of the tag to update",
+				},
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "New name (optional)",
+				},
+				"color": map[string]interface{}{
+					"type":        "string",
+					"description": "New color (optional)",
+				},
+				"match": map[string]interface{}{
+					"type":        "string",
+					"description": "New matching pattern (optional)",
+				},
+				"matching_algorithm": map[string]interface{}{
+					"type":        "integer",
+					"description": "New matching algorithm (optional)",
+				},
+				"is_insensitive": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Case insensitive matching (optional)",
+				},
+				"is_inbox_tag": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Whether this is an inbox tag (optional)",
+				},
+			},
+			"required": []string{"tag_id"},
+		},
+		Handler: s.handleUpdateTag,
+	})
+	if err != nil {
+		slog.Error("Failed to register update_tag tool", "error", err)
+	}
+
+	// Register the delete_tag tool
+	err = s.RegisterTool(Tool{
+		Name:        "delete_tag",
+		Description: "Delete a tag from Paperless",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"tag_id": map[string]interface{}{
+					"type":        "integer",
+					"description": "ID of the tag to delete",
+				},
+			},
+			"required": []string{"tag_id"},
+		},
+		Handler: s.handleDeleteTag,
+	})
+	if err != nil {
+		slog.Error("Failed to register delete_tag tool", "error", err)
+	}
+
+	err = s.RegisterTool(Tool{
+		Name:        "list_tag_tree",
+		Description: "List all tags as a hierarchy, reading tag names such as Finance/Taxes/2024 as paths split on the configured delimiter. Groups implied by a path but without a tag of their own have no id.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"path": map[string]interface{}{
+					"type":        "string",
+					"description": "Only return the subtrees whose path ends with this partial path, e.g. Taxes or Finance/Taxes (optional)",
+				},
+				"max_depth": map[string]interface{}{
+					"type":        "integer",
+					"description": "Number of levels to return (optional, default all)",
+				},
+			},
+			"required": []string{},
+		},
+		Handler: s.handleListTagTree,
+	})
+	if err != nil {
+		slog.Error("Failed to register list_tag_tree tool", "error", err)
+	}
+
+	err = s.RegisterTool(Tool{
+		Name:        "resolve_tag_path",
+		Description: "Find the tag ID for a full or partial tag path such as Taxes/2024, matching the trailing levels of hierarchical tag names case-insensitively; the last level may be a prefix. Returns tag_id when the match is unambiguous, otherwise all candidates.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"path": map[string]interface{}{
+					"type":        "string",
+					"description": "Full or partial tag path",
+				},
+			},
+			"required": []string{"path"},
+		},
+		Handler: s.handleResolveTagPath,
+	})
+	if err != nil {
+		slog.Error("Failed to register resolve_tag_path tool", "error", err)
+	}
+
+	// Register the list_custom_fields tool
+	err = s.RegisterTool(Tool{
+		Name:        "list_custom_fields",
+		Description: "List all custom fields with pagination support",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"page": map[string]interface{}{
+					"type":        "integer",
+					"description": "Page number (1-based, optional, default: 1)",
+				},
+				"page_size": map[string]interface{}{
+					"type":        "integer",
+					"description": "Number of results per page (optional, default: 25, max: 100)",
+				},
+			},
+			"required": []string{},
+		},
+		Handler: s.handleListCustomFields,
+	})
+	if err != nil {
+		slog.Error("Failed to register list_custom_fields tool", "error", err)
+	}
+
+	// Register the get_custom_field tool
+	err = s.RegisterTool(Tool{
+		Name:        "get_custom_field",
+		Description: "Get a custom field by ID",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"field_id": map[string]interface{}{
+					"type":        "integer",
+					"description": "ID of the custom field to retrieve",
+				},
+			},
+			"required": []string{"field_id"},
+		},
+		Handler: s.handleGetCustomField,
+	})
+	if err != nil {
+		slog.Error("Failed to register get_custom_field tool", "error", err)
+	}
+
+	// Register the create_custom_field tool
+	err = s.RegisterTool(Tool{
+		Name:        "create_custom_field",
+		Description: "Create a new custom field in Paperless",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the custom field",
+				},
+				"data_type": map[string]interface{}{
+					"type":        "string",
+					"description": "Data type of the custom field (e.g., string, integer, boolean, date, url; see describe_paperless_enums)",
+				},
+			},
+			"required": []string{"name", "data_type"},
+		},
+		Handler: s.handleCreateCustomField,
+	})
+	if err != nil {
+		slog.Error("Failed to register create_custom_field tool", "error", err)
+	}
+
+	// Register the update_custom_field tool
+	err = s.RegisterTool(Tool{
+		Name:        "update_custom_field",
+		Description: "Update a custom field's information",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"field_id": map[string]interface{}{
+					"type":        "integer",
+					"description": "ID of the custom field to update",
+				},
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "New name (optional)",
+				},
+				"data_type": map[string]interface{}{
+					"type":        "string",
+					"description": "New data type (optional)",
+				},
+			},
+			"required": []string{"field_id"},
+		},
+		Handler: s.handleUpdateCustomField,
+	})
+	if err != nil {
+		slog.Error("Failed to register update_custom_field tool", "error", err)
+	}
+
+	// Register the delete_custom_field tool
+	err = s.RegisterTool(Tool{
+		Name:        "delete_custom_field",
+		Description: "Delete a custom field from Paperless",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"field_id": map[string]interface{}{
+					"type":        "integer",
+					"description": "ID of the custom field to delete",
+				},
+			},
+			"required": []string{"field_id"},
+		},
+		Handler: s.handleDeleteCustomField,
+	})
+	if err != nil {
+		slog.Error("Failed to register delete_custom_field tool", "error", err)
+	}
+
+	// Register the bulk_edit_documents tool
+	err = s.RegisterTool(Tool{
+		Name:        "bulk_edit_documents",
+		Description: "Perform bulk edit operations on multiple documents. Large edits are sent to Paperless in chunks, with progress notifications; if a chunk fails the edit stops and the documents not edited are listed.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"document_ids": map[string]interface{}{
+					"type":        "array",
+					"description": "Array of document IDs to edit",
+					"items": map[string]interface{}{
+						"type": "integer",
+					},
+				},
+				"add_tags": map[string]interface{}{
+					"type":        "array",
+					"description": "Array of tag IDs to add (optional)",
+					"items": map[string]interface{}{
+						"type": "integer",
+					},
+				},
+				"remove_tags": map[string]interface{}{
+					"type":        "array",
+					"description": "Array of tag IDs to remove (optional)",
+					"items": map[string]interface{}{
+						"type": "integer",
+					},
+				},
+				"set_correspondent": map[string]interface{}{
 					"type":        "integer",
-					"description": "Matching algorithm type (optional)",
+					"description": "Correspondent ID to set (optional)",
 				},
-				"is_insensitive": map[string]interface{}{
+				"set_document_type": map[string]interface{}{
+					"type":        "integer",
+					"description": "Document type ID to set (optional)",
+				},
+				"set_storage_path": map[string]interface{}{
+					"type":        "integer",
+					"description": "Storage path ID to set (optional)",
+				},
+				"verify": map[string]interface{}{
+					"type":        "string",
+					"description": "Re-fetch edited documents afterwards and report any the edit did not take effect on, as Paperless silently skips documents the user may not change: none (default), sample (up to 20 documents) or all",
+				},
+				"plan_only": map[string]interface{}{
 					"type":        "boolean",
-					"description": "Case insensitive matching (optional)",
+					"description": "Return the ordered list of API calls that would be made, one per chunk, without editing any document (optional, default: false)",
 				},
 			},
-			"required": []string{"name"},
+			"required": []string{"document_ids"},
 		},
-		Handler: s.handleCreateDocumentType,
+		Handler: s.handleBulkEditDocuments,
 	})
 	if err != nil {
-		slog.Error("Failed to register create_document_type tool", "error", err)
+		slog.Error("Failed to register bulk_edit_documents tool", "error", err)
 	}
 
-	// Register the update_document_type tool
+	// Register the check_duplicate_document tool
 	err = s.RegisterTool(Tool{
-		Name:        "update_document_type",
-		Description: "Update a document type's information",
+		Name:        "check_duplicate_document",
+		Description: "Check whether a file already exists in Paperless by comparing its MD5 checksum with existing documents' originals, and optionally whether an archive serial number is already in use",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
-				"document_type_id": map[string]interface{}{
+				"file_path": map[string]interface{}{
+					"type":        "string",
+					"description": "Path to the file on the MCP server's filesystem, inside the client's roots when it shares any (optional if content_base64 is given)",
+				},
+				"content_base64": map[string]interface{}{
+					"type":        "string",
+					"description": "Base64-encoded file content (optional if file_path is given)",
+				},
+				"archive_serial_number": map[string]interface{}{
 					"type":        "integer",
-					"description": "ID of the document type to update",
+					"description": "Archive serial number the upload would be given; the document already holding it is reported (optional)",
 				},
-				"name": map[string]interface{}{
+			},
+			"required": []string{},
+		},
+		Handler: s.handleCheckDuplicateDocument,
+	})
+	if err != nil {
+		slog.Error("Failed to register check_duplicate_document tool", "error", err)
+	}
+
+	// Register the verify_documents tool
+	err = s.RegisterTool(Tool{
+		Name:        "verify_documents",
+		Description: "Audit the stored files of a set of documents: reports documents whose file metadata cannot be read, that have no archived PDF version or no checksum, and, with verify_checksums, whose original or archived file no longer matches the checksum Paperless recorded. Paperless itself only offers this check as a management command.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"document_ids": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "integer"},
+					"description": "IDs of the documents to verify (optional if filter is given)",
+				},
+				"filter": map[string]interface{}{
+					"type":        "object",
+					"description": "Paperless document filter query parameters selecting the documents to verify, e.g. {\"added__date__gt\": \"2024-01-01\"}; {} verifies all documents up to max_documents (optional if document_ids is given)",
+				},
+				"verify_checksums": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Download the original and archived files and compare their MD5 checksums with the recorded ones; slow on large sets (optional, default: false)",
+				},
+				"max_documents": map[string]interface{}{
+					"type":        "integer",
+					"description": "Maximum number of documents to verify (optional, default: 100, max: 1000)",
+				},
+				"stream": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Send issues as notifications/partial_result notifications while documents are verified, leaving them out of the final result (optional, default: false)",
+				},
+				"chunk_size": map[string]interface{}{
+					"type":        "integer",
+					"description": "Issues per partial result when streaming (optional, default: 50, max: 500)",
+				},
+			},
+			"required": []string{},
+		},
+		Handler: s.handleVerifyDocuments,
+	})
+	if err != nil {
+		slog.Error("Failed to register verify_documents tool", "error", err)
+	}
+
+	// Register the compare_documents tool
+	err = s.RegisterTool(Tool{
+		Name:        "compare_documents",
+		Description: "Compare two documents, returning a structured diff of their metadata (title, dates, tags, correspondent, custom fields) and content similarity statistics to help resolve near-duplicates",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"document_id_a": map[string]interface{}{
+					"type":        "integer",
+					"description": "ID of the first document",
+				},
+				"document_id_b": map[string]interface{}{
+					"type":        "integer",
+					"description": "ID of the second document",
+				},
+			},
+			"required": []string{"document_id_a", "document_id_b"},
+		},
+		Handler: s.handleCompareDocuments,
+	})
+	if err != nil {
+		slog.Error("Failed to register compare_documents tool", "error", err)
+	}
+
+	// Register the test_matching_rule tool
+	err = s.RegisterTool(Tool{
+		Name:        "test_matching_rule",
+		Description: "Check whether a matching rule (match string and algorithm) would fire for sample text or an existing document's content, to validate auto-assignment rules before saving them. Algorithms: 0=none, 1=any, 2=all, 3=literal, 4=regex, 5=fuzzy, 6=auto (auto cannot be evaluated)",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"match": map[string]interface{}{
 					"type":        "string",
-					"description": "New name (optional)",
+					"description": "Matching text pattern",
+				},
+				"matching_algorithm": map[string]interface{}{
+					"type":        "integer",
+					"description": "Matching algorithm type",
+				},
+				"is_insensitive": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Case insensitive matching (optional, default: true)",
+				},
+				"text": map[string]interface{}{
+					"type":        "string",
+					"description": "Sample text to test against (optional if document_id is given)",
+				},
+				"document_id": map[string]interface{}{
+					"type":        "integer",
+					"description": "ID of a document whose content to test against (optional if text is given)",
+				},
+			},
+			"required": []string{"match", "matching_algorithm"},
+		},
+		Handler: s.handleTestMatchingRule,
+	})
+	if err != nil {
+		slog.Error("Failed to register test_matching_rule tool", "error", err)
+	}
+
+	// Register the preview_filter_matches tool
+	err = s.RegisterTool(Tool{
+		Name:        "preview_filter_matches",
+		Description: "Dry run for taxonomy and workflow changes: return the count and a sample of documents that currently match a prospective matching rule, a saved-view style filter, or both (the filter narrows the documents the rule is tested against)",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"filter": map[string]interface{}{
+					"type":        "object",
+					"description": "Paperless document filter query parameters, e.g. {\"tags__id__all\": [1, 2], \"correspondent__id\": 3, \"title__icontains\": \"invoice\"} (optional)",
 				},
 				"match": map[string]interface{}{
 					"type":        "string",
-					"description": "New matching pattern (optional)",
+					"description": "Matching text pattern of the prospective rule (optional)",
 				},
 				"matching_algorithm": map[string]interface{}{
 					"type":        "integer",
-					"description": "New matching algorithm (optional)",
+					"description": "Matching algorithm of the prospective rule: 0=none, 1=any, 2=all, 3=literal, 4=regex, 5=fuzzy (optional)",
 				},
 				"is_insensitive": map[string]interface{}{
 					"type":        "boolean",
-					"description": "Case insensitive matching (optional)",
+					"description": "Case insensitive matching (optional, default: true)",
+				},
+				"sample_size": map[string]interface{}{
+					"type":        "integer",
+					"description": "Number of matching documents to return (optional, default: 10, max: 50)",
+				},
+				"max_documents": map[string]interface{}{
+					"type":        "integer",
+					"description": "Maximum number of documents to scan when testing a matching rule (optional, default: 1000, max: 10000)",
+				},
+				"preview_chars": map[string]interface{}{
+					"type":        "integer",
+					"description": "Also return at most this many characters of each sampled document: the search highlight when the filter has a full text query, otherwise the start of the content (optional, max: 2000)",
 				},
 			},
-			"required": []string{"document_type_id"},
+			"required": []string{},
 		},
-		Handler: s.handleUpdateDocumentType,
+		Handler: s.handlePreviewFilterMatches,
 	})
 	if err != nil {
-		slog.Error("Failed to register update_document_type tool", "error", err)
+		slog.Error("Failed to register preview_filter_matches tool", "error", err)
 	}
 
-	// Register the delete_document_type tool
+	// Register the list_mail_rules tool
 	err = s.RegisterTool(Tool{
-		Name:        "delete_document_type",
-		Description: "Delete a document type from Paperless",
+		Name:        "list_mail_rules",
+		Description: "List the mail rules Paperless uses to consume e-mail, in the order they are applied, with their account and the tags, correspondent and document type they assign",
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+			"required":   []string{},
+		},
+		Handler: s.handleListMailRules,
+	})
+	if err != nil {
+		slog.Error("Failed to register list_mail_rules tool", "error", err)
+	}
+
+	// Register the get_mail_rule_documents tool
+	err = s.RegisterTool(Tool{
+		Name:        "get_mail_rule_documents",
+		Description: "List the documents ingested through a mail rule. Paperless does not record the rule on the document, so these are the documents carrying every tag the rule assigns; rules assigning no tags cannot be filtered on",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
-				"document_type_id": map[string]interface{}{
+				"mail_rule_id": map[string]interface{}{
 					"type":        "integer",
-					"description": "ID of the document type to delete",
+					"description": "ID of the mail rule, from list_mail_rules",
+				},
+				"page": map[string]interface{}{
+					"type":        "integer",
+					"description": "Page number (1-based, optional, default: 1)",
+				},
+				"page_size": map[string]interface{}{
+					"type":        "integer",
+					"description": "Number of results per page (optional, default: 25, max: 100)",
+				},
+				"preview_chars": map[string]interface{}{
+					"type":        "integer",
+					"description": "Return at most this many characters per document instead of its full content (optional, max: 2000)",
+				},
+			},
+			"required": []string{"mail_rule_id"},
+		},
+		Handler: s.handleGetMailRuleDocuments,
+	})
+	if err != nil {
+		slog.Error("Failed to register get_mail_rule_documents tool", "error", err)
+	}
+
+	// Register the list_saved_views tool
+	err = s.RegisterTool(Tool{
+		Name:        "list_saved_views",
+		Description: "List the saved views users configured in the Paperless UI, with their filter rules (rule types named and referenced tags, correspondents, document types, storage paths and custom fields resolved), sort order, display fields and dashboard and sidebar placement",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"page": map[string]interface{}{
+					"type":        "integer",
+					"description": "Page number (1-based, optional, default: 1)",
+				},
+				"page_size": map[string]interface{}{
+					"type":        "integer",
+					"description": "Number of results per page (optional, default: 25, max: 100)",
+				},
+			},
+			"required": []string{},
+		},
+		Handler: s.handleListSavedViews,
+	})
+	if err != nil {
+		slog.Error("Failed to register list_saved_views tool", "error", err)
+	}
+
+	// Register the get_saved_view tool
+	err = s.RegisterTool(Tool{
+		Name:        "get_saved_view",
+		Description: "Get a Paperless saved view by ID, with its filter rules described, sort order and display settings",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"view_id": map[string]interface{}{
+					"type":        "integer",
+					"description": "ID of the saved view",
+				},
+			},
+			"required": []string{"view_id"},
+		},
+		Handler: s.handleGetSavedView,
+	})
+	if err != nil {
+		slog.Error("Failed to register get_saved_view tool", "error", err)
+	}
+
+	// Register the execute_saved_view tool
+	err = s.RegisterTool(Tool{
+		Name:        "execute_saved_view",
+		Description: "Return the documents a Paperless saved view shows, e.g. \"what's in my 'Taxes 2024' view\": its filter rules are translated into a documents query, sorted as the view sorts",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"view_id": map[string]interface{}{
+					"type":        "integer",
+					"description": "ID of the saved view, as returned by list_saved_views",
+				},
+				"page": map[string]interface{}{
+					"type":        "integer",
+					"description": "Page number (1-based, optional, default: 1)",
+				},
+				"page_size": map[string]interface{}{
+					"type":        "integer",
+					"description": "Number of results per page (optional, default: the view's page size or 25, max: 100)",
+				},
+				"preview_chars": map[string]interface{}{
+					"type":        "integer",
+					"description": "Return at most this many characters per document instead of its full content: the search highlight when there is one, otherwise the start of the content (optional, max: 2000)",
+				},
+			},
+			"required": []string{"view_id"},
+		},
+		Handler: s.handleExecuteSavedView,
+	})
+	if err != nil {
+		slog.Error("Failed to register execute_saved_view tool", "error", err)
+	}
+
+	// Register the save_query tool
+	err = s.RegisterTool(Tool{
+		Name:        "save_query",
+		Description: "Save a named document filter (e.g. \"unpaid invoices\") on the MCP server, independent of Paperless saved views, so it can be rerun later with run_saved_query",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the query, unique per user (case-insensitive)",
+				},
+				"filter": map[string]interface{}{
+					"type":        "object",
+					"description": "Paperless document filter query parameters, e.g. {\"tags__id__all\": [1, 2], \"correspondent__id\": 3}",
+				},
+				"description": map[string]interface{}{
+					"type":        "string",
+					"description": "What the query is for (optional)",
+				},
+				"overwrite": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Replace an existing query with the same name (optional, default: false)",
 				},
 			},
-			"required": []string{"document_type_id"},
+			"required": []string{"name", "filter"},
 		},
-		Handler: s.handleDeleteDocumentType,
+		Handler: s.handleSaveQuery,
+		Local:   true,
 	})
 	if err != nil {
-		slog.Error("Failed to register delete_document_type tool", "error", err)
+		slog.Error("Failed to register save_query tool", "error", err)
 	}
 
-	
-	// Register the list_tags tool
+	// Register the list_saved_queries tool
 	err = s.RegisterTool(Tool{
-		Name:        "list_tags",
-		Description: "List all tags with pagination support",
+		Name:        "list_saved_queries",
+		Description: "List the queries saved with save_query",
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+			"required":   []string{},
+		},
+		Handler: s.handleListSavedQueries,
+		Local:   true,
+	})
+	if err != nil {
+		slog.Error("Failed to register list_saved_queries tool", "error", err)
+	}
+
+	// Register the run_saved_query tool
+	err = s.RegisterTool(Tool{
+		Name:        "run_saved_query",
+		Description: "Run a query saved with save_query and return the matching documents",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the saved query",
+				},
 				"page": map[string]interface{}{
 					"type":        "integer",
 					"description": "Page number (1-based, optional, default: 1)",
@@ -521,329 +2009,391 @@ func (s *Server) registerTools() {
 					"type":        "integer",
 					"description": "Number of results per page (optional, default: 25, max: 100)",
 				},
+				"preview_chars": map[string]interface{}{
+					"type":        "integer",
+					"description": "Return at most this many characters per document instead of its full content: the search highlight when there is one, otherwise the start of the content (optional, max: 2000)",
+				},
 			},
-			"required": []string{},
+			"required": []string{"name"},
 		},
-		Handler: s.handleListTags,
+		Handler: s.handleRunSavedQuery,
 	})
 	if err != nil {
-		slog.Error("Failed to register list_tags tool", "error", err)
+		slog.Error("Failed to register run_saved_query tool", "error", err)
 	}
 
-	// Register the get_tag tool
+	// Register the delete_saved_query tool
 	err = s.RegisterTool(Tool{
-		Name:        "get_tag",
-		Description: "Get a tag by ID",
+		Name:        "delete_saved_query",
+		Description: "Delete a query saved with save_query",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
-				"tag_id": map[string]interface{}{
-					"type":        "integer",
-					"description": "ID of the tag to retrieve",
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the saved query",
 				},
 			},
-			"required": []string{"tag_id"},
+			"required": []string{"name"},
 		},
-		Handler: s.handleGetTag,
+		Handler: s.handleDeleteSavedQuery,
+		Local:   true,
 	})
 	if err != nil {
-		slog.Error("Failed to register get_tag tool", "error", err)
+		slog.Error("Failed to register delete_saved_query tool", "error", err)
 	}
 
-	// Register the create_tag tool
+	// Register the export_metadata tool
 	err = s.RegisterTool(Tool{
-		Name:        "create_tag",
-		Description: "Create a new tag in Paperless",
+		Name:        "export_metadata",
+		Description: "Export all tags, correspondents, document types, storage paths, custom fields and saved views as a single JSON snapshot for backup, versioning or diffing",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
-				"name": map[string]interface{}{
-					"type":        "string",
-					"description": "Name of the tag",
-				},
-				"color": map[string]interface{}{
-					"type":        "string",
-					"description": "Color of the tag (hex format)",
-				},
-				"match": map[string]interface{}{
+				"output_path": map[string]interface{}{
 					"type":        "string",
-					"description": "Matching text pattern (optional)",
-				},
-				"matching_algorithm": map[string]interface{}{
-					"type":        "integer",
-					"description": "Matching algorithm type (optional)",
+					"description": "File path on the MCP server to write the snapshot to, inside the client's roots when it shares any (optional); when omitted the snapshot is returned directly",
 				},
-				"is_insensitive": map[string]interface{}{
+				"stream": map[string]interface{}{
 					"type":        "boolean",
-					"description": "Case insensitive matching (optional)",
+					"description": "Send the snapshot as notifications/partial_result notifications, a page of one kind at a time, and return only the counts; for archives with very large taxonomies (optional, default: false, cannot be combined with output_path)",
 				},
-				"is_inbox_tag": map[string]interface{}{
-					"type":        "boolean",
-					"description": "Whether this is an inbox tag (optional)",
+				"chunk_size": map[string]interface{}{
+					"type":        "integer",
+					"description": "Objects per partial result when streaming (optional, default: 50, max: 500)",
 				},
 			},
-			"required": []string{"name", "color"},
+			"required": []string{},
 		},
-		Handler: s.handleCreateTag,
+		Handler: s.handleExportMetadata,
 	})
 	if err != nil {
-		slog.Error("Failed to register create_tag tool", "error", err)
+		slog.Error("Failed to register export_metadata tool", "error", err)
 	}
 
-	// Register the update_tag tool
+	// Register the import_metadata tool
 	err = s.RegisterTool(Tool{
-		Name:        "update_tag",
-		Description: "Update a tag's information",
+		Name:        "import_metadata",
+		Description: "Restore tags, correspondents, document types, storage paths and custom fields from an export_metadata snapshot, creating missing objects by name without ever duplicating. Runs as a dry run by default, reporting what would be created or changed",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
-				"tag_id": map[string]interface{}{
-					"type":        "integer",
-					"description": "ID of the tag to update",
-				},
-				"name": map[string]interface{}{
-					"type":        "string",
-					"description": "New name (optional)",
+				"snapshot": map[string]interface{}{
+					"type":        "object",
+					"description": "Snapshot object as returned by export_metadata (optional if input_path is given)",
 				},
-				"color": map[string]interface{}{
-					"type":        "string",
-					"description": "New color (optional)",
-				},
-				"match": map[string]interface{}{
+				"input_path": map[string]interface{}{
 					"type":        "string",
-					"description": "New matching pattern (optional)",
-				},
-				"matching_algorithm": map[string]interface{}{
-					"type":        "integer",
-					"description": "New matching algorithm (optional)",
+					"description": "Path to a snapshot file on the MCP server, inside the client's roots when it shares any (optional if snapshot is given)",
 				},
-				"is_insensitive": map[string]interface{}{
+				"dry_run": map[string]interface{}{
 					"type":        "boolean",
-					"description": "Case insensitive matching (optional)",
+					"description": "Only report what would be created or changed (optional, default: true)",
 				},
-				"is_inbox_tag": map[string]interface{}{
+				"update_existing": map[string]interface{}{
 					"type":        "boolean",
-					"description": "Whether this is an inbox tag (optional)",
+					"description": "Also update existing objects whose fields differ from the snapshot (optional, default: false)",
 				},
 			},
-			"required": []string{"tag_id"},
+			"required": []string{},
 		},
-		Handler: s.handleUpdateTag,
+		Handler: s.handleImportMetadata,
 	})
 	if err != nil {
-		slog.Error("Failed to register update_tag tool", "error", err)
+		slog.Error("Failed to register import_metadata tool", "error", err)
 	}
 
-	// Register the delete_tag tool
+	// Register the get_taxonomy_overview tool
 	err = s.RegisterTool(Tool{
-		Name:        "delete_tag",
-		Description: "Delete a tag from Paperless",
+		Name:        "get_taxonomy_overview",
+		Description: "Get an overview of the archive: the total number of documents, tags, correspondents and document types, and the most used of each by document count. A good first call to learn how an archive is organised.",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
-				"tag_id": map[string]interface{}{
+				"limit": map[string]interface{}{
 					"type":        "integer",
-					"description": "ID of the tag to delete",
+					"description": "How many of the most used tags, correspondents and document types to list (optional, default: 10, max: 100)",
 				},
 			},
-			"required": []string{"tag_id"},
+			"required": []string{},
 		},
-		Handler: s.handleDeleteTag,
+		Handler: s.handleGetTaxonomyOverview,
 	})
 	if err != nil {
-		slog.Error("Failed to register delete_tag tool", "error", err)
+		slog.Error("Failed to register get_taxonomy_overview tool", "error", err)
 	}
 
-
-
-	// Register the list_custom_fields tool
+	// Register the get_tag_cooccurrence tool
 	err = s.RegisterTool(Tool{
-		Name:        "list_custom_fields",
-		Description: "List all custom fields with pagination support",
+		Name:        "get_tag_cooccurrence",
+		Description: "Find which tags most often appear together on the same documents, from a sample of the most recently added documents. Each pair reports how often it occurs, its Jaccard similarity and how often each tag comes with the other, plus a suggestion (merge, nest or auto-assign) for pairs that nearly always appear together. Read-only; use it to propose taxonomy simplifications.",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
-				"page": map[string]interface{}{
+				"tag_id": map[string]interface{}{
 					"type":        "integer",
-					"description": "Page number (1-based, optional, default: 1)",
+					"description": "Only report pairs including this tag, sampling its documents; the other tags' totals then come from their document counts (optional)",
 				},
-				"page_size": map[string]interface{}{
+				"sample_size": map[string]interface{}{
 					"type":        "integer",
-					"description": "Number of results per page (optional, default: 25, max: 100)",
+					"description": "How many of the most recently added documents to sample (optional, default: 1000, max: 10000)",
+				},
+				"min_count": map[string]interface{}{
+					"type":        "integer",
+					"description": "Ignore pairs appearing together on fewer documents (optional, default: 3)",
+				},
+				"limit": map[string]interface{}{
+					"type":        "integer",
+					"description": "How many pairs to return, most frequent first (optional, default: 20, max: 100)",
 				},
 			},
 			"required": []string{},
 		},
-		Handler: s.handleListCustomFields,
+		Handler: s.handleGetTagCooccurrence,
 	})
 	if err != nil {
-		slog.Error("Failed to register list_custom_fields tool", "error", err)
+		slog.Error("Failed to register get_tag_cooccurrence tool", "error", err)
 	}
 
-	// Register the get_custom_field tool
+	// Register the get_paperless_settings tool
 	err = s.RegisterTool(Tool{
-		Name:        "get_custom_field",
-		Description: "Get a custom field by ID",
+		Name:        "get_paperless_settings",
+		Description: "Read the Paperless UI settings and application configuration (OCR languages and mode, archive settings, branding) and list the inbox tags. Read-only.",
 		InputSchema: map[string]interface{}{
-			"type": "object",
-			"properties": map[string]interface{}{
-				"field_id": map[string]interface{}{
-					"type":        "integer",
-					"description": "ID of the custom field to retrieve",
-				},
-			},
-			"required": []string{"field_id"},
+			"type":       "object",
+			"properties": map[string]interface{}{},
+			"required":   []string{},
 		},
-		Handler: s.handleGetCustomField,
+		Handler: s.handleGetPaperlessSettings,
 	})
 	if err != nil {
-		slog.Error("Failed to register get_custom_field tool", "error", err)
+		slog.Error("Failed to register get_paperless_settings tool", "error", err)
 	}
 
-	// Register the create_custom_field tool
+	// Register the get_cache_stats tool
 	err = s.RegisterTool(Tool{
-		Name:        "create_custom_field",
-		Description: "Create a new custom field in Paperless",
+		Name:        "get_cache_stats",
+		Description: "Show hit, miss and eviction counters and occupancy of the server's document cache, and hit, miss and refresh counters of each metadata cache (tags, correspondents, document types, storage paths, custom fields, mail rules and accounts), to rule out stale cached data",
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+			"required":   []string{},
+		},
+		Handler: s.handleGetCacheStats,
+		Local:   true,
+	})
+	if err != nil {
+		slog.Error("Failed to register get_cache_stats tool", "error", err)
+	}
+
+	// Register the clear_cache tool
+	err = s.RegisterTool(Tool{
+		Name:        "clear_cache",
+		Description: "Clear the server's document cache, and unless limited to the current session its cached tags, correspondents, document types, storage paths and custom fields, so the next reads fetch fresh data from Paperless",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
-				"name": map[string]interface{}{
-					"type":        "string",
-					"description": "Name of the custom field",
-				},
-				"data_type": map[string]interface{}{
-					"type":        "string",
-					"description": "Data type of the custom field (e.g., string, integer, boolean, date, url)",
+				"current_session_only": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Only clear documents cached for the calling session, keeping cached metadata (optional, default: false)",
 				},
 			},
-			"required": []string{"name", "data_type"},
+			"required": []string{},
 		},
-		Handler: s.handleCreateCustomField,
+		Handler: s.handleClearCache,
+		Local:   true,
 	})
 	if err != nil {
-		slog.Error("Failed to register create_custom_field tool", "error", err)
+		slog.Error("Failed to register clear_cache tool", "error", err)
 	}
 
-	// Register the update_custom_field tool
+	// Register the set_session_defaults tool
 	err = s.RegisterTool(Tool{
-		Name:        "update_custom_field",
-		Description: "Update a custom field's information",
+		Name:        "set_session_defaults",
+		Description: "Set sticky defaults for the rest of this session: a page_size used by paginated tools called without one, and default tags that restrict search_documents and run_saved_query to documents having all of them (unless the query filters by tag itself). The Paperless instance and a detail level cannot be set: the server talks to one instance and its tools have no detail levels. Returns the current defaults; call without arguments to view them.",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
-				"field_id": map[string]interface{}{
+				"page_size": map[string]interface{}{
 					"type":        "integer",
-					"description": "ID of the custom field to update",
+					"description": "Default number of results per page, maximum 100; 0 unsets it (optional)",
 				},
-				"name": map[string]interface{}{
-					"type":        "string",
-					"description": "New name (optional)",
+				"tags": map[string]interface{}{
+					"type":        "array",
+					"description": "Tag IDs documents must have; an empty array unsets the tag filter (optional)",
+					"items": map[string]interface{}{
+						"type": "integer",
+					},
 				},
-				"data_type": map[string]interface{}{
-					"type":        "string",
-					"description": "New data type (optional)",
+				"clear": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Reset all defaults before applying the other arguments (optional, default: false)",
 				},
 			},
-			"required": []string{"field_id"},
+			"required": []string{},
 		},
-		Handler: s.handleUpdateCustomField,
+		Handler: s.handleSetSessionDefaults,
+		Local:   true,
 	})
 	if err != nil {
-		slog.Error("Failed to register update_custom_field tool", "error", err)
+		slog.Error("Failed to register set_session_defaults tool", "error", err)
 	}
 
-	// Register the delete_custom_field tool
+	// Register the undo_last_operation tool
 	err = s.RegisterTool(Tool{
-		Name:        "delete_custom_field",
-		Description: "Delete a custom field from Paperless",
+		Name:        "undo_last_operation",
+		Description: "Revert the most recent change made through this server within the last hour: restore previous field values of an update or bulk edit, delete an object that was just created, or restore a deleted document from the trash. Call repeatedly to step further back.",
 		InputSchema: map[string]interface{}{
-			"type": "object",
-			"properties": map[string]interface{}{
-				"field_id": map[string]interface{}{
-					"type":        "integer",
-					"description": "ID of the custom field to delete",
-				},
-			},
-			"required": []string{"field_id"},
+			"type":       "object",
+			"properties": map[string]interface{}{},
+			"required":   []string{},
 		},
-		Handler: s.handleDeleteCustomField,
+		Handler: s.handleUndoLastOperation,
 	})
 	if err != nil {
-		slog.Error("Failed to register delete_custom_field tool", "error", err)
+		slog.Error("Failed to register undo_last_operation tool", "error", err)
 	}
 
-
-
-	// Register the bulk_edit_documents tool
+	// Register the resume_operation tool
 	err = s.RegisterTool(Tool{
-		Name:        "bulk_edit_documents",
-		Description: "Perform bulk edit operations on multiple documents",
+		Name:        "resume_operation",
+		Description: "Finish a bulk edit that was sent to Paperless in chunks and stopped part way, because a chunk failed or the server was restarted. Without operation_id, lists the operations that can be resumed with their progress; with it, edits the remaining documents from where the operation stopped. Operations can be resumed for 24 hours.",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
-				"document_ids": map[string]interface{}{
-					"type":        "array",
-					"description": "Array of document IDs to edit",
-					"items": map[string]interface{}{
-						"type": "integer",
-					},
-				},
-				"add_tags": map[string]interface{}{
-					"type":        "array",
-					"description": "Array of tag IDs to add (optional)",
-					"items": map[string]interface{}{
-						"type": "integer",
-					},
-				},
-				"remove_tags": map[string]interface{}{
-					"type":        "array",
-					"description": "Array of tag IDs to remove (optional)",
-					"items": map[string]interface{}{
-						"type": "integer",
-					},
-				},
-				"set_correspondent": map[string]interface{}{
-					"type":        "integer",
-					"description": "Correspondent ID to set (optional)",
-				},
-				"set_document_type": map[string]interface{}{
-					"type":        "integer",
-					"description": "Document type ID to set (optional)",
-				},
-				"set_storage_path": map[string]interface{}{
+				"operation_id": map[string]interface{}{
 					"type":        "integer",
-					"description": "Storage path ID to set (optional)",
+					"description": "ID of the operation to resume, as returned in a partial bulk edit result or listed by calling this tool without it (optional)",
 				},
 			},
-			"required": []string{"document_ids"},
+			"required": []string{},
 		},
-		Handler: s.handleBulkEditDocuments,
+		Handler: s.handleResumeOperation,
 	})
 	if err != nil {
-		slog.Error("Failed to register bulk_edit_documents tool", "error", err)
+		slog.Error("Failed to register resume_operation tool", "error", err)
 	}
 
+	// Register the raw_api_get tool, only when paths it may read are configured
+	if len(s.cfg.MCPRawAPIPaths) > 0 {
+		err = s.RegisterTool(Tool{
+			Name:        "raw_api_get",
+			Description: "Read-only escape hatch for Paperless filters and endpoints this server does not expose yet: send a GET request to a Paperless API path with an arbitrary query string and return the JSON response. Only paths under the prefixes configured by the server administrator are allowed.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"path": map[string]interface{}{
+						"type":        "string",
+						"description": "Paperless API path, e.g. /api/documents/",
+					},
+					"query": map[string]interface{}{
+						"type":        "string",
+						"description": "Query string passed to Paperless as is, e.g. title__icontains=invoice&created__year=2024 (optional)",
+					},
+				},
+				"required": []string{"path"},
+			},
+			Handler: s.handleRawAPIGet,
+		})
+		if err != nil {
+			slog.Error("Failed to register raw_api_get tool", "error", err)
+		}
+	}
 
 	slog.Info("Tool registration complete", "total_tools", len(s.tools))
 }
 
 // handlePing is a simple test tool that returns "pong"
 func (s *Server) handlePing(ctx context.Context, args map[string]interface{}) (interface{}, error) {
-	slog.Debug("Ping tool invoked")
+	logging.FromContext(ctx).Debug("Ping tool invoked")
 	return map[string]string{
 		"status":  "ok",
 		"message": "pong",
 	}, nil
 }
 
-// handleServerInfo returns information about the MCP server
+// handleServerInfo returns information about the MCP server and a
+// capability report, so agents can discover what is available at the
+// start of a session
 func (s *Server) handleServerInfo(ctx context.Context, args map[string]interface{}) (interface{}, error) {
-	slog.Debug("Server info tool invoked")
-	return map[string]string{
+	logging.FromContext(ctx).Debug("Server info tool invoked")
+
+	paperlessInfo := map[string]interface{}{
+		"reachable": true,
+	}
+	s.paperlessURLInfo(paperlessInfo)
+	if version, err := s.paperlessClient.GetServerVersion(ctx); err != nil {
+		logging.FromContext(ctx).Warn("Failed to get Paperless version", "error", err)
+		paperlessInfo["reachable"] = false
+		paperlessInfo["error"] = err.Error()
+	} else {
+		paperlessInfo["version"] = version.Version
+		paperlessInfo["api_version"] = version.APIVersion
+	}
+
+	status := "ok"
+	if health := s.health.state(); health.Degraded {
+		status = "degraded"
+		paperlessInfo["unavailable_since"] = health.Since.UTC().Format(time.RFC3339)
+		paperlessInfo["last_probe"] = health.CheckedAt.UTC().Format(time.RFC3339)
+	}
+
+	if scope := s.scopeInfo(); scope != nil {
+		paperlessInfo["token_scope"] = scope
+	}
+
+	toolNames := s.getToolNames()
+	sort.Strings(toolNames)
+
+	transport := map[string]interface{}{
+		"type": s.cfg.MCPTransport,
+	}
+	if s.cfg.MCPTransport == "http" {
+		transport["port"] = s.cfg.MCPHTTPPort
+		transport["endpoint"] = StreamableHTTPEndpoint
+		transport["auth_required"] = s.cfg.MCPAuthToken != "" || len(s.cfg.MCPAuthTokenMap) > 0
+		transport["auth_identities"] = len(s.cfg.MCPAuthTokenMap)
+		transport["heartbeat_interval_seconds"] = s.cfg.MCPHeartbeatInterval.Seconds()
+		transport["write_timeout_seconds"] = s.cfg.MCPHTTPWriteTimeout.Seconds()
+		transport["tls"] = s.cfg.MCPTLSCertFile != ""
+	}
+
+	cacheStats := s.documents.snapshot()
+
+	return map[string]interface{}{
 		"server_name":    ServerName,
 		"server_version": ServerVersion,
-		"paperless_url":  s.cfg.PaperlessURL,
+		"instance":       s.instance,
+		"paperless_url":  s.paperlessClient.BaseURL(),
 		"transport":      s.cfg.MCPTransport,
-		"status":         "ok",
+		"status":         status,
+		"paperless":      paperlessInfo,
+		"file_access":    s.fileAccess(ctx),
+		"capabilities": map[string]interface{}{
+			"tools":      toolNames,
+			"tool_count": len(toolNames),
+			"transport":  transport,
+			"document_cache": map[string]interface{}{
+				"enabled":              cacheStats.Capacity > 0,
+				"capacity_per_session": cacheStats.Capacity,
+				"ttl_seconds":          cacheStats.TTLSeconds,
+				"entries":              cacheStats.Entries,
+			},
+			"cassette_mode":          s.cfg.PaperlessCassetteMode,
+			"idempotency_persistent": s.cfg.MCPIdempotencyFile != "",
+		},
+	}, nil
+}
+
+// handleDescribePaperlessEnums returns the valid values of the enumerations
+// used by Paperless objects and tools
+func (s *Server) handleDescribePaperlessEnums(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	logging.FromContext(ctx).Debug("Describe Paperless enums tool invoked")
+	return map[string]interface{}{
+		"matching_algorithms":     paperless.MatchingAlgorithms,
+		"custom_field_data_types": paperless.CustomFieldDataTypes,
+		"bulk_edit_methods":       paperless.BulkEditMethods,
+		"permission_levels":       paperless.PermissionLevels,
+		"filter_rule_types":       paperless.FilterRuleTypes,
 	}, nil
 }
@@ -0,0 +1,108 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"testing"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/config"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// TestDescribePaperlessEnums tests that every enumeration is listed as
+// values with unique names and a description, the matching algorithms
+// numbered as Paperless numbers them
+func TestDescribePaperlessEnums(t *testing.T) {
+	s := &Server{}
+	result, err := s.handleDescribePaperlessEnums(context.Background(), map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("describe_paperless_enums failed: %v", err)
+	}
+
+	data, _ := json.Marshal(result)
+	var enums map[string][]struct {
+		Value       interface{} `json:"value"`
+		Name        string      `json:"name"`
+		Description string      `json:"description"`
+	}
+	if err := json.Unmarshal(data, &enums); err != nil {
+		t.Fatalf("Unexpected result shape: %v", err)
+	}
+
+	for _, name := range []string{"matching_algorithms", "custom_field_data_types", "bulk_edit_methods", "permission_levels", "filter_rule_types"} {
+		values, ok := enums[name]
+		if !ok || len(values) == 0 {
+			t.Errorf("Expected %s to be described", name)
+			continue
+		}
+		seen := make(map[string]bool)
+		for _, value := range values {
+			if value.Value == nil || value.Name == "" || value.Description == "" || seen[value.Name] {
+				t.Errorf("%s: unexpected or duplicate value %+v", name, value)
+			}
+			seen[value.Name] = true
+		}
+	}
+
+	for i, value := range enums["matching_algorithms"] {
+		if value.Value != float64(i) {
+			t.Errorf("Expected matching algorithm %q to be %d, got %v", value.Name, i, value.Value)
+		}
+	}
+	if last := enums["matching_algorithms"][len(enums["matching_algorithms"])-1]; last.Name != "auto" {
+		t.Errorf("Expected auto to be the last matching algorithm, got %q", last.Name)
+	}
+}
+
+// TestServerInfo tests that server_info reports the Paperless version and
+// a capability report listing the registered tools and the transport
+func TestServerInfo(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(paperless.VersionHeader, "2.14.7")
+		w.Header().Set(paperless.APIVersionHeader, "7")
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	s, err := New(&config.Config{
+		PaperlessURL:   ts.URL,
+		PaperlessToken: "test-token",
+		MCPTransport:   "http",
+		MCPHTTPPort:    "8080",
+		MCPAuthToken:   "secret",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	result, err := s.ExecuteTool(context.Background(), "server_info", map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("server_info failed: %v", err)
+	}
+	info := result.(map[string]interface{})
+	if info["server_name"] != ServerName || info["status"] != "ok" || info["transport"] != "http" {
+		t.Errorf("Unexpected server info %v", info)
+	}
+
+	upstream := info["paperless"].(map[string]interface{})
+	if upstream["reachable"] != true || upstream["version"] != "2.14.7" || upstream["api_version"] != "7" {
+		t.Errorf("Expected the Paperless version, got %v", upstream)
+	}
+
+	capabilities := info["capabilities"].(map[string]interface{})
+	tools := capabilities["tools"].([]string)
+	if capabilities["tool_count"] != len(tools) || !sort.StringsAreSorted(tools) || !slices.Contains(tools, "server_info") {
+		t.Errorf("Expected the sorted registered tools, got %v", tools)
+	}
+	transport := capabilities["transport"].(map[string]interface{})
+	if transport["port"] != "8080" || transport["endpoint"] != StreamableHTTPEndpoint || transport["auth_required"] != true {
+		t.Errorf("Unexpected transport %v", transport)
+	}
+	if _, ok := capabilities["document_cache"].(map[string]interface{}); !ok {
+		t.Errorf("Expected the document cache to be described, got %v", capabilities)
+	}
+}
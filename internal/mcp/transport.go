@@ -2,85 +2,122 @@ package mcp
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
-	"log/slog"
+	"io"
+	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
 	"github.com/mark3labs/mcp-go/server"
 )
 
 // Transport constants
 const (
-	// ShutdownTimeout is the maximum time to wait for graceful shutdown
+	// ShutdownTimeout is the maximum time to wait for HTTP connections to
+	// close once tool calls have been drained
 	ShutdownTimeout = 10 * time.Second
 
 	// StreamableHTTPEndpoint is the HTTP streaming endpoint path
 	StreamableHTTPEndpoint = "/mcp"
 
-	// HeartbeatInterval is the interval for connection heartbeat/keep-alive
-	HeartbeatInterval = 30 * time.Second
-
 	// HealthEndpoint is the health check endpoint
 	HealthEndpoint = "/health"
-
-	// HTTP server timeout constants
-	HTTPReadTimeout  = 30 * time.Second
-	HTTPWriteTimeout = 0                  // No timeout for streaming
-	HTTPIdleTimeout  = 120 * time.Second
 )
 
-// StartStdio starts the MCP server with stdio transport
+// StartStdio starts the MCP server with stdio transport. When the client
+// disconnects, by closing stdin or the stdout pipe, in-flight tool calls
+// are given StdioDrainTimeout to finish and StartStdio returns nil, so a
+// client restart is not reported as a crash. On a shutdown signal they are
+// given MCP_SHUTDOWN_GRACE_PERIOD, see Drain.
 func (s *Server) StartStdio(ctx context.Context) error {
-	slog.Info("Starting MCP server with stdio transport")
+	logging.FromContext(ctx).Info("Starting MCP server with stdio transport")
+
+	// Writing to a closed stdout pipe would otherwise kill the process
+	// with SIGPIPE instead of returning EPIPE
+	signal.Ignore(syscall.SIGPIPE)
 
+	return s.serveStdio(ctx, os.Stdin, os.Stdout)
+}
+
+// serveStdio serves MCP over the given input and output streams
+func (s *Server) serveStdio(ctx context.Context, stdin io.Reader, stdout io.Writer) error {
 	// Create a channel to listen for shutdown signals
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
 
-	// Create error channel
-	errChan := make(chan error, 1)
+	gone := newClientGone()
+	listenCtx, cancelListen := context.WithCancel(context.Background())
+	defer cancelListen()
+
+	// Use the mark3labs MCP SDK stdio server
+	stdioServer := server.NewStdioServer(s.mcpServer)
+	stdioServer.SetErrorLogger(log.New(&stdioErrorLog{gone: gone}, "", 0))
 
 	// Start the stdio server in a goroutine
+	errChan := make(chan error, 1)
 	go func() {
-		slog.Debug("Starting stdio transport listener")
-		// Use the mark3labs MCP SDK stdio server
-		if err := server.ServeStdio(s.mcpServer); err != nil {
-			slog.Error("Stdio server error", "error", err)
-			errChan <- err
-		}
+		logging.FromContext(ctx).Debug("Starting stdio transport listener")
+		errChan <- stdioServer.Listen(listenCtx,
+			&stdinReader{r: stdin, gone: gone},
+			&stdoutWriter{w: stdout, gone: gone})
 	}()
 
-	// Wait for shutdown signal or error
+	// Wait for shutdown signal, client disconnect or error
 	select {
 	case <-ctx.Done():
-		slog.Info("Context cancelled, shutting down stdio server")
-		return nil
+		logging.FromContext(ctx).Info("Context cancelled, shutting down stdio server")
 	case sig := <-sigChan:
-		slog.Info("Received shutdown signal", "signal", sig)
+		logging.FromContext(ctx).Info("Received shutdown signal", "signal", sig)
+	case <-gone.done:
+		logging.FromContext(ctx).Info("Stdio client disconnected, finishing in-flight requests",
+			"timeout", StdioDrainTimeout)
+		select {
+		case err := <-errChan:
+			if err != nil && !isDisconnect(err) {
+				return fmt.Errorf("stdio server error: %w", err)
+			}
+		case <-time.After(StdioDrainTimeout):
+			logging.FromContext(ctx).Warn("In-flight requests did not finish in time, cancelling them")
+			cancelListen()
+		}
 		return nil
 	case err := <-errChan:
+		if err == nil || isDisconnect(err) {
+			logging.FromContext(ctx).Info("Stdio client disconnected")
+			return nil
+		}
 		return fmt.Errorf("stdio server error: %w", err)
 	}
+
+	s.Drain(ctx)
+	cancelListen()
+	return nil
 }
 
-// StartHTTP starts the MCP server with StreamableHTTP transport
+// StartHTTP starts the MCP server with StreamableHTTP transport. On
+// shutdown in-flight tool calls are drained before the HTTP server stops.
 func (s *Server) StartHTTP(ctx context.Context) error {
 	port := s.cfg.MCPHTTPPort
 	addr := ":" + port
-	slog.Info("Starting MCP server with StreamableHTTP transport",
+	logging.FromContext(ctx).Info("Starting MCP server with StreamableHTTP transport",
 		"port", port,
 		"endpoint", StreamableHTTPEndpoint,
-		"heartbeat_interval", HeartbeatInterval)
+		"heartbeat_interval", s.cfg.MCPHeartbeatInterval)
 
 	// Create the StreamableHTTP server from the SDK
 	// This replaces the legacy SSE transport with the modern HTTP streaming standard
 	streamableServer := server.NewStreamableHTTPServer(s.mcpServer,
 		server.WithEndpointPath(StreamableHTTPEndpoint),
-		server.WithHeartbeatInterval(HeartbeatInterval),
+		server.WithHeartbeatInterval(s.cfg.MCPHeartbeatInterval),
+		server.WithSessionIdManager(&sessionIDManager{sessions: s.sessions}),
 	)
 
 	// Create HTTP server mux
@@ -89,6 +126,9 @@ func (s *Server) StartHTTP(ctx context.Context) error {
 	// Setup health endpoint
 	mux.HandleFunc(HealthEndpoint, s.handleHealth)
 
+	// Setup metrics endpoint, behind authentication like the MCP endpoint
+	mux.Handle(MetricsEndpoint, s.metrics.Handler())
+
 	// Setup StreamableHTTP endpoint using the SDK's server
 	// StreamableHTTP handles POST (client messages), GET (server notifications), and DELETE (cleanup)
 	mux.Handle(StreamableHTTPEndpoint, streamableServer)
@@ -96,11 +136,13 @@ func (s *Server) StartHTTP(ctx context.Context) error {
 	// Create HTTP server with timeouts
 	httpServer := &http.Server{
 		Addr:         addr,
-		Handler:      s.authMiddleware(mux),
-		ReadTimeout:  HTTPReadTimeout,
-		WriteTimeout: HTTPWriteTimeout,
-		IdleTimeout:  HTTPIdleTimeout,
+		Handler:      s.originMiddleware(s.authMiddleware(mux)),
+		ReadTimeout:  s.cfg.MCPHTTPReadTimeout,
+		WriteTimeout: s.cfg.MCPHTTPWriteTimeout,
+		IdleTimeout:  s.cfg.MCPHTTPIdleTimeout,
+		TLSConfig:    &tls.Config{MinVersion: s.cfg.MCPTLSMinVersion},
 	}
+	useTLS := s.cfg.MCPTLSCertFile != ""
 
 	// Create a channel to listen for shutdown signals
 	sigChan := make(chan os.Signal, 1)
@@ -111,9 +153,15 @@ func (s *Server) StartHTTP(ctx context.Context) error {
 
 	// Start the HTTP server in a goroutine
 	go func() {
-		slog.Info("HTTP server listening", "addr", addr)
-		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			slog.Error("HTTP server error", "error", err)
+		logging.FromContext(ctx).Info("HTTP server listening", "addr", addr, "tls", useTLS)
+		var err error
+		if useTLS {
+			err = httpServer.ListenAndServeTLS(s.cfg.MCPTLSCertFile, s.cfg.MCPTLSKeyFile)
+		} else {
+			err = httpServer.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
+			logging.FromContext(ctx).Error("HTTP server error", "error", err)
 			errChan <- err
 		}
 	}()
@@ -121,32 +169,39 @@ func (s *Server) StartHTTP(ctx context.Context) error {
 	// Wait for shutdown signal, context cancellation, or error
 	select {
 	case <-ctx.Done():
-		slog.Info("Context cancelled, initiating HTTP server shutdown")
+		logging.FromContext(ctx).Info("Context cancelled, initiating HTTP server shutdown")
 	case sig := <-sigChan:
-		slog.Info("Received shutdown signal, initiating HTTP server shutdown", "signal", sig)
+		logging.FromContext(ctx).Info("Received shutdown signal, initiating HTTP server shutdown", "signal", sig)
 	case err := <-errChan:
 		return fmt.Errorf("HTTP server error: %w", err)
 	}
 
+	// Let running tool calls finish before connections are closed. The
+	// server keeps answering meanwhile, refusing new calls and reporting
+	// itself as draining on the health endpoint.
+	s.Drain(ctx)
+
 	// Graceful shutdown
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
 	defer cancel()
 
-	slog.Info("Shutting down HTTP server gracefully", "timeout", ShutdownTimeout)
+	logging.FromContext(ctx).Info("Shutting down HTTP server gracefully", "timeout", ShutdownTimeout)
 	if err := httpServer.Shutdown(shutdownCtx); err != nil {
-		slog.Error("HTTP server shutdown error", "error", err)
+		logging.FromContext(ctx).Error("HTTP server shutdown error", "error", err)
 		return fmt.Errorf("shutdown error: %w", err)
 	}
 
-	slog.Info("HTTP server shutdown complete")
+	logging.FromContext(ctx).Info("HTTP server shutdown complete")
 	return nil
 }
 
-// authMiddleware adds authentication if MCP_AUTH_TOKEN is configured
+// authMiddleware adds authentication if MCP_AUTH_TOKEN or
+// MCP_AUTH_TOKEN_MAP is configured. Tokens from the map additionally
+// switch the request's Paperless calls to the mapped Paperless token.
 func (s *Server) authMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// If no auth token is configured, skip authentication
-		if s.cfg.MCPAuthToken == "" {
+		if s.cfg.MCPAuthToken == "" && len(s.cfg.MCPAuthTokenMap) == 0 {
 			next.ServeHTTP(w, r)
 			return
 		}
@@ -159,19 +214,25 @@ func (s *Server) authMiddleware(next http.Handler) http.Handler {
 
 		// Check Authorization header
 		authHeader := r.Header.Get("Authorization")
-		expectedAuth := "Bearer " + s.cfg.MCPAuthToken
-
-		if authHeader != expectedAuth {
-			slog.Warn("Authentication failed",
+		bearerToken, hasBearer := strings.CutPrefix(authHeader, "Bearer ")
+
+		switch {
+		case hasBearer && s.cfg.MCPAuthToken != "" && bearerToken == s.cfg.MCPAuthToken:
+			// Shared token, uses the server's own Paperless token
+		case hasBearer && s.cfg.MCPAuthTokenMap[bearerToken] != "":
+			paperlessToken := s.cfg.MCPAuthTokenMap[bearerToken]
+			r = r.WithContext(paperless.WithToken(r.Context(), paperlessToken))
+		default:
+			logging.FromContext(r.Context()).Warn("Authentication failed",
 				"path", r.URL.Path,
-				"remote_addr", r.RemoteAddr)
+				"client_ip", s.clientIP(r))
 			http.Error(w, "Unauthorized", http.StatusUnauthorized)
 			return
 		}
 
-		slog.Debug("Authentication successful",
+		logging.FromContext(r.Context()).Debug("Authentication successful",
 			"path", r.URL.Path,
-			"remote_addr", r.RemoteAddr)
+			"client_ip", s.clientIP(r))
 
 		next.ServeHTTP(w, r)
 	})
@@ -184,7 +245,19 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// A degraded server still answers 200 so orchestrators do not restart
+	// it over an outage of Paperless
+	status, code := "ok", http.StatusOK
+	if s.health.state().Degraded {
+		status = "degraded"
+	}
+
+	// A draining server answers 503 so load balancers stop routing to it
+	if s.inflight.isDraining() {
+		status, code = "draining", http.StatusServiceUnavailable
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"status":"ok","server":"` + ServerName + `","version":"` + ServerVersion + `"}`))
+	w.WriteHeader(code)
+	w.Write([]byte(`{"status":"` + status + `","server":"` + ServerName + `","version":"` + ServerVersion + `"}`))
 }
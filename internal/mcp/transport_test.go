@@ -0,0 +1,177 @@
+package mcp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/config"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// TestClientIP tests that X-Forwarded-For is only honoured from trusted proxies
+func TestClientIP(t *testing.T) {
+	s := &Server{cfg: &config.Config{
+		MCPTrustedProxies: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+	}}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  string
+		expected   string
+	}{
+		{"untrusted peer ignores header", "203.0.113.5:4000", "198.51.100.1", "203.0.113.5"},
+		{"trusted peer uses header", "10.0.0.2:4000", "198.51.100.1", "198.51.100.1"},
+		{"skips chained trusted proxies", "10.0.0.2:4000", "198.51.100.1, 10.0.0.3", "198.51.100.1"},
+		{"spoofed leftmost entry ignored", "10.0.0.2:4000", "1.2.3.4, 198.51.100.1", "198.51.100.1"},
+		{"trusted peer without header", "10.0.0.2:4000", "", "10.0.0.2"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/mcp", nil)
+		req.RemoteAddr = tt.remoteAddr
+		if tt.forwarded != "" {
+			req.Header.Set(ForwardedForHeader, tt.forwarded)
+		}
+		if got := s.clientIP(req); got != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.expected, got)
+		}
+	}
+}
+
+// TestAuthMiddlewareTokenMap tests that mapped MCP tokens authenticate and
+// carry their Paperless token on the request context
+func TestAuthMiddlewareTokenMap(t *testing.T) {
+	s := &Server{cfg: &config.Config{
+		MCPAuthToken:    "shared",
+		MCPAuthTokenMap: map[string]string{"alice-mcp": "alice-paperless"},
+	}}
+
+	var gotToken string
+	var gotOverride bool
+	handler := s.authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotToken, gotOverride = paperless.TokenFromContext(r.Context())
+	}))
+
+	tests := []struct {
+		bearer       string
+		status       int
+		override     bool
+		overrideWith string
+	}{
+		{"shared", http.StatusOK, false, ""},
+		{"alice-mcp", http.StatusOK, true, "alice-paperless"},
+		{"alice-paperless", http.StatusUnauthorized, false, ""},
+		{"", http.StatusUnauthorized, false, ""},
+	}
+
+	for _, tt := range tests {
+		gotToken, gotOverride = "", false
+		req := httptest.NewRequest("POST", StreamableHTTPEndpoint, nil)
+		if tt.bearer != "" {
+			req.Header.Set("Authorization", "Bearer "+tt.bearer)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != tt.status {
+			t.Errorf("bearer %q: expected status %d, got %d", tt.bearer, tt.status, rec.Code)
+		}
+		if gotOverride != tt.override || gotToken != tt.overrideWith {
+			t.Errorf("bearer %q: expected override %v (%q), got %v (%q)",
+				tt.bearer, tt.override, tt.overrideWith, gotOverride, gotToken)
+		}
+	}
+}
+
+// TestOriginMiddleware tests that browser requests from foreign origins
+// are rejected while loopback, listed and origin-less requests pass
+func TestOriginMiddleware(t *testing.T) {
+	s := &Server{cfg: &config.Config{MCPAllowedOrigins: []string{"https://chat.example.com"}}}
+	handler := s.originMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		path   string
+		origin string
+		status int
+	}{
+		{StreamableHTTPEndpoint, "", http.StatusOK},
+		{StreamableHTTPEndpoint, "http://localhost:3000", http.StatusOK},
+		{StreamableHTTPEndpoint, "http://127.0.0.1:8080", http.StatusOK},
+		{StreamableHTTPEndpoint, "http://[::1]:8080", http.StatusOK},
+		{StreamableHTTPEndpoint, "https://Chat.Example.com/", http.StatusOK},
+		{StreamableHTTPEndpoint, "http://attacker.example", http.StatusForbidden},
+		{StreamableHTTPEndpoint, "http://localhost.attacker.example", http.StatusForbidden},
+		{StreamableHTTPEndpoint, "null", http.StatusForbidden},
+		{HealthEndpoint, "http://attacker.example", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", tt.path, nil)
+		if tt.origin != "" {
+			req.Header.Set("Origin", tt.origin)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.status {
+			t.Errorf("%s from %q: expected status %d, got %d", tt.path, tt.origin, tt.status, rec.Code)
+		}
+	}
+
+	s.cfg.MCPAllowedOrigins = []string{"*"}
+	req := httptest.NewRequest("POST", StreamableHTTPEndpoint, nil)
+	req.Header.Set("Origin", "http://attacker.example")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected \"*\" to allow any origin, got status %d", rec.Code)
+	}
+}
+
+// TestServeStdioClientDisconnect tests that a client closing stdin or the
+// stdout pipe ends the stdio server without an error
+func TestServeStdioClientDisconnect(t *testing.T) {
+	s, err := New(&config.Config{
+		PaperlessURL:   "http://localhost:8000",
+		PaperlessToken: "test-token",
+		MCPTransport:   "stdio",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	serve := func(stdin io.Reader, stdout io.Writer) chan error {
+		done := make(chan error, 1)
+		go func() { done <- s.serveStdio(context.Background(), stdin, stdout) }()
+		return done
+	}
+	wait := func(name string, done chan error) {
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Errorf("%s: expected nil error, got %v", name, err)
+			}
+		case <-time.After(StdioDrainTimeout + time.Second):
+			t.Errorf("%s: server did not stop", name)
+		}
+	}
+
+	// Client closes stdin
+	inReader, inWriter := io.Pipe()
+	done := serve(inReader, io.Discard)
+	inWriter.Close()
+	wait("stdin closed", done)
+
+	// Client closes the pipe responses are written to
+	inReader, inWriter = io.Pipe()
+	outReader, outWriter := io.Pipe()
+	outReader.Close()
+	done = serve(inReader, outWriter)
+	go inWriter.Write([]byte(`{"jsonrpc":"2.0","id":1,"method":"ping"}` + "\n"))
+	wait("stdout closed", done)
+}
@@ -0,0 +1,272 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// intListArg reads an optional array of integer IDs argument
+func intListArg(args map[string]interface{}, name string) ([]int, error) {
+	raw, ok := args[name].([]interface{})
+	if !ok {
+		return nil, nil
+	}
+	ids := make([]int, len(raw))
+	for i, value := range raw {
+		id, ok := value.(float64)
+		if !ok {
+			return nil, fmt.Errorf("%s must contain only integers", name)
+		}
+		ids[i] = int(id)
+	}
+	return ids, nil
+}
+
+// inboxTags returns the tags flagged is_inbox_tag
+func (s *Server) inboxTags(ctx context.Context) ([]paperless.Tag, error) {
+	tags, err := s.metadata.tags.get(ctx, s.paperlessClient)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to list tags", "error", err)
+		return nil, fmt.Errorf("failed to list inbox tags: %w", err)
+	}
+	var inbox []paperless.Tag
+	for _, t := range tags {
+		if t.IsInboxTag {
+			inbox = append(inbox, t)
+		}
+	}
+	return inbox, nil
+}
+
+// triageTags returns the document's tags after adding add and, when
+// removeInbox is set, removing inbox tags not explicitly added, along with
+// the inbox tags removed
+func triageTags(current, add []int, inbox map[int]bool, removeInbox bool) ([]int, []int) {
+	adding := make(map[int]bool, len(add))
+	for _, id := range add {
+		adding[id] = true
+	}
+
+	tags := []int{}
+	removed := []int{}
+	seen := make(map[int]bool)
+	for _, id := range append(append([]int{}, current...), add...) {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if removeInbox && inbox[id] && !adding[id] {
+			removed = append(removed, id)
+			continue
+		}
+		tags = append(tags, id)
+	}
+	return tags, removed
+}
+
+// handleTriageDocument handles the triage_document tool, applying in one
+// update what processing an inbox document takes: tags, correspondent,
+// document type, storage path, removing inbox tags and assigning an ASN
+func (s *Server) handleTriageDocument(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	documentIDFloat, ok := args["document_id"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("document_id parameter is required and must be an integer")
+	}
+	documentID := int(documentIDFloat)
+	if documentID < 1 {
+		return nil, fmt.Errorf("document_id must be a positive integer")
+	}
+
+	addTags, err := intListArg(args, "add_tags")
+	if err != nil {
+		return nil, err
+	}
+	removeInbox := true
+	if v, ok := args["remove_inbox_tags"].(bool); ok {
+		removeInbox = v
+	}
+	assignASN, _ := args["assign_asn"].(bool)
+	asnVal, hasASN := args["archive_serial_number"].(float64)
+	if assignASN && hasASN {
+		return nil, fmt.Errorf("pass either archive_serial_number or assign_asn, not both")
+	}
+
+	logging.FromContext(ctx).Debug("Triaging document",
+		"document_id", documentID,
+		"add_tags", len(addTags),
+		"remove_inbox_tags", removeInbox)
+
+	// Read the document fresh; the new tag list is derived from its tags
+	document, err := s.paperlessClient.GetDocument(ctx, documentID)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to get document", "document_id", documentID, "error", err)
+		return nil, fmt.Errorf("failed to get document: %w", err)
+	}
+
+	updates := make(map[string]interface{})
+	previous := make(map[string]interface{})
+	for _, field := range []struct {
+		arg     string
+		current *int
+	}{
+		{"correspondent", document.Correspondent},
+		{"document_type", document.DocumentType},
+		{"storage_path", document.StoragePath},
+	} {
+		if id, ok := args[field.arg].(float64); ok {
+			updates[field.arg] = int(id)
+			previous[field.arg] = field.current
+		}
+	}
+	if title, ok := args["title"].(string); ok && title != "" {
+		updates["title"] = title
+		previous["title"] = document.Title
+	}
+
+	inbox := make(map[int]bool)
+	if removeInbox {
+		inboxTags, err := s.inboxTags(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range inboxTags {
+			inbox[t.ID] = true
+		}
+	}
+	tags, removed := triageTags(document.Tags, addTags, inbox, removeInbox)
+	if len(addTags) > 0 || len(removed) > 0 {
+		updates["tags"] = tags
+		previous["tags"] = document.Tags
+	}
+
+	// An ASN is only assigned when the document has none, so triaging twice
+	// does not renumber it
+	switch {
+	case hasASN:
+		asn := int(asnVal)
+		if err := s.checkASNAvailable(ctx, asn, documentID); err != nil {
+			return nil, err
+		}
+		updates["archive_serial_number"] = asn
+		previous["archive_serial_number"] = document.ArchiveSerialNumber
+	case assignASN && document.ArchiveSerialNumber == nil:
+		asn, err := s.paperlessClient.NextASN(ctx)
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to get next archive serial number", "error", err)
+			return nil, fmt.Errorf("failed to get next archive serial number: %w", err)
+		}
+		updates["archive_serial_number"] = asn
+		previous["archive_serial_number"] = nil
+	}
+
+	if len(updates) == 0 {
+		return nil, fmt.Errorf("nothing to do: document %d has no inbox tags and no changes were requested", documentID)
+	}
+
+	// The reads above are cheap and decide the update, so a plan makes
+	// them and stops short of the update itself
+	if planOnly(args) {
+		plan := newOperationPlan("triage_document")
+		plan.performed(fmt.Sprintf("/api/documents/%d/", documentID), "Read the document's current tags and fields")
+		if removeInbox {
+			plan.performed("/api/tags/", "Find the inbox tags to remove")
+		}
+		switch {
+		case hasASN:
+			plan.performed(fmt.Sprintf("/api/documents/?archive_serial_number=%d&page_size=1", int(asnVal)),
+				"Check the archive serial number is not assigned to another document")
+		case assignASN && document.ArchiveSerialNumber == nil:
+			plan.performed("/api/documents/next_asn/", "Get the next free archive serial number")
+			plan.note("The next archive serial number may change if another document is given one before the update")
+		}
+		plan.write(http.MethodPatch, fmt.Sprintf("/api/documents/%d/", documentID), updates, "Apply the triage in one update")
+		return plan, nil
+	}
+
+	updatedDocument, err := s.paperlessClient.UpdateDocument(ctx, documentID, updates)
+	// Invalidate even on failure; the change may still have been applied
+	s.documents.invalidate(documentID)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to triage document",
+			"document_id", documentID,
+			"error", err)
+		return nil, fmt.Errorf("failed to triage document: %w", err)
+	}
+
+	s.journal.recordUpdate(ctx, "triage_document", "documents", documentID, previous,
+		fmt.Sprintf("Triaged document %d", documentID))
+
+	fields := make([]string, 0, len(updates))
+	for field := range updates {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	logging.FromContext(ctx).Info("Document triaged",
+		"document_id", documentID,
+		"fields", len(updates),
+		"inbox_tags_removed", len(removed))
+
+	return map[string]interface{}{
+		"document":           updatedDocument,
+		"updated_fields":     fields,
+		"removed_inbox_tags": removed,
+	}, nil
+}
+
+// handleMarkDocumentsProcessed handles the mark_documents_processed tool,
+// removing every inbox tag from the given documents through a bulk edit so
+// that chunking, confirmation and undo work as for bulk_edit_documents
+func (s *Server) handleMarkDocumentsProcessed(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	documentIDs, ok := args["document_ids"].([]interface{})
+	if !ok || len(documentIDs) == 0 {
+		return nil, fmt.Errorf("document_ids parameter is required and must be a non-empty array")
+	}
+
+	inboxTags, err := s.inboxTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(inboxTags) == 0 {
+		return nil, fmt.Errorf("no tags are flagged as inbox tags, so there is nothing to remove")
+	}
+	tagIDs := make([]interface{}, len(inboxTags))
+	removed := make([]map[string]interface{}, len(inboxTags))
+	for i, t := range inboxTags {
+		tagIDs[i] = float64(t.ID)
+		removed[i] = map[string]interface{}{"id": t.ID, "name": t.Name}
+	}
+
+	logging.FromContext(ctx).Debug("Marking documents processed",
+		"document_count", len(documentIDs),
+		"inbox_tags", len(inboxTags))
+
+	result, err := s.handleBulkEditDocuments(ctx, map[string]interface{}{
+		"document_ids": documentIDs,
+		"remove_tags":  tagIDs,
+		"plan_only":    planOnly(args),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if plan, ok := result.(*operationPlan); ok {
+		plan.Tool = "mark_documents_processed"
+		plan.prepend(plannedCall{
+			Method:    http.MethodGet,
+			Path:      "/api/tags/",
+			Purpose:   "Find the inbox tags to remove",
+			Performed: true,
+		})
+		return plan, nil
+	}
+
+	return map[string]interface{}{
+		"inbox_tags_removed": removed,
+		"bulk_edit":          result,
+	}, nil
+}
@@ -0,0 +1,113 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/config"
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// TestTriageDocument tests that triage applies tags, metadata, inbox tag
+// removal and the next ASN in a single update
+func TestTriageDocument(t *testing.T) {
+	var patches []map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPatch && r.URL.Path == "/api/documents/5/":
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			patches = append(patches, body)
+			w.Write([]byte(`{"id":5,"title":"Invoice","tags":[2,7]}`))
+		case r.URL.Path == "/api/documents/5/":
+			w.Write([]byte(`{"id":5,"title":"Invoice","tags":[1,2],"archive_serial_number":null}`))
+		case r.URL.Path == "/api/documents/next_asn/":
+			w.Write([]byte(`42`))
+		case r.URL.Path == "/api/tags/":
+			w.Write([]byte(`{"count":2,"next":null,"results":[{"id":1,"name":"Inbox","is_inbox_tag":true},{"id":2,"name":"Bills"}]}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	s := &Server{
+		paperlessClient: paperless.New(ts.URL, "test-token"),
+		journal:         newUndoJournal(""),
+		documents:       newDocumentCache(0, 0),
+	}
+	result, err := s.handleTriageDocument(context.Background(), map[string]interface{}{
+		"document_id":   float64(5),
+		"add_tags":      []interface{}{float64(7)},
+		"correspondent": float64(3),
+		"assign_asn":    true,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(patches) != 1 {
+		t.Fatalf("Expected a single update, got %d", len(patches))
+	}
+	patch := patches[0]
+	tags, _ := json.Marshal(patch["tags"])
+	if string(tags) != "[2,7]" {
+		t.Errorf("Expected the inbox tag removed and tag 7 added, got %s", tags)
+	}
+	if patch["correspondent"] != float64(3) || patch["archive_serial_number"] != float64(42) {
+		t.Errorf("Expected correspondent 3 and ASN 42, got %v", patch)
+	}
+	if removed := result.(map[string]interface{})["removed_inbox_tags"].([]int); len(removed) != 1 || removed[0] != 1 {
+		t.Errorf("Expected inbox tag 1 reported as removed, got %v", removed)
+	}
+}
+
+// TestTriageTags tests that an inbox tag explicitly added is kept
+func TestTriageTags(t *testing.T) {
+	tags, removed := triageTags([]int{1, 2}, []int{1, 3}, map[int]bool{1: true}, true)
+	if len(tags) != 3 || len(removed) != 0 {
+		t.Errorf("Expected tags 1, 2 and 3 with none removed, got %v and %v", tags, removed)
+	}
+}
+
+// TestMarkDocumentsProcessed tests that every inbox tag is removed from the
+// documents in a bulk edit
+func TestMarkDocumentsProcessed(t *testing.T) {
+	var bulkEdit map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/tags/":
+			w.Write([]byte(`{"count":3,"next":null,"results":[{"id":1,"name":"Inbox","is_inbox_tag":true},{"id":2,"name":"Bills"},{"id":4,"name":"Scanned","is_inbox_tag":true}]}`))
+		case "/api/documents/bulk_edit/":
+			json.NewDecoder(r.Body).Decode(&bulkEdit)
+			w.Write([]byte(`{"result":"OK"}`))
+		default:
+			w.Write([]byte(`{"count":0,"results":[]}`))
+		}
+	}))
+	defer ts.Close()
+
+	s := &Server{
+		cfg:             &config.Config{MCPBulkChunkSize: 100, MCPConfirmThreshold: 50},
+		paperlessClient: paperless.New(ts.URL, "test-token"),
+		journal:         newUndoJournal(""),
+		documents:       newDocumentCache(0, 0),
+	}
+	result, err := s.handleMarkDocumentsProcessed(context.Background(), map[string]interface{}{
+		"document_ids": []interface{}{float64(5), float64(6)},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if removeTags, _ := json.Marshal(bulkEdit["remove_tags"]); string(removeTags) != "[1,4]" {
+		t.Errorf("Expected inbox tags 1 and 4 removed, got %v", bulkEdit)
+	}
+	if removed := result.(map[string]interface{})["inbox_tags_removed"].([]map[string]interface{}); len(removed) != 2 {
+		t.Errorf("Expected two inbox tags reported, got %v", removed)
+	}
+}
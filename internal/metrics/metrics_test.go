@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestRegistryWrite tests the text format of counters and histograms
+func TestRegistryWrite(t *testing.T) {
+	r := NewRegistry()
+	counter := r.NewCounterVec("requests_total", "Requests.", "endpoint", "class")
+	histogram := r.NewHistogramVec("duration_seconds", "Durations.", []float64{1, 0.1}, "endpoint")
+
+	counter.Inc("/api/", "ok")
+	counter.Inc("/api/", "ok")
+	counter.Inc(`/a"b/`, "5xx")
+	histogram.Observe(0.05, "/api/")
+	histogram.Observe(0.5, "/api/")
+	histogram.Observe(5, "/api/")
+
+	var b strings.Builder
+	r.Write(&b)
+	want := `# HELP requests_total Requests.
+# TYPE requests_total counter
+requests_total{endpoint="/a\"b/",class="5xx"} 1
+requests_total{endpoint="/api/",class="ok"} 2
+# HELP duration_seconds Durations.
+# TYPE duration_seconds histogram
+duration_seconds_bucket{endpoint="/api/",le="0.1"} 1
+duration_seconds_bucket{endpoint="/api/",le="1"} 2
+duration_seconds_bucket{endpoint="/api/",le="+Inf"} 3
+duration_seconds_sum{endpoint="/api/"} 5.55
+duration_seconds_count{endpoint="/api/"} 3
+`
+	if b.String() != want {
+		t.Errorf("Unexpected output:\n%s\nwant:\n%s", b.String(), want)
+	}
+	if counter.Value("/api/", "ok") != 2 {
+		t.Errorf("Expected a value of 2, got %v", counter.Value("/api/", "ok"))
+	}
+}
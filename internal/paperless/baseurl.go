@@ -0,0 +1,192 @@
+package paperless
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// MaxPrefixSegments bounds how deep a path prefix ResolveBaseURL looks for
+// the API under, when the configured URL points at the wrong path
+const MaxPrefixSegments = 3
+
+// ErrNotPaperlessAPI is returned by ResolveBaseURL when the server answers
+// but no Paperless API was found at or under the configured URL
+var ErrNotPaperlessAPI = errors.New("no Paperless API found")
+
+// ErrOffHostRedirect is returned by ResolveBaseURL when the API was found
+// only by following a redirect to another host, which the client does not
+// switch to on its own since the token would go to that host
+var ErrOffHostRedirect = errors.New("Paperless API redirects to another host")
+
+// BaseURL returns the URL requests are sent under
+func (c *Client) BaseURL() string {
+	c.baseMu.RLock()
+	defer c.baseMu.RUnlock()
+	return c.baseURL
+}
+
+// SetBaseURL changes the URL requests are sent under, e.g. to the one
+// ResolveBaseURL found
+func (c *Client) SetBaseURL(baseURL string) {
+	c.baseMu.Lock()
+	defer c.baseMu.Unlock()
+	c.baseURL = strings.TrimRight(baseURL, "/")
+}
+
+// NormalizeBaseURL trims trailing slashes and a trailing /api from a
+// configured Paperless URL, which should name where Paperless is installed
+// rather than its API
+func NormalizeBaseURL(baseURL string) string {
+	baseURL = strings.TrimRight(baseURL, "/")
+	baseURL = strings.TrimSuffix(baseURL, "/api")
+	return strings.TrimRight(baseURL, "/")
+}
+
+// apiProbe is the outcome of requesting the API root under one base URL
+type apiProbe struct {
+	baseURL    string // where the API answered, after redirects
+	finalURL   *url.URL
+	statusCode int
+	isAPI      bool
+}
+
+// probeAPI requests baseURL/api/, following redirects. The Paperless API
+// answers in JSON, either with its endpoints or, for a rejected token,
+// with 401 or 403; other answers are a proxy, the web UI or another app.
+func (c *Client) probeAPI(ctx context.Context, baseURL string) (*apiProbe, error) {
+	return c.probe(ctx, baseURL+"/api/", func(resp *http.Response) bool {
+		mediaType, _, _ := mime.ParseMediaType(resp.Header.Get(ContentTypeHeader))
+		switch {
+		case mediaType != ContentTypeJSON:
+			return false
+		case resp.StatusCode >= 200 && resp.StatusCode < 300:
+			return true
+		}
+		return resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden
+	})
+}
+
+// probe requests target through the interceptor chain and reports where
+// it ended up and whether isAPI accepts the response
+func (c *Client) probe(ctx context.Context, target string, isAPI func(*http.Response) bool) (*apiProbe, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := c.roundTrip(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, c.maxResponseSize))
+
+	final := req.URL
+	if resp.Request != nil {
+		final = resp.Request.URL
+	}
+	result := &apiProbe{finalURL: final, statusCode: resp.StatusCode}
+
+	// The API root ends in /api/; a redirect elsewhere, e.g. to a login
+	// page, means the API is not here
+	if !strings.HasSuffix(final.Path, "/api/") && !strings.HasSuffix(final.Path, "/api") {
+		return result, nil
+	}
+	result.isAPI = isAPI(resp)
+	base := *final
+	base.Path = strings.TrimSuffix(strings.TrimSuffix(final.Path, "/"), "/api")
+	base.RawPath, base.RawQuery, base.Fragment = "", "", ""
+	result.baseURL = strings.TrimRight(base.String(), "/")
+	return result, nil
+}
+
+// sameHost reports whether a base URL found by following redirects stays
+// on the configured host: the same scheme and host, or an upgrade from
+// http to https of the same host name
+func sameHost(configured, found string) bool {
+	from, err := url.Parse(configured)
+	if err != nil {
+		return false
+	}
+	to, err := url.Parse(found)
+	if err != nil {
+		return false
+	}
+	switch {
+	case from.Scheme == to.Scheme:
+		return strings.EqualFold(from.Host, to.Host)
+	case from.Scheme == "http" && to.Scheme == "https":
+		return strings.EqualFold(from.Hostname(), to.Hostname())
+	}
+	return false
+}
+
+// ResolveBaseURL checks that the Paperless API answers under the client's
+// base URL and returns the base URL it actually answers under. Redirects
+// are followed, so an http URL upgraded to https or a host redirecting to
+// its subpath install resolves to where Paperless lives; trailing slashes
+// and a trailing /api are ignored. When the API does not answer at the
+// configured path, the path prefixes of the server's root redirect are
+// tried, for Paperless installed under e.g. https://host/paperless.
+//
+// It returns ErrNotPaperlessAPI when the server answers but no API was
+// found, ErrOffHostRedirect when the API was found on another host, and
+// the request error or ErrUnavailable when the server cannot be reached
+// or is failing.
+func (c *Client) ResolveBaseURL(ctx context.Context) (string, error) {
+	configured := NormalizeBaseURL(c.BaseURL())
+	found := func(baseURL string) (string, error) {
+		if !sameHost(configured, baseURL) {
+			return "", fmt.Errorf("%w: %s/api/ redirects to %s/api/; set PAPERLESS_URL to it if that host is trusted", ErrOffHostRedirect, configured, baseURL)
+		}
+		return baseURL, nil
+	}
+
+	direct, err := c.probeAPI(ctx, configured)
+	if err != nil {
+		return "", err
+	}
+	if direct.isAPI {
+		return found(direct.baseURL)
+	}
+	if direct.statusCode >= 500 {
+		return "", fmt.Errorf("%w: HTTP %d from %s", ErrUnavailable, direct.statusCode, direct.finalURL.Redacted())
+	}
+
+	// Follow the root redirect, typically to the web UI or its login page
+	// under the installation's prefix, and look for the API under each
+	// prefix of where it ends up; the token is not sent to another host
+	root, err := c.probe(ctx, configured+"/", func(*http.Response) bool { return false })
+	if err != nil {
+		return "", err
+	}
+	segments := strings.Split(strings.Trim(root.finalURL.Path, "/"), "/")
+	if !sameHost(configured, root.finalURL.Scheme+"://"+root.finalURL.Host) {
+		segments = nil
+	}
+	tried := map[string]bool{configured: true}
+	for i := 1; i <= min(len(segments), MaxPrefixSegments); i++ {
+		candidate := *root.finalURL
+		candidate.Path = "/" + strings.Join(segments[:i], "/")
+		candidate.RawPath, candidate.RawQuery, candidate.Fragment = "", "", ""
+		if segments[0] == "" || tried[candidate.String()] {
+			continue
+		}
+		tried[candidate.String()] = true
+
+		probe, err := c.probeAPI(ctx, candidate.String())
+		if err != nil {
+			return "", err
+		}
+		if probe.isAPI {
+			return found(probe.baseURL)
+		}
+	}
+
+	return "", fmt.Errorf("%w at %s/api/ (HTTP %d from %s)", ErrNotPaperlessAPI, configured, direct.statusCode, direct.finalURL.Redacted())
+}
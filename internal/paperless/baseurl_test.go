@@ -0,0 +1,126 @@
+package paperless
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// subpathPaperless serves the Paperless API under /paperless, the web UI
+// login page for the root and 404 pages elsewhere, like a reverse proxy
+// with Paperless installed under a subpath
+func subpathPaperless() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/paperless/api/":
+			w.Header().Set(ContentTypeHeader, ContentTypeJSON)
+			if r.Header.Get(AuthHeaderName) != "Token test-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+			w.Write([]byte(`{"documents": "/paperless/api/documents/"}`))
+		case "/":
+			http.Redirect(w, r, "/paperless/accounts/login/?next=/paperless/", http.StatusFound)
+		case "/legacy/api/":
+			http.Redirect(w, r, "/paperless/api/", http.StatusMovedPermanently)
+		default:
+			w.Header().Set(ContentTypeHeader, "text/html")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("<html>Not found</html>"))
+		}
+	}))
+}
+
+// TestResolveBaseURL tests that the base URL the API answers under is found
+// from trailing slashes, an /api suffix, redirects and subpath installs
+func TestResolveBaseURL(t *testing.T) {
+	ts := subpathPaperless()
+	defer ts.Close()
+
+	for _, tc := range []struct {
+		name       string
+		configured string
+		token      string
+	}{
+		{"exact", ts.URL + "/paperless", "test-token"},
+		{"trailing slashes", ts.URL + "/paperless//", "test-token"},
+		{"api suffix", ts.URL + "/paperless/api/", "test-token"},
+		{"redirect", ts.URL + "/legacy", "test-token"},
+		{"subpath missing", ts.URL, "test-token"},
+		{"subpath missing, rejected token", ts.URL + "/", "wrong"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := New(tc.configured, tc.token).ResolveBaseURL(context.Background())
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != ts.URL+"/paperless" {
+				t.Errorf("Expected %s/paperless, got %s", ts.URL, got)
+			}
+		})
+	}
+
+	// A wrong subpath is not guessed at
+	if _, err := New(ts.URL+"/elsewhere", "test-token").ResolveBaseURL(context.Background()); !errors.Is(err, ErrNotPaperlessAPI) {
+		t.Errorf("Expected ErrNotPaperlessAPI for a wrong subpath, got %v", err)
+	}
+}
+
+// TestResolveBaseURLNotFound tests that a server without the API and a
+// failing server are told apart
+func TestResolveBaseURLNotFound(t *testing.T) {
+	status := http.StatusNotFound
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(ContentTypeHeader, "text/html")
+		w.WriteHeader(status)
+	}))
+	defer ts.Close()
+
+	client := New(ts.URL, "test-token")
+	if _, err := client.ResolveBaseURL(context.Background()); !errors.Is(err, ErrNotPaperlessAPI) {
+		t.Errorf("Expected ErrNotPaperlessAPI, got %v", err)
+	}
+
+	status = http.StatusBadGateway
+	if _, err := client.ResolveBaseURL(context.Background()); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Expected ErrUnavailable, got %v", err)
+	}
+}
+
+// TestResolveBaseURLOffHost tests that the API found by a redirect to
+// another host is reported rather than resolved to
+func TestResolveBaseURLOffHost(t *testing.T) {
+	other := subpathPaperless()
+	defer other.Close()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, other.URL+"/paperless"+r.URL.Path, http.StatusFound)
+	}))
+	defer ts.Close()
+
+	if _, err := New(ts.URL, "test-token").ResolveBaseURL(context.Background()); !errors.Is(err, ErrOffHostRedirect) {
+		t.Errorf("Expected ErrOffHostRedirect, got %v", err)
+	}
+}
+
+// TestSameHost tests which redirects keep a base URL on its host
+func TestSameHost(t *testing.T) {
+	for _, tc := range []struct {
+		found string
+		want  bool
+	}{
+		{"http://paperless.lan:8000/paperless", true},
+		{"https://PAPERLESS.lan", true},
+		{"https://paperless.lan:8443", true},
+		{"http://paperless.lan:9000", false},
+		{"http://evil.example", false},
+		{"ftp://paperless.lan:8000", false},
+	} {
+		if got := sameHost("http://paperless.lan:8000", tc.found); got != tc.want {
+			t.Errorf("sameHost(%s) = %v, want %v", tc.found, got, tc.want)
+		}
+	}
+	if sameHost("https://paperless.lan", "http://paperless.lan") {
+		t.Error("Expected a downgrade to http to be refused")
+	}
+}
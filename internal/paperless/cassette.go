@@ -0,0 +1,222 @@
+package paperless
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+)
+
+// Cassette modes
+const (
+	CassetteModeRecord = "record"
+	CassetteModeReplay = "replay"
+)
+
+// recordedHeaders are the response headers kept in a cassette; everything
+// else (cookies, server banners) is dropped
+var recordedHeaders = []string{ContentTypeHeader, RetryAfterHeader}
+
+// Interaction is a single recorded request/response pair. Request
+// headers, including Authorization, are never recorded.
+type Interaction struct {
+	Method          string            `json:"method"`
+	Path            string            `json:"path"`
+	RequestBody     string            `json:"request_body,omitempty"`
+	StatusCode      int               `json:"status_code"`
+	ResponseHeaders map[string]string `json:"response_headers,omitempty"`
+	ResponseBody    string            `json:"response_body"`
+}
+
+// Cassette is a file of recorded Paperless interactions
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Recorder records every Paperless interaction to a cassette file
+type Recorder struct {
+	path string
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder creates a recorder that writes to the cassette at path,
+// replacing any existing file
+func NewRecorder(path string) (*Recorder, error) {
+	r := &Recorder{path: path}
+	if err := r.save(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Interceptor returns the interceptor that records interactions. The
+// cassette is rewritten after every interaction so nothing is lost if the
+// process exits abruptly.
+func (r *Recorder) Interceptor(next RoundTripFunc) RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		requestBody, err := readRequestBody(req)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := next(req)
+		if err != nil {
+			return resp, err
+		}
+
+		responseBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response for recording: %w", err)
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(responseBody))
+
+		interaction := Interaction{
+			Method:          req.Method,
+			Path:            req.URL.RequestURI(),
+			RequestBody:     requestBody,
+			StatusCode:      resp.StatusCode,
+			ResponseHeaders: make(map[string]string),
+			ResponseBody:    string(responseBody),
+		}
+		for _, name := range recordedHeaders {
+			if value := resp.Header.Get(name); value != "" {
+				interaction.ResponseHeaders[name] = value
+			}
+		}
+
+		r.mu.Lock()
+		r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+		saveErr := r.save()
+		r.mu.Unlock()
+		if saveErr != nil {
+			logging.FromContext(req.Context()).Error("Failed to save cassette", "path", r.path, "error", saveErr)
+		}
+
+		return resp, nil
+	}
+}
+
+// save writes the cassette to disk; callers must hold r.mu
+func (r *Recorder) save() error {
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cassette: %w", err)
+	}
+	if err := os.WriteFile(r.path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// Replayer serves responses from a cassette instead of contacting Paperless
+type Replayer struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewReplayer loads the cassette at path for replay
+func NewReplayer(path string) (*Replayer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette: %w", err)
+	}
+
+	return &Replayer{
+		interactions: cassette.Interactions,
+		used:         make([]bool, len(cassette.Interactions)),
+	}, nil
+}
+
+// Interceptor returns the interceptor that replays interactions. Each
+// request is matched to the first unused interaction with the same method,
+// path and body; once all matching interactions have been used the last
+// one is replayed again, so read-only requests can repeat freely.
+func (r *Replayer) Interceptor(next RoundTripFunc) RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		requestBody, err := readRequestBody(req)
+		if err != nil {
+			return nil, err
+		}
+		path := req.URL.RequestURI()
+
+		r.mu.Lock()
+		match := -1
+		for i, interaction := range r.interactions {
+			if interaction.Method != req.Method || interaction.Path != path || interaction.RequestBody != requestBody {
+				continue
+			}
+			match = i
+			if !r.used[i] {
+				break
+			}
+		}
+		if match >= 0 {
+			r.used[match] = true
+		}
+		r.mu.Unlock()
+
+		if match < 0 {
+			return nil, fmt.Errorf("no recorded interaction for %s %s", req.Method, path)
+		}
+
+		interaction := r.interactions[match]
+		header := make(http.Header)
+		for name, value := range interaction.ResponseHeaders {
+			header.Set(name, value)
+		}
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.StatusCode, http.StatusText(interaction.StatusCode)),
+			StatusCode:    interaction.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader([]byte(interaction.ResponseBody))),
+			ContentLength: int64(len(interaction.ResponseBody)),
+			Request:       req,
+		}, nil
+	}
+}
+
+// readRequestBody reads the request body without consuming it for the
+// next interceptor
+func readRequestBody(req *http.Request) (string, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return "", nil
+	}
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return "", fmt.Errorf("failed to read request body: %w", err)
+		}
+		defer body.Close()
+		data, err := io.ReadAll(body)
+		if err != nil {
+			return "", fmt.Errorf("failed to read request body: %w", err)
+		}
+		return string(data), nil
+	}
+
+	data, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return "", fmt.Errorf("failed to read request body: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	return string(data), nil
+}
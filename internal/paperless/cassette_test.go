@@ -0,0 +1,69 @@
+package paperless
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestCassetteRecordAndReplay tests that recorded interactions can be
+// replayed without contacting Paperless
+func TestCassetteRecordAndReplay(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(ContentTypeHeader, ContentTypeJSON)
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": 5, "name": "Bills", "color": "#ff0000"}`))
+			return
+		}
+		w.Write([]byte(`{"id": 5, "name": "Bills", "color": "#ff0000"}`))
+	}))
+
+	cassettePath := filepath.Join(t.TempDir(), "cassette.json")
+
+	recorder, err := NewRecorder(cassettePath)
+	if err != nil {
+		t.Fatalf("Failed to create recorder: %v", err)
+	}
+	client := New(ts.URL, "secret-token")
+	client.Use(recorder.Interceptor)
+
+	if _, err := client.CreateTag(context.Background(), &Tag{Name: "Bills", Color: "#ff0000"}); err != nil {
+		t.Fatalf("Failed to record create: %v", err)
+	}
+	if _, err := client.GetTag(context.Background(), 5); err != nil {
+		t.Fatalf("Failed to record get: %v", err)
+	}
+	ts.Close()
+
+	data, err := os.ReadFile(cassettePath)
+	if err != nil {
+		t.Fatalf("Failed to read cassette: %v", err)
+	}
+	if strings.Contains(string(data), "secret-token") {
+		t.Error("Cassette must not contain the Paperless token")
+	}
+
+	replayer, err := NewReplayer(cassettePath)
+	if err != nil {
+		t.Fatalf("Failed to load cassette: %v", err)
+	}
+	replayClient := New(ts.URL, "")
+	replayClient.Use(replayer.Interceptor)
+
+	tag, err := replayClient.GetTag(context.Background(), 5)
+	if err != nil {
+		t.Fatalf("Failed to replay get: %v", err)
+	}
+	if tag.Name != "Bills" {
+		t.Errorf("Expected replayed tag 'Bills', got %q", tag.Name)
+	}
+
+	if _, err := replayClient.GetTag(context.Background(), 6); err == nil {
+		t.Error("Expected unrecorded request to fail")
+	}
+}
@@ -3,23 +3,34 @@ package paperless
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 	"net/url"
 )
 
 // Client constants
 const (
-	DefaultTimeout    = 30 * time.Second
-	AuthHeaderName    = "Authorization"
-	AuthTokenPrefix   = "Token"
-	ContentTypeJSON   = "application/json"
-	ContentTypeHeader = "Content-Type"
+	DefaultTimeout      = 30 * time.Second
+	AuthHeaderName      = "Authorization"
+	AuthTokenPrefix     = "Token"
+	ProxyAuthHeaderName = "Proxy-Authorization"
+	ContentTypeJSON     = "application/json"
+	ContentTypeHeader   = "Content-Type"
+)
+
+// Response size constants
+const (
+	// DefaultMaxResponseSize caps how much of a response body is read
+	DefaultMaxResponseSize int64 = 32 << 20 // 32 MiB
 )
 
 // Pagination constants
@@ -31,54 +42,201 @@ const (
 
 // Client represents a Paperless API client
 type Client struct {
-	baseURL    string
-	token      string
-	httpClient *http.Client
+	baseMu          sync.RWMutex
+	baseURL         string
+	tokenSource     TokenSource
+	httpClient      *http.Client
+	interceptors    []Interceptor
+	maxResponseSize int64
+	basicAuth       *basicAuth
+
+	transportWrappers []TransportWrapper
+	logger            *slog.Logger
 }
 
-// New creates a new Paperless API client
-func New(baseURL, token string) *Client {
-	return &Client{
-		baseURL: strings.TrimSuffix(baseURL, "/"),
-		token:   token,
+// basicAuth holds credentials for a reverse proxy in front of Paperless
+type basicAuth struct {
+	header string
+	value  string
+}
+
+// RoundTripFunc executes a prepared HTTP request and returns the response
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// Interceptor wraps a RoundTripFunc to add behaviour around every API
+// request, such as authentication, retries, metrics or caching.
+// Interceptors run in the order they were added; the first one added
+// is the outermost.
+type Interceptor func(next RoundTripFunc) RoundTripFunc
+
+// New creates a new Paperless API client, configured by opts
+func New(baseURL, token string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:     strings.TrimSuffix(baseURL, "/"),
+		tokenSource: StaticTokenSource(token),
 		httpClient: &http.Client{
-			Timeout: DefaultTimeout,
+			Timeout:   DefaultTimeout,
+			Transport: NewTransport(DefaultTransportOptions()),
 		},
+		maxResponseSize: DefaultMaxResponseSize,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.httpClient.Transport = c.wrapTransport(c.httpClient.Transport)
+	return c
+}
+
+// SetMaxResponseSize sets the maximum number of response body bytes the
+// client will read. Values less than 1 restore the default.
+func (c *Client) SetMaxResponseSize(size int64) {
+	if size < 1 {
+		size = DefaultMaxResponseSize
+	}
+	c.maxResponseSize = size
+}
+
+// SetTimeout sets the overall timeout of each request; 0 means none, for
+// when ApplyRequestPolicies bounds requests per endpoint class instead
+func (c *Client) SetTimeout(timeout time.Duration) {
+	c.httpClient.Timeout = timeout
+}
+
+// Use appends interceptors to the client's request chain
+func (c *Client) Use(interceptors ...Interceptor) {
+	c.interceptors = append(c.interceptors, interceptors...)
+}
+
+// SetTokenSource replaces the source of the Paperless API token
+func (c *Client) SetTokenSource(ts TokenSource) {
+	c.tokenSource = ts
+}
+
+// SetBasicAuth sends HTTP Basic credentials in the given header with every
+// request, for Paperless deployments behind a basic-auth protected reverse
+// proxy. With Proxy-Authorization (or another custom header) the Paperless
+// token is still sent in the Authorization header. With Authorization the
+// token is not sent at all, and Paperless itself must accept the basic
+// credentials.
+func (c *Client) SetBasicAuth(username, password, header string) {
+	if header == "" {
+		header = ProxyAuthHeaderName
+	}
+	credentials := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+	c.basicAuth = &basicAuth{header: http.CanonicalHeaderKey(header), value: "Basic " + credentials}
+}
+
+// authInterceptor adds the Paperless token authorization header. When
+// Paperless responds with 401 the token source is refreshed and, if that
+// yields a different token, the request is retried once. A token set on
+// the request context with WithToken takes precedence and is never refreshed.
+func (c *Client) authInterceptor(next RoundTripFunc) RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		if c.basicAuth != nil {
+			req.Header.Set(c.basicAuth.header, c.basicAuth.value)
+			if c.basicAuth.header == AuthHeaderName {
+				return next(req)
+			}
+		}
+
+		if token, ok := TokenFromContext(req.Context()); ok {
+			req.Header.Set(AuthHeaderName, fmt.Sprintf("%s %s", AuthTokenPrefix, token))
+			return next(req)
+		}
+
+		token, err := c.tokenSource.Token(req.Context())
+		if err != nil {
+			return nil, fmt.Errorf("failed to obtain Paperless token: %w", err)
+		}
+
+		retry, canRetry := cloneRequest(req)
+		req.Header.Set(AuthHeaderName, fmt.Sprintf("%s %s", AuthTokenPrefix, token))
+		resp, err := next(req)
+		if err != nil || resp.StatusCode != http.StatusUnauthorized || !canRetry {
+			return resp, err
+		}
+
+		refreshed, refreshErr := c.tokenSource.Refresh(req.Context())
+		if refreshErr != nil {
+			c.log(req.Context()).Warn("Failed to refresh Paperless token", "error", refreshErr)
+			return resp, nil
+		}
+		if refreshed == token {
+			return resp, nil
+		}
+
+		c.log(req.Context()).Info("Retrying request with refreshed Paperless token", "url", req.URL.String())
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		retry.Header.Set(AuthHeaderName, fmt.Sprintf("%s %s", AuthTokenPrefix, refreshed))
+		return next(retry)
 	}
 }
 
-// doRequest performs an HTTP request with authentication
+// cloneRequest returns a copy of req whose body can be sent again, and
+// false if the body cannot be re-created
+func cloneRequest(req *http.Request) (*http.Request, bool) {
+	clone := req.Clone(req.Context())
+	if req.Body != nil && req.Body != http.NoBody {
+		if req.GetBody == nil {
+			return nil, false
+		}
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, false
+		}
+		clone.Body = body
+	}
+	return clone, true
+}
+
+// roundTrip builds the interceptor chain around the HTTP client.
+// Authentication is always the innermost interceptor so that every
+// attempt made by outer interceptors (e.g. retries) is authenticated.
+func (c *Client) roundTrip(req *http.Request) (*http.Response, error) {
+	rt := c.authInterceptor(c.httpClient.Do)
+	for i := len(c.interceptors) - 1; i >= 0; i-- {
+		rt = c.interceptors[i](rt)
+	}
+	return rt(req)
+}
+
+// doRequest performs an HTTP request through the interceptor chain
 func (c *Client) doRequest(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
+	return c.doRequestWithContentType(ctx, method, path, body, ContentTypeJSON)
+}
+
+// doRequestWithContentType performs an HTTP request whose body, if any,
+// is sent with the given content type
+func (c *Client) doRequestWithContentType(ctx context.Context, method, path string, body io.Reader, contentType string) (*http.Response, error) {
 	// Build full URL
-	url := c.baseURL + path
+	url := c.BaseURL() + path
 
 	// Create request with context
 	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
-		slog.Error("Failed to create HTTP request",
+		c.log(ctx).Error("Failed to create HTTP request",
 			"method", method,
 			"url", url,
 			"error", err)
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	// Add authorization header
-	req.Header.Set(AuthHeaderName, fmt.Sprintf("%s %s", AuthTokenPrefix, c.token))
-
 	// Add content type for requests with body
 	if body != nil && (method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch) {
-		req.Header.Set(ContentTypeHeader, ContentTypeJSON)
+		req.Header.Set(ContentTypeHeader, contentType)
 	}
 
 	// Log request (without sensitive data)
-	slog.Debug("Making API request",
+	c.log(ctx).Debug("Making API request",
 		"method", method,
 		"url", url)
 
 	// Execute request
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.roundTrip(req)
 	if err != nil {
-		slog.Error("HTTP request failed",
+		c.log(ctx).Error("HTTP request failed",
 			"method", method,
 			"url", url,
 			"error", err)
@@ -86,7 +244,7 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body io.Rea
 	}
 
 	// Log response
-	slog.Debug("Received API response",
+	c.log(ctx).Debug("Received API response",
 		"method", method,
 		"url", url,
 		"status", resp.StatusCode)
@@ -94,38 +252,17 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body io.Rea
 	return resp, nil
 }
 
-// GET performs a GET request
-func (c *Client) GET(ctx context.Context, path string) ([]byte, error) {
-	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	// Read response body
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		slog.Error("Failed to read response body",
-			"path", path,
-			"error", err)
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	// Check for error status codes
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, parseError(resp.StatusCode, bodyBytes)
-	}
-
-	return bodyBytes, nil
-}
-
-// POST performs a POST request
-func (c *Client) POST(ctx context.Context, path string, body interface{}) ([]byte, error) {
+// do performs an API request, marshaling body as JSON when non-nil.
+// When out is non-nil a successful response is decoded into it as it
+// streams in and no bytes are returned; otherwise the raw body is
+// returned. Response bodies larger than the client's maximum response
+// size fail with ErrResponseTooLarge.
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) ([]byte, error) {
 	var bodyReader io.Reader
 	if body != nil {
 		bodyBytes, err := json.Marshal(body)
 		if err != nil {
-			slog.Error("Failed to marshal request body",
+			c.log(ctx).Error("Failed to marshal request body",
 				"path", path,
 				"error", err)
 			return nil, fmt.Errorf("failed to marshal body: %w", err)
@@ -133,112 +270,105 @@ func (c *Client) POST(ctx context.Context, path string, body interface{}) ([]byt
 		bodyReader = bytes.NewReader(bodyBytes)
 	}
 
-	resp, err := c.doRequest(ctx, http.MethodPost, path, bodyReader)
+	resp, err := c.doRequest(ctx, method, path, bodyReader)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		slog.Error("Failed to read response body",
-			"path", path,
-			"error", err)
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
+	limited := &limitedReader{r: resp.Body, remaining: c.maxResponseSize}
 
+	// Check for error status codes
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, parseError(resp.StatusCode, bodyBytes)
+		bodyBytes, _ := io.ReadAll(limited)
+		apiErr := parseError(resp.StatusCode, bodyBytes)
+		if resp.StatusCode == http.StatusTooManyRequests {
+			return nil, &RateLimitError{
+				Err:        apiErr,
+				RetryAfter: parseRetryAfter(resp.Header.Get(RetryAfterHeader), time.Now()),
+			}
+		}
+		return nil, apiErr
 	}
 
-	return bodyBytes, nil
-}
-
-// PUT performs a PUT request
-func (c *Client) PUT(ctx context.Context, path string, body interface{}) ([]byte, error) {
-	var bodyReader io.Reader
-	if body != nil {
-		bodyBytes, err := json.Marshal(body)
-		if err != nil {
-			slog.Error("Failed to marshal request body",
+	// Stream-decode directly into the caller's value
+	if out != nil {
+		if err := json.NewDecoder(limited).Decode(out); err != nil && err != io.EOF {
+			c.log(ctx).Error("Failed to decode response body",
 				"path", path,
 				"error", err)
-			return nil, fmt.Errorf("failed to marshal body: %w", err)
+			if errors.Is(err, ErrResponseTooLarge) {
+				return nil, err
+			}
+			return nil, fmt.Errorf("failed to parse response: %w", err)
 		}
-		bodyReader = bytes.NewReader(bodyBytes)
+		return nil, nil
 	}
 
-	resp, err := c.doRequest(ctx, http.MethodPut, path, bodyReader)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	bodyBytes, err := io.ReadAll(resp.Body)
+	// Read response body
+	bodyBytes, err := io.ReadAll(limited)
 	if err != nil {
-		slog.Error("Failed to read response body",
+		c.log(ctx).Error("Failed to read response body",
 			"path", path,
 			"error", err)
+		if errors.Is(err, ErrResponseTooLarge) {
+			return nil, err
+		}
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, parseError(resp.StatusCode, bodyBytes)
-	}
-
 	return bodyBytes, nil
 }
 
-// PATCH performs a PATCH request
-func (c *Client) PATCH(ctx context.Context, path string, body interface{}) ([]byte, error) {
-	var bodyReader io.Reader
-	if body != nil {
-		bodyBytes, err := json.Marshal(body)
-		if err != nil {
-			slog.Error("Failed to marshal request body",
-				"path", path,
-				"error", err)
-			return nil, fmt.Errorf("failed to marshal body: %w", err)
+// limitedReader reads from r until remaining bytes are exhausted, then
+// fails with ErrResponseTooLarge if the underlying reader has more data
+type limitedReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.remaining <= 0 {
+		// Probe for a single extra byte to distinguish EOF from overflow
+		var probe [1]byte
+		n, err := l.r.Read(probe[:])
+		if n > 0 {
+			return 0, ErrResponseTooLarge
 		}
-		bodyReader = bytes.NewReader(bodyBytes)
+		return 0, err
 	}
-
-	resp, err := c.doRequest(ctx, http.MethodPatch, path, bodyReader)
-	if err != nil {
-		return nil, err
+	if int64(len(p)) > l.remaining {
+		p = p[:l.remaining]
 	}
-	defer resp.Body.Close()
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	return n, err
+}
 
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		slog.Error("Failed to read response body",
-			"path", path,
-			"error", err)
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
+// GET performs a GET request
+func (c *Client) GET(ctx context.Context, path string) ([]byte, error) {
+	return c.do(ctx, http.MethodGet, path, nil, nil)
+}
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, parseError(resp.StatusCode, bodyBytes)
-	}
+// POST performs a POST request
+func (c *Client) POST(ctx context.Context, path string, body interface{}) ([]byte, error) {
+	return c.do(ctx, http.MethodPost, path, body, nil)
+}
 
-	return bodyBytes, nil
+// PUT performs a PUT request
+func (c *Client) PUT(ctx context.Context, path string, body interface{}) ([]byte, error) {
+	return c.do(ctx, http.MethodPut, path, body, nil)
+}
+
+// PATCH performs a PATCH request
+func (c *Client) PATCH(ctx context.Context, path string, body interface{}) ([]byte, error) {
+	return c.do(ctx, http.MethodPatch, path, body, nil)
 }
 
 // DELETE performs a DELETE request
 func (c *Client) DELETE(ctx context.Context, path string) error {
-	resp, err := c.doRequest(ctx, http.MethodDelete, path, nil)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	// DELETE can return 204 No Content or 200 OK
-	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return parseError(resp.StatusCode, bodyBytes)
-	}
-
-	return nil
+	_, err := c.do(ctx, http.MethodDelete, path, nil, nil)
+	return err
 }
 
 
@@ -258,23 +388,15 @@ func (c *Client) SearchDocuments(ctx context.Context, query string, page, pageSi
 	path := fmt.Sprintf("/api/documents/?query=%s&page=%d&page_size=%d",
 		url.QueryEscape(query), page, pageSize)
 
-	slog.Debug("Searching documents",
+	c.log(ctx).Debug("Searching documents",
 		"query", query,
 		"page", page,
 		"page_size", pageSize)
 
-	// Make GET request
-	bodyBytes, err := c.GET(ctx, path)
-	if err != nil {
-		return nil, err
-	}
-
-	// Parse response
+	// Make GET request, decoding the page as it streams in
 	var response PaginatedResponse
-	if err := json.Unmarshal(bodyBytes, &response); err != nil {
-		slog.Error("Failed to parse search response",
-			"error", err)
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	if _, err := c.do(ctx, http.MethodGet, path, nil, &response); err != nil {
+		return nil, err
 	}
 
 	return &response, nil
@@ -296,23 +418,15 @@ func (c *Client) GetSimilarDocuments(ctx context.Context, documentID int, page,
 	path := fmt.Sprintf("/api/documents/%d/similar/?page=%d&page_size=%d",
 		documentID, page, pageSize)
 
-	slog.Debug("Finding similar documents",
+	c.log(ctx).Debug("Finding similar documents",
 		"document_id", documentID,
 		"page", page,
 		"page_size", pageSize)
 
-	// Make GET request
-	bodyBytes, err := c.GET(ctx, path)
-	if err != nil {
-		return nil, err
-	}
-
-	// Parse response
+	// Make GET request, decoding the page as it streams in
 	var response PaginatedResponse
-	if err := json.Unmarshal(bodyBytes, &response); err != nil {
-		slog.Error("Failed to parse similar documents response",
-			"error", err)
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	if _, err := c.do(ctx, http.MethodGet, path, nil, &response); err != nil {
+		return nil, err
 	}
 
 	return &response, nil
@@ -322,7 +436,7 @@ func (c *Client) GetSimilarDocuments(ctx context.Context, documentID int, page,
 func (c *Client) GetDocument(ctx context.Context, documentID int) (*Document, error) {
 	path := fmt.Sprintf("/api/documents/%d/", documentID)
 
-	slog.Debug("Getting document", "document_id", documentID)
+	c.log(ctx).Debug("Getting document", "document_id", documentID)
 
 	// Make GET request
 	bodyBytes, err := c.GET(ctx, path)
@@ -333,7 +447,7 @@ func (c *Client) GetDocument(ctx context.Context, documentID int) (*Document, er
 	// Parse response
 	var document Document
 	if err := json.Unmarshal(bodyBytes, &document); err != nil {
-		slog.Error("Failed to parse document response",
+		c.log(ctx).Error("Failed to parse document response",
 			"document_id", documentID,
 			"error", err)
 		return nil, fmt.Errorf("failed to parse document: %w", err)
@@ -350,7 +464,7 @@ func (c *Client) GetDocumentContent(ctx context.Context, documentID int) (string
 		return "", err
 	}
 
-	slog.Debug("Retrieved document content",
+	c.log(ctx).Debug("Retrieved document content",
 		"document_id", documentID,
 		"content_length", len(document.Content))
 
@@ -361,7 +475,7 @@ func (c *Client) GetDocumentContent(ctx context.Context, documentID int) (string
 func (c *Client) CreateDocument(ctx context.Context, document *Document) (*Document, error) {
 	path := "/api/documents/"
 
-	slog.Debug("Creating document", "title", document.Title)
+	c.log(ctx).Debug("Creating document", "title", document.Title)
 
 	// Make POST request
 	bodyBytes, err := c.POST(ctx, path, document)
@@ -372,12 +486,12 @@ func (c *Client) CreateDocument(ctx context.Context, document *Document) (*Docum
 	// Parse response
 	var createdDocument Document
 	if err := json.Unmarshal(bodyBytes, &createdDocument); err != nil {
-		slog.Error("Failed to parse created document response",
+		c.log(ctx).Error("Failed to parse created document response",
 			"error", err)
 		return nil, fmt.Errorf("failed to parse created document: %w", err)
 	}
 
-	slog.Info("Document created successfully",
+	c.log(ctx).Info("Document created successfully",
 		"document_id", createdDocument.ID,
 		"title", createdDocument.Title)
 
@@ -388,7 +502,7 @@ func (c *Client) CreateDocument(ctx context.Context, document *Document) (*Docum
 func (c *Client) UpdateDocument(ctx context.Context, documentID int, updates map[string]interface{}) (*Document, error) {
 	path := fmt.Sprintf("/api/documents/%d/", documentID)
 
-	slog.Debug("Updating document",
+	c.log(ctx).Debug("Updating document",
 		"document_id", documentID,
 		"fields", len(updates))
 
@@ -401,13 +515,13 @@ func (c *Client) UpdateDocument(ctx context.Context, documentID int, updates map
 	// Parse response
 	var updatedDocument Document
 	if err := json.Unmarshal(bodyBytes, &updatedDocument); err != nil {
-		slog.Error("Failed to parse updated document response",
+		c.log(ctx).Error("Failed to parse updated document response",
 			"document_id", documentID,
 			"error", err)
 		return nil, fmt.Errorf("failed to parse updated document: %w", err)
 	}
 
-	slog.Info("Document updated successfully",
+	c.log(ctx).Info("Document updated successfully",
 		"document_id", documentID,
 		"title", updatedDocument.Title)
 
@@ -418,7 +532,7 @@ func (c *Client) UpdateDocument(ctx context.Context, documentID int, updates map
 func (c *Client) DeleteDocument(ctx context.Context, documentID int) error {
 	path := fmt.Sprintf("/api/documents/%d/", documentID)
 
-	slog.Debug("Deleting document", "document_id", documentID)
+	c.log(ctx).Debug("Deleting document", "document_id", documentID)
 
 	// Make DELETE request
 	err := c.DELETE(ctx, path)
@@ -426,7 +540,7 @@ func (c *Client) DeleteDocument(ctx context.Context, documentID int) error {
 		return err
 	}
 
-	slog.Info("Document deleted successfully", "document_id", documentID)
+	c.log(ctx).Info("Document deleted successfully", "document_id", documentID)
 	return nil
 }
 
@@ -444,29 +558,27 @@ func (c *Client) ListCorrespondents(ctx context.Context, page, pageSize int) (*P
 
 	path := fmt.Sprintf("/api/correspondents/?page=%d&page_size=%d", page, pageSize)
 
-	slog.Debug("Listing correspondents", "page", page, "page_size", pageSize)
+	c.log(ctx).Debug("Listing correspondents", "page", page, "page_size", pageSize)
 
-	// Make GET request
-	bodyBytes, err := c.GET(ctx, path)
-	if err != nil {
-		return nil, err
-	}
-
-	// Parse response
+	// Make GET request, decoding the page as it streams in
 	var response PaginatedResponse
-	if err := json.Unmarshal(bodyBytes, &response); err != nil {
-		slog.Error("Failed to parse correspondents response", "error", err)
-		return nil, fmt.Errorf("failed to parse correspondents: %w", err)
+	if _, err := c.do(ctx, http.MethodGet, path, nil, &response); err != nil {
+		return nil, err
 	}
 
 	return &response, nil
 }
 
+// ListCorrespondentsMatching lists the correspondents matching opts
+func (c *Client) ListCorrespondentsMatching(ctx context.Context, opts ListOptions, page, pageSize int) (*PaginatedResponse, error) {
+	return c.listMatching(ctx, "/api/correspondents/", opts, page, pageSize)
+}
+
 // GetCorrespondent retrieves a correspondent by ID
 func (c *Client) GetCorrespondent(ctx context.Context, correspondentID int) (*Correspondent, error) {
 	path := fmt.Sprintf("/api/correspondents/%d/", correspondentID)
 
-	slog.Debug("Getting correspondent", "correspondent_id", correspondentID)
+	c.log(ctx).Debug("Getting correspondent", "correspondent_id", correspondentID)
 
 	// Make GET request
 	bodyBytes, err := c.GET(ctx, path)
@@ -477,7 +589,7 @@ func (c *Client) GetCorrespondent(ctx context.Context, correspondentID int) (*Co
 	// Parse response
 	var correspondent Correspondent
 	if err := json.Unmarshal(bodyBytes, &correspondent); err != nil {
-		slog.Error("Failed to parse correspondent response",
+		c.log(ctx).Error("Failed to parse correspondent response",
 			"correspondent_id", correspondentID,
 			"error", err)
 		return nil, fmt.Errorf("failed to parse correspondent: %w", err)
@@ -490,7 +602,7 @@ func (c *Client) GetCorrespondent(ctx context.Context, correspondentID int) (*Co
 func (c *Client) CreateCorrespondent(ctx context.Context, correspondent *Correspondent) (*Correspondent, error) {
 	path := "/api/correspondents/"
 
-	slog.Debug("Creating correspondent", "name", correspondent.Name)
+	c.log(ctx).Debug("Creating correspondent", "name", correspondent.Name)
 
 	// Make POST request
 	bodyBytes, err := c.POST(ctx, path, correspondent)
@@ -501,11 +613,11 @@ func (c *Client) CreateCorrespondent(ctx context.Context, correspondent *Corresp
 	// Parse response
 	var createdCorrespondent Correspondent
 	if err := json.Unmarshal(bodyBytes, &createdCorrespondent); err != nil {
-		slog.Error("Failed to parse created correspondent response", "error", err)
+		c.log(ctx).Error("Failed to parse created correspondent response", "error", err)
 		return nil, fmt.Errorf("failed to parse created correspondent: %w", err)
 	}
 
-	slog.Info("Correspondent created successfully",
+	c.log(ctx).Info("Correspondent created successfully",
 		"correspondent_id", createdCorrespondent.ID,
 		"name", createdCorrespondent.Name)
 
@@ -516,7 +628,7 @@ func (c *Client) CreateCorrespondent(ctx context.Context, correspondent *Corresp
 func (c *Client) UpdateCorrespondent(ctx context.Context, correspondentID int, updates map[string]interface{}) (*Correspondent, error) {
 	path := fmt.Sprintf("/api/correspondents/%d/", correspondentID)
 
-	slog.Debug("Updating correspondent",
+	c.log(ctx).Debug("Updating correspondent",
 		"correspondent_id", correspondentID,
 		"fields", len(updates))
 
@@ -529,13 +641,13 @@ func (c *Client) UpdateCorrespondent(ctx context.Context, correspondentID int, u
 	// Parse response
 	var updatedCorrespondent Correspondent
 	if err := json.Unmarshal(bodyBytes, &updatedCorrespondent); err != nil {
-		slog.Error("Failed to parse updated correspondent response",
+		c.log(ctx).Error("Failed to parse updated correspondent response",
 			"correspondent_id", correspondentID,
 			"error", err)
 		return nil, fmt.Errorf("failed to parse updated correspondent: %w", err)
 	}
 
-	slog.Info("Correspondent updated successfully",
+	c.log(ctx).Info("Correspondent updated successfully",
 		"correspondent_id", correspondentID,
 		"name", updatedCorrespondent.Name)
 
@@ -546,7 +658,7 @@ func (c *Client) UpdateCorrespondent(ctx context.Context, correspondentID int, u
 func (c *Client) DeleteCorrespondent(ctx context.Context, correspondentID int) error {
 	path := fmt.Sprintf("/api/correspondents/%d/", correspondentID)
 
-	slog.Debug("Deleting correspondent", "correspondent_id", correspondentID)
+	c.log(ctx).Debug("Deleting correspondent", "correspondent_id", correspondentID)
 
 	// Make DELETE request
 	err := c.DELETE(ctx, path)
@@ -554,7 +666,7 @@ func (c *Client) DeleteCorrespondent(ctx context.Context, correspondentID int) e
 		return err
 	}
 
-	slog.Info("Correspondent deleted successfully", "correspondent_id", correspondentID)
+	c.log(ctx).Info("Correspondent deleted successfully", "correspondent_id", correspondentID)
 	return nil
 }
 
@@ -573,29 +685,27 @@ func (c *Client) ListDocumentTypes(ctx context.Context, page, pageSize int) (*Pa
 
 	path := fmt.Sprintf("/api/document_types/?page=%d&page_size=%d", page, pageSize)
 
-	slog.Debug("Listing document types", "page", page, "page_size", pageSize)
+	c.log(ctx).Debug("Listing document types", "page", page, "page_size", pageSize)
 
-	// Make GET request
-	bodyBytes, err := c.GET(ctx, path)
-	if err != nil {
-		return nil, err
-	}
-
-	// Parse response
+	// Make GET request, decoding the page as it streams in
 	var response PaginatedResponse
-	if err := json.Unmarshal(bodyBytes, &response); err != nil {
-		slog.Error("Failed to parse document types response", "error", err)
-		return nil, fmt.Errorf("failed to parse document types: %w", err)
+	if _, err := c.do(ctx, http.MethodGet, path, nil, &response); err != nil {
+		return nil, err
 	}
 
 	return &response, nil
 }
 
+// ListDocumentTypesMatching lists the document types matching opts
+func (c *Client) ListDocumentTypesMatching(ctx context.Context, opts ListOptions, page, pageSize int) (*PaginatedResponse, error) {
+	return c.listMatching(ctx, "/api/document_types/", opts, page, pageSize)
+}
+
 // GetDocumentType retrieves a document type by ID
 func (c *Client) GetDocumentType(ctx context.Context, typeID int) (*DocumentType, error) {
 	path := fmt.Sprintf("/api/document_types/%d/", typeID)
 
-	slog.Debug("Getting document type", "document_type_id", typeID)
+	c.log(ctx).Debug("Getting document type", "document_type_id", typeID)
 
 	// Make GET request
 	bodyBytes, err := c.GET(ctx, path)
@@ -606,7 +716,7 @@ func (c *Client) GetDocumentType(ctx context.Context, typeID int) (*DocumentType
 	// Parse response
 	var docType DocumentType
 	if err := json.Unmarshal(bodyBytes, &docType); err != nil {
-		slog.Error("Failed to parse document type response",
+		c.log(ctx).Error("Failed to parse document type response",
 			"document_type_id", typeID,
 			"error", err)
 		return nil, fmt.Errorf("failed to parse document type: %w", err)
@@ -619,7 +729,7 @@ func (c *Client) GetDocumentType(ctx context.Context, typeID int) (*DocumentType
 func (c *Client) CreateDocumentType(ctx context.Context, docType *DocumentType) (*DocumentType, error) {
 	path := "/api/document_types/"
 
-	slog.Debug("Creating document type", "name", docType.Name)
+	c.log(ctx).Debug("Creating document type", "name", docType.Name)
 
 	// Make POST request
 	bodyBytes, err := c.POST(ctx, path, docType)
@@ -630,11 +740,11 @@ func (c *Client) CreateDocumentType(ctx context.Context, docType *DocumentType)
 	// Parse response
 	var createdDocType DocumentType
 	if err := json.Unmarshal(bodyBytes, &createdDocType); err != nil {
-		slog.Error("Failed to parse created document type response", "error", err)
+		c.log(ctx).Error("Failed to parse created document type response", "error", err)
 		return nil, fmt.Errorf("failed to parse created document type: %w", err)
 	}
 
-	slog.Info("Document type created successfully",
+	c.log(ctx).Info("Document type created successfully",
 		"document_type_id", createdDocType.ID,
 		"name", createdDocType.Name)
 
@@ -645,7 +755,7 @@ func (c *Client) CreateDocumentType(ctx context.Context, docType *DocumentType)
 func (c *Client) UpdateDocumentType(ctx context.Context, typeID int, updates map[string]interface{}) (*DocumentType, error) {
 	path := fmt.Sprintf("/api/document_types/%d/", typeID)
 
-	slog.Debug("Updating document type",
+	c.log(ctx).Debug("Updating document type",
 		"document_type_id", typeID,
 		"fields", len(updates))
 
@@ -658,13 +768,13 @@ func (c *Client) UpdateDocumentType(ctx context.Context, typeID int, updates map
 	// Parse response
 	var updatedDocType DocumentType
 	if err := json.Unmarshal(bodyBytes, &updatedDocType); err != nil {
-		slog.Error("Failed to parse updated document type response",
+		c.log(ctx).Error("Failed to parse updated document type response",
 			"document_type_id", typeID,
 			"error", err)
 		return nil, fmt.Errorf("failed to parse updated document type: %w", err)
 	}
 
-	slog.Info("Document type updated successfully",
+	c.log(ctx).Info("Document type updated successfully",
 		"document_type_id", typeID,
 		"name", updatedDocType.Name)
 
@@ -675,7 +785,7 @@ func (c *Client) UpdateDocumentType(ctx context.Context, typeID int, updates map
 func (c *Client) DeleteDocumentType(ctx context.Context, typeID int) error {
 	path := fmt.Sprintf("/api/document_types/%d/", typeID)
 
-	slog.Debug("Deleting document type", "document_type_id", typeID)
+	c.log(ctx).Debug("Deleting document type", "document_type_id", typeID)
 
 	// Make DELETE request
 	err := c.DELETE(ctx, path)
@@ -683,7 +793,7 @@ func (c *Client) DeleteDocumentType(ctx context.Context, typeID int) error {
 		return err
 	}
 
-	slog.Info("Document type deleted successfully", "document_type_id", typeID)
+	c.log(ctx).Info("Document type deleted successfully", "document_type_id", typeID)
 	return nil
 }
 
@@ -702,29 +812,27 @@ func (c *Client) ListTags(ctx context.Context, page, pageSize int) (*PaginatedRe
 
 	path := fmt.Sprintf("/api/tags/?page=%d&page_size=%d", page, pageSize)
 
-	slog.Debug("Listing tags", "page", page, "page_size", pageSize)
-
-	// Make GET request
-	bodyBytes, err := c.GET(ctx, path)
-	if err != nil {
-		return nil, err
-	}
+	c.log(ctx).Debug("Listing tags", "page", page, "page_size", pageSize)
 
-	// Parse response
+	// Make GET request, decoding the page as it streams in
 	var response PaginatedResponse
-	if err := json.Unmarshal(bodyBytes, &response); err != nil {
-		slog.Error("Failed to parse tags response", "error", err)
-		return nil, fmt.Errorf("failed to parse tags: %w", err)
+	if _, err := c.do(ctx, http.MethodGet, path, nil, &response); err != nil {
+		return nil, err
 	}
 
 	return &response, nil
 }
 
+// ListTagsMatching lists the tags matching opts
+func (c *Client) ListTagsMatching(ctx context.Context, opts ListOptions, page, pageSize int) (*PaginatedResponse, error) {
+	return c.listMatching(ctx, "/api/tags/", opts, page, pageSize)
+}
+
 // GetTag retrieves a tag by ID
 func (c *Client) GetTag(ctx context.Context, tagID int) (*Tag, error) {
 	path := fmt.Sprintf("/api/tags/%d/", tagID)
 
-	slog.Debug("Getting tag", "tag_id", tagID)
+	c.log(ctx).Debug("Getting tag", "tag_id", tagID)
 
 	// Make GET request
 	bodyBytes, err := c.GET(ctx, path)
@@ -735,7 +843,7 @@ func (c *Client) GetTag(ctx context.Context, tagID int) (*Tag, error) {
 	// Parse response
 	var tag Tag
 	if err := json.Unmarshal(bodyBytes, &tag); err != nil {
-		slog.Error("Failed to parse tag response",
+		c.log(ctx).Error("Failed to parse tag response",
 			"tag_id", tagID,
 			"error", err)
 		return nil, fmt.Errorf("failed to parse tag: %w", err)
@@ -748,7 +856,7 @@ func (c *Client) GetTag(ctx context.Context, tagID int) (*Tag, error) {
 func (c *Client) CreateTag(ctx context.Context, tag *Tag) (*Tag, error) {
 	path := "/api/tags/"
 
-	slog.Debug("Creating tag", "name", tag.Name)
+	c.log(ctx).Debug("Creating tag", "name", tag.Name)
 
 	// Make POST request
 	bodyBytes, err := c.POST(ctx, path, tag)
@@ -759,11 +867,11 @@ func (c *Client) CreateTag(ctx context.Context, tag *Tag) (*Tag, error) {
 	// Parse response
 	var createdTag Tag
 	if err := json.Unmarshal(bodyBytes, &createdTag); err != nil {
-		slog.Error("Failed to parse created tag response", "error", err)
+		c.log(ctx).Error("Failed to parse created tag response", "error", err)
 		return nil, fmt.Errorf("failed to parse created tag: %w", err)
 	}
 
-	slog.Info("Tag created successfully",
+	c.log(ctx).Info("Tag created successfully",
 		"tag_id", createdTag.ID,
 		"name", createdTag.Name)
 
@@ -774,7 +882,7 @@ func (c *Client) CreateTag(ctx context.Context, tag *Tag) (*Tag, error) {
 func (c *Client) UpdateTag(ctx context.Context, tagID int, updates map[string]interface{}) (*Tag, error) {
 	path := fmt.Sprintf("/api/tags/%d/", tagID)
 
-	slog.Debug("Updating tag",
+	c.log(ctx).Debug("Updating tag",
 		"tag_id", tagID,
 		"fields", len(updates))
 
@@ -787,13 +895,13 @@ func (c *Client) UpdateTag(ctx context.Context, tagID int, updates map[string]in
 	// Parse response
 	var updatedTag Tag
 	if err := json.Unmarshal(bodyBytes, &updatedTag); err != nil {
-		slog.Error("Failed to parse updated tag response",
+		c.log(ctx).Error("Failed to parse updated tag response",
 			"tag_id", tagID,
 			"error", err)
 		return nil, fmt.Errorf("failed to parse updated tag: %w", err)
 	}
 
-	slog.Info("Tag updated successfully",
+	c.log(ctx).Info("Tag updated successfully",
 		"tag_id", tagID,
 		"name", updatedTag.Name)
 
@@ -804,7 +912,7 @@ func (c *Client) UpdateTag(ctx context.Context, tagID int, updates map[string]in
 func (c *Client) DeleteTag(ctx context.Context, tagID int) error {
 	path := fmt.Sprintf("/api/tags/%d/", tagID)
 
-	slog.Debug("Deleting tag", "tag_id", tagID)
+	c.log(ctx).Debug("Deleting tag", "tag_id", tagID)
 
 	// Make DELETE request
 	err := c.DELETE(ctx, path)
@@ -812,17 +920,17 @@ func (c *Client) DeleteTag(ctx context.Context, tagID int) error {
 		return err
 	}
 
-	slog.Info("Tag deleted successfully", "tag_id", tagID)
+	c.log(ctx).Info("Tag deleted successfully", "tag_id", tagID)
 	return nil
 }
 
 
 // parseError parses an error response from the API
-func parseError(statusCode int, body []byte) error {
+func parseError(statusCode int, body []byte) *Error {
 	var errorData map[string]interface{}
 	if err := json.Unmarshal(body, &errorData); err != nil {
-		// If we can't parse as JSON, use the raw body as message
-		return NewError(statusCode, string(body), nil)
+		// Not from the API, e.g. a proxy error page; summarise the body
+		return NewError(statusCode, nonJSONMessage(statusCode, body), nil)
 	}
 
 	// Try to extract common error message fields
@@ -833,6 +941,8 @@ func parseError(statusCode int, body []byte) error {
 		message = msg
 	} else if msg, ok := errorData["error"].(string); ok {
 		message = msg
+	} else if statusCode == http.StatusBadRequest {
+		message = "validation failed"
 	} else {
 		message = "API request failed"
 	}
@@ -855,29 +965,27 @@ func (c *Client) ListStoragePaths(ctx context.Context, page, pageSize int) (*Pag
 
 	path := fmt.Sprintf("/api/storage_paths/?page=%d&page_size=%d", page, pageSize)
 
-	slog.Debug("Listing storage paths", "page", page, "page_size", pageSize)
+	c.log(ctx).Debug("Listing storage paths", "page", page, "page_size", pageSize)
 
-	// Make GET request
-	bodyBytes, err := c.GET(ctx, path)
-	if err != nil {
-		return nil, err
-	}
-
-	// Parse response
+	// Make GET request, decoding the page as it streams in
 	var response PaginatedResponse
-	if err := json.Unmarshal(bodyBytes, &response); err != nil {
-		slog.Error("Failed to parse storage paths response", "error", err)
-		return nil, fmt.Errorf("failed to parse storage paths: %w", err)
+	if _, err := c.do(ctx, http.MethodGet, path, nil, &response); err != nil {
+		return nil, err
 	}
 
 	return &response, nil
 }
 
+// ListStoragePathsMatching lists the storage paths matching opts
+func (c *Client) ListStoragePathsMatching(ctx context.Context, opts ListOptions, page, pageSize int) (*PaginatedResponse, error) {
+	return c.listMatching(ctx, "/api/storage_paths/", opts, page, pageSize)
+}
+
 // GetStoragePath retrieves a storage path by ID
 func (c *Client) GetStoragePath(ctx context.Context, pathID int) (*StoragePath, error) {
 	path := fmt.Sprintf("/api/storage_paths/%d/", pathID)
 
-	slog.Debug("Getting storage path", "path_id", pathID)
+	c.log(ctx).Debug("Getting storage path", "path_id", pathID)
 
 	// Make GET request
 	bodyBytes, err := c.GET(ctx, path)
@@ -888,7 +996,7 @@ func (c *Client) GetStoragePath(ctx context.Context, pathID int) (*StoragePath,
 	// Parse response
 	var storagePath StoragePath
 	if err := json.Unmarshal(bodyBytes, &storagePath); err != nil {
-		slog.Error("Failed to parse storage path response",
+		c.log(ctx).Error("Failed to parse storage path response",
 			"path_id", pathID,
 			"error", err)
 		return nil, fmt.Errorf("failed to parse storage path: %w", err)
@@ -901,7 +1009,7 @@ func (c *Client) GetStoragePath(ctx context.Context, pathID int) (*StoragePath,
 func (c *Client) CreateStoragePath(ctx context.Context, storagePath *StoragePath) (*StoragePath, error) {
 	path := "/api/storage_paths/"
 
-	slog.Debug("Creating storage path", "name", storagePath.Name)
+	c.log(ctx).Debug("Creating storage path", "name", storagePath.Name)
 
 	// Make POST request
 	bodyBytes, err := c.POST(ctx, path, storagePath)
@@ -912,11 +1020,11 @@ func (c *Client) CreateStoragePath(ctx context.Context, storagePath *StoragePath
 	// Parse response
 	var createdStoragePath StoragePath
 	if err := json.Unmarshal(bodyBytes, &createdStoragePath); err != nil {
-		slog.Error("Failed to parse created storage path response", "error", err)
+		c.log(ctx).Error("Failed to parse created storage path response", "error", err)
 		return nil, fmt.Errorf("failed to parse created storage path: %w", err)
 	}
 
-	slog.Info("Storage path created successfully",
+	c.log(ctx).Info("Storage path created successfully",
 		"path_id", createdStoragePath.ID,
 		"name", createdStoragePath.Name)
 
@@ -927,7 +1035,7 @@ func (c *Client) CreateStoragePath(ctx context.Context, storagePath *StoragePath
 func (c *Client) UpdateStoragePath(ctx context.Context, pathID int, updates map[string]interface{}) (*StoragePath, error) {
 	path := fmt.Sprintf("/api/storage_paths/%d/", pathID)
 
-	slog.Debug("Updating storage path",
+	c.log(ctx).Debug("Updating storage path",
 		"path_id", pathID,
 		"fields", len(updates))
 
@@ -940,13 +1048,13 @@ func (c *Client) UpdateStoragePath(ctx context.Context, pathID int, updates map[
 	// Parse response
 	var updatedStoragePath StoragePath
 	if err := json.Unmarshal(bodyBytes, &updatedStoragePath); err != nil {
-		slog.Error("Failed to parse updated storage path response",
+		c.log(ctx).Error("Failed to parse updated storage path response",
 			"path_id", pathID,
 			"error", err)
 		return nil, fmt.Errorf("failed to parse updated storage path: %w", err)
 	}
 
-	slog.Info("Storage path updated successfully",
+	c.log(ctx).Info("Storage path updated successfully",
 		"path_id", pathID,
 		"name", updatedStoragePath.Name)
 
@@ -957,7 +1065,7 @@ func (c *Client) UpdateStoragePath(ctx context.Context, pathID int, updates map[
 func (c *Client) DeleteStoragePath(ctx context.Context, pathID int) error {
 	path := fmt.Sprintf("/api/storage_paths/%d/", pathID)
 
-	slog.Debug("Deleting storage path", "path_id", pathID)
+	c.log(ctx).Debug("Deleting storage path", "path_id", pathID)
 
 	// Make DELETE request
 	err := c.DELETE(ctx, path)
@@ -965,7 +1073,7 @@ func (c *Client) DeleteStoragePath(ctx context.Context, pathID int) error {
 		return err
 	}
 
-	slog.Info("Storage path deleted successfully", "path_id", pathID)
+	c.log(ctx).Info("Storage path deleted successfully", "path_id", pathID)
 	return nil
 }
 
@@ -989,24 +1097,17 @@ func (c *Client) ListCustomFields(ctx context.Context, page, pageSize int) (*Pag
 
 	path := "/api/custom_fields/?" + params.Encode()
 
-	slog.Debug("Listing custom fields",
+	c.log(ctx).Debug("Listing custom fields",
 		"page", page,
 		"page_size", pageSize)
 
-	// Make GET request
-	bodyBytes, err := c.GET(ctx, path)
-	if err != nil {
-		return nil, err
-	}
-
-	// Parse paginated response
+	// Make GET request, decoding the page as it streams in
 	var response PaginatedResponse
-	if err := json.Unmarshal(bodyBytes, &response); err != nil {
-		slog.Error("Failed to parse custom fields list", "error", err)
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	if _, err := c.do(ctx, http.MethodGet, path, nil, &response); err != nil {
+		return nil, err
 	}
 
-	slog.Info("Custom fields listed successfully",
+	c.log(ctx).Info("Custom fields listed successfully",
 		"count", response.Count,
 		"page", page)
 
@@ -1017,7 +1118,7 @@ func (c *Client) ListCustomFields(ctx context.Context, page, pageSize int) (*Pag
 func (c *Client) GetCustomField(ctx context.Context, fieldID int) (*CustomField, error) {
 	path := fmt.Sprintf("/api/custom_fields/%d/", fieldID)
 
-	slog.Debug("Getting custom field", "field_id", fieldID)
+	c.log(ctx).Debug("Getting custom field", "field_id", fieldID)
 
 	// Make GET request
 	bodyBytes, err := c.GET(ctx, path)
@@ -1028,11 +1129,11 @@ func (c *Client) GetCustomField(ctx context.Context, fieldID int) (*CustomField,
 	// Parse response
 	var field CustomField
 	if err := json.Unmarshal(bodyBytes, &field); err != nil {
-		slog.Error("Failed to parse custom field", "error", err)
+		c.log(ctx).Error("Failed to parse custom field", "error", err)
 		return nil, fmt.Errorf("failed to parse custom field: %w", err)
 	}
 
-	slog.Info("Custom field retrieved successfully",
+	c.log(ctx).Info("Custom field retrieved successfully",
 		"field_id", fieldID,
 		"name", field.Name)
 
@@ -1043,7 +1144,7 @@ func (c *Client) GetCustomField(ctx context.Context, fieldID int) (*CustomField,
 func (c *Client) CreateCustomField(ctx context.Context, field *CustomField) (*CustomField, error) {
 	path := "/api/custom_fields/"
 
-	slog.Debug("Creating custom field", "name", field.Name)
+	c.log(ctx).Debug("Creating custom field", "name", field.Name)
 
 	// Make POST request
 	bodyBytes, err := c.POST(ctx, path, field)
@@ -1054,11 +1155,11 @@ func (c *Client) CreateCustomField(ctx context.Context, field *CustomField) (*Cu
 	// Parse response
 	var createdField CustomField
 	if err := json.Unmarshal(bodyBytes, &createdField); err != nil {
-		slog.Error("Failed to parse created custom field", "error", err)
+		c.log(ctx).Error("Failed to parse created custom field", "error", err)
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	slog.Info("Custom field created successfully",
+	c.log(ctx).Info("Custom field created successfully",
 		"field_id", createdField.ID,
 		"name", createdField.Name)
 
@@ -1069,7 +1170,7 @@ func (c *Client) CreateCustomField(ctx context.Context, field *CustomField) (*Cu
 func (c *Client) UpdateCustomField(ctx context.Context, fieldID int, updates map[string]interface{}) (*CustomField, error) {
 	path := fmt.Sprintf("/api/custom_fields/%d/", fieldID)
 
-	slog.Debug("Updating custom field",
+	c.log(ctx).Debug("Updating custom field",
 		"field_id", fieldID,
 		"fields", len(updates))
 
@@ -1082,11 +1183,11 @@ func (c *Client) UpdateCustomField(ctx context.Context, fieldID int, updates map
 	// Parse response
 	var field CustomField
 	if err := json.Unmarshal(bodyBytes, &field); err != nil {
-		slog.Error("Failed to parse updated custom field", "error", err)
+		c.log(ctx).Error("Failed to parse updated custom field", "error", err)
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	slog.Info("Custom field updated successfully",
+	c.log(ctx).Info("Custom field updated successfully",
 		"field_id", fieldID,
 		"name", field.Name)
 
@@ -1097,7 +1198,7 @@ func (c *Client) UpdateCustomField(ctx context.Context, fieldID int, updates map
 func (c *Client) DeleteCustomField(ctx context.Context, fieldID int) error {
 	path := fmt.Sprintf("/api/custom_fields/%d/", fieldID)
 
-	slog.Debug("Deleting custom field", "field_id", fieldID)
+	c.log(ctx).Debug("Deleting custom field", "field_id", fieldID)
 
 	// Make DELETE request
 	err := c.DELETE(ctx, path)
@@ -1105,7 +1206,7 @@ func (c *Client) DeleteCustomField(ctx context.Context, fieldID int) error {
 		return err
 	}
 
-	slog.Info("Custom field deleted successfully", "field_id", fieldID)
+	c.log(ctx).Info("Custom field deleted successfully", "field_id", fieldID)
 
 	return nil
 }
@@ -1115,7 +1216,7 @@ func (c *Client) DeleteCustomField(ctx context.Context, fieldID int) error {
 func (c *Client) BulkEditDocuments(ctx context.Context, documentIDs []int, operations map[string]interface{}) (map[string]interface{}, error) {
 	path := "/api/documents/bulk_edit/"
 
-	slog.Debug("Bulk editing documents",
+	c.log(ctx).Debug("Bulk editing documents",
 		"document_count", len(documentIDs),
 		"operations", len(operations))
 
@@ -1138,12 +1239,291 @@ func (c *Client) BulkEditDocuments(ctx context.Context, documentIDs []int, opera
 	// Parse response
 	var response map[string]interface{}
 	if err := json.Unmarshal(bodyBytes, &response); err != nil {
-		slog.Error("Failed to parse bulk edit response", "error", err)
+		c.log(ctx).Error("Failed to parse bulk edit response", "error", err)
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	slog.Info("Bulk edit completed successfully",
+	c.log(ctx).Info("Bulk edit completed successfully",
 		"document_count", len(documentIDs))
 
 	return response, nil
 }
+
+// FindDocumentByChecksum looks up a document by the MD5 checksum of its
+// original file. It returns nil without error when no document matches.
+func (c *Client) FindDocumentByChecksum(ctx context.Context, checksum string) (*Document, error) {
+	params := url.Values{}
+	params.Set("checksum__iexact", checksum)
+	params.Set("page_size", "1")
+	path := "/api/documents/?" + params.Encode()
+
+	c.log(ctx).Debug("Finding document by checksum", "checksum", checksum)
+
+	// Make GET request, decoding the page as it streams in
+	var response PaginatedResponse
+	if _, err := c.do(ctx, http.MethodGet, path, nil, &response); err != nil {
+		return nil, err
+	}
+
+	var documents []Document
+	if err := json.Unmarshal(response.Results, &documents); err != nil {
+		c.log(ctx).Error("Failed to parse checksum lookup response", "error", err)
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if len(documents) == 0 {
+		return nil, nil
+	}
+	return &documents[0], nil
+}
+
+// FindDocumentByASN looks up the document holding an archive serial
+// number. It returns nil without error when no document holds it.
+func (c *Client) FindDocumentByASN(ctx context.Context, asn int) (*Document, error) {
+	params := url.Values{}
+	params.Set("archive_serial_number", strconv.Itoa(asn))
+	params.Set("page_size", "1")
+	path := "/api/documents/?" + params.Encode()
+
+	c.log(ctx).Debug("Finding document by archive serial number", "asn", asn)
+
+	var response PaginatedResponse
+	if _, err := c.do(ctx, http.MethodGet, path, nil, &response); err != nil {
+		return nil, err
+	}
+
+	var documents []Document
+	if err := json.Unmarshal(response.Results, &documents); err != nil {
+		c.log(ctx).Error("Failed to parse archive serial number lookup response", "error", err)
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if len(documents) == 0 {
+		return nil, nil
+	}
+	return &documents[0], nil
+}
+
+// NextASN returns the archive serial number Paperless would assign next,
+// one above the highest in use
+func (c *Client) NextASN(ctx context.Context) (int, error) {
+	c.log(ctx).Debug("Getting next archive serial number")
+
+	var asn int
+	if _, err := c.do(ctx, http.MethodGet, "/api/documents/next_asn/", nil, &asn); err != nil {
+		return 0, err
+	}
+	return asn, nil
+}
+
+// ListSavedViews retrieves saved views with pagination
+func (c *Client) ListSavedViews(ctx context.Context, page, pageSize int) (*PaginatedResponse, error) {
+	// Validate and set defaults for pagination
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = DefaultPageSize
+	} else if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+
+	path := fmt.Sprintf("/api/saved_views/?page=%d&page_size=%d", page, pageSize)
+
+	c.log(ctx).Debug("Listing saved views", "page", page, "page_size", pageSize)
+
+	// Make GET request, decoding the page as it streams in
+	var response PaginatedResponse
+	if _, err := c.do(ctx, http.MethodGet, path, nil, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// GetSavedView retrieves a specific saved view by ID
+func (c *Client) GetSavedView(ctx context.Context, viewID int) (*SavedView, error) {
+	path := fmt.Sprintf("/api/saved_views/%d/", viewID)
+
+	c.log(ctx).Debug("Getting saved view", "view_id", viewID)
+
+	// Make GET request, decoding the view as it streams in
+	var view SavedView
+	if _, err := c.do(ctx, http.MethodGet, path, nil, &view); err != nil {
+		return nil, err
+	}
+
+	return &view, nil
+}
+
+// ListOptions narrows and orders a list of tags, correspondents, document
+// types or storage paths. The zero value lists everything in Paperless'
+// default order.
+type ListOptions struct {
+	Name     string // only objects whose name contains this, ignoring case
+	Ordering string // field to sort by, prefixed with - for descending
+}
+
+// listMatching lists the objects at path matching opts
+func (c *Client) listMatching(ctx context.Context, path string, opts ListOptions, page, pageSize int) (*PaginatedResponse, error) {
+	// Validate and set defaults for pagination
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = DefaultPageSize
+	} else if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+
+	params := url.Values{}
+	if opts.Name != "" {
+		params.Set("name__icontains", opts.Name)
+	}
+	if opts.Ordering != "" {
+		params.Set("ordering", opts.Ordering)
+	}
+	params.Set("page", strconv.Itoa(page))
+	params.Set("page_size", strconv.Itoa(pageSize))
+
+	c.log(ctx).Debug("Listing objects",
+		"path", path,
+		"name", opts.Name,
+		"ordering", opts.Ordering,
+		"page", page,
+		"page_size", pageSize)
+
+	// Make GET request, decoding the page as it streams in
+	var response PaginatedResponse
+	if _, err := c.do(ctx, http.MethodGet, path+"?"+params.Encode(), nil, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// ListDocuments retrieves documents matching the given filter query
+// parameters with pagination
+func (c *Client) ListDocuments(ctx context.Context, filters url.Values, page, pageSize int) (*PaginatedResponse, error) {
+	// Validate and set defaults for pagination
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = DefaultPageSize
+	} else if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+
+	params := url.Values{}
+	for key, values := range filters {
+		params[key] = values
+	}
+	params.Set("page", strconv.Itoa(page))
+	params.Set("page_size", strconv.Itoa(pageSize))
+	path := "/api/documents/?" + params.Encode()
+
+	c.log(ctx).Debug("Listing documents",
+		"filters", filters.Encode(),
+		"page", page,
+		"page_size", pageSize)
+
+	// Make GET request, decoding the page as it streams in
+	var response PaginatedResponse
+	if _, err := c.do(ctx, http.MethodGet, path, nil, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// GetDocumentMetadata retrieves the file metadata of a document, including
+// the sizes of its original and archived files
+func (c *Client) GetDocumentMetadata(ctx context.Context, documentID int) (*DocumentMetadata, error) {
+	path := fmt.Sprintf("/api/documents/%d/metadata/", documentID)
+
+	c.log(ctx).Debug("Getting document metadata", "document_id", documentID)
+
+	var metadata DocumentMetadata
+	if _, err := c.do(ctx, http.MethodGet, path, nil, &metadata); err != nil {
+		return nil, err
+	}
+
+	return &metadata, nil
+}
+
+// Version headers Paperless sets on API responses
+const (
+	VersionHeader    = "X-Version"
+	APIVersionHeader = "X-Api-Version"
+)
+
+// ServerVersion identifies the Paperless release and API version in use
+type ServerVersion struct {
+	Version    string `json:"version"`
+	APIVersion string `json:"api_version"`
+}
+
+// GetServerVersion reads the Paperless version from the headers of the
+// API root response
+func (c *Client) GetServerVersion(ctx context.Context) (*ServerVersion, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/api/", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, c.maxResponseSize))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, parseError(resp.StatusCode, body)
+	}
+
+	return &ServerVersion{
+		Version:    resp.Header.Get(VersionHeader),
+		APIVersion: resp.Header.Get(APIVersionHeader),
+	}, nil
+}
+
+// GetUISettings retrieves the UI settings of the authenticated user
+func (c *Client) GetUISettings(ctx context.Context) (*UISettings, error) {
+	c.log(ctx).Debug("Getting UI settings")
+
+	var settings UISettings
+	if _, err := c.do(ctx, http.MethodGet, "/api/ui_settings/", nil, &settings); err != nil {
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+// GetApplicationConfig retrieves the application configuration, which
+// holds the OCR and branding settings that can be changed at runtime.
+// Paperless returns it as a list with a single entry.
+func (c *Client) GetApplicationConfig(ctx context.Context) ([]map[string]interface{}, error) {
+	c.log(ctx).Debug("Getting application configuration")
+
+	var config []map[string]interface{}
+	if _, err := c.do(ctx, http.MethodGet, "/api/config/", nil, &config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// RestoreDocuments restores deleted documents from the trash. Paperless
+// versions without a trash, or with the trash disabled, respond with an
+// error.
+func (c *Client) RestoreDocuments(ctx context.Context, documentIDs []int) error {
+	c.log(ctx).Debug("Restoring documents from trash", "document_count", len(documentIDs))
+
+	body := map[string]interface{}{
+		"action":    "restore",
+		"documents": documentIDs,
+	}
+	if _, err := c.POST(ctx, "/api/trash/", body); err != nil {
+		return err
+	}
+
+	c.log(ctx).Info("Documents restored from trash", "document_count", len(documentIDs))
+	return nil
+}
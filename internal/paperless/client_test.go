@@ -0,0 +1,622 @@
+package paperless
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+)
+
+// TestInterceptorChain tests that interceptors wrap requests in order and
+// that the auth header is always applied
+func TestInterceptorChain(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get(AuthHeaderName); got != "Token test-token" {
+			t.Errorf("Expected auth header 'Token test-token', got %q", got)
+		}
+		w.Header().Set(ContentTypeHeader, ContentTypeJSON)
+		w.Write([]byte(`{"id": 7, "name": "Invoices"}`))
+	}))
+	defer ts.Close()
+
+	client := New(ts.URL, "test-token")
+
+	var order []string
+	client.Use(
+		func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				order = append(order, "first")
+				return next(req)
+			}
+		},
+		func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				order = append(order, "second")
+				return next(req)
+			}
+		},
+	)
+
+	var tag Tag
+	if _, err := client.do(context.Background(), http.MethodGet, "/api/tags/7/", nil, &tag); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+
+	if tag.ID != 7 || tag.Name != "Invoices" {
+		t.Errorf("Unexpected decoded tag: %+v", tag)
+	}
+
+	if len(order) != 2 || order[0] != "first" || order[1] != "second" {
+		t.Errorf("Expected interceptors to run in order [first second], got %v", order)
+	}
+}
+
+// TestDoErrorStatus tests that non-2xx responses are returned as API errors
+func TestDoErrorStatus(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"detail": "Not found."}`))
+	}))
+	defer ts.Close()
+
+	client := New(ts.URL, "test-token")
+
+	_, err := client.GET(context.Background(), "/api/tags/999/")
+	if !IsNotFound(err) {
+		t.Fatalf("Expected not found error, got %v", err)
+	}
+}
+
+// TestMaxResponseSize tests that oversized responses are rejected
+func TestMaxResponseSize(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"count": 1, "next": null, "previous": null, "results": [{"id": 1}]}`))
+	}))
+	defer ts.Close()
+
+	client := New(ts.URL, "test-token")
+	client.SetMaxResponseSize(16)
+
+	if _, err := client.GET(context.Background(), "/api/tags/"); !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("Expected ErrResponseTooLarge from GET, got %v", err)
+	}
+
+	if _, err := client.ListTags(context.Background(), 1, 25); !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("Expected ErrResponseTooLarge from ListTags, got %v", err)
+	}
+
+	client.SetMaxResponseSize(0)
+	response, err := client.ListTags(context.Background(), 1, 25)
+	if err != nil {
+		t.Fatalf("Expected list to succeed with default limit, got %v", err)
+	}
+	if response.Count != 1 {
+		t.Errorf("Expected count 1, got %d", response.Count)
+	}
+}
+
+// TestRetryOnRateLimit tests that 429 responses are retried after the
+// Retry-After delay and surfaced as RateLimitError when the wait is too long
+func TestRetryOnRateLimit(t *testing.T) {
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if r.URL.Path == "/api/tags/1/" && attempts == 1 {
+			w.Header().Set(RetryAfterHeader, "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		if r.URL.Path == "/api/tags/2/" {
+			w.Header().Set(RetryAfterHeader, "120")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"detail": "Request was throttled."}`))
+			return
+		}
+		w.Write([]byte(`{"id": 1, "name": "Invoices"}`))
+	}))
+	defer ts.Close()
+
+	client := New(ts.URL, "test-token")
+	client.Use(RetryOnRateLimit(DefaultRateLimitRetries, time.Second))
+
+	// The retry is logged with the request's logger and its attributes
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil)).With("tool", "get_tag")
+	tag, err := client.GetTag(logging.WithLogger(context.Background(), logger), 1)
+	if err != nil {
+		t.Fatalf("Expected retry to succeed, got %v", err)
+	}
+	if tag.ID != 1 || attempts != 2 {
+		t.Errorf("Expected tag 1 after 2 attempts, got tag %d after %d attempts", tag.ID, attempts)
+	}
+	if !strings.Contains(logs.String(), "Rate limited by Paperless") || !strings.Contains(logs.String(), "tool=get_tag") {
+		t.Errorf("Expected the retry logged with the request's logger, got %q", logs.String())
+	}
+
+	_, err = client.GetTag(context.Background(), 2)
+	var rateLimitErr *RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		t.Fatalf("Expected RateLimitError, got %v", err)
+	}
+	if rateLimitErr.RetryAfter != 120*time.Second {
+		t.Errorf("Expected RetryAfter 120s, got %v", rateLimitErr.RetryAfter)
+	}
+	if err.Error() != "rate limited by Paperless, retry in 120s" {
+		t.Errorf("Unexpected error message: %s", err.Error())
+	}
+}
+
+// TestClassifyEndpoint tests that requests are grouped into the endpoint
+// classes request policies are configured for
+func TestClassifyEndpoint(t *testing.T) {
+	for _, tc := range []struct {
+		method, path, want string
+	}{
+		{http.MethodGet, "/api/documents/", EndpointClassList},
+		{http.MethodGet, "/api/tags/", EndpointClassList},
+		{http.MethodGet, "/api/tags/5/", EndpointClassRead},
+		{http.MethodGet, "/api/documents/5/metadata/", EndpointClassRead},
+		{http.MethodGet, "/api/ui_settings/", EndpointClassRead},
+		{http.MethodGet, "/api/documents/5/download/", EndpointClassDownload},
+		{http.MethodGet, "/api/documents/5/thumb/", EndpointClassDownload},
+		{http.MethodPost, "/api/documents/post_document/", EndpointClassUpload},
+		{http.MethodPost, "/api/documents/bulk_edit/", EndpointClassWrite},
+		{http.MethodDelete, "/api/tags/5/", EndpointClassWrite},
+	} {
+		if got := ClassifyEndpoint(tc.method, tc.path); got != tc.want {
+			t.Errorf("%s %s: expected %s, got %s", tc.method, tc.path, tc.want, got)
+		}
+	}
+}
+
+// TestApplyRequestPolicies tests that transient failures are retried as
+// often as their class allows, with the body sent again, that POSTs are
+// only retried when they were not handled, and that slow requests time out
+// per class
+func TestApplyRequestPolicies(t *testing.T) {
+	// The handler runs on the server's goroutines, including one still
+	// serving the timed out download
+	var mu sync.Mutex
+	attempts := make(map[string]int)
+	var bodies []string
+	attemptsOf := func(path string) int {
+		mu.Lock()
+		defer mu.Unlock()
+		return attempts[path]
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		attempts[r.URL.Path]++
+		attempt := attempts[r.URL.Path]
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/api/tags/":
+			if attempt == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`{"count": 0, "results": []}`))
+		case "/api/tags/1/":
+			if r.Method == http.MethodPatch {
+				body, _ := io.ReadAll(r.Body)
+				mu.Lock()
+				bodies = append(bodies, string(body))
+				mu.Unlock()
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+		case "/api/documents/1/download/":
+			time.Sleep(200 * time.Millisecond)
+			w.Write([]byte("file"))
+		case "/api/correspondents/":
+			w.WriteHeader(http.StatusGatewayTimeout)
+		case "/api/document_types/":
+			if attempt == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`{"id": 2, "name": "Invoice"}`))
+		}
+	}))
+	defer ts.Close()
+
+	client := New(ts.URL, "test-token")
+	client.SetTimeout(0)
+	client.Use(ApplyRequestPolicies(map[string]RequestPolicy{
+		EndpointClassList:     {Timeout: time.Second, Retries: 2},
+		EndpointClassRead:     {Timeout: time.Second, Retries: 2},
+		EndpointClassWrite:    {Timeout: time.Second, Retries: 1},
+		EndpointClassDownload: {Timeout: 50 * time.Millisecond},
+	}))
+
+	if _, err := client.ListTags(context.Background(), 1, 25); err != nil {
+		t.Fatalf("Expected the retried list to succeed, got %v", err)
+	}
+	if attemptsOf("/api/tags/") != 2 {
+		t.Errorf("Expected 2 list attempts, got %d", attemptsOf("/api/tags/"))
+	}
+
+	if _, err := client.GetTag(context.Background(), 1); err == nil {
+		t.Error("Expected a 500 response to fail")
+	}
+	if attemptsOf("/api/tags/1/") != 1 {
+		t.Errorf("Expected a 500 response not to be retried, got %d attempts", attemptsOf("/api/tags/1/"))
+	}
+
+	if _, err := client.UpdateTag(context.Background(), 1, map[string]interface{}{"name": "Invoices"}); err == nil {
+		t.Error("Expected the update to fail once its retries are used up")
+	}
+	mu.Lock()
+	if len(bodies) != 2 || bodies[0] != bodies[1] || !strings.Contains(bodies[1], "Invoices") {
+		t.Errorf("Expected the body to be sent with both attempts, got %q", bodies)
+	}
+	mu.Unlock()
+
+	if _, err := client.DownloadDocument(context.Background(), 1, false); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the download to time out, got %v", err)
+	}
+
+	// A POST that timed out upstream may have been applied, while a 503
+	// means it was not
+	if _, err := client.CreateCorrespondent(context.Background(), &Correspondent{Name: "Bell"}); err == nil {
+		t.Error("Expected a 504 response to fail")
+	}
+	if attemptsOf("/api/correspondents/") != 1 {
+		t.Errorf("Expected a POST answered with 504 not to be retried, got %d attempts", attemptsOf("/api/correspondents/"))
+	}
+	if _, err := client.CreateDocumentType(context.Background(), &DocumentType{Name: "Invoice"}); err != nil {
+		t.Errorf("Expected a POST answered with 503 to be retried, got %v", err)
+	}
+	if attemptsOf("/api/document_types/") != 2 {
+		t.Errorf("Expected 2 attempts of the POST, got %d", attemptsOf("/api/document_types/"))
+	}
+	dropped := errors.New("connection reset by peer")
+	if transientFailure(context.Background(), http.MethodPost, nil, dropped) || !transientFailure(context.Background(), http.MethodDelete, nil, dropped) {
+		t.Error("Expected a dropped connection to be retried for a DELETE only")
+	}
+}
+
+// TestInstrument tests that each attempt is reported with its endpoint
+// pattern and outcome class, including rate limited attempts that are
+// retried
+func TestInstrument(t *testing.T) {
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		switch {
+		case r.URL.Path == "/api/tags/1/" && attempts == 1:
+			w.Header().Set(RetryAfterHeader, "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case r.URL.Path == "/api/tags/2/":
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/api/tags/3/":
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			w.Write([]byte(`{"id": 1, "name": "Invoices"}`))
+		}
+	}))
+	defer ts.Close()
+
+	var observed []string
+	client := New(ts.URL, "test-token")
+	client.Use(RetryOnRateLimit(DefaultRateLimitRetries, time.Second))
+	client.Use(Instrument(func(method, endpoint, class string, duration time.Duration) {
+		observed = append(observed, method+" "+endpoint+" "+class)
+	}))
+
+	for id := 1; id <= 3; id++ {
+		client.GetTag(context.Background(), id)
+	}
+
+	want := []string{
+		"GET /api/tags/{id}/ rate_limited",
+		"GET /api/tags/{id}/ ok",
+		"GET /api/tags/{id}/ auth",
+		"GET /api/tags/{id}/ 5xx",
+	}
+	if !reflect.DeepEqual(observed, want) {
+		t.Errorf("Expected %v, got %v", want, observed)
+	}
+
+	if class := ClassifyOutcome(nil, context.DeadlineExceeded); class != ClassTimeout {
+		t.Errorf("Expected a deadline to be a timeout, got %s", class)
+	}
+	if class := ClassifyOutcome(nil, errors.New("connection refused")); class != ClassNetwork {
+		t.Errorf("Expected a network error, got %s", class)
+	}
+}
+
+// TestTokenRefreshOnUnauthorized tests that a 401 triggers a token refresh
+// and a single retry with the new token
+func TestTokenRefreshOnUnauthorized(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(AuthHeaderName) != "Token rotated" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"detail": "Invalid token."}`))
+			return
+		}
+		w.Write([]byte(`{"id": 3, "name": "Receipts"}`))
+	}))
+	defer ts.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("expired\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write token file: %v", err)
+	}
+
+	client := New(ts.URL, "")
+	source := NewFileTokenSource(tokenFile)
+	client.SetTokenSource(source)
+
+	if _, err := client.GetTag(context.Background(), 3); !IsUnauthorized(err) {
+		t.Fatalf("Expected unauthorized error with expired token, got %v", err)
+	}
+
+	// Rotate the token in place but keep the old modification time, so only
+	// the refresh triggered by the 401 can pick it up
+	info, err := os.Stat(tokenFile)
+	if err != nil {
+		t.Fatalf("Failed to stat token file: %v", err)
+	}
+	if err := os.WriteFile(tokenFile, []byte("rotated\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write token file: %v", err)
+	}
+	if err := os.Chtimes(tokenFile, info.ModTime(), info.ModTime()); err != nil {
+		t.Fatalf("Failed to reset token file times: %v", err)
+	}
+
+	tag, err := client.GetTag(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("Expected request with rotated token to succeed, got %v", err)
+	}
+	if tag.ID != 3 {
+		t.Errorf("Expected tag 3, got %d", tag.ID)
+	}
+}
+
+// TestBasicAuth tests header placement of reverse proxy basic credentials
+func TestBasicAuth(t *testing.T) {
+	var got http.Header
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.Write([]byte(`{"id": 1, "name": "Inbox"}`))
+	}))
+	defer ts.Close()
+
+	basic := "Basic " + base64.StdEncoding.EncodeToString([]byte("proxy:secret"))
+
+	client := New(ts.URL, "test-token")
+	client.SetBasicAuth("proxy", "secret", "")
+	if _, err := client.GetTag(context.Background(), 1); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got.Get(ProxyAuthHeaderName) != basic {
+		t.Errorf("Expected %s %q, got %q", ProxyAuthHeaderName, basic, got.Get(ProxyAuthHeaderName))
+	}
+	if got.Get(AuthHeaderName) != "Token test-token" {
+		t.Errorf("Expected token in %s, got %q", AuthHeaderName, got.Get(AuthHeaderName))
+	}
+
+	client.SetBasicAuth("proxy", "secret", "authorization")
+	if _, err := client.GetTag(context.Background(), 1); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got.Get(AuthHeaderName) != basic {
+		t.Errorf("Expected basic credentials in %s, got %q", AuthHeaderName, got.Get(AuthHeaderName))
+	}
+}
+
+func TestUploadDocument(t *testing.T) {
+	var form *multipart.Form
+	var content []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/documents/post_document/" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("Failed to parse multipart form: %v", err)
+		}
+		form = r.MultipartForm
+		f, _, err := r.FormFile("document")
+		if err != nil {
+			t.Fatalf("Missing document part: %v", err)
+		}
+		content, _ = io.ReadAll(f)
+		w.Write([]byte(`"4c4bd4b1-2f7c-4d1a-9c3e-0a5b8e1f2d3c"`))
+	}))
+	defer ts.Close()
+
+	correspondent, asn := 3, 1042
+	client := New(ts.URL, "test-token")
+	taskID, err := client.UploadDocument(context.Background(), "invoice.pdf", []byte("%PDF-1.4"), &UploadOptions{
+		Title:               "Invoice",
+		Created:             "2024-03-01",
+		Correspondent:       &correspondent,
+		Tags:                []int{1, 2},
+		ArchiveSerialNumber: &asn,
+		CustomFields:        map[int]interface{}{5: "INV-7"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if taskID != "4c4bd4b1-2f7c-4d1a-9c3e-0a5b8e1f2d3c" {
+		t.Errorf("Unexpected task ID %q", taskID)
+	}
+	if string(content) != "%PDF-1.4" {
+		t.Errorf("Unexpected file content %q", content)
+	}
+
+	want := map[string][]string{
+		"title":                 {"Invoice"},
+		"created":               {"2024-03-01"},
+		"correspondent":         {"3"},
+		"tags":                  {"1", "2"},
+		"archive_serial_number": {"1042"},
+		"custom_fields":         {`{"5":"INV-7"}`},
+	}
+	for key, values := range want {
+		if got := form.Value[key]; !reflect.DeepEqual(got, values) {
+			t.Errorf("Field %s: expected %v, got %v", key, values, got)
+		}
+	}
+	if _, ok := form.Value["document_type"]; ok {
+		t.Error("Unset overrides should not be sent")
+	}
+}
+
+func TestDownloadDocument(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/documents/7/download/" || r.URL.Query().Get("original") != "true" {
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Header().Set("Content-Disposition", `attachment; filename="scan.png"`)
+		w.Write([]byte("png-bytes"))
+	}))
+	defer ts.Close()
+
+	client := New(ts.URL, "test-token")
+	file, err := client.DownloadDocument(context.Background(), 7, true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if file.Filename != "scan.png" || file.ContentType != "image/png" || string(file.Content) != "png-bytes" {
+		t.Errorf("Unexpected file: %+v", file)
+	}
+
+	client.SetMaxResponseSize(4)
+	if _, err := client.DownloadDocument(context.Background(), 7, true); !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("Expected ErrResponseTooLarge, got %v", err)
+	}
+}
+
+func TestGetDocumentThumbnail(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/documents/404/thumb/" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"detail": "Not found."}`))
+			return
+		}
+		w.Header().Set("Content-Type", "image/webp")
+		w.Write([]byte("webp"))
+	}))
+	defer ts.Close()
+
+	client := New(ts.URL, "test-token")
+	file, err := client.GetDocumentThumbnail(context.Background(), 5)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if file.ContentType != "image/webp" || file.Filename != "thumbnail-5" {
+		t.Errorf("Unexpected thumbnail: %+v", file)
+	}
+	if _, err := client.GetDocumentThumbnail(context.Background(), 404); !IsNotFound(err) {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+}
+
+// TestListMatching tests that list options become Paperless' name filter
+// and ordering on the right endpoint
+func TestListMatching(t *testing.T) {
+	var path, name, ordering string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		name = r.URL.Query().Get("name__icontains")
+		ordering = r.URL.Query().Get("ordering")
+		w.Write([]byte(`{"count":1,"results":[{"id":4,"name":"Bell Canada"}]}`))
+	}))
+	defer ts.Close()
+
+	client := New(ts.URL, "test-token")
+	opts := ListOptions{Name: "bell can", Ordering: "-document_count"}
+	response, err := client.ListCorrespondentsMatching(context.Background(), opts, 1, 25)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if path != "/api/correspondents/" || name != "bell can" || ordering != "-document_count" {
+		t.Errorf("Expected a filtered, ordered list of correspondents, got %s with %q ordered by %q", path, name, ordering)
+	}
+	if response.Count != 1 {
+		t.Errorf("Expected 1 result, got %d", response.Count)
+	}
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// TestClientOptions tests injecting an HTTP client, transport wrappers and
+// a logger through New
+func TestClientOptions(t *testing.T) {
+	double := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{ContentTypeHeader: {ContentTypeJSON}},
+			Body:       io.NopCloser(strings.NewReader(`{"id":3,"name":"Bills"}`)),
+			Request:    req,
+		}, nil
+	})
+	var wrapped []string
+	wrapper := func(name string) TransportWrapper {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				wrapped = append(wrapped, name)
+				return next.RoundTrip(req)
+			})
+		}
+	}
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	hc := &http.Client{Transport: double}
+	client := New("http://paperless.invalid", "test-token",
+		WithHTTPClient(hc),
+		WithTransportWrapper(wrapper("outer")),
+		WithTransportWrapper(wrapper("inner")),
+		WithLogger(logger))
+
+	tag, err := client.GetTag(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if tag.Name != "Bills" {
+		t.Errorf("Expected the test double's response, got %+v", tag)
+	}
+	if strings.Join(wrapped, ",") != "outer,inner" {
+		t.Errorf("Expected wrappers outermost first, got %v", wrapped)
+	}
+	if hc.Transport == nil || reflect.ValueOf(hc.Transport).Pointer() != reflect.ValueOf(double).Pointer() {
+		t.Error("Expected the caller's HTTP client to be left unchanged")
+	}
+	if !strings.Contains(logs.String(), "Making API request") {
+		t.Errorf("Expected requests to be logged to the given logger, got %q", logs.String())
+	}
+
+	// Replacing the transport keeps the wrappers
+	wrapped = nil
+	client.SetTransport(double)
+	if _, err := client.GetTag(context.Background(), 3); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(wrapped) != 2 {
+		t.Errorf("Expected wrappers to survive SetTransport, got %v", wrapped)
+	}
+}
@@ -0,0 +1,88 @@
+package paperless
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+)
+
+// DocumentFile is a file downloaded from Paperless
+type DocumentFile struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
+
+// DownloadDocument downloads the file of a document: the archived PDF
+// version when one exists, or the original file when original is set.
+// Files larger than the client's maximum response size fail with
+// ErrResponseTooLarge.
+func (c *Client) DownloadDocument(ctx context.Context, documentID int, original bool) (*DocumentFile, error) {
+	path := fmt.Sprintf("/api/documents/%d/download/", documentID)
+	if original {
+		path += "?original=true"
+	}
+
+	c.log(ctx).Debug("Downloading document",
+		"document_id", documentID,
+		"original", original)
+
+	file, err := c.downloadFile(ctx, path, fmt.Sprintf("document-%d", documentID))
+	if err != nil {
+		return nil, err
+	}
+
+	c.log(ctx).Info("Document downloaded",
+		"document_id", documentID,
+		"filename", file.Filename,
+		"size", len(file.Content))
+
+	return file, nil
+}
+
+// GetDocumentThumbnail downloads the thumbnail image of a document
+func (c *Client) GetDocumentThumbnail(ctx context.Context, documentID int) (*DocumentFile, error) {
+	path := fmt.Sprintf("/api/documents/%d/thumb/", documentID)
+
+	c.log(ctx).Debug("Getting document thumbnail", "document_id", documentID)
+
+	return c.downloadFile(ctx, path, fmt.Sprintf("thumbnail-%d", documentID))
+}
+
+// downloadFile performs a GET request for a binary file, taking the
+// filename from Content-Disposition when present
+func (c *Client) downloadFile(ctx context.Context, path, defaultName string) (*DocumentFile, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	limited := &limitedReader{r: resp.Body, remaining: c.maxResponseSize}
+	content, err := io.ReadAll(limited)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, parseError(resp.StatusCode, content)
+	}
+	if err != nil {
+		if errors.Is(err, ErrResponseTooLarge) {
+			return nil, fmt.Errorf("%w: file exceeds %d bytes", ErrResponseTooLarge, c.maxResponseSize)
+		}
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	file := &DocumentFile{
+		Filename:    defaultName,
+		ContentType: resp.Header.Get(ContentTypeHeader),
+		Content:     content,
+	}
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil && params["filename"] != "" {
+		file.Filename = params["filename"]
+	}
+	if file.ContentType == "" {
+		file.ContentType = "application/octet-stream"
+	}
+	return file, nil
+}
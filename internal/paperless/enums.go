@@ -0,0 +1,123 @@
+package paperless
+
+// EnumValue describes one valid value of a Paperless enumeration
+type EnumValue struct {
+	Value       interface{} `json:"value"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+}
+
+// MatchingAlgorithms describes the values of matching_algorithm
+var MatchingAlgorithms = []EnumValue{
+	{MatchNone, "none", "Never assign automatically"},
+	{MatchAny, "any", "Document contains any of the words in match (quote phrases to keep them together)"},
+	{MatchAll, "all", "Document contains all of the words in match (quote phrases to keep them together)"},
+	{MatchLiteral, "literal", "Document contains match exactly as written"},
+	{MatchRegex, "regex", "Document matches the regular expression in match"},
+	{MatchFuzzy, "fuzzy", "Document contains a close approximation of match"},
+	{MatchAuto, "auto", "Learn assignments from existing documents with the Paperless classifier"},
+}
+
+// Custom field data types
+const (
+	CustomFieldString       = "string"
+	CustomFieldURL          = "url"
+	CustomFieldDate         = "date"
+	CustomFieldBoolean      = "boolean"
+	CustomFieldInteger      = "integer"
+	CustomFieldFloat        = "float"
+	CustomFieldMonetary     = "monetary"
+	CustomFieldDocumentLink = "documentlink"
+	CustomFieldSelect       = "select"
+)
+
+// CustomFieldDataTypes describes the values of a custom field's data_type
+var CustomFieldDataTypes = []EnumValue{
+	{CustomFieldString, "String", "Free text up to 128 characters"},
+	{CustomFieldURL, "URL", "A URL"},
+	{CustomFieldDate, "Date", "A date formatted YYYY-MM-DD"},
+	{CustomFieldBoolean, "Boolean", "true or false"},
+	{CustomFieldInteger, "Integer", "A whole number"},
+	{CustomFieldFloat, "Float", "A decimal number"},
+	{CustomFieldMonetary, "Monetary", "An amount with optional ISO 4217 currency prefix, e.g. EUR12.50"},
+	{CustomFieldDocumentLink, "Document link", "A list of document IDs"},
+	{CustomFieldSelect, "Select", "One of a fixed list of options defined on the field"},
+}
+
+// BulkEditMethods describes the methods of the documents bulk edit endpoint
+var BulkEditMethods = []EnumValue{
+	{"set_correspondent", "Set correspondent", "Set or clear the correspondent (parameter: correspondent)"},
+	{"set_document_type", "Set document type", "Set or clear the document type (parameter: document_type)"},
+	{"set_storage_path", "Set storage path", "Set or clear the storage path (parameter: storage_path)"},
+	{"add_tag", "Add tag", "Add one tag (parameter: tag)"},
+	{"remove_tag", "Remove tag", "Remove one tag (parameter: tag)"},
+	{"modify_tags", "Modify tags", "Add and remove several tags at once (parameters: add_tags, remove_tags)"},
+	{"modify_custom_fields", "Modify custom fields", "Add and remove custom fields (parameters: add_custom_fields, remove_custom_fields)"},
+	{"delete", "Delete", "Delete the documents"},
+	{"reprocess", "Reprocess", "Re-run OCR and consumption on the original files"},
+	{"set_permissions", "Set permissions", "Set owner and view/change permissions (parameters: owner, set_permissions, merge)"},
+	{"rotate", "Rotate", "Rotate pages (parameter: degrees)"},
+	{"merge", "Merge", "Merge the documents into a new one (parameters: metadata_document_id, delete_originals)"},
+	{"split", "Split", "Split a document into several (parameters: pages, delete_originals)"},
+	{"delete_pages", "Delete pages", "Delete pages from a document (parameter: pages)"},
+}
+
+// PermissionLevels describes the object permission levels
+var PermissionLevels = []EnumValue{
+	{"owner", "Owner", "Full control, including changing permissions and deleting; objects without an owner are visible to everyone"},
+	{"change", "Change", "View and edit the object (set_permissions.change.users / groups)"},
+	{"view", "View", "View the object only (set_permissions.view.users / groups)"},
+}
+
+// FilterRuleTypes describes the values of a saved view filter rule's
+// rule_type. Rules referring to objects hold their ID as value.
+var FilterRuleTypes = []EnumValue{
+	{0, "title_contains", "Title contains the value"},
+	{1, "content_contains", "Content contains the value"},
+	{2, "asn_is", "Archive serial number is the value"},
+	{3, "correspondent_is", "Correspondent is the correspondent ID"},
+	{4, "document_type_is", "Document type is the document type ID"},
+	{5, "is_in_inbox", "Document has an inbox tag"},
+	{6, "has_tag", "Document has the tag ID (all such rules must match)"},
+	{7, "has_any_tag", "Document has any tag (value true) or none (false)"},
+	{8, "created_before", "Created before the date"},
+	{9, "created_after", "Created after the date"},
+	{10, "created_year_is", "Created in the year"},
+	{11, "created_month_is", "Created in the month (1-12)"},
+	{12, "created_day_is", "Created on the day of the month"},
+	{13, "added_before", "Added before the date"},
+	{14, "added_after", "Added after the date"},
+	{15, "modified_before", "Modified before the date"},
+	{16, "modified_after", "Modified after the date"},
+	{17, "does_not_have_tag", "Document does not have the tag ID"},
+	{18, "does_not_have_asn", "Document has no archive serial number"},
+	{19, "title_or_content_contains", "Title or content contains the value"},
+	{20, "fulltext_query", "Full text search query"},
+	{21, "more_like_this", "Similar to the document ID"},
+	{22, "has_tags_in", "Document has any of the tag IDs given by such rules"},
+	{23, "asn_greater_than", "Archive serial number is greater than the value"},
+	{24, "asn_less_than", "Archive serial number is less than the value"},
+	{25, "storage_path_is", "Storage path is the storage path ID"},
+	{26, "has_correspondent_in", "Correspondent is any of the IDs given by such rules"},
+	{27, "does_not_have_correspondent_in", "Correspondent is none of the IDs given by such rules"},
+	{28, "has_document_type_in", "Document type is any of the IDs given by such rules"},
+	{29, "does_not_have_document_type_in", "Document type is none of the IDs given by such rules"},
+	{30, "has_storage_path_in", "Storage path is any of the IDs given by such rules"},
+	{31, "does_not_have_storage_path_in", "Storage path is none of the IDs given by such rules"},
+	{32, "owner_is", "Owner is the user ID"},
+	{33, "has_owner_in", "Owner is any of the user IDs given by such rules"},
+	{34, "does_not_have_owner", "Document has no owner (value true)"},
+	{35, "does_not_have_owner_in", "Owner is none of the user IDs given by such rules"},
+	{36, "has_custom_field_value", "A custom field value contains the value"},
+	{37, "is_shared_by_me", "Document is shared by the current user"},
+	{38, "has_custom_fields_all", "Document has all of the custom field IDs given by such rules"},
+	{39, "has_custom_field_in", "Document has any of the custom field IDs given by such rules"},
+	{40, "does_not_have_custom_field_in", "Document has none of the custom field IDs given by such rules"},
+	{41, "has_any_custom_field", "Document has any custom field (value true) or none (false)"},
+	{42, "custom_fields_query", "Custom field query in Paperless' JSON query syntax"},
+	{43, "created_to", "Created on or before the date"},
+	{44, "created_from", "Created on or after the date"},
+	{45, "added_to", "Added on or before the date"},
+	{46, "added_from", "Added on or after the date"},
+	{47, "mime_type_is", "Original file has the MIME type"},
+}
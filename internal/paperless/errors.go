@@ -1,18 +1,54 @@
 package paperless
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"sort"
+	"strings"
 )
 
+// ErrResponseTooLarge is returned when a response body exceeds the
+// client's configured maximum response size
+var ErrResponseTooLarge = errors.New("paperless response exceeds maximum allowed size")
+
+// Sentinel error categories for API errors, usable with errors.Is
+var (
+	ErrNotFound     = errors.New("paperless: not found")
+	ErrValidation   = errors.New("paperless: validation failed")
+	ErrConflict     = errors.New("paperless: conflict")
+	ErrUnauthorized = errors.New("paperless: unauthorized")
+	ErrUnavailable  = errors.New("paperless: temporarily unavailable")
+)
+
+// MaxErrorMessageLength bounds error messages taken from response bodies
+// that are not JSON
+const MaxErrorMessageLength = 300
+
+// UnavailableMessage replaces the body of 502, 503 and 504 responses that
+// are not from the Paperless API, such as proxy or maintenance pages
+const UnavailableMessage = "Paperless is temporarily unavailable (it may be restarting or in maintenance), try again shortly"
+
+// NonFieldErrorsKey is the key DRF uses for validation errors that are
+// not tied to a specific field
+const NonFieldErrorsKey = "non_field_errors"
+
+// FieldErrors maps request field names to their validation messages
+type FieldErrors map[string][]string
+
 // Error represents a Paperless API error
 type Error struct {
 	StatusCode int
 	Message    string
 	Details    map[string]interface{}
+	Fields     FieldErrors
 }
 
 func (e *Error) Error() string {
+	if len(e.Fields) > 0 {
+		return fmt.Sprintf("paperless API error (status %d): %s - %s",
+			e.StatusCode, e.Message, e.Fields.String())
+	}
 	if len(e.Details) > 0 {
 		return fmt.Sprintf("paperless API error (status %d): %s - %v",
 			e.StatusCode, e.Message, e.Details)
@@ -21,28 +57,161 @@ func (e *Error) Error() string {
 		e.StatusCode, e.Message)
 }
 
+// Unwrap returns the sentinel category matching the status code so that
+// callers can use errors.Is(err, ErrNotFound) and friends
+func (e *Error) Unwrap() error {
+	switch e.StatusCode {
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return ErrValidation
+	case http.StatusConflict:
+		return ErrConflict
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrUnauthorized
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return ErrUnavailable
+	}
+	return nil
+}
+
+// Retryable reports whether the request may succeed if retried later
+func (e *Error) Retryable() bool {
+	return errors.Is(e, ErrUnavailable)
+}
+
+// String formats field errors as "field: message; field: message" in
+// stable field order
+func (f FieldErrors) String() string {
+	fields := make([]string, 0, len(f))
+	for field := range f {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	parts := make([]string, 0, len(fields))
+	for _, field := range fields {
+		parts = append(parts, fmt.Sprintf("%s: %s", field, strings.Join(f[field], " ")))
+	}
+	return strings.Join(parts, "; ")
+}
+
 // NewError creates a new API error
 func NewError(statusCode int, message string, details map[string]interface{}) *Error {
-	return &Error{
+	apiErr := &Error{
 		StatusCode: statusCode,
 		Message:    message,
 		Details:    details,
 	}
+	if statusCode == http.StatusBadRequest {
+		apiErr.Fields = parseFieldErrors(details)
+	}
+	return apiErr
+}
+
+// parseFieldErrors extracts DRF per-field validation errors, which take
+// the shape {"field": ["message", ...]} with nested objects for related
+// fields (e.g. custom_fields), flattening nested keys with dots
+func parseFieldErrors(details map[string]interface{}) FieldErrors {
+	fields := make(FieldErrors)
+	collectFieldErrors(fields, "", details)
+	if len(fields) == 0 {
+		return nil
+	}
+	return fields
+}
+
+func collectFieldErrors(fields FieldErrors, prefix string, value interface{}) {
+	switch v := value.(type) {
+	case string:
+		if prefix != "" {
+			fields[prefix] = append(fields[prefix], v)
+		}
+	case []interface{}:
+		for i, item := range v {
+			if _, ok := item.(string); ok {
+				collectFieldErrors(fields, prefix, item)
+			} else {
+				collectFieldErrors(fields, fmt.Sprintf("%s[%d]", prefix, i), item)
+			}
+		}
+	case map[string]interface{}:
+		for key, item := range v {
+			// Top-level message keys are reported via Message, not Fields
+			if prefix == "" && (key == "detail" || key == "message" || key == "error") {
+				continue
+			}
+			name := key
+			if prefix != "" {
+				name = prefix + "." + key
+			}
+			collectFieldErrors(fields, name, item)
+		}
+	}
 }
 
 // IsNotFound checks if error is a 404
 func IsNotFound(err error) bool {
-	if apiErr, ok := err.(*Error); ok {
-		return apiErr.StatusCode == http.StatusNotFound
-	}
-	return false
+	return errors.Is(err, ErrNotFound)
 }
 
 // IsUnauthorized checks if error is a 401/403
 func IsUnauthorized(err error) bool {
-	if apiErr, ok := err.(*Error); ok {
-		return apiErr.StatusCode == http.StatusUnauthorized ||
-			apiErr.StatusCode == http.StatusForbidden
+	return errors.Is(err, ErrUnauthorized)
+}
+
+// IsValidation checks if error is a 400 validation failure
+func IsValidation(err error) bool {
+	return errors.Is(err, ErrValidation)
+}
+
+// IsConflict checks if error is a 409
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrConflict)
+}
+
+// IsUnavailable checks if error is a 502/503/504
+func IsUnavailable(err error) bool {
+	return errors.Is(err, ErrUnavailable)
+}
+
+// nonJSONMessage summarises a response body that is not JSON, such as an
+// HTML error page from a reverse proxy, without passing the raw markup on
+func nonJSONMessage(statusCode int, body []byte) string {
+	switch statusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return UnavailableMessage
+	}
+
+	text := strings.TrimSpace(string(body))
+	lower := strings.ToLower(text)
+	if strings.HasPrefix(lower, "<!doctype html") || strings.HasPrefix(lower, "<html") {
+		page := text
+		text = http.StatusText(statusCode)
+		// Offsets in lower only apply to page when lowering kept its length
+		start := strings.Index(lower, "<title>")
+		end := strings.Index(lower, "</title>")
+		if len(lower) == len(page) && start >= 0 && end > start {
+			if title := strings.TrimSpace(page[start+len("<title>") : end]); title != "" {
+				text = title
+			}
+		}
+	}
+	if len(text) > MaxErrorMessageLength {
+		text = strings.ToValidUTF8(text[:MaxErrorMessageLength], "") + "..."
+	}
+	if text == "" {
+		text = http.StatusText(statusCode)
+	}
+	return text
+}
+
+// GetFieldErrors returns the per-field validation errors carried by err,
+// or nil if err is not a Paperless validation error
+func GetFieldErrors(err error) FieldErrors {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Fields
 	}
-	return false
+	return nil
 }
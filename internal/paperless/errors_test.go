@@ -0,0 +1,85 @@
+package paperless
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// TestErrorSentinels tests that API errors unwrap to their status category
+func TestErrorSentinels(t *testing.T) {
+	tests := []struct {
+		status   int
+		sentinel error
+	}{
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusBadRequest, ErrValidation},
+		{http.StatusConflict, ErrConflict},
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusForbidden, ErrUnauthorized},
+	}
+
+	for _, tt := range tests {
+		err := error(parseError(tt.status, []byte(`{"detail": "failed"}`)))
+		if !errors.Is(err, tt.sentinel) {
+			t.Errorf("Expected status %d to match %v", tt.status, tt.sentinel)
+		}
+	}
+
+	if errors.Is(parseError(http.StatusInternalServerError, nil), ErrNotFound) {
+		t.Error("Expected status 500 not to match ErrNotFound")
+	}
+}
+
+// TestNonJSONErrors tests that proxy and maintenance pages become short
+// messages instead of raw HTML
+func TestNonJSONErrors(t *testing.T) {
+	page := []byte("<!DOCTYPE html><html><head><title>502 Bad Gateway</title></head><body>nginx</body></html>")
+	err := parseError(http.StatusBadGateway, page)
+	if err.Message != UnavailableMessage || !IsUnavailable(err) || !err.Retryable() {
+		t.Errorf("Expected a retryable unavailable error, got %v", err)
+	}
+
+	err = parseError(http.StatusNotFound, []byte("<html><head><title> Not here </title></head></html>"))
+	if err.Message != "Not here" {
+		t.Errorf("Expected the page title as message, got %q", err.Message)
+	}
+	err = parseError(http.StatusForbidden, []byte("<html><body>denied</body></html>"))
+	if err.Message != "Forbidden" {
+		t.Errorf("Expected the status text as message, got %q", err.Message)
+	}
+
+	err = parseError(http.StatusInternalServerError, []byte(strings.Repeat("x", 1000)))
+	if len(err.Message) != MaxErrorMessageLength+3 {
+		t.Errorf("Expected a truncated message, got %d bytes", len(err.Message))
+	}
+	if err.Retryable() {
+		t.Error("Expected a 500 not to be retryable")
+	}
+}
+
+// TestFieldErrors tests that DRF per-field validation errors are parsed
+func TestFieldErrors(t *testing.T) {
+	body := []byte(`{
+		"name": ["This field is required."],
+		"custom_fields": [{"value": ["Enter a valid date."]}],
+		"non_field_errors": ["Object violates owner / name unique constraint."]
+	}`)
+
+	err := parseError(http.StatusBadRequest, body)
+	if err.Message != "validation failed" {
+		t.Errorf("Expected message 'validation failed', got %q", err.Message)
+	}
+
+	fields := GetFieldErrors(err)
+	if got := fields["name"]; len(got) != 1 || got[0] != "This field is required." {
+		t.Errorf("Unexpected name errors: %v", got)
+	}
+	if got := fields["custom_fields[0].value"]; len(got) != 1 || got[0] != "Enter a valid date." {
+		t.Errorf("Unexpected custom field errors: %v", got)
+	}
+	if got := fields[NonFieldErrorsKey]; len(got) != 1 {
+		t.Errorf("Unexpected non-field errors: %v", got)
+	}
+}
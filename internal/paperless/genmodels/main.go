@@ -0,0 +1,342 @@
+// Command genmodels generates the Paperless API models of the paperless
+// package from the Paperless-ngx OpenAPI schema, adjusted by an overlay
+// where the schema and the API disagree or where the server needs its own
+// types. Properties of a model that the overlay does not mention are
+// generated as the schema describes them, so fields Paperless adds appear
+// with the next schema update; overlay entries for properties the schema
+// no longer has are errors, so drift is caught when generating.
+//
+// Usage:
+//
+//	go run ./genmodels -spec schema.yaml -overlay overlay.yaml -out models_gen.go
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// initialisms are name parts written in upper case in Go
+var initialisms = map[string]string{
+	"id":   "ID",
+	"url":  "URL",
+	"uuid": "UUID",
+}
+
+// spec is the part of an OpenAPI document models are generated from
+type spec struct {
+	Info struct {
+		Title   string `yaml:"title"`
+		Version string `yaml:"version"`
+	} `yaml:"info"`
+	Components struct {
+		Schemas map[string]schema `yaml:"schemas"`
+	} `yaml:"components"`
+}
+
+// schema is an OpenAPI schema object
+type schema struct {
+	Ref                  string        `yaml:"$ref"`
+	Type                 string        `yaml:"type"`
+	Format               string        `yaml:"format"`
+	Nullable             bool          `yaml:"nullable"`
+	Enum                 []interface{} `yaml:"enum"`
+	Items                *schema       `yaml:"items"`
+	AllOf                []schema      `yaml:"allOf"`
+	OneOf                []schema      `yaml:"oneOf"`
+	AnyOf                []schema      `yaml:"anyOf"`
+	Properties           properties    `yaml:"properties"`
+	AdditionalProperties interface{}   `yaml:"additionalProperties"`
+}
+
+// property is a named property of an object schema
+type property struct {
+	Name   string
+	Schema schema
+}
+
+// properties keeps the properties of an object schema in schema order
+type properties []property
+
+func (p *properties) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: properties must be a mapping", node.Line)
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		var s schema
+		if err := node.Content[i+1].Decode(&s); err != nil {
+			return err
+		}
+		*p = append(*p, property{Name: node.Content[i].Value, Schema: s})
+	}
+	return nil
+}
+
+// overlay adjusts the generated models
+type overlay struct {
+	// Formats maps string formats, such as date-time, to Go types
+	Formats map[string]string `yaml:"formats"`
+	Models  []overlayModel    `yaml:"models"`
+}
+
+// overlayModel is one model to generate
+type overlayModel struct {
+	Name   string                  `yaml:"name"`
+	Schema string                  `yaml:"schema"`
+	Doc    string                  `yaml:"doc"`
+	Skip   []string                `yaml:"skip"`
+	Fields map[string]overlayField `yaml:"fields"`
+	Extra  []extraField            `yaml:"extra"`
+}
+
+// overlayField overrides how a schema property is generated
+type overlayField struct {
+	Type      string `yaml:"type"`
+	Omitempty bool   `yaml:"omitempty"`
+	Doc       string `yaml:"doc"`
+}
+
+// extraField is a field the schema does not have
+type extraField struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type"`
+	JSON string `yaml:"json"`
+	Doc  string `yaml:"doc"`
+}
+
+func main() {
+	specPath := flag.String("spec", "", "Paperless-ngx OpenAPI schema (YAML)")
+	overlayPath := flag.String("overlay", "", "overlay adjusting the generated models (YAML)")
+	out := flag.String("out", "", "Go file to write")
+	flag.Parse()
+
+	if err := run(*specPath, *overlayPath, *out); err != nil {
+		fmt.Fprintf(os.Stderr, "genmodels: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run generates the models and writes them to out
+func run(specPath, overlayPath, out string) error {
+	if specPath == "" || overlayPath == "" || out == "" {
+		return fmt.Errorf("-spec, -overlay and -out are required")
+	}
+	specData, err := os.ReadFile(specPath)
+	if err != nil {
+		return err
+	}
+	overlayData, err := os.ReadFile(overlayPath)
+	if err != nil {
+		return err
+	}
+	src, err := generate(specData, overlayData)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(out, src, 0o644)
+}
+
+// generate renders the Go source of the models described by the overlay
+func generate(specData, overlayData []byte) ([]byte, error) {
+	var api spec
+	if err := yaml.Unmarshal(specData, &api); err != nil {
+		return nil, fmt.Errorf("failed to parse schema: %w", err)
+	}
+	var adjust overlay
+	if err := yaml.Unmarshal(overlayData, &adjust); err != nil {
+		return nil, fmt.Errorf("failed to parse overlay: %w", err)
+	}
+
+	g := &generator{api: api, overlay: adjust, models: make(map[string]string)}
+	for _, model := range adjust.Models {
+		g.models[model.Schema] = model.Name
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by genmodels from the %s %s schema; DO NOT EDIT.\n\n", api.Info.Title, api.Info.Version)
+	buf.WriteString("package paperless\n")
+
+	var problems []error
+	for _, model := range adjust.Models {
+		if err := g.model(&buf, model); err != nil {
+			problems = append(problems, err)
+		}
+	}
+	if len(problems) > 0 {
+		return nil, errors.Join(problems...)
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w", err)
+	}
+	return src, nil
+}
+
+// generator renders models from one schema and overlay
+type generator struct {
+	api     spec
+	overlay overlay
+	// models maps schema names to the Go names of generated models
+	models map[string]string
+}
+
+// model renders one model
+func (g *generator) model(buf *bytes.Buffer, model overlayModel) error {
+	s, ok := g.api.Components.Schemas[model.Schema]
+	if !ok {
+		return fmt.Errorf("%s: schema %s does not exist", model.Name, model.Schema)
+	}
+
+	var problems []error
+	known := make(map[string]bool, len(s.Properties))
+	for _, prop := range s.Properties {
+		known[prop.Name] = true
+	}
+	skip := make(map[string]bool, len(model.Skip))
+	for _, name := range model.Skip {
+		skip[name] = true
+	}
+	for _, name := range append(append([]string{}, model.Skip...), sortedKeys(model.Fields)...) {
+		if !known[name] {
+			problems = append(problems, fmt.Errorf("%s: the overlay names property %s, which schema %s no longer has", model.Name, name, model.Schema))
+		}
+	}
+
+	fmt.Fprintf(buf, "\n// %s\n", strings.ReplaceAll(strings.TrimSpace(model.Doc), "\n", "\n// "))
+	fmt.Fprintf(buf, "type %s struct {\n", model.Name)
+	for _, prop := range s.Properties {
+		if skip[prop.Name] {
+			continue
+		}
+		field := model.Fields[prop.Name]
+		typ := field.Type
+		if typ == "" {
+			var err error
+			if typ, err = g.goType(prop.Schema); err != nil {
+				problems = append(problems, fmt.Errorf("%s.%s: %w; set its type in the overlay or skip it", model.Name, prop.Name, err))
+				continue
+			}
+		}
+		tag := prop.Name
+		if field.Omitempty {
+			tag += ",omitempty"
+		}
+		writeDoc(buf, field.Doc)
+		fmt.Fprintf(buf, "\t%s %s `json:%q`\n", goName(prop.Name), typ, tag)
+	}
+	for _, extra := range model.Extra {
+		writeDoc(buf, extra.Doc)
+		fmt.Fprintf(buf, "\t%s %s `json:%q`\n", extra.Name, extra.Type, extra.JSON)
+	}
+	buf.WriteString("}\n")
+
+	return errors.Join(problems...)
+}
+
+// goType returns the Go type of a schema
+func (g *generator) goType(s schema) (string, error) {
+	switch {
+	case s.Ref != "":
+		name := s.Ref[strings.LastIndex(s.Ref, "/")+1:]
+		if model, ok := g.models[name]; ok {
+			return nullable(model, s.Nullable), nil
+		}
+		target, ok := g.api.Components.Schemas[name]
+		if !ok {
+			return "", fmt.Errorf("schema %s does not exist", name)
+		}
+		if len(target.Enum) == 0 {
+			return "", fmt.Errorf("schema %s is not generated", name)
+		}
+		target.Nullable = s.Nullable
+		return g.goType(target)
+	case len(s.AllOf) == 1:
+		inner := s.AllOf[0]
+		inner.Nullable = inner.Nullable || s.Nullable
+		return g.goType(inner)
+	case len(s.OneOf)+len(s.AnyOf) > 0:
+		return "interface{}", nil
+	}
+
+	switch s.Type {
+	case "string":
+		if typ, ok := g.overlay.Formats[s.Format]; ok {
+			return typ, nil
+		}
+		return nullable("string", s.Nullable), nil
+	case "integer":
+		return nullable("int", s.Nullable), nil
+	case "number":
+		return nullable("float64", s.Nullable), nil
+	case "boolean":
+		return nullable("bool", s.Nullable), nil
+	case "array":
+		if s.Items == nil {
+			return "[]interface{}", nil
+		}
+		item, err := g.goType(*s.Items)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + item, nil
+	case "object":
+		if len(s.Properties) > 0 {
+			return "", fmt.Errorf("inline objects are not supported")
+		}
+		return "map[string]interface{}", nil
+	case "":
+		return "interface{}", nil
+	}
+	return "", fmt.Errorf("unsupported type %s", s.Type)
+}
+
+// nullable makes typ a pointer when the schema allows null
+func nullable(typ string, isNullable bool) string {
+	if isNullable {
+		return "*" + typ
+	}
+	return typ
+}
+
+// goName converts a snake_case property name to an exported Go name
+func goName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		if upper, ok := initialisms[part]; ok {
+			b.WriteString(upper)
+		} else if part != "" {
+			b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	return b.String()
+}
+
+// writeDoc writes a field comment
+func writeDoc(buf *bytes.Buffer, doc string) {
+	doc = strings.TrimSpace(doc)
+	if doc == "" {
+		return
+	}
+	for _, line := range strings.Split(doc, "\n") {
+		fmt.Fprintf(buf, "\t// %s\n", line)
+	}
+}
+
+// sortedKeys returns the keys of m in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestModelsUpToDate tests that models_gen.go is what the schema and
+// overlay generate, so neither is changed without regenerating
+func TestModelsUpToDate(t *testing.T) {
+	specData, err := os.ReadFile("../../../docs/paperless_api_schema.yaml")
+	if err != nil {
+		t.Fatalf("Failed to read schema: %v", err)
+	}
+	overlayData, err := os.ReadFile("../models_overlay.yaml")
+	if err != nil {
+		t.Fatalf("Failed to read overlay: %v", err)
+	}
+	committed, err := os.ReadFile("../models_gen.go")
+	if err != nil {
+		t.Fatalf("Failed to read generated models: %v", err)
+	}
+
+	generated, err := generate(specData, overlayData)
+	if err != nil {
+		t.Fatalf("Failed to generate models: %v", err)
+	}
+	if string(generated) != string(committed) {
+		t.Error("models_gen.go is out of date, run go generate ./internal/paperless")
+	}
+}
+
+// TestGenerateDetectsDrift tests that overlay entries for properties the
+// schema no longer has, and properties without a Go type, are errors
+func TestGenerateDetectsDrift(t *testing.T) {
+	specData := []byte(`
+info: {title: Test API, version: "1"}
+components:
+  schemas:
+    Widget:
+      type: object
+      properties:
+        id: {type: integer, readOnly: true}
+        label: {type: string, nullable: true}
+        added: {type: string, format: date-time}
+        kind: {allOf: [{$ref: '#/components/schemas/KindEnum'}]}
+        settings: {type: object, properties: {colour: {type: string}}}
+    KindEnum: {type: integer, enum: [1, 2]}
+`)
+
+	generated, err := generate(specData, []byte(`
+formats: {date-time: FlexibleTime}
+models:
+  - {name: Widget, schema: Widget, doc: Widget is a test model, skip: [settings]}
+`))
+	if err != nil {
+		t.Fatalf("Failed to generate models: %v", err)
+	}
+	for _, want := range []string{"ID int `json:\"id\"`", "Label *string", "Added FlexibleTime", "Kind int"} {
+		if !strings.Contains(strings.Join(strings.Fields(string(generated)), " "), want) {
+			t.Errorf("Expected %q in generated code:\n%s", want, generated)
+		}
+	}
+
+	_, err = generate(specData, []byte(`
+models:
+  - {name: Widget, schema: Widget, doc: Widget is a test model, fields: {colour: {omitempty: true}}}
+`))
+	if err == nil || !strings.Contains(err.Error(), "no longer has") || !strings.Contains(err.Error(), "Widget.settings: inline objects are not supported") {
+		t.Errorf("Expected a stale overlay entry and an unsupported property to be reported, got %v", err)
+	}
+}
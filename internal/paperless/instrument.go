@@ -0,0 +1,81 @@
+package paperless
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Request outcome classes reported by Instrument, chosen so that a slow or
+// failing Paperless can be told apart from a rejected token
+const (
+	ClassOK          = "ok"
+	ClassTimeout     = "timeout"
+	ClassNetwork     = "network"
+	ClassCanceled    = "canceled"
+	ClassRateLimited = "rate_limited"
+	ClassAuth        = "auth"
+	ClassClientError = "4xx"
+	ClassServerError = "5xx"
+)
+
+// RequestObserver is called once for every request attempt with the
+// endpoint pattern, the outcome class and how long the attempt took
+type RequestObserver func(method, endpoint, class string, duration time.Duration)
+
+// Instrument reports every request attempt to observe. Installed inside
+// RetryOnRateLimit, each retried attempt is reported separately.
+func Instrument(observe RequestObserver) Interceptor {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next(req)
+			observe(req.Method, EndpointPattern(req.URL.Path), ClassifyOutcome(resp, err), time.Since(start))
+			return resp, err
+		}
+	}
+}
+
+// ClassifyOutcome returns the class of a request attempt's result
+func ClassifyOutcome(resp *http.Response, err error) string {
+	if err != nil {
+		var netErr net.Error
+		switch {
+		case errors.Is(err, context.Canceled):
+			return ClassCanceled
+		case errors.Is(err, context.DeadlineExceeded),
+			errors.As(err, &netErr) && netErr.Timeout():
+			return ClassTimeout
+		default:
+			return ClassNetwork
+		}
+	}
+
+	switch code := resp.StatusCode; {
+	case code == http.StatusTooManyRequests:
+		return ClassRateLimited
+	case code == http.StatusUnauthorized, code == http.StatusForbidden:
+		return ClassAuth
+	case code >= 500:
+		return ClassServerError
+	case code >= 400:
+		return ClassClientError
+	default:
+		return ClassOK
+	}
+}
+
+// EndpointPattern replaces the numeric IDs in an API path with {id}, so
+// that metrics are labelled per endpoint rather than per object
+func EndpointPattern(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if segment != "" && strings.Trim(segment, "0123456789") == "" {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,42 @@
+package paperless
+
+import (
+	"context"
+)
+
+// Values of a mail rule's assign_title_from and consumption_scope
+const (
+	MailTitleFromSubject  = 1
+	MailTitleFromFilename = 2
+
+	MailScopeAttachments       = 1
+	MailScopeEML               = 2
+	MailScopeEMLAndAttachments = 3
+)
+
+// MailProvenance describes how a document arrived by e-mail. Paperless
+// does not record the mail rule that consumed a document, so the rule is
+// attributed from the tags it assigns; Basis says why. The subject and
+// sender are known only when the e-mail itself was consumed as .eml, or
+// the rule titled the document with the subject.
+type MailProvenance struct {
+	MailRule        int    `json:"mail_rule,omitempty"`
+	MailRuleName    string `json:"mail_rule_name,omitempty"`
+	MailAccount     int    `json:"mail_account,omitempty"`
+	MailAccountName string `json:"mail_account_name,omitempty"`
+	Basis           string `json:"basis,omitempty"`
+	Subject         string `json:"subject,omitempty"`
+	SubjectSource   string `json:"subject_source,omitempty"` // email or title
+	From            string `json:"from,omitempty"`
+}
+
+// ListMailRules retrieves mail rules with pagination, in the order
+// Paperless applies them
+func (c *Client) ListMailRules(ctx context.Context, page, pageSize int) (*PaginatedResponse, error) {
+	return c.listMatching(ctx, "/api/mail_rules/", ListOptions{Ordering: "order"}, page, pageSize)
+}
+
+// ListMailAccounts retrieves mail accounts with pagination
+func (c *Client) ListMailAccounts(ctx context.Context, page, pageSize int) (*PaginatedResponse, error) {
+	return c.listMatching(ctx, "/api/mail_accounts/", ListOptions{}, page, pageSize)
+}
@@ -0,0 +1,170 @@
+package paperless
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Matching algorithms used by tags, correspondents, document types and
+// storage paths
+const (
+	MatchNone    = 0
+	MatchAny     = 1
+	MatchAll     = 2
+	MatchLiteral = 3
+	MatchRegex   = 4
+	MatchFuzzy   = 5
+	MatchAuto    = 6
+)
+
+// FuzzyMatchThreshold is the minimum partial ratio, out of 100, for a fuzzy
+// match to fire
+const FuzzyMatchThreshold = 90
+
+// ErrMatchNotEvaluable is returned for the auto algorithm, which depends on
+// the classifier trained inside Paperless
+var ErrMatchNotEvaluable = errors.New("auto matching uses the Paperless classifier and cannot be evaluated locally")
+
+// MatchingAlgorithmNames maps matching algorithm values to their names
+var MatchingAlgorithmNames = map[int]string{
+	MatchNone:    "none",
+	MatchAny:     "any",
+	MatchAll:     "all",
+	MatchLiteral: "literal",
+	MatchRegex:   "regex",
+	MatchFuzzy:   "fuzzy",
+	MatchAuto:    "auto",
+}
+
+var (
+	matchTermPattern   = regexp.MustCompile(`"([^"]+)"|(\S+)`)
+	whitespacePattern  = regexp.MustCompile(`\s+`)
+	punctuationPattern = regexp.MustCompile(`[^\w\s]`)
+)
+
+// splitMatch splits a match string into terms, keeping quoted phrases
+// together, and returns each term as a regular expression that tolerates
+// any whitespace between words
+func splitMatch(match string) []string {
+	var terms []string
+	for _, groups := range matchTermPattern.FindAllStringSubmatch(match, -1) {
+		term := groups[1]
+		if term == "" {
+			term = groups[2]
+		}
+		term = whitespacePattern.ReplaceAllString(strings.TrimSpace(term), " ")
+		if term == "" {
+			continue
+		}
+		terms = append(terms, strings.ReplaceAll(regexp.QuoteMeta(term), " ", `\s+`))
+	}
+	return terms
+}
+
+// MatchText reports whether a matching rule would fire for text, following
+// the rules Paperless applies when consuming a document. It also returns the
+// terms that matched for the any and all algorithms. Regular expressions use
+// Go syntax, which differs from Python's in a few edge cases.
+func MatchText(match string, algorithm int, insensitive bool, text string) (bool, []string, error) {
+	flags := ""
+	if insensitive {
+		flags = "(?i)"
+	}
+
+	switch algorithm {
+	case MatchNone:
+		return false, nil, nil
+
+	case MatchAny, MatchAll:
+		var matched []string
+		terms := splitMatch(match)
+		for _, term := range terms {
+			re, err := regexp.Compile(flags + `\b` + term + `\b`)
+			if err != nil {
+				return false, nil, fmt.Errorf("invalid match term %q: %w", term, err)
+			}
+			if re.MatchString(text) {
+				matched = append(matched, strings.ReplaceAll(term, `\s+`, " "))
+			}
+		}
+		if algorithm == MatchAny {
+			return len(matched) > 0, matched, nil
+		}
+		return len(terms) > 0 && len(matched) == len(terms), matched, nil
+
+	case MatchLiteral:
+		re, err := regexp.Compile(flags + `\b` + regexp.QuoteMeta(match) + `\b`)
+		if err != nil {
+			return false, nil, fmt.Errorf("invalid literal match: %w", err)
+		}
+		return re.MatchString(text), nil, nil
+
+	case MatchRegex:
+		re, err := regexp.Compile(flags + match)
+		if err != nil {
+			return false, nil, fmt.Errorf("invalid regular expression: %w", err)
+		}
+		return re.MatchString(text), nil, nil
+
+	case MatchFuzzy:
+		pattern := punctuationPattern.ReplaceAllString(match, "")
+		content := punctuationPattern.ReplaceAllString(text, "")
+		if insensitive {
+			pattern = strings.ToLower(pattern)
+			content = strings.ToLower(content)
+		}
+		return partialRatio(pattern, content) >= FuzzyMatchThreshold, nil, nil
+
+	case MatchAuto:
+		return false, nil, ErrMatchNotEvaluable
+
+	default:
+		return false, nil, fmt.Errorf("unknown matching algorithm %d", algorithm)
+	}
+}
+
+// partialRatio returns the best similarity, out of 100, between the shorter
+// string and any equally long substring of the longer one
+func partialRatio(a, b string) float64 {
+	short, long := []rune(a), []rune(b)
+	if len(short) > len(long) {
+		short, long = long, short
+	}
+	if len(short) == 0 {
+		return 0
+	}
+
+	best := 0
+	for start := 0; start+len(short) <= len(long); start++ {
+		if lcs := longestCommonSubsequence(short, long[start:start+len(short)]); lcs > best {
+			best = lcs
+			if best == len(short) {
+				break
+			}
+		}
+	}
+	return float64(best) * 100 / float64(len(short))
+}
+
+// longestCommonSubsequence returns the length of the longest common
+// subsequence of a and b
+func longestCommonSubsequence(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			switch {
+			case a[i-1] == b[j-1]:
+				curr[j] = prev[j-1] + 1
+			case prev[j] > curr[j-1]:
+				curr[j] = prev[j]
+			default:
+				curr[j] = curr[j-1]
+			}
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,50 @@
+package paperless
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMatchText(t *testing.T) {
+	text := "Invoice from ACME Corporation, order 12345"
+
+	tests := []struct {
+		name        string
+		match       string
+		algorithm   int
+		insensitive bool
+		want        bool
+	}{
+		{"none", "acme", MatchNone, true, false},
+		{"any", "foo acme", MatchAny, true, true},
+		{"any case sensitive", "foo acme", MatchAny, false, false},
+		{"any word boundary", "acm", MatchAny, true, false},
+		{"all", "invoice acme", MatchAll, true, true},
+		{"all missing", "invoice receipt", MatchAll, true, false},
+		{"all quoted phrase", `"acme  corporation" order`, MatchAll, true, true},
+		{"literal", "ACME Corporation", MatchLiteral, false, true},
+		{"literal order", "Corporation ACME", MatchLiteral, false, false},
+		{"regex", `order \d{5}`, MatchRegex, false, true},
+		{"fuzzy", "ACME Corporaton", MatchFuzzy, true, true},
+		{"fuzzy no match", "Globex Industries", MatchFuzzy, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := MatchText(tt.match, tt.algorithm, tt.insensitive, text)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("MatchText(%q) = %v, want %v", tt.match, got, tt.want)
+			}
+		})
+	}
+
+	if _, _, err := MatchText("x", MatchAuto, true, text); !errors.Is(err, ErrMatchNotEvaluable) {
+		t.Errorf("auto error = %v, want ErrMatchNotEvaluable", err)
+	}
+	if _, _, err := MatchText("(", MatchRegex, true, text); err == nil {
+		t.Error("expected error for invalid regex")
+	}
+}
@@ -0,0 +1,72 @@
+package paperless
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+)
+
+// Option configures a Client created with New
+type Option func(*Client)
+
+// TransportWrapper wraps the HTTP transport used to reach Paperless, e.g.
+// to add tracing, a proxy or a test double
+type TransportWrapper func(http.RoundTripper) http.RoundTripper
+
+// WithHTTPClient makes the client send requests with a copy of hc instead
+// of its default HTTP client, keeping hc's timeout, cookie jar and
+// redirect policy. A nil transport means http.DefaultTransport.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		if hc == nil {
+			return
+		}
+		httpClient := *hc
+		if httpClient.Transport == nil {
+			httpClient.Transport = http.DefaultTransport
+		}
+		c.httpClient = &httpClient
+	}
+}
+
+// WithTransportWrapper wraps the client's HTTP transport. Wrappers are
+// kept when the transport is later replaced with SetTransport, and the
+// first one given is the outermost.
+func WithTransportWrapper(wrap TransportWrapper) Option {
+	return func(c *Client) {
+		if wrap != nil {
+			c.transportWrappers = append(c.transportWrappers, wrap)
+		}
+	}
+}
+
+// WithLogger makes the client log to logger instead of the default slog
+// logger, for requests whose context carries no logger of its own
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// wrapTransport applies the client's transport wrappers to transport
+func (c *Client) wrapTransport(transport http.RoundTripper) http.RoundTripper {
+	for i := len(c.transportWrappers) - 1; i >= 0; i-- {
+		transport = c.transportWrappers[i](transport)
+	}
+	return transport
+}
+
+// log returns the logger the client writes to while serving ctx: the
+// request's own logger when it carries one, so client log lines keep the
+// caller's session and tool, otherwise the client's logger
+func (c *Client) log(ctx context.Context) *slog.Logger {
+	if logging.HasLogger(ctx) {
+		return logging.FromContext(ctx)
+	}
+	if c.logger != nil {
+		return c.logger
+	}
+	return slog.Default()
+}
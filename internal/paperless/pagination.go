@@ -0,0 +1,37 @@
+package paperless
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// MaxCollectPages bounds how many pages CollectAll will fetch, protecting
+// against a server that never reports a last page
+const MaxCollectPages = 1000
+
+// ListFunc fetches one page of a paginated list endpoint
+type ListFunc func(ctx context.Context, page, pageSize int) (*PaginatedResponse, error)
+
+// CollectAll pages through a list endpoint using the largest page size and
+// returns every result decoded as T
+func CollectAll[T any](ctx context.Context, list ListFunc) ([]T, error) {
+	var all []T
+	for page := 1; page <= MaxCollectPages; page++ {
+		response, err := list(ctx, page, MaxPageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		var items []T
+		if err := json.Unmarshal(response.Results, &items); err != nil {
+			return nil, fmt.Errorf("failed to parse page %d: %w", page, err)
+		}
+		all = append(all, items...)
+
+		if response.Next == nil || len(items) == 0 {
+			return all, nil
+		}
+	}
+	return nil, fmt.Errorf("gave up after %d pages", MaxCollectPages)
+}
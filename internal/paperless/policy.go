@@ -0,0 +1,171 @@
+package paperless
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+)
+
+// Endpoint classes requests are grouped in for timeout and retry
+// policies, since a file upload and a tag lookup should not share one
+// timeout
+const (
+	EndpointClassList     = "list"
+	EndpointClassRead     = "read"
+	EndpointClassWrite    = "write"
+	EndpointClassUpload   = "upload"
+	EndpointClassDownload = "download"
+)
+
+// DefaultRetryBackoff is the wait before the first retry of a transient
+// failure; it doubles with every further attempt
+const DefaultRetryBackoff = 500 * time.Millisecond
+
+// singletonResources are API resources whose root is a single object
+// rather than a list
+var singletonResources = map[string]bool{
+	"profile":        true,
+	"remote_version": true,
+	"schema":         true,
+	"statistics":     true,
+	"status":         true,
+	"ui_settings":    true,
+}
+
+// fileEndpoints are the last path segments of endpoints returning files
+var fileEndpoints = map[string]bool{
+	"download": true,
+	"preview":  true,
+	"thumb":    true,
+}
+
+// RequestPolicy is the timeout and retry behaviour of one endpoint class
+type RequestPolicy struct {
+	// Timeout bounds each attempt, including reading the response body;
+	// 0 means no timeout
+	Timeout time.Duration
+	// Retries is how often a transient failure (a network error, a timed
+	// out attempt or a 502, 503 or 504 response) is retried. POST and PATCH
+	// requests are only retried on 502 and 503 responses.
+	Retries int
+}
+
+// ClassifyEndpoint returns the endpoint class of a request
+func ClassifyEndpoint(method, path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	last := segments[len(segments)-1]
+	switch {
+	case method == http.MethodPost && last == "post_document":
+		return EndpointClassUpload
+	case method != http.MethodGet && method != http.MethodHead:
+		return EndpointClassWrite
+	case fileEndpoints[last]:
+		return EndpointClassDownload
+	case len(segments) == 2 && segments[0] == "api" && !singletonResources[last]:
+		return EndpointClassList
+	}
+	return EndpointClassRead
+}
+
+// ApplyRequestPolicies returns an interceptor that bounds and retries each
+// request according to the policy of its endpoint class. Requests of
+// classes without a policy pass through unchanged. The client's own
+// timeout still applies on top, so it should be disabled with SetTimeout
+// when policies allow longer requests.
+func ApplyRequestPolicies(policies map[string]RequestPolicy) Interceptor {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			class := ClassifyEndpoint(req.Method, req.URL.Path)
+			policy, ok := policies[class]
+			if !ok {
+				return next(req)
+			}
+
+			// Keep a replayable copy before the first attempt reads the body
+			retry, canRetry := cloneRequest(req)
+			resp, err := attemptWithTimeout(next, req, policy.Timeout)
+			backoff := DefaultRetryBackoff
+			for attempt := 1; attempt <= policy.Retries && canRetry && transientFailure(req.Context(), req.Method, resp, err); attempt++ {
+				logging.FromContext(req.Context()).Warn("Paperless request failed, retrying",
+					"url", req.URL.String(),
+					"class", class,
+					"attempt", attempt,
+					"outcome", ClassifyOutcome(resp, err))
+
+				// Drain and close so the connection can be reused
+				if resp != nil {
+					io.Copy(io.Discard, resp.Body)
+					resp.Body.Close()
+				}
+
+				timer := time.NewTimer(backoff)
+				select {
+				case <-req.Context().Done():
+					timer.Stop()
+					return nil, req.Context().Err()
+				case <-timer.C:
+				}
+				backoff *= 2
+
+				attemptReq := retry
+				retry, canRetry = cloneRequest(retry)
+				resp, err = attemptWithTimeout(next, attemptReq, policy.Timeout)
+			}
+			return resp, err
+		}
+	}
+}
+
+// attemptWithTimeout sends req, cancelling it after timeout. The deadline
+// also covers reading the body, so it is released when the body is closed.
+func attemptWithTimeout(next RoundTripFunc, req *http.Request, timeout time.Duration) (*http.Response, error) {
+	if timeout <= 0 {
+		return next(req)
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	resp, err := next(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnClose releases a request's context when its body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// transientFailure reports whether an attempt failed in a way a retry may
+// fix. Failures caused by the caller giving up are not retried. A POST or
+// PATCH that failed with a network error, a timeout or a 504 may have been
+// applied all the same, and is only retried when the response says it
+// was not handled: a 502 or 503.
+func transientFailure(ctx context.Context, method string, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	idempotent := method != http.MethodPost && method != http.MethodPatch
+	if err != nil {
+		return idempotent
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return true
+	case http.StatusGatewayTimeout:
+		return idempotent
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+package paperless
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+)
+
+// Rate limit constants
+const (
+	RetryAfterHeader         = "Retry-After"
+	DefaultRateLimitRetries  = 2
+	DefaultRateLimitMaxWait  = 10 * time.Second
+	DefaultRateLimitFallback = 1 * time.Second
+)
+
+// RateLimitError is returned when Paperless keeps responding with
+// 429 Too Many Requests after the client has waited and retried
+type RateLimitError struct {
+	Err        *Error
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("rate limited by Paperless, retry in %ds",
+		int(math.Ceil(e.RetryAfter.Seconds())))
+}
+
+// Unwrap returns the underlying API error
+func (e *RateLimitError) Unwrap() error {
+	return e.Err
+}
+
+// Retryable reports that the failed operation can be retried later
+func (e *RateLimitError) Retryable() bool {
+	return true
+}
+
+// RetryOnRateLimit returns an interceptor that retries requests rejected
+// with 429 Too Many Requests, waiting for the duration given by the
+// Retry-After header. Waits longer than maxWait are not attempted and the
+// 429 response is passed through so the caller can surface it.
+func RetryOnRateLimit(maxRetries int, maxWait time.Duration) Interceptor {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			resp, err := next(req)
+			for attempt := 1; attempt <= maxRetries; attempt++ {
+				if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+					return resp, err
+				}
+
+				wait := parseRetryAfter(resp.Header.Get(RetryAfterHeader), time.Now())
+				if wait > maxWait {
+					logging.FromContext(req.Context()).Warn("Paperless rate limit wait exceeds maximum, not retrying",
+						"url", req.URL.String(),
+						"retry_after", wait,
+						"max_wait", maxWait)
+					return resp, nil
+				}
+
+				// The request body can only be replayed if it can be re-created
+				retry, ok := cloneRequest(req)
+				if !ok {
+					return resp, nil
+				}
+
+				logging.FromContext(req.Context()).Warn("Rate limited by Paperless, waiting before retry",
+					"url", req.URL.String(),
+					"retry_after", wait,
+					"attempt", attempt)
+
+				// Drain and close so the connection can be reused
+				io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+
+				timer := time.NewTimer(wait)
+				select {
+				case <-req.Context().Done():
+					timer.Stop()
+					return nil, req.Context().Err()
+				case <-timer.C:
+				}
+
+				resp, err = next(retry)
+			}
+			return resp, err
+		}
+	}
+}
+
+// parseRetryAfter parses a Retry-After header given either as a number of
+// seconds or as an HTTP date, falling back to a short default delay
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return DefaultRateLimitFallback
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := date.Sub(now); wait > 0 {
+			return wait
+		}
+		return 0
+	}
+	return DefaultRateLimitFallback
+}
@@ -0,0 +1,113 @@
+package paperless
+
+import (
+	"context"
+	"net/http"
+)
+
+// APISchema is the part of Paperless' OpenAPI schema describing its
+// endpoints and the objects they exchange
+type APISchema struct {
+	Info struct {
+		Title   string `json:"title"`
+		Version string `json:"version"`
+	} `json:"info"`
+	Paths      map[string]APIPathItem `json:"paths"`
+	Components struct {
+		Schemas map[string]APISchemaObject `json:"schemas"`
+	} `json:"components"`
+}
+
+// APIPathItem holds the operations of one API path by HTTP method
+type APIPathItem struct {
+	Get    *APIOperation `json:"get,omitempty"`
+	Post   *APIOperation `json:"post,omitempty"`
+	Put    *APIOperation `json:"put,omitempty"`
+	Patch  *APIOperation `json:"patch,omitempty"`
+	Delete *APIOperation `json:"delete,omitempty"`
+}
+
+// Operations returns the operations of the path keyed by HTTP method
+func (p APIPathItem) Operations() map[string]*APIOperation {
+	operations := make(map[string]*APIOperation)
+	for method, op := range map[string]*APIOperation{
+		http.MethodGet:    p.Get,
+		http.MethodPost:   p.Post,
+		http.MethodPut:    p.Put,
+		http.MethodPatch:  p.Patch,
+		http.MethodDelete: p.Delete,
+	} {
+		if op != nil {
+			operations[method] = op
+		}
+	}
+	return operations
+}
+
+// APIOperation is one endpoint of the API
+type APIOperation struct {
+	OperationID string                 `json:"operationId"`
+	Summary     string                 `json:"summary"`
+	Description string                 `json:"description"`
+	Parameters  []APIParameter         `json:"parameters"`
+	RequestBody *APIBody               `json:"requestBody"`
+	Responses   map[string]APIResponse `json:"responses"`
+}
+
+// APIParameter is a path or query parameter of an endpoint
+type APIParameter struct {
+	Name        string          `json:"name"`
+	In          string          `json:"in"`
+	Description string          `json:"description"`
+	Required    bool            `json:"required"`
+	Schema      APISchemaObject `json:"schema"`
+}
+
+// APIBody is the request body of an endpoint
+type APIBody struct {
+	Required bool                    `json:"required"`
+	Content  map[string]APIMediaType `json:"content"`
+}
+
+// APIResponse is one response of an endpoint
+type APIResponse struct {
+	Description string                  `json:"description"`
+	Content     map[string]APIMediaType `json:"content"`
+}
+
+// APIMediaType is the schema of a body in one content type
+type APIMediaType struct {
+	Schema APISchemaObject `json:"schema"`
+}
+
+// APISchemaObject describes a value, either inline or as a $ref to a
+// named schema in the components
+type APISchemaObject struct {
+	Ref         string                     `json:"$ref,omitempty"`
+	Type        string                     `json:"type,omitempty"`
+	Format      string                     `json:"format,omitempty"`
+	Description string                     `json:"description,omitempty"`
+	Enum        []interface{}              `json:"enum,omitempty"`
+	ReadOnly    bool                       `json:"readOnly,omitempty"`
+	WriteOnly   bool                       `json:"writeOnly,omitempty"`
+	Nullable    bool                       `json:"nullable,omitempty"`
+	Required    []string                   `json:"required,omitempty"`
+	Properties  map[string]APISchemaObject `json:"properties,omitempty"`
+	Items       *APISchemaObject           `json:"items,omitempty"`
+	AllOf       []APISchemaObject          `json:"allOf,omitempty"`
+	OneOf       []APISchemaObject          `json:"oneOf,omitempty"`
+	AnyOf       []APISchemaObject          `json:"anyOf,omitempty"`
+}
+
+// GetAPISchema retrieves the OpenAPI schema Paperless publishes for its
+// REST API, in JSON
+func (c *Client) GetAPISchema(ctx context.Context) (*APISchema, error) {
+	c.log(ctx).Debug("Getting API schema")
+
+	var schema APISchema
+	if _, err := c.do(ctx, http.MethodGet, "/api/schema/?format=json", nil, &schema); err != nil {
+		return nil, err
+	}
+
+	return &schema, nil
+}
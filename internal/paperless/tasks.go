@@ -0,0 +1,72 @@
+package paperless
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Task states and names reported by Paperless
+const (
+	TaskStatusFailure   = "FAILURE"
+	TaskStatusSuccess   = "SUCCESS"
+	TaskNameConsumeFile = "consume_file"
+)
+
+// TaskFilter narrows a list of tasks. The zero value lists every task
+// Paperless still keeps.
+type TaskFilter struct {
+	TaskID   string // the UUID returned by an upload
+	Status   string // e.g. FAILURE
+	TaskName string // e.g. consume_file
+	// Unacknowledged leaves out tasks dismissed in the Paperless UI
+	Unacknowledged bool
+}
+
+// ListTasks retrieves the background tasks matching filter, newest
+// first. The tasks endpoint is not paginated.
+func (c *Client) ListTasks(ctx context.Context, filter TaskFilter) ([]Task, error) {
+	query := url.Values{}
+	if filter.TaskID != "" {
+		query.Set("task_id", filter.TaskID)
+	}
+	if filter.Status != "" {
+		query.Set("status", filter.Status)
+	}
+	if filter.TaskName != "" {
+		query.Set("task_name", filter.TaskName)
+	}
+	if filter.Unacknowledged {
+		query.Set("acknowledged", "false")
+	}
+	path := "/api/tasks/"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	c.log(ctx).Debug("Listing tasks",
+		"task_id", filter.TaskID,
+		"status", filter.Status)
+
+	var tasks []Task
+	if _, err := c.do(ctx, http.MethodGet, path, nil, &tasks); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// GetTask retrieves a task by the UUID returned when its file was uploaded
+func (c *Client) GetTask(ctx context.Context, taskID string) (*Task, error) {
+	tasks, err := c.ListTasks(ctx, TaskFilter{TaskID: taskID})
+	if err != nil {
+		return nil, err
+	}
+	for i := range tasks {
+		if tasks[i].TaskID == taskID {
+			return &tasks[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: task %s", ErrNotFound, taskID)
+}
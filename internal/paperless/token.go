@@ -0,0 +1,169 @@
+package paperless
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/logging"
+)
+
+// TokenSource supplies the Paperless API token used to authenticate
+// requests. Refresh is consulted when Paperless rejects a request with
+// 401 Unauthorized, allowing rotated credentials to be picked up without
+// restarting the server.
+type TokenSource interface {
+	// Token returns the current token
+	Token(ctx context.Context) (string, error)
+
+	// Refresh discards any cached token and returns a fresh one
+	Refresh(ctx context.Context) (string, error)
+}
+
+// tokenContextKey is the context key for a per-request token override
+type tokenContextKey struct{}
+
+// WithToken returns a context whose requests authenticate with token
+// instead of the client's token source. This lets a single client serve
+// several Paperless accounts, one per MCP identity.
+func WithToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, tokenContextKey{}, token)
+}
+
+// TokenFromContext returns the per-request token override, if any
+func TokenFromContext(ctx context.Context) (string, bool) {
+	token, ok := ctx.Value(tokenContextKey{}).(string)
+	return token, ok && token != ""
+}
+
+// StaticTokenSource always returns the same token
+type StaticTokenSource string
+
+// Token returns the static token
+func (s StaticTokenSource) Token(ctx context.Context) (string, error) {
+	return string(s), nil
+}
+
+// Refresh returns the static token; it cannot change
+func (s StaticTokenSource) Refresh(ctx context.Context) (string, error) {
+	return string(s), nil
+}
+
+// FileTokenSource reads the token from a file, re-reading it whenever the
+// file's modification time changes (e.g. a rotated Docker/Kubernetes secret)
+type FileTokenSource struct {
+	path string
+
+	mu      sync.Mutex
+	token   string
+	modTime time.Time
+}
+
+// NewFileTokenSource creates a token source backed by the file at path
+func NewFileTokenSource(path string) *FileTokenSource {
+	return &FileTokenSource{path: path}
+}
+
+// Token returns the file's token, re-reading it if the file has changed
+func (f *FileTokenSource) Token(ctx context.Context) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat token file: %w", err)
+	}
+	if f.token != "" && info.ModTime().Equal(f.modTime) {
+		return f.token, nil
+	}
+	return f.load(ctx, info.ModTime())
+}
+
+// Refresh re-reads the token file unconditionally
+func (f *FileTokenSource) Refresh(ctx context.Context) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat token file: %w", err)
+	}
+	return f.load(ctx, info.ModTime())
+}
+
+// load reads the token file; callers must hold f.mu
+func (f *FileTokenSource) load(ctx context.Context, modTime time.Time) (string, error) {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token file: %w", err)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("token file %s is empty", f.path)
+	}
+
+	if f.token != "" && token != f.token {
+		logging.FromContext(ctx).Info("Paperless token reloaded from file", "path", f.path)
+	}
+	f.token = token
+	f.modTime = modTime
+	return token, nil
+}
+
+// CommandTokenSource obtains the token by running a shell command and
+// reading its standard output. The token is cached until Refresh.
+type CommandTokenSource struct {
+	command string
+
+	mu    sync.Mutex
+	token string
+}
+
+// NewCommandTokenSource creates a token source backed by a shell command
+func NewCommandTokenSource(command string) *CommandTokenSource {
+	return &CommandTokenSource{command: command}
+}
+
+// Token returns the cached token, running the command if none is cached
+func (c *CommandTokenSource) Token(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" {
+		return c.token, nil
+	}
+	return c.run(ctx)
+}
+
+// Refresh runs the command again to obtain a new token
+func (c *CommandTokenSource) Refresh(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.run(ctx)
+}
+
+// run executes the token command; callers must hold c.mu
+func (c *CommandTokenSource) run(ctx context.Context) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", c.command)
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("token command failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	token := strings.TrimSpace(string(out))
+	if token == "" {
+		return "", fmt.Errorf("token command produced no output")
+	}
+
+	logging.FromContext(ctx).Debug("Paperless token obtained from command")
+	c.token = token
+	return token, nil
+}
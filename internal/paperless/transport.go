@@ -0,0 +1,69 @@
+package paperless
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+)
+
+// Transport defaults, tuned for the bursts of parallel requests batch
+// tools send to a single Paperless host
+const (
+	DefaultMaxIdleConns    = 100
+	DefaultMaxConnsPerHost = 0 // unlimited
+	DefaultIdleConnTimeout = 90 * time.Second
+)
+
+// TransportOptions configures the connection pool of the HTTP transport
+// used to reach Paperless
+type TransportOptions struct {
+	// MaxIdleConns is the number of idle keep-alive connections kept open.
+	// All requests go to one host, so this also applies per host.
+	MaxIdleConns int
+	// MaxConnsPerHost limits concurrent connections; 0 means no limit
+	MaxConnsPerHost int
+	// IdleConnTimeout is how long an idle connection is kept open
+	IdleConnTimeout time.Duration
+	// HTTP2 enables HTTP/2 negotiation over TLS
+	HTTP2 bool
+	// MinTLSVersion is the oldest TLS version accepted, e.g.
+	// tls.VersionTLS12; 0 keeps the crypto/tls default
+	MinTLSVersion uint16
+}
+
+// DefaultTransportOptions returns the default transport settings
+func DefaultTransportOptions() TransportOptions {
+	return TransportOptions{
+		MaxIdleConns:    DefaultMaxIdleConns,
+		MaxConnsPerHost: DefaultMaxConnsPerHost,
+		IdleConnTimeout: DefaultIdleConnTimeout,
+		HTTP2:           true,
+	}
+}
+
+// NewTransport builds an HTTP transport from the given options, starting
+// from the settings of http.DefaultTransport
+func NewTransport(opts TransportOptions) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = opts.MaxIdleConns
+	// The default of two idle connections per host forces bursts of
+	// parallel requests to open and discard new connections
+	transport.MaxIdleConnsPerHost = opts.MaxIdleConns
+	transport.MaxConnsPerHost = opts.MaxConnsPerHost
+	transport.IdleConnTimeout = opts.IdleConnTimeout
+	transport.ForceAttemptHTTP2 = opts.HTTP2
+	if !opts.HTTP2 {
+		// A non-nil empty map disables the automatic HTTP/2 upgrade
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	if opts.MinTLSVersion != 0 {
+		transport.TLSClientConfig = &tls.Config{MinVersion: opts.MinTLSVersion}
+	}
+	return transport
+}
+
+// SetTransport replaces the HTTP transport used to reach Paperless. It is
+// wrapped by any WithTransportWrapper wrappers the client was created with.
+func (c *Client) SetTransport(transport http.RoundTripper) {
+	c.httpClient.Transport = c.wrapTransport(transport)
+}
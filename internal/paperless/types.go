@@ -1,5 +1,9 @@
 package paperless
 
+// The Paperless API models are generated from the Paperless-ngx OpenAPI
+// schema; the types below are those the schema does not describe.
+//go:generate go run ./genmodels -spec ../../docs/paperless_api_schema.yaml -overlay models_overlay.yaml -out models_gen.go
+
 import (
 	"encoding/json"
 	"fmt"
@@ -66,104 +70,10 @@ type PaginatedResponse struct {
 	Results  json.RawMessage `json:"results"`
 }
 
-// Document represents a document in Paperless
-type Document struct {
-	ID                  int                  `json:"id"`
-	Correspondent       *int                 `json:"correspondent"`
-	DocumentType        *int                 `json:"document_type"`
-	StoragePath         *int                 `json:"storage_path"`
-	Title               string               `json:"title"`
-	Content             string               `json:"content,omitempty"`
-	Tags                []int                `json:"tags"`
-	Created             FlexibleTime         `json:"created"`
-	CreatedDate         string               `json:"created_date"`
-	Modified            FlexibleTime         `json:"modified"`
-	Added               FlexibleTime         `json:"added"`
-	ArchiveSerialNumber *int                 `json:"archive_serial_number"`
-	OriginalFileName    string               `json:"original_file_name"`
-	ArchivedFileName    *string              `json:"archived_file_name"`
-	Owner               int                  `json:"owner,omitempty"`
-	UserCanChange       bool                 `json:"user_can_change,omitempty"`
-	Notes               []Note               `json:"notes,omitempty"`
-	CustomFields        []CustomFieldValue   `json:"custom_fields,omitempty"`
-}
-
-// Correspondent represents a document correspondent
-type Correspondent struct {
-	ID                 int          `json:"id"`
-	Slug               string       `json:"slug"`
-	Name               string       `json:"name"`
-	Match              string       `json:"match"`
-	MatchingAlgorithm  int          `json:"matching_algorithm"`
-	IsInsensitive      bool         `json:"is_insensitive"`
-	DocumentCount      int          `json:"document_count"`
-	LastCorrespondence FlexibleTime `json:"last_correspondence,omitempty"`
-	Owner              int          `json:"owner,omitempty"`
-	UserCanChange      bool         `json:"user_can_change,omitempty"`
-}
-
-// DocumentType represents a document type
-type DocumentType struct {
-	ID                int    `json:"id"`
-	Slug              string `json:"slug"`
-	Name              string `json:"name"`
-	Match             string `json:"match"`
-	MatchingAlgorithm int    `json:"matching_algorithm"`
-	IsInsensitive     bool   `json:"is_insensitive"`
-	DocumentCount     int    `json:"document_count"`
-	Owner             int    `json:"owner,omitempty"`
-	UserCanChange     bool   `json:"user_can_change,omitempty"`
-}
-
-// Tag represents a document tag
-type Tag struct {
-	ID                int    `json:"id"`
-	Slug              string `json:"slug"`
-	Name              string `json:"name"`
-	Color             string `json:"color"`
-	Match             string `json:"match"`
-	MatchingAlgorithm int    `json:"matching_algorithm"`
-	IsInsensitive     bool   `json:"is_insensitive"`
-	IsInboxTag        bool   `json:"is_inbox_tag"`
-	DocumentCount     int    `json:"document_count"`
-	Owner             int    `json:"owner,omitempty"`
-	UserCanChange     bool   `json:"user_can_change,omitempty"`
-}
-
-// StoragePath represents a storage path
-type StoragePath struct {
-	ID                int    `json:"id"`
-	Slug              string `json:"slug"`
-	Name              string `json:"name"`
-	Path              string `json:"path"`
-	Match             string `json:"match"`
-	MatchingAlgorithm int    `json:"matching_algorithm"`
-	IsInsensitive     bool   `json:"is_insensitive"`
-	DocumentCount     int    `json:"document_count"`
-	Owner             int    `json:"owner,omitempty"`
-	UserCanChange     bool   `json:"user_can_change,omitempty"`
-}
-
-// CustomField represents a custom field definition
-type CustomField struct {
-	ID       int    `json:"id"`
-	Name     string `json:"name"`
-	DataType string `json:"data_type"`
-}
-
-// CustomFieldValue represents a custom field value on a document
-type CustomFieldValue struct {
-	Field int         `json:"field"`
-	Value interface{} `json:"value"`
-}
-
-// Note represents a document note
-type Note struct {
-	ID       int          `json:"id"`
-	Note     string       `json:"note"`
-	Created  FlexibleTime `json:"created"`
-	Document int          `json:"document"`
-	User     *int         `json:"user"`
+// DocumentRef identifies a document by ID and title
+type DocumentRef struct {
+	ID    int    `json:"id"`
+	Title string `json:"title,omitempty"`
 }
 
 // SearchResult represents a search result
@@ -173,3 +83,25 @@ type SearchResult struct {
 	Page      int        `json:"page,omitempty"`
 	PageCount int        `json:"page_count,omitempty"`
 }
+
+// FileMetadata represents a single embedded metadata entry of a file
+type FileMetadata struct {
+	Namespace string `json:"namespace"`
+	Prefix    string `json:"prefix"`
+	Key       string `json:"key"`
+	Value     string `json:"value"`
+}
+
+// UISettings represents the UI settings of the authenticated user
+type UISettings struct {
+	User        UISettingsUser         `json:"user"`
+	Settings    map[string]interface{} `json:"settings"`
+	Permissions []string               `json:"permissions"`
+}
+
+// UISettingsUser identifies the user the UI settings belong to
+type UISettingsUser struct {
+	ID          int    `json:"id"`
+	Username    string `json:"username"`
+	IsSuperuser bool   `json:"is_superuser"`
+}
@@ -0,0 +1,143 @@
+package paperless
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+// UploadOptions holds the consumption-time overrides sent with an upload
+// so the document is filed as soon as it is consumed. Zero values are not
+// sent and leave the decision to Paperless' matching and workflows.
+type UploadOptions struct {
+	Title               string
+	Created             string
+	Correspondent       *int
+	DocumentType        *int
+	StoragePath         *int
+	Tags                []int
+	ArchiveSerialNumber *int
+	Owner               *int
+	// CustomFields maps custom field IDs to their initial values. A nil
+	// value attaches the field without setting it.
+	CustomFields map[int]interface{}
+}
+
+// writeFields adds the non-empty overrides to the multipart form
+func (o *UploadOptions) writeFields(w *multipart.Writer) error {
+	fields := [][2]string{}
+	if o.Title != "" {
+		fields = append(fields, [2]string{"title", o.Title})
+	}
+	if o.Created != "" {
+		fields = append(fields, [2]string{"created", o.Created})
+	}
+	for _, field := range []struct {
+		name string
+		id   *int
+	}{
+		{"correspondent", o.Correspondent},
+		{"document_type", o.DocumentType},
+		{"storage_path", o.StoragePath},
+		{"archive_serial_number", o.ArchiveSerialNumber},
+		// Only applied by Paperless versions that accept an owner on
+		// upload; older versions ignore unknown form fields
+		{"owner", o.Owner},
+	} {
+		if field.id != nil {
+			fields = append(fields, [2]string{field.name, strconv.Itoa(*field.id)})
+		}
+	}
+	for _, tag := range o.Tags {
+		fields = append(fields, [2]string{"tags", strconv.Itoa(tag)})
+	}
+
+	if len(o.CustomFields) > 0 {
+		hasValues := false
+		for _, value := range o.CustomFields {
+			if value != nil {
+				hasValues = true
+				break
+			}
+		}
+		if hasValues {
+			// Values are sent as a JSON object of field ID to value
+			encoded, err := json.Marshal(o.CustomFields)
+			if err != nil {
+				return fmt.Errorf("failed to encode custom fields: %w", err)
+			}
+			fields = append(fields, [2]string{"custom_fields", string(encoded)})
+		} else {
+			ids := make([]int, 0, len(o.CustomFields))
+			for id := range o.CustomFields {
+				ids = append(ids, id)
+			}
+			sort.Ints(ids)
+			for _, id := range ids {
+				fields = append(fields, [2]string{"custom_fields", strconv.Itoa(id)})
+			}
+		}
+	}
+
+	for _, field := range fields {
+		if err := w.WriteField(field[0], field[1]); err != nil {
+			return fmt.Errorf("failed to write form field %s: %w", field[0], err)
+		}
+	}
+	return nil
+}
+
+// UploadDocument uploads a file for consumption via post_document and
+// returns the UUID of the consumption task
+func (c *Client) UploadDocument(ctx context.Context, filename string, content []byte, opts *UploadOptions) (string, error) {
+	path := "/api/documents/post_document/"
+
+	c.log(ctx).Debug("Uploading document", "filename", filename, "size", len(content))
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	part, err := w.CreateFormFile("document", filename)
+	if err != nil {
+		return "", fmt.Errorf("failed to create form file: %w", err)
+	}
+	if _, err := part.Write(content); err != nil {
+		return "", fmt.Errorf("failed to write form file: %w", err)
+	}
+	if opts != nil {
+		if err := opts.writeFields(w); err != nil {
+			return "", err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return "", fmt.Errorf("failed to finish multipart body: %w", err)
+	}
+
+	resp, err := c.doRequestWithContentType(ctx, http.MethodPost, path, bytes.NewReader(buf.Bytes()), w.FormDataContentType())
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, c.maxResponseSize))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", parseError(resp.StatusCode, body)
+	}
+
+	var taskID string
+	if err := json.Unmarshal(body, &taskID); err != nil {
+		c.log(ctx).Error("Failed to parse upload response", "error", err)
+		return "", fmt.Errorf("failed to parse upload response: %w", err)
+	}
+
+	c.log(ctx).Info("Document uploaded for consumption",
+		"filename", filename,
+		"task_id", taskID)
+
+	return taskID, nil
+}
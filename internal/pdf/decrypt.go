@@ -0,0 +1,431 @@
+// Package pdf removes the password protection from PDF files, so documents
+// such as bank statements can be consumed by Paperless, which cannot read
+// encrypted PDFs
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// Errors returned by Decrypt
+var (
+	ErrNotPDF                = errors.New("not a PDF file")
+	ErrNotEncrypted          = errors.New("PDF is not encrypted")
+	ErrWrongPassword         = errors.New("incorrect PDF password")
+	ErrUnsupportedEncryption = errors.New("unsupported PDF encryption")
+)
+
+// objectHeader matches the start of an indirect object
+var objectHeader = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+
+// object is an indirect object of a PDF file
+type object struct {
+	num, gen  int
+	pos       int    // offset of the definition, later definitions win
+	body      []byte // the object, or a stream's dictionary
+	stream    []byte
+	hasStream bool
+}
+
+// IsEncrypted reports whether data is a PDF file with an encryption
+// dictionary
+func IsEncrypted(data []byte) bool {
+	if !isPDF(data) {
+		return false
+	}
+	_, ok := findKey(findTrailer(data, parseObjects(data)), "Encrypt", false)
+	return ok
+}
+
+// Decrypt returns data with its encryption removed, using password as the
+// user or the owner password. The result is rewritten as a PDF file with a
+// classic cross-reference table and no object streams.
+func Decrypt(data []byte, password string) ([]byte, error) {
+	if !isPDF(data) {
+		return nil, ErrNotPDF
+	}
+	objects := parseObjects(data)
+	trailer := findTrailer(data, objects)
+
+	// Read the encryption dictionary, which may be an indirect object
+	pos, ok := findKey(trailer, "Encrypt", false)
+	if !ok {
+		return nil, ErrNotEncrypted
+	}
+	encryptNum, indirect := dictRef(trailer, "Encrypt")
+	var encryptDict []byte
+	if indirect {
+		if obj := objects[encryptNum]; obj != nil {
+			encryptDict = obj.body
+		}
+	} else {
+		encryptDict, _ = dictAt(trailer, pos)
+	}
+	if encryptDict == nil {
+		return nil, fmt.Errorf("%w: encryption dictionary not found", ErrUnsupportedEncryption)
+	}
+	enc, err := parseEncryption(encryptDict, trailerID(trailer))
+	if err != nil {
+		return nil, err
+	}
+	key, err := enc.authenticate([]byte(password))
+	if err != nil {
+		return nil, err
+	}
+
+	// Decrypt every object, then unpack the object streams, whose contents
+	// were encrypted only as part of the stream
+	for num, obj := range objects {
+		if indirect && num == encryptNum {
+			delete(objects, num)
+			continue
+		}
+		if err := decryptObject(obj, enc, key); err != nil {
+			return nil, fmt.Errorf("failed to decrypt object %d: %w", num, err)
+		}
+	}
+	for num, obj := range objects {
+		switch typ, _ := dictName(obj.body, "Type", false); typ {
+		case "XRef":
+			delete(objects, num)
+		case "ObjStm":
+			if err := unpackObjectStream(obj, objects); err != nil {
+				return nil, fmt.Errorf("failed to unpack object stream %d: %w", num, err)
+			}
+			delete(objects, num)
+		}
+	}
+
+	return writePDF(data, objects, trailer), nil
+}
+
+// isPDF reports whether data starts with a PDF header, allowing for the
+// junk some producers put before it
+func isPDF(data []byte) bool {
+	return bytes.Contains(data[:min(len(data), 1024)], []byte("%PDF-"))
+}
+
+// parseObjects reads the indirect objects of data in file order, so the
+// object of an incremental update replaces the one it updates
+func parseObjects(data []byte) map[int]*object {
+	objects := make(map[int]*object)
+	pos := 0
+	for pos < len(data) {
+		base := pos
+		match := objectHeader.FindSubmatchIndex(data[base:])
+		if match == nil {
+			break
+		}
+		start := base + match[0]
+		pos = base + match[1]
+		if start > 0 && !isWhite(data[start-1]) && !isDelim(data[start-1]) {
+			continue
+		}
+		num, _ := strconv.Atoi(string(data[base+match[2] : base+match[3]]))
+		gen, _ := strconv.Atoi(string(data[base+match[4] : base+match[5]]))
+		obj := &object{num: num, gen: gen, pos: start}
+
+		// The body ends at the stream or endobj keyword
+		bodyStart, bodyEnd := pos, pos
+		for {
+			kind, tokStart, tokEnd := nextToken(data, bodyEnd)
+			if kind == tokEOF {
+				bodyEnd = tokStart
+				break
+			}
+			word := string(data[tokStart:tokEnd])
+			if kind == tokOther && (word == "endobj" || word == "stream") {
+				obj.hasStream = word == "stream"
+				pos = tokEnd
+				break
+			}
+			bodyEnd = tokEnd
+		}
+		obj.body = bytes.TrimSpace(data[bodyStart:bodyEnd])
+
+		if obj.hasStream {
+			obj.stream, pos = readStream(data, pos, obj.body)
+		}
+		objects[num] = obj
+	}
+	return objects
+}
+
+// readStream returns the data of the stream whose keyword ends at pos and
+// the position after it
+func readStream(data []byte, pos int, dict []byte) ([]byte, int) {
+	if bytes.HasPrefix(data[pos:], []byte("\r\n")) {
+		pos += 2
+	} else if pos < len(data) && (data[pos] == '\n' || data[pos] == '\r') {
+		pos++
+	}
+
+	// Trust /Length only when endstream follows it; an indirect length
+	// fails this check and falls back to searching for the keyword. The
+	// bound is written so that a huge length cannot overflow.
+	if length, ok := dictInt(dict, "Length", false); ok && length >= 0 && length <= len(data)-pos {
+		end := skipSpace(data, pos+length)
+		if bytes.HasPrefix(data[end:], []byte("endstream")) {
+			return data[pos : pos+length], end + len("endstream")
+		}
+	}
+	end := bytes.Index(data[pos:], []byte("endstream"))
+	if end < 0 {
+		return data[pos:], len(data)
+	}
+	stream := data[pos : pos+end]
+	stream = bytes.TrimSuffix(stream, []byte("\n"))
+	stream = bytes.TrimSuffix(stream, []byte("\r"))
+	return stream, pos + end + len("endstream")
+}
+
+// findTrailer returns the last trailer dictionary of data, which is either
+// a classic trailer or the dictionary of a cross-reference stream
+func findTrailer(data []byte, objects map[int]*object) []byte {
+	var trailer []byte
+	trailerPos := -1
+	if i := bytes.LastIndex(data, []byte("trailer")); i >= 0 {
+		if dict, ok := dictAt(data, i+len("trailer")); ok {
+			trailer, trailerPos = dict, i
+		}
+	}
+	for _, obj := range objects {
+		if typ, _ := dictName(obj.body, "Type", false); typ == "XRef" && obj.pos > trailerPos {
+			trailer, trailerPos = obj.body, obj.pos
+		}
+	}
+	return trailer
+}
+
+// trailerID returns the first element of the trailer's file identifier
+func trailerID(trailer []byte) []byte {
+	pos, ok := findKey(trailer, "ID", false)
+	if !ok {
+		return nil
+	}
+	kind, _, end := nextToken(trailer, pos)
+	if kind != tokOther || trailer[end-1] != '[' {
+		return nil
+	}
+	kind, start, end := nextToken(trailer, end)
+	if kind != tokString {
+		return nil
+	}
+	return decodeString(trailer[start:end])
+}
+
+// decryptObject decrypts the strings and stream of obj in place
+func decryptObject(obj *object, enc *encryption, key []byte) error {
+	if typ, _ := dictName(obj.body, "Type", false); typ == "XRef" {
+		// Cross-reference streams are never encrypted
+		return nil
+	}
+
+	if enc.strMethod != methodNone {
+		var out bytes.Buffer
+		pos := 0
+		for {
+			kind, start, end := nextToken(obj.body, pos)
+			if kind == tokEOF {
+				break
+			}
+			if kind == tokString {
+				plain, err := decryptData(enc.strMethod, key, obj.num, obj.gen, decodeString(obj.body[start:end]))
+				if err != nil {
+					return err
+				}
+				out.Write(obj.body[pos:start])
+				fmt.Fprintf(&out, "<%s>", hex.EncodeToString(plain))
+				pos = end
+				continue
+			}
+			out.Write(obj.body[pos:end])
+			pos = end
+		}
+		out.Write(obj.body[pos:])
+		obj.body = out.Bytes()
+	}
+
+	if obj.hasStream && enc.stmMethod != methodNone {
+		if typ, _ := dictName(obj.body, "Type", false); typ == "Metadata" && !enc.encryptMetadata {
+			return nil
+		}
+		plain, err := decryptData(enc.stmMethod, key, obj.num, obj.gen, obj.stream)
+		if err != nil {
+			return err
+		}
+		obj.stream = plain
+	}
+	return nil
+}
+
+// unpackObjectStream adds the objects packed in the object stream obj to
+// objects, unless a later definition replaces them
+func unpackObjectStream(obj *object, objects map[int]*object) error {
+	content := obj.stream
+	if filter, ok := dictName(obj.body, "Filter", false); ok {
+		if filter != "FlateDecode" {
+			return fmt.Errorf("unsupported filter %s", filter)
+		}
+		if _, ok := findKey(obj.body, "DecodeParms", false); ok {
+			return fmt.Errorf("unsupported decode parameters")
+		}
+		r, err := zlib.NewReader(bytes.NewReader(content))
+		if err != nil {
+			return err
+		}
+		if content, err = io.ReadAll(r); err != nil {
+			return err
+		}
+	} else if _, ok := findKey(obj.body, "Filter", false); ok {
+		return fmt.Errorf("unsupported filter chain")
+	}
+
+	count, _ := dictInt(obj.body, "N", false)
+	first, ok := dictInt(obj.body, "First", false)
+	if !ok || first < 0 || first > len(content) {
+		return fmt.Errorf("invalid /First")
+	}
+	// Each object takes at least a byte of the header, which bounds /N
+	if count < 0 || count > first {
+		return fmt.Errorf("invalid /N")
+	}
+
+	// The header lists the number and offset of each object
+	type entry struct{ num, offset int }
+	entries := make([]entry, 0, count)
+	pos := 0
+	for i := 0; i < count; i++ {
+		var pair [2]int
+		for j := range pair {
+			_, start, end := nextToken(content[:first], pos)
+			value, err := strconv.Atoi(string(content[start:end]))
+			if err != nil {
+				return fmt.Errorf("invalid object stream header")
+			}
+			pair[j], pos = value, end
+		}
+		if pair[1] < 0 || pair[1] > len(content)-first {
+			return fmt.Errorf("object %d lies outside the stream", pair[0])
+		}
+		entries = append(entries, entry{pair[0], pair[1]})
+	}
+
+	for i, e := range entries {
+		end := len(content)
+		if i+1 < len(entries) {
+			end = first + entries[i+1].offset
+		}
+		if existing := objects[e.num]; existing != nil && existing.pos > obj.pos {
+			continue
+		}
+		objects[e.num] = &object{
+			num:  e.num,
+			pos:  obj.pos,
+			body: bytes.TrimSpace(content[first+e.offset : max(end, first+e.offset)]),
+		}
+	}
+	return nil
+}
+
+// setLength returns dict with /Length set to length
+func setLength(dict []byte, length int) []byte {
+	value := []byte(" " + strconv.Itoa(length))
+	pos, ok := findKey(dict, "Length", false)
+	if !ok {
+		open := bytes.Index(dict, []byte("<<"))
+		if open < 0 {
+			return dict
+		}
+		return concat(dict[:open+2], []byte("/Length"), value, dict[open+2:])
+	}
+
+	// The old value is a number, or the three tokens of a reference
+	_, _, end := nextToken(dict, pos)
+	if _, genStart, genEnd := nextToken(dict, end); genEnd > genStart {
+		if _, rStart, rEnd := nextToken(dict, genEnd); string(dict[rStart:rEnd]) == "R" {
+			if _, err := strconv.Atoi(string(dict[genStart:genEnd])); err == nil {
+				end = rEnd
+			}
+		}
+	}
+	return concat(dict[:pos], value, dict[end:])
+}
+
+func concat(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
+
+// writePDF serializes objects as a PDF file with the header of original
+// and a trailer carrying over the document catalog, information dictionary
+// and file identifier of trailer
+func writePDF(original []byte, objects map[int]*object, trailer []byte) []byte {
+	var out bytes.Buffer
+	header := original[bytes.Index(original, []byte("%PDF-")):]
+	if eol := bytes.IndexAny(header, "\r\n"); eol >= 0 {
+		header = header[:eol]
+	}
+	out.Write(header)
+	out.WriteString("\n%\xE2\xE3\xCF\xD3\n")
+
+	nums := make([]int, 0, len(objects))
+	for num := range objects {
+		nums = append(nums, num)
+	}
+	sort.Ints(nums)
+	size := 1
+	if len(nums) > 0 {
+		size = nums[len(nums)-1] + 1
+	}
+
+	offsets := make(map[int]int, len(nums))
+	for _, num := range nums {
+		obj := objects[num]
+		offsets[num] = out.Len()
+		fmt.Fprintf(&out, "%d %d obj\n", num, obj.gen)
+		if obj.hasStream {
+			out.Write(setLength(obj.body, len(obj.stream)))
+			out.WriteString("\nstream\n")
+			out.Write(obj.stream)
+			out.WriteString("\nendstream")
+		} else {
+			out.Write(obj.body)
+		}
+		out.WriteString("\nendobj\n")
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", size)
+	for num := 1; num < size; num++ {
+		if obj, ok := objects[num]; ok {
+			fmt.Fprintf(&out, "%010d %05d n \n", offsets[num], obj.gen)
+		} else {
+			out.WriteString("0000000000 00000 f \n")
+		}
+	}
+
+	fmt.Fprintf(&out, "trailer\n<< /Size %d", size)
+	for _, key := range []string{"Root", "Info"} {
+		if num, ok := dictRef(trailer, key); ok && objects[num] != nil {
+			fmt.Fprintf(&out, " /%s %d %d R", key, num, objects[num].gen)
+		}
+	}
+	if pos, ok := findKey(trailer, "ID", false); ok {
+		if kind, start, _ := nextToken(trailer, pos); kind == tokOther && trailer[start] == '[' {
+			if end := bytes.IndexByte(trailer[start:], ']'); end >= 0 {
+				out.WriteString(" /ID ")
+				out.Write(trailer[start : start+end+1])
+			}
+		}
+	}
+	fmt.Fprintf(&out, " >>\nstartxref\n%d\n%%%%EOF\n", xref)
+	return out.Bytes()
+}
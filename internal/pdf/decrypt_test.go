@@ -0,0 +1,276 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// testID is the first element of the file identifier of test documents
+var testID = []byte("0123456789abcdef")
+
+// encryptTestData is the inverse of decryptData, with a fixed IV for AES
+func encryptTestData(method string, key []byte, num, gen int, data []byte) []byte {
+	switch method {
+	case methodRC4:
+		return rc4Crypt(objectKey(key, num, gen, false), data)
+	case methodAESV2, methodAESV3:
+		if method == methodAESV2 {
+			key = objectKey(key, num, gen, true)
+		}
+		pad := aes.BlockSize - len(data)%aes.BlockSize
+		plain := append(append([]byte{}, data...), bytes.Repeat([]byte{byte(pad)}, pad)...)
+		iv := []byte("fedcba9876543210")
+		block, _ := aes.NewCipher(key)
+		out := make([]byte, len(plain))
+		cipher.NewCBCEncrypter(block, iv).CryptBlocks(out, plain)
+		return append(iv, out...)
+	}
+	return data
+}
+
+// testEncryption returns the encryption dictionary and file key of the
+// standard security handler revision r for the user and owner passwords
+func testEncryption(t testing.TB, r int, user, owner string) (string, *encryption, []byte) {
+	t.Helper()
+	enc := &encryption{r: r, p: -3904, id0: testID, encryptMetadata: true}
+
+	switch r {
+	case 3, 4:
+		enc.v, enc.keyLength, enc.strMethod = 2, 16, methodRC4
+		if r == 4 {
+			enc.v, enc.strMethod = 4, methodAESV2
+		}
+		enc.stmMethod = enc.strMethod
+
+		// The O entry encrypts the padded user password with the owner key
+		enc.o = padPassword([]byte(user))
+		ownerKey := enc.ownerKey([]byte(owner))
+		for i := 0; i <= 19; i++ {
+			enc.o = rc4Crypt(xorKey(ownerKey, byte(i)), enc.o)
+		}
+		key := enc.fileKey([]byte(user))
+		enc.u = append(enc.userEntry(key), make([]byte, 16)...)
+
+		dict := fmt.Sprintf("<< /Filter /Standard /V %d /R %d /Length 128 /P -3904 /O <%x> /U <%x>", enc.v, r, enc.o, enc.u)
+		if r == 4 {
+			dict += " /CF << /StdCF << /CFM /AESV2 /Length 16 /AuthEvent /DocOpen >> >> /StmF /StdCF /StrF /StdCF"
+		}
+		return dict + " >>", enc, key
+
+	case 6:
+		enc.v, enc.keyLength, enc.strMethod, enc.stmMethod = 5, 32, methodAESV3, methodAESV3
+		key := []byte("0123456789abcdef0123456789ABCDEF")
+		encryptKey := func(k []byte) []byte {
+			block, _ := aes.NewCipher(k)
+			out := make([]byte, len(key))
+			cipher.NewCBCEncrypter(block, make([]byte, aes.BlockSize)).CryptBlocks(out, key)
+			return out
+		}
+
+		userSalts := []byte("uvsaltxxukSaltxx")
+		enc.u = append(enc.hashV5([]byte(user), userSalts[:8], nil), userSalts...)
+		enc.ue = encryptKey(enc.hashV5([]byte(user), userSalts[8:], nil))
+		ownerSalts := []byte("ovsaltxxokSaltxx")
+		enc.o = append(enc.hashV5([]byte(owner), ownerSalts[:8], enc.u), ownerSalts...)
+		enc.oe = encryptKey(enc.hashV5([]byte(owner), ownerSalts[8:], enc.u))
+
+		dict := fmt.Sprintf("<< /Filter /Standard /V 5 /R 6 /Length 256 /P -3904 /O <%x> /U <%x> /OE <%x> /UE <%x> /Perms <00>"+
+			" /CF << /StdCF << /CFM /AESV3 /Length 32 /AuthEvent /DocOpen >> >> /StmF /StdCF /StrF /StdCF >>",
+			enc.o, enc.u, enc.oe, enc.ue)
+		return dict, enc, key
+	}
+	t.Fatalf("Unsupported test revision %d", r)
+	return "", nil, nil
+}
+
+// buildEncryptedPDF returns a one page statement encrypted with revision
+// r, with its catalog, pages and information dictionary packed in an
+// object stream when packed is set
+func buildEncryptedPDF(t testing.TB, r int, user, owner string, packed bool) []byte {
+	t.Helper()
+	encryptDict, enc, key := testEncryption(t, r, user, owner)
+	encryptString := func(num int, s string) string {
+		return fmt.Sprintf("<%x>", encryptTestData(enc.strMethod, key, num, 0, []byte(s)))
+	}
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.7\n")
+	writeObject := func(num int, body string, stream []byte) {
+		fmt.Fprintf(&out, "%d 0 obj\n%s\n", num, body)
+		if stream != nil {
+			fmt.Fprintf(&out, "stream\r\n%s\nendstream\n", stream)
+		}
+		out.WriteString("endobj\n")
+	}
+	writeStream := func(num int, dict string, content []byte) {
+		encrypted := encryptTestData(enc.stmMethod, key, num, 0, content)
+		writeObject(num, fmt.Sprintf("<< %s /Length %d >>", dict, len(encrypted)), encrypted)
+	}
+
+	catalog := "<< /Type /Catalog /Pages 2 0 R >>"
+	pages := "<< /Type /Pages /Kids [3 0 R] /Count 1 >>"
+	if packed {
+		// Strings of packed objects are encrypted only with their stream
+		objects := []string{catalog, pages, "<< /Title (Bank statement \\(March\\)) >>"}
+		var header, body bytes.Buffer
+		for i, num := range []int{1, 2, 5} {
+			fmt.Fprintf(&header, "%d %d ", num, body.Len())
+			body.WriteString(objects[i] + "\n")
+		}
+		var compressed bytes.Buffer
+		w := zlib.NewWriter(&compressed)
+		w.Write(append(header.Bytes(), body.Bytes()...))
+		w.Close()
+		writeStream(6, fmt.Sprintf("/Type /ObjStm /N 3 /First %d /Filter /FlateDecode", header.Len()), compressed.Bytes())
+	} else {
+		writeObject(1, catalog, nil)
+		writeObject(2, pages, nil)
+		writeObject(5, "<< /Title "+encryptString(5, "Bank statement (March)")+" >>", nil)
+	}
+	writeObject(3, "<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R >>", nil)
+	writeStream(4, "", []byte("BT /F1 12 Tf 72 720 Td (Closing balance 1,234.56) Tj ET"))
+	writeObject(7, encryptDict, nil)
+
+	trailer := fmt.Sprintf("/Size 9 /Root 1 0 R /Info 5 0 R /Encrypt 7 0 R /ID [<%x> <%x>]", testID, testID)
+	if packed {
+		// The cross-reference data is never read, only the dictionary
+		writeObject(8, fmt.Sprintf("<< /Type /XRef %s /W [1 2 1] /Length 4 >>", trailer), []byte{0, 0, 0, 0})
+		out.WriteString("startxref\n0\n%%EOF\n")
+	} else {
+		fmt.Fprintf(&out, "xref\n0 1\n0000000000 65535 f \ntrailer\n<< %s >>\nstartxref\n0\n%%%%EOF\n", trailer)
+	}
+	return out.Bytes()
+}
+
+// TestDecrypt tests that documents of each supported revision are
+// decrypted with the user and owner passwords and rejected with others
+func TestDecrypt(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		r      int
+		user   string
+		packed bool
+	}{
+		{"RC4 128-bit", 3, "1234", false},
+		{"AES 128-bit", 4, "1234", false},
+		{"AES 128-bit empty user password", 4, "", false},
+		{"AES 256-bit", 6, "1234", false},
+		{"AES 256-bit object stream", 6, "1234", true},
+		{"RC4 128-bit object stream", 3, "1234", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			data := buildEncryptedPDF(t, tc.r, tc.user, "bank-owner", tc.packed)
+			if !IsEncrypted(data) {
+				t.Fatal("Expected the document to be encrypted")
+			}
+			if bytes.Contains(data, []byte("Closing balance")) {
+				t.Fatal("Expected the test document to hide its content")
+			}
+
+			for _, password := range []string{tc.user, "bank-owner"} {
+				decrypted, err := Decrypt(data, password)
+				if err != nil {
+					t.Fatalf("Decrypt with %q failed: %v", password, err)
+				}
+				if !bytes.Contains(decrypted, []byte("(Closing balance 1,234.56) Tj")) {
+					t.Errorf("Expected the page content in %s", decrypted)
+				}
+				title := []byte("Bank statement \\(March\\)")
+				if !tc.packed {
+					title = []byte(hex.EncodeToString([]byte("Bank statement (March)")))
+				}
+				if !bytes.Contains(decrypted, title) {
+					t.Errorf("Expected the decrypted title in %s", decrypted)
+				}
+				if bytes.Contains(decrypted, []byte("/Encrypt")) || bytes.Contains(decrypted, []byte("/ObjStm")) || IsEncrypted(decrypted) {
+					t.Errorf("Expected no encryption or object streams in %s", decrypted)
+				}
+				if !bytes.Contains(decrypted, []byte("/Root 1 0 R /Info 5 0 R")) {
+					t.Errorf("Expected the trailer to keep the catalog and info in %s", decrypted)
+				}
+				if _, err := Decrypt(decrypted, ""); !errors.Is(err, ErrNotEncrypted) {
+					t.Errorf("Expected the output to be readable as an unencrypted PDF, got %v", err)
+				}
+			}
+
+			if _, err := Decrypt(data, "wrong"); !errors.Is(err, ErrWrongPassword) {
+				t.Errorf("Expected ErrWrongPassword, got %v", err)
+			}
+		})
+	}
+}
+
+// TestDecryptRejectsOtherFiles tests that files that are not encrypted
+// PDFs are reported as such
+func TestDecryptRejectsOtherFiles(t *testing.T) {
+	plain := []byte("%PDF-1.4\n1 0 obj\n<< /Type /Catalog >>\nendobj\ntrailer\n<< /Size 2 /Root 1 0 R >>\n%%EOF\n")
+	if _, err := Decrypt(plain, ""); !errors.Is(err, ErrNotEncrypted) {
+		t.Errorf("Expected ErrNotEncrypted, got %v", err)
+	}
+	if IsEncrypted(plain) {
+		t.Error("Expected an unencrypted PDF not to be reported as encrypted")
+	}
+	if _, err := Decrypt([]byte("PK\x03\x04 not a pdf"), ""); !errors.Is(err, ErrNotPDF) {
+		t.Errorf("Expected ErrNotPDF, got %v", err)
+	}
+
+	data := bytes.Replace(buildEncryptedPDF(t, 4, "", "owner", false), []byte("/Filter /Standard"), []byte("/Filter /Adobe.PubSec"), 1)
+	if _, err := Decrypt(data, ""); !errors.Is(err, ErrUnsupportedEncryption) {
+		t.Errorf("Expected ErrUnsupportedEncryption for a certificate handler, got %v", err)
+	}
+}
+
+// TestDecryptMalformed tests that crafted lengths, counts, offsets and key
+// lengths are rejected instead of panicking
+func TestDecryptMalformed(t *testing.T) {
+	hugeLength := []byte("%PDF-1.4\n1 0 obj\n<< /Length 9223372036854775807 >>\nstream\nabc\nendstream\nendobj\ntrailer\n<< /Root 1 0 R /Encrypt << /Filter /Standard >> >>\n%%EOF\n")
+	if !IsEncrypted(hugeLength) {
+		t.Error("Expected the encryption dictionary to be found past a stream with a huge /Length")
+	}
+	if _, err := Decrypt(hugeLength, ""); err == nil {
+		t.Error("Expected a file with an incomplete encryption dictionary to be rejected")
+	}
+
+	wideKey := bytes.Replace(buildEncryptedPDF(t, 3, "", "owner", false), []byte("/Length 128"), []byte("/Length 256"), 1)
+	if _, err := Decrypt(wideKey, ""); !errors.Is(err, ErrUnsupportedEncryption) {
+		t.Errorf("Expected a 256-bit key for revision 3 to be unsupported, got %v", err)
+	}
+
+	for _, tc := range []struct {
+		name, dict, content string
+	}{
+		{"negative count", "<< /Type /ObjStm /N -1 /First 4 >>", "1 0 <<>>"},
+		{"huge count", "<< /Type /ObjStm /N 9223372036854775807 /First 4 >>", "1 0 <<>>"},
+		{"negative offset", "<< /Type /ObjStm /N 1 /First 5 >>", "1 -5 <<>>"},
+		{"offset past the end", "<< /Type /ObjStm /N 1 /First 5 >>", "1 99 <<>>"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := &object{num: 6, body: []byte(tc.dict), stream: []byte(tc.content), hasStream: true}
+			if err := unpackObjectStream(obj, map[int]*object{}); err == nil {
+				t.Error("Expected the object stream to be rejected")
+			}
+		})
+	}
+}
+
+// FuzzDecrypt checks that no input makes the parser panic; uploads of any
+// file reach IsEncrypted and, when encrypted, Decrypt
+func FuzzDecrypt(f *testing.F) {
+	for _, r := range []int{3, 4, 6} {
+		for _, packed := range []bool{false, true} {
+			f.Add(buildEncryptedPDF(f, r, "", "owner", packed))
+		}
+	}
+	f.Add([]byte("%PDF-1.4\n1 0 obj\n<< /Type /Catalog >>\nendobj\ntrailer\n<< /Size 2 /Root 1 0 R >>\n%%EOF\n"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if IsEncrypted(data) {
+			Decrypt(data, "")
+		}
+	})
+}
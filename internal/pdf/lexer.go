@@ -0,0 +1,288 @@
+package pdf
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+)
+
+// tokenKind classifies the tokens of PDF object syntax the decryptor needs
+// to tell apart
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokString
+	tokDictOpen
+	tokDictClose
+	tokName
+	tokOther // numbers, keywords and array brackets
+)
+
+func isWhite(c byte) bool {
+	return c == 0 || c == '\t' || c == '\n' || c == '\f' || c == '\r' || c == ' '
+}
+
+func isDelim(c byte) bool {
+	return bytes.IndexByte([]byte("()<>[]{}/%"), c) >= 0
+}
+
+func isRegular(c byte) bool {
+	return !isWhite(c) && !isDelim(c)
+}
+
+// skipSpace returns the position of the first byte at or after pos that is
+// neither white space nor part of a comment
+func skipSpace(data []byte, pos int) int {
+	for pos < len(data) {
+		switch c := data[pos]; {
+		case isWhite(c):
+			pos++
+		case c == '%':
+			for pos < len(data) && data[pos] != '\n' && data[pos] != '\r' {
+				pos++
+			}
+		default:
+			return pos
+		}
+	}
+	return pos
+}
+
+// nextToken returns the kind and extent of the token at or after pos
+func nextToken(data []byte, pos int) (kind tokenKind, start, end int) {
+	start = skipSpace(data, pos)
+	if start >= len(data) {
+		return tokEOF, start, start
+	}
+	c := data[start]
+	switch {
+	case c == '(':
+		return tokString, start, skipLiteral(data, start)
+	case c == '<' && start+1 < len(data) && data[start+1] == '<':
+		return tokDictOpen, start, start + 2
+	case c == '<':
+		if i := bytes.IndexByte(data[start:], '>'); i >= 0 {
+			return tokString, start, start + i + 1
+		}
+		return tokString, start, len(data)
+	case c == '>' && start+1 < len(data) && data[start+1] == '>':
+		return tokDictClose, start, start + 2
+	case c == '/':
+		end = start + 1
+		for end < len(data) && isRegular(data[end]) {
+			end++
+		}
+		return tokName, start, end
+	case isDelim(c):
+		return tokOther, start, start + 1
+	}
+	end = start
+	for end < len(data) && isRegular(data[end]) {
+		end++
+	}
+	return tokOther, start, end
+}
+
+// skipLiteral returns the position just after the literal string starting
+// at pos, honouring nested parentheses and escapes
+func skipLiteral(data []byte, pos int) int {
+	depth := 0
+	for i := pos; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return len(data)
+}
+
+// decodeString returns the bytes of a literal or hex string token
+func decodeString(tok []byte) []byte {
+	if len(tok) > 0 && tok[0] == '<' {
+		digits := make([]byte, 0, len(tok))
+		for _, c := range bytes.TrimSuffix(tok[1:], []byte(">")) {
+			if !isWhite(c) {
+				digits = append(digits, c)
+			}
+		}
+		if len(digits)%2 == 1 {
+			digits = append(digits, '0')
+		}
+		decoded := make([]byte, len(digits)/2)
+		if _, err := hex.Decode(decoded, digits); err != nil {
+			return nil
+		}
+		return decoded
+	}
+
+	body := bytes.TrimPrefix(tok, []byte("("))
+	body = bytes.TrimSuffix(body, []byte(")"))
+	out := make([]byte, 0, len(body))
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		switch {
+		case c == '\r':
+			// An unescaped end of line is read as a line feed
+			if i+1 < len(body) && body[i+1] == '\n' {
+				i++
+			}
+			out = append(out, '\n')
+		case c != '\\' || i+1 == len(body):
+			out = append(out, c)
+		default:
+			i++
+			switch e := body[i]; e {
+			case 'n':
+				out = append(out, '\n')
+			case 'r':
+				out = append(out, '\r')
+			case 't':
+				out = append(out, '\t')
+			case 'b':
+				out = append(out, '\b')
+			case 'f':
+				out = append(out, '\f')
+			case '\r':
+				// A backslash before an end of line continues the string
+				if i+1 < len(body) && body[i+1] == '\n' {
+					i++
+				}
+			case '\n':
+			case '0', '1', '2', '3', '4', '5', '6', '7':
+				value := int(e - '0')
+				for n := 1; n < 3 && i+1 < len(body) && body[i+1] >= '0' && body[i+1] <= '7'; n++ {
+					i++
+					value = value*8 + int(body[i]-'0')
+				}
+				out = append(out, byte(value))
+			default:
+				out = append(out, e)
+			}
+		}
+	}
+	return out
+}
+
+// findKey returns the position of the value of key in the dictionary
+// starting at the beginning of dict, looking only at the dictionary's own
+// entries unless anyDepth is set
+func findKey(dict []byte, key string, anyDepth bool) (int, bool) {
+	depth := 0
+	pos := 0
+	for {
+		kind, start, end := nextToken(dict, pos)
+		switch kind {
+		case tokEOF:
+			return 0, false
+		case tokDictOpen:
+			depth++
+		case tokDictClose:
+			depth--
+		case tokName:
+			if (depth == 1 || anyDepth) && string(dict[start+1:end]) == key {
+				return end, true
+			}
+		}
+		pos = end
+	}
+}
+
+// dictInt returns the integer value of key in dict
+func dictInt(dict []byte, key string, anyDepth bool) (int, bool) {
+	pos, ok := findKey(dict, key, anyDepth)
+	if !ok {
+		return 0, false
+	}
+	_, start, end := nextToken(dict, pos)
+	var value int
+	if _, err := fmt.Sscanf(string(dict[start:end]), "%d", &value); err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+// dictRef returns the object number of the indirect reference key points to
+func dictRef(dict []byte, key string) (int, bool) {
+	pos, ok := findKey(dict, key, false)
+	if !ok {
+		return 0, false
+	}
+	var num, gen int
+	var r string
+	_, start, _ := nextToken(dict, pos)
+	if _, err := fmt.Sscanf(string(dict[start:]), "%d %d %1s", &num, &gen, &r); err != nil || r != "R" {
+		return 0, false
+	}
+	return num, true
+}
+
+// dictName returns the name value of key in dict, without its slash
+func dictName(dict []byte, key string, anyDepth bool) (string, bool) {
+	pos, ok := findKey(dict, key, anyDepth)
+	if !ok {
+		return "", false
+	}
+	kind, start, end := nextToken(dict, pos)
+	if kind != tokName {
+		return "", false
+	}
+	return string(dict[start+1 : end]), true
+}
+
+// dictString returns the decoded string value of key in dict
+func dictString(dict []byte, key string) ([]byte, bool) {
+	pos, ok := findKey(dict, key, false)
+	if !ok {
+		return nil, false
+	}
+	kind, start, end := nextToken(dict, pos)
+	if kind != tokString {
+		return nil, false
+	}
+	return decodeString(dict[start:end]), true
+}
+
+// dictBool returns the boolean value of key in dict
+func dictBool(dict []byte, key string) (bool, bool) {
+	pos, ok := findKey(dict, key, false)
+	if !ok {
+		return false, false
+	}
+	_, start, end := nextToken(dict, pos)
+	switch string(dict[start:end]) {
+	case "true":
+		return true, true
+	case "false":
+		return false, true
+	}
+	return false, false
+}
+
+// dictAt returns the dictionary starting at the first << at or after pos
+func dictAt(data []byte, pos int) ([]byte, bool) {
+	kind, start, end := nextToken(data, pos)
+	if kind != tokDictOpen {
+		return nil, false
+	}
+	depth := 1
+	for depth > 0 {
+		kind, _, end = nextToken(data, end)
+		switch kind {
+		case tokEOF:
+			return nil, false
+		case tokDictOpen:
+			depth++
+		case tokDictClose:
+			depth--
+		}
+	}
+	return data[start:end], true
+}
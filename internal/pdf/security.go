@@ -0,0 +1,327 @@
+package pdf
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/md5"
+	"crypto/rc4"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"hash"
+)
+
+// Crypt methods applied to strings and streams
+const (
+	methodNone  = "None"
+	methodRC4   = "V2"
+	methodAESV2 = "AESV2"
+	methodAESV3 = "AESV3"
+)
+
+// passwordPadding completes passwords to 32 bytes for revisions 2 to 4
+var passwordPadding = []byte{
+	0x28, 0xBF, 0x4E, 0x5E, 0x4E, 0x75, 0x8A, 0x41, 0x64, 0x00, 0x4E, 0x56, 0xFF, 0xFA, 0x01, 0x08,
+	0x2E, 0x2E, 0x00, 0xB6, 0xD0, 0x68, 0x3E, 0x80, 0x2F, 0x0C, 0xA9, 0xFE, 0x64, 0x53, 0x69, 0x7A,
+}
+
+// encryption holds the standard security handler's settings from a PDF's
+// encryption dictionary
+type encryption struct {
+	v, r            int
+	keyLength       int // bytes
+	o, u, oe, ue    []byte
+	p               int32
+	id0             []byte
+	encryptMetadata bool
+	strMethod       string
+	stmMethod       string
+}
+
+// parseEncryption reads an encryption dictionary
+func parseEncryption(dict, id0 []byte) (*encryption, error) {
+	if filter, _ := dictName(dict, "Filter", false); filter != "Standard" {
+		return nil, fmt.Errorf("%w: security handler %q", ErrUnsupportedEncryption, filter)
+	}
+	e := &encryption{id0: id0, encryptMetadata: true, keyLength: 5}
+	e.v, _ = dictInt(dict, "V", false)
+	e.r, _ = dictInt(dict, "R", false)
+	if bits, ok := dictInt(dict, "Length", false); ok && bits >= 40 && bits <= 256 && bits%8 == 0 {
+		e.keyLength = bits / 8
+	}
+	p, _ := dictInt(dict, "P", false)
+	e.p = int32(p)
+	e.o, _ = dictString(dict, "O")
+	e.u, _ = dictString(dict, "U")
+	e.oe, _ = dictString(dict, "OE")
+	e.ue, _ = dictString(dict, "UE")
+	if encryptMetadata, ok := dictBool(dict, "EncryptMetadata"); ok {
+		e.encryptMetadata = encryptMetadata
+	}
+
+	switch e.v {
+	case 1, 2:
+		e.strMethod, e.stmMethod = methodRC4, methodRC4
+		if e.v == 1 {
+			e.keyLength = 5
+		}
+	case 4, 5:
+		// Strings and streams name crypt filters; this handler supports the
+		// standard filter only, with Identity meaning unencrypted
+		cfm, _ := dictName(dict, "CFM", true)
+		if cfm != methodRC4 && cfm != methodAESV2 && cfm != methodAESV3 {
+			return nil, fmt.Errorf("%w: crypt filter method %q", ErrUnsupportedEncryption, cfm)
+		}
+		e.strMethod, e.stmMethod = methodNone, methodNone
+		if name, _ := dictName(dict, "StrF", false); name != "" && name != "Identity" {
+			e.strMethod = cfm
+		}
+		if name, _ := dictName(dict, "StmF", false); name != "" && name != "Identity" {
+			e.stmMethod = cfm
+		}
+		if cfm == methodAESV2 {
+			e.keyLength = 16
+		}
+		if e.v == 5 {
+			e.keyLength = 32
+		}
+	default:
+		return nil, fmt.Errorf("%w: version %d", ErrUnsupportedEncryption, e.v)
+	}
+
+	// Revisions 2 to 4 derive the key from an MD5 sum, so it can be at
+	// most 16 bytes long
+	if e.r >= 2 && e.r <= 4 && e.keyLength > md5.Size {
+		return nil, fmt.Errorf("%w: %d byte key for revision %d", ErrUnsupportedEncryption, e.keyLength, e.r)
+	}
+
+	switch {
+	case e.r >= 2 && e.r <= 4 && len(e.o) >= 32 && len(e.u) >= 32:
+	case (e.r == 5 || e.r == 6) && len(e.o) >= 48 && len(e.u) >= 48 && len(e.oe) == 32 && len(e.ue) == 32:
+	default:
+		return nil, fmt.Errorf("%w: revision %d", ErrUnsupportedEncryption, e.r)
+	}
+	return e, nil
+}
+
+// authenticate returns the file key for password, trying it as the user
+// password and then as the owner password
+func (e *encryption) authenticate(password []byte) ([]byte, error) {
+	if e.r >= 5 {
+		if len(password) > 127 {
+			password = password[:127]
+		}
+		if bytes.Equal(e.hashV5(password, e.u[32:40], nil), e.u[:32]) {
+			return aesDecryptNoPadding(e.hashV5(password, e.u[40:48], nil), e.ue)
+		}
+		if bytes.Equal(e.hashV5(password, e.o[32:40], e.u[:48]), e.o[:32]) {
+			return aesDecryptNoPadding(e.hashV5(password, e.o[40:48], e.u[:48]), e.oe)
+		}
+		return nil, ErrWrongPassword
+	}
+
+	if key := e.fileKey(password); e.checkUserKey(key) {
+		return key, nil
+	}
+	if key := e.fileKey(e.userPasswordFromOwner(password)); e.checkUserKey(key) {
+		return key, nil
+	}
+	return nil, ErrWrongPassword
+}
+
+// padPassword truncates or pads a password to 32 bytes
+func padPassword(password []byte) []byte {
+	padded := make([]byte, 0, 32)
+	padded = append(padded, password[:min(len(password), 32)]...)
+	return append(padded, passwordPadding[:32-len(padded)]...)
+}
+
+// fileKey computes the file key of revisions 2 to 4 from a user password
+func (e *encryption) fileKey(password []byte) []byte {
+	h := md5.New()
+	h.Write(padPassword(password))
+	h.Write(e.o[:32])
+	binary.Write(h, binary.LittleEndian, e.p)
+	h.Write(e.id0)
+	if e.r >= 4 && !e.encryptMetadata {
+		h.Write([]byte{0xFF, 0xFF, 0xFF, 0xFF})
+	}
+	key := h.Sum(nil)
+	if e.r >= 3 {
+		for i := 0; i < 50; i++ {
+			sum := md5.Sum(key[:e.keyLength])
+			key = sum[:]
+		}
+	}
+	return key[:e.keyLength]
+}
+
+// userEntry computes the U entry a file key produces in revisions 2 to 4
+func (e *encryption) userEntry(key []byte) []byte {
+	if e.r == 2 {
+		return rc4Crypt(key, passwordPadding)
+	}
+	h := md5.New()
+	h.Write(passwordPadding)
+	h.Write(e.id0)
+	entry := rc4Crypt(key, h.Sum(nil))
+	for i := 1; i <= 19; i++ {
+		entry = rc4Crypt(xorKey(key, byte(i)), entry)
+	}
+	return entry
+}
+
+// checkUserKey reports whether key is the file key, comparing the U entry
+// it produces with the document's
+func (e *encryption) checkUserKey(key []byte) bool {
+	if e.r == 2 {
+		return bytes.Equal(e.userEntry(key), e.u[:32])
+	}
+	return bytes.Equal(e.userEntry(key), e.u[:16])
+}
+
+// ownerKey computes the key that encrypts the user password in the O
+// entry from an owner password
+func (e *encryption) ownerKey(owner []byte) []byte {
+	sum := md5.Sum(padPassword(owner))
+	key := sum[:]
+	if e.r >= 3 {
+		for i := 0; i < 50; i++ {
+			sum = md5.Sum(key)
+			key = sum[:]
+		}
+	}
+	return key[:e.keyLength]
+}
+
+// userPasswordFromOwner recovers the padded user password from the O entry
+// with an owner password
+func (e *encryption) userPasswordFromOwner(owner []byte) []byte {
+	key := e.ownerKey(owner)
+	if e.r == 2 {
+		return rc4Crypt(key, e.o[:32])
+	}
+	user := e.o[:32]
+	for i := 19; i >= 0; i-- {
+		user = rc4Crypt(xorKey(key, byte(i)), user)
+	}
+	return user
+}
+
+// hashV5 is the password hash of revisions 5 and 6; revision 6 hardens
+// SHA-256 with rounds of AES and SHA-2
+func (e *encryption) hashV5(password, salt, userKey []byte) []byte {
+	h := sha256.New()
+	h.Write(password)
+	h.Write(salt)
+	h.Write(userKey)
+	k := h.Sum(nil)
+	if e.r == 5 {
+		return k
+	}
+
+	for round := 0; ; round++ {
+		k1 := bytes.Repeat(append(append(append([]byte{}, password...), k...), userKey...), 64)
+		block, _ := aes.NewCipher(k[:16])
+		encrypted := make([]byte, len(k1))
+		cipher.NewCBCEncrypter(block, k[16:32]).CryptBlocks(encrypted, k1)
+
+		sum := 0
+		for _, b := range encrypted[:16] {
+			sum += int(b)
+		}
+		var next hash.Hash
+		switch sum % 3 {
+		case 0:
+			next = sha256.New()
+		case 1:
+			next = sha512.New384()
+		default:
+			next = sha512.New()
+		}
+		next.Write(encrypted)
+		k = next.Sum(nil)
+		if round >= 63 && int(encrypted[len(encrypted)-1]) <= round-32 {
+			break
+		}
+	}
+	return k[:32]
+}
+
+// objectKey derives the key of one object for revisions 2 to 4
+func objectKey(fileKey []byte, num, gen int, aesMethod bool) []byte {
+	h := md5.New()
+	h.Write(fileKey)
+	h.Write([]byte{byte(num), byte(num >> 8), byte(num >> 16), byte(gen), byte(gen >> 8)})
+	if aesMethod {
+		h.Write([]byte("sAlT"))
+	}
+	return h.Sum(nil)[:min(len(fileKey)+5, 16)]
+}
+
+// decryptData decrypts a string or stream of object num with method
+func decryptData(method string, fileKey []byte, num, gen int, data []byte) ([]byte, error) {
+	switch method {
+	case methodRC4:
+		return rc4Crypt(objectKey(fileKey, num, gen, false), data), nil
+	case methodAESV2:
+		return aesDecrypt(objectKey(fileKey, num, gen, true), data)
+	case methodAESV3:
+		return aesDecrypt(fileKey, data)
+	}
+	return data, nil
+}
+
+func rc4Crypt(key, data []byte) []byte {
+	c, _ := rc4.NewCipher(key)
+	out := make([]byte, len(data))
+	c.XORKeyStream(out, data)
+	return out
+}
+
+// xorKey returns key with every byte XORed with b
+func xorKey(key []byte, b byte) []byte {
+	out := make([]byte, len(key))
+	for i := range key {
+		out[i] = key[i] ^ b
+	}
+	return out
+}
+
+// aesDecrypt decrypts AES-CBC data prefixed with its IV and removes the
+// padding
+func aesDecrypt(key, data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return data, nil
+	}
+	if len(data) < 2*aes.BlockSize || len(data)%aes.BlockSize != 0 {
+		// Empty strings are sometimes stored as a bare IV
+		if len(data) == aes.BlockSize {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("AES data of %d bytes is not a whole number of blocks", len(data))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(data)-aes.BlockSize)
+	cipher.NewCBCDecrypter(block, data[:aes.BlockSize]).CryptBlocks(out, data[aes.BlockSize:])
+	if pad := int(out[len(out)-1]); pad >= 1 && pad <= aes.BlockSize && bytes.Equal(out[len(out)-pad:], bytes.Repeat([]byte{byte(pad)}, pad)) {
+		out = out[:len(out)-pad]
+	}
+	return out, nil
+}
+
+// aesDecryptNoPadding decrypts the 32 byte OE or UE entry with a zero IV
+func aesDecryptNoPadding(key, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, make([]byte, aes.BlockSize)).CryptBlocks(out, data)
+	return out, nil
+}
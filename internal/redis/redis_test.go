@@ -0,0 +1,159 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeServer is an in-process server speaking enough RESP for the client
+type fakeServer struct {
+	listener net.Listener
+	password string
+
+	mu       sync.Mutex
+	data     map[string]string
+	commands []string
+}
+
+func newFakeServer(t *testing.T, password string) *fakeServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	f := &fakeServer{listener: listener, password: password, data: make(map[string]string)}
+	go f.serve()
+	t.Cleanup(func() { listener.Close() })
+	return f
+}
+
+func (f *fakeServer) serve() {
+	for {
+		c, err := f.listener.Accept()
+		if err != nil {
+			return
+		}
+		go f.handle(c)
+	}
+}
+
+func (f *fakeServer) handle(c net.Conn) {
+	defer c.Close()
+	r := bufio.NewReader(c)
+	authed := f.password == ""
+	for {
+		reply, err := readReply(r)
+		if err != nil {
+			return
+		}
+		items := reply.([]interface{})
+		args := make([]string, len(items))
+		for i, item := range items {
+			args[i] = string(item.([]byte))
+		}
+
+		f.mu.Lock()
+		f.commands = append(f.commands, strings.Join(args, " "))
+		var out string
+		switch {
+		case args[0] == "AUTH":
+			if args[len(args)-1] == f.password {
+				authed = true
+				out = "+OK\r\n"
+			} else {
+				out = "-WRONGPASS invalid password\r\n"
+			}
+		case !authed:
+			out = "-NOAUTH Authentication required.\r\n"
+		case args[0] == "PING":
+			out = "+PONG\r\n"
+		case args[0] == "SELECT":
+			out = "+OK\r\n"
+		case args[0] == "SET":
+			f.data[args[1]] = args[2]
+			out = "+OK\r\n"
+		case args[0] == "GET":
+			if value, ok := f.data[args[1]]; ok {
+				out = fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+			} else {
+				out = "$-1\r\n"
+			}
+		case args[0] == "DEL":
+			delete(f.data, args[1])
+			out = ":1\r\n"
+		default:
+			out = "-ERR unknown command\r\n"
+		}
+		f.mu.Unlock()
+		c.Write([]byte(out))
+	}
+}
+
+// TestClient tests authentication, database selection and the key commands
+func TestClient(t *testing.T) {
+	f := newFakeServer(t, "secret")
+	client, err := New("redis://:secret@" + f.listener.Addr().String() + "/2")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	if err := client.Set(ctx, "key", []byte("value\r\nwith newline"), time.Minute); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	value, err := client.Get(ctx, "key")
+	if err != nil || string(value) != "value\r\nwith newline" {
+		t.Errorf("Expected the stored value, got %q, %v", value, err)
+	}
+	if err := client.Del(ctx, "key"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if value, err := client.Get(ctx, "key"); err != nil || value != nil {
+		t.Errorf("Expected a missing key to return nil, got %q, %v", value, err)
+	}
+
+	var replyErr Error
+	if _, err := client.Do(ctx, "BOGUS"); !errors.As(err, &replyErr) {
+		t.Errorf("Expected an error reply, got %v", err)
+	}
+	if err := client.Ping(ctx); err != nil {
+		t.Errorf("Expected the connection to stay usable after an error reply, got %v", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	want := []string{"AUTH secret", "SELECT 2", "SET key value\r\nwith newline PX 60000"}
+	for i, command := range want {
+		if f.commands[i] != command {
+			t.Errorf("Command %d: expected %q, got %q", i, command, f.commands[i])
+		}
+	}
+}
+
+// TestClientWrongPassword tests that failed authentication is reported
+func TestClientWrongPassword(t *testing.T) {
+	f := newFakeServer(t, "secret")
+	client, err := New("redis://:wrong@" + f.listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := client.Ping(context.Background()); err == nil || !strings.Contains(err.Error(), "authentication failed") {
+		t.Errorf("Expected an authentication error, got %v", err)
+	}
+}
+
+// TestNewInvalidURL tests URL validation
+func TestNewInvalidURL(t *testing.T) {
+	for _, rawURL := range []string{"http://localhost:6379", "redis://", "redis://localhost/db"} {
+		if _, err := New(rawURL); err == nil {
+			t.Errorf("Expected %q to be rejected", rawURL)
+		}
+	}
+}
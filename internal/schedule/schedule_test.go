@@ -0,0 +1,52 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+// TestNext tests common schedules against a fixed starting time
+func TestNext(t *testing.T) {
+	// Wednesday 2024-01-10 10:30
+	start := time.Date(2024, 1, 10, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"0 8 * * MON", time.Date(2024, 1, 15, 8, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, 1, 10, 10, 45, 0, 0, time.UTC)},
+		{"0 9-17 * * 1-5", time.Date(2024, 1, 10, 11, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 feb *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC)},
+		// Day of month and day of week are ORed when both are restricted
+		{"0 0 13 * fri", time.Date(2024, 1, 12, 0, 0, 0, 0, time.UTC)},
+		{"30 10 10 1 *", time.Date(2025, 1, 10, 10, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		s, err := Parse(tt.spec)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", tt.spec, err)
+			continue
+		}
+		if got := s.Next(start); !got.Equal(tt.want) {
+			t.Errorf("Next for %q = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+// TestParseErrors tests that malformed expressions are rejected
+func TestParseErrors(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "0 24 * * *", "0 0 0 * *", "0 0 * 13 *", "*/0 * * * *", "0 0 * * funday", "5-1 * * * *"} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q) should fail", spec)
+		}
+	}
+
+	s, _ := Parse("0 0 30 feb *")
+	if next := s.Next(time.Now()); !next.IsZero() {
+		t.Errorf("Expected a schedule that never fires to return the zero time, got %v", next)
+	}
+}
@@ -0,0 +1,25 @@
+package textutil
+
+import "testing"
+
+// TestEditDistance tests insertions, deletions and substitutions
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "tag", 3},
+		{"tag", "", 3},
+		{"title", "title", 0},
+		{"titel", "title", 2},
+		{"PAPERLESS_URL", "PAPERLES_URL", 1},
+		{"kitten", "sitting", 3},
+	}
+
+	for _, tt := range tests {
+		if got := EditDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("EditDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
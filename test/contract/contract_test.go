@@ -0,0 +1,333 @@
+// Package contract exercises the Paperless client against a live
+// Paperless-ngx instance to catch API drift between Paperless releases.
+//
+// The suite is opt-in: it is skipped unless PAPERLESS_CONTRACT_URL and
+// PAPERLESS_CONTRACT_TOKEN are set. Every fixture it creates is named with
+// a unique prefix and deleted when the test finishes.
+//
+//	PAPERLESS_CONTRACT_URL=http://localhost:8000 \
+//	PAPERLESS_CONTRACT_TOKEN=... go test ./test/contract/ -v
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"git.binckly.ca/cbinckly/paperless-mcp-go/internal/paperless"
+)
+
+// Environment variables enabling the contract suite
+const (
+	EnvContractURL   = "PAPERLESS_CONTRACT_URL"
+	EnvContractToken = "PAPERLESS_CONTRACT_TOKEN"
+)
+
+// contractTimeout bounds each test against a slow instance
+const contractTimeout = 60 * time.Second
+
+// newClient returns a client for the contract instance, skipping the test
+// when the suite is not enabled
+func newClient(t *testing.T) (*paperless.Client, context.Context) {
+	t.Helper()
+
+	baseURL := os.Getenv(EnvContractURL)
+	token := os.Getenv(EnvContractToken)
+	if baseURL == "" || token == "" {
+		t.Skipf("Contract tests disabled; set %s and %s to run", EnvContractURL, EnvContractToken)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), contractTimeout)
+	t.Cleanup(cancel)
+
+	return paperless.New(baseURL, token), ctx
+}
+
+// fixtureName returns a unique name for a fixture created by this run
+func fixtureName(kind string) string {
+	return fmt.Sprintf("mcp-contract-%s-%d", kind, time.Now().UnixNano())
+}
+
+// cleanup deletes a fixture when the test ends, ignoring fixtures that the
+// test already deleted
+func cleanup(t *testing.T, kind string, id int, del func(context.Context, int) error) {
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), contractTimeout)
+		defer cancel()
+		if err := del(ctx, id); err != nil && !paperless.IsNotFound(err) {
+			t.Errorf("Failed to clean up %s %d: %v", kind, id, err)
+		}
+	})
+}
+
+// assertListed checks that a paginated response decodes and contains id
+func assertListed(t *testing.T, response *paperless.PaginatedResponse, id int) {
+	t.Helper()
+
+	var items []struct {
+		ID int `json:"id"`
+	}
+	if err := json.Unmarshal(response.Results, &items); err != nil {
+		t.Fatalf("Failed to decode list results: %v", err)
+	}
+	for _, item := range items {
+		if item.ID == id {
+			return
+		}
+	}
+	t.Errorf("Expected id %d in first page of %d results", id, response.Count)
+}
+
+// TestTagLifecycle exercises tag create, get, list, update and delete
+func TestTagLifecycle(t *testing.T) {
+	client, ctx := newClient(t)
+
+	created, err := client.CreateTag(ctx, &paperless.Tag{Name: fixtureName("tag"), Color: "#a6cee3"})
+	if err != nil {
+		t.Fatalf("CreateTag failed: %v", err)
+	}
+	cleanup(t, "tag", created.ID, client.DeleteTag)
+
+	got, err := client.GetTag(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetTag failed: %v", err)
+	}
+	if got.Name != created.Name {
+		t.Errorf("Expected name %q, got %q", created.Name, got.Name)
+	}
+
+	list, err := client.ListTags(ctx, 1, paperless.MaxPageSize)
+	if err != nil {
+		t.Fatalf("ListTags failed: %v", err)
+	}
+	assertListed(t, list, created.ID)
+
+	updated, err := client.UpdateTag(ctx, created.ID, map[string]interface{}{"color": "#1f78b4"})
+	if err != nil {
+		t.Fatalf("UpdateTag failed: %v", err)
+	}
+	if updated.Color != "#1f78b4" {
+		t.Errorf("Expected color #1f78b4, got %q", updated.Color)
+	}
+
+	if err := client.DeleteTag(ctx, created.ID); err != nil {
+		t.Fatalf("DeleteTag failed: %v", err)
+	}
+	if _, err := client.GetTag(ctx, created.ID); !paperless.IsNotFound(err) {
+		t.Errorf("Expected deleted tag to be not found, got %v", err)
+	}
+}
+
+// TestCorrespondentLifecycle exercises correspondent CRUD
+func TestCorrespondentLifecycle(t *testing.T) {
+	client, ctx := newClient(t)
+
+	created, err := client.CreateCorrespondent(ctx, &paperless.Correspondent{Name: fixtureName("correspondent")})
+	if err != nil {
+		t.Fatalf("CreateCorrespondent failed: %v", err)
+	}
+	cleanup(t, "correspondent", created.ID, client.DeleteCorrespondent)
+
+	if _, err := client.GetCorrespondent(ctx, created.ID); err != nil {
+		t.Fatalf("GetCorrespondent failed: %v", err)
+	}
+
+	list, err := client.ListCorrespondents(ctx, 1, paperless.MaxPageSize)
+	if err != nil {
+		t.Fatalf("ListCorrespondents failed: %v", err)
+	}
+	assertListed(t, list, created.ID)
+
+	updated, err := client.UpdateCorrespondent(ctx, created.ID, map[string]interface{}{"match": "contract"})
+	if err != nil {
+		t.Fatalf("UpdateCorrespondent failed: %v", err)
+	}
+	if updated.Match != "contract" {
+		t.Errorf("Expected match 'contract', got %q", updated.Match)
+	}
+
+	if err := client.DeleteCorrespondent(ctx, created.ID); err != nil {
+		t.Fatalf("DeleteCorrespondent failed: %v", err)
+	}
+}
+
+// TestDocumentTypeLifecycle exercises document type CRUD
+func TestDocumentTypeLifecycle(t *testing.T) {
+	client, ctx := newClient(t)
+
+	created, err := client.CreateDocumentType(ctx, &paperless.DocumentType{Name: fixtureName("doctype")})
+	if err != nil {
+		t.Fatalf("CreateDocumentType failed: %v", err)
+	}
+	cleanup(t, "document type", created.ID, client.DeleteDocumentType)
+
+	if _, err := client.GetDocumentType(ctx, created.ID); err != nil {
+		t.Fatalf("GetDocumentType failed: %v", err)
+	}
+
+	list, err := client.ListDocumentTypes(ctx, 1, paperless.MaxPageSize)
+	if err != nil {
+		t.Fatalf("ListDocumentTypes failed: %v", err)
+	}
+	assertListed(t, list, created.ID)
+
+	updated, err := client.UpdateDocumentType(ctx, created.ID, map[string]interface{}{"match": "contract"})
+	if err != nil {
+		t.Fatalf("UpdateDocumentType failed: %v", err)
+	}
+	if updated.Match != "contract" {
+		t.Errorf("Expected match 'contract', got %q", updated.Match)
+	}
+
+	if err := client.DeleteDocumentType(ctx, created.ID); err != nil {
+		t.Fatalf("DeleteDocumentType failed: %v", err)
+	}
+}
+
+// TestStoragePathLifecycle exercises storage path CRUD
+func TestStoragePathLifecycle(t *testing.T) {
+	client, ctx := newClient(t)
+
+	name := fixtureName("storagepath")
+	created, err := client.CreateStoragePath(ctx, &paperless.StoragePath{
+		Name: name,
+		Path: name + "/{{ title }}",
+	})
+	if err != nil {
+		t.Fatalf("CreateStoragePath failed: %v", err)
+	}
+	cleanup(t, "storage path", created.ID, client.DeleteStoragePath)
+
+	if _, err := client.GetStoragePath(ctx, created.ID); err != nil {
+		t.Fatalf("GetStoragePath failed: %v", err)
+	}
+
+	list, err := client.ListStoragePaths(ctx, 1, paperless.MaxPageSize)
+	if err != nil {
+		t.Fatalf("ListStoragePaths failed: %v", err)
+	}
+	assertListed(t, list, created.ID)
+
+	newPath := name + "/{{ created_year }}/{{ title }}"
+	updated, err := client.UpdateStoragePath(ctx, created.ID, map[string]interface{}{"path": newPath})
+	if err != nil {
+		t.Fatalf("UpdateStoragePath failed: %v", err)
+	}
+	if updated.Path != newPath {
+		t.Errorf("Expected path %q, got %q", newPath, updated.Path)
+	}
+
+	if err := client.DeleteStoragePath(ctx, created.ID); err != nil {
+		t.Fatalf("DeleteStoragePath failed: %v", err)
+	}
+}
+
+// TestCustomFieldLifecycle exercises custom field CRUD
+func TestCustomFieldLifecycle(t *testing.T) {
+	client, ctx := newClient(t)
+
+	created, err := client.CreateCustomField(ctx, &paperless.CustomField{
+		Name:     fixtureName("field"),
+		DataType: "string",
+	})
+	if err != nil {
+		t.Fatalf("CreateCustomField failed: %v", err)
+	}
+	cleanup(t, "custom field", created.ID, client.DeleteCustomField)
+
+	if _, err := client.GetCustomField(ctx, created.ID); err != nil {
+		t.Fatalf("GetCustomField failed: %v", err)
+	}
+
+	list, err := client.ListCustomFields(ctx, 1, paperless.MaxPageSize)
+	if err != nil {
+		t.Fatalf("ListCustomFields failed: %v", err)
+	}
+	assertListed(t, list, created.ID)
+
+	newName := created.Name + "-renamed"
+	updated, err := client.UpdateCustomField(ctx, created.ID, map[string]interface{}{"name": newName})
+	if err != nil {
+		t.Fatalf("UpdateCustomField failed: %v", err)
+	}
+	if updated.Name != newName {
+		t.Errorf("Expected name %q, got %q", newName, updated.Name)
+	}
+
+	if err := client.DeleteCustomField(ctx, created.ID); err != nil {
+		t.Fatalf("DeleteCustomField failed: %v", err)
+	}
+}
+
+// TestDocumentReads exercises the read-only document endpoints against
+// whatever documents exist; documents cannot be created without an upload
+func TestDocumentReads(t *testing.T) {
+	client, ctx := newClient(t)
+
+	response, err := client.SearchDocuments(ctx, "*", 1, 1)
+	if err != nil {
+		t.Fatalf("SearchDocuments failed: %v", err)
+	}
+
+	var documents []paperless.Document
+	if err := json.Unmarshal(response.Results, &documents); err != nil {
+		t.Fatalf("Failed to decode search results: %v", err)
+	}
+	if len(documents) == 0 {
+		t.Skip("No documents in contract instance; skipping document reads")
+	}
+
+	document, err := client.GetDocument(ctx, documents[0].ID)
+	if err != nil {
+		t.Fatalf("GetDocument failed: %v", err)
+	}
+	if document.ID != documents[0].ID {
+		t.Errorf("Expected document %d, got %d", documents[0].ID, document.ID)
+	}
+
+	if _, err := client.GetSimilarDocuments(ctx, document.ID, 1, 1); err != nil {
+		t.Fatalf("GetSimilarDocuments failed: %v", err)
+	}
+}
+
+// TestBulkEditTags exercises bulk_edit by adding and removing a fixture tag
+// on an existing document, restoring its original state
+func TestBulkEditTags(t *testing.T) {
+	client, ctx := newClient(t)
+
+	response, err := client.SearchDocuments(ctx, "*", 1, 1)
+	if err != nil {
+		t.Fatalf("SearchDocuments failed: %v", err)
+	}
+	var documents []paperless.Document
+	if err := json.Unmarshal(response.Results, &documents); err != nil {
+		t.Fatalf("Failed to decode search results: %v", err)
+	}
+	if len(documents) == 0 {
+		t.Skip("No documents in contract instance; skipping bulk edit")
+	}
+	documentID := documents[0].ID
+
+	tag, err := client.CreateTag(ctx, &paperless.Tag{Name: fixtureName("bulk"), Color: "#b2df8a"})
+	if err != nil {
+		t.Fatalf("CreateTag failed: %v", err)
+	}
+	cleanup(t, "tag", tag.ID, client.DeleteTag)
+
+	if _, err := client.BulkEditDocuments(ctx, []int{documentID}, map[string]interface{}{
+		"method":     "add_tag",
+		"parameters": map[string]interface{}{"tag": tag.ID},
+	}); err != nil {
+		t.Fatalf("BulkEditDocuments add_tag failed: %v", err)
+	}
+
+	if _, err := client.BulkEditDocuments(ctx, []int{documentID}, map[string]interface{}{
+		"method":     "remove_tag",
+		"parameters": map[string]interface{}{"tag": tag.ID},
+	}); err != nil {
+		t.Fatalf("BulkEditDocuments remove_tag failed: %v", err)
+	}
+}